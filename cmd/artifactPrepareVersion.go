@@ -6,17 +6,21 @@ import (
 	"io"
 	netHttp "net/http"
 	"os"
+	"regexp"
 	"strings"
 	"text/template"
 	"time"
 
+	"github.com/Masterminds/semver/v3"
 	piperhttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
 
 	"github.com/SAP/jenkins-library/pkg/command"
 	gitUtils "github.com/SAP/jenkins-library/pkg/git"
 	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/monorepo"
 	"github.com/SAP/jenkins-library/pkg/orchestrator"
+	"github.com/SAP/jenkins-library/pkg/piperenv"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 	"github.com/SAP/jenkins-library/pkg/versioning"
 	"github.com/pkg/errors"
@@ -123,6 +127,15 @@ func runArtifactPrepareVersion(config *artifactPrepareVersionOptions, telemetryD
 		VersionSource:       config.DockerVersionSource,
 	}
 
+	// support former groovy versioning template and translate into new options
+	if len(config.VersioningTemplate) > 0 {
+		config.VersioningType, _, config.IncludeCommitID = templateCompatibility(config.VersioningTemplate)
+	}
+
+	if len(config.WorkspaceDescriptor) > 0 {
+		return runWorkspaceVersioning(config, commonPipelineEnvironment, &artifactOpts, utils, repository, getWorktree)
+	}
+
 	var err error
 	if artifact == nil {
 		artifact, err = versioning.GetArtifact(config.BuildTool, config.FilePath, &artifactOpts, utils)
@@ -132,11 +145,6 @@ func runArtifactPrepareVersion(config *artifactPrepareVersionOptions, telemetryD
 		}
 	}
 
-	// support former groovy versioning template and translate into new options
-	if len(config.VersioningTemplate) > 0 {
-		config.VersioningType, _, config.IncludeCommitID = templateCompatibility(config.VersioningTemplate)
-	}
-
 	version, err := artifact.GetVersion()
 	if err != nil {
 		log.SetErrorCategory(log.ErrorConfiguration)
@@ -155,20 +163,37 @@ func runArtifactPrepareVersion(config *artifactPrepareVersionOptions, telemetryD
 	newVersion := version
 	now := time.Now()
 
-	if config.VersioningType == "cloud" || config.VersioningType == "cloud_noTag" {
+	if config.VersioningType == "cloud" || config.VersioningType == "cloud_noTag" || config.VersioningType == "semantic" {
 		// make sure that versioning does not create tags (when set to "cloud")
 		// for PR pipelines, optimized pipelines (= no build)
 		provider, err := utils.NewOrchestratorSpecificConfigProvider()
 		if err != nil {
 			log.Entry().WithError(err).Warning("Cannot infer config from CI environment")
 		}
-		if provider.IsPullRequest() || config.IsOptimizedAndScheduled {
+		noTag := provider.IsPullRequest() || config.IsOptimizedAndScheduled
+		if noTag && config.VersioningType == "cloud" {
 			config.VersioningType = "cloud_noTag"
 		}
 
-		newVersion, err = calculateCloudVersion(artifact, config, version, gitCommitID, now)
-		if err != nil {
-			return err
+		if config.VersioningType == "semantic" {
+			history, err := newReleaseHistory(repository)
+			if err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return errors.Wrap(err, "failed to access release history")
+			}
+			release, err := calculateSemanticVersion(config, history, provider.GetBranch(), version)
+			if err != nil {
+				return err
+			}
+			newVersion = release.version
+			commonPipelineEnvironment.custom.releaseNotes = release.releaseNotes
+			// a version which has been released already must not be tagged again
+			noTag = noTag || !release.isNew
+		} else {
+			newVersion, err = calculateCloudVersion(artifact, config, version, gitCommitID, now)
+			if err != nil {
+				return err
+			}
 		}
 
 		worktree, err := getWorktree(repository)
@@ -196,13 +221,17 @@ func runArtifactPrepareVersion(config *artifactPrepareVersionOptions, telemetryD
 
 		// propagate version information to additional descriptors
 		if len(config.AdditionalTargetTools) > 0 {
-			err = propagateVersion(config, utils, &artifactOpts, version, gitCommitID, now)
+			descriptorVersion := version
+			if config.VersioningType == "semantic" {
+				descriptorVersion = newVersion
+			}
+			err = propagateVersion(config, utils, &artifactOpts, descriptorVersion, gitCommitID, now)
 			if err != nil {
 				return err
 			}
 		}
 
-		if config.VersioningType == "cloud" {
+		if config.VersioningType == "cloud" || (config.VersioningType == "semantic" && !noTag) {
 			// commit changes and push to repository (including new version tag)
 			gitCommitID, err = pushChanges(config, newVersion, repository, worktree, now)
 			if err != nil {
@@ -351,22 +380,26 @@ func pushChanges(config *artifactPrepareVersionOptions, newVersion string, repos
 		return commitID, err
 	}
 
-	ref := gitConfig.RefSpec(fmt.Sprintf("refs/tags/%v:refs/tags/%v", tag, tag))
+	return commitID, pushTags(config, []string{tag}, repository)
+}
 
-	pushOptions := git.PushOptions{
-		RefSpecs: []gitConfig.RefSpec{gitConfig.RefSpec(ref)},
+// pushTags pushes the tags to the remote origin, ssh is used in case no credentials for http(s) are available
+func pushTags(config *artifactPrepareVersionOptions, tags []string, repository gitRepository) error {
+	pushOptions := git.PushOptions{}
+	for _, tag := range tags {
+		pushOptions.RefSpecs = append(pushOptions.RefSpecs, gitConfig.RefSpec(fmt.Sprintf("refs/tags/%v:refs/tags/%v", tag, tag)))
 	}
 
 	currentRemoteOrigin, err := repository.Remote("origin")
 	if err != nil {
-		return commitID, errors.Wrap(err, "failed to retrieve current remote origin")
+		return errors.Wrap(err, "failed to retrieve current remote origin")
 	}
 	var updatedRemoteOrigin *git.Remote
 
 	urls := originUrls(repository)
 	if len(urls) == 0 {
 		log.SetErrorCategory(log.ErrorConfiguration)
-		return commitID, fmt.Errorf("no remote url maintained")
+		return fmt.Errorf("no remote url maintained")
 	}
 	if strings.HasPrefix(urls[0], "http") {
 		if len(config.Username) == 0 || len(config.Password) == 0 {
@@ -378,17 +411,17 @@ func pushChanges(config *artifactPrepareVersionOptions, newVersion string, repos
 			// update remote origin url to point to ssh url instead of http(s) url
 			err = repository.DeleteRemote("origin")
 			if err != nil {
-				return commitID, errors.Wrap(err, "failed to update remote origin - remove")
+				return errors.Wrap(err, "failed to update remote origin - remove")
 			}
 			updatedRemoteOrigin, err = repository.CreateRemote(&gitConfig.RemoteConfig{Name: "origin", URLs: []string{remoteURL}})
 			if err != nil {
-				return commitID, errors.Wrap(err, "failed to update remote origin - create")
+				return errors.Wrap(err, "failed to update remote origin - create")
 			}
 
 			pushOptions.Auth, err = sshAgentAuth("git")
 			if err != nil {
 				log.SetErrorCategory(log.ErrorConfiguration)
-				return commitID, errors.Wrap(err, "failed to retrieve ssh authentication")
+				return errors.Wrap(err, "failed to retrieve ssh authentication")
 			}
 			log.Entry().Infof("using remote '%v'", remoteURL)
 		} else {
@@ -398,7 +431,7 @@ func pushChanges(config *artifactPrepareVersionOptions, newVersion string, repos
 		pushOptions.Auth, err = sshAgentAuth("git")
 		if err != nil {
 			log.SetErrorCategory(log.ErrorConfiguration)
-			return commitID, errors.Wrap(err, "failed to retrieve ssh authentication")
+			return errors.Wrap(err, "failed to retrieve ssh authentication")
 		}
 	}
 
@@ -422,21 +455,21 @@ func pushChanges(config *artifactPrepareVersionOptions, newVersion string, repos
 		case strings.Contains(errText, "connection timed out"):
 			log.SetErrorCategory(log.ErrorInfrastructure)
 		}
-		return commitID, err
+		return err
 	}
 
 	if updatedRemoteOrigin != currentRemoteOrigin {
 		err = repository.DeleteRemote("origin")
 		if err != nil {
-			return commitID, errors.Wrap(err, "failed to restore remote origin - remove")
+			return errors.Wrap(err, "failed to restore remote origin - remove")
 		}
 		_, err := repository.CreateRemote(currentRemoteOrigin.Config())
 		if err != nil {
-			return commitID, errors.Wrap(err, "failed to restore remote origin - create")
+			return errors.Wrap(err, "failed to restore remote origin - create")
 		}
 	}
 
-	return commitID, nil
+	return nil
 }
 
 func addAndCommit(config *artifactPrepareVersionOptions, worktree gitWorktree, newVersion string, t time.Time) (plumbing.Hash, error) {
@@ -474,6 +507,263 @@ func templateCompatibility(groovyTemplate string) (versioningType string, useTim
 	return
 }
 
+// releaseHistory provides the release tags and the commits of a git repository
+type releaseHistory interface {
+	ReleaseTags(prefix string) ([]string, error)
+	CommitsSince(tag string) ([]*object.Commit, error)
+	FilesChangedSince(tag string) ([]string, error)
+}
+
+type gitReleaseHistory struct {
+	repository *git.Repository
+}
+
+func (h *gitReleaseHistory) ReleaseTags(prefix string) ([]string, error) {
+	return gitUtils.ReleaseTags(h.repository, prefix)
+}
+
+func (h *gitReleaseHistory) CommitsSince(tag string) ([]*object.Commit, error) {
+	return gitUtils.CommitsSince(h.repository, tag)
+}
+
+func (h *gitReleaseHistory) FilesChangedSince(tag string) ([]string, error) {
+	return gitUtils.FilesChangedSince(h.repository, tag)
+}
+
+// newReleaseHistory is a variable in order to allow replacing the git history in tests
+var newReleaseHistory = func(repository gitRepository) (releaseHistory, error) {
+	repo, ok := repository.(*git.Repository)
+	if !ok {
+		return nil, fmt.Errorf("release history not available for repository of type %T", repository)
+	}
+	return &gitReleaseHistory{repository: repo}, nil
+}
+
+type semanticRelease struct {
+	version      string
+	releaseNotes string
+	// isNew is false if the commit has been released already
+	isNew bool
+}
+
+var invalidChannelCharacters = regexp.MustCompile(`[^0-9A-Za-z-]+`)
+
+// semanticChannel returns the pre-release channel for versions built from the branch, an empty channel denotes a release
+func semanticChannel(config *artifactPrepareVersionOptions, branch string) string {
+	branch = strings.TrimPrefix(branch, "refs/heads/")
+	if len(branch) == 0 || branch == "n/a" {
+		log.Entry().Warn("branch cannot be determined, building a release")
+		return ""
+	}
+	if piperutils.ContainsString(config.SemanticReleaseBranches, branch) {
+		return ""
+	}
+	if channel, ok := config.SemanticPrereleaseChannels[branch]; ok {
+		return fmt.Sprint(channel)
+	}
+	return strings.Trim(invalidChannelCharacters.ReplaceAllString(strings.ToLower(branch), "-"), "-")
+}
+
+// calculateSemanticVersion derives the version from the Conventional Commits since the latest release.
+// Without any release tag, the version of the build descriptor is the first release.
+func calculateSemanticVersion(config *artifactPrepareVersionOptions, history releaseHistory, branch, version string) (semanticRelease, error) {
+	tags, err := history.ReleaseTags(config.TagPrefix)
+	if err != nil {
+		return semanticRelease{}, errors.Wrap(err, "failed to retrieve release tags")
+	}
+
+	latest, latestTag, found := versioning.LatestRelease(tags, config.TagPrefix)
+	if !found {
+		descriptorVersion, err := semver.NewVersion(version)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return semanticRelease{}, errors.Wrapf(err, "version '%v' of the build descriptor is no semantic version", version)
+		}
+		latest = semver.MustParse(fmt.Sprintf("%v.%v.%v", descriptorVersion.Major(), descriptorVersion.Minor(), descriptorVersion.Patch()))
+		log.Entry().Infof("No release tag with prefix '%v' found, version '%v' is the first release", config.TagPrefix, latest)
+	}
+
+	commits, err := history.CommitsSince(latestTag)
+	if err != nil {
+		return semanticRelease{}, errors.Wrapf(err, "failed to retrieve commits since release '%v'", latestTag)
+	}
+	if found && len(commits) == 0 {
+		log.Entry().Infof("No commits since release '%v'", latestTag)
+		return semanticRelease{version: latest.String()}, nil
+	}
+
+	conventionalCommits := []versioning.ConventionalCommit{}
+	for _, commit := range commits {
+		if conventionalCommit, ok := versioning.ParseConventionalCommit(commit.Hash.String(), commit.Message); ok {
+			conventionalCommits = append(conventionalCommits, conventionalCommit)
+		}
+	}
+	changelog := versioning.NewChangelog(conventionalCommits)
+
+	releaseType := changelog.ReleaseType()
+	if !found {
+		releaseType = versioning.ReleaseNone
+	} else if releaseType == versioning.ReleaseNone {
+		// every new commit requires a new version
+		releaseType = versioning.ReleasePatch
+	}
+
+	newVersion, err := versioning.NextSemanticVersion(latest, releaseType, semanticChannel(config, branch), tags, config.TagPrefix)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return semanticRelease{}, err
+	}
+	return semanticRelease{version: newVersion, releaseNotes: changelog.Markdown(), isNew: true}, nil
+}
+
+// runWorkspaceVersioning versions the projects of a monorepo independently of each other. Only projects which changed
+// since their last release, or which depend on such a project, get a new version and, for versioning type "cloud", a release tag.
+func runWorkspaceVersioning(config *artifactPrepareVersionOptions, commonPipelineEnvironment *artifactPrepareVersionCommonPipelineEnvironment, artifactOpts *versioning.Options, utils artifactPrepareVersionUtils, repository gitRepository, getWorktree func(gitRepository) (gitWorktree, error)) error {
+	if config.VersioningType == "semantic" {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return fmt.Errorf("versioningType 'semantic' is not supported in combination with a workspaceDescriptor")
+	}
+
+	workspace, err := monorepo.Load(config.WorkspaceDescriptor, utils)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return err
+	}
+
+	gitCommit, gitCommitMessage, err := getGitCommitID(repository)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return err
+	}
+	gitCommitID := gitCommit.String()
+	commonPipelineEnvironment.git.headCommitID = gitCommitID
+
+	history, err := newReleaseHistory(repository)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return errors.Wrap(err, "failed to access release history")
+	}
+	releasedVersions := map[string]string{}
+	changedFiles := map[string][]string{}
+	for _, project := range workspace.Projects {
+		tags, err := history.ReleaseTags(project.TagPrefix)
+		if err != nil {
+			return errors.Wrapf(err, "failed to retrieve release tags of project '%v'", project.Name)
+		}
+		tag, version := monorepo.LatestTag(tags, project.TagPrefix)
+		releasedVersions[project.Name] = version
+		changedFiles[project.Name], err = history.FilesChangedSince(tag)
+		if err != nil {
+			return errors.Wrapf(err, "failed to determine changes of project '%v'", project.Name)
+		}
+	}
+	affected := workspace.Affected(changedFiles)
+
+	cloud := config.VersioningType == "cloud" || config.VersioningType == "cloud_noTag"
+	noTag := config.VersioningType != "cloud"
+	var worktree gitWorktree
+	if cloud {
+		provider, err := utils.NewOrchestratorSpecificConfigProvider()
+		if err != nil {
+			log.Entry().WithError(err).Warning("Cannot infer config from CI environment")
+		}
+		noTag = noTag || provider.IsPullRequest() || config.IsOptimizedAndScheduled
+
+		worktree, err = getWorktree(repository)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return errors.Wrap(err, "failed to retrieve git worktree")
+		}
+		err = initializeWorktree(gitCommit, worktree)
+		if err != nil {
+			return err
+		}
+	}
+
+	now := time.Now()
+	projects := piperenv.Projects{}
+	tags := []string{}
+	for _, project := range workspace.Projects {
+		state := piperenv.Project{
+			Name:      project.Name,
+			Path:      project.Path,
+			BuildTool: project.BuildTool,
+			Version:   releasedVersions[project.Name],
+			Changed:   affected[project.Name],
+		}
+		if state.Changed || len(state.Version) == 0 {
+			state.Version, err = prepareProjectVersion(project, config, artifactOpts, utils, cloud && state.Changed, gitCommitID, now)
+			if err != nil {
+				return err
+			}
+		}
+		if state.Changed && !noTag {
+			tags = append(tags, project.TagPrefix+state.Version)
+		}
+		log.Entry().Infof("Project '%v': version '%v' (changed: %v)", project.Name, state.Version, state.Changed)
+		projects = append(projects, state)
+	}
+
+	if len(tags) > 0 {
+		// commit changes and push to repository (including the release tags of all changed projects)
+		commit, err := addAndCommit(config, worktree, strings.Join(tags, ", "), now)
+		if err != nil {
+			return err
+		}
+		gitCommitID = commit.String()
+		for _, tag := range tags {
+			if _, err := repository.CreateTag(tag, commit, nil); err != nil {
+				if strings.Contains(fmt.Sprint(err), "reference already exists") {
+					log.SetErrorCategory(log.ErrorCustom)
+				}
+				return errors.Wrapf(err, "failed to create tag '%v'", tag)
+			}
+		}
+		if err := pushTags(config, tags, repository); err != nil {
+			return errors.Wrapf(err, "failed to push changes for tags %v", tags)
+		}
+	}
+
+	commonPipelineEnvironment.git.commitID = gitCommitID
+	commonPipelineEnvironment.git.commitMessage = gitCommitMessage
+	commonPipelineEnvironment.custom.workspaceProjects = projects
+	return nil
+}
+
+// prepareProjectVersion returns the version of a workspace project, for cloud versioning the new version is calculated
+// and written to the version source of the project
+func prepareProjectVersion(project monorepo.Project, config *artifactPrepareVersionOptions, artifactOpts *versioning.Options, utils artifactPrepareVersionUtils, cloud bool, gitCommitID string, now time.Time) (string, error) {
+	if len(project.Descriptor()) == 0 {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return "", fmt.Errorf("no versionSource defined for project '%v' using build tool '%v'", project.Name, project.BuildTool)
+	}
+	artifact, err := versioning.GetArtifact(project.BuildTool, project.Descriptor(), artifactOpts, utils)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return "", errors.Wrapf(err, "failed to retrieve artifact of project '%v'", project.Name)
+	}
+	version, err := artifact.GetVersion()
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return "", errors.Wrapf(err, "failed to retrieve version of project '%v'", project.Name)
+	}
+	if !cloud {
+		return version, nil
+	}
+
+	newVersion, err := calculateCloudVersion(artifact, config, version, gitCommitID, now)
+	if err != nil {
+		return "", err
+	}
+	if newVersion != version {
+		if err := artifact.SetVersion(newVersion); err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return "", errors.Wrapf(err, "failed to write version of project '%v'", project.Name)
+		}
+	}
+	return newVersion, nil
+}
+
 func calculateCloudVersion(artifact versioning.Artifact, config *artifactPrepareVersionOptions, version, gitCommitID string, timestamp time.Time) (string, error) {
 	versioningTempl, err := versioningTemplate(artifact.VersioningScheme())
 	if err != nil {
@@ -18,28 +18,31 @@ import (
 )
 
 type artifactPrepareVersionOptions struct {
-	AdditionalTargetTools       []string `json:"additionalTargetTools,omitempty" validate:"possible-values=custom docker dub golang gradle helm maven mta npm pip sbt yarn"`
-	AdditionalTargetDescriptors []string `json:"additionalTargetDescriptors,omitempty"`
-	BuildTool                   string   `json:"buildTool,omitempty" validate:"possible-values=custom docker dub golang gradle helm maven mta npm pip sbt yarn"`
-	CommitUserName              string   `json:"commitUserName,omitempty"`
-	CustomVersionField          string   `json:"customVersionField,omitempty"`
-	CustomVersionSection        string   `json:"customVersionSection,omitempty"`
-	CustomVersioningScheme      string   `json:"customVersioningScheme,omitempty" validate:"possible-values=docker maven pep440 semver2"`
-	DockerVersionSource         string   `json:"dockerVersionSource,omitempty"`
-	FetchCoordinates            bool     `json:"fetchCoordinates,omitempty"`
-	FilePath                    string   `json:"filePath,omitempty"`
-	GlobalSettingsFile          string   `json:"globalSettingsFile,omitempty"`
-	IncludeCommitID             bool     `json:"includeCommitId,omitempty"`
-	IsOptimizedAndScheduled     bool     `json:"isOptimizedAndScheduled,omitempty"`
-	M2Path                      string   `json:"m2Path,omitempty"`
-	Password                    string   `json:"password,omitempty"`
-	ProjectSettingsFile         string   `json:"projectSettingsFile,omitempty"`
-	ShortCommitID               bool     `json:"shortCommitId,omitempty"`
-	TagPrefix                   string   `json:"tagPrefix,omitempty"`
-	UnixTimestamp               bool     `json:"unixTimestamp,omitempty"`
-	Username                    string   `json:"username,omitempty"`
-	VersioningTemplate          string   `json:"versioningTemplate,omitempty"`
-	VersioningType              string   `json:"versioningType,omitempty" validate:"possible-values=cloud cloud_noTag library"`
+	AdditionalTargetTools       []string               `json:"additionalTargetTools,omitempty" validate:"possible-values=cargo custom docker dotnet dub golang gradle helm maven mta npm pip sbt xcode yarn"`
+	AdditionalTargetDescriptors []string               `json:"additionalTargetDescriptors,omitempty"`
+	BuildTool                   string                 `json:"buildTool,omitempty" validate:"possible-values=cargo custom docker dotnet dub golang gradle helm maven mta npm pip sbt xcode yarn"`
+	CommitUserName              string                 `json:"commitUserName,omitempty"`
+	CustomVersionField          string                 `json:"customVersionField,omitempty"`
+	CustomVersionSection        string                 `json:"customVersionSection,omitempty"`
+	CustomVersioningScheme      string                 `json:"customVersioningScheme,omitempty" validate:"possible-values=docker maven pep440 semver2"`
+	DockerVersionSource         string                 `json:"dockerVersionSource,omitempty"`
+	FetchCoordinates            bool                   `json:"fetchCoordinates,omitempty"`
+	FilePath                    string                 `json:"filePath,omitempty"`
+	GlobalSettingsFile          string                 `json:"globalSettingsFile,omitempty"`
+	IncludeCommitID             bool                   `json:"includeCommitId,omitempty"`
+	IsOptimizedAndScheduled     bool                   `json:"isOptimizedAndScheduled,omitempty"`
+	M2Path                      string                 `json:"m2Path,omitempty"`
+	Password                    string                 `json:"password,omitempty"`
+	ProjectSettingsFile         string                 `json:"projectSettingsFile,omitempty"`
+	SemanticPrereleaseChannels  map[string]interface{} `json:"semanticPrereleaseChannels,omitempty"`
+	SemanticReleaseBranches     []string               `json:"semanticReleaseBranches,omitempty"`
+	ShortCommitID               bool                   `json:"shortCommitId,omitempty"`
+	TagPrefix                   string                 `json:"tagPrefix,omitempty"`
+	UnixTimestamp               bool                   `json:"unixTimestamp,omitempty"`
+	Username                    string                 `json:"username,omitempty"`
+	VersioningTemplate          string                 `json:"versioningTemplate,omitempty"`
+	VersioningType              string                 `json:"versioningType,omitempty" validate:"possible-values=cloud cloud_noTag library semantic"`
+	WorkspaceDescriptor         string                 `json:"workspaceDescriptor,omitempty"`
 }
 
 type artifactPrepareVersionCommonPipelineEnvironment struct {
@@ -53,6 +56,10 @@ type artifactPrepareVersionCommonPipelineEnvironment struct {
 		headCommitID  string
 		commitMessage string
 	}
+	custom struct {
+		releaseNotes      string
+		workspaceProjects piperenv.Projects
+	}
 }
 
 func (p *artifactPrepareVersionCommonPipelineEnvironment) persist(path, resourceName string) {
@@ -69,6 +76,8 @@ func (p *artifactPrepareVersionCommonPipelineEnvironment) persist(path, resource
 		{category: "git", name: "commitId", value: p.git.commitID},
 		{category: "git", name: "headCommitId", value: p.git.headCommitID},
 		{category: "git", name: "commitMessage", value: p.git.commitMessage},
+		{category: "custom", name: "releaseNotes", value: p.custom.releaseNotes},
+		{category: "custom", name: "workspaceProjects", value: p.custom.workspaceProjects},
 	}
 
 	errCount := 0
@@ -137,6 +146,59 @@ Unlike for the _Continuous Deloyment_ pattern described above, in this case ther
 
 Configuration of this pattern is done via ` + "`" + `versioningType: library` + "`" + `.
 
+### 3. Semantic version derived from Conventional Commits
+
+With ` + "`" + `versioningType: semantic` + "`" + ` the version is calculated from the commit messages following the [Conventional Commits](https://www.conventionalcommits.org) specification.
+The commits since the latest release tag (a tag ` + "`" + `<tagPrefix><major>.<minor>.<patch>` + "`" + `) determine the increment of the new version:
+
+* a breaking change (` + "`" + `feat!: ...` + "`" + ` or a ` + "`" + `BREAKING CHANGE:` + "`" + ` footer) increments the major version
+* a feature (` + "`" + `feat: ...` + "`" + `) increments the minor version
+* any other commit, e.g. ` + "`" + `fix: ...` + "`" + `, increments the patch version
+
+In case no release tag exists yet, the version of the build descriptor is released as first version.
+Versions built from branches other than [` + "`" + `semanticReleaseBranches` + "`" + `](#semanticreleasebranches) are pre-releases, e.g. ` + "`" + `1.3.0-beta.2` + "`" + `, see [` + "`" + `semanticPrereleaseChannels` + "`" + `](#semanticprereleasechannels).
+
+The new version is written into the build descriptor, commited and tagged in the git repository like for ` + "`" + `versioningType: cloud` + "`" + `.
+Additionally, the changes contained in the new version are provided as release notes via the common pipeline environment (` + "`" + `custom/releaseNotes` + "`" + `), which are used by the step [githubPublishRelease](githubPublishRelease.md) as body of the release.
+
+### Rust, .NET and Xcode projects
+
+* ` + "`" + `buildTool: cargo` + "`" + ` uses the version of the ` + "`" + `Cargo.toml` + "`" + `, i.e. ` + "`" + `package.version` + "`" + ` or ` + "`" + `workspace.package.version` + "`" + `. When a version is written, the versions of all workspace members which do not inherit the version of the workspace are updated as well. So are the version requirements of path dependencies on workspace members, e.g. ` + "`" + `api = { path = "../api", version = "=1.2.3" }` + "`" + `, the operator of the requirement is kept.
+* ` + "`" + `buildTool: dotnet` + "`" + ` uses the ` + "`" + `<Version>` + "`" + ` (or ` + "`" + `<VersionPrefix>` + "`" + `) property of the ` + "`" + `Directory.Build.props` + "`" + ` or, if not available, of the only project file (` + "`" + `*.csproj` + "`" + `, ` + "`" + `*.fsproj` + "`" + `, ` + "`" + `*.vbproj` + "`" + `) in the root folder. Use parameter ` + "`" + `filePath` + "`" + ` to point to a dedicated project file.
+* ` + "`" + `buildTool: xcode` + "`" + ` uses ` + "`" + `CFBundleShortVersionString` + "`" + ` of the app's ` + "`" + `Info.plist` + "`" + `. Since Xcode only allows period-separated integers, the automatically generated version is split: the ` + "`" + `<major>.<minor>.<patch>` + "`" + ` part is written as ` + "`" + `CFBundleShortVersionString` + "`" + ` and the timestamp as build number (` + "`" + `CFBundleVersion` + "`" + `). In case the ` + "`" + `Info.plist` + "`" + ` refers to build settings like ` + "`" + `$(MARKETING_VERSION)` + "`" + `, the build settings in the ` + "`" + `project.pbxproj` + "`" + ` are updated.
+
+### Monorepo with independently versioned projects
+
+With parameter [` + "`" + `workspaceDescriptor` + "`" + `](#workspacedescriptor) the projects of a monorepo are versioned independently of each other.
+The workspace descriptor is a yaml file listing the projects:
+
+` + "`" + `` + "`" + `` + "`" + `yaml
+projects:
+  - name: common
+    path: libs/common
+    buildTool: maven
+  - name: orders
+    path: services/orders
+    buildTool: maven
+    dependencies: [common]
+  - name: web
+    path: ui/web
+    buildTool: npm
+    versionSource: package.json
+    dependencies: [orders]
+    tagPrefix: web-v
+` + "`" + `` + "`" + `` + "`" + `
+
+* ` + "`" + `versionSource` + "`" + ` is the file containing the version relative to ` + "`" + `path` + "`" + `. It defaults to the build descriptor of the build tool, e.g. ` + "`" + `pom.xml` + "`" + ` for ` + "`" + `maven` + "`" + ` or ` + "`" + `package.json` + "`" + ` for ` + "`" + `npm` + "`" + `.
+* ` + "`" + `dependencies` + "`" + ` lists the names of the projects the project depends on.
+* ` + "`" + `tagPrefix` + "`" + ` is used for the release tags of the project, it defaults to ` + "`" + `<name>/` + "`" + `.
+
+Only projects which changed since their latest release tag, as well as projects depending on them, get a new version and a new release tag.
+The remaining projects keep the version of their latest release tag.
+All projects are provided in dependency order together with their version via the common pipeline environment (` + "`" + `custom/workspaceProjects` + "`" + `).
+Build steps like [mavenBuild](mavenBuild.md) and [npmExecuteScripts](npmExecuteScripts.md) use this information in order to build and publish only the changed projects.
+Versioning type ` + "`" + `semantic` + "`" + ` is not supported for a monorepo.
+
 ### Support of additional build tools
 
 Besides the ` + "`" + `buildTools` + "`" + ` provided out of the box (like ` + "`" + `maven` + "`" + `, ` + "`" + `mta` + "`" + `, ` + "`" + `npm` + "`" + `, ...) it is possible to set ` + "`" + `buildTool: custom` + "`" + `.
@@ -259,12 +321,15 @@ func addArtifactPrepareVersionFlags(cmd *cobra.Command, stepConfig *artifactPrep
 	cmd.Flags().StringVar(&stepConfig.M2Path, "m2Path", os.Getenv("PIPER_m2Path"), "Maven only - Path to the location of the local repository that should be used.")
 	cmd.Flags().StringVar(&stepConfig.Password, "password", os.Getenv("PIPER_password"), "Password/token for git authentication.")
 	cmd.Flags().StringVar(&stepConfig.ProjectSettingsFile, "projectSettingsFile", os.Getenv("PIPER_projectSettingsFile"), "Maven only - Path to the mvn settings file that should be used as project settings file.")
+
+	cmd.Flags().StringSliceVar(&stepConfig.SemanticReleaseBranches, "semanticReleaseBranches", []string{`main`, `master`}, "For `versioningType: semantic`: Defines the branches from which releases without pre-release identifier are built.")
 	cmd.Flags().BoolVar(&stepConfig.ShortCommitID, "shortCommitId", false, "Defines if a short version of the commitId should be used. GitHub format is used (first 7 characters).")
-	cmd.Flags().StringVar(&stepConfig.TagPrefix, "tagPrefix", `build_`, "Defines the prefix which is used for the git tag which is written during the versioning run (only `versioningType: cloud`).")
+	cmd.Flags().StringVar(&stepConfig.TagPrefix, "tagPrefix", `build_`, "Defines the prefix which is used for the git tag which is written during the versioning run (only `versioningType: cloud` and `versioningType: semantic`).")
 	cmd.Flags().BoolVar(&stepConfig.UnixTimestamp, "unixTimestamp", false, "Defines if the Unix timestamp number should be used as build number instead of the standard date format.")
 	cmd.Flags().StringVar(&stepConfig.Username, "username", os.Getenv("PIPER_username"), "User name for git authentication")
 	cmd.Flags().StringVar(&stepConfig.VersioningTemplate, "versioningTemplate", os.Getenv("PIPER_versioningTemplate"), "DEPRECATED: Defines the template for the automatic version which will be created")
 	cmd.Flags().StringVar(&stepConfig.VersioningType, "versioningType", `cloud`, "Defines the type of versioning")
+	cmd.Flags().StringVar(&stepConfig.WorkspaceDescriptor, "workspaceDescriptor", os.Getenv("PIPER_workspaceDescriptor"), "Path to the workspace descriptor of a monorepo listing the projects which are versioned independently, see [Monorepo with independently versioned projects](#monorepo-with-independently-versioned-projects).")
 
 	cmd.MarkFlagRequired("buildTool")
 }
@@ -445,6 +510,23 @@ func artifactPrepareVersionMetadata() config.StepData {
 						Aliases:     []config.Alias{{Name: "maven/projectSettingsFile"}},
 						Default:     os.Getenv("PIPER_projectSettingsFile"),
 					},
+					{
+						Name:        "semanticPrereleaseChannels",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "map[string]interface{}",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
+					{
+						Name:        "semanticReleaseBranches",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{`main`, `master`},
+					},
 					{
 						Name:        "shortCommitId",
 						ResourceRef: []config.ResourceReference{},
@@ -511,6 +593,15 @@ func artifactPrepareVersionMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     `cloud`,
 					},
+					{
+						Name:        "workspaceDescriptor",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_workspaceDescriptor"),
+					},
 				},
 			},
 			Containers: []config.Container{
@@ -530,6 +621,8 @@ func artifactPrepareVersionMetadata() config.StepData {
 							{"name": "git/commitId"},
 							{"name": "git/headCommitId"},
 							{"name": "git/commitMessage"},
+							{"name": "custom/releaseNotes"},
+							{"name": "custom/workspaceProjects", "type": "piperenv.Projects"},
 						},
 					},
 				},
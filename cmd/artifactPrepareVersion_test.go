@@ -8,6 +8,7 @@ import (
 
 	"github.com/SAP/jenkins-library/pkg/mock"
 	"github.com/SAP/jenkins-library/pkg/orchestrator"
+	"github.com/SAP/jenkins-library/pkg/piperenv"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 	"github.com/SAP/jenkins-library/pkg/versioning"
 
@@ -75,6 +76,7 @@ type gitRepositoryMock struct {
 	revisionHash        plumbing.Hash
 	revisionError       string
 	tag                 string
+	tags                []string
 	tagHash             plumbing.Hash
 	tagError            string
 	worktree            *git.Worktree
@@ -92,6 +94,7 @@ func (r *gitRepositoryMock) CreateTag(name string, hash plumbing.Hash, opts *git
 		return nil, fmt.Errorf(r.tagError)
 	}
 	r.tag = name
+	r.tags = append(r.tags, name)
 	r.tagHash = hash
 	return nil, nil
 }
@@ -170,6 +173,32 @@ func (w *gitWorktreeMock) Commit(msg string, opts *git.CommitOptions) (plumbing.
 	return w.commitHash, nil
 }
 
+type releaseHistoryMock struct {
+	tags         []string
+	commits      map[string][]*object.Commit
+	changedFiles map[string][]string
+}
+
+func (h *releaseHistoryMock) ReleaseTags(prefix string) ([]string, error) {
+	return h.tags, nil
+}
+
+func (h *releaseHistoryMock) CommitsSince(tag string) ([]*object.Commit, error) {
+	commits, ok := h.commits[tag]
+	if !ok {
+		return nil, fmt.Errorf("tag '%v' not found", tag)
+	}
+	return commits, nil
+}
+
+func (h *releaseHistoryMock) FilesChangedSince(tag string) ([]string, error) {
+	files, ok := h.changedFiles[tag]
+	if !ok {
+		return nil, fmt.Errorf("tag '%v' not found", tag)
+	}
+	return files, nil
+}
+
 type artifactPrepareVersionMockUtils struct {
 	*mock.ExecMockRunner
 	*mock.FilesMock
@@ -311,6 +340,57 @@ func TestRunArtifactPrepareVersion(t *testing.T) {
 		assert.Equal(t, "1.2.3", cpe.artifactVersion)
 	})
 
+	t.Run("success case - semantic", func(t *testing.T) {
+		config := artifactPrepareVersionOptions{
+			BuildTool:               "maven",
+			Password:                "****",
+			TagPrefix:               "v",
+			Username:                "testUser",
+			VersioningType:          "semantic",
+			SemanticReleaseBranches: []string{"main"},
+		}
+
+		cpe := artifactPrepareVersionCommonPipelineEnvironment{}
+
+		versioningMock := artifactVersioningMock{
+			originalVersion:  "1.2.3",
+			versioningScheme: "maven",
+		}
+
+		worktree := gitWorktreeMock{
+			commitHash: plumbing.ComputeHash(plumbing.CommitObject, []byte{2, 3, 4}),
+		}
+
+		conf := gitConfig.RemoteConfig{Name: "origin", URLs: []string{"https://my.test.server"}}
+
+		repo := gitRepositoryMock{
+			revisionHash: plumbing.ComputeHash(plumbing.CommitObject, []byte{1, 2, 3}),
+			remote:       git.NewRemote(nil, &conf),
+		}
+
+		defer func(f func(gitRepository) (releaseHistory, error)) { newReleaseHistory = f }(newReleaseHistory)
+		newReleaseHistory = func(gitRepository) (releaseHistory, error) {
+			return &releaseHistoryMock{
+				tags: []string{"v1.2.3"},
+				commits: map[string][]*object.Commit{"v1.2.3": {
+					{Hash: plumbing.ComputeHash(plumbing.CommitObject, []byte{5}), Message: "feat: dark mode"},
+					{Hash: plumbing.ComputeHash(plumbing.CommitObject, []byte{6}), Message: "docs: describe dark mode"},
+				}},
+			}, nil
+		}
+
+		err := runArtifactPrepareVersion(&config, &telemetry.CustomData{}, &cpe, &versioningMock, newArtifactPrepareVersionMockUtils(), &repo, func(r gitRepository) (gitWorktree, error) { return &worktree, nil })
+
+		assert.NoError(t, err)
+		assert.Equal(t, "1.3.0", versioningMock.newVersion)
+		assert.Equal(t, "v1.3.0", repo.tag)
+		assert.True(t, repo.pushCalled)
+		assert.Equal(t, "1.3.0", cpe.artifactVersion)
+		assert.Equal(t, "1.2.3", cpe.originalArtifactVersion)
+		assert.Contains(t, cpe.custom.releaseNotes, "### Features\n\n* dark mode (")
+		assert.NotContains(t, cpe.custom.releaseNotes, "describe dark mode")
+	})
+
 	t.Run("success case - library", func(t *testing.T) {
 		config := artifactPrepareVersionOptions{
 			BuildTool:      "maven",
@@ -547,6 +627,132 @@ func TestRunArtifactPrepareVersion(t *testing.T) {
 	})
 }
 
+func TestRunArtifactPrepareVersionWorkspace(t *testing.T) {
+	workspaceDescriptor := `projects:
+  - name: app
+    path: services/app
+    buildTool: cargo
+    dependencies: [lib]
+  - name: lib
+    path: libs/lib
+    buildTool: cargo
+  - name: tool
+    path: tools/tool
+    buildTool: cargo
+`
+	newUtils := func() *artifactPrepareVersionMockUtils {
+		utils := newArtifactPrepareVersionMockUtils()
+		utils.AddFile("workspace.yml", []byte(workspaceDescriptor))
+		utils.AddFile("services/app/Cargo.toml", []byte("[package]\nname = \"app\"\nversion = \"2.0.0\"\n"))
+		utils.AddFile("libs/lib/Cargo.toml", []byte("[package]\nname = \"lib\"\nversion = \"1.1.0\"\n"))
+		utils.AddFile("tools/tool/Cargo.toml", []byte("[package]\nname = \"tool\"\nversion = \"0.3.0\"\n"))
+		return utils
+	}
+	history := &releaseHistoryMock{
+		tags: []string{"app/2.0.0-20221001120000", "lib/1.1.0-20221001120000", "tool/0.3.0-20221001120000", "tool/0.2.0-20220901120000"},
+		changedFiles: map[string][]string{
+			"app/2.0.0-20221001120000":  {"libs/lib/src/lib.rs"},
+			"lib/1.1.0-20221001120000":  {"libs/lib/src/lib.rs"},
+			"tool/0.3.0-20221001120000": {"libs/lib/src/lib.rs"},
+		},
+	}
+	defer func(f func(gitRepository) (releaseHistory, error)) { newReleaseHistory = f }(newReleaseHistory)
+	newReleaseHistory = func(gitRepository) (releaseHistory, error) { return history, nil }
+
+	t.Run("success case - cloud", func(t *testing.T) {
+		config := artifactPrepareVersionOptions{
+			Password:            "****",
+			Username:            "testUser",
+			VersioningType:      "cloud",
+			WorkspaceDescriptor: "workspace.yml",
+		}
+		cpe := artifactPrepareVersionCommonPipelineEnvironment{}
+		utils := newUtils()
+		worktree := gitWorktreeMock{
+			commitHash: plumbing.ComputeHash(plumbing.CommitObject, []byte{2, 3, 4}),
+		}
+		conf := gitConfig.RemoteConfig{Name: "origin", URLs: []string{"https://my.test.server"}}
+		repo := gitRepositoryMock{
+			revisionHash: plumbing.ComputeHash(plumbing.CommitObject, []byte{1, 2, 3}),
+			remote:       git.NewRemote(nil, &conf),
+		}
+
+		err := runArtifactPrepareVersion(&config, &telemetry.CustomData{}, &cpe, nil, utils, &repo, func(r gitRepository) (gitWorktree, error) { return &worktree, nil })
+
+		assert.NoError(t, err)
+		if assert.Len(t, cpe.custom.workspaceProjects, 3) {
+			// projects are sorted in dependency order
+			lib, tool, app := cpe.custom.workspaceProjects[0], cpe.custom.workspaceProjects[1], cpe.custom.workspaceProjects[2]
+			assert.Equal(t, "lib", lib.Name)
+			assert.True(t, lib.Changed)
+			assert.Regexp(t, `^1\.1\.0-\d{14}$`, lib.Version)
+			assert.Equal(t, "app", app.Name)
+			assert.True(t, app.Changed)
+			assert.Regexp(t, `^2\.0\.0-\d{14}$`, app.Version)
+			assert.Equal(t, piperenv.Project{Name: "tool", Path: "tools/tool", BuildTool: "cargo", Version: "0.3.0-20221001120000"}, tool)
+
+			assert.Equal(t, []string{"lib/" + lib.Version, "app/" + app.Version}, repo.tags)
+			content, _ := utils.FileRead("libs/lib/Cargo.toml")
+			assert.Contains(t, string(content), "version = \""+lib.Version+"\"")
+		}
+		content, _ := utils.FileRead("tools/tool/Cargo.toml")
+		assert.Contains(t, string(content), "version = \"0.3.0\"")
+		assert.True(t, repo.pushCalled)
+		assert.Len(t, repo.pushOptions.RefSpecs, 2)
+		assert.Equal(t, worktree.commitHash.String(), cpe.git.commitID)
+		assert.Equal(t, repo.revisionHash.String(), cpe.git.headCommitID)
+	})
+
+	t.Run("success case - library", func(t *testing.T) {
+		config := artifactPrepareVersionOptions{
+			VersioningType:      "library",
+			WorkspaceDescriptor: "workspace.yml",
+		}
+		cpe := artifactPrepareVersionCommonPipelineEnvironment{}
+		repo := gitRepositoryMock{
+			revisionHash: plumbing.ComputeHash(plumbing.CommitObject, []byte{1, 2, 3}),
+		}
+
+		err := runArtifactPrepareVersion(&config, &telemetry.CustomData{}, &cpe, nil, newUtils(), &repo, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, piperenv.Projects{
+			{Name: "lib", Path: "libs/lib", BuildTool: "cargo", Version: "1.1.0", Changed: true},
+			{Name: "tool", Path: "tools/tool", BuildTool: "cargo", Version: "0.3.0-20221001120000"},
+			{Name: "app", Path: "services/app", BuildTool: "cargo", Version: "2.0.0", Changed: true},
+		}, cpe.custom.workspaceProjects)
+		assert.Empty(t, repo.tags)
+		assert.Equal(t, repo.revisionHash.String(), cpe.git.commitID)
+	})
+
+	t.Run("error case - semantic", func(t *testing.T) {
+		config := artifactPrepareVersionOptions{
+			VersioningType:      "semantic",
+			WorkspaceDescriptor: "workspace.yml",
+		}
+
+		err := runArtifactPrepareVersion(&config, &telemetry.CustomData{}, &artifactPrepareVersionCommonPipelineEnvironment{}, nil, newUtils(), &gitRepositoryMock{}, nil)
+
+		assert.EqualError(t, err, "versioningType 'semantic' is not supported in combination with a workspaceDescriptor")
+	})
+
+	t.Run("error case - missing version source", func(t *testing.T) {
+		config := artifactPrepareVersionOptions{
+			VersioningType:      "library",
+			WorkspaceDescriptor: "workspace.yml",
+		}
+		utils := newUtils()
+		utils.AddFile("workspace.yml", []byte("projects:\n  - name: lib\n    path: libs/lib\n    buildTool: dotnet\n"))
+		repo := gitRepositoryMock{
+			revisionHash: plumbing.ComputeHash(plumbing.CommitObject, []byte{1, 2, 3}),
+		}
+
+		err := runArtifactPrepareVersion(&config, &telemetry.CustomData{}, &artifactPrepareVersionCommonPipelineEnvironment{}, nil, utils, &repo, nil)
+
+		assert.EqualError(t, err, "no versionSource defined for project 'lib' using build tool 'dotnet'")
+	})
+}
+
 func TestVersioningTemplate(t *testing.T) {
 	tt := []struct {
 		scheme      string
@@ -822,6 +1028,30 @@ func TestPropagateVersion(t *testing.T) {
 		assert.Equal(t, "1.2.4-20200101000000+theGitCommitId", chartMeta.Version)
 	})
 
+	t.Run("success case - cargo, dotnet and xcode", func(t *testing.T) {
+		config := artifactPrepareVersionOptions{
+			VersioningType:              "cloud",
+			AdditionalTargetTools:       []string{"cargo", "dotnet", "xcode"},
+			AdditionalTargetDescriptors: []string{"cli/Cargo.toml", "src/*/*.csproj", "App/Info.plist"},
+		}
+
+		utils := newArtifactPrepareVersionMockUtils()
+		utils.AddFile("cli/Cargo.toml", []byte("[package]\nname = \"cli\"\nversion = \"1.2.3\"\n"))
+		utils.AddFile("src/Service/Service.csproj", []byte("<Project><PropertyGroup><Version>1.2.3</Version></PropertyGroup></Project>"))
+		utils.AddFile("App/Info.plist", []byte("<dict><key>CFBundleShortVersionString</key><string>1.2.3</string><key>CFBundleVersion</key><string>1</string></dict>"))
+		artifactOpts := versioning.Options{}
+
+		err := propagateVersion(&config, utils, &artifactOpts, "1.2.4", gitCommitID, testTime)
+		assert.NoError(t, err)
+
+		content, _ := utils.FileRead("cli/Cargo.toml")
+		assert.Contains(t, string(content), "version = \"1.2.4-20200101000000\"")
+		content, _ = utils.FileRead("src/Service/Service.csproj")
+		assert.Contains(t, string(content), "<Version>1.2.4-20200101000000</Version>")
+		content, _ = utils.FileRead("App/Info.plist")
+		assert.Contains(t, string(content), "<string>1.2.4</string><key>CFBundleVersion</key><string>20200101000000</string>")
+	})
+
 	t.Run("success case - dedicated build descriptors / no cloud", func(t *testing.T) {
 		config := artifactPrepareVersionOptions{
 			VersioningType:              "library",
@@ -882,3 +1112,91 @@ func TestPropagateVersion(t *testing.T) {
 		assert.Contains(t, fmt.Sprint(err), "failed to retrieve artifact")
 	})
 }
+
+func TestCalculateSemanticVersion(t *testing.T) {
+	t.Parallel()
+
+	commit := func(message string) *object.Commit {
+		return &object.Commit{Hash: plumbing.ComputeHash(plumbing.CommitObject, []byte(message)), Message: message}
+	}
+	history := &releaseHistoryMock{
+		tags: []string{"v1.0.0", "v1.1.0", "v2.0.0-beta.1"},
+		commits: map[string][]*object.Commit{
+			"":       {commit("feat: initial version")},
+			"v1.1.0": {commit("fix(api): handle timeouts"), commit("feat!: drop v1 endpoints")},
+		},
+	}
+	config := artifactPrepareVersionOptions{
+		TagPrefix:                  "v",
+		SemanticReleaseBranches:    []string{"main", "master"},
+		SemanticPrereleaseChannels: map[string]interface{}{"develop": "beta"},
+	}
+
+	t.Run("release", func(t *testing.T) {
+		release, err := calculateSemanticVersion(&config, history, "main", "1.1.0")
+		assert.NoError(t, err)
+		assert.Equal(t, "2.0.0", release.version)
+		assert.True(t, release.isNew)
+		assert.Contains(t, release.releaseNotes, "### Breaking Changes\n\n* drop v1 endpoints")
+		assert.Contains(t, release.releaseNotes, "### Bug Fixes\n\n* **api:** handle timeouts")
+	})
+
+	t.Run("pre-release", func(t *testing.T) {
+		release, err := calculateSemanticVersion(&config, history, "develop", "1.1.0")
+		assert.NoError(t, err)
+		assert.Equal(t, "2.0.0-beta.2", release.version)
+	})
+
+	t.Run("no new commits", func(t *testing.T) {
+		history := &releaseHistoryMock{tags: []string{"v1.1.0"}, commits: map[string][]*object.Commit{"v1.1.0": {}}}
+		release, err := calculateSemanticVersion(&config, history, "main", "1.1.0")
+		assert.NoError(t, err)
+		assert.Equal(t, "1.1.0", release.version)
+		assert.False(t, release.isNew)
+	})
+
+	t.Run("only commits without release type", func(t *testing.T) {
+		history := &releaseHistoryMock{tags: []string{"v1.1.0"}, commits: map[string][]*object.Commit{"v1.1.0": {commit("Update README.md")}}}
+		release, err := calculateSemanticVersion(&config, history, "main", "1.1.0")
+		assert.NoError(t, err)
+		assert.Equal(t, "1.1.1", release.version)
+		assert.Empty(t, release.releaseNotes)
+	})
+
+	t.Run("first release", func(t *testing.T) {
+		history := &releaseHistoryMock{commits: history.commits}
+		release, err := calculateSemanticVersion(&config, history, "main", "0.1.0-SNAPSHOT")
+		assert.NoError(t, err)
+		assert.Equal(t, "0.1.0", release.version)
+		assert.Contains(t, release.releaseNotes, "initial version")
+	})
+
+	t.Run("first release with invalid version", func(t *testing.T) {
+		history := &releaseHistoryMock{}
+		_, err := calculateSemanticVersion(&config, history, "main", "latest")
+		assert.Contains(t, err.Error(), "version 'latest' of the build descriptor is no semantic version")
+	})
+}
+
+func TestSemanticChannel(t *testing.T) {
+	t.Parallel()
+
+	config := artifactPrepareVersionOptions{
+		SemanticReleaseBranches:    []string{"main", "master"},
+		SemanticPrereleaseChannels: map[string]interface{}{"develop": "beta"},
+	}
+
+	tt := []struct {
+		branch   string
+		expected string
+	}{
+		{branch: "main", expected: ""},
+		{branch: "refs/heads/master", expected: ""},
+		{branch: "n/a", expected: ""},
+		{branch: "develop", expected: "beta"},
+		{branch: "feature/Dark_Mode", expected: "feature-dark-mode"},
+	}
+	for _, test := range tt {
+		assert.Equal(t, test.expected, semanticChannel(&config, test.branch), test.branch)
+	}
+}
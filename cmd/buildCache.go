@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"github.com/SAP/jenkins-library/pkg/buildcache"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/orchestrator"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+)
+
+// newBuildCache creates the cache for the dependencies of build tools. It returns a nil cache, which never restores
+// anything, if caching is disabled or the cache cannot be accessed. The returned function releases the resources of the cache.
+func newBuildCache(enabled bool, location string, maxSizeMB int, utils piperutils.FileUtils) (*buildcache.BuildCache, func()) {
+	noop := func() {}
+	if !enabled {
+		return nil, noop
+	}
+	if len(location) == 0 {
+		log.Entry().Info("build cache is disabled, because buildCacheLocation is empty")
+		return nil, noop
+	}
+	store, closeStore := newCacheStore("build cache", location, utils)
+	if store == nil {
+		return nil, closeStore
+	}
+	return buildcache.New(store, maxSizeMB, buildCacheScope()), closeStore
+}
+
+// buildCacheScope identifies the repository of the build, the fallback archives of the build cache are only shared
+// between builds of the same repository
+var buildCacheScope = func() string {
+	provider, err := orchestrator.NewOrchestratorSpecificConfigProvider()
+	if err != nil {
+		return ""
+	}
+	if repoURL := provider.GetRepoURL(); repoURL != "n/a" {
+		return repoURL
+	}
+	return ""
+}
+
+// runWithBuildCache restores the cache directories, runs the build and saves the directories in case the build succeeded
+func runWithBuildCache(cache *buildcache.BuildCache, directories []*buildcache.Directory, build func() error) error {
+	for _, directory := range directories {
+		cache.Restore(directory)
+	}
+	if err := build(); err != nil {
+		return err
+	}
+	for _, directory := range directories {
+		cache.Save(directory)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/buildcache"
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewBuildCache(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		cache, closeCache := newBuildCache(false, "buildcache", 1024, &mock.FilesMock{})
+		defer closeCache()
+		assert.Nil(t, cache)
+	})
+
+	t.Run("without location", func(t *testing.T) {
+		cache, closeCache := newBuildCache(true, "", 1024, &mock.FilesMock{})
+		defer closeCache()
+		assert.Nil(t, cache)
+	})
+
+	t.Run("unsupported location", func(t *testing.T) {
+		cache, closeCache := newBuildCache(true, "https://example.org/cache", 1024, &mock.FilesMock{})
+		defer closeCache()
+		assert.Nil(t, cache)
+	})
+
+	t.Run("local directory", func(t *testing.T) {
+		cache, closeCache := newBuildCache(true, "buildcache", 1024, &mock.FilesMock{})
+		defer closeCache()
+		assert.NotNil(t, cache)
+	})
+}
+
+func TestRunWithBuildCache(t *testing.T) {
+	defer func(scope func() string) { buildCacheScope = scope }(buildCacheScope)
+	buildCacheScope = func() string { return "https://github.com/SAP/jenkins-library" }
+	dir := t.TempDir()
+	cache, closeCache := newBuildCache(true, filepath.Join(dir, "buildcache"), 1024, &piperutils.Files{})
+	defer closeCache()
+	repository := filepath.Join(dir, "repository")
+
+	t.Run("failed build", func(t *testing.T) {
+		err := runWithBuildCache(cache, []*buildcache.Directory{{Name: "maven", Path: repository, Key: "abc"}}, func() error {
+			require.NoError(t, os.MkdirAll(repository, 0o755))
+			return errors.New("build failed")
+		})
+
+		assert.EqualError(t, err, "build failed")
+		assert.NoFileExists(t, filepath.Join(dir, "buildcache", "maven-abc.tar.gz"))
+	})
+
+	t.Run("successful build", func(t *testing.T) {
+		err := runWithBuildCache(cache, []*buildcache.Directory{{Name: "maven", Path: repository, Key: "abc"}}, func() error {
+			return os.WriteFile(filepath.Join(repository, "lib.jar"), []byte("jar"), 0o644)
+		})
+
+		assert.NoError(t, err)
+		assert.FileExists(t, filepath.Join(dir, "buildcache", "maven-abc.tar.gz"))
+		fallback, _ := filepath.Glob(filepath.Join(dir, "buildcache", "*-maven.tar.gz"))
+		assert.Len(t, fallback, 1)
+	})
+
+	t.Run("restore", func(t *testing.T) {
+		target := filepath.Join(dir, "restored")
+
+		err := runWithBuildCache(cache, []*buildcache.Directory{{Name: "maven", Path: target, Key: "abc"}}, func() error {
+			assert.FileExists(t, filepath.Join(target, "lib.jar"))
+			return nil
+		})
+
+		assert.NoError(t, err)
+	})
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/SAP/jenkins-library/pkg/cachestore"
+	"github.com/SAP/jenkins-library/pkg/gcs"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// newCacheStore creates the store of the build or the scan cache, named by cacheName, for the location. It returns a nil
+// store if the location cannot be accessed. The returned function releases the resources of the store.
+func newCacheStore(cacheName, location string, utils piperutils.FileUtils) (cachestore.Store, func()) {
+	noop := func() {}
+	cacheLocation, err := cachestore.ParseLocation(location)
+	if err != nil {
+		log.Entry().WithError(err).Warnf("%v is disabled", cacheName)
+		return nil, noop
+	}
+
+	switch cacheLocation.Scheme {
+	case "gs":
+		envVars := []gcs.EnvVar{
+			{Name: "GOOGLE_APPLICATION_CREDENTIALS", Value: GeneralConfig.GCPJsonKeyFilePath, Modified: false},
+		}
+		gcsClient, err := gcs.NewClient(gcs.WithEnvVars(envVars))
+		if err != nil {
+			log.Entry().WithError(err).Warnf("%v is disabled, creation of GCS client failed", cacheName)
+			return nil, noop
+		}
+		return &cachestore.GCSStore{Client: gcsClient, Bucket: cacheLocation.Bucket, Folder: cacheLocation.Path}, func() { _ = gcsClient.Close() }
+	case "s3":
+		cfg, err := config.LoadDefaultConfig(context.TODO())
+		if err != nil {
+			log.Entry().WithError(err).Warnf("%v is disabled, AWS client configuration failed", cacheName)
+			return nil, noop
+		}
+		return &cachestore.S3Store{Client: s3.NewFromConfig(cfg), Bucket: cacheLocation.Bucket, Prefix: cacheLocation.Path, Files: utils}, noop
+	default:
+		return &cachestore.LocalStore{Dir: cacheLocation.Path, Files: utils}, noop
+	}
+}
@@ -0,0 +1,381 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/checkmarxone"
+	piperGithub "github.com/SAP/jenkins-library/pkg/github"
+	piperHttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/reporting"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/SAP/jenkins-library/pkg/toolrecord"
+	"github.com/pkg/errors"
+)
+
+// checkmarxOnePollInterval defines the time between two requests for the status of a Checkmarx One scan
+var checkmarxOnePollInterval = 10 * time.Second
+
+func checkmarxOneExecuteScan(config checkmarxOneExecuteScanOptions, _ *telemetry.CustomData, influx *checkmarxOneExecuteScanInflux) {
+	client := &piperHttp.Client{}
+	client.SetOptions(piperHttp.ClientOptions{MaxRetries: config.MaxRetries, TransportTimeout: 15 * time.Minute})
+	// TODO provide parameter for trusted certs
+	ctx, ghClient, err := piperGithub.NewClient(config.GithubToken, config.GithubAPIURL, "", []string{})
+	if err != nil {
+		log.Entry().WithError(err).Warning("Failed to get GitHub client")
+	}
+	sys, err := checkmarxone.NewSystemInstance(client, config.ServerURL, config.IamURL, config.Tenant, config.APIKey)
+	if err != nil {
+		log.Entry().WithError(err).Fatalf("Failed to create Checkmarx One client talking to URL %v", config.ServerURL)
+	}
+	influx.step_data.fields.checkmarxone = false
+	utils := newCheckmarxExecuteScanUtilsBundle("./", ghClient)
+	if err := runCheckmarxOneScan(ctx, config, sys, influx, utils); err != nil {
+		log.Entry().WithError(err).Fatal("Failed to execute Checkmarx One scan.")
+	}
+	influx.step_data.fields.checkmarxone = true
+}
+
+func runCheckmarxOneScan(ctx context.Context, config checkmarxOneExecuteScanOptions, sys checkmarxone.System, influx *checkmarxOneExecuteScanInflux, utils checkmarxExecuteScanUtils) error {
+	if len(config.ProjectName) == 0 {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return errors.New("You need to provide the Checkmarx One project name, projectName parameter is mandatory")
+	}
+	for _, scanType := range config.ScanTypes {
+		if !piperutils.ContainsString([]string{checkmarxone.ScanTypeSAST, checkmarxone.ScanTypeSCA, checkmarxone.ScanTypeKICS}, scanType) {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return fmt.Errorf("scan type %v not supported, supported are sast, sca and kics", scanType)
+		}
+	}
+
+	project, err := loadOrCreateCheckmarxOneProject(config, sys)
+	if err != nil {
+		return err
+	}
+	branch := checkmarxOneBranch(config, project)
+
+	var scan checkmarxone.Scan
+	if config.VerifyOnly {
+		scans, err := sys.GetLastScans(project.ID, branch, 1)
+		if err != nil {
+			return errors.Wrapf(err, "cannot load scans of project %v", project.Name)
+		}
+		if len(scans) == 0 {
+			return fmt.Errorf("no completed scan of branch %v of project %v found, verification only mode aborted", branch, project.Name)
+		}
+		scan = scans[0]
+	} else {
+		scan, err = uploadAndScanCheckmarxOne(config, sys, project, branch, utils)
+		if err != nil {
+			return errors.Wrap(err, "scan and upload returned an error")
+		}
+	}
+	return verifyCheckmarxOneProjectCompliance(ctx, config, sys, project, scan, influx, utils)
+}
+
+func loadOrCreateCheckmarxOneProject(config checkmarxOneExecuteScanOptions, sys checkmarxone.System) (checkmarxone.Project, error) {
+	projects, err := sys.GetProjectsByName(config.ProjectName)
+	if err != nil {
+		return checkmarxone.Project{}, errors.Wrap(err, "error when trying to load project")
+	}
+	for _, project := range projects {
+		if strings.EqualFold(project.Name, config.ProjectName) { // case insensitive string comparison
+			log.Entry().Infof("Project %v exists...", project.Name)
+			return project, nil
+		}
+	}
+
+	log.Entry().Infof("Project %v does not exist, starting to create it...", config.ProjectName)
+	applicationID := ""
+	if len(config.ApplicationName) > 0 {
+		application, err := loadOrCreateCheckmarxOneApplication(config.ApplicationName, sys)
+		if err != nil {
+			return checkmarxone.Project{}, err
+		}
+		applicationID = application.ID
+	}
+	project, err := sys.CreateProject(config.ProjectName, applicationID, config.Groups)
+	if err != nil {
+		return checkmarxone.Project{}, errors.Wrapf(err, "failed to create new project %v", config.ProjectName)
+	}
+	log.Entry().Debugf("New Project %v created", project.Name)
+	return project, nil
+}
+
+func loadOrCreateCheckmarxOneApplication(applicationName string, sys checkmarxone.System) (checkmarxone.Application, error) {
+	applications, err := sys.GetApplicationsByName(applicationName)
+	if err != nil {
+		return checkmarxone.Application{}, errors.Wrap(err, "error when trying to load application")
+	}
+	if len(applications) > 0 {
+		return applications[0], nil
+	}
+	log.Entry().Infof("Application %v does not exist, starting to create it...", applicationName)
+	application, err := sys.CreateApplication(applicationName)
+	if err != nil {
+		return checkmarxone.Application{}, errors.Wrapf(err, "failed to create new application %v", applicationName)
+	}
+	return application, nil
+}
+
+// checkmarxOneBranch returns the branch the scan is assigned to, pull requests are scanned as branches of the project
+func checkmarxOneBranch(config checkmarxOneExecuteScanOptions, project checkmarxone.Project) string {
+	switch {
+	case len(config.PullRequestName) > 0:
+		return config.PullRequestName
+	case len(config.Branch) > 0:
+		return config.Branch
+	case len(project.MainBranch) > 0:
+		return project.MainBranch
+	}
+	return "main"
+}
+
+func uploadAndScanCheckmarxOne(config checkmarxOneExecuteScanOptions, sys checkmarxone.System, project checkmarxone.Project, branch string, utils checkmarxExecuteScanUtils) (checkmarxone.Scan, error) {
+	zipFile, err := zipWorkspaceFiles(config.FilterPattern, utils)
+	if err != nil {
+		return checkmarxone.Scan{}, errors.Wrap(err, "failed to zip workspace files")
+	}
+	uploadURL, err := sys.UploadProjectSourceCode(zipFile.Name())
+	if err != nil {
+		return checkmarxone.Scan{}, errors.Wrapf(err, "failed to upload source code for project %v", project.Name)
+	}
+	log.Entry().Debugf("Source code uploaded for project %v", project.Name)
+	if err := os.Remove(zipFile.Name()); err != nil {
+		log.Entry().WithError(err).Warnf("Failed to delete zipped source code for project %v", project.Name)
+	}
+
+	scan, err := sys.ScanProject(project.ID, uploadURL, branch, checkmarxOneScanConfigurations(config))
+	if err != nil {
+		return checkmarxone.Scan{}, errors.Wrapf(err, "cannot scan project %v", project.Name)
+	}
+	log.Entry().Debugf("Scanning project %v ", project.Name)
+	scan, err = pollCheckmarxOneScanStatus(sys, scan, time.Duration(config.PollingMinutes)*time.Minute)
+	if err != nil {
+		return scan, errors.Wrap(err, "polling scan status failed")
+	}
+	log.Entry().Debugln("Scan finished")
+	return scan, nil
+}
+
+func checkmarxOneScanConfigurations(config checkmarxOneExecuteScanOptions) []checkmarxone.ScanConfiguration {
+	configurations := []checkmarxone.ScanConfiguration{}
+	for _, scanType := range config.ScanTypes {
+		value := map[string]string{}
+		if scanType == checkmarxone.ScanTypeSAST {
+			incremental := config.Incremental && !config.IsOptimizedAndScheduled
+			value["incremental"] = fmt.Sprint(incremental)
+			if len(config.Preset) > 0 {
+				value["presetName"] = config.Preset
+			}
+		}
+		configurations = append(configurations, checkmarxone.ScanConfiguration{Type: scanType, Value: value})
+	}
+	return configurations
+}
+
+func pollCheckmarxOneScanStatus(sys checkmarxone.System, scan checkmarxone.Scan, timeout time.Duration) (checkmarxone.Scan, error) {
+	pastStatus := ""
+	deadline := time.Now().Add(timeout)
+	for {
+		var err error
+		scan, err = sys.GetScan(scan.ID)
+		if err != nil {
+			return scan, err
+		}
+		if scan.Status != pastStatus {
+			log.Entry().Infof("Scan phase: %v", scan.Status)
+			pastStatus = scan.Status
+		}
+		switch scan.Status {
+		case checkmarxone.ScanStatusCompleted:
+			return scan, nil
+		case checkmarxone.ScanStatusPartial:
+			log.Entry().Warnf("Scan finished partially: %v", checkmarxOneScanDetails(scan))
+			return scan, nil
+		case checkmarxone.ScanStatusCanceled:
+			log.SetErrorCategory(log.ErrorCustom)
+			return scan, fmt.Errorf("scan canceled via web interface")
+		case checkmarxone.ScanStatusFailed:
+			return scan, fmt.Errorf("Checkmarx One scan failed with the following error: %v", checkmarxOneScanDetails(scan))
+		}
+		if time.Now().After(deadline) {
+			log.SetErrorCategory(log.ErrorService)
+			return scan, fmt.Errorf("scan did not finish within %v, last status: %v", timeout, scan.Status)
+		}
+		log.Entry().Debug("Polling for status: sleeping...")
+		time.Sleep(checkmarxOnePollInterval)
+	}
+}
+
+func checkmarxOneScanDetails(scan checkmarxone.Scan) string {
+	details := []string{}
+	for _, detail := range scan.StatusDetails {
+		if detail.Status == checkmarxone.ScanStatusFailed || detail.Status == checkmarxone.ScanStatusCanceled {
+			details = append(details, fmt.Sprintf("%v: %v", detail.Name, detail.Details))
+		}
+	}
+	return strings.Join(details, ", ")
+}
+
+func verifyCheckmarxOneProjectCompliance(ctx context.Context, config checkmarxOneExecuteScanOptions, sys checkmarxone.System, project checkmarxone.Project, scan checkmarxone.Scan, influx *checkmarxOneExecuteScanInflux, utils checkmarxExecuteScanUtils) error {
+	var reports []piperutils.Path
+	results, err := sys.GetResults(scan.ID)
+	if err != nil {
+		return errors.Wrap(err, "failed to get results")
+	}
+	// only results of the configured scanners are evaluated, since verifyOnly may pick up a scan with other scanners
+	results = checkmarxone.FilterResults(results, config.ScanTypes)
+	counts := checkmarxone.CountByType(results)
+	deepLink := sys.DeepLink(project.ID, scan.ID, scan.Branch)
+	detailedResults := checkmarxone.DetailedResults(project, scan, checkmarxone.FilterResults(results, []string{checkmarxone.ScanTypeSAST}), deepLink, config.VulnerabilityThresholdLowPerQuery)
+
+	if config.ConvertToSarif {
+		log.Entry().Info("Calling conversion to SARIF function.")
+		paths, err := checkmarxone.WriteSarif(checkmarxone.ConvertToSarif(results, deepLink), utils.GetWorkspace())
+		if err != nil {
+			return fmt.Errorf("failed to write sarif")
+		}
+		reports = append(reports, paths...)
+	}
+
+	toolRecordFileName, err := createToolRecordCxOne(utils, utils.GetWorkspace(), config, detailedResults)
+	if err != nil {
+		// do not fail until the framework is well established
+		log.Entry().Warning("TR_CHECKMARXONE: Failed to create toolrecord file ...", err)
+	} else {
+		reports = append(reports, piperutils.Path{Target: toolRecordFileName})
+	}
+
+	// create JSON report (regardless vulnerabilityThreshold enabled or not)
+	paths, err := checkmarxone.WriteJSONReport(checkmarxone.CreateJSONReport(detailedResults), utils.GetWorkspace())
+	if err != nil {
+		log.Entry().Warning("failed to write JSON report...", err)
+	} else {
+		reports = append(reports, paths...)
+	}
+	links := []piperutils.Path{{Target: deepLink, Name: "Checkmarx One Web UI"}}
+
+	insecure := false
+	var insecureResults []string
+	var neutralResults []string
+
+	if config.VulnerabilityThresholdEnabled {
+		insecure, insecureResults, neutralResults = enforceCheckmarxOneThresholds(config, project, scan, results, deepLink)
+		scanReport := checkmarxone.CreateCustomReport(detailedResults, counts, insecureResults, neutralResults)
+
+		if insecure && config.CreateResultIssue && len(config.GithubToken) > 0 && len(config.GithubAPIURL) > 0 && len(config.Owner) > 0 && len(config.Repository) > 0 {
+			log.Entry().Debug("Creating/updating GitHub issue with check results")
+			gh := reporting.GitHub{
+				Owner:         &config.Owner,
+				Repository:    &config.Repository,
+				Assignees:     &config.Assignees,
+				IssueService:  utils.GetIssueService(),
+				SearchService: utils.GetSearchService(),
+			}
+			if err := gh.UploadSingleReport(ctx, scanReport); err != nil {
+				return fmt.Errorf("failed to upload scan results into GitHub: %w", err)
+			}
+		}
+
+		paths, err := checkmarxone.WriteCustomReports(scanReport, project.Name, project.ID, utils.GetWorkspace())
+		if err != nil {
+			// do not fail until we have a better idea to handle it
+			log.Entry().Warning("failed to write HTML/MarkDown report file ...", err)
+		} else {
+			reports = append(reports, paths...)
+		}
+	}
+
+	piperutils.PersistReportsAndLinks("checkmarxOneExecuteScan", utils.GetWorkspace(), utils, reports, links)
+	checkmarxOneReportToInflux(detailedResults, counts, influx)
+
+	if insecure {
+		if config.VulnerabilityThresholdResult == "FAILURE" {
+			log.SetErrorCategory(log.ErrorCompliance)
+			return fmt.Errorf("the project is not compliant - see report for details")
+		}
+		log.Entry().Errorf("Checkmarx One scan result set to %v, some results are not meeting defined thresholds. For details see the archived report.", config.VulnerabilityThresholdResult)
+	} else {
+		log.Entry().Infoln("Checkmarx One scan finished successfully")
+	}
+	return nil
+}
+
+// enforceCheckmarxOneThresholds evaluates the thresholds for the results of each configured scan type exactly like the ones of Checkmarx SAST
+func enforceCheckmarxOneThresholds(config checkmarxOneExecuteScanOptions, project checkmarxone.Project, scan checkmarxone.Scan, results []checkmarxone.ScanResult, deepLink string) (bool, []string, []string) {
+	thresholds := checkmarxExecuteScanOptions{
+		VulnerabilityThresholdUnit:           config.VulnerabilityThresholdUnit,
+		VulnerabilityThresholdHigh:           config.VulnerabilityThresholdHigh,
+		VulnerabilityThresholdMedium:         config.VulnerabilityThresholdMedium,
+		VulnerabilityThresholdLow:            config.VulnerabilityThresholdLow,
+		VulnerabilityThresholdLowPerQuery:    config.VulnerabilityThresholdLowPerQuery,
+		VulnerabilityThresholdLowPerQueryMax: config.VulnerabilityThresholdLowPerQueryMax,
+	}
+	insecure := false
+	insecureResults := []string{}
+	neutralResults := []string{}
+	for _, scanType := range config.ScanTypes {
+		typeResults := checkmarxone.DetailedResults(project, scan, checkmarxone.FilterResults(results, []string{scanType}), deepLink, config.VulnerabilityThresholdLowPerQuery)
+		typeInsecure, typeInsecureResults, typeNeutralResults := enforceThresholds(thresholds, typeResults)
+		insecure = insecure || typeInsecure
+		for _, result := range typeInsecureResults {
+			insecureResults = append(insecureResults, fmt.Sprintf("%v %v", strings.ToUpper(scanType), result))
+		}
+		for _, result := range typeNeutralResults {
+			neutralResults = append(neutralResults, fmt.Sprintf("%v %v", strings.ToUpper(scanType), result))
+		}
+	}
+	return insecure, insecureResults, neutralResults
+}
+
+func checkmarxOneReportToInflux(results map[string]interface{}, counts map[string]int, influx *checkmarxOneExecuteScanInflux) {
+	influx.checkmarxone_data.fields.high_issues = results["High"].(map[string]int)["Issues"]
+	influx.checkmarxone_data.fields.high_not_false_positive = results["High"].(map[string]int)["NotFalsePositive"]
+	influx.checkmarxone_data.fields.high_to_verify = results["High"].(map[string]int)["ToVerify"]
+	influx.checkmarxone_data.fields.medium_issues = results["Medium"].(map[string]int)["Issues"]
+	influx.checkmarxone_data.fields.medium_not_false_positive = results["Medium"].(map[string]int)["NotFalsePositive"]
+	influx.checkmarxone_data.fields.medium_to_verify = results["Medium"].(map[string]int)["ToVerify"]
+	influx.checkmarxone_data.fields.low_issues = results["Low"].(map[string]int)["Issues"]
+	influx.checkmarxone_data.fields.low_not_false_positive = results["Low"].(map[string]int)["NotFalsePositive"]
+	influx.checkmarxone_data.fields.low_to_verify = results["Low"].(map[string]int)["ToVerify"]
+	influx.checkmarxone_data.fields.information_issues = results["Information"].(map[string]int)["Issues"]
+	influx.checkmarxone_data.fields.sca_issues = counts[checkmarxone.ScanTypeSCA]
+	influx.checkmarxone_data.fields.kics_issues = counts[checkmarxone.ScanTypeKICS]
+	influx.checkmarxone_data.fields.scan_id = results["ScanId"].(string)
+	influx.checkmarxone_data.fields.project_id = results["ProjectId"].(string)
+	influx.checkmarxone_data.fields.projectName = results["ProjectName"].(string)
+	influx.checkmarxone_data.fields.branch = results["Branch"].(string)
+	influx.checkmarxone_data.fields.scan_type = results["ScanType"].(string)
+	influx.checkmarxone_data.fields.deep_link = results["DeepLink"].(string)
+}
+
+func createToolRecordCxOne(utils checkmarxExecuteScanUtils, workspace string, config checkmarxOneExecuteScanOptions, results map[string]interface{}) (string, error) {
+	record := toolrecord.New(utils, workspace, "checkmarxone", config.ServerURL)
+	// Project
+	err := record.AddKeyData("project",
+		results["ProjectId"].(string),
+		results["ProjectName"].(string),
+		"")
+	if err != nil {
+		return "", err
+	}
+	// Scan
+	err = record.AddKeyData("scanid",
+		results["ScanId"].(string),
+		results["ScanId"].(string),
+		results["DeepLink"].(string))
+	if err != nil {
+		return "", err
+	}
+	err = record.Persist()
+	if err != nil {
+		return "", err
+	}
+	return record.GetFileName(), nil
+}
@@ -0,0 +1,700 @@
+// Code generated by piper's step-generator. DO NOT EDIT.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/gcs"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperenv"
+	"github.com/SAP/jenkins-library/pkg/splunk"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/SAP/jenkins-library/pkg/validation"
+	"github.com/bmatcuk/doublestar"
+	"github.com/spf13/cobra"
+)
+
+type checkmarxOneExecuteScanOptions struct {
+	APIKey                               string   `json:"apiKey,omitempty"`
+	ServerURL                            string   `json:"serverUrl,omitempty"`
+	IamURL                               string   `json:"iamUrl,omitempty"`
+	Tenant                               string   `json:"tenant,omitempty"`
+	ApplicationName                      string   `json:"applicationName,omitempty"`
+	ProjectName                          string   `json:"projectName,omitempty"`
+	Groups                               []string `json:"groups,omitempty"`
+	Branch                               string   `json:"branch,omitempty"`
+	PullRequestName                      string   `json:"pullRequestName,omitempty"`
+	ScanTypes                            []string `json:"scanTypes,omitempty" validate:"possible-values=sast sca kics"`
+	PollingMinutes                       int      `json:"pollingMinutes,omitempty"`
+	Preset                               string   `json:"preset,omitempty"`
+	Incremental                          bool     `json:"incremental,omitempty"`
+	FilterPattern                        string   `json:"filterPattern,omitempty"`
+	MaxRetries                           int      `json:"maxRetries,omitempty"`
+	VerifyOnly                           bool     `json:"verifyOnly,omitempty"`
+	VulnerabilityThresholdEnabled        bool     `json:"vulnerabilityThresholdEnabled,omitempty"`
+	VulnerabilityThresholdHigh           int      `json:"vulnerabilityThresholdHigh,omitempty"`
+	VulnerabilityThresholdMedium         int      `json:"vulnerabilityThresholdMedium,omitempty"`
+	VulnerabilityThresholdLow            int      `json:"vulnerabilityThresholdLow,omitempty"`
+	VulnerabilityThresholdLowPerQuery    bool     `json:"vulnerabilityThresholdLowPerQuery,omitempty"`
+	VulnerabilityThresholdLowPerQueryMax int      `json:"vulnerabilityThresholdLowPerQueryMax,omitempty"`
+	VulnerabilityThresholdResult         string   `json:"vulnerabilityThresholdResult,omitempty" validate:"possible-values=FAILURE"`
+	VulnerabilityThresholdUnit           string   `json:"vulnerabilityThresholdUnit,omitempty" validate:"possible-values=percentage absolute"`
+	IsOptimizedAndScheduled              bool     `json:"isOptimizedAndScheduled,omitempty"`
+	ConvertToSarif                       bool     `json:"convertToSarif,omitempty"`
+	CreateResultIssue                    bool     `json:"createResultIssue,omitempty"`
+	Assignees                            []string `json:"assignees,omitempty"`
+	GithubAPIURL                         string   `json:"githubApiUrl,omitempty"`
+	GithubToken                          string   `json:"githubToken,omitempty"`
+	Owner                                string   `json:"owner,omitempty"`
+	Repository                           string   `json:"repository,omitempty"`
+}
+
+type checkmarxOneExecuteScanInflux struct {
+	step_data struct {
+		fields struct {
+			checkmarxone bool
+		}
+		tags struct {
+		}
+	}
+	checkmarxone_data struct {
+		fields struct {
+			high_issues               int
+			high_not_false_positive   int
+			high_to_verify            int
+			medium_issues             int
+			medium_not_false_positive int
+			medium_to_verify          int
+			low_issues                int
+			low_not_false_positive    int
+			low_to_verify             int
+			information_issues        int
+			sca_issues                int
+			kics_issues               int
+			scan_id                   string
+			project_id                string
+			projectName               string
+			branch                    string
+			scan_type                 string
+			deep_link                 string
+		}
+		tags struct {
+		}
+	}
+}
+
+func (i *checkmarxOneExecuteScanInflux) persist(path, resourceName string) {
+	measurementContent := []struct {
+		measurement string
+		valType     string
+		name        string
+		value       interface{}
+	}{
+		{valType: config.InfluxField, measurement: "step_data", name: "checkmarxone", value: i.step_data.fields.checkmarxone},
+		{valType: config.InfluxField, measurement: "checkmarxone_data", name: "high_issues", value: i.checkmarxone_data.fields.high_issues},
+		{valType: config.InfluxField, measurement: "checkmarxone_data", name: "high_not_false_positive", value: i.checkmarxone_data.fields.high_not_false_positive},
+		{valType: config.InfluxField, measurement: "checkmarxone_data", name: "high_to_verify", value: i.checkmarxone_data.fields.high_to_verify},
+		{valType: config.InfluxField, measurement: "checkmarxone_data", name: "medium_issues", value: i.checkmarxone_data.fields.medium_issues},
+		{valType: config.InfluxField, measurement: "checkmarxone_data", name: "medium_not_false_positive", value: i.checkmarxone_data.fields.medium_not_false_positive},
+		{valType: config.InfluxField, measurement: "checkmarxone_data", name: "medium_to_verify", value: i.checkmarxone_data.fields.medium_to_verify},
+		{valType: config.InfluxField, measurement: "checkmarxone_data", name: "low_issues", value: i.checkmarxone_data.fields.low_issues},
+		{valType: config.InfluxField, measurement: "checkmarxone_data", name: "low_not_false_positive", value: i.checkmarxone_data.fields.low_not_false_positive},
+		{valType: config.InfluxField, measurement: "checkmarxone_data", name: "low_to_verify", value: i.checkmarxone_data.fields.low_to_verify},
+		{valType: config.InfluxField, measurement: "checkmarxone_data", name: "information_issues", value: i.checkmarxone_data.fields.information_issues},
+		{valType: config.InfluxField, measurement: "checkmarxone_data", name: "sca_issues", value: i.checkmarxone_data.fields.sca_issues},
+		{valType: config.InfluxField, measurement: "checkmarxone_data", name: "kics_issues", value: i.checkmarxone_data.fields.kics_issues},
+		{valType: config.InfluxField, measurement: "checkmarxone_data", name: "scan_id", value: i.checkmarxone_data.fields.scan_id},
+		{valType: config.InfluxField, measurement: "checkmarxone_data", name: "project_id", value: i.checkmarxone_data.fields.project_id},
+		{valType: config.InfluxField, measurement: "checkmarxone_data", name: "projectName", value: i.checkmarxone_data.fields.projectName},
+		{valType: config.InfluxField, measurement: "checkmarxone_data", name: "branch", value: i.checkmarxone_data.fields.branch},
+		{valType: config.InfluxField, measurement: "checkmarxone_data", name: "scan_type", value: i.checkmarxone_data.fields.scan_type},
+		{valType: config.InfluxField, measurement: "checkmarxone_data", name: "deep_link", value: i.checkmarxone_data.fields.deep_link},
+	}
+
+	errCount := 0
+	for _, metric := range measurementContent {
+		err := piperenv.SetResourceParameter(path, resourceName, filepath.Join(metric.measurement, fmt.Sprintf("%vs", metric.valType), metric.name), metric.value)
+		if err != nil {
+			log.Entry().WithError(err).Error("Error persisting influx environment.")
+			errCount++
+		}
+	}
+	if errCount > 0 {
+		log.Entry().Error("failed to persist Influx environment")
+	}
+}
+
+type checkmarxOneExecuteScanReports struct {
+}
+
+func (p *checkmarxOneExecuteScanReports) persist(stepConfig checkmarxOneExecuteScanOptions, gcpJsonKeyFilePath string, gcsBucketId string, gcsFolderPath string, gcsSubFolder string) {
+	if gcsBucketId == "" {
+		log.Entry().Info("persisting reports to GCS is disabled, because gcsBucketId is empty")
+		return
+	}
+	log.Entry().Info("Uploading reports to Google Cloud Storage...")
+	content := []gcs.ReportOutputParam{
+		{FilePattern: "**/piper_checkmarxone_report.html", ParamRef: "", StepResultType: "checkmarxone"},
+		{FilePattern: "**/piper_checkmarxone_report.json", ParamRef: "", StepResultType: "checkmarxone"},
+		{FilePattern: "**/checkmarxOne/result.sarif", ParamRef: "", StepResultType: "checkmarxone"},
+		{FilePattern: "**/toolrun_checkmarxone_*.json", ParamRef: "", StepResultType: "checkmarxone"},
+	}
+	envVars := []gcs.EnvVar{
+		{Name: "GOOGLE_APPLICATION_CREDENTIALS", Value: gcpJsonKeyFilePath, Modified: false},
+	}
+	gcsClient, err := gcs.NewClient(gcs.WithEnvVars(envVars))
+	if err != nil {
+		log.Entry().Errorf("creation of GCS client failed: %v", err)
+		return
+	}
+	defer gcsClient.Close()
+	structVal := reflect.ValueOf(&stepConfig).Elem()
+	inputParameters := map[string]string{}
+	for i := 0; i < structVal.NumField(); i++ {
+		field := structVal.Type().Field(i)
+		if field.Type.String() == "string" {
+			paramName := strings.Split(field.Tag.Get("json"), ",")
+			paramValue, _ := structVal.Field(i).Interface().(string)
+			inputParameters[paramName[0]] = paramValue
+		}
+	}
+	if err := gcs.PersistReportsToGCS(gcsClient, content, inputParameters, gcsFolderPath, gcsBucketId, gcsSubFolder, doublestar.Glob, os.Stat); err != nil {
+		log.Entry().Errorf("failed to persist reports: %v", err)
+	}
+}
+
+// CheckmarxOneExecuteScanCommand Checkmarx One is the cloud platform of Checkmarx for static code analysis, open source and infrastructure as code scans.
+func CheckmarxOneExecuteScanCommand() *cobra.Command {
+	const STEP_NAME = "checkmarxOneExecuteScan"
+
+	metadata := checkmarxOneExecuteScanMetadata()
+	var stepConfig checkmarxOneExecuteScanOptions
+	var startTime time.Time
+	var influx checkmarxOneExecuteScanInflux
+	var reports checkmarxOneExecuteScanReports
+	var logCollector *log.CollectorHook
+	var splunkClient *splunk.Splunk
+	telemetryClient := &telemetry.Telemetry{}
+
+	var createCheckmarxOneExecuteScanCmd = &cobra.Command{
+		Use:   STEP_NAME,
+		Short: "Checkmarx One is the cloud platform of Checkmarx for static code analysis, open source and infrastructure as code scans.",
+		Long: `This step scans the sources with Checkmarx One, the successor of the on-premise Checkmarx SAST (see step ` + "`" + `checkmarxExecuteScan` + "`" + `).
+Depending on ` + "`" + `scanTypes` + "`" + ` the sources are scanned for security flaws (SAST), vulnerable open source dependencies (SCA) and
+misconfigurations of infrastructure as code (KICS).
+
+The project is created if it does not exist yet, optionally as part of the application ` + "`" + `applicationName` + "`" + `.
+Branches are handled natively by Checkmarx One, thus pull requests are scanned as branch of the same project.
+
+The thresholds are applied to the results of each scan type in the same way as for Checkmarx SAST and therefore ensure by default that:
+
+* No 'To Verify' High and Medium issues exist in your project
+* Total number of High and Medium 'Confirmed' or 'Urgent' issues is zero
+* 10% of all Low issues are 'Confirmed' or 'Not Exploitable'
+
+The results of all scan types are provided as SARIF file and listed in the report.`,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			startTime = time.Now()
+			log.SetStepName(STEP_NAME)
+			log.SetVerbose(GeneralConfig.Verbose)
+
+			GeneralConfig.GitHubAccessTokens = ResolveAccessTokens(GeneralConfig.GitHubTokens)
+
+			path, _ := os.Getwd()
+			fatalHook := &log.FatalHook{CorrelationID: GeneralConfig.CorrelationID, Path: path}
+			log.RegisterHook(fatalHook)
+
+			err := PrepareConfig(cmd, &metadata, STEP_NAME, &stepConfig, config.OpenPiperFile)
+			if err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+			log.RegisterSecret(stepConfig.APIKey)
+			log.RegisterSecret(stepConfig.GithubToken)
+
+			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
+				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
+				log.RegisterHook(&sentryHook)
+			}
+
+			if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+				splunkClient = &splunk.Splunk{}
+				logCollector = &log.CollectorHook{CorrelationID: GeneralConfig.CorrelationID}
+				log.RegisterHook(logCollector)
+			}
+
+			if err = log.RegisterANSHookIfConfigured(GeneralConfig.CorrelationID); err != nil {
+				log.Entry().WithError(err).Warn("failed to set up SAP Alert Notification Service log hook")
+			}
+
+			validation, err := validation.New(validation.WithJSONNamesForStructFields(), validation.WithPredefinedErrorMessages())
+			if err != nil {
+				return err
+			}
+			if err = validation.ValidateStruct(stepConfig); err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+
+			return nil
+		},
+		Run: func(_ *cobra.Command, _ []string) {
+			stepTelemetryData := telemetry.CustomData{}
+			stepTelemetryData.ErrorCode = "1"
+			handler := func() {
+				influx.persist(GeneralConfig.EnvRootPath, "influx")
+				reports.persist(stepConfig, GeneralConfig.GCPJsonKeyFilePath, GeneralConfig.GCSBucketId, GeneralConfig.GCSFolderPath, GeneralConfig.GCSSubFolder)
+				config.RemoveVaultSecretFiles()
+				stepTelemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
+				stepTelemetryData.ErrorCategory = log.GetErrorCategory().String()
+				stepTelemetryData.PiperCommitHash = GitCommit
+				telemetryClient.SetData(&stepTelemetryData)
+				telemetryClient.Send()
+				if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+					splunkClient.Send(telemetryClient.GetData(), logCollector)
+				}
+			}
+			log.DeferExitHandler(handler)
+			defer handler()
+			telemetryClient.Initialize(GeneralConfig.NoTelemetry, STEP_NAME)
+			if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+				splunkClient.Initialize(GeneralConfig.CorrelationID,
+					GeneralConfig.HookConfig.SplunkConfig.Dsn,
+					GeneralConfig.HookConfig.SplunkConfig.Token,
+					GeneralConfig.HookConfig.SplunkConfig.Index,
+					GeneralConfig.HookConfig.SplunkConfig.SendLogs)
+			}
+			checkmarxOneExecuteScan(stepConfig, &stepTelemetryData, &influx)
+			stepTelemetryData.ErrorCode = "0"
+			log.Entry().Info("SUCCESS")
+		},
+	}
+
+	addCheckmarxOneExecuteScanFlags(createCheckmarxOneExecuteScanCmd, &stepConfig)
+	return createCheckmarxOneExecuteScanCmd
+}
+
+func addCheckmarxOneExecuteScanFlags(cmd *cobra.Command, stepConfig *checkmarxOneExecuteScanOptions) {
+	cmd.Flags().StringVar(&stepConfig.APIKey, "apiKey", os.Getenv("PIPER_apiKey"), "The API key to authenticate, which is exchanged for an OAuth access token.")
+	cmd.Flags().StringVar(&stepConfig.ServerURL, "serverUrl", os.Getenv("PIPER_serverUrl"), "The URL pointing to the root of the Checkmarx One server, e.g. `https://eu.ast.checkmarx.net`.")
+	cmd.Flags().StringVar(&stepConfig.IamURL, "iamUrl", os.Getenv("PIPER_iamUrl"), "The URL pointing to the access control server of Checkmarx One, e.g. `https://eu.iam.checkmarx.net`.")
+	cmd.Flags().StringVar(&stepConfig.Tenant, "tenant", os.Getenv("PIPER_tenant"), "The name of the Checkmarx One tenant.")
+	cmd.Flags().StringVar(&stepConfig.ApplicationName, "applicationName", os.Getenv("PIPER_applicationName"), "The name of the Checkmarx One application newly created projects are assigned to. The application is created if it does not exist.")
+	cmd.Flags().StringVar(&stepConfig.ProjectName, "projectName", os.Getenv("PIPER_projectName"), "The name of the Checkmarx One project to scan into.")
+	cmd.Flags().StringSliceVar(&stepConfig.Groups, "groups", []string{}, "The IDs of the groups newly created projects are assigned to.")
+	cmd.Flags().StringVar(&stepConfig.Branch, "branch", os.Getenv("PIPER_branch"), "The branch the scan is assigned to. If not set, the main branch of the project or `main` is used.")
+	cmd.Flags().StringVar(&stepConfig.PullRequestName, "pullRequestName", os.Getenv("PIPER_pullRequestName"), "Used to supply the name of the branch the scan is assigned to in pull request scenarios, it takes precedence over `branch`.")
+	cmd.Flags().StringSliceVar(&stepConfig.ScanTypes, "scanTypes", []string{`sast`}, "The scanners to run.")
+	cmd.Flags().IntVar(&stepConfig.PollingMinutes, "pollingMinutes", 240, "The number of minutes to wait for a scan to finish, if exceeded polling will be stopped and an error will be thrown.")
+	cmd.Flags().StringVar(&stepConfig.Preset, "preset", os.Getenv("PIPER_preset"), "The SAST preset to use for scanning, if not set explicitly the project's setting is used.")
+	cmd.Flags().BoolVar(&stepConfig.Incremental, "incremental", true, "Whether incremental SAST scans are to be applied which optimizes the scan time but might reduce detection capabilities. Scheduled runs of optimized pipelines always trigger full scans.")
+	cmd.Flags().StringVar(&stepConfig.FilterPattern, "filterPattern", `!**/target-static-fixtures/**,!**/dist/**, !**/node_modules/**, !**/.xmake/**, !**/*_test.go, !**/vendor/**/*.go, **/*.html, **/*.xml, **/*.go, **/*.py, **/*.js, **/*.scala, !**/test/**,!**/*_cxsast.html,!**/target/**`, "The filter pattern used to zip the files relevant for scanning, patterns can be negated by setting an exclamation mark in front i.e. `!test/*.js` would avoid adding any javascript files located in the test directory")
+	cmd.Flags().IntVar(&stepConfig.MaxRetries, "maxRetries", 3, "Maximum number of HTTP request retries upon intermittend connetion interrupts")
+	cmd.Flags().BoolVar(&stepConfig.VerifyOnly, "verifyOnly", false, "Whether the step shall only apply verification checks on the latest completed scan of the branch or whether it does a full scan and check cycle")
+	cmd.Flags().BoolVar(&stepConfig.VulnerabilityThresholdEnabled, "vulnerabilityThresholdEnabled", true, "Whether the thresholds are enabled or not. If enabled the build will be set to `vulnerabilityThresholdResult` in case a specific threshold value is exceeded")
+	cmd.Flags().IntVar(&stepConfig.VulnerabilityThresholdHigh, "vulnerabilityThresholdHigh", 100, "The specific threshold for high severity findings")
+	cmd.Flags().IntVar(&stepConfig.VulnerabilityThresholdMedium, "vulnerabilityThresholdMedium", 100, "The specific threshold for medium severity findings")
+	cmd.Flags().IntVar(&stepConfig.VulnerabilityThresholdLow, "vulnerabilityThresholdLow", 10, "The specific threshold for low severity findings")
+	cmd.Flags().BoolVar(&stepConfig.VulnerabilityThresholdLowPerQuery, "vulnerabilityThresholdLowPerQuery", false, "Flag to activate/deactivate the threshold of low severity findings per query")
+	cmd.Flags().IntVar(&stepConfig.VulnerabilityThresholdLowPerQueryMax, "vulnerabilityThresholdLowPerQueryMax", 10, "Upper threshold of low severity findings per query (in absolute number)")
+	cmd.Flags().StringVar(&stepConfig.VulnerabilityThresholdResult, "vulnerabilityThresholdResult", `FAILURE`, "The result of the build in case thresholds are enabled and exceeded")
+	cmd.Flags().StringVar(&stepConfig.VulnerabilityThresholdUnit, "vulnerabilityThresholdUnit", `percentage`, "The unit for the threshold to apply.")
+	cmd.Flags().BoolVar(&stepConfig.IsOptimizedAndScheduled, "isOptimizedAndScheduled", false, "Whether the pipeline runs in optimized mode and the current execution is a scheduled one")
+	cmd.Flags().BoolVar(&stepConfig.ConvertToSarif, "convertToSarif", true, "Convert the Checkmarx One scan results to the open SARIF standard.")
+	cmd.Flags().BoolVar(&stepConfig.CreateResultIssue, "createResultIssue", false, "Activate creation of a result issue in GitHub.")
+	cmd.Flags().StringSliceVar(&stepConfig.Assignees, "assignees", []string{``}, "Defines the assignees for the Github Issue created/updated with the results of the scan as a list of login names.")
+	cmd.Flags().StringVar(&stepConfig.GithubAPIURL, "githubApiUrl", `https://api.github.com`, "Set the GitHub API URL.")
+	cmd.Flags().StringVar(&stepConfig.GithubToken, "githubToken", os.Getenv("PIPER_githubToken"), "GitHub personal access token as per https://help.github.com/en/github/authenticating-to-github/creating-a-personal-access-token-for-the-command-line")
+	cmd.Flags().StringVar(&stepConfig.Owner, "owner", os.Getenv("PIPER_owner"), "Set the GitHub organization.")
+	cmd.Flags().StringVar(&stepConfig.Repository, "repository", os.Getenv("PIPER_repository"), "Set the GitHub repository.")
+
+	cmd.MarkFlagRequired("apiKey")
+	cmd.MarkFlagRequired("serverUrl")
+	cmd.MarkFlagRequired("iamUrl")
+	cmd.MarkFlagRequired("tenant")
+	cmd.MarkFlagRequired("projectName")
+}
+
+// retrieve step metadata
+func checkmarxOneExecuteScanMetadata() config.StepData {
+	var theMetaData = config.StepData{
+		Metadata: config.StepMetadata{
+			Name:        "checkmarxOneExecuteScan",
+			Aliases:     []config.Alias{},
+			Description: "Checkmarx One is the cloud platform of Checkmarx for static code analysis, open source and infrastructure as code scans.",
+		},
+		Spec: config.StepSpec{
+			Inputs: config.StepInputs{
+				Secrets: []config.StepSecrets{
+					{Name: "checkmarxOneCredentialsId", Description: "Jenkins 'Secret text' credentials ID containing the API key to communicate with the Checkmarx One backend.", Type: "jenkins"},
+					{Name: "githubTokenCredentialsId", Description: "Jenkins 'Secret text' credentials ID containing token to authenticate to GitHub.", Type: "jenkins"},
+				},
+				Resources: []config.StepResources{
+					{Name: "checkmarx", Type: "stash"},
+				},
+				Parameters: []config.StepParameters{
+					{
+						Name: "apiKey",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "checkmarxOneCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:    "checkmarxOneVaultSecretName",
+								Type:    "vaultSecret",
+								Default: "checkmarxone",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: true,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_apiKey"),
+					},
+					{
+						Name:        "serverUrl",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   true,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_serverUrl"),
+					},
+					{
+						Name:        "iamUrl",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   true,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_iamUrl"),
+					},
+					{
+						Name:        "tenant",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   true,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_tenant"),
+					},
+					{
+						Name:        "applicationName",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_applicationName"),
+					},
+					{
+						Name:        "projectName",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   true,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_projectName"),
+					},
+					{
+						Name:        "groups",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{},
+					},
+					{
+						Name:        "branch",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_branch"),
+					},
+					{
+						Name:        "pullRequestName",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_pullRequestName"),
+					},
+					{
+						Name:        "scanTypes",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{`sast`},
+					},
+					{
+						Name:        "pollingMinutes",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     240,
+					},
+					{
+						Name:        "preset",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_preset"),
+					},
+					{
+						Name:        "incremental",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     true,
+					},
+					{
+						Name:        "filterPattern",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `!**/target-static-fixtures/**,!**/dist/**, !**/node_modules/**, !**/.xmake/**, !**/*_test.go, !**/vendor/**/*.go, **/*.html, **/*.xml, **/*.go, **/*.py, **/*.js, **/*.scala, !**/test/**,!**/*_cxsast.html,!**/target/**`,
+					},
+					{
+						Name:        "maxRetries",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     3,
+					},
+					{
+						Name:        "verifyOnly",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name:        "vulnerabilityThresholdEnabled",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     true,
+					},
+					{
+						Name:        "vulnerabilityThresholdHigh",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     100,
+					},
+					{
+						Name:        "vulnerabilityThresholdMedium",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     100,
+					},
+					{
+						Name:        "vulnerabilityThresholdLow",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     10,
+					},
+					{
+						Name:        "vulnerabilityThresholdLowPerQuery",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name:        "vulnerabilityThresholdLowPerQueryMax",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     10,
+					},
+					{
+						Name:        "vulnerabilityThresholdResult",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `FAILURE`,
+					},
+					{
+						Name:        "vulnerabilityThresholdUnit",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `percentage`,
+					},
+					{
+						Name: "isOptimizedAndScheduled",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "custom/isOptimizedAndScheduled",
+							},
+						},
+						Scope:     []string{"PARAMETERS"},
+						Type:      "bool",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   false,
+					},
+					{
+						Name:        "convertToSarif",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     true,
+					},
+					{
+						Name: "createResultIssue",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "custom/isOptimizedAndScheduled",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "bool",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   false,
+					},
+					{
+						Name:        "assignees",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{``},
+					},
+					{
+						Name:        "githubApiUrl",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `https://api.github.com`,
+					},
+					{
+						Name: "githubToken",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "githubTokenCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:    "githubVaultSecretName",
+								Type:    "vaultSecret",
+								Default: "github",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{{Name: "access_token"}},
+						Default:   os.Getenv("PIPER_githubToken"),
+					},
+					{
+						Name: "owner",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "github/owner",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{{Name: "githubOrg"}},
+						Default:   os.Getenv("PIPER_owner"),
+					},
+					{
+						Name: "repository",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "github/repository",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{{Name: "githubRepo"}},
+						Default:   os.Getenv("PIPER_repository"),
+					},
+				},
+			},
+			Outputs: config.StepOutputs{
+				Resources: []config.StepResources{
+					{
+						Name: "influx",
+						Type: "influx",
+						Parameters: []map[string]interface{}{
+							{"name": "step_data", "fields": []map[string]string{{"name": "checkmarxone"}}},
+							{"name": "checkmarxone_data", "fields": []map[string]string{{"name": "high_issues"}, {"name": "high_not_false_positive"}, {"name": "high_to_verify"}, {"name": "medium_issues"}, {"name": "medium_not_false_positive"}, {"name": "medium_to_verify"}, {"name": "low_issues"}, {"name": "low_not_false_positive"}, {"name": "low_to_verify"}, {"name": "information_issues"}, {"name": "sca_issues"}, {"name": "kics_issues"}, {"name": "scan_id"}, {"name": "project_id"}, {"name": "projectName"}, {"name": "branch"}, {"name": "scan_type"}, {"name": "deep_link"}}},
+						},
+					},
+					{
+						Name: "reports",
+						Type: "reports",
+						Parameters: []map[string]interface{}{
+							{"filePattern": "**/piper_checkmarxone_report.html", "type": "checkmarxone"},
+							{"filePattern": "**/piper_checkmarxone_report.json", "type": "checkmarxone"},
+							{"filePattern": "**/checkmarxOne/result.sarif", "type": "checkmarxone"},
+							{"filePattern": "**/toolrun_checkmarxone_*.json", "type": "checkmarxone"},
+						},
+					},
+				},
+			},
+		},
+	}
+	return theMetaData
+}
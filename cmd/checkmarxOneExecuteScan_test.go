@@ -0,0 +1,322 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/checkmarxone"
+	"github.com/stretchr/testify/assert"
+)
+
+type checkmarxOneSystemMock struct {
+	projects            []checkmarxone.Project
+	applications        []checkmarxone.Application
+	lastScans           []checkmarxone.Scan
+	scanStatus          []string
+	results             []checkmarxone.ScanResult
+	createdProject      string
+	createdApplication  string
+	projectApplication  string
+	scannedBranch       string
+	scanConfigurations  []checkmarxone.ScanConfiguration
+	uploaded            bool
+	errorOnGetProjects  bool
+	errorOnUploadSource bool
+}
+
+func (sys *checkmarxOneSystemMock) GetApplicationsByName(name string) ([]checkmarxone.Application, error) {
+	return sys.applications, nil
+}
+
+func (sys *checkmarxOneSystemMock) CreateApplication(name string) (checkmarxone.Application, error) {
+	sys.createdApplication = name
+	return checkmarxone.Application{ID: "a-new", Name: name}, nil
+}
+
+func (sys *checkmarxOneSystemMock) GetProjectsByName(name string) ([]checkmarxone.Project, error) {
+	if sys.errorOnGetProjects {
+		return nil, fmt.Errorf("error on GetProjectsByName")
+	}
+	return sys.projects, nil
+}
+
+func (sys *checkmarxOneSystemMock) CreateProject(name string, applicationID string, groups []string) (checkmarxone.Project, error) {
+	sys.createdProject = name
+	sys.projectApplication = applicationID
+	return checkmarxone.Project{ID: "p-new", Name: name, Groups: groups}, nil
+}
+
+func (sys *checkmarxOneSystemMock) UploadProjectSourceCode(zipFile string) (string, error) {
+	if sys.errorOnUploadSource {
+		return "", fmt.Errorf("error on UploadProjectSourceCode")
+	}
+	sys.uploaded = true
+	return "https://upload.server.com/signed", nil
+}
+
+func (sys *checkmarxOneSystemMock) ScanProject(projectID, uploadURL, branch string, configurations []checkmarxone.ScanConfiguration) (checkmarxone.Scan, error) {
+	sys.scannedBranch = branch
+	sys.scanConfigurations = configurations
+	return checkmarxone.Scan{ID: "s-new", ProjectID: projectID, Branch: branch, Status: checkmarxone.ScanStatusQueued}, nil
+}
+
+func (sys *checkmarxOneSystemMock) GetScan(scanID string) (checkmarxone.Scan, error) {
+	status := checkmarxone.ScanStatusCompleted
+	if len(sys.scanStatus) > 0 {
+		status = sys.scanStatus[0]
+		sys.scanStatus = sys.scanStatus[1:]
+	}
+	return checkmarxone.Scan{ID: scanID, Branch: sys.scannedBranch, Status: status, StatusDetails: []checkmarxone.ScanStatusDetail{{Name: "sast", Status: status, Details: "no sources"}}}, nil
+}
+
+func (sys *checkmarxOneSystemMock) GetLastScans(projectID, branch string, limit int) ([]checkmarxone.Scan, error) {
+	return sys.lastScans, nil
+}
+
+func (sys *checkmarxOneSystemMock) GetResults(scanID string) ([]checkmarxone.ScanResult, error) {
+	return sys.results, nil
+}
+
+func (sys *checkmarxOneSystemMock) DeepLink(projectID, scanID, branch string) string {
+	return fmt.Sprintf("https://cx1.server.com/projects/%v/scans?id=%v&branch=%v", projectID, scanID, branch)
+}
+
+var checkmarxOneTestResults = []checkmarxone.ScanResult{
+	{Type: "sast", ID: "r-1", Severity: "HIGH", State: "TO_VERIFY", Data: checkmarxone.ResultData{QueryID: "1", QueryName: "SQL_Injection", LanguageName: "Java"}},
+	{Type: "sast", ID: "r-2", Severity: "HIGH", State: "NOT_EXPLOITABLE", Data: checkmarxone.ResultData{QueryID: "1", QueryName: "SQL_Injection", LanguageName: "Java"}},
+	{Type: "sast", ID: "r-3", Severity: "LOW", State: "CONFIRMED", Data: checkmarxone.ResultData{QueryID: "2", QueryName: "Log_Forging", LanguageName: "Java"}},
+	{Type: "sca", ID: "CVE-2022-1234", Severity: "HIGH", State: "TO_VERIFY", Data: checkmarxone.ResultData{PackageIdentifier: "npm-lodash-4.17.15"}},
+	{Type: "kics", ID: "k-1", Severity: "MEDIUM", State: "TO_VERIFY", Data: checkmarxone.ResultData{QueryID: "k1", QueryName: "Container Running As Root", FileName: "Dockerfile", Line: 1}},
+}
+
+func defaultCheckmarxOneOptions() checkmarxOneExecuteScanOptions {
+	return checkmarxOneExecuteScanOptions{
+		ServerURL:                            "https://cx1.server.com",
+		ProjectName:                          "my-project",
+		ScanTypes:                            []string{"sast"},
+		Incremental:                          true,
+		FilterPattern:                        "**/*.go",
+		VulnerabilityThresholdEnabled:        true,
+		VulnerabilityThresholdUnit:           "absolute",
+		VulnerabilityThresholdHigh:           0,
+		VulnerabilityThresholdMedium:         0,
+		VulnerabilityThresholdLow:            10,
+		VulnerabilityThresholdLowPerQueryMax: 10,
+		VulnerabilityThresholdResult:         "FAILURE",
+		ConvertToSarif:                       true,
+	}
+}
+
+func newCheckmarxOneTestWorkspace(t *testing.T) *checkmarxExecuteScanUtilsMock {
+	workspace := t.TempDir()
+	err := os.WriteFile(filepath.Join(workspace, "main.go"), []byte("package main"), 0o700)
+	assert.NoError(t, err)
+	utils := newCheckmarxExecuteScanUtilsMock()
+	utils.workspace = workspace
+	return utils
+}
+
+func TestRunCheckmarxOneScan(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("existing project compliant", func(t *testing.T) {
+		t.Parallel()
+		sys := &checkmarxOneSystemMock{projects: []checkmarxone.Project{{ID: "p-1", Name: "My-Project", MainBranch: "master"}}, results: checkmarxOneTestResults[2:]}
+		config := defaultCheckmarxOneOptions()
+		utils := newCheckmarxOneTestWorkspace(t)
+		influx := checkmarxOneExecuteScanInflux{}
+
+		err := runCheckmarxOneScan(ctx, config, sys, &influx, utils)
+
+		assert.NoError(t, err)
+		assert.True(t, sys.uploaded)
+		assert.Empty(t, sys.createdProject)
+		assert.Equal(t, "master", sys.scannedBranch)
+		assert.Equal(t, []checkmarxone.ScanConfiguration{{Type: "sast", Value: map[string]string{"incremental": "true"}}}, sys.scanConfigurations)
+		assert.Equal(t, 1, influx.checkmarxone_data.fields.low_issues)
+		assert.Equal(t, 0, influx.checkmarxone_data.fields.sca_issues)
+		assert.Equal(t, "s-new", influx.checkmarxone_data.fields.scan_id)
+		assert.FileExists(t, filepath.Join(utils.workspace, "checkmarxOne", "piper_checkmarxone_report.html"))
+		assert.FileExists(t, filepath.Join(utils.workspace, "checkmarxOne", "result.sarif"))
+		assert.NoFileExists(t, filepath.Join(utils.workspace, "workspace.zip"))
+	})
+
+	t.Run("new project in new application for pull request", func(t *testing.T) {
+		t.Parallel()
+		sys := &checkmarxOneSystemMock{}
+		config := defaultCheckmarxOneOptions()
+		config.ApplicationName = "my-app"
+		config.Groups = []string{"g-1"}
+		config.PullRequestName = "PR-17"
+		config.Branch = "main"
+		config.ScanTypes = []string{"sast", "sca", "kics"}
+		config.Preset = "ASA Premium"
+		config.IsOptimizedAndScheduled = true
+		utils := newCheckmarxOneTestWorkspace(t)
+
+		err := runCheckmarxOneScan(ctx, config, sys, &checkmarxOneExecuteScanInflux{}, utils)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "my-app", sys.createdApplication)
+		assert.Equal(t, "my-project", sys.createdProject)
+		assert.Equal(t, "a-new", sys.projectApplication)
+		assert.Equal(t, "PR-17", sys.scannedBranch)
+		assert.Equal(t, []checkmarxone.ScanConfiguration{
+			{Type: "sast", Value: map[string]string{"incremental": "false", "presetName": "ASA Premium"}},
+			{Type: "sca", Value: map[string]string{}},
+			{Type: "kics", Value: map[string]string{}},
+		}, sys.scanConfigurations)
+	})
+
+	t.Run("thresholds exceeded", func(t *testing.T) {
+		t.Parallel()
+		sys := &checkmarxOneSystemMock{projects: []checkmarxone.Project{{ID: "p-1", Name: "my-project"}}, results: checkmarxOneTestResults}
+		config := defaultCheckmarxOneOptions()
+		config.ScanTypes = []string{"sast", "sca", "kics"}
+		utils := newCheckmarxOneTestWorkspace(t)
+		influx := checkmarxOneExecuteScanInflux{}
+
+		err := runCheckmarxOneScan(ctx, config, sys, &influx, utils)
+
+		assert.EqualError(t, err, "the project is not compliant - see report for details")
+		assert.Equal(t, 2, influx.checkmarxone_data.fields.high_issues)
+		assert.Equal(t, 1, influx.checkmarxone_data.fields.high_not_false_positive)
+		assert.Equal(t, 1, influx.checkmarxone_data.fields.sca_issues)
+		assert.Equal(t, 1, influx.checkmarxone_data.fields.kics_issues)
+	})
+
+	t.Run("thresholds exceeded by SCA results", func(t *testing.T) {
+		t.Parallel()
+		sys := &checkmarxOneSystemMock{projects: []checkmarxone.Project{{ID: "p-1", Name: "my-project"}}, results: checkmarxOneTestResults[2:]}
+		config := defaultCheckmarxOneOptions()
+		config.ScanTypes = []string{"sast", "sca"}
+		utils := newCheckmarxOneTestWorkspace(t)
+
+		err := runCheckmarxOneScan(ctx, config, sys, &checkmarxOneExecuteScanInflux{}, utils)
+
+		assert.EqualError(t, err, "the project is not compliant - see report for details")
+	})
+
+	t.Run("percentage thresholds", func(t *testing.T) {
+		t.Parallel()
+		sys := &checkmarxOneSystemMock{projects: []checkmarxone.Project{{ID: "p-1", Name: "my-project"}}, results: checkmarxOneTestResults}
+		config := defaultCheckmarxOneOptions()
+		config.VulnerabilityThresholdUnit = "percentage"
+		config.VulnerabilityThresholdHigh = 50
+		config.VulnerabilityThresholdMedium = 100
+		utils := newCheckmarxOneTestWorkspace(t)
+
+		err := runCheckmarxOneScan(ctx, config, sys, &checkmarxOneExecuteScanInflux{}, utils)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("verify only", func(t *testing.T) {
+		t.Parallel()
+		sys := &checkmarxOneSystemMock{projects: []checkmarxone.Project{{ID: "p-1", Name: "my-project"}}, lastScans: []checkmarxone.Scan{{ID: "s-old", Branch: "main"}}, results: checkmarxOneTestResults}
+		config := defaultCheckmarxOneOptions()
+		config.VerifyOnly = true
+		config.VulnerabilityThresholdEnabled = false
+		utils := newCheckmarxOneTestWorkspace(t)
+		influx := checkmarxOneExecuteScanInflux{}
+
+		err := runCheckmarxOneScan(ctx, config, sys, &influx, utils)
+
+		assert.NoError(t, err)
+		assert.False(t, sys.uploaded)
+		assert.Equal(t, "s-old", influx.checkmarxone_data.fields.scan_id)
+		assert.Equal(t, 0, influx.checkmarxone_data.fields.sca_issues)
+	})
+
+	t.Run("verify only without scan", func(t *testing.T) {
+		t.Parallel()
+		sys := &checkmarxOneSystemMock{projects: []checkmarxone.Project{{ID: "p-1", Name: "my-project"}}}
+		config := defaultCheckmarxOneOptions()
+		config.VerifyOnly = true
+		config.Branch = "develop"
+
+		err := runCheckmarxOneScan(ctx, config, sys, &checkmarxOneExecuteScanInflux{}, newCheckmarxOneTestWorkspace(t))
+
+		assert.EqualError(t, err, "no completed scan of branch develop of project my-project found, verification only mode aborted")
+	})
+
+	t.Run("scan failed", func(t *testing.T) {
+		t.Parallel()
+		sys := &checkmarxOneSystemMock{projects: []checkmarxone.Project{{ID: "p-1", Name: "my-project"}}, scanStatus: []string{checkmarxone.ScanStatusFailed}}
+		config := defaultCheckmarxOneOptions()
+
+		err := runCheckmarxOneScan(ctx, config, sys, &checkmarxOneExecuteScanInflux{}, newCheckmarxOneTestWorkspace(t))
+
+		assert.EqualError(t, err, "scan and upload returned an error: polling scan status failed: Checkmarx One scan failed with the following error: sast: no sources")
+	})
+
+	t.Run("upload failed", func(t *testing.T) {
+		t.Parallel()
+		sys := &checkmarxOneSystemMock{projects: []checkmarxone.Project{{ID: "p-1", Name: "my-project"}}, errorOnUploadSource: true}
+
+		err := runCheckmarxOneScan(ctx, defaultCheckmarxOneOptions(), sys, &checkmarxOneExecuteScanInflux{}, newCheckmarxOneTestWorkspace(t))
+
+		assert.EqualError(t, err, "scan and upload returned an error: failed to upload source code for project my-project: error on UploadProjectSourceCode")
+	})
+
+	t.Run("unsupported scan type", func(t *testing.T) {
+		t.Parallel()
+		config := defaultCheckmarxOneOptions()
+		config.ScanTypes = []string{"sast", "dast"}
+
+		err := runCheckmarxOneScan(ctx, config, &checkmarxOneSystemMock{}, &checkmarxOneExecuteScanInflux{}, newCheckmarxOneTestWorkspace(t))
+
+		assert.EqualError(t, err, "scan type dast not supported, supported are sast, sca and kics")
+	})
+
+	t.Run("loading projects failed", func(t *testing.T) {
+		t.Parallel()
+		sys := &checkmarxOneSystemMock{errorOnGetProjects: true}
+
+		err := runCheckmarxOneScan(ctx, defaultCheckmarxOneOptions(), sys, &checkmarxOneExecuteScanInflux{}, newCheckmarxOneTestWorkspace(t))
+
+		assert.EqualError(t, err, "error when trying to load project: error on GetProjectsByName")
+	})
+}
+
+func TestPollCheckmarxOneScanStatus(t *testing.T) {
+	checkmarxOnePollInterval = 0
+
+	t.Run("running until completed", func(t *testing.T) {
+		sys := &checkmarxOneSystemMock{scanStatus: []string{checkmarxone.ScanStatusQueued, checkmarxone.ScanStatusRunning, checkmarxone.ScanStatusCompleted}}
+
+		scan, err := pollCheckmarxOneScanStatus(sys, checkmarxone.Scan{ID: "s-1"}, time.Minute)
+
+		assert.NoError(t, err)
+		assert.Equal(t, checkmarxone.ScanStatusCompleted, scan.Status)
+		assert.Empty(t, sys.scanStatus)
+	})
+
+	t.Run("partial", func(t *testing.T) {
+		sys := &checkmarxOneSystemMock{scanStatus: []string{checkmarxone.ScanStatusPartial}}
+
+		_, err := pollCheckmarxOneScanStatus(sys, checkmarxone.Scan{ID: "s-1"}, time.Minute)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		sys := &checkmarxOneSystemMock{scanStatus: []string{checkmarxone.ScanStatusCanceled}}
+
+		_, err := pollCheckmarxOneScanStatus(sys, checkmarxone.Scan{ID: "s-1"}, time.Minute)
+
+		assert.EqualError(t, err, "scan canceled via web interface")
+	})
+	t.Run("timeout", func(t *testing.T) {
+		sys := &checkmarxOneSystemMock{scanStatus: []string{checkmarxone.ScanStatusRunning, checkmarxone.ScanStatusRunning}}
+
+		_, err := pollCheckmarxOneScanStatus(sys, checkmarxone.Scan{ID: "s-1"}, 0)
+
+		assert.EqualError(t, err, "scan did not finish within 0s, last status: Running")
+	})
+}
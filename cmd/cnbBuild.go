@@ -23,6 +23,8 @@ import (
 	"github.com/SAP/jenkins-library/pkg/syft"
 
 	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
 	"github.com/imdario/mergo"
 	"github.com/mitchellh/mapstructure"
 	"github.com/pkg/errors"
@@ -395,7 +397,11 @@ func callCnbBuild(config *cnbBuildOptions, telemetryData *telemetry.CustomData,
 	}
 
 	for _, c := range mergedConfigs {
-		err = runCnbBuild(&c, cnbTelemetry, utils, commonPipelineEnvironment, httpClient)
+		if c.Rebase {
+			err = runCnbRebase(&c, utils, commonPipelineEnvironment, httpClient)
+		} else {
+			err = runCnbBuild(&c, cnbTelemetry, utils, commonPipelineEnvironment, httpClient)
+		}
 		if err != nil {
 			return err
 		}
@@ -494,16 +500,7 @@ func runCnbBuild(config *cnbBuildOptions, cnbTelemetry *cnbBuildTelemetry, utils
 	customTelemetryData.BuildEnv.KeyValues = privacy.FilterEnv(config.BuildEnvVars)
 	cnbTelemetry.Data = append(cnbTelemetry.Data, customTelemetryData)
 
-	if commonPipelineEnvironment.container.imageNameTag == "" {
-		commonPipelineEnvironment.container.registryURL = fmt.Sprintf("%s://%s", targetImage.ContainerRegistry.Scheme, targetImage.ContainerRegistry.Host)
-		commonPipelineEnvironment.container.imageNameTag = fmt.Sprintf("%v:%v", targetImage.ContainerImageName, targetImage.ContainerImageTag)
-	}
-	commonPipelineEnvironment.container.imageNameTags = append(commonPipelineEnvironment.container.imageNameTags, fmt.Sprintf("%v:%v", targetImage.ContainerImageName, targetImage.ContainerImageTag))
-	imageNameAlias := targetImage.ContainerImageName
-	if config.ContainerImageAlias != "" {
-		imageNameAlias = config.ContainerImageAlias
-	}
-	commonPipelineEnvironment.container.imageNames = append(commonPipelineEnvironment.container.imageNames, imageNameAlias)
+	addTargetImageToCPE(config, targetImage, commonPipelineEnvironment)
 
 	if config.BuildEnvVars != nil && len(config.BuildEnvVars) > 0 {
 		log.Entry().Infof("Setting custom environment variables: '%v'", config.BuildEnvVars)
@@ -652,3 +649,70 @@ func runCnbBuild(config *cnbBuildOptions, cnbTelemetry *cnbBuildTelemetry, utils
 
 	return nil
 }
+
+func addTargetImageToCPE(config *cnbBuildOptions, targetImage *cnbutils.TargetImage, commonPipelineEnvironment *cnbBuildCommonPipelineEnvironment) {
+	if commonPipelineEnvironment.container.imageNameTag == "" {
+		commonPipelineEnvironment.container.registryURL = fmt.Sprintf("%s://%s", targetImage.ContainerRegistry.Scheme, targetImage.ContainerRegistry.Host)
+		commonPipelineEnvironment.container.imageNameTag = fmt.Sprintf("%v:%v", targetImage.ContainerImageName, targetImage.ContainerImageTag)
+	}
+	commonPipelineEnvironment.container.imageNameTags = append(commonPipelineEnvironment.container.imageNameTags, fmt.Sprintf("%v:%v", targetImage.ContainerImageName, targetImage.ContainerImageTag))
+	imageNameAlias := targetImage.ContainerImageName
+	if config.ContainerImageAlias != "" {
+		imageNameAlias = config.ContainerImageAlias
+	}
+	commonPipelineEnvironment.container.imageNames = append(commonPipelineEnvironment.container.imageNames, imageNameAlias)
+}
+
+// runCnbRebase updates an image built by a previous cnbBuild to the latest run image without running the lifecycle
+func runCnbRebase(config *cnbBuildOptions, utils cnbutils.BuildUtils, commonPipelineEnvironment *cnbBuildCommonPipelineEnvironment, httpClient piperhttp.Sender) error {
+	projDescPath, err := project.ResolvePath(config.ProjectDescriptor, config.Path, utils)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return errors.Wrap(err, "failed to check if project descriptor exists")
+	}
+
+	var projectID string
+	if projDescPath != "" {
+		descriptor, err := project.ParseDescriptor(projDescPath, utils, httpClient)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return errors.Wrapf(err, "failed to parse %s", projDescPath)
+		}
+		projectID = descriptor.ProjectID
+	}
+
+	targetImage, err := cnbutils.GetTargetImage(config.ContainerRegistryURL, config.ContainerImageName, config.ContainerImageTag, projectID, GeneralConfig.EnvRootPath)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return errors.Wrap(err, "failed to retrieve target image configuration")
+	}
+	addTargetImageToCPE(config, targetImage, commonPipelineEnvironment)
+
+	if config.DockerConfigJSON != "" {
+		os.Setenv("DOCKER_CONFIG", filepath.Dir(config.DockerConfigJSON))
+	}
+
+	containerImage := path.Join(targetImage.ContainerRegistry.Host, targetImage.ContainerImageName)
+	appImage := fmt.Sprintf("%s:%s", containerImage, targetImage.ContainerImageTag)
+	tags := []string{appImage}
+	for _, tag := range config.AdditionalTags {
+		target := fmt.Sprintf("%s:%s", containerImage, tag)
+		if !piperutils.ContainsString(tags, target) {
+			tags = append(tags, target)
+		}
+	}
+
+	log.Entry().Infof("Rebasing image '%s'", appImage)
+	result, err := cnbutils.Rebase(appImage, config.RunImage, tags, remote.WithAuthFromKeychain(authn.DefaultKeychain))
+	if err != nil {
+		log.SetErrorCategory(log.ErrorBuild)
+		return errors.Wrapf(err, "failed to rebase image '%s'", appImage)
+	}
+	if result.Rebased {
+		log.Entry().Infof("Rebased image '%s' on run image '%s'", appImage, result.RunImage)
+	}
+
+	commonPipelineEnvironment.container.imageDigest = result.Digest
+	commonPipelineEnvironment.container.imageDigests = append(commonPipelineEnvironment.container.imageDigests, result.Digest)
+	return nil
+}
@@ -41,6 +41,7 @@ type cnbBuildOptions struct {
 	SyftDownloadURL           string                   `json:"syftDownloadUrl,omitempty"`
 	RunImage                  string                   `json:"runImage,omitempty"`
 	DefaultProcess            string                   `json:"defaultProcess,omitempty"`
+	Rebase                    bool                     `json:"rebase,omitempty"`
 }
 
 type cnbBuildCommonPipelineEnvironment struct {
@@ -238,6 +239,7 @@ func addCnbBuildFlags(cmd *cobra.Command, stepConfig *cnbBuildOptions) {
 	cmd.Flags().StringVar(&stepConfig.SyftDownloadURL, "syftDownloadUrl", `https://github.com/anchore/syft/releases/download/v0.62.3/syft_0.62.3_linux_amd64.tar.gz`, "Specifies the download url of the Syft Linux amd64 tar binary file. This can be found at https://github.com/anchore/syft/releases/.")
 	cmd.Flags().StringVar(&stepConfig.RunImage, "runImage", os.Getenv("PIPER_runImage"), "Base image from which application images are built. Will be defaulted to the image provided by the builder.")
 	cmd.Flags().StringVar(&stepConfig.DefaultProcess, "defaultProcess", os.Getenv("PIPER_defaultProcess"), "Process that should be started by default. See https://buildpacks.io/docs/app-developer-guide/run-an-app/")
+	cmd.Flags().BoolVar(&stepConfig.Rebase, "rebase", false, "Updates existing application images to the latest run image instead of building them.\nThe run image layers of the image `<containerRegistryUrl>/<containerImageName>:<containerImageTag>` are replaced by the layers of the run image configured in `runImage`, or of the run image recorded by the lifecycle when the image was built.\nThe application layers are kept as they are and no buildpack is executed. The rebased image is pushed to `containerImageTag` and all `additionalTags`, an image already based on the latest run image is pushed to them unchanged.\nIn case of `multipleImages`, each image is rebased.\n")
 
 	cmd.MarkFlagRequired("containerImageTag")
 	cmd.MarkFlagRequired("containerRegistryUrl")
@@ -466,6 +468,15 @@ func cnbBuildMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     os.Getenv("PIPER_defaultProcess"),
 					},
+					{
+						Name:        "rebase",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "STEPS", "STAGES", "PARAMETERS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
 				},
 			},
 			Containers: []config.Container{
@@ -3,7 +3,10 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	stdlog "log"
 	"net/http"
+	"net/http/httptest"
 	"path/filepath"
 	"testing"
 
@@ -12,8 +15,14 @@ import (
 	piperhttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/mock"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/buildpacks/lifecycle/platform"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
 	v1 "github.com/google/go-containerregistry/pkg/v1"
 	"github.com/google/go-containerregistry/pkg/v1/fake"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
 	"github.com/jarcoal/httpmock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -684,3 +693,83 @@ uri = "some-buildpack"
 		assert.Equal(t, []string{"simple", "my-image-1"}, commonPipelineEnvironment.container.imageNames)
 	})
 }
+
+func TestRunCnbRebase(t *testing.T) {
+	configOptions.openFile = piperconf.OpenPiperFile
+
+	server := httptest.NewServer(registry.New(registry.Logger(stdlog.New(io.Discard, "", 0))))
+	defer server.Close()
+	host := server.Listener.Addr().String()
+
+	pushImage := func(image v1.Image, reference string) {
+		ref, err := name.ParseReference(reference)
+		require.NoError(t, err)
+		require.NoError(t, remote.Write(ref, image))
+	}
+	runImage := fmt.Sprintf("%s/run:base", host)
+	oldRun, err := random.Image(64, 1)
+	require.NoError(t, err)
+	pushImage(oldRun, runImage)
+	oldRunConfig, _ := oldRun.ConfigFile()
+	appLayer, err := random.Layer(64, "application/vnd.docker.image.rootfs.diff.tar.gzip")
+	require.NoError(t, err)
+	app, err := mutate.AppendLayers(oldRun, appLayer)
+	require.NoError(t, err)
+	metadata, _ := json.Marshal(platform.LayersMetadata{
+		RunImage: platform.RunImageMetadata{TopLayer: oldRunConfig.RootFS.DiffIDs[0].String()},
+		Stack:    platform.StackMetadata{RunImage: platform.StackRunImageMetadata{Image: runImage}},
+	})
+	app, err = mutate.Config(app, v1.Config{Labels: map[string]string{platform.LayerMetadataLabel: string(metadata)}})
+	require.NoError(t, err)
+	pushImage(app, fmt.Sprintf("%s/my-image:0.0.1", host))
+
+	newRun, err := random.Image(64, 1)
+	require.NoError(t, err)
+	pushImage(newRun, runImage)
+
+	t.Run("success case", func(t *testing.T) {
+		commonPipelineEnvironment := cnbBuildCommonPipelineEnvironment{}
+		config := cnbBuildOptions{
+			ContainerImageName:   "my-image",
+			ContainerImageTag:    "0.0.1",
+			ContainerRegistryURL: fmt.Sprintf("http://%s", host),
+			AdditionalTags:       []string{"latest"},
+			Rebase:               true,
+		}
+		utils := newCnbBuildTestsUtils()
+
+		err := callCnbBuild(&config, &telemetry.CustomData{}, &utils, &commonPipelineEnvironment, &piperhttp.Client{})
+
+		require.NoError(t, err)
+		assert.Empty(t, utils.ExecMockRunner.Calls)
+		assert.Equal(t, config.ContainerRegistryURL, commonPipelineEnvironment.container.registryURL)
+		assert.Equal(t, "my-image:0.0.1", commonPipelineEnvironment.container.imageNameTag)
+
+		ref, _ := name.ParseReference(fmt.Sprintf("%s/my-image:latest", host))
+		rebased, err := remote.Image(ref)
+		require.NoError(t, err)
+		digest, _ := rebased.Digest()
+		assert.Equal(t, digest.String(), commonPipelineEnvironment.container.imageDigest)
+		assert.Equal(t, []string{digest.String()}, commonPipelineEnvironment.container.imageDigests)
+		layers, _ := rebased.Layers()
+		newRunLayers, _ := newRun.Layers()
+		newRunLayerDigest, _ := newRunLayers[0].Digest()
+		layerDigest, _ := layers[0].Digest()
+		assert.Equal(t, newRunLayerDigest, layerDigest)
+	})
+
+	t.Run("error case: image does not exist", func(t *testing.T) {
+		commonPipelineEnvironment := cnbBuildCommonPipelineEnvironment{}
+		config := cnbBuildOptions{
+			ContainerImageName:   "not-found",
+			ContainerImageTag:    "0.0.1",
+			ContainerRegistryURL: fmt.Sprintf("http://%s", host),
+			Rebase:               true,
+		}
+		utils := newCnbBuildTestsUtils()
+
+		err := callCnbBuild(&config, &telemetry.CustomData{}, &utils, &commonPipelineEnvironment, &piperhttp.Client{})
+
+		assert.ErrorContains(t, err, fmt.Sprintf("failed to rebase image '%s/not-found:0.0.1'", host))
+	})
+}
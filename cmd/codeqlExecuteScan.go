@@ -159,8 +159,8 @@ func getToken(config *codeqlExecuteScanOptions) (bool, string) {
 	return false, ""
 }
 
-func uploadResults(config *codeqlExecuteScanOptions, repoInfo RepoInfo, token string, utils codeqlExecuteScanUtils) error {
-	cmd := []string{"github", "upload-results", "--sarif=" + filepath.Join(config.ModulePath, "target", "codeqlReport.sarif")}
+func uploadResults(config *codeqlExecuteScanOptions, repoInfo RepoInfo, token string, sarifFile string, utils codeqlExecuteScanUtils) error {
+	cmd := []string{"github", "upload-results", "--sarif=" + sarifFile}
 
 	if config.GithubToken != "" {
 		cmd = append(cmd, "-a="+token)
@@ -193,73 +193,170 @@ func uploadResults(config *codeqlExecuteScanOptions, repoInfo RepoInfo, token st
 	return nil
 }
 
-func runCodeqlExecuteScan(config *codeqlExecuteScanOptions, telemetryData *telemetry.CustomData, utils codeqlExecuteScanUtils) ([]piperutils.Path, error) {
-	codeqlVersion, err := os.ReadFile("/etc/image-version")
-	if err != nil {
-		log.Entry().Infof("CodeQL image version: unknown")
-	} else {
-		log.Entry().Infof("CodeQL image version: %s", string(codeqlVersion))
+// codeqlLanguage describes the database which is created and analyzed for one language
+type codeqlLanguage struct {
+	language     string
+	buildCommand string
+	database     string
+	// category distinguishes the results of several languages analyzed in one run
+	category   string
+	reportName string
+}
+
+func getLanguages(config *codeqlExecuteScanOptions) ([]codeqlLanguage, error) {
+	if len(config.Languages) == 0 {
+		language := getLangFromBuildTool(config.BuildTool)
+
+		if len(language) == 0 && len(config.Language) == 0 {
+			if config.BuildTool == "custom" {
+				return nil, fmt.Errorf("as the buildTool is custom. please specify the language parameter")
+			} else {
+				return nil, fmt.Errorf("the step could not recognize the specified buildTool %s. please specify valid buildtool", config.BuildTool)
+			}
+		}
+		if len(language) == 0 {
+			language = config.Language
+		}
+		return []codeqlLanguage{{language: language, buildCommand: config.BuildCommand, database: config.Database, reportName: "codeqlReport"}}, nil
 	}
 
-	var reports []piperutils.Path
-	cmd := []string{"database", "create", config.Database, "--overwrite", "--source-root", config.ModulePath}
+	languages := []codeqlLanguage{}
+	for _, entry := range config.Languages {
+		language, _ := entry["language"].(string)
+		if len(language) == 0 {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return nil, fmt.Errorf("each entry of the languages parameter needs to specify the language")
+		}
+		buildCommand, _ := entry["buildCommand"].(string)
+		database, _ := entry["database"].(string)
+		if len(database) == 0 {
+			database = filepath.Join(config.Database, language)
+		}
+		languages = append(languages, codeqlLanguage{language: language, buildCommand: buildCommand, database: database, category: language, reportName: "codeqlReport-" + language})
+	}
+	return languages, nil
+}
 
-	language := getLangFromBuildTool(config.BuildTool)
+// downloadQueryPacks downloads all query packs which are not available locally
+func downloadQueryPacks(config *codeqlExecuteScanOptions, utils codeqlExecuteScanUtils) error {
+	remotePacks := []string{}
+	for _, pack := range config.QueryPacks {
+		if exists, _ := utils.FileExists(pack); exists {
+			continue
+		}
+		if exists, _ := utils.DirExists(pack); exists {
+			continue
+		}
+		remotePacks = append(remotePacks, pack)
+	}
+	if len(remotePacks) == 0 {
+		return nil
+	}
 
-	if len(language) == 0 && len(config.Language) == 0 {
-		if config.BuildTool == "custom" {
-			return reports, fmt.Errorf("as the buildTool is custom. please specify the language parameter")
-		} else {
-			return reports, fmt.Errorf("the step could not recognize the specified buildTool %s. please specify valid buildtool", config.BuildTool)
+	cmd := []string{"pack", "download"}
+	if len(config.PackRegistryURL) > 0 {
+		qlconfig := fmt.Sprintf("registries:\n  - packages: '*'\n    url: %v\n", config.PackRegistryURL)
+		if err := utils.FileWrite("qlconfig.yml", []byte(qlconfig), 0o644); err != nil {
+			return errors.Wrap(err, "failed to write CodeQL configuration for the pack registry")
+		}
+		cmd = append(cmd, "--qlconfig-file=qlconfig.yml")
+		if len(config.PackRegistryToken) > 0 {
+			utils.AppendEnv([]string{fmt.Sprintf("CODEQL_REGISTRIES_AUTH=%v=%v", config.PackRegistryURL, config.PackRegistryToken)})
 		}
 	}
-	if len(language) > 0 {
-		cmd = append(cmd, "--language="+language)
-	} else {
-		cmd = append(cmd, "--language="+config.Language)
+	cmd = append(cmd, remotePacks...)
+	if err := execute(utils, cmd, GeneralConfig.Verbose); err != nil {
+		log.Entry().Error("failed running command codeql pack download")
+		return err
 	}
+	return nil
+}
 
+// analyzeLanguage creates the database of the language and analyzes it. The paths of the SARIF and CSV reports are returned.
+func analyzeLanguage(config *codeqlExecuteScanOptions, language codeqlLanguage, utils codeqlExecuteScanUtils) (string, string, error) {
+	cmd := []string{"database", "create", language.database, "--overwrite", "--source-root", config.ModulePath}
+	cmd = append(cmd, "--language="+language.language)
 	cmd = append(cmd, getRamAndThreadsFromConfig(config)...)
 
 	//codeql has an autobuilder which tries to build the project based on specified programming language
-	if len(config.BuildCommand) > 0 {
-		cmd = append(cmd, "--command="+config.BuildCommand)
+	if len(language.buildCommand) > 0 {
+		cmd = append(cmd, "--command="+language.buildCommand)
 	}
 
-	err = execute(utils, cmd, GeneralConfig.Verbose)
+	err := execute(utils, cmd, GeneralConfig.Verbose)
 	if err != nil {
 		log.Entry().Error("failed running command codeql database create")
-		return reports, err
+		return "", "", err
 	}
 
-	err = os.MkdirAll(filepath.Join(config.ModulePath, "target"), os.ModePerm)
+	err = utils.MkdirAll(filepath.Join(config.ModulePath, "target"), os.ModePerm)
 	if err != nil {
-		return reports, fmt.Errorf("failed to create directory: %w", err)
+		return "", "", fmt.Errorf("failed to create directory: %w", err)
 	}
 
+	sarifFile := filepath.Join(config.ModulePath, "target", language.reportName+".sarif")
 	cmd = nil
-	cmd = append(cmd, "database", "analyze", "--format=sarif-latest", fmt.Sprintf("--output=%v", filepath.Join(config.ModulePath, "target", "codeqlReport.sarif")), config.Database)
+	cmd = append(cmd, "database", "analyze", "--format=sarif-latest", fmt.Sprintf("--output=%v", sarifFile), language.database)
+	if len(language.category) > 0 {
+		cmd = append(cmd, "--sarif-category="+language.category)
+	}
 	cmd = append(cmd, getRamAndThreadsFromConfig(config)...)
 	cmd = codeqlQuery(cmd, config.QuerySuite)
+	cmd = append(cmd, config.QueryPacks...)
 	err = execute(utils, cmd, GeneralConfig.Verbose)
 	if err != nil {
 		log.Entry().Error("failed running command codeql database analyze for sarif generation")
-		return reports, err
+		return "", "", err
 	}
 
-	reports = append(reports, piperutils.Path{Target: filepath.Join(config.ModulePath, "target", "codeqlReport.sarif")})
-
+	csvFile := filepath.Join(config.ModulePath, "target", language.reportName+".csv")
 	cmd = nil
-	cmd = append(cmd, "database", "analyze", "--format=csv", fmt.Sprintf("--output=%v", filepath.Join(config.ModulePath, "target", "codeqlReport.csv")), config.Database)
+	cmd = append(cmd, "database", "analyze", "--format=csv", fmt.Sprintf("--output=%v", csvFile), language.database)
 	cmd = append(cmd, getRamAndThreadsFromConfig(config)...)
 	cmd = codeqlQuery(cmd, config.QuerySuite)
+	cmd = append(cmd, config.QueryPacks...)
 	err = execute(utils, cmd, GeneralConfig.Verbose)
 	if err != nil {
 		log.Entry().Error("failed running command codeql database analyze for csv generation")
+		return sarifFile, "", err
+	}
+
+	return sarifFile, csvFile, nil
+}
+
+func runCodeqlExecuteScan(config *codeqlExecuteScanOptions, telemetryData *telemetry.CustomData, utils codeqlExecuteScanUtils) ([]piperutils.Path, error) {
+	codeqlVersion, err := os.ReadFile("/etc/image-version")
+	if err != nil {
+		log.Entry().Infof("CodeQL image version: unknown")
+	} else {
+		log.Entry().Infof("CodeQL image version: %s", string(codeqlVersion))
+	}
+
+	var reports []piperutils.Path
+	languages, err := getLanguages(config)
+	if err != nil {
+		return reports, err
+	}
+
+	if err := downloadQueryPacks(config, utils); err != nil {
 		return reports, err
 	}
 
-	reports = append(reports, piperutils.Path{Target: filepath.Join(config.ModulePath, "target", "codeqlReport.csv")})
+	sarifFiles := []string{}
+	for _, language := range languages {
+		log.Entry().Infof("Analyzing language %v", language.language)
+		sarifFile, csvFile, err := analyzeLanguage(config, language, utils)
+		if len(sarifFile) > 0 {
+			sarifFiles = append(sarifFiles, sarifFile)
+			reports = append(reports, piperutils.Path{Target: sarifFile})
+		}
+		if len(csvFile) > 0 {
+			reports = append(reports, piperutils.Path{Target: csvFile})
+		}
+		if err != nil {
+			return reports, err
+		}
+	}
 
 	repoInfo := initGitInfo(config)
 	repoUrl := fmt.Sprintf("%s/%s/%s", repoInfo.serverUrl, repoInfo.owner, repoInfo.repo)
@@ -274,10 +371,12 @@ func runCodeqlExecuteScan(config *codeqlExecuteScanOptions, telemetryData *telem
 			return reports, errors.New("failed running upload-results as githubToken was not specified")
 		}
 
-		err = uploadResults(config, repoInfo, token, utils)
-		if err != nil {
+		for _, sarifFile := range sarifFiles {
+			err = uploadResults(config, repoInfo, token, sarifFile, utils)
+			if err != nil {
 
-			return reports, err
+				return reports, err
+			}
 		}
 
 		codeqlScanAuditInstance := codeql.NewCodeqlScanAuditInstance(config.GithubAPIURL, repoInfo.owner, repoInfo.repo, token, []string{})
@@ -286,8 +385,19 @@ func runCodeqlExecuteScan(config *codeqlExecuteScanOptions, telemetryData *telem
 			return reports, errors.Wrap(err, "failed to get scan results")
 		}
 
+		// with a baseline the compliance is checked based on the new alerts only
+		baselineCompared := false
+		if config.CompareWithBaseline {
+			var paths []piperutils.Path
+			paths, baselineCompared, err = compareWithBaseline(config, repoInfo, sarifFiles, utils)
+			reports = append(reports, paths...)
+			if err != nil {
+				return reports, err
+			}
+		}
+
 		unaudited := (scanResults.Total - scanResults.Audited)
-		if unaudited > config.VulnerabilityThresholdTotal {
+		if unaudited > config.VulnerabilityThresholdTotal && !baselineCompared {
 			msg := fmt.Sprintf("Your repository %v with ref %v is not compliant. Total unaudited issues are %v which is greater than the VulnerabilityThresholdTotal count %v", repoUrl, repoInfo.ref, unaudited, config.VulnerabilityThresholdTotal)
 			if config.CheckForCompliance {
 
@@ -306,6 +416,14 @@ func runCodeqlExecuteScan(config *codeqlExecuteScanOptions, telemetryData *telem
 		reports = append(reports, paths...)
 	}
 
+	if config.CompareWithBaseline && !config.UploadResults {
+		paths, _, err := compareWithBaseline(config, repoInfo, sarifFiles, utils)
+		reports = append(reports, paths...)
+		if err != nil {
+			return reports, err
+		}
+	}
+
 	toolRecordFileName, err := createAndPersistToolRecord(utils, repoInfo, repoReference, repoUrl, repoCodeqlScanUrl)
 	if err != nil {
 		log.Entry().Warning("TR_CODEQL: Failed to create toolrecord file ...", err)
@@ -316,6 +434,97 @@ func runCodeqlExecuteScan(config *codeqlExecuteScanOptions, telemetryData *telem
 	return reports, nil
 }
 
+// compareWithBaseline checks the compliance based on the alerts which are not contained in the baseline.
+// It returns false in case no baseline is available for the current run and thus the compliance has not been checked.
+func compareWithBaseline(config *codeqlExecuteScanOptions, repoInfo RepoInfo, sarifFiles []string, utils codeqlExecuteScanUtils) ([]piperutils.Path, bool, error) {
+	current := []codeql.SarifAlert{}
+	for _, sarifFile := range sarifFiles {
+		alerts, err := readSarifAlerts(sarifFile, utils)
+		if err != nil {
+			return nil, false, err
+		}
+		current = append(current, alerts...)
+	}
+
+	baselineRef, baselineReports, err := loadBaseline(config, repoInfo, utils)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "failed to load baseline")
+	}
+	if baselineReports == nil {
+		return nil, false, nil
+	}
+	if len(baselineReports) == 0 {
+		log.Entry().Warnf("No CodeQL analysis found for %v, all alerts are considered new", baselineRef)
+	}
+	baseline := []codeql.SarifAlert{}
+	for _, report := range baselineReports {
+		alerts, err := codeql.ReadSarifAlerts(report)
+		if err != nil {
+			return nil, false, err
+		}
+		baseline = append(baseline, alerts...)
+	}
+
+	comparison := codeql.CompareWithBaseline(current, baseline, baselineRef)
+	log.Entry().Infof("Compared to %v there are %v new alerts, %v existing alerts and %v fixed alerts", baselineRef, len(comparison.NewAlerts), comparison.ExistingAlerts, comparison.FixedAlerts)
+	for _, alert := range comparison.NewAlerts {
+		log.Entry().Infof("New alert %v at %v: %v", alert.RuleID, alert.Location, alert.Message)
+	}
+	paths, err := codeql.WriteBaselineReport(comparison, config.ModulePath, utils)
+	if err != nil {
+		return nil, true, err
+	}
+
+	if len(comparison.NewAlerts) > config.VulnerabilityThresholdTotal {
+		msg := fmt.Sprintf("Your changes are not compliant. There are %v new alerts compared to %v which is greater than the VulnerabilityThresholdTotal count %v", len(comparison.NewAlerts), baselineRef, config.VulnerabilityThresholdTotal)
+		if config.CheckForCompliance {
+			log.SetErrorCategory(log.ErrorCompliance)
+			return paths, true, errors.New(msg)
+		}
+		log.Entry().Warning(msg)
+	}
+	return paths, true, nil
+}
+
+func readSarifAlerts(sarifFile string, utils codeqlExecuteScanUtils) ([]codeql.SarifAlert, error) {
+	content, err := utils.FileRead(sarifFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read %v", sarifFile)
+	}
+	return codeql.ReadSarifAlerts(content)
+}
+
+// loadBaseline returns the SARIF reports of the baseline, either from the configured file or from GitHub code scanning.
+// No reports are returned if no baseline is available for the current run, an empty list if the baseline ref has not been analyzed yet.
+func loadBaseline(config *codeqlExecuteScanOptions, repoInfo RepoInfo, utils codeqlExecuteScanUtils) (string, [][]byte, error) {
+	if len(config.BaselineSarifFile) > 0 {
+		content, err := utils.FileRead(config.BaselineSarifFile)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return "", nil, errors.Wrapf(err, "failed to read %v", config.BaselineSarifFile)
+		}
+		return config.BaselineSarifFile, [][]byte{content}, nil
+	}
+
+	baselineRef := config.BaselineRef
+	if len(baselineRef) == 0 {
+		provider, err := orchestrator.NewOrchestratorSpecificConfigProvider()
+		if err != nil || !provider.IsPullRequest() {
+			log.Entry().Warn("No baseline available as the run is not a pull request and baselineRef is not set. Skipping the comparison.")
+			return "", nil, nil
+		}
+		baselineRef = "refs/heads/" + strings.TrimPrefix(provider.GetPullRequestConfig().Base, "refs/heads/")
+	}
+
+	hasToken, token := getToken(config)
+	if !hasToken {
+		return "", nil, errors.New("the baseline cannot be downloaded as githubToken was not specified")
+	}
+	codeqlScanAuditInstance := codeql.NewCodeqlScanAuditInstance(config.GithubAPIURL, repoInfo.owner, repoInfo.repo, token, []string{})
+	baselineReports, err := codeqlScanAuditInstance.GetSarifReports(baselineRef)
+	return baselineRef, baselineReports, err
+}
+
 func createAndPersistToolRecord(utils codeqlExecuteScanUtils, repoInfo RepoInfo, repoReference string, repoUrl string, repoCodeqlScanUrl string) (string, error) {
 	toolRecord, err := createToolRecordCodeql(utils, repoInfo, repoReference, repoUrl, repoCodeqlScanUrl)
 	if err != nil {
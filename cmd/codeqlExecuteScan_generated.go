@@ -20,22 +20,29 @@ import (
 )
 
 type codeqlExecuteScanOptions struct {
-	GithubToken                 string `json:"githubToken,omitempty"`
-	GithubAPIURL                string `json:"githubApiUrl,omitempty"`
-	BuildTool                   string `json:"buildTool,omitempty" validate:"possible-values=custom maven golang npm pip yarn"`
-	BuildCommand                string `json:"buildCommand,omitempty"`
-	Language                    string `json:"language,omitempty"`
-	ModulePath                  string `json:"modulePath,omitempty"`
-	Database                    string `json:"database,omitempty"`
-	QuerySuite                  string `json:"querySuite,omitempty"`
-	UploadResults               bool   `json:"uploadResults,omitempty"`
-	Threads                     string `json:"threads,omitempty"`
-	Ram                         string `json:"ram,omitempty"`
-	AnalyzedRef                 string `json:"analyzedRef,omitempty"`
-	Repository                  string `json:"repository,omitempty"`
-	CommitID                    string `json:"commitId,omitempty"`
-	VulnerabilityThresholdTotal int    `json:"vulnerabilityThresholdTotal,omitempty"`
-	CheckForCompliance          bool   `json:"checkForCompliance,omitempty"`
+	GithubToken                 string                   `json:"githubToken,omitempty"`
+	GithubAPIURL                string                   `json:"githubApiUrl,omitempty"`
+	BuildTool                   string                   `json:"buildTool,omitempty" validate:"possible-values=custom maven golang npm pip yarn"`
+	BuildCommand                string                   `json:"buildCommand,omitempty"`
+	Language                    string                   `json:"language,omitempty"`
+	Languages                   []map[string]interface{} `json:"languages,omitempty"`
+	ModulePath                  string                   `json:"modulePath,omitempty"`
+	Database                    string                   `json:"database,omitempty"`
+	QuerySuite                  string                   `json:"querySuite,omitempty"`
+	QueryPacks                  []string                 `json:"queryPacks,omitempty"`
+	PackRegistryURL             string                   `json:"packRegistryUrl,omitempty"`
+	PackRegistryToken           string                   `json:"packRegistryToken,omitempty"`
+	UploadResults               bool                     `json:"uploadResults,omitempty"`
+	Threads                     string                   `json:"threads,omitempty"`
+	Ram                         string                   `json:"ram,omitempty"`
+	AnalyzedRef                 string                   `json:"analyzedRef,omitempty"`
+	Repository                  string                   `json:"repository,omitempty"`
+	CommitID                    string                   `json:"commitId,omitempty"`
+	VulnerabilityThresholdTotal int                      `json:"vulnerabilityThresholdTotal,omitempty"`
+	CheckForCompliance          bool                     `json:"checkForCompliance,omitempty"`
+	CompareWithBaseline         bool                     `json:"compareWithBaseline,omitempty"`
+	BaselineRef                 string                   `json:"baselineRef,omitempty"`
+	BaselineSarifFile           string                   `json:"baselineSarifFile,omitempty"`
 }
 
 type codeqlExecuteScanReports struct {
@@ -51,6 +58,7 @@ func (p *codeqlExecuteScanReports) persist(stepConfig codeqlExecuteScanOptions,
 		{FilePattern: "**/*.csv", ParamRef: "", StepResultType: "codeql"},
 		{FilePattern: "**/*.sarif", ParamRef: "", StepResultType: "codeql"},
 		{FilePattern: "**/toolrun_codeql_*.json", ParamRef: "", StepResultType: "codeql"},
+		{FilePattern: "**/piper_codeql_baseline_report.json", ParamRef: "", StepResultType: "codeql"},
 	}
 	envVars := []gcs.EnvVar{
 		{Name: "GOOGLE_APPLICATION_CREDENTIALS", Value: gcpJsonKeyFilePath, Modified: false},
@@ -112,6 +120,7 @@ and Java plus Maven.`,
 				return err
 			}
 			log.RegisterSecret(stepConfig.GithubToken)
+			log.RegisterSecret(stepConfig.PackRegistryToken)
 
 			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
 				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
@@ -180,9 +189,13 @@ func addCodeqlExecuteScanFlags(cmd *cobra.Command, stepConfig *codeqlExecuteScan
 	cmd.Flags().StringVar(&stepConfig.BuildTool, "buildTool", `maven`, "Defines the build tool which is used for building the project.")
 	cmd.Flags().StringVar(&stepConfig.BuildCommand, "buildCommand", os.Getenv("PIPER_buildCommand"), "Command to build the project")
 	cmd.Flags().StringVar(&stepConfig.Language, "language", os.Getenv("PIPER_language"), "The programming language used to analyze.")
+
 	cmd.Flags().StringVar(&stepConfig.ModulePath, "modulePath", `./`, "Allows providing the path for the module to scan")
 	cmd.Flags().StringVar(&stepConfig.Database, "database", `codeqlDB`, "Path to the CodeQL database to create. This directory will be created, and must not already exist.")
 	cmd.Flags().StringVar(&stepConfig.QuerySuite, "querySuite", os.Getenv("PIPER_querySuite"), "The name of a CodeQL query suite. If omitted, the default query suite for the language of the database being analyzed will be used.")
+	cmd.Flags().StringSliceVar(&stepConfig.QueryPacks, "queryPacks", []string{}, "CodeQL query packs which are analyzed in addition to the `querySuite`. An entry is either the path to a local query pack\nor a pack reference like `my-org/security-queries@1.2.0` which is downloaded from the configured `packRegistryUrl`\n(by default from the GitHub container registry) before the analysis.\nIf `querySuite` is not set, only the query packs are analyzed.\n")
+	cmd.Flags().StringVar(&stepConfig.PackRegistryURL, "packRegistryUrl", os.Getenv("PIPER_packRegistryUrl"), "URL of the OCI registry from which the query packs are downloaded, e.g. `https://containers.github.example.com/v2/`.")
+	cmd.Flags().StringVar(&stepConfig.PackRegistryToken, "packRegistryToken", os.Getenv("PIPER_packRegistryToken"), "Token to authenticate to the registry of the query packs.")
 	cmd.Flags().BoolVar(&stepConfig.UploadResults, "uploadResults", false, "Allows you to upload codeql SARIF results to your github project. You will need to set githubToken for this.")
 	cmd.Flags().StringVar(&stepConfig.Threads, "threads", `0`, "Use this many threads for the codeql operations.")
 	cmd.Flags().StringVar(&stepConfig.Ram, "ram", os.Getenv("PIPER_ram"), "Use this much ram (MB) for the codeql operations.")
@@ -191,6 +204,9 @@ func addCodeqlExecuteScanFlags(cmd *cobra.Command, stepConfig *codeqlExecuteScan
 	cmd.Flags().StringVar(&stepConfig.CommitID, "commitId", os.Getenv("PIPER_commitId"), "SHA of commit that was analyzed.")
 	cmd.Flags().IntVar(&stepConfig.VulnerabilityThresholdTotal, "vulnerabilityThresholdTotal", 0, "Threashold for maximum number of allowed vulnerabilities.")
 	cmd.Flags().BoolVar(&stepConfig.CheckForCompliance, "checkForCompliance", false, "If set to true, the piper step checks for compliance based on vulnerability threadholds. Example - If total vulnerabilites are 10 and vulnerabilityThresholdTotal is set as 0, then the steps throws an compliance error.")
+	cmd.Flags().BoolVar(&stepConfig.CompareWithBaseline, "compareWithBaseline", false, "If set to true, the SARIF results are compared against the results of the baseline (e.g. the target branch of the pull request). Only new alerts are then considered for `vulnerabilityThresholdTotal`. Without a baseline, e.g. for runs which are no pull request and have no `baselineRef`, all unaudited alerts are considered. In case the baseline ref has not been analyzed yet, all alerts are considered new.")
+	cmd.Flags().StringVar(&stepConfig.BaselineRef, "baselineRef", os.Getenv("PIPER_baselineRef"), "For `compareWithBaseline`: The ref whose SARIF results uploaded to GitHub code scanning are used as baseline. Defaults to the target branch of the pull request.")
+	cmd.Flags().StringVar(&stepConfig.BaselineSarifFile, "baselineSarifFile", os.Getenv("PIPER_baselineSarifFile"), "For `compareWithBaseline`: Path to a stored SARIF file of the baseline. If set, the baseline is not downloaded from GitHub.")
 
 	cmd.MarkFlagRequired("buildTool")
 }
@@ -207,6 +223,7 @@ func codeqlExecuteScanMetadata() config.StepData {
 			Inputs: config.StepInputs{
 				Secrets: []config.StepSecrets{
 					{Name: "githubTokenCredentialsId", Description: "Jenkins 'Secret text' credentials ID containing token to authenticate to GitHub.", Type: "jenkins"},
+					{Name: "packRegistryTokenCredentialsId", Description: "Jenkins 'Secret text' credentials ID containing the token to authenticate to the registry of the query packs.", Type: "jenkins"},
 				},
 				Resources: []config.StepResources{
 					{Name: "commonPipelineEnvironment"},
@@ -270,6 +287,14 @@ func codeqlExecuteScanMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     os.Getenv("PIPER_language"),
 					},
+					{
+						Name:        "languages",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]map[string]interface{}",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+					},
 					{
 						Name:        "modulePath",
 						ResourceRef: []config.ResourceReference{},
@@ -297,6 +322,38 @@ func codeqlExecuteScanMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     os.Getenv("PIPER_querySuite"),
 					},
+					{
+						Name:        "queryPacks",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{},
+					},
+					{
+						Name:        "packRegistryUrl",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_packRegistryUrl"),
+					},
+					{
+						Name: "packRegistryToken",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "packRegistryTokenCredentialsId",
+								Type: "secret",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_packRegistryToken"),
+					},
 					{
 						Name:        "uploadResults",
 						ResourceRef: []config.ResourceReference{},
@@ -384,6 +441,33 @@ func codeqlExecuteScanMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     false,
 					},
+					{
+						Name:        "compareWithBaseline",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name:        "baselineRef",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_baselineRef"),
+					},
+					{
+						Name:        "baselineSarifFile",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_baselineSarifFile"),
+					},
 				},
 			},
 			Containers: []config.Container{
@@ -398,6 +482,7 @@ func codeqlExecuteScanMetadata() config.StepData {
 							{"filePattern": "**/*.csv", "type": "codeql"},
 							{"filePattern": "**/*.sarif", "type": "codeql"},
 							{"filePattern": "**/toolrun_codeql_*.json", "type": "codeql"},
+							{"filePattern": "**/piper_codeql_baseline_report.json", "type": "codeql"},
 						},
 					},
 				},
@@ -2,10 +2,13 @@ package cmd
 
 import (
 	"fmt"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/SAP/jenkins-library/pkg/mock"
 	"github.com/SAP/jenkins-library/pkg/orchestrator"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -71,6 +74,90 @@ func TestRunCodeqlExecuteScan(t *testing.T) {
 		_, err := runCodeqlExecuteScan(&config, nil, newCodeqlExecuteScanTestsUtils())
 		assert.NoError(t, err)
 	})
+
+	t.Run("Multiple languages", func(t *testing.T) {
+		config := codeqlExecuteScanOptions{BuildTool: "maven", ModulePath: "./", Database: "codeqlDB", Languages: []map[string]interface{}{
+			{"language": "java", "buildCommand": "mvn package"},
+			{"language": "python", "database": "pyDB"},
+		}}
+		utils := newCodeqlExecuteScanTestsUtils()
+		reports, err := runCodeqlExecuteScan(&config, nil, utils)
+		assert.NoError(t, err)
+		if assert.Equal(t, 6, len(utils.Calls)) {
+			assert.Equal(t, []string{"database", "create", filepath.Join("codeqlDB", "java"), "--overwrite", "--source-root", "./", "--language=java", "--command=mvn package"}, utils.Calls[0].Params)
+			assert.Equal(t, []string{"database", "analyze", "--format=sarif-latest", "--output=" + filepath.Join("target", "codeqlReport-java.sarif"), filepath.Join("codeqlDB", "java"), "--sarif-category=java"}, utils.Calls[1].Params)
+			assert.Equal(t, []string{"database", "create", "pyDB", "--overwrite", "--source-root", "./", "--language=python"}, utils.Calls[3].Params)
+		}
+		assert.Contains(t, reports, piperutils.Path{Target: filepath.Join("target", "codeqlReport-python.csv")})
+	})
+
+	t.Run("Language missing in languages", func(t *testing.T) {
+		config := codeqlExecuteScanOptions{BuildTool: "maven", ModulePath: "./", Languages: []map[string]interface{}{{"buildCommand": "mvn package"}}}
+		_, err := runCodeqlExecuteScan(&config, nil, newCodeqlExecuteScanTestsUtils())
+		assert.EqualError(t, err, "each entry of the languages parameter needs to specify the language")
+	})
+
+	t.Run("Query packs", func(t *testing.T) {
+		config := codeqlExecuteScanOptions{BuildTool: "maven", ModulePath: "./", Database: "codeqlDB", QuerySuite: "java-security-extended.qls",
+			QueryPacks: []string{"queries/local-pack", "my-org/security-queries@1.2.0"}, PackRegistryURL: "https://containers.github.example.com/v2/", PackRegistryToken: "secret"}
+		utils := newCodeqlExecuteScanTestsUtils()
+		utils.AddDir("queries/local-pack")
+		_, err := runCodeqlExecuteScan(&config, nil, utils)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"pack", "download", "--qlconfig-file=qlconfig.yml", "my-org/security-queries@1.2.0"}, utils.Calls[0].Params)
+		assert.Contains(t, utils.Env, "CODEQL_REGISTRIES_AUTH=https://containers.github.example.com/v2/=secret")
+		qlconfig, err := utils.FileRead("qlconfig.yml")
+		assert.NoError(t, err)
+		assert.Contains(t, string(qlconfig), "url: https://containers.github.example.com/v2/")
+		assert.Equal(t, []string{"database", "analyze", "--format=sarif-latest", "--output=" + filepath.Join("target", "codeqlReport.sarif"), "codeqlDB",
+			"java-security-extended.qls", "queries/local-pack", "my-org/security-queries@1.2.0"}, utils.Calls[2].Params)
+	})
+
+	t.Run("Baseline comparison", func(t *testing.T) {
+		sarif := func(fingerprints ...string) []byte {
+			results := []string{}
+			for _, fingerprint := range fingerprints {
+				results = append(results, fmt.Sprintf(`{"ruleId": "java/xss", "partialFingerprints": {"primaryLocationLineHash": "%v"}}`, fingerprint))
+			}
+			return []byte(fmt.Sprintf(`{"runs": [{"results": [%v]}]}`, strings.Join(results, ",")))
+		}
+		config := codeqlExecuteScanOptions{BuildTool: "maven", ModulePath: "./", CompareWithBaseline: true, BaselineSarifFile: "baseline.sarif", CheckForCompliance: true}
+
+		t.Run("only existing alerts", func(t *testing.T) {
+			utils := newCodeqlExecuteScanTestsUtils()
+			utils.AddFile("baseline.sarif", sarif("a", "b"))
+			utils.AddFile(filepath.Join("target", "codeqlReport.sarif"), sarif("a"))
+			reports, err := runCodeqlExecuteScan(&config, nil, utils)
+			assert.NoError(t, err)
+			assert.Contains(t, reports, piperutils.Path{Name: "Codeql Baseline Comparison", Target: filepath.Join("codeql", "piper_codeql_baseline_report.json")})
+		})
+
+		t.Run("new alerts", func(t *testing.T) {
+			utils := newCodeqlExecuteScanTestsUtils()
+			utils.AddFile("baseline.sarif", sarif("a"))
+			utils.AddFile(filepath.Join("target", "codeqlReport.sarif"), sarif("a", "c"))
+			_, err := runCodeqlExecuteScan(&config, nil, utils)
+			assert.EqualError(t, err, "Your changes are not compliant. There are 1 new alerts compared to baseline.sarif which is greater than the VulnerabilityThresholdTotal count 0")
+		})
+
+		t.Run("no baseline for run", func(t *testing.T) {
+			utils := newCodeqlExecuteScanTestsUtils()
+			utils.AddFile(filepath.Join("target", "codeqlReport.sarif"), sarif("a"))
+			noBaselineConfig := codeqlExecuteScanOptions{CompareWithBaseline: true, CheckForCompliance: true}
+			paths, compared, err := compareWithBaseline(&noBaselineConfig, RepoInfo{}, []string{filepath.Join("target", "codeqlReport.sarif")}, utils)
+			assert.NoError(t, err)
+			// the total threshold is checked instead
+			assert.False(t, compared)
+			assert.Empty(t, paths)
+		})
+
+		t.Run("missing baseline file", func(t *testing.T) {
+			utils := newCodeqlExecuteScanTestsUtils()
+			utils.AddFile(filepath.Join("target", "codeqlReport.sarif"), sarif("a"))
+			_, err := runCodeqlExecuteScan(&config, nil, utils)
+			assert.Contains(t, fmt.Sprint(err), "failed to load baseline: failed to read baseline.sarif")
+		})
+	})
 }
 
 func TestGetGitRepoInfo(t *testing.T) {
@@ -21,6 +21,7 @@ import (
 	"github.com/SAP/jenkins-library/pkg/orchestrator"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
 	"github.com/SAP/jenkins-library/pkg/reporting"
+	"github.com/SAP/jenkins-library/pkg/scancache"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 	"github.com/SAP/jenkins-library/pkg/toolrecord"
 	"github.com/SAP/jenkins-library/pkg/versioning"
@@ -146,10 +147,58 @@ func detectExecuteScan(config detectExecuteScanOptions, _ *telemetry.CustomData,
 }
 
 func runDetect(ctx context.Context, config detectExecuteScanOptions, utils detectUtils, influx *detectExecuteScanInflux) error {
+	cache, closeCache := newScanCache(config.ScanCache, config.ScanCacheLocation, config.ScanCacheMaxAge, utils)
+	defer closeCache()
+	if cache != nil && utils.GetProvider().IsPullRequest() {
+		// pull request scans comment their result on the pull request
+		cache = nil
+	}
+	cacheKey := ""
+	if cache != nil {
+		var err error
+		if cacheKey, err = detectScanCacheKey(config, utils); err != nil {
+			log.Entry().WithError(err).Warn("scan cache is disabled")
+			cache = nil
+		}
+	}
+	if entry := cache.Lookup(cacheKey); entry != nil {
+		if restored, err := restoreScanResult(entry, utils); restored {
+			restoreDetectInfluxData(entry, influx)
+			return err
+		}
+	}
+
+	reports, cacheable, err := executeDetectScan(ctx, config, utils, influx)
+	if reports != nil {
+		// the entry of the scan cache contains the list of reports, hence it needs to be written first
+		if persistErr := piperutils.PersistReportsAndLinks("detectExecuteScan", "", utils, reports, nil); persistErr != nil {
+			log.Entry().WithError(persistErr).Warn("failed to persist reports")
+			cacheable = false
+		}
+	}
+	if cacheable {
+		entry := scancache.NewEntry("detectExecuteScan", cacheKey)
+		if outputErr := entry.SetOutput("influx", getDetectInfluxData(influx)); outputErr == nil {
+			saveScanResult(cache, entry, err, utils,
+				filepath.Join(bd.ReportsDirectory, "*"),
+				"piper_detect_policy_violation_report.html",
+				"blackduck-ip.json",
+				"*BlackDuck_RiskReport.pdf",
+				"toolrun_detectExecute_*.json",
+				filepath.Join(reporting.StepReportDirectory, "detectExecuteScan_*.json"),
+				"detectExecuteScan_reports.json",
+				"detectExecuteScan_links.json")
+		}
+	}
+	return err
+}
+
+// executeDetectScan runs the scan and returns the paths of the reports as well as whether the result is complete, i.e. it can be cached
+func executeDetectScan(ctx context.Context, config detectExecuteScanOptions, utils detectUtils, influx *detectExecuteScanInflux) ([]piperutils.Path, bool, error) {
 	// detect execution details, see https://synopsys.atlassian.net/wiki/spaces/INTDOCS/pages/88440888/Sample+Synopsys+Detect+Scan+Configuration+Scenarios+for+Black+Duck
 	err := getDetectScript(config, utils)
 	if err != nil {
-		return fmt.Errorf("failed to download 'detect.sh' script: %w", err)
+		return nil, false, fmt.Errorf("failed to download 'detect.sh' script: %w", err)
 	}
 	defer func() {
 		err := utils.FileRemove("detect.sh")
@@ -159,7 +208,7 @@ func runDetect(ctx context.Context, config detectExecuteScanOptions, utils detec
 	}()
 	err = utils.Chmod("detect.sh", 0o700)
 	if err != nil {
-		return err
+		return nil, false, err
 	}
 
 	if config.InstallArtifacts {
@@ -169,7 +218,7 @@ func runDetect(ctx context.Context, config detectExecuteScanOptions, utils detec
 			GlobalSettingsFile:  config.GlobalSettingsFile,
 		}, utils)
 		if err != nil {
-			return err
+			return nil, false, err
 		}
 	}
 
@@ -178,7 +227,7 @@ func runDetect(ctx context.Context, config detectExecuteScanOptions, utils detec
 	args := []string{"./detect.sh"}
 	args, err = addDetectArgs(args, config, utils, blackduckSystem)
 	if err != nil {
-		return err
+		return nil, false, err
 	}
 	script := strings.Join(args, " ")
 
@@ -189,7 +238,9 @@ func runDetect(ctx context.Context, config detectExecuteScanOptions, utils detec
 	utils.SetEnv(envs)
 
 	err = utils.RunShell("/bin/bash", script)
-	reportingErr := postScanChecksAndReporting(ctx, config, influx, utils, blackduckSystem)
+	reports, reportingErr := postScanChecksAndReporting(ctx, config, influx, utils, blackduckSystem)
+	// results with policy violations are complete, while failures of the scan or the reporting are not worth caching
+	cacheable := reportingErr == nil || isDetectViolation(reportingErr)
 	if reportingErr != nil {
 		if strings.Contains(reportingErr.Error(), "License Policy Violations found") {
 			log.Entry().Errorf("License Policy Violations found")
@@ -204,6 +255,7 @@ func runDetect(ctx context.Context, config detectExecuteScanOptions, utils detec
 	if err != nil {
 		// Setting error category based on exit code
 		mapErrorCategory(utils.GetExitCode())
+		cacheable = cacheable && log.GetErrorCategory() == log.ErrorCompliance
 		if log.GetErrorCategory() == log.ErrorCompliance && !config.FailOnSevereVulnerabilities {
 			err = nil
 			log.Entry().Infof("policy violation(s) found - step will only create data but not fail due to setting failOnSevereVulnerabilities: false")
@@ -218,7 +270,102 @@ func runDetect(ctx context.Context, config detectExecuteScanOptions, utils detec
 		// do not fail until the framework is well established
 		log.Entry().Warning("TR_DETECT: Failed to create toolrecord file "+toolRecordFileName, err)
 	}
-	return err
+	return reports, cacheable, err
+}
+
+// detectScanCacheKey identifies a scan by the bill of materials of the project and the configuration which influences the result
+func detectScanCacheKey(config detectExecuteScanOptions, utils detectUtils) (string, error) {
+	dir := "."
+	if len(config.DependencyPath) > 0 {
+		dir = config.DependencyPath
+	}
+	if err := scancache.CheckPinnedDependencies(utils, dir); err != nil {
+		return "", err
+	}
+	contentHash, err := scancache.HashDependencyDescriptors(utils, dir)
+	if err != nil {
+		return "", err
+	}
+	if detectSignatureScanEnabled(config) {
+		// the signature scanner fingerprints the source files, not only the declared dependencies
+		scanPaths := []string{dir}
+		if len(config.ScanPaths) > 0 && len(config.ScanPaths[0]) > 0 {
+			scanPaths = config.ScanPaths
+		}
+		for _, scanPath := range scanPaths {
+			sourceHash, err := scancache.HashSourceTree(utils, scanPath)
+			if err != nil {
+				return "", err
+			}
+			contentHash += sourceHash
+		}
+	}
+	return scancache.Key("detectExecuteScan", contentHash, struct {
+		ServerURL                   string   `json:"serverUrl"`
+		ProjectName                 string   `json:"projectName"`
+		Version                     string   `json:"version"`
+		Scanners                    []string `json:"scanners"`
+		ScanPaths                   []string `json:"scanPaths"`
+		ScanProperties              []string `json:"scanProperties"`
+		FailOn                      []string `json:"failOn"`
+		FailOnSevereVulnerabilities bool     `json:"failOnSevereVulnerabilities"`
+		IncludedPackageManagers     []string `json:"includedPackageManagers"`
+		ExcludedPackageManagers     []string `json:"excludedPackageManagers"`
+		MavenExcludedScopes         []string `json:"mavenExcludedScopes"`
+		DetectTools                 []string `json:"detectTools"`
+		ExcludedDirectories         []string `json:"excludedDirectories"`
+		NpmDependencyTypesExcluded  []string `json:"npmDependencyTypesExcluded"`
+	}{config.ServerURL, config.ProjectName, getVersionName(config), config.Scanners, config.ScanPaths, config.ScanProperties,
+		config.FailOn, config.FailOnSevereVulnerabilities, config.IncludedPackageManagers, config.ExcludedPackageManagers,
+		config.MavenExcludedScopes, config.DetectTools, config.ExcludedDirectories, config.NpmDependencyTypesExcluded})
+}
+
+// detectSignatureScanEnabled checks whether the tools run by detect include the signature or the binary scanner,
+// which are part of the default tools of detect
+func detectSignatureScanEnabled(config detectExecuteScanOptions) bool {
+	tools := config.DetectTools
+	excluded := []string{}
+	for _, property := range config.ScanProperties {
+		if strings.HasPrefix(property, "--detect.tools=") {
+			tools = strings.Split(strings.TrimPrefix(property, "--detect.tools="), ",")
+		}
+		if strings.HasPrefix(property, "--detect.tools.excluded=") {
+			excluded = strings.Split(strings.TrimPrefix(property, "--detect.tools.excluded="), ",")
+		}
+	}
+	for _, tool := range []string{"SIGNATURE_SCAN", "BINARY_SCAN"} {
+		if piperutils.ContainsString(excluded, tool) || piperutils.ContainsString(excluded, "ALL") {
+			continue
+		}
+		if len(tools) == 0 || piperutils.ContainsString(tools, tool) || piperutils.ContainsString(tools, "ALL") {
+			return true
+		}
+	}
+	return false
+}
+
+// restoreDetectInfluxData sets the influx data of a scan restored from the scan cache
+func restoreDetectInfluxData(entry *scancache.Entry, influx *detectExecuteScanInflux) {
+	result := map[string]int{}
+	if _, err := entry.Output("influx", &result); err != nil {
+		log.Entry().WithError(err).Warn("failed to restore influx data from scan cache")
+		return
+	}
+	influx.detect_data.fields.vulnerabilities = result["vulnerabilities"]
+	influx.detect_data.fields.major_vulnerabilities = result["major_vulnerabilities"]
+	influx.detect_data.fields.minor_vulnerabilities = result["minor_vulnerabilities"]
+	influx.detect_data.fields.components = result["components"]
+	influx.detect_data.fields.policy_violations = result["policy_violations"]
+}
+
+func getDetectInfluxData(influx *detectExecuteScanInflux) map[string]int {
+	return map[string]int{
+		"vulnerabilities":       influx.detect_data.fields.vulnerabilities,
+		"major_vulnerabilities": influx.detect_data.fields.major_vulnerabilities,
+		"minor_vulnerabilities": influx.detect_data.fields.minor_vulnerabilities,
+		"components":            influx.detect_data.fields.components,
+		"policy_violations":     influx.detect_data.fields.policy_violations,
+	}
 }
 
 // Get proper error category
@@ -515,6 +662,51 @@ func createVulnerabilityReport(config detectExecuteScanOptions, vulns *bd.Vulner
 	return scanReport
 }
 
+// commentRapidScanResult creates or updates the pull request comment containing the result of the rapid scan
+func commentRapidScanResult(config detectExecuteScanOptions, utils detectUtils, sys *blackduckSystem) error {
+	pullRequestKey := utils.GetProvider().GetPullRequestConfig().Key
+	number, err := strconv.Atoi(pullRequestKey)
+	if err != nil {
+		return errors.Wrapf(err, "invalid pull-request id '%v'", pullRequestKey)
+	}
+	if len(config.GithubToken) == 0 {
+		return errors.New("no githubToken provided")
+	}
+	commentBody, err := reporting.RapidScanResult("./report", upgradeGuidanceLookup(sys))
+	if err != nil {
+		return errors.Wrap(err, "couldn't read report of rapid scan")
+	}
+	_, err = piperGithub.CreateOrUpdatePullRequestComment(&piperGithub.PullRequestCommentOptions{
+		APIURL:       config.GithubAPIURL,
+		Token:        config.GithubToken,
+		Owner:        config.Owner,
+		Repository:   config.Repository,
+		Number:       number,
+		Marker:       "piper-detectExecuteScan",
+		Body:         []byte(commentBody),
+		TrustedCerts: config.CustomTLSCertificateLinks,
+	})
+	return err
+}
+
+// upgradeGuidanceLookup provides the upgrade guidance of Black Duck for the components of the rapid scan result
+func upgradeGuidanceLookup(sys *blackduckSystem) reporting.UpgradeGuidanceLookup {
+	return func(component reporting.Component) (reporting.UpgradeGuidance, error) {
+		result := reporting.UpgradeGuidance{}
+		guidance, err := sys.Client.GetUpgradeGuidance(component.Meta.Href)
+		if err != nil {
+			return result, err
+		}
+		if guidance.ShortTerm != nil {
+			result.ShortTerm = guidance.ShortTerm.VersionName
+		}
+		if guidance.LongTerm != nil {
+			result.LongTerm = guidance.LongTerm.VersionName
+		}
+		return result, nil
+	}
+}
+
 func isActiveVulnerability(v bd.Vulnerability) bool {
 	if v.Ignored {
 		return false
@@ -545,38 +737,20 @@ func isMajorVulnerability(v bd.Vulnerability) bool {
 	}
 }
 
-func postScanChecksAndReporting(ctx context.Context, config detectExecuteScanOptions, influx *detectExecuteScanInflux, utils detectUtils, sys *blackduckSystem) error {
+// postScanChecksAndReporting creates the reports of the scan and returns their paths
+func postScanChecksAndReporting(ctx context.Context, config detectExecuteScanOptions, influx *detectExecuteScanInflux, utils detectUtils, sys *blackduckSystem) ([]piperutils.Path, error) {
 
 	if utils.GetProvider().IsPullRequest() {
-		issueNumber, err := strconv.Atoi(utils.GetProvider().GetPullRequestConfig().Key)
-		if err != nil {
-			log.Entry().Warning("Can not get issue number ", err)
-			return nil
+		if err := commentRapidScanResult(config, utils, sys); err != nil {
+			log.Entry().WithError(err).Warning("Failed to comment rapid scan result on pull request")
 		}
-		commentBody, err := reporting.RapidScanResult("./report")
-		if err != nil {
-			log.Entry().Warning("Couldn't read file of report of rapid scan, error: ", err)
-			return nil
-		}
-		_, _, err = utils.GetIssueService().CreateComment(ctx,
-			config.Owner,
-			config.Repository,
-			issueNumber,
-			&github.IssueComment{
-				Body: &commentBody,
-			})
-		if err != nil {
-			log.Entry().Warning("Can send request to github ", err)
-			return nil
-		}
-
-		return nil
+		return nil, nil
 	}
 
 	errorsOccured := []string{}
 	vulns, err := getVulnerabilitiesWithComponents(config, influx, sys)
 	if err != nil {
-		return errors.Wrap(err, "failed to fetch vulnerabilities")
+		return nil, errors.Wrap(err, "failed to fetch vulnerabilities")
 	}
 
 	if config.CreateResultIssue && len(config.GithubToken) > 0 && len(config.GithubAPIURL) > 0 && len(config.Owner) > 0 && len(config.Repository) > 0 {
@@ -626,11 +800,6 @@ func postScanChecksAndReporting(ctx context.Context, config detectExecuteScanOpt
 	}
 	paths = append(paths, policyReportPaths...)
 
-	piperutils.PersistReportsAndLinks("detectExecuteScan", "", utils, paths, nil)
-	if err != nil {
-		errorsOccured = append(errorsOccured, fmt.Sprint(err))
-	}
-
 	err, violationCount := writeIpPolicyJson(config, utils, paths, sys)
 	if err != nil {
 		errorsOccured = append(errorsOccured, fmt.Sprint(err))
@@ -642,10 +811,25 @@ func postScanChecksAndReporting(ctx context.Context, config detectExecuteScanOpt
 	}
 
 	if len(errorsOccured) > 0 {
-		return fmt.Errorf(strings.Join(errorsOccured, ": "))
+		err := fmt.Errorf(strings.Join(errorsOccured, ": "))
+		if violationCount > 0 && len(errorsOccured) == 1 {
+			return paths, detectViolationError{err}
+		}
+		return paths, err
 	}
 
-	return nil
+	return paths, nil
+}
+
+// detectViolationError is returned if license policy violations are found,
+// the results of the scan are complete nevertheless
+type detectViolationError struct {
+	error
+}
+
+func isDetectViolation(err error) bool {
+	var violation detectViolationError
+	return errors.As(err, &violation)
 }
 
 func getVulnerabilitiesWithComponents(config detectExecuteScanOptions, influx *detectExecuteScanInflux, sys *blackduckSystem) (*bd.Vulnerabilities, error) {
@@ -59,6 +59,9 @@ type detectExecuteScanOptions struct {
 	ExcludedDirectories         []string `json:"excludedDirectories,omitempty"`
 	NpmDependencyTypesExcluded  []string `json:"npmDependencyTypesExcluded,omitempty" validate:"possible-values=NONE DEV PEER"`
 	NpmArguments                []string `json:"npmArguments,omitempty"`
+	ScanCache                   bool     `json:"scanCache,omitempty"`
+	ScanCacheLocation           string   `json:"scanCacheLocation,omitempty"`
+	ScanCacheMaxAge             int      `json:"scanCacheMaxAge,omitempty"`
 }
 
 type detectExecuteScanInflux struct {
@@ -293,6 +296,9 @@ func addDetectExecuteScanFlags(cmd *cobra.Command, stepConfig *detectExecuteScan
 	cmd.Flags().StringSliceVar(&stepConfig.ExcludedDirectories, "excludedDirectories", []string{}, "List of directories which should be excluded from the scan.")
 	cmd.Flags().StringSliceVar(&stepConfig.NpmDependencyTypesExcluded, "npmDependencyTypesExcluded", []string{}, "List of npm dependency types which Detect should exclude from the BOM.")
 	cmd.Flags().StringSliceVar(&stepConfig.NpmArguments, "npmArguments", []string{}, "List of additional arguments that Detect will add at then end of the npm ls command line when Detect executes the NPM CLI Detector on an NPM project.")
+	cmd.Flags().BoolVar(&stepConfig.ScanCache, "scanCache", false, "Reuse the scan result of a previous run if the scanned content and the configuration are unchanged.")
+	cmd.Flags().StringVar(&stepConfig.ScanCacheLocation, "scanCacheLocation", os.Getenv("PIPER_scanCacheLocation"), "Location of the scan cache: a local directory, `gs://<bucket>/<folder>` or `s3://<bucket>/<prefix>`.")
+	cmd.Flags().IntVar(&stepConfig.ScanCacheMaxAge, "scanCacheMaxAge", 72, "Time to live of a cached scan result in hours.")
 
 	cmd.MarkFlagRequired("token")
 	cmd.MarkFlagRequired("projectName")
@@ -698,6 +704,38 @@ func detectExecuteScanMetadata() config.StepData {
 						Aliases:     []config.Alias{{Name: "detect/npmArguments"}},
 						Default:     []string{},
 					},
+					{
+						Name: "scanCache",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "custom/isOptimizedAndScheduled",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "bool",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   false,
+					},
+					{
+						Name:        "scanCacheLocation",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_scanCacheLocation"),
+					},
+					{
+						Name:        "scanCacheMaxAge",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     72,
+					},
 				},
 			},
 			Containers: []config.Container{
@@ -17,6 +17,7 @@ import (
 	piperhttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/mock"
 	"github.com/SAP/jenkins-library/pkg/orchestrator"
+	"github.com/SAP/jenkins-library/pkg/reporting"
 
 	"github.com/google/go-github/v45/github"
 	"github.com/stretchr/testify/assert"
@@ -45,13 +46,21 @@ func (d *detectTestUtilsBundle) GetSearchService() *github.SearchService {
 
 type orchestratorConfigProviderMock struct {
 	orchestrator.UnknownOrchestratorConfigProvider
-	isPullRequest bool
+	isPullRequest  bool
+	pullRequestKey string
 }
 
 func (o *orchestratorConfigProviderMock) IsPullRequest() bool {
 	return o.isPullRequest
 }
 
+func (o *orchestratorConfigProviderMock) GetPullRequestConfig() orchestrator.PullRequestConfig {
+	if len(o.pullRequestKey) == 0 {
+		return o.UnknownOrchestratorConfigProvider.GetPullRequestConfig()
+	}
+	return orchestrator.PullRequestConfig{Key: o.pullRequestKey}
+}
+
 type httpMockClient struct {
 	responseBodyForURL map[string]string
 	errorMessageForURL map[string]string
@@ -787,15 +796,63 @@ func TestPostScanChecksAndReporting(t *testing.T) {
 		config := detectExecuteScanOptions{Token: "token", ServerURL: "https://my.blackduck.system", ProjectName: "SHC-PiperTest", Version: "", CustomScanVersion: "1.0"}
 		utils := newDetectTestUtilsBundle(false)
 		sys := newBlackduckMockSystem(config)
-		err := postScanChecksAndReporting(ctx, config, &detectExecuteScanInflux{}, utils, &sys)
+		reports, err := postScanChecksAndReporting(ctx, config, &detectExecuteScanInflux{}, utils, &sys)
 
 		assert.EqualError(t, err, "License Policy Violations found")
+		assert.True(t, isDetectViolation(err))
+		assert.NotEmpty(t, reports)
 		content, err := utils.FileRead("blackduck-ip.json")
 		assert.NoError(t, err)
 		assert.Contains(t, string(content), `"policyViolations":2`)
 	})
 }
 
+func TestCommentRapidScanResult(t *testing.T) {
+	t.Parallel()
+	t.Run("invalid pull request id", func(t *testing.T) {
+		config := detectExecuteScanOptions{GithubToken: "token"}
+		utils := newDetectTestUtilsBundle(true)
+		sys := newBlackduckMockSystem(config)
+
+		err := commentRapidScanResult(config, utils, &sys)
+		assert.EqualError(t, err, "invalid pull-request id 'n/a': strconv.Atoi: parsing \"n/a\": invalid syntax")
+	})
+
+	t.Run("no GitHub token", func(t *testing.T) {
+		config := detectExecuteScanOptions{}
+		utils := newDetectTestUtilsBundle(true)
+		utils.orchestrator.pullRequestKey = "42"
+		sys := newBlackduckMockSystem(config)
+
+		err := commentRapidScanResult(config, utils, &sys)
+		assert.EqualError(t, err, "no githubToken provided")
+	})
+}
+
+func TestUpgradeGuidanceLookup(t *testing.T) {
+	t.Parallel()
+	versionLink := "https://my.blackduck.system/api/components/c1/versions/v1"
+	myTestClient := httpMockClient{
+		responseBodyForURL: map[string]string{
+			"https://my.blackduck.system/api/tokens/authenticate": authContent,
+			versionLink + "/upgrade-guidance":                     `{"componentName": "qs", "versionName": "5.2.1", "longTerm": {"versionName": "6.11.0"}}`,
+		},
+		header: map[string]http.Header{},
+	}
+	sys := blackduckSystem{Client: bd.NewClient("token", "https://my.blackduck.system", &myTestClient)}
+
+	t.Run("success", func(t *testing.T) {
+		guidance, err := upgradeGuidanceLookup(&sys)(reporting.Component{Meta: reporting.Meta{Href: versionLink}})
+		assert.NoError(t, err)
+		assert.Equal(t, reporting.UpgradeGuidance{LongTerm: "6.11.0"}, guidance)
+	})
+
+	t.Run("component without version link", func(t *testing.T) {
+		_, err := upgradeGuidanceLookup(&sys)(reporting.Component{})
+		assert.EqualError(t, err, "'' is not a link to a component version")
+	})
+}
+
 func TestIsMajorVulnerability(t *testing.T) {
 	t.Parallel()
 	t.Run("Case True", func(t *testing.T) {
@@ -923,3 +980,48 @@ func TestGetVulnerabilitiesWithComponents(t *testing.T) {
 		assert.Equal(t, vulnerableComponentLog4j, vulnerabilityLog4j2.Component)
 	})
 }
+
+func TestDetectScanCacheKey(t *testing.T) {
+	t.Parallel()
+	t.Run("signature scan includes the sources", func(t *testing.T) {
+		t.Parallel()
+		utils := newDetectTestUtilsBundle(false)
+		utils.AddFile("package.json", []byte("{}"))
+		utils.AddFile("package-lock.json", []byte("{}"))
+		utils.AddFile("src/index.js", []byte(""))
+		config := detectExecuteScanOptions{ProjectName: "project", Version: "1.0"}
+
+		key, err := detectScanCacheKey(config, utils)
+		assert.NoError(t, err)
+
+		utils.AddFile("src/util.js", []byte(""))
+		changed, err := detectScanCacheKey(config, utils)
+		assert.NoError(t, err)
+		assert.NotEqual(t, key, changed)
+
+		config.DetectTools = []string{"DETECTOR"}
+		withoutSources, err := detectScanCacheKey(config, utils)
+		assert.NoError(t, err)
+		utils.AddFile("src/other.js", []byte(""))
+		unchanged, _ := detectScanCacheKey(config, utils)
+		assert.Equal(t, withoutSources, unchanged)
+	})
+
+	t.Run("unpinned dependencies", func(t *testing.T) {
+		t.Parallel()
+		utils := newDetectTestUtilsBundle(false)
+		utils.AddFile("pom.xml", []byte("<project/>"))
+
+		_, err := detectScanCacheKey(detectExecuteScanOptions{}, utils)
+		assert.EqualError(t, err, "'pom.xml' does not pin its dependencies, none of [lockfile.json] found")
+	})
+}
+
+func TestDetectSignatureScanEnabled(t *testing.T) {
+	t.Parallel()
+	assert.True(t, detectSignatureScanEnabled(detectExecuteScanOptions{}))
+	assert.True(t, detectSignatureScanEnabled(detectExecuteScanOptions{DetectTools: []string{"DETECTOR", "SIGNATURE_SCAN"}}))
+	assert.False(t, detectSignatureScanEnabled(detectExecuteScanOptions{DetectTools: []string{"DETECTOR"}}))
+	assert.False(t, detectSignatureScanEnabled(detectExecuteScanOptions{ScanProperties: []string{"--detect.tools=DETECTOR"}}))
+	assert.False(t, detectSignatureScanEnabled(detectExecuteScanOptions{ScanProperties: []string{"--detect.tools.excluded=SIGNATURE_SCAN,BINARY_SCAN"}}))
+}
@@ -343,6 +343,26 @@ func (f *fortifyMock) GetIssueComments(parentId int64) ([]*models.IssueAuditComm
 	return []*models.IssueAuditComment{{Comment: &comment}}, nil
 }
 
+func (f *fortifyMock) AddIssueComment(issueID int64, comment string) error {
+	return nil
+}
+
+func (f *fortifyMock) GetIssueAuditState(issueID int64) (*fortify.IssueAuditState, error) {
+	return &fortify.IssueAuditState{ID: issueID}, nil
+}
+
+func (f *fortifyMock) AuditIssue(projectVersionID int64, audit fortify.IssueAuditRequest) error {
+	return nil
+}
+
+func (f *fortifyMock) GetProjectVersionsOfProject(projectID int64) ([]*models.ProjectVersion, error) {
+	return []*models.ProjectVersion{}, nil
+}
+
+func (f *fortifyMock) DeleteProjectVersion(id int64) error {
+	return nil
+}
+
 func (f *fortifyMock) UploadResultFile(endpoint, file string, projectVersionID int64) error {
 	return nil
 }
@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/fortify"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/SAP/jenkins-library/pkg/versioning"
+	"github.com/piper-validation/fortify-client-go/models"
+	"github.com/pkg/errors"
+)
+
+func fortifySyncAudits(config fortifySyncAuditsOptions, telemetryData *telemetry.CustomData) {
+	sys := fortify.NewSystemInstance(config.ServerURL, config.APIEndpoint, config.AuthToken, time.Minute*15)
+	utils := &piperutils.Files{}
+	reports, err := runFortifySyncAudits(config, sys, utils, time.Now())
+	piperutils.PersistReportsAndLinks("fortifySyncAudits", "./", utils, reports, nil)
+	if err != nil {
+		log.Entry().WithError(err).Fatal("Fortify audit synchronization failed")
+	}
+}
+
+func runFortifySyncAudits(config fortifySyncAuditsOptions, sys fortify.System, utils piperutils.FileUtils, now time.Time) ([]piperutils.Path, error) {
+	pattern, err := regexp.Compile(config.PullRequestVersionPattern)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return nil, errors.Wrapf(err, "invalid pullRequestVersionPattern %v", config.PullRequestVersionPattern)
+	}
+
+	projectName, versionName := versioning.DetermineProjectCoordinatesWithCustomVersion(config.ProjectName, config.VersioningModel, config.CustomScanVersion, versioning.Coordinates{Version: config.Version})
+	project, err := sys.GetProjectByName(projectName, false, "")
+	if err != nil {
+		classifyErrorOnLookup(err)
+		return nil, fmt.Errorf("Failed to load project %v: %w", projectName, err)
+	}
+	master, err := sys.GetProjectVersionDetailsByProjectIDAndVersionName(project.ID, versionName, false, projectName)
+	if err != nil {
+		classifyErrorOnLookup(err)
+		return nil, fmt.Errorf("Failed to load project version %v: %w", versionName, err)
+	}
+
+	pullRequestVersions, err := lookupPullRequestVersions(config, sys, project, master, pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	// stale versions are deleted after their audits have been synchronized into the main version
+	staleVersions := []*models.ProjectVersion{}
+	if config.StalePullRequestVersionDays > 0 {
+		versions, err := sys.GetProjectVersionsOfProject(project.ID)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list project versions of project %v", projectName)
+		}
+		keep := []int64{master.ID}
+		if len(config.PullRequestName) > 0 {
+			keep = append(keep, pullRequestVersions[0].ID)
+		}
+		maxAge := time.Duration(config.StalePullRequestVersionDays) * 24 * time.Hour
+		staleVersions = fortify.StalePullRequestVersions(versions, pattern, maxAge, now, keep...)
+	}
+	stale := map[int64]bool{}
+	for _, version := range staleVersions {
+		stale[version.ID] = true
+	}
+
+	result := &fortify.AuditSyncResult{
+		ProjectName:     projectName,
+		MasterVersion:   versionName,
+		MasterVersionID: master.ID,
+		Versions:        []fortify.AuditSyncVersionResult{},
+		DeletedVersions: []string{},
+	}
+	options := fortify.AuditSyncOptions{
+		Direction:          config.SyncDirection,
+		ConflictResolution: config.ConflictResolution,
+		SyncTags:           config.SyncTags,
+		SyncSuppressions:   config.SyncSuppressions,
+		SyncComments:       config.SyncComments,
+	}
+	for _, pullRequestVersion := range pullRequestVersions {
+		if stale[pullRequestVersion.ID] {
+			continue
+		}
+		if _, err := syncPullRequestVersionAudits(sys, master, pullRequestVersion, options, result); err != nil {
+			return nil, err
+		}
+	}
+
+	// audits of stale versions only need to reach the main version since the versions are deleted afterwards
+	staleOptions := options
+	staleOptions.Direction = fortify.SyncDirectionToMaster
+	for _, staleVersion := range staleVersions {
+		versionResult, err := syncPullRequestVersionAudits(sys, master, staleVersion, staleOptions, result)
+		if err != nil {
+			log.Entry().WithError(err).Warnf("Keeping stale project version %v since its audits could not be synchronized", *staleVersion.Name)
+			continue
+		}
+		if len(versionResult.Conflicts) > 0 {
+			log.Entry().Warnf("Keeping stale project version %v since %v conflicting audits could not be synchronized", *staleVersion.Name, len(versionResult.Conflicts))
+			continue
+		}
+		deleted, err := fortify.DeleteProjectVersions(sys, []*models.ProjectVersion{staleVersion})
+		result.DeletedVersions = append(result.DeletedVersions, deleted...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	report, err := fortify.WriteAuditSyncReport(result, utils)
+	if err != nil {
+		return nil, err
+	}
+	reports := []piperutils.Path{report}
+
+	if config.FailOnConflicts && result.ConflictsSkipped > 0 {
+		log.SetErrorCategory(log.ErrorCompliance)
+		return reports, fmt.Errorf("%v conflicting audits could not be synchronized, please resolve them manually", result.ConflictsSkipped)
+	}
+	return reports, nil
+}
+
+// syncPullRequestVersionAudits synchronizes the audits between the main version and the pull-request version and adds the outcome to result
+func syncPullRequestVersionAudits(sys fortify.System, master, pullRequestVersion *models.ProjectVersion, options fortify.AuditSyncOptions, result *fortify.AuditSyncResult) (*fortify.AuditSyncVersionResult, error) {
+	log.Entry().Infof("Synchronizing audits between project versions %v and %v", *master.Name, *pullRequestVersion.Name)
+	versionResult, err := fortify.SyncAudits(sys, master, pullRequestVersion, options)
+	if err != nil {
+		return nil, err
+	}
+	log.Entry().Infof("Matched %v issues, updated %v issues in %v and %v issues in %v, copied %v comments, %v conflicts skipped",
+		versionResult.MatchedIssues, len(versionResult.IssuesAuditedMaster), *master.Name, len(versionResult.IssuesAuditedPullRequest), *pullRequestVersion.Name,
+		versionResult.CommentsCopiedMaster+versionResult.CommentsCopiedPullRequest, len(versionResult.Conflicts))
+	for _, conflict := range versionResult.Conflicts {
+		log.Entry().Warnf("Conflict for issue %v (%v): custom tag %v is '%v' in %v and '%v' in %v",
+			conflict.IssueInstanceID, conflict.IssueName, conflict.CustomTagGUID, conflict.MasterValue, *master.Name, conflict.PullRequestValue, *pullRequestVersion.Name)
+	}
+	result.ConflictsSkipped += len(versionResult.Conflicts)
+	result.Versions = append(result.Versions, *versionResult)
+	return versionResult, nil
+}
+
+func lookupPullRequestVersions(config fortifySyncAuditsOptions, sys fortify.System, project *models.Project, master *models.ProjectVersion, pattern *regexp.Regexp) ([]*models.ProjectVersion, error) {
+	if len(config.PullRequestName) > 0 {
+		version, err := sys.GetProjectVersionDetailsByProjectIDAndVersionName(project.ID, config.PullRequestName, false, *project.Name)
+		if err != nil {
+			classifyErrorOnLookup(err)
+			return nil, fmt.Errorf("Failed to load project version %v: %w", config.PullRequestName, err)
+		}
+		return []*models.ProjectVersion{version}, nil
+	}
+	versions, err := sys.GetProjectVersionsOfProject(project.ID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list project versions of project %v", *project.Name)
+	}
+	pullRequestVersions := []*models.ProjectVersion{}
+	for _, version := range versions {
+		if version.ID != master.ID && version.Name != nil && pattern.MatchString(*version.Name) {
+			pullRequestVersions = append(pullRequestVersions, version)
+		}
+	}
+	return pullRequestVersions, nil
+}
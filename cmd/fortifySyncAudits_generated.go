@@ -0,0 +1,398 @@
+// Code generated by piper's step-generator. DO NOT EDIT.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/gcs"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/splunk"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/SAP/jenkins-library/pkg/validation"
+	"github.com/bmatcuk/doublestar"
+	"github.com/spf13/cobra"
+)
+
+type fortifySyncAuditsOptions struct {
+	AuthToken                   string `json:"authToken,omitempty"`
+	ServerURL                   string `json:"serverUrl,omitempty"`
+	APIEndpoint                 string `json:"apiEndpoint,omitempty"`
+	ProjectName                 string `json:"projectName,omitempty"`
+	Version                     string `json:"version,omitempty"`
+	CustomScanVersion           string `json:"customScanVersion,omitempty"`
+	VersioningModel             string `json:"versioningModel,omitempty" validate:"possible-values=major major-minor semantic full"`
+	PullRequestName             string `json:"pullRequestName,omitempty"`
+	PullRequestVersionPattern   string `json:"pullRequestVersionPattern,omitempty"`
+	SyncDirection               string `json:"syncDirection,omitempty" validate:"possible-values=both toMaster toPullRequest"`
+	ConflictResolution          string `json:"conflictResolution,omitempty" validate:"possible-values=skip master pullRequest"`
+	SyncTags                    bool   `json:"syncTags,omitempty"`
+	SyncSuppressions            bool   `json:"syncSuppressions,omitempty"`
+	SyncComments                bool   `json:"syncComments,omitempty"`
+	FailOnConflicts             bool   `json:"failOnConflicts,omitempty"`
+	StalePullRequestVersionDays int    `json:"stalePullRequestVersionDays,omitempty"`
+}
+
+type fortifySyncAuditsReports struct {
+}
+
+func (p *fortifySyncAuditsReports) persist(stepConfig fortifySyncAuditsOptions, gcpJsonKeyFilePath string, gcsBucketId string, gcsFolderPath string, gcsSubFolder string) {
+	if gcsBucketId == "" {
+		log.Entry().Info("persisting reports to GCS is disabled, because gcsBucketId is empty")
+		return
+	}
+	log.Entry().Info("Uploading reports to Google Cloud Storage...")
+	content := []gcs.ReportOutputParam{
+		{FilePattern: "**/fortifyAuditSync.json", ParamRef: "", StepResultType: "fortify"},
+	}
+	envVars := []gcs.EnvVar{
+		{Name: "GOOGLE_APPLICATION_CREDENTIALS", Value: gcpJsonKeyFilePath, Modified: false},
+	}
+	gcsClient, err := gcs.NewClient(gcs.WithEnvVars(envVars))
+	if err != nil {
+		log.Entry().Errorf("creation of GCS client failed: %v", err)
+		return
+	}
+	defer gcsClient.Close()
+	structVal := reflect.ValueOf(&stepConfig).Elem()
+	inputParameters := map[string]string{}
+	for i := 0; i < structVal.NumField(); i++ {
+		field := structVal.Type().Field(i)
+		if field.Type.String() == "string" {
+			paramName := strings.Split(field.Tag.Get("json"), ",")
+			paramValue, _ := structVal.Field(i).Interface().(string)
+			inputParameters[paramName[0]] = paramValue
+		}
+	}
+	if err := gcs.PersistReportsToGCS(gcsClient, content, inputParameters, gcsFolderPath, gcsBucketId, gcsSubFolder, doublestar.Glob, os.Stat); err != nil {
+		log.Entry().Errorf("failed to persist reports: %v", err)
+	}
+}
+
+// FortifySyncAuditsCommand This step synchronizes the audits of Fortify issues between pull-request project versions and the main project version.
+func FortifySyncAuditsCommand() *cobra.Command {
+	const STEP_NAME = "fortifySyncAudits"
+
+	metadata := fortifySyncAuditsMetadata()
+	var stepConfig fortifySyncAuditsOptions
+	var startTime time.Time
+	var reports fortifySyncAuditsReports
+	var logCollector *log.CollectorHook
+	var splunkClient *splunk.Splunk
+	telemetryClient := &telemetry.Telemetry{}
+
+	var createFortifySyncAuditsCmd = &cobra.Command{
+		Use:   STEP_NAME,
+		Short: "This step synchronizes the audits of Fortify issues between pull-request project versions and the main project version.",
+		Long: `This step synchronizes the audits of Fortify issues between the project versions created for pull-requests (see [` + "`" + `fortifyExecuteScan` + "`" + `](fortifyExecuteScan.md) parameter ` + "`" + `pullRequestName` + "`" + `) and the main project version in Fortify SSC.
+
+Issues are matched between the versions by their instance ID. For every matched issue the step copies
+* custom tag values (e.g. ` + "`" + `Analysis` + "`" + `) which are only set in one of the versions,
+* suppressions, an issue is never unsuppressed by the synchronization,
+* comments, which are prefixed with ` + "`" + `[synced from <version>, author <user>]` + "`" + ` and never copied back.
+
+Custom tags which are set to different values in both versions are treated as conflicts and resolved according to [` + "`" + `conflictResolution` + "`" + `](#conflictresolution).
+The outcome of the synchronization is written to ` + "`" + `fortify/fortifyAuditSync.json` + "`" + `.
+
+Optionally, pull-request project versions which did not receive a scan result for a given number of days are deleted.`,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			startTime = time.Now()
+			log.SetStepName(STEP_NAME)
+			log.SetVerbose(GeneralConfig.Verbose)
+
+			GeneralConfig.GitHubAccessTokens = ResolveAccessTokens(GeneralConfig.GitHubTokens)
+
+			path, _ := os.Getwd()
+			fatalHook := &log.FatalHook{CorrelationID: GeneralConfig.CorrelationID, Path: path}
+			log.RegisterHook(fatalHook)
+
+			err := PrepareConfig(cmd, &metadata, STEP_NAME, &stepConfig, config.OpenPiperFile)
+			if err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+			log.RegisterSecret(stepConfig.AuthToken)
+
+			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
+				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
+				log.RegisterHook(&sentryHook)
+			}
+
+			if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+				splunkClient = &splunk.Splunk{}
+				logCollector = &log.CollectorHook{CorrelationID: GeneralConfig.CorrelationID}
+				log.RegisterHook(logCollector)
+			}
+
+			if err = log.RegisterANSHookIfConfigured(GeneralConfig.CorrelationID); err != nil {
+				log.Entry().WithError(err).Warn("failed to set up SAP Alert Notification Service log hook")
+			}
+
+			validation, err := validation.New(validation.WithJSONNamesForStructFields(), validation.WithPredefinedErrorMessages())
+			if err != nil {
+				return err
+			}
+			if err = validation.ValidateStruct(stepConfig); err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+
+			return nil
+		},
+		Run: func(_ *cobra.Command, _ []string) {
+			stepTelemetryData := telemetry.CustomData{}
+			stepTelemetryData.ErrorCode = "1"
+			handler := func() {
+				reports.persist(stepConfig, GeneralConfig.GCPJsonKeyFilePath, GeneralConfig.GCSBucketId, GeneralConfig.GCSFolderPath, GeneralConfig.GCSSubFolder)
+				config.RemoveVaultSecretFiles()
+				stepTelemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
+				stepTelemetryData.ErrorCategory = log.GetErrorCategory().String()
+				stepTelemetryData.PiperCommitHash = GitCommit
+				telemetryClient.SetData(&stepTelemetryData)
+				telemetryClient.Send()
+				if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+					splunkClient.Send(telemetryClient.GetData(), logCollector)
+				}
+			}
+			log.DeferExitHandler(handler)
+			defer handler()
+			telemetryClient.Initialize(GeneralConfig.NoTelemetry, STEP_NAME)
+			if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+				splunkClient.Initialize(GeneralConfig.CorrelationID,
+					GeneralConfig.HookConfig.SplunkConfig.Dsn,
+					GeneralConfig.HookConfig.SplunkConfig.Token,
+					GeneralConfig.HookConfig.SplunkConfig.Index,
+					GeneralConfig.HookConfig.SplunkConfig.SendLogs)
+			}
+			fortifySyncAudits(stepConfig, &stepTelemetryData)
+			stepTelemetryData.ErrorCode = "0"
+			log.Entry().Info("SUCCESS")
+		},
+	}
+
+	addFortifySyncAuditsFlags(createFortifySyncAuditsCmd, &stepConfig)
+	return createFortifySyncAuditsCmd
+}
+
+func addFortifySyncAuditsFlags(cmd *cobra.Command, stepConfig *fortifySyncAuditsOptions) {
+	cmd.Flags().StringVar(&stepConfig.AuthToken, "authToken", os.Getenv("PIPER_authToken"), "The FortifyToken to use for authentication")
+	cmd.Flags().StringVar(&stepConfig.ServerURL, "serverUrl", os.Getenv("PIPER_serverUrl"), "Fortify SSC Url to be used for accessing the APIs")
+	cmd.Flags().StringVar(&stepConfig.APIEndpoint, "apiEndpoint", `/api/v1`, "Fortify SSC endpoint used for accessing the APIs")
+	cmd.Flags().StringVar(&stepConfig.ProjectName, "projectName", os.Getenv("PIPER_projectName"), "The project in SSC whose versions are synchronized")
+	cmd.Flags().StringVar(&stepConfig.Version, "version", os.Getenv("PIPER_version"), "Version used in conjunction with [`versioningModel`](#versioningmodel) to identify the main project version in SSC.")
+	cmd.Flags().StringVar(&stepConfig.CustomScanVersion, "customScanVersion", os.Getenv("PIPER_customScanVersion"), "Custom version of the main Fortify project version, supersedes [`version`](#version) and [`versioningModel`](#versioningmodel).")
+	cmd.Flags().StringVar(&stepConfig.VersioningModel, "versioningModel", `major`, "The project versioning model used for creating the main project version name based on [`version`](#version), can be one of `'major'`, `'major-minor'`, `'semantic'`, `'full'`")
+	cmd.Flags().StringVar(&stepConfig.PullRequestName, "pullRequestName", os.Getenv("PIPER_pullRequestName"), "The name of the pull-request project version to synchronize with the main project version. If empty, all project versions matching [`pullRequestVersionPattern`](#pullrequestversionpattern) are synchronized.")
+	cmd.Flags().StringVar(&stepConfig.PullRequestVersionPattern, "pullRequestVersionPattern", `^PR-\d+$`, "Regular expression identifying pull-request project versions, used for synchronizing all pull-requests and for the cleanup of stale versions.")
+	cmd.Flags().StringVar(&stepConfig.SyncDirection, "syncDirection", `both`, "Defines in which direction audits are synchronized.")
+	cmd.Flags().StringVar(&stepConfig.ConflictResolution, "conflictResolution", `skip`, "Defines how custom tags which are set to different values in both versions are handled. `skip` leaves both values untouched and reports the conflict, `master` and `pullRequest` define which version wins.")
+	cmd.Flags().BoolVar(&stepConfig.SyncTags, "syncTags", true, "Whether custom tag values (e.g. `Analysis`) are synchronized.")
+	cmd.Flags().BoolVar(&stepConfig.SyncSuppressions, "syncSuppressions", true, "Whether suppressions are synchronized.")
+	cmd.Flags().BoolVar(&stepConfig.SyncComments, "syncComments", true, "Whether audit comments are synchronized.")
+	cmd.Flags().BoolVar(&stepConfig.FailOnConflicts, "failOnConflicts", false, "Whether the step fails if conflicts were skipped.")
+	cmd.Flags().IntVar(&stepConfig.StalePullRequestVersionDays, "stalePullRequestVersionDays", 0, "Pull-request project versions matching [`pullRequestVersionPattern`](#pullrequestversionpattern) whose last scan result upload is older than the given number of days are deleted. Before deletion the audits of a stale version are synchronized into the master version, versions with conflicting audits are kept. The master version is never deleted. `0` disables the cleanup.")
+
+	cmd.MarkFlagRequired("authToken")
+	cmd.MarkFlagRequired("serverUrl")
+	cmd.MarkFlagRequired("projectName")
+}
+
+// retrieve step metadata
+func fortifySyncAuditsMetadata() config.StepData {
+	var theMetaData = config.StepData{
+		Metadata: config.StepMetadata{
+			Name:        "fortifySyncAudits",
+			Aliases:     []config.Alias{},
+			Description: "This step synchronizes the audits of Fortify issues between pull-request project versions and the main project version.",
+		},
+		Spec: config.StepSpec{
+			Inputs: config.StepInputs{
+				Secrets: []config.StepSecrets{
+					{Name: "fortifyCredentialsId", Description: "Jenkins 'Secret text' credentials ID containing token to authenticate to Fortify SSC.", Type: "jenkins"},
+				},
+				Resources: []config.StepResources{
+					{Name: "commonPipelineEnvironment"},
+				},
+				Parameters: []config.StepParameters{
+					{
+						Name: "authToken",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "fortifyCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:    "fortifyVaultSecretName",
+								Type:    "vaultSecret",
+								Default: "fortify",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: true,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_authToken"),
+					},
+					{
+						Name:        "serverUrl",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   true,
+						Aliases:     []config.Alias{{Name: "fortifyServerUrl"}},
+						Default:     os.Getenv("PIPER_serverUrl"),
+					},
+					{
+						Name:        "apiEndpoint",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{{Name: "fortifyApiEndpoint"}},
+						Default:     `/api/v1`,
+					},
+					{
+						Name:        "projectName",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   true,
+						Aliases:     []config.Alias{{Name: "fortifyProjectName"}},
+						Default:     os.Getenv("PIPER_projectName"),
+					},
+					{
+						Name: "version",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "artifactVersion",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{{Name: "fortifyProjectVersion"}},
+						Default:   os.Getenv("PIPER_version"),
+					},
+					{
+						Name:        "customScanVersion",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_customScanVersion"),
+					},
+					{
+						Name:        "versioningModel",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "GENERAL", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `major`,
+					},
+					{
+						Name:        "pullRequestName",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_pullRequestName"),
+					},
+					{
+						Name:        "pullRequestVersionPattern",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `^PR-\d+$`,
+					},
+					{
+						Name:        "syncDirection",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `both`,
+					},
+					{
+						Name:        "conflictResolution",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `skip`,
+					},
+					{
+						Name:        "syncTags",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     true,
+					},
+					{
+						Name:        "syncSuppressions",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     true,
+					},
+					{
+						Name:        "syncComments",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     true,
+					},
+					{
+						Name:        "failOnConflicts",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name:        "stalePullRequestVersionDays",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     0,
+					},
+				},
+			},
+			Outputs: config.StepOutputs{
+				Resources: []config.StepResources{
+					{
+						Name: "reports",
+						Type: "reports",
+						Parameters: []map[string]interface{}{
+							{"filePattern": "**/fortifyAuditSync.json", "type": "fortify"},
+						},
+					},
+				},
+			},
+		},
+	}
+	return theMetaData
+}
@@ -0,0 +1,17 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFortifySyncAuditsCommand(t *testing.T) {
+	t.Parallel()
+
+	testCmd := FortifySyncAuditsCommand()
+
+	// only high level testing performed - details are tested in step generation procedure
+	assert.Equal(t, "fortifySyncAudits", testCmd.Use, "command name incorrect")
+
+}
@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/fortify"
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/piper-validation/fortify-client-go/models"
+	"github.com/stretchr/testify/assert"
+)
+
+type fortifySyncAuditsSystemMock struct {
+	fortify.System
+	versions        []*models.ProjectVersion
+	issues          map[int64][]*models.ProjectVersionIssue
+	auditStates     map[int64]*fortify.IssueAuditState
+	audited         map[int64]int
+	deletedVersions []int64
+	calls           []string
+}
+
+func (m *fortifySyncAuditsSystemMock) GetProjectByName(name string, autoCreate bool, projectVersion string) (*models.Project, error) {
+	if name != "project" {
+		return nil, errors.New("project not found")
+	}
+	return &models.Project{ID: 4711, Name: &name}, nil
+}
+
+func (m *fortifySyncAuditsSystemMock) GetProjectVersionDetailsByProjectIDAndVersionName(id int64, name string, autoCreate bool, projectName string) (*models.ProjectVersion, error) {
+	for _, version := range m.versions {
+		if *version.Name == name {
+			return version, nil
+		}
+	}
+	return nil, errors.New("project version not found")
+}
+
+func (m *fortifySyncAuditsSystemMock) GetProjectVersionsOfProject(projectID int64) ([]*models.ProjectVersion, error) {
+	return m.versions, nil
+}
+
+func (m *fortifySyncAuditsSystemMock) GetAllIssueDetails(projectVersionID int64) ([]*models.ProjectVersionIssue, error) {
+	return m.issues[projectVersionID], nil
+}
+
+func (m *fortifySyncAuditsSystemMock) GetIssueAuditState(issueID int64) (*fortify.IssueAuditState, error) {
+	return m.auditStates[issueID], nil
+}
+
+func (m *fortifySyncAuditsSystemMock) AuditIssue(projectVersionID int64, audit fortify.IssueAuditRequest) error {
+	m.audited[projectVersionID]++
+	m.calls = append(m.calls, fmt.Sprintf("audit %v", projectVersionID))
+	return nil
+}
+
+func (m *fortifySyncAuditsSystemMock) DeleteProjectVersion(id int64) error {
+	m.deletedVersions = append(m.deletedVersions, id)
+	m.calls = append(m.calls, fmt.Sprintf("delete %v", id))
+	return nil
+}
+
+func newFortifySyncAuditsSystemMock(now time.Time) *fortifySyncAuditsSystemMock {
+	version := func(id int64, name string, daysAgo int) *models.ProjectVersion {
+		uploaded := models.Iso8601MilliDateTime(now.AddDate(0, 0, -daysAgo))
+		return &models.ProjectVersion{ID: id, Name: &name, CreationDate: &uploaded, CurrentState: &models.ProjectVersionState{LastFprUploadDate: &uploaded}}
+	}
+	issue := func(id int64, instanceID string) *models.ProjectVersionIssue {
+		return &models.ProjectVersionIssue{ID: id, IssueInstanceID: &instanceID, Audited: true}
+	}
+	return &fortifySyncAuditsSystemMock{
+		versions: []*models.ProjectVersion{version(1, "1", 1), version(2, "PR-1", 2), version(3, "PR-2", 60), version(4, "feature", 60)},
+		issues: map[int64][]*models.ProjectVersionIssue{
+			1: {issue(11, "AAA")},
+			2: {issue(21, "AAA")},
+			3: {issue(31, "AAA")},
+		},
+		auditStates: map[int64]*fortify.IssueAuditState{
+			11: {ID: 11, CustomTagValues: []fortify.CustomTagValue{{CustomTagGUID: "analysis", ValueGUID: "not-an-issue"}}},
+			21: {ID: 21},
+			31: {ID: 31, CustomTagValues: []fortify.CustomTagValue{{CustomTagGUID: "analysis", ValueGUID: "exploitable"}}},
+		},
+		audited: map[int64]int{},
+	}
+}
+
+func defaultFortifySyncAuditsOptions() fortifySyncAuditsOptions {
+	return fortifySyncAuditsOptions{
+		ProjectName:               "project",
+		Version:                   "1.2.3",
+		VersioningModel:           "major",
+		PullRequestVersionPattern: `^PR-\d+$`,
+		SyncDirection:             fortify.SyncDirectionBoth,
+		ConflictResolution:        fortify.ConflictResolutionSkip,
+		SyncTags:                  true,
+		SyncSuppressions:          true,
+	}
+}
+
+func TestRunFortifySyncAudits(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2023, 3, 20, 0, 0, 0, 0, time.UTC)
+
+	t.Run("synchronizes all pull-request versions and keeps stale ones with conflicts", func(t *testing.T) {
+		t.Parallel()
+		config := defaultFortifySyncAuditsOptions()
+		config.StalePullRequestVersionDays = 30
+		sys := newFortifySyncAuditsSystemMock(now)
+		utils := &mock.FilesMock{}
+
+		reports, err := runFortifySyncAudits(config, sys, utils, now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(reports))
+		assert.Equal(t, 1, sys.audited[2])
+		assert.Equal(t, 0, sys.audited[1])
+		assert.Empty(t, sys.deletedVersions)
+		content, err := utils.FileRead("fortify/fortifyAuditSync.json")
+		assert.NoError(t, err)
+		assert.Contains(t, string(content), `"pullRequestVersion": "PR-1"`)
+		assert.Contains(t, string(content), `"pullRequestVersion": "PR-2"`)
+		assert.Contains(t, string(content), `"conflictsSkipped": 1`)
+		assert.Contains(t, string(content), `"deletedVersions": []`)
+	})
+
+	t.Run("synchronizes audits of stale versions into master before deleting them", func(t *testing.T) {
+		t.Parallel()
+		config := defaultFortifySyncAuditsOptions()
+		config.StalePullRequestVersionDays = 30
+		config.ConflictResolution = fortify.ConflictResolutionPullRequest
+		sys := newFortifySyncAuditsSystemMock(now)
+		utils := &mock.FilesMock{}
+
+		_, err := runFortifySyncAudits(config, sys, utils, now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"audit 2", "audit 1", "delete 3"}, sys.calls)
+		content, err := utils.FileRead("fortify/fortifyAuditSync.json")
+		assert.NoError(t, err)
+		assert.Contains(t, string(content), `"deletedVersions": [
+    "PR-2"
+  ]`)
+	})
+
+	t.Run("never deletes the master version", func(t *testing.T) {
+		t.Parallel()
+		config := defaultFortifySyncAuditsOptions()
+		config.PullRequestVersionPattern = `.*`
+		config.StalePullRequestVersionDays = 30
+		config.ConflictResolution = fortify.ConflictResolutionPullRequest
+		sys := newFortifySyncAuditsSystemMock(now)
+		uploaded := models.Iso8601MilliDateTime(now.AddDate(0, 0, -60))
+		sys.versions[0].CurrentState.LastFprUploadDate = &uploaded
+		utils := &mock.FilesMock{}
+
+		_, err := runFortifySyncAudits(config, sys, utils, now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []int64{3, 4}, sys.deletedVersions)
+	})
+
+	t.Run("synchronizes a single pull-request version", func(t *testing.T) {
+		t.Parallel()
+		config := defaultFortifySyncAuditsOptions()
+		config.PullRequestName = "PR-2"
+		config.ConflictResolution = fortify.ConflictResolutionPullRequest
+		sys := newFortifySyncAuditsSystemMock(now)
+		utils := &mock.FilesMock{}
+
+		_, err := runFortifySyncAudits(config, sys, utils, now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, sys.audited[1])
+		assert.Equal(t, 0, sys.audited[2])
+		assert.Empty(t, sys.deletedVersions)
+	})
+
+	t.Run("fails on conflicts", func(t *testing.T) {
+		t.Parallel()
+		config := defaultFortifySyncAuditsOptions()
+		config.FailOnConflicts = true
+		sys := newFortifySyncAuditsSystemMock(now)
+		utils := &mock.FilesMock{}
+
+		reports, err := runFortifySyncAudits(config, sys, utils, now)
+
+		assert.EqualError(t, err, "1 conflicting audits could not be synchronized, please resolve them manually")
+		assert.Equal(t, 1, len(reports))
+	})
+
+	t.Run("unknown project", func(t *testing.T) {
+		t.Parallel()
+		config := defaultFortifySyncAuditsOptions()
+		config.ProjectName = "unknown"
+
+		_, err := runFortifySyncAudits(config, newFortifySyncAuditsSystemMock(now), &mock.FilesMock{}, now)
+
+		assert.EqualError(t, err, "Failed to load project unknown: project not found")
+	})
+
+	t.Run("unknown pull-request version", func(t *testing.T) {
+		t.Parallel()
+		config := defaultFortifySyncAuditsOptions()
+		config.PullRequestName = "PR-3"
+
+		_, err := runFortifySyncAudits(config, newFortifySyncAuditsSystemMock(now), &mock.FilesMock{}, now)
+
+		assert.EqualError(t, err, "Failed to load project version PR-3: project version not found")
+	})
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		t.Parallel()
+		config := defaultFortifySyncAuditsOptions()
+		config.PullRequestVersionPattern = "(["
+
+		_, err := runFortifySyncAudits(config, newFortifySyncAuditsSystemMock(now), &mock.FilesMock{}, now)
+
+		assert.Contains(t, fmt.Sprint(err), "invalid pullRequestVersionPattern")
+	})
+}
@@ -58,6 +58,10 @@ func runGithubPublishRelease(ctx context.Context, config *githubPublishReleaseOp
 	publishedAt = lastRelease.GetPublishedAt()
 	log.Entry().Debugf("Previous GitHub release published: '%v'", publishedAt)
 
+	if config.UploadArtifacts {
+		addReleaseArtifacts(config)
+	}
+
 	// updating assets only supported on latest release
 	if len(config.AssetPath) > 0 && config.Version == "latest" {
 		return uploadReleaseAsset(ctx, lastRelease.GetID(), config, ghRepoClient)
@@ -71,6 +75,10 @@ func runGithubPublishRelease(ctx context.Context, config *githubPublishReleaseOp
 		releaseBody += config.ReleaseBodyHeader + "\n"
 	}
 
+	if len(config.ReleaseNotes) > 0 {
+		releaseBody += config.ReleaseNotes + "\n"
+	}
+
 	if config.AddClosedIssues {
 		releaseBody += getClosedIssuesText(ctx, publishedAt, config, ghIssueClient)
 	}
@@ -162,6 +170,26 @@ func getReleaseDeltaText(config *githubPublishReleaseOptions, lastRelease *githu
 	return releaseDeltaText
 }
 
+// addReleaseArtifacts adds the files of the build artifacts to the list of release assets. Artifacts which do not
+// provide the path of their file, e.g. artifacts which were published to a repository, are skipped.
+func addReleaseArtifacts(config *githubPublishReleaseOptions) {
+	assets := []string{}
+	if len(config.AssetPath) > 0 {
+		assets = append(assets, config.AssetPath)
+		config.AssetPath = ""
+	}
+	assets = append(assets, config.AssetPathList...)
+	for _, artifact := range config.Artifacts {
+		path, _ := artifact["path"].(string)
+		if len(path) == 0 {
+			log.Entry().Debugf("Skipping artifact '%v' without file", artifact["name"])
+			continue
+		}
+		assets = append(assets, path)
+	}
+	config.AssetPathList = assets
+}
+
 func uploadReleaseAssetList(ctx context.Context, releaseID int64, config *githubPublishReleaseOptions, ghRepoClient GithubRepoClient) error {
 	for _, asset := range config.AssetPathList {
 		config.AssetPath = asset
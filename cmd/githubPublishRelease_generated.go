@@ -16,23 +16,26 @@ import (
 )
 
 type githubPublishReleaseOptions struct {
-	AddClosedIssues       bool     `json:"addClosedIssues,omitempty"`
-	AddDeltaToLastRelease bool     `json:"addDeltaToLastRelease,omitempty"`
-	APIURL                string   `json:"apiUrl,omitempty"`
-	AssetPath             string   `json:"assetPath,omitempty"`
-	AssetPathList         []string `json:"assetPathList,omitempty"`
-	Commitish             string   `json:"commitish,omitempty"`
-	ExcludeLabels         []string `json:"excludeLabels,omitempty"`
-	Labels                []string `json:"labels,omitempty"`
-	Owner                 string   `json:"owner,omitempty"`
-	PreRelease            bool     `json:"preRelease,omitempty"`
-	ReleaseBodyHeader     string   `json:"releaseBodyHeader,omitempty"`
-	Repository            string   `json:"repository,omitempty"`
-	ServerURL             string   `json:"serverUrl,omitempty"`
-	TagPrefix             string   `json:"tagPrefix,omitempty"`
-	Token                 string   `json:"token,omitempty"`
-	UploadURL             string   `json:"uploadUrl,omitempty"`
-	Version               string   `json:"version,omitempty"`
+	AddClosedIssues       bool                     `json:"addClosedIssues,omitempty"`
+	AddDeltaToLastRelease bool                     `json:"addDeltaToLastRelease,omitempty"`
+	APIURL                string                   `json:"apiUrl,omitempty"`
+	AssetPath             string                   `json:"assetPath,omitempty"`
+	AssetPathList         []string                 `json:"assetPathList,omitempty"`
+	UploadArtifacts       bool                     `json:"uploadArtifacts,omitempty"`
+	Artifacts             []map[string]interface{} `json:"artifacts,omitempty"`
+	Commitish             string                   `json:"commitish,omitempty"`
+	ExcludeLabels         []string                 `json:"excludeLabels,omitempty"`
+	Labels                []string                 `json:"labels,omitempty"`
+	Owner                 string                   `json:"owner,omitempty"`
+	PreRelease            bool                     `json:"preRelease,omitempty"`
+	ReleaseBodyHeader     string                   `json:"releaseBodyHeader,omitempty"`
+	ReleaseNotes          string                   `json:"releaseNotes,omitempty"`
+	Repository            string                   `json:"repository,omitempty"`
+	ServerURL             string                   `json:"serverUrl,omitempty"`
+	TagPrefix             string                   `json:"tagPrefix,omitempty"`
+	Token                 string                   `json:"token,omitempty"`
+	UploadURL             string                   `json:"uploadUrl,omitempty"`
+	Version               string                   `json:"version,omitempty"`
 }
 
 // GithubPublishReleaseCommand Publish a release in GitHub
@@ -143,12 +146,15 @@ func addGithubPublishReleaseFlags(cmd *cobra.Command, stepConfig *githubPublishR
 	cmd.Flags().StringVar(&stepConfig.APIURL, "apiUrl", `https://api.github.com`, "Set the GitHub API url.")
 	cmd.Flags().StringVar(&stepConfig.AssetPath, "assetPath", os.Getenv("PIPER_assetPath"), "Path to a release asset which should be uploaded to the list of release assets.")
 	cmd.Flags().StringSliceVar(&stepConfig.AssetPathList, "assetPathList", []string{}, "List of paths to a release asset which should be uploaded to the list of release assets.")
+	cmd.Flags().BoolVar(&stepConfig.UploadArtifacts, "uploadArtifacts", false, "If set to `true`, the artifacts reported by the build via the common pipeline environment, e.g. the archives and checksums created by [golangBuild](golangBuild.md) with `releasePackaging`, are uploaded as release assets.")
+
 	cmd.Flags().StringVar(&stepConfig.Commitish, "commitish", `master`, "Target git commitish for the release")
 	cmd.Flags().StringSliceVar(&stepConfig.ExcludeLabels, "excludeLabels", []string{}, "Allows to exclude issues with dedicated list of labels.")
 	cmd.Flags().StringSliceVar(&stepConfig.Labels, "labels", []string{}, "Labels to include in issue search.")
 	cmd.Flags().StringVar(&stepConfig.Owner, "owner", os.Getenv("PIPER_owner"), "Name of the GitHub organization.")
 	cmd.Flags().BoolVar(&stepConfig.PreRelease, "preRelease", false, "If set to `true` the release will be marked as Pre-release.")
 	cmd.Flags().StringVar(&stepConfig.ReleaseBodyHeader, "releaseBodyHeader", os.Getenv("PIPER_releaseBodyHeader"), "Content which will appear for the release.")
+	cmd.Flags().StringVar(&stepConfig.ReleaseNotes, "releaseNotes", os.Getenv("PIPER_releaseNotes"), "Release notes which will appear for the release after the `releaseBodyHeader`, e.g. the changes of the version provided by step [artifactPrepareVersion](artifactPrepareVersion.md) with `versioningType: semantic`.")
 	cmd.Flags().StringVar(&stepConfig.Repository, "repository", os.Getenv("PIPER_repository"), "Name of the GitHub repository.")
 	cmd.Flags().StringVar(&stepConfig.ServerURL, "serverUrl", `https://github.com`, "GitHub server url for end-user access.")
 	cmd.Flags().StringVar(&stepConfig.TagPrefix, "tagPrefix", ``, "Defines a prefix to be added to the tag.")
@@ -224,6 +230,28 @@ func githubPublishReleaseMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     []string{},
 					},
+					{
+						Name:        "uploadArtifacts",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name: "artifacts",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "custom/artifacts",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "[]map[string]interface{}",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+					},
 					{
 						Name: "commitish",
 						ResourceRef: []config.ResourceReference{
@@ -288,6 +316,20 @@ func githubPublishReleaseMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     os.Getenv("PIPER_releaseBodyHeader"),
 					},
+					{
+						Name: "releaseNotes",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "custom/releaseNotes",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_releaseNotes"),
+					},
 					{
 						Name: "repository",
 						ResourceRef: []config.ResourceReference{
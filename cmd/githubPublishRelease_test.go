@@ -149,6 +149,30 @@ func TestRunGithubPublishRelease(t *testing.T) {
 		assert.Equal(t, "v1.0", ghRepoClient.release.GetTagName())
 	})
 
+	t.Run("Success - first release with release notes", func(t *testing.T) {
+		ghIssueClient := ghICMock{}
+		ghRepoClient := ghRCMock{
+			latestStatusCode: 404,
+			latestErr:        fmt.Errorf("not found"),
+		}
+
+		myGithubPublishReleaseOptions := githubPublishReleaseOptions{
+			Commitish:         "master",
+			Owner:             "TEST",
+			Repository:        "test",
+			ServerURL:         "https://github.com",
+			ReleaseBodyHeader: "Header",
+			ReleaseNotes:      "### Features\n\n* dark mode (0123456)\n",
+			Version:           "1.1.0",
+			TagPrefix:         "v",
+		}
+		err := runGithubPublishRelease(ctx, &myGithubPublishReleaseOptions, &ghRepoClient, &ghIssueClient)
+		assert.NoError(t, err, "Error occurred but none expected.")
+
+		assert.Equal(t, "Header\n### Features\n\n* dark mode (0123456)\n\n", ghRepoClient.release.GetBody())
+		assert.Equal(t, "v1.1.0", ghRepoClient.release.GetTagName())
+	})
+
 	t.Run("Success - subsequent releases & with body", func(t *testing.T) {
 		lastTag := "1.0"
 		lastPublishedAt := github.Timestamp{Time: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)}
@@ -259,6 +283,88 @@ func TestRunGithubPublishRelease(t *testing.T) {
 
 		assert.Equal(t, "Creation of release '1.0' failed: Create release error", fmt.Sprint(err))
 	})
+
+	t.Run("Success - upload artifacts", func(t *testing.T) {
+		owner := "OWNER"
+		repository := "REPOSITORY"
+		var releaseID int64 = 1
+		assetURL := mock.Anything
+		tagName := "1.0"
+		myGithubPublishReleaseOptions := githubPublishReleaseOptions{
+			Owner:           owner,
+			Repository:      repository,
+			Version:         tagName,
+			UploadArtifacts: true,
+			Artifacts: []map[string]interface{}{
+				{"name": "tool_1.0_linux_amd64.tar.gz", "path": filepath.Join("testdata", "TestUploadReleaseAssetList", "Success_-_multiple_asset_1_test.txt")},
+				{"name": "com.example:lib:1.0"},
+				{"name": "SHA256SUMS", "path": filepath.Join("testdata", "TestUploadReleaseAssetList", "Success_-_multiple_asset_2_test.txt")},
+			},
+		}
+		ghClient := &mocks.GithubRepoClient{}
+		ghClient.Test(t)
+		ghClient.
+			On("GetLatestRelease", ctx, owner, repository).Return(
+			&github.RepositoryRelease{},
+			&github.Response{Response: &http.Response{StatusCode: 200}},
+			nil,
+		).
+			On("CreateRelease", ctx, owner, repository, mock.AnythingOfType("*github.RepositoryRelease")).Return(
+			&github.RepositoryRelease{ID: &releaseID, TagName: &tagName},
+			&github.Response{Response: &http.Response{StatusCode: 201}},
+			nil,
+		).
+			On("ListReleaseAssets", ctx, owner, repository, releaseID, mock.AnythingOfType("*github.ListOptions")).Return(
+			[]*github.ReleaseAsset{},
+			nil,
+			nil,
+		).
+			On("UploadReleaseAsset", ctx, owner, repository, releaseID, mock.MatchedBy(func(opts *github.UploadOptions) bool {
+				return opts.Name == "Success_-_multiple_asset_1_test.txt"
+			}), mock.AnythingOfType("*os.File")).Return(
+			&github.ReleaseAsset{URL: &assetURL},
+			&github.Response{Response: &http.Response{StatusCode: 201}},
+			nil,
+		).Once().
+			On("UploadReleaseAsset", ctx, owner, repository, releaseID, mock.MatchedBy(func(opts *github.UploadOptions) bool {
+				return opts.Name == "Success_-_multiple_asset_2_test.txt"
+			}), mock.AnythingOfType("*os.File")).Return(
+			&github.ReleaseAsset{URL: &assetURL},
+			&github.Response{Response: &http.Response{StatusCode: 201}},
+			nil,
+		).Once()
+
+		err := runGithubPublishRelease(ctx, &myGithubPublishReleaseOptions, ghClient, &ghICMock{})
+
+		assert.NoError(t, err)
+		ghClient.AssertExpectations(t)
+	})
+}
+
+func TestAddReleaseArtifacts(t *testing.T) {
+	t.Run("artifacts and asset paths", func(t *testing.T) {
+		config := githubPublishReleaseOptions{
+			AssetPath:     "notes.txt",
+			AssetPathList: []string{"install.sh"},
+			Artifacts: []map[string]interface{}{
+				{"name": "tool.tar.gz", "path": "dist/tool.tar.gz"},
+				{"name": "tool.jar"},
+			},
+		}
+
+		addReleaseArtifacts(&config)
+
+		assert.Empty(t, config.AssetPath)
+		assert.Equal(t, []string{"notes.txt", "install.sh", "dist/tool.tar.gz"}, config.AssetPathList)
+	})
+
+	t.Run("no artifacts", func(t *testing.T) {
+		config := githubPublishReleaseOptions{}
+
+		addReleaseArtifacts(&config)
+
+		assert.Empty(t, config.AssetPathList)
+	})
 }
 
 func TestGetClosedIssuesText(t *testing.T) {
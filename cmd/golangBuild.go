@@ -8,16 +8,21 @@ import (
 	"path"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/SAP/jenkins-library/pkg/buildcache"
 	"github.com/SAP/jenkins-library/pkg/buildsettings"
 	"github.com/SAP/jenkins-library/pkg/certutils"
 	"github.com/SAP/jenkins-library/pkg/command"
+	"github.com/SAP/jenkins-library/pkg/git"
 	"github.com/SAP/jenkins-library/pkg/goget"
 	piperhttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/piperenv"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/release"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 
 	"github.com/SAP/jenkins-library/pkg/multiarch"
@@ -37,6 +42,7 @@ const (
 	golangTestsumPackage        = "gotest.tools/gotestsum@latest"
 	golangCycloneDXPackage      = "github.com/CycloneDX/cyclonedx-gomod/cmd/cyclonedx-gomod@latest"
 	sbomFilename                = "bom-golang.xml"
+	golangReleaseDir            = "dist"
 )
 
 type golangBuildUtils interface {
@@ -50,6 +56,7 @@ type golangBuildUtils interface {
 	GetExitCode() int
 	DownloadFile(url, filename string, header http.Header, cookies []*http.Cookie) error
 	Untar(src string, dest string, stripComponentLevel int) error
+	ChangedFiles() (git.ChangeRange, []string, error)
 
 	// Add more methods here, or embed additional interfaces, or remove/replace as required.
 	// The golangBuildUtils interface should be descriptive of your runtime dependencies,
@@ -83,6 +90,10 @@ func (g *golangBuildUtilsBundle) Untar(src string, dest string, stripComponentLe
 	return piperutils.Untar(src, dest, stripComponentLevel)
 }
 
+func (g *golangBuildUtilsBundle) ChangedFiles() (git.ChangeRange, []string, error) {
+	return git.ChangedFilesOfCurrentRun(".")
+}
+
 func newGolangBuildUtils(config golangBuildOptions) golangBuildUtils {
 	httpClientOptions := piperhttp.ClientOptions{}
 
@@ -118,7 +129,15 @@ func golangBuild(config golangBuildOptions, telemetryData *telemetry.CustomData,
 
 	// Error situations will be bubbled up until they reach the line below which will then stop execution
 	// through the log.Entry().Fatal() call leading to an os.Exit(1) in the end.
-	err := runGolangBuild(&config, telemetryData, utils, commonPipelineEnvironment)
+	cache, closeCache := newBuildCache(config.BuildCache, config.BuildCacheLocation, config.BuildCacheMaxSize, utils)
+	defer closeCache()
+	cacheDirectories, err := buildcache.GoDirectories(utils)
+	if err != nil {
+		log.Entry().WithError(err).Warn("failed to determine Go cache directories")
+	}
+	err = runWithBuildCache(cache, cacheDirectories, func() error {
+		return runGolangBuild(&config, telemetryData, utils, commonPipelineEnvironment)
+	})
 	if err != nil {
 		log.Entry().WithError(err).Fatal("execution of golang build failed")
 	}
@@ -150,16 +169,24 @@ func runGolangBuild(config *golangBuildOptions, telemetryData *telemetry.CustomD
 	failedTests := false
 
 	if config.RunTests {
-		success, err := runGolangTests(config, utils)
+		packages, err := golangTestPackages(config, utils)
 		if err != nil {
 			return err
 		}
-		failedTests = !success
-	}
+		if len(packages) == 0 {
+			log.Entry().Info("No Go package is affected by the changes. Skipping unit tests")
+		} else {
+			success, err := runGolangTests(config, utils, packages)
+			if err != nil {
+				return err
+			}
+			failedTests = !success
 
-	if config.RunTests && config.ReportCoverage {
-		if err := reportGolangTestCoverage(config, utils); err != nil {
-			return err
+			if config.ReportCoverage {
+				if err := reportGolangTestCoverage(config, utils); err != nil {
+					return err
+				}
+			}
 		}
 	}
 
@@ -220,8 +247,18 @@ func runGolangBuild(config *golangBuildOptions, telemetryData *telemetry.CustomD
 		return err
 	}
 
+	var releasePackage *golangRelease
+	sourceDateEpoch := ""
+	if config.ReleasePackaging {
+		releasePackage, err = prepareGolangRelease(config, goModFile, utils)
+		if err != nil {
+			return err
+		}
+		sourceDateEpoch = strconv.FormatInt(releasePackage.modTime.Unix(), 10)
+	}
+
 	for _, platform := range platforms {
-		binaryNames, err := runGolangBuildPerArchitecture(config, goModFile, utils, ldflags, platform)
+		binaryNames, err := runGolangBuildPerArchitecture(config, goModFile, utils, ldflags, platform, sourceDateEpoch)
 
 		if err != nil {
 			return err
@@ -230,6 +267,21 @@ func runGolangBuild(config *golangBuildOptions, telemetryData *telemetry.CustomD
 		if len(binaryNames) > 0 {
 			binaries = append(binaries, binaryNames...)
 		}
+
+		// binaries are packaged right away since the binaries of different platforms may share the same name
+		if releasePackage != nil {
+			if err := packageGolangBinaries(releasePackage, config, utils, platform, binaryNames); err != nil {
+				return err
+			}
+		}
+	}
+
+	if releasePackage != nil {
+		artifacts, err := finishGolangRelease(releasePackage, config, utils)
+		if err != nil {
+			return err
+		}
+		commonPipelineEnvironment.custom.artifacts = artifacts
 	}
 
 	log.Entry().Debugf("creating build settings information...")
@@ -256,24 +308,9 @@ func runGolangBuild(config *golangBuildOptions, telemetryData *telemetry.CustomD
 			return fmt.Errorf("there's no target repository for binary publishing configured")
 		}
 
-		artifactVersion := config.ArtifactVersion
-
-		if len(artifactVersion) == 0 {
-			artifactOpts := versioning.Options{
-				VersioningScheme: "library",
-			}
-
-			artifact, err := versioning.GetArtifact("golang", "", &artifactOpts, utils)
-
-			if err != nil {
-				return err
-			}
-
-			artifactVersion, err = artifact.GetVersion()
-
-			if err != nil {
-				return err
-			}
+		artifactVersion, err := golangArtifactVersion(config, utils)
+		if err != nil {
+			return err
 		}
 
 		if goModFile == nil {
@@ -319,13 +356,27 @@ func runGolangBuild(config *golangBuildOptions, telemetryData *telemetry.CustomD
 				Name: binary,
 			})
 		}
-		commonPipelineEnvironment.custom.artifacts = binaryArtifacts
+		commonPipelineEnvironment.custom.artifacts = append(commonPipelineEnvironment.custom.artifacts, binaryArtifacts...)
 
 	}
 
 	return nil
 }
 
+func golangArtifactVersion(config *golangBuildOptions, utils golangBuildUtils) (string, error) {
+	if len(config.ArtifactVersion) > 0 {
+		return config.ArtifactVersion, nil
+	}
+	artifactOpts := versioning.Options{
+		VersioningScheme: "library",
+	}
+	artifact, err := versioning.GetArtifact("golang", "", &artifactOpts, utils)
+	if err != nil {
+		return "", err
+	}
+	return artifact.GetVersion()
+}
+
 func prepareGolangEnvironment(config *golangBuildOptions, goModFile *modfile.File, utils golangBuildUtils) error {
 	// configure truststore
 	err := certutils.CertificateUpdate(config.CustomTLSCertificateLinks, utils, utils, "/etc/ssl/certs/ca-certificates.crt") // TODO reimplement
@@ -364,9 +415,50 @@ func prepareGolangEnvironment(config *golangBuildOptions, goModFile *modfile.Fil
 	return nil
 }
 
-func runGolangTests(config *golangBuildOptions, utils golangBuildUtils) (bool, error) {
+// golangTestPackages returns the packages to run the unit tests for.
+// An empty list is returned if testChangedPackagesOnly is active and no package is affected by the changes.
+func golangTestPackages(config *golangBuildOptions, utils golangBuildUtils) ([]string, error) {
+	if !config.TestChangedPackagesOnly {
+		return []string{"./..."}, nil
+	}
+	changeRange, files, err := utils.ChangedFiles()
+	if err != nil {
+		log.Entry().WithError(err).Warn("Failed to determine the changes of the pipeline run, all packages will be tested")
+		return []string{"./..."}, nil
+	}
+	modules, err := git.DiscoverGoPackages(utils)
+	if err != nil {
+		return nil, err
+	}
+	analysis := git.AnalyzeChanges(changeRange, files, modules, config.ChangeAnalysisGlobalFiles)
+	reportPath, err := git.WriteChangeAnalysisReport(analysis, "golangBuild", utils)
+	if err != nil {
+		return nil, err
+	}
+	piperutils.PersistReportsAndLinks("golangBuild", "", utils, []piperutils.Path{reportPath}, nil)
+
+	if len(analysis.SkippedModules) == 0 {
+		// all packages are affected, no need to list them explicitly
+		return []string{"./..."}, nil
+	}
+	for _, module := range analysis.SkippedModules {
+		log.Entry().Infof("Skipping tests of Go package '%v' since it is not affected by the changes", module.Name)
+	}
+	packages := []string{}
+	for _, module := range analysis.AffectedModules {
+		if module.Path == "." {
+			packages = append(packages, ".")
+		} else {
+			packages = append(packages, "./"+module.Path)
+		}
+	}
+	return packages, nil
+}
+
+func runGolangTests(config *golangBuildOptions, utils golangBuildUtils, packages []string) (bool, error) {
 	// execute gotestsum in order to have more output options
-	testOptions := []string{"--junitfile", golangUnitTestOutput, "--jsonfile", unitJsonReport, "--", fmt.Sprintf("-coverprofile=%v", coverageFile), "./..."}
+	testOptions := []string{"--junitfile", golangUnitTestOutput, "--jsonfile", unitJsonReport, "--", fmt.Sprintf("-coverprofile=%v", coverageFile)}
+	testOptions = append(testOptions, packages...)
 	testOptions = append(testOptions, config.TestOptions...)
 	if err := utils.RunExecutable("gotestsum", testOptions...); err != nil {
 		exists, fileErr := utils.FileExists(golangUnitTestOutput)
@@ -488,11 +580,14 @@ func prepareLdflags(config *golangBuildOptions, utils golangBuildUtils, envRootP
 	return cpe.ParseTemplate(config.LdflagsTemplate)
 }
 
-func runGolangBuildPerArchitecture(config *golangBuildOptions, goModFile *modfile.File, utils golangBuildUtils, ldflags string, architecture multiarch.Platform) ([]string, error) {
+func runGolangBuildPerArchitecture(config *golangBuildOptions, goModFile *modfile.File, utils golangBuildUtils, ldflags string, architecture multiarch.Platform, sourceDateEpoch string) ([]string, error) {
 	var binaryNames []string
 
 	envVars := os.Environ()
 	envVars = append(envVars, fmt.Sprintf("GOOS=%v", architecture.OS), fmt.Sprintf("GOARCH=%v", architecture.Arch))
+	if len(sourceDateEpoch) > 0 {
+		envVars = append(envVars, fmt.Sprintf("SOURCE_DATE_EPOCH=%v", sourceDateEpoch))
+	}
 
 	if !config.CgoEnabled {
 		envVars = append(envVars, "CGO_ENABLED=0")
@@ -630,3 +725,154 @@ func isMainPackage(utils golangBuildUtils, pkg string) (bool, error) {
 
 	return true, nil
 }
+
+// golangRelease collects the archives of a release packaging
+type golangRelease struct {
+	name    string
+	version string
+	// modTime is the commit time of HEAD, it is used for all archive entries in order to build reproducible archives
+	modTime  time.Time
+	files    []release.File
+	archives []string
+	// platforms contains the platform of each archive
+	platforms []string
+}
+
+func prepareGolangRelease(config *golangBuildOptions, goModFile *modfile.File, utils golangBuildUtils) (*golangRelease, error) {
+	version, err := golangArtifactVersion(config, utils)
+	if err != nil {
+		return nil, err
+	}
+
+	name := ""
+	if len(config.Output) > 0 {
+		name = filepath.Base(strings.TrimRight(config.Output, string(os.PathSeparator)))
+	} else if goModFile != nil && goModFile.Module != nil {
+		name = path.Base(goModFile.Module.Mod.Path)
+	} else {
+		return nil, fmt.Errorf("the release name cannot be determined, please configure output or provide a go.mod file declaring a module path")
+	}
+
+	var commitTime bytes.Buffer
+	utils.Stdout(&commitTime)
+	err = utils.RunExecutable("git", "log", "-1", "--format=%ct")
+	utils.Stdout(log.Writer())
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve the commit time of HEAD: %w", err)
+	}
+	epoch, err := strconv.ParseInt(strings.TrimSpace(commitTime.String()), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the commit time of HEAD '%v': %w", strings.TrimSpace(commitTime.String()), err)
+	}
+
+	if err := utils.MkdirAll(golangReleaseDir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create release directory %v: %w", golangReleaseDir, err)
+	}
+
+	files := []release.File{}
+	for _, pattern := range config.ReleaseFiles {
+		matches, err := utils.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("failed to find release files matching '%v': %w", pattern, err)
+		}
+		for _, match := range matches {
+			files = append(files, release.File{Path: match, Name: filepath.ToSlash(match), Mode: 0644})
+		}
+	}
+
+	log.Entry().Infof("packaging release %v %v, SOURCE_DATE_EPOCH=%v", name, version, epoch)
+	return &golangRelease{name: name, version: version, modTime: time.Unix(epoch, 0), files: files}, nil
+}
+
+// packageGolangBinaries creates the archive of a platform containing the binaries and the release files
+func packageGolangBinaries(releasePackage *golangRelease, config *golangBuildOptions, utils golangBuildUtils, platform multiarch.Platform, binaries []string) error {
+	fileExtension := ""
+	if platform.OS == "windows" {
+		fileExtension = ".exe"
+	}
+	files := append([]release.File{}, releasePackage.files...)
+	for _, binary := range binaries {
+		// without output the go tool appends the file extension to the default binary name itself
+		if !strings.HasSuffix(binary, fileExtension) {
+			binary += fileExtension
+		}
+		// the platform suffix of the binary name is omitted within the archive
+		name := filepath.Base(binary)
+		if len(config.Output) > 0 && len(config.Packages) <= 1 {
+			name = releasePackage.name + fileExtension
+		}
+		files = append(files, release.File{Path: binary, Name: name, Mode: 0755})
+	}
+
+	format := release.ArchiveFormat(platform.OS)
+	archive := filepath.Join(golangReleaseDir, release.ArchiveName(releasePackage.name, releasePackage.version, platform.OS, platform.Arch, format))
+	if err := release.CreateArchive(archive, format, files, releasePackage.modTime, utils); err != nil {
+		return err
+	}
+	log.Entry().Infof("created release archive %v", archive)
+	releasePackage.archives = append(releasePackage.archives, archive)
+	releasePackage.platforms = append(releasePackage.platforms, platform.OS+"/"+platform.Arch)
+	return nil
+}
+
+// finishGolangRelease writes the checksums of all archives, signs them optionally and returns the manifest of the release
+func finishGolangRelease(releasePackage *golangRelease, config *golangBuildOptions, utils golangBuildUtils) (piperenv.Artifacts, error) {
+	content, checksums, err := release.Checksums(releasePackage.archives, utils)
+	if err != nil {
+		return nil, err
+	}
+	checksumsFile := filepath.Join(golangReleaseDir, release.ChecksumsFileName)
+	if err := utils.FileWrite(checksumsFile, content, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write checksums: %w", err)
+	}
+
+	artifacts := piperenv.Artifacts{}
+	for i, archive := range releasePackage.archives {
+		artifacts = append(artifacts, piperenv.Artifact{
+			Name:     filepath.Base(archive),
+			Path:     archive,
+			Type:     "archive",
+			Platform: releasePackage.platforms[i],
+			SHA256:   checksums[archive],
+		})
+	}
+	artifacts = append(artifacts, piperenv.Artifact{Name: release.ChecksumsFileName, Path: checksumsFile, Type: "checksums"})
+
+	if len(config.ReleaseSigningKey) > 0 {
+		signature, err := signGolangRelease(config, utils, checksumsFile)
+		if err != nil {
+			return nil, err
+		}
+		artifacts = append(artifacts, piperenv.Artifact{Name: filepath.Base(signature), Path: signature, Type: "signature"})
+	}
+	return artifacts, nil
+}
+
+// signGolangRelease creates a detached GPG signature of the file, the key is imported into a temporary keyring
+func signGolangRelease(config *golangBuildOptions, utils golangBuildUtils, file string) (string, error) {
+	gnupgHome, err := utils.TempDir("", "gnupg")
+	if err != nil {
+		return "", fmt.Errorf("failed to create GPG home directory: %w", err)
+	}
+	defer utils.RemoveAll(gnupgHome)
+	utils.SetEnv(append(os.Environ(), "GNUPGHOME="+gnupgHome))
+	defer utils.SetEnv(os.Environ())
+
+	if err := utils.RunExecutable("gpg", "--batch", "--import", config.ReleaseSigningKey); err != nil {
+		return "", fmt.Errorf("failed to import signing key: %w", err)
+	}
+
+	signature := file + ".sig"
+	parameters := []string{"--batch", "--yes"}
+	if len(config.ReleaseSigningKeyPassphrase) > 0 {
+		// the passphrase is passed via stdin in order to keep it out of the command line
+		utils.Stdin(strings.NewReader(config.ReleaseSigningKeyPassphrase))
+		defer utils.Stdin(nil)
+		parameters = append(parameters, "--pinentry-mode", "loopback", "--passphrase-fd", "0")
+	}
+	parameters = append(parameters, "--output", signature, "--detach-sign", file)
+	if err := utils.RunExecutable("gpg", parameters...); err != nil {
+		return "", fmt.Errorf("failed to sign '%v': %w", file, err)
+	}
+	return signature, nil
+}
@@ -23,6 +23,9 @@ import (
 
 type golangBuildOptions struct {
 	BuildFlags                   []string `json:"buildFlags,omitempty"`
+	BuildCache                   bool     `json:"buildCache,omitempty"`
+	BuildCacheLocation           string   `json:"buildCacheLocation,omitempty"`
+	BuildCacheMaxSize            int      `json:"buildCacheMaxSize,omitempty"`
 	BuildSettingsInfo            string   `json:"buildSettingsInfo,omitempty"`
 	CgoEnabled                   bool     `json:"cgoEnabled,omitempty"`
 	CoverageFormat               string   `json:"coverageFormat,omitempty" validate:"possible-values=cobertura html"`
@@ -36,9 +39,15 @@ type golangBuildOptions struct {
 	TargetRepositoryPassword     string   `json:"targetRepositoryPassword,omitempty"`
 	TargetRepositoryUser         string   `json:"targetRepositoryUser,omitempty"`
 	TargetRepositoryURL          string   `json:"targetRepositoryURL,omitempty"`
+	ReleasePackaging             bool     `json:"releasePackaging,omitempty"`
+	ReleaseFiles                 []string `json:"releaseFiles,omitempty"`
+	ReleaseSigningKey            string   `json:"releaseSigningKey,omitempty"`
+	ReleaseSigningKeyPassphrase  string   `json:"releaseSigningKeyPassphrase,omitempty"`
 	ReportCoverage               bool     `json:"reportCoverage,omitempty"`
 	RunLint                      bool     `json:"runLint,omitempty"`
 	RunTests                     bool     `json:"runTests,omitempty"`
+	TestChangedPackagesOnly      bool     `json:"testChangedPackagesOnly,omitempty"`
+	ChangeAnalysisGlobalFiles    []string `json:"changeAnalysisGlobalFiles,omitempty"`
 	RunIntegrationTests          bool     `json:"runIntegrationTests,omitempty"`
 	TargetArchitectures          []string `json:"targetArchitectures,omitempty"`
 	TestOptions                  []string `json:"testOptions,omitempty"`
@@ -157,6 +166,8 @@ If the build is successful the resulting artifact can be uploaded to e.g. a bina
 			}
 			log.RegisterSecret(stepConfig.TargetRepositoryPassword)
 			log.RegisterSecret(stepConfig.TargetRepositoryUser)
+			log.RegisterSecret(stepConfig.ReleaseSigningKey)
+			log.RegisterSecret(stepConfig.ReleaseSigningKeyPassphrase)
 			log.RegisterSecret(stepConfig.PrivateModulesGitToken)
 
 			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
@@ -223,6 +234,9 @@ If the build is successful the resulting artifact can be uploaded to e.g. a bina
 
 func addGolangBuildFlags(cmd *cobra.Command, stepConfig *golangBuildOptions) {
 	cmd.Flags().StringSliceVar(&stepConfig.BuildFlags, "buildFlags", []string{}, "Defines list of build flags to be used.")
+	cmd.Flags().BoolVar(&stepConfig.BuildCache, "buildCache", false, "Restore the dependency caches of the build tool before the build and save them afterwards.")
+	cmd.Flags().StringVar(&stepConfig.BuildCacheLocation, "buildCacheLocation", os.Getenv("PIPER_buildCacheLocation"), "Location of the build cache: a local directory, `gs://<bucket>/<folder>` or `s3://<bucket>/<prefix>`.")
+	cmd.Flags().IntVar(&stepConfig.BuildCacheMaxSize, "buildCacheMaxSize", 2048, "Maximum size of a compressed cache directory in MB, larger caches are not saved.")
 	cmd.Flags().StringVar(&stepConfig.BuildSettingsInfo, "buildSettingsInfo", os.Getenv("PIPER_buildSettingsInfo"), "build settings info is typically filled by the step automatically to create information about the build settings that were used during the maven build . This information is typically used for compliance related processes.")
 	cmd.Flags().BoolVar(&stepConfig.CgoEnabled, "cgoEnabled", false, "If active: enables the creation of Go packages that call C code.")
 	cmd.Flags().StringVar(&stepConfig.CoverageFormat, "coverageFormat", `html`, "Defines the format of the coverage repository.")
@@ -236,9 +250,15 @@ func addGolangBuildFlags(cmd *cobra.Command, stepConfig *golangBuildOptions) {
 	cmd.Flags().StringVar(&stepConfig.TargetRepositoryPassword, "targetRepositoryPassword", os.Getenv("PIPER_targetRepositoryPassword"), "Password for the target repository where the compiled binaries shall be uploaded - typically provided by the CI/CD environment.")
 	cmd.Flags().StringVar(&stepConfig.TargetRepositoryUser, "targetRepositoryUser", os.Getenv("PIPER_targetRepositoryUser"), "Username for the target repository where the compiled binaries shall be uploaded - typically provided by the CI/CD environment.")
 	cmd.Flags().StringVar(&stepConfig.TargetRepositoryURL, "targetRepositoryURL", os.Getenv("PIPER_targetRepositoryURL"), "URL of the target repository where the compiled binaries shall be uploaded - typically provided by the CI/CD environment.")
+	cmd.Flags().BoolVar(&stepConfig.ReleasePackaging, "releasePackaging", false, "Packages the binaries of all target architectures for a release.")
+	cmd.Flags().StringSliceVar(&stepConfig.ReleaseFiles, "releaseFiles", []string{`LICENSE*`, `NOTICE*`, `README*`}, "Patterns of the files which are added to the release archives next to the binaries, e.g. license files.")
+	cmd.Flags().StringVar(&stepConfig.ReleaseSigningKey, "releaseSigningKey", os.Getenv("PIPER_releaseSigningKey"), "Path to the ASCII armored private GPG key used for signing the release checksums.")
+	cmd.Flags().StringVar(&stepConfig.ReleaseSigningKeyPassphrase, "releaseSigningKeyPassphrase", os.Getenv("PIPER_releaseSigningKeyPassphrase"), "Passphrase of the GPG key used for signing the release checksums.")
 	cmd.Flags().BoolVar(&stepConfig.ReportCoverage, "reportCoverage", true, "Defines if a coverage report should be created.")
 	cmd.Flags().BoolVar(&stepConfig.RunLint, "runLint", false, "Configures the build to run linters with [golangci-lint](https://golangci-lint.run/).")
 	cmd.Flags().BoolVar(&stepConfig.RunTests, "runTests", true, "Activates execution of tests using [gotestsum](https://github.com/gotestyourself/gotestsum).")
+	cmd.Flags().BoolVar(&stepConfig.TestChangedPackagesOnly, "testChangedPackagesOnly", false, "If enabled, the unit tests are only executed for the Go packages affected by the changes of the pull request (or of the change set of the build). Packages importing changed packages are tested as well. The unit tests are skipped if no package is affected. The analyzed changes as well as the tested and skipped packages are written to `golangBuild_changeAnalysis.json`. If the changes cannot be determined, all packages are tested.")
+	cmd.Flags().StringSliceVar(&stepConfig.ChangeAnalysisGlobalFiles, "changeAnalysisGlobalFiles", []string{`.pipeline/**`}, "For `testChangedPackagesOnly`: Glob patterns of files whose modification requires testing all packages.")
 	cmd.Flags().BoolVar(&stepConfig.RunIntegrationTests, "runIntegrationTests", false, "Activates execution of a second test run using tag `integration`.")
 	cmd.Flags().StringSliceVar(&stepConfig.TargetArchitectures, "targetArchitectures", []string{`linux,amd64`}, "Defines the target architectures for which the build should run using OS and architecture separated by a comma.")
 	cmd.Flags().StringSliceVar(&stepConfig.TestOptions, "testOptions", []string{}, "Options to pass to test as per `go test` documentation (comprises e.g. flags, packages).")
@@ -263,6 +283,8 @@ func golangBuildMetadata() config.StepData {
 			Inputs: config.StepInputs{
 				Secrets: []config.StepSecrets{
 					{Name: "golangPrivateModulesGitTokenCredentialsId", Description: "Jenkins 'Username with password' credentials ID containing username/password for http access to your git repos where your go private modules are stored.", Type: "jenkins"},
+					{Name: "golangReleaseSigningKeyCredentialsId", Description: "Jenkins 'Secret file' credentials ID containing the ASCII armored private GPG key used for signing the release checksums.", Type: "jenkins"},
+					{Name: "golangReleaseSigningKeyPassphraseCredentialsId", Description: "Jenkins 'Secret text' credentials ID containing the passphrase of the GPG key used for signing the release checksums.", Type: "jenkins"},
 				},
 				Parameters: []config.StepParameters{
 					{
@@ -274,6 +296,33 @@ func golangBuildMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     []string{},
 					},
+					{
+						Name:        "buildCache",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name:        "buildCacheLocation",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_buildCacheLocation"),
+					},
+					{
+						Name:        "buildCacheMaxSize",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     2048,
+					},
 					{
 						Name: "buildSettingsInfo",
 						ResourceRef: []config.ResourceReference{
@@ -426,6 +475,64 @@ func golangBuildMetadata() config.StepData {
 						Aliases:   []config.Alias{},
 						Default:   os.Getenv("PIPER_targetRepositoryURL"),
 					},
+					{
+						Name:        "releasePackaging",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name:        "releaseFiles",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{`LICENSE*`, `NOTICE*`, `README*`},
+					},
+					{
+						Name: "releaseSigningKey",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "golangReleaseSigningKeyCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:    "golangReleaseSigningKeyVaultSecretName",
+								Type:    "vaultSecretFile",
+								Default: "golang-release-signing-key",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_releaseSigningKey"),
+					},
+					{
+						Name: "releaseSigningKeyPassphrase",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name: "golangReleaseSigningKeyPassphraseCredentialsId",
+								Type: "secret",
+							},
+
+							{
+								Name:    "golangReleaseSigningKeyPassphraseVaultSecretName",
+								Type:    "vaultSecret",
+								Default: "golang-release-signing-key-passphrase",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_releaseSigningKeyPassphrase"),
+					},
 					{
 						Name:        "reportCoverage",
 						ResourceRef: []config.ResourceReference{},
@@ -453,6 +560,24 @@ func golangBuildMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     true,
 					},
+					{
+						Name:        "testChangedPackagesOnly",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"STEPS", "STAGES", "PARAMETERS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name:        "changeAnalysisGlobalFiles",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"STEPS", "STAGES", "PARAMETERS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{`.pipeline/**`},
+					},
 					{
 						Name:        "runIntegrationTests",
 						ResourceRef: []config.ResourceReference{},
@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/SAP/jenkins-library/pkg/git"
 	piperhttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/mock"
 	"github.com/SAP/jenkins-library/pkg/multiarch"
@@ -24,10 +25,12 @@ type golangBuildMockUtils struct {
 	*mock.ExecMockRunner
 	*mock.FilesMock
 
-	returnFileUploadStatus  int   // expected to be set upfront
-	returnFileUploadError   error // expected to be set upfront
-	returnFileDownloadError error // expected to be set upfront
-	returnFileUntarError    error // expected to be set upfront
+	returnFileUploadStatus  int      // expected to be set upfront
+	returnFileUploadError   error    // expected to be set upfront
+	returnFileDownloadError error    // expected to be set upfront
+	returnFileUntarError    error    // expected to be set upfront
+	changedFiles            []string // expected to be set upfront
+	changedFilesError       error    // expected to be set upfront
 
 	clientOptions  []piperhttp.ClientOptions // set by mock
 	fileUploads    map[string]string         // set by mock
@@ -86,6 +89,10 @@ func (g *golangBuildMockUtils) Untar(src string, dest string, stripComponentLeve
 	return nil
 }
 
+func (g *golangBuildMockUtils) ChangedFiles() (git.ChangeRange, []string, error) {
+	return git.ChangeRange{Base: "main", Head: "HEAD"}, g.changedFiles, g.changedFilesError
+}
+
 func newGolangBuildTestsUtils() *golangBuildMockUtils {
 	utils := golangBuildMockUtils{
 		ExecMockRunner: &mock.ExecMockRunner{},
@@ -144,6 +151,48 @@ go 1.17`
 		assert.Equal(t, []string{"build", "-trimpath", "-ldflags", "test", "package/foo"}, utils.ExecMockRunner.Calls[2].Params)
 	})
 
+	t.Run("success - tests of changed packages", func(t *testing.T) {
+		config := golangBuildOptions{
+			RunTests:                true,
+			ReportCoverage:          true,
+			TestChangedPackagesOnly: true,
+			Packages:                []string{"package/foo"},
+			TargetArchitectures:     []string{"linux,amd64"},
+		}
+		utils := newGolangBuildTestsUtils()
+		utils.FilesMock.AddFile("go.mod", []byte(modTestFile))
+		utils.FilesMock.AddFile("foo/foo.go", []byte("package foo\n"))
+		utils.FilesMock.AddFile("bar/bar.go", []byte("package bar\n"))
+		utils.changedFiles = []string{"foo/foo.go"}
+		telemetryData := telemetry.CustomData{}
+
+		err := runGolangBuild(&config, &telemetryData, utils, &cpe)
+		assert.NoError(t, err)
+		assert.Equal(t, "gotestsum", utils.ExecMockRunner.Calls[1].Exec)
+		assert.Equal(t, []string{"--junitfile", "TEST-go.xml", "--jsonfile", "unit-report.out", "--", fmt.Sprintf("-coverprofile=%v", coverageFile), "./foo"}, utils.ExecMockRunner.Calls[1].Params)
+	})
+
+	t.Run("success - no changed packages", func(t *testing.T) {
+		config := golangBuildOptions{
+			RunTests:                true,
+			ReportCoverage:          true,
+			TestChangedPackagesOnly: true,
+			Packages:                []string{"package/foo"},
+			TargetArchitectures:     []string{"linux,amd64"},
+		}
+		utils := newGolangBuildTestsUtils()
+		utils.FilesMock.AddFile("go.mod", []byte(modTestFile))
+		utils.FilesMock.AddFile("foo/foo.go", []byte("package foo\n"))
+		utils.changedFiles = []string{"docs/index.md"}
+		telemetryData := telemetry.CustomData{}
+
+		err := runGolangBuild(&config, &telemetryData, utils, &cpe)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"install", "gotest.tools/gotestsum@latest"}, utils.ExecMockRunner.Calls[0].Params)
+		assert.Equal(t, "go", utils.ExecMockRunner.Calls[1].Exec)
+		assert.Equal(t, []string{"build", "-trimpath", "package/foo"}, utils.ExecMockRunner.Calls[1].Params)
+	})
+
 	t.Run("success - test flags", func(t *testing.T) {
 		config := golangBuildOptions{
 			RunTests:            true,
@@ -524,7 +573,7 @@ func TestRunGolangTests(t *testing.T) {
 		utils.AddFile("TEST-go.xml", []byte("some content"))
 		utils.AddFile(coverageFile, []byte("some content"))
 
-		success, err := runGolangTests(&config, utils)
+		success, err := runGolangTests(&config, utils, []string{"./..."})
 		assert.NoError(t, err)
 		assert.True(t, success)
 		assert.Equal(t, "gotestsum", utils.ExecMockRunner.Calls[0].Exec)
@@ -539,7 +588,7 @@ func TestRunGolangTests(t *testing.T) {
 		utils.AddFile(coverageFile, []byte("some content"))
 		utils.ExecMockRunner.ShouldFailOnCommand = map[string]error{"gotestsum": fmt.Errorf("execution error")}
 
-		success, err := runGolangTests(&config, utils)
+		success, err := runGolangTests(&config, utils, []string{"./..."})
 		assert.NoError(t, err)
 		assert.False(t, success)
 	})
@@ -550,7 +599,7 @@ func TestRunGolangTests(t *testing.T) {
 		utils := newGolangBuildTestsUtils()
 		utils.ExecMockRunner.ShouldFailOnCommand = map[string]error{"gotestsum": fmt.Errorf("execution error")}
 
-		_, err := runGolangTests(&config, utils)
+		_, err := runGolangTests(&config, utils, []string{"./..."})
 		assert.EqualError(t, err, "running tests failed - junit result missing: execution error")
 	})
 
@@ -561,11 +610,82 @@ func TestRunGolangTests(t *testing.T) {
 		utils.ExecMockRunner.ShouldFailOnCommand = map[string]error{"gotestsum": fmt.Errorf("execution error")}
 		utils.AddFile("TEST-go.xml", []byte("some content"))
 
-		_, err := runGolangTests(&config, utils)
+		_, err := runGolangTests(&config, utils, []string{"./..."})
 		assert.EqualError(t, err, "running tests failed - coverage output missing: execution error")
 	})
 }
 
+func TestGolangTestPackages(t *testing.T) {
+	t.Parallel()
+
+	newUtils := func() *golangBuildMockUtils {
+		utils := newGolangBuildTestsUtils()
+		utils.AddFile("go.mod", []byte("module example.com/test\n\ngo 1.18\n"))
+		utils.AddFile("cmd/app/main.go", []byte("package main\n\nimport \"example.com/test/pkg/api\"\n"))
+		utils.AddFile("pkg/api/api.go", []byte("package api\n\nimport \"example.com/test/pkg/util\"\n"))
+		utils.AddFile("pkg/util/util.go", []byte("package util\n"))
+		utils.AddFile("pkg/other/other.go", []byte("package other\n"))
+		return utils
+	}
+
+	t.Run("all packages if not restricted to changes", func(t *testing.T) {
+		t.Parallel()
+		config := golangBuildOptions{}
+		utils := newUtils()
+
+		packages, err := golangTestPackages(&config, utils)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"./..."}, packages)
+		assert.False(t, utils.HasWrittenFile("golangBuild_changeAnalysis.json"))
+	})
+
+	t.Run("affected packages and their importers", func(t *testing.T) {
+		t.Parallel()
+		config := golangBuildOptions{TestChangedPackagesOnly: true}
+		utils := newUtils()
+		utils.changedFiles = []string{"pkg/api/api.go"}
+
+		packages, err := golangTestPackages(&config, utils)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"./cmd/app", "./pkg/api"}, packages)
+		assert.True(t, utils.HasWrittenFile("golangBuild_changeAnalysis.json"))
+		assert.True(t, utils.HasWrittenFile("golangBuild_reports.json"))
+	})
+
+	t.Run("no package affected", func(t *testing.T) {
+		t.Parallel()
+		config := golangBuildOptions{TestChangedPackagesOnly: true}
+		utils := newUtils()
+		utils.changedFiles = []string{"README.md"}
+
+		packages, err := golangTestPackages(&config, utils)
+		assert.NoError(t, err)
+		assert.Empty(t, packages)
+	})
+
+	t.Run("all packages affected by global file", func(t *testing.T) {
+		t.Parallel()
+		config := golangBuildOptions{TestChangedPackagesOnly: true, ChangeAnalysisGlobalFiles: []string{".pipeline/**"}}
+		utils := newUtils()
+		utils.changedFiles = []string{".pipeline/config.yml"}
+
+		packages, err := golangTestPackages(&config, utils)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"./..."}, packages)
+	})
+
+	t.Run("all packages if changes cannot be determined", func(t *testing.T) {
+		t.Parallel()
+		config := golangBuildOptions{TestChangedPackagesOnly: true}
+		utils := newUtils()
+		utils.changedFilesError = fmt.Errorf("no pull request and no change set available")
+
+		packages, err := golangTestPackages(&config, utils)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"./..."}, packages)
+	})
+}
+
 func TestRunGolangIntegrationTests(t *testing.T) {
 	t.Parallel()
 
@@ -737,7 +857,7 @@ func TestRunGolangBuildPerArchitecture(t *testing.T) {
 		architecture, _ := multiarch.ParsePlatformString("linux,amd64")
 		goModFile := modfile.File{Module: &modfile.Module{Mod: module.Version{Path: "test/testBinary"}}}
 
-		binaryName, err := runGolangBuildPerArchitecture(&config, &goModFile, utils, ldflags, architecture)
+		binaryName, err := runGolangBuildPerArchitecture(&config, &goModFile, utils, ldflags, architecture, "")
 		assert.NoError(t, err)
 		assert.Greater(t, len(utils.Env), 3)
 		assert.Contains(t, utils.Env, "CGO_ENABLED=0")
@@ -756,7 +876,7 @@ func TestRunGolangBuildPerArchitecture(t *testing.T) {
 		architecture, _ := multiarch.ParsePlatformString("linux,amd64")
 		goModFile := modfile.File{Module: &modfile.Module{Mod: module.Version{Path: "test/testBinary"}}}
 
-		binaryNames, err := runGolangBuildPerArchitecture(&config, &goModFile, utils, ldflags, architecture)
+		binaryNames, err := runGolangBuildPerArchitecture(&config, &goModFile, utils, ldflags, architecture, "")
 		assert.NoError(t, err)
 		assert.Contains(t, utils.Calls[0].Params, "-o")
 		assert.Contains(t, utils.Calls[0].Params, "testBin-linux.amd64")
@@ -775,7 +895,7 @@ func TestRunGolangBuildPerArchitecture(t *testing.T) {
 		architecture, _ := multiarch.ParsePlatformString("windows,amd64")
 		goModFile := modfile.File{Module: &modfile.Module{Mod: module.Version{Path: "test/testBinary"}}}
 
-		binaryNames, err := runGolangBuildPerArchitecture(&config, &goModFile, utils, ldflags, architecture)
+		binaryNames, err := runGolangBuildPerArchitecture(&config, &goModFile, utils, ldflags, architecture, "")
 		assert.NoError(t, err)
 		assert.Contains(t, utils.Calls[0].Params, "-o")
 		assert.Contains(t, utils.Calls[0].Params, "testBin-windows.amd64.exe")
@@ -795,7 +915,7 @@ func TestRunGolangBuildPerArchitecture(t *testing.T) {
 		architecture, _ := multiarch.ParsePlatformString("linux,amd64")
 		goModFile := modfile.File{Module: &modfile.Module{Mod: module.Version{Path: "test/testBinary"}}}
 
-		binaryNames, err := runGolangBuildPerArchitecture(&config, &goModFile, utils, ldflags, architecture)
+		binaryNames, err := runGolangBuildPerArchitecture(&config, &goModFile, utils, ldflags, architecture, "")
 		assert.NoError(t, err)
 		assert.Contains(t, utils.Calls[0].Params, "list")
 		assert.Contains(t, utils.Calls[0].Params, "package/foo")
@@ -819,7 +939,7 @@ func TestRunGolangBuildPerArchitecture(t *testing.T) {
 		architecture, _ := multiarch.ParsePlatformString("windows,amd64")
 		goModFile := modfile.File{Module: &modfile.Module{Mod: module.Version{Path: "test/testBinary"}}}
 
-		binaryNames, err := runGolangBuildPerArchitecture(&config, &goModFile, utils, ldflags, architecture)
+		binaryNames, err := runGolangBuildPerArchitecture(&config, &goModFile, utils, ldflags, architecture, "")
 		assert.NoError(t, err)
 		assert.Contains(t, utils.Calls[0].Params, "list")
 		assert.Contains(t, utils.Calls[0].Params, "package/foo")
@@ -843,7 +963,7 @@ func TestRunGolangBuildPerArchitecture(t *testing.T) {
 		architecture, _ := multiarch.ParsePlatformString("linux,amd64")
 		goModFile := modfile.File{Module: &modfile.Module{Mod: module.Version{Path: "test/testBinary"}}}
 
-		binaryNames, err := runGolangBuildPerArchitecture(&config, &goModFile, utils, ldflags, architecture)
+		binaryNames, err := runGolangBuildPerArchitecture(&config, &goModFile, utils, ldflags, architecture, "")
 		assert.NoError(t, err)
 		assert.Contains(t, utils.Calls[0].Params, "list")
 		assert.Contains(t, utils.Calls[0].Params, "package/foo")
@@ -863,7 +983,7 @@ func TestRunGolangBuildPerArchitecture(t *testing.T) {
 		architecture, _ := multiarch.ParsePlatformString("linux,amd64")
 		goModFile := modfile.File{Module: &modfile.Module{Mod: module.Version{Path: "test/testBinary"}}}
 
-		_, err := runGolangBuildPerArchitecture(&config, &goModFile, utils, ldflags, architecture)
+		_, err := runGolangBuildPerArchitecture(&config, &goModFile, utils, ldflags, architecture, "")
 		assert.EqualError(t, err, "failed to run build for linux.amd64: execution error")
 	})
 
@@ -1149,3 +1269,132 @@ func TestRetrieveGolangciLint(t *testing.T) {
 		})
 	}
 }
+
+func TestGolangReleasePackaging(t *testing.T) {
+	newReleaseUtils := func() *golangBuildMockUtils {
+		utils := newGolangBuildTestsUtils()
+		utils.FilesMock.AddFile("go.mod", []byte("module example.com/my/tool"))
+		utils.FilesMock.AddFile("LICENSE", []byte("Apache License"))
+		utils.FilesMock.AddFile("tool-linux.amd64", []byte("linux binary"))
+		utils.FilesMock.AddFile("tool-windows.amd64.exe", []byte("windows binary"))
+		utils.StdoutReturn = map[string]string{"git log -1 --format=%ct": "1700000000\n"}
+		return utils
+	}
+
+	t.Run("success - archives and checksums", func(t *testing.T) {
+		cpe := golangBuildCommonPipelineEnvironment{}
+		config := golangBuildOptions{
+			TargetArchitectures: []string{"linux,amd64", "windows,amd64"},
+			Output:              "tool",
+			ArtifactVersion:     "1.2.3",
+			ReleasePackaging:    true,
+			ReleaseFiles:        []string{"LICENSE*", "NOTICE*"},
+		}
+		utils := newReleaseUtils()
+
+		err := runGolangBuild(&config, &telemetry.CustomData{}, utils, &cpe)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, mock.ExecCall{Exec: "git", Params: []string{"log", "-1", "--format=%ct"}}, utils.Calls[0])
+			assert.Contains(t, utils.Env, "SOURCE_DATE_EPOCH=1700000000")
+			releaseDirExists, _ := utils.DirExists("dist")
+			assert.True(t, releaseDirExists)
+			assert.True(t, utils.HasFile(filepath.Join("dist", "tool_1.2.3_linux_amd64.tar.gz")))
+			assert.True(t, utils.HasFile(filepath.Join("dist", "tool_1.2.3_windows_amd64.zip")))
+			checksums, err := utils.FileRead(filepath.Join("dist", "SHA256SUMS"))
+			assert.NoError(t, err)
+			assert.Contains(t, string(checksums), "  tool_1.2.3_linux_amd64.tar.gz\n")
+			assert.Contains(t, string(checksums), "  tool_1.2.3_windows_amd64.zip\n")
+
+			if assert.Len(t, cpe.custom.artifacts, 3) {
+				assert.Equal(t, "tool_1.2.3_linux_amd64.tar.gz", cpe.custom.artifacts[0].Name)
+				assert.Equal(t, filepath.Join("dist", "tool_1.2.3_linux_amd64.tar.gz"), cpe.custom.artifacts[0].Path)
+				assert.Equal(t, "archive", cpe.custom.artifacts[0].Type)
+				assert.Equal(t, "linux/amd64", cpe.custom.artifacts[0].Platform)
+				assert.Len(t, cpe.custom.artifacts[0].SHA256, 64)
+				assert.Equal(t, "windows/amd64", cpe.custom.artifacts[1].Platform)
+				assert.Equal(t, "checksums", cpe.custom.artifacts[2].Type)
+			}
+		}
+	})
+
+	t.Run("success - default binary name on windows", func(t *testing.T) {
+		config := golangBuildOptions{
+			TargetArchitectures: []string{"windows,amd64"},
+			ArtifactVersion:     "1.2.3",
+			ReleasePackaging:    true,
+		}
+		utils := newReleaseUtils()
+		utils.FilesMock.AddFile("tool.exe", []byte("windows binary"))
+
+		err := runGolangBuild(&config, &telemetry.CustomData{}, utils, &golangBuildCommonPipelineEnvironment{})
+
+		if assert.NoError(t, err) {
+			assert.True(t, utils.HasFile(filepath.Join("dist", "tool_1.2.3_windows_amd64.zip")))
+		}
+	})
+
+	t.Run("success - reproducible archives", func(t *testing.T) {
+		config := golangBuildOptions{
+			TargetArchitectures: []string{"linux,amd64"},
+			Output:              "tool",
+			ArtifactVersion:     "1.2.3",
+			ReleasePackaging:    true,
+			ReleaseFiles:        []string{"LICENSE"},
+		}
+		first, second := newReleaseUtils(), newReleaseUtils()
+
+		assert.NoError(t, runGolangBuild(&config, &telemetry.CustomData{}, first, &golangBuildCommonPipelineEnvironment{}))
+		assert.NoError(t, runGolangBuild(&config, &telemetry.CustomData{}, second, &golangBuildCommonPipelineEnvironment{}))
+
+		firstArchive, _ := first.FileRead(filepath.Join("dist", "tool_1.2.3_linux_amd64.tar.gz"))
+		secondArchive, _ := second.FileRead(filepath.Join("dist", "tool_1.2.3_linux_amd64.tar.gz"))
+		assert.Equal(t, firstArchive, secondArchive)
+	})
+
+	t.Run("success - signed checksums", func(t *testing.T) {
+		cpe := golangBuildCommonPipelineEnvironment{}
+		config := golangBuildOptions{
+			TargetArchitectures:         []string{"linux,amd64"},
+			Output:                      "tool",
+			ArtifactVersion:             "1.2.3",
+			ReleasePackaging:            true,
+			ReleaseSigningKey:           "/secrets/signing.key",
+			ReleaseSigningKeyPassphrase: "passphrase",
+		}
+		utils := newReleaseUtils()
+
+		err := runGolangBuild(&config, &telemetry.CustomData{}, utils, &cpe)
+
+		if assert.NoError(t, err) && assert.Len(t, utils.Calls, 4) {
+			assert.Equal(t, mock.ExecCall{Exec: "gpg", Params: []string{"--batch", "--import", "/secrets/signing.key"}}, utils.Calls[2])
+			assert.Equal(t, mock.ExecCall{Exec: "gpg", Params: []string{"--batch", "--yes", "--pinentry-mode", "loopback", "--passphrase-fd", "0",
+				"--output", filepath.Join("dist", "SHA256SUMS.sig"), "--detach-sign", filepath.Join("dist", "SHA256SUMS")}}, utils.Calls[3])
+			assert.NotContains(t, utils.Calls[3].Params, "passphrase")
+			assert.Equal(t, golangArtifactTypes(cpe), []string{"archive", "checksums", "signature"})
+		}
+	})
+
+	t.Run("failure - commit time", func(t *testing.T) {
+		config := golangBuildOptions{
+			TargetArchitectures: []string{"linux,amd64"},
+			Output:              "tool",
+			ArtifactVersion:     "1.2.3",
+			ReleasePackaging:    true,
+		}
+		utils := newReleaseUtils()
+		utils.ShouldFailOnCommand = map[string]error{"git log": fmt.Errorf("not a git repository")}
+
+		err := runGolangBuild(&config, &telemetry.CustomData{}, utils, &golangBuildCommonPipelineEnvironment{})
+
+		assert.EqualError(t, err, "failed to retrieve the commit time of HEAD: not a git repository")
+	})
+}
+
+func golangArtifactTypes(cpe golangBuildCommonPipelineEnvironment) []string {
+	types := []string{}
+	for _, artifact := range cpe.custom.artifacts {
+		types = append(types, artifact.Type)
+	}
+	return types
+}
@@ -4,13 +4,16 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"io/fs"
+	"net/http"
 	"path/filepath"
 	"strings"
 	"text/template"
 
 	"github.com/SAP/jenkins-library/pkg/command"
 	"github.com/SAP/jenkins-library/pkg/gradle"
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/piperenv"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
@@ -137,12 +140,14 @@ type gradleExecuteBuildUtils interface {
 	command.ExecRunner
 	piperutils.FileUtils
 	Filepath
+	piperhttp.Sender
 }
 
 type gradleExecuteBuildUtilsBundle struct {
 	*command.Command
 	*piperutils.Files
 	Filepath
+	*piperhttp.Client
 }
 
 func newGradleExecuteBuildUtils() gradleExecuteBuildUtils {
@@ -153,6 +158,7 @@ func newGradleExecuteBuildUtils() gradleExecuteBuildUtils {
 		},
 		Files:    &piperutils.Files{},
 		Filepath: walkDirFunc,
+		Client:   &piperhttp.Client{},
 	}
 	utils.Stdout(log.Writer())
 	utils.Stderr(log.Writer())
@@ -250,9 +256,115 @@ func publishArtifacts(config *gradleExecuteBuildOptions, utils gradleExecuteBuil
 		return err
 	}
 	pipelineEnv.custom.artifacts = artifacts
+
+	if config.VerifyPublishedArtifacts {
+		return verifyPublishedArtifacts(config, utils)
+	}
+	return nil
+}
+
+// verifyPublishedArtifacts checks that the repository contains all projects which are expected to be published
+// including the archives which have been built for them
+func verifyPublishedArtifacts(config *gradleExecuteBuildOptions, utils gradleExecuteBuildUtils) error {
+	model, err := gradle.GetProjectModel(config.Path, config.UseWrapper, utils)
+	if err != nil {
+		return err
+	}
+	utils.SetOptions(piperhttp.ClientOptions{Username: config.RepositoryUsername, Password: config.RepositoryPassword})
+
+	repositoryURL := strings.TrimSuffix(config.RepositoryURL, "/") + "/"
+	missing := []string{}
+	for _, project := range expectedPublications(config, model) {
+		coordinates := fmt.Sprintf("%v:%v:%v", project.GroupID, project.ArtifactID, project.Version)
+		// the metadata of a snapshot version is read since it lists the timestamped archives
+		descriptor, err := requestPublishedFile(utils, repositoryURL+project.PublishedDescriptor(), project.IsSnapshot())
+		if err != nil {
+			log.Entry().WithError(err).Errorf("artifact '%v' of project '%v' not found in repository", coordinates, project.Path)
+			missing = append(missing, coordinates)
+			continue
+		}
+		for _, artifact := range project.Artifacts {
+			if exists, _ := utils.FileExists(artifact.File); !exists {
+				log.Entry().Debugf("Skipping archive '%v' of project '%v' as it has not been built", artifact.File, project.Path)
+				continue
+			}
+			var published bool
+			if project.IsSnapshot() {
+				published, err = gradle.SnapshotContainsArtifact(descriptor, artifact)
+			} else {
+				_, err = requestPublishedFile(utils, repositoryURL+project.PublishedArtifact(artifact), false)
+				published = err == nil
+			}
+			if !published {
+				artifactCoordinates := artifactCoordinates(project, artifact)
+				log.Entry().WithError(err).Errorf("archive '%v' of project '%v' not found in repository", artifactCoordinates, project.Path)
+				missing = append(missing, artifactCoordinates)
+			}
+		}
+		log.Entry().Infof("artifact '%v' of project '%v' has been published", coordinates, project.Path)
+	}
+	if len(missing) > 0 {
+		log.SetErrorCategory(log.ErrorBuild)
+		return fmt.Errorf("artifacts not published to '%v': %v", config.RepositoryURL, strings.Join(missing, ", "))
+	}
 	return nil
 }
 
+// requestPublishedFile checks the existence of a file in the repository, its content is only returned if read is true
+func requestPublishedFile(utils gradleExecuteBuildUtils, url string, read bool) ([]byte, error) {
+	method := http.MethodHead
+	if read {
+		method = http.MethodGet
+	}
+	response, err := utils.SendRequest(method, url, nil, nil, nil)
+	if response != nil && response.Body != nil {
+		defer response.Body.Close()
+	}
+	if err != nil || !read {
+		return nil, err
+	}
+	if response == nil || response.Body == nil {
+		return nil, fmt.Errorf("empty response of %v", url)
+	}
+	return io.ReadAll(response.Body)
+}
+
+// artifactCoordinates returns the coordinates of an archive in the format groupId:artifactId:extension[:classifier]:version
+func artifactCoordinates(project gradle.Project, artifact gradle.Artifact) string {
+	parts := []string{project.GroupID, project.ArtifactID, artifact.Extension}
+	if len(artifact.Classifier) > 0 {
+		parts = append(parts, artifact.Classifier)
+	}
+	return strings.Join(append(parts, project.Version), ":")
+}
+
+// expectedPublications returns the projects published via the publish init script with the coordinates overwritten by the configuration
+func expectedPublications(config *gradleExecuteBuildOptions, model *gradle.Model) []gradle.Project {
+	projects := []gradle.Project{}
+	for _, project := range model.Projects {
+		if config.ApplyPublishingForAllProjects {
+			if piperutils.ContainsString(config.ExcludePublishingForProjects, project.Name) {
+				continue
+			}
+		} else {
+			if project.Path != ":" {
+				continue
+			}
+			if len(config.ArtifactID) > 0 {
+				project.ArtifactID = config.ArtifactID
+			}
+		}
+		if len(config.ArtifactGroupID) > 0 {
+			project.GroupID = config.ArtifactGroupID
+		}
+		if len(config.ArtifactVersion) > 0 {
+			project.Version = config.ArtifactVersion
+		}
+		projects = append(projects, project)
+	}
+	return projects
+}
+
 func getInitScriptContent(options *gradleExecuteBuildOptions, templateContent string) (string, error) {
 	tmpl, err := template.New("resources").Parse(templateContent)
 	if err != nil {
@@ -37,6 +37,7 @@ type gradleExecuteBuildOptions struct {
 	ExcludeCreateBOMForProjects   []string `json:"excludeCreateBOMForProjects,omitempty"`
 	ExcludePublishingForProjects  []string `json:"excludePublishingForProjects,omitempty"`
 	BuildFlags                    []string `json:"buildFlags,omitempty"`
+	VerifyPublishedArtifacts      bool     `json:"verifyPublishedArtifacts,omitempty"`
 }
 
 type gradleExecuteBuildReports struct {
@@ -217,6 +218,7 @@ func addGradleExecuteBuildFlags(cmd *cobra.Command, stepConfig *gradleExecuteBui
 	cmd.Flags().StringSliceVar(&stepConfig.ExcludeCreateBOMForProjects, "excludeCreateBOMForProjects", []string{}, "Defines which projects/subprojects will be ignored during bom creation. Only if applyCreateBOMForAllProjects is set to true")
 	cmd.Flags().StringSliceVar(&stepConfig.ExcludePublishingForProjects, "excludePublishingForProjects", []string{}, "Defines which projects/subprojects will be ignored during publishing. Only if applyCreateBOMForAllProjects is set to true")
 	cmd.Flags().StringSliceVar(&stepConfig.BuildFlags, "buildFlags", []string{}, "Defines a list of tasks and/or arguments to be provided for gradle in the respective order to be executed. This list takes precedence if specified over 'task' parameter")
+	cmd.Flags().BoolVar(&stepConfig.VerifyPublishedArtifacts, "verifyPublishedArtifacts", false, "If set to true the step checks after publishing that every expected project has been published to the repository.")
 
 }
 
@@ -396,6 +398,15 @@ func gradleExecuteBuildMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     []string{},
 					},
+					{
+						Name:        "verifyPublishedArtifacts",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
 				},
 			},
 			Containers: []config.Container{
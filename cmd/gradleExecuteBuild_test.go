@@ -2,13 +2,17 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"io/fs"
+	"net/http"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/mock"
 	"github.com/SAP/jenkins-library/pkg/piperenv"
 )
@@ -19,6 +23,28 @@ type gradleExecuteBuildMockUtils struct {
 	*mock.ExecMockRunner
 	*mock.FilesMock
 	Filepath
+	*gradleRepositoryMock
+}
+
+type gradleRepositoryMock struct {
+	published []string
+	contents  map[string]string
+	requested []string
+	options   piperhttp.ClientOptions
+}
+
+func (r *gradleRepositoryMock) SendRequest(method, url string, body io.Reader, header http.Header, cookies []*http.Cookie) (*http.Response, error) {
+	r.requested = append(r.requested, method+" "+url)
+	for _, published := range r.published {
+		if published == url {
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(r.contents[url]))}, nil
+		}
+	}
+	return &http.Response{StatusCode: http.StatusNotFound}, fmt.Errorf("request to %v returned with response 404 Not Found", url)
+}
+
+func (r *gradleRepositoryMock) SetOptions(options piperhttp.ClientOptions) {
+	r.options = options
 }
 
 type isDirEntryMock func() bool
@@ -250,6 +276,116 @@ func TestRunGradleExecuteBuild(t *testing.T) {
 	})
 }
 
+func TestVerifyPublishedArtifacts(t *testing.T) {
+	const projectModel = `{"projects": [
+		{"path": ":", "name": "service", "dir": ".", "groupId": "com.example", "artifactId": "service", "version": "1.0.0", "packaging": "jar",
+			"artifacts": [{"file": "build/libs/service-1.0.0.jar", "extension": "jar"}, {"file": "build/libs/service-1.0.0-sources.jar", "classifier": "sources", "extension": "jar"}]},
+		{"path": ":api", "name": "api", "dir": "api", "groupId": "com.example", "artifactId": "api", "version": "1.0.0", "packaging": "jar",
+			"artifacts": [{"file": "api/build/libs/api-1.0.0.jar", "extension": "jar"}, {"file": "api/build/libs/api-1.0.0-javadoc.jar", "classifier": "javadoc", "extension": "jar"}]},
+		{"path": ":tests", "name": "tests", "dir": "tests", "groupId": "com.example", "artifactId": "tests", "version": "1.0.0", "packaging": "jar"}
+	]}`
+	newUtils := func(published ...string) gradleExecuteBuildMockUtils {
+		utils := gradleExecuteBuildMockUtils{
+			ExecMockRunner:       &mock.ExecMockRunner{},
+			FilesMock:            &mock.FilesMock{},
+			gradleRepositoryMock: &gradleRepositoryMock{published: published},
+		}
+		utils.FilesMock.AddFile("path/to/build.gradle", []byte{})
+		utils.FilesMock.AddFile("path/to/piperProjectModel.json.tmp", []byte(projectModel))
+		// the javadoc archive of the api project has not been built
+		utils.FilesMock.AddFile("path/to/build/libs/service-1.0.0.jar", []byte{})
+		utils.FilesMock.AddFile("path/to/build/libs/service-1.0.0-sources.jar", []byte{})
+		utils.FilesMock.AddFile("path/to/api/build/libs/api-1.0.0.jar", []byte{})
+		return utils
+	}
+
+	t.Run("success case - all projects", func(t *testing.T) {
+		utils := newUtils(
+			"https://repo.example.com/releases/com/example/service/1.0.0/service-1.0.0.pom",
+			"https://repo.example.com/releases/com/example/service/1.0.0/service-1.0.0.jar",
+			"https://repo.example.com/releases/com/example/service/1.0.0/service-1.0.0-sources.jar",
+			"https://repo.example.com/releases/com/example/api/1.0.0/api-1.0.0.pom",
+			"https://repo.example.com/releases/com/example/api/1.0.0/api-1.0.0.jar",
+		)
+		options := &gradleExecuteBuildOptions{
+			Path:                          "path/to",
+			RepositoryURL:                 "https://repo.example.com/releases/",
+			RepositoryUsername:            "user",
+			RepositoryPassword:            "password",
+			ApplyPublishingForAllProjects: true,
+			ExcludePublishingForProjects:  []string{"tests"},
+		}
+
+		err := verifyPublishedArtifacts(options, utils)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "user", utils.options.Username)
+		assert.Equal(t, []string{
+			"HEAD https://repo.example.com/releases/com/example/service/1.0.0/service-1.0.0.pom",
+			"HEAD https://repo.example.com/releases/com/example/service/1.0.0/service-1.0.0.jar",
+			"HEAD https://repo.example.com/releases/com/example/service/1.0.0/service-1.0.0-sources.jar",
+			"HEAD https://repo.example.com/releases/com/example/api/1.0.0/api-1.0.0.pom",
+			"HEAD https://repo.example.com/releases/com/example/api/1.0.0/api-1.0.0.jar",
+		}, utils.requested)
+		assert.Equal(t, mock.ExecCall{Exec: "gradle", Params: []string{"piperProjectModel", "-p", "path/to", "--init-script", "initScript.gradle.tmp"}}, utils.Calls[1])
+	})
+
+	t.Run("success case - root project with overwritten coordinates", func(t *testing.T) {
+		metadataURL := "https://repo.example.com/snapshots/org/example/service-bundle/1.1.0-SNAPSHOT/maven-metadata.xml"
+		utils := newUtils(metadataURL)
+		utils.contents = map[string]string{metadataURL: `<metadata><versioning><snapshotVersions>
+			<snapshotVersion><extension>jar</extension><value>1.1.0-20240102.030405-1</value></snapshotVersion>
+			<snapshotVersion><classifier>sources</classifier><extension>jar</extension><value>1.1.0-20240102.030405-1</value></snapshotVersion>
+		</snapshotVersions></versioning></metadata>`}
+		options := &gradleExecuteBuildOptions{
+			Path:            "path/to",
+			RepositoryURL:   "https://repo.example.com/snapshots",
+			ArtifactGroupID: "org.example",
+			ArtifactID:      "service-bundle",
+			ArtifactVersion: "1.1.0-SNAPSHOT",
+		}
+
+		err := verifyPublishedArtifacts(options, utils)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"GET " + metadataURL}, utils.requested)
+	})
+
+	t.Run("failed case - missing artifacts", func(t *testing.T) {
+		utils := newUtils(
+			"https://repo.example.com/releases/com/example/service/1.0.0/service-1.0.0.pom",
+			"https://repo.example.com/releases/com/example/service/1.0.0/service-1.0.0.jar",
+		)
+		options := &gradleExecuteBuildOptions{
+			Path:                          "path/to",
+			RepositoryURL:                 "https://repo.example.com/releases",
+			ApplyPublishingForAllProjects: true,
+		}
+
+		err := verifyPublishedArtifacts(options, utils)
+
+		assert.EqualError(t, err, "artifacts not published to 'https://repo.example.com/releases': com.example:service:jar:sources:1.0.0, com.example:api:1.0.0, com.example:tests:1.0.0")
+	})
+
+	t.Run("failed case - snapshot archive missing", func(t *testing.T) {
+		metadataURL := "https://repo.example.com/snapshots/com/example/service/1.1.0-SNAPSHOT/maven-metadata.xml"
+		utils := newUtils(metadataURL)
+		utils.contents = map[string]string{metadataURL: `<metadata><versioning><snapshotVersions>
+			<snapshotVersion><extension>pom</extension><value>1.1.0-20240102.030405-1</value></snapshotVersion>
+			<snapshotVersion><classifier>sources</classifier><extension>jar</extension><value>1.1.0-20240102.030405-1</value></snapshotVersion>
+		</snapshotVersions></versioning></metadata>`}
+		options := &gradleExecuteBuildOptions{
+			Path:            "path/to",
+			RepositoryURL:   "https://repo.example.com/snapshots",
+			ArtifactVersion: "1.1.0-SNAPSHOT",
+		}
+
+		err := verifyPublishedArtifacts(options, utils)
+
+		assert.EqualError(t, err, "artifacts not published to 'https://repo.example.com/snapshots': com.example:service:jar:1.1.0-SNAPSHOT")
+	})
+}
+
 func TestGetPublishedArtifactsNames(t *testing.T) {
 	tt := []struct {
 		name              string
@@ -0,0 +1,186 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+
+	"github.com/SAP/jenkins-library/pkg/command"
+	"github.com/SAP/jenkins-library/pkg/iacscan"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/reporting"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/pkg/errors"
+)
+
+const iacReportsDirectory = "iac"
+
+type iacExecuteScanUtils interface {
+	command.ExecRunner
+	piperutils.FileUtils
+}
+
+type iacExecuteScanUtilsBundle struct {
+	*command.Command
+	*piperutils.Files
+}
+
+func newIacExecuteScanUtils() iacExecuteScanUtils {
+	utils := iacExecuteScanUtilsBundle{
+		Command: &command.Command{},
+		Files:   &piperutils.Files{},
+	}
+	// Reroute command output to logging framework
+	utils.Stdout(log.Writer())
+	utils.Stderr(log.Writer())
+	return &utils
+}
+
+func iacExecuteScan(config iacExecuteScanOptions, telemetryData *telemetry.CustomData, influx *iacExecuteScanInflux) {
+	utils := newIacExecuteScanUtils()
+
+	err := runIacExecuteScan(&config, utils, influx)
+	if err != nil {
+		log.Entry().WithError(err).Fatal("step execution failed")
+	}
+}
+
+func runIacExecuteScan(config *iacExecuteScanOptions, utils iacExecuteScanUtils, influx *iacExecuteScanInflux) error {
+	policies, err := readIacPolicies(config, utils)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return err
+	}
+
+	files, err := findIacFiles(config, utils)
+	if err != nil {
+		return err
+	}
+	scanned := []string{}
+	resources := []iacscan.Resource{}
+	for _, file := range files {
+		content, err := utils.FileRead(file)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read file '%v'", file)
+		}
+		fileType := iacscan.DetectType(file, content)
+		if len(fileType) == 0 {
+			continue
+		}
+		log.Entry().Infof("Checking %v file '%v'", fileType, file)
+		fileResources, err := iacscan.ReadResources(fileType, filepath.ToSlash(file), content)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return err
+		}
+		scanned = append(scanned, file)
+		resources = append(resources, fileResources...)
+	}
+
+	for _, chartPath := range config.HelmChartPaths {
+		chartResources, err := renderIacHelmChart(chartPath, config.HelmValues, utils)
+		if err != nil {
+			return err
+		}
+		scanned = append(scanned, chartPath)
+		resources = append(resources, chartResources...)
+	}
+
+	for _, planFile := range config.TerraformPlanFiles {
+		planResources, err := readIacTerraformPlan(planFile, utils)
+		if err != nil {
+			return err
+		}
+		scanned = append(scanned, planFile)
+		resources = append(resources, planResources...)
+	}
+
+	if len(scanned) == 0 {
+		log.Entry().Warn("No files found to be checked")
+	}
+
+	findings := iacscan.Scan(resources, policies)
+	for _, finding := range findings {
+		log.Entry().Infof("%v %v (%v) in %v: %v", finding.PolicyID, finding.Title, finding.Severity, finding.Resource, finding.Message)
+	}
+	severe := iacscan.CountSevere(findings, config.FailOnSeverity)
+	influx.iac_data.fields.findings = len(findings)
+	influx.iac_data.fields.severe_findings = severe
+
+	scanReport := iacscan.CreateScanReport(scanned, findings, config.FailOnSeverity)
+	reportPaths, err := writeIacReports(scanReport, findings, utils)
+	if err != nil {
+		return err
+	}
+	piperutils.PersistReportsAndLinks("iacExecuteScan", "./", utils, reportPaths, nil)
+
+	if severe > 0 {
+		log.SetErrorCategory(log.ErrorCompliance)
+		return fmt.Errorf("%v misconfiguration(s) with severity %v or higher found", severe, config.FailOnSeverity)
+	}
+	return nil
+}
+
+func readIacPolicies(config *iacExecuteScanOptions, utils iacExecuteScanUtils) ([]iacscan.Policy, error) {
+	custom := []iacscan.Policy{}
+	if len(config.PoliciesFile) > 0 {
+		content, err := utils.FileRead(config.PoliciesFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read policies file '%v'", config.PoliciesFile)
+		}
+		if custom, err = iacscan.ReadPolicies(content); err != nil {
+			return nil, errors.Wrapf(err, "failed to read policies file '%v'", config.PoliciesFile)
+		}
+	}
+	return iacscan.SelectPolicies(iacscan.BuiltInPolicies(), custom, config.SkipPolicies), nil
+}
+
+func findIacFiles(config *iacExecuteScanOptions, utils iacExecuteScanUtils) ([]string, error) {
+	return piperutils.FindFiles(config.FilePatterns, config.Excludes, utils)
+}
+
+func renderIacHelmChart(chartPath string, values []string, utils iacExecuteScanUtils) ([]iacscan.Resource, error) {
+	output := bytes.Buffer{}
+	utils.Stdout(&output)
+	defer utils.Stdout(log.Writer())
+
+	params := []string{"template", filepath.Base(chartPath), chartPath}
+	for _, value := range values {
+		params = append(params, "--values", value)
+	}
+	log.Entry().Infof("Rendering Helm chart '%v'", chartPath)
+	if err := utils.RunExecutable(toolHelm, params...); err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return nil, errors.Wrapf(err, "failed to render Helm chart '%v'", chartPath)
+	}
+	resources, err := iacscan.ReadRenderedChart(filepath.ToSlash(chartPath), output.Bytes())
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read rendered Helm chart '%v'", chartPath)
+	}
+	return resources, nil
+}
+
+func readIacTerraformPlan(planFile string, utils iacExecuteScanUtils) ([]iacscan.Resource, error) {
+	output := bytes.Buffer{}
+	utils.Stdout(&output)
+	defer utils.Stdout(log.Writer())
+
+	log.Entry().Infof("Converting Terraform plan '%v'", planFile)
+	if err := utils.RunExecutable("terraform", "show", "-json", planFile); err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return nil, errors.Wrapf(err, "failed to convert Terraform plan '%v'", planFile)
+	}
+	return iacscan.ReadResources(iacscan.TypeTerraform, filepath.ToSlash(planFile), output.Bytes())
+}
+
+func writeIacReports(scanReport reporting.ScanReport, findings []iacscan.Finding, utils iacExecuteScanUtils) ([]piperutils.Path, error) {
+	return reporting.WriteScanReports(scanReport, reporting.ScanReportFiles{
+		Directory: iacReportsDirectory,
+		Name:      "IaC Misconfiguration",
+		HTMLFile:  "piper_iac_report.html",
+		SarifFile: "piper_iac.sarif",
+		Sarif:     iacscan.CreateSarif(findings),
+		JSONFile:  "iacExecuteScan_iac.json",
+	}, utils)
+}
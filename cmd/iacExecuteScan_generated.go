@@ -0,0 +1,332 @@
+// Code generated by piper's step-generator. DO NOT EDIT.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/gcs"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperenv"
+	"github.com/SAP/jenkins-library/pkg/splunk"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/SAP/jenkins-library/pkg/validation"
+	"github.com/bmatcuk/doublestar"
+	"github.com/spf13/cobra"
+)
+
+type iacExecuteScanOptions struct {
+	FilePatterns       []string `json:"filePatterns,omitempty"`
+	Excludes           []string `json:"excludes,omitempty"`
+	HelmChartPaths     []string `json:"helmChartPaths,omitempty"`
+	HelmValues         []string `json:"helmValues,omitempty"`
+	TerraformPlanFiles []string `json:"terraformPlanFiles,omitempty"`
+	PoliciesFile       string   `json:"policiesFile,omitempty"`
+	SkipPolicies       []string `json:"skipPolicies,omitempty"`
+	FailOnSeverity     string   `json:"failOnSeverity,omitempty" validate:"possible-values=low medium high none"`
+}
+
+type iacExecuteScanInflux struct {
+	iac_data struct {
+		fields struct {
+			findings        int
+			severe_findings int
+		}
+		tags struct {
+		}
+	}
+}
+
+func (i *iacExecuteScanInflux) persist(path, resourceName string) {
+	measurementContent := []struct {
+		measurement string
+		valType     string
+		name        string
+		value       interface{}
+	}{
+		{valType: config.InfluxField, measurement: "iac_data", name: "findings", value: i.iac_data.fields.findings},
+		{valType: config.InfluxField, measurement: "iac_data", name: "severe_findings", value: i.iac_data.fields.severe_findings},
+	}
+
+	errCount := 0
+	for _, metric := range measurementContent {
+		err := piperenv.SetResourceParameter(path, resourceName, filepath.Join(metric.measurement, fmt.Sprintf("%vs", metric.valType), metric.name), metric.value)
+		if err != nil {
+			log.Entry().WithError(err).Error("Error persisting influx environment.")
+			errCount++
+		}
+	}
+	if errCount > 0 {
+		log.Entry().Error("failed to persist Influx environment")
+	}
+}
+
+type iacExecuteScanReports struct {
+}
+
+func (p *iacExecuteScanReports) persist(stepConfig iacExecuteScanOptions, gcpJsonKeyFilePath string, gcsBucketId string, gcsFolderPath string, gcsSubFolder string) {
+	if gcsBucketId == "" {
+		log.Entry().Info("persisting reports to GCS is disabled, because gcsBucketId is empty")
+		return
+	}
+	log.Entry().Info("Uploading reports to Google Cloud Storage...")
+	content := []gcs.ReportOutputParam{
+		{FilePattern: "**/piper_iac_report.html", ParamRef: "", StepResultType: "iac"},
+		{FilePattern: "**/piper_iac.sarif", ParamRef: "", StepResultType: "iac"},
+	}
+	envVars := []gcs.EnvVar{
+		{Name: "GOOGLE_APPLICATION_CREDENTIALS", Value: gcpJsonKeyFilePath, Modified: false},
+	}
+	gcsClient, err := gcs.NewClient(gcs.WithEnvVars(envVars))
+	if err != nil {
+		log.Entry().Errorf("creation of GCS client failed: %v", err)
+		return
+	}
+	defer gcsClient.Close()
+	structVal := reflect.ValueOf(&stepConfig).Elem()
+	inputParameters := map[string]string{}
+	for i := 0; i < structVal.NumField(); i++ {
+		field := structVal.Type().Field(i)
+		if field.Type.String() == "string" {
+			paramName := strings.Split(field.Tag.Get("json"), ",")
+			paramValue, _ := structVal.Field(i).Interface().(string)
+			inputParameters[paramName[0]] = paramValue
+		}
+	}
+	if err := gcs.PersistReportsToGCS(gcsClient, content, inputParameters, gcsFolderPath, gcsBucketId, gcsSubFolder, doublestar.Glob, os.Stat); err != nil {
+		log.Entry().Errorf("failed to persist reports: %v", err)
+	}
+}
+
+// IacExecuteScanCommand Checks Dockerfiles and infrastructure as code for security misconfigurations before deployment.
+func IacExecuteScanCommand() *cobra.Command {
+	const STEP_NAME = "iacExecuteScan"
+
+	metadata := iacExecuteScanMetadata()
+	var stepConfig iacExecuteScanOptions
+	var startTime time.Time
+	var influx iacExecuteScanInflux
+	var reports iacExecuteScanReports
+	var logCollector *log.CollectorHook
+	var splunkClient *splunk.Splunk
+	telemetryClient := &telemetry.Telemetry{}
+
+	var createIacExecuteScanCmd = &cobra.Command{
+		Use:   STEP_NAME,
+		Short: "Checks Dockerfiles and infrastructure as code for security misconfigurations before deployment.",
+		Long: `This step checks deployment descriptors against built-in policies without calling an external service. Supported are
+
+* Kubernetes manifests, e.g. privileged containers, shared host namespaces, missing resource limits or images using the ` + "`" + `latest` + "`" + ` tag
+* Helm charts, which are rendered via ` + "`" + `helm template` + "`" + ` before they are checked like Kubernetes manifests
+* Terraform plans in JSON format (` + "`" + `terraform show -json` + "`" + `), binary plans created by ` + "`" + `terraformExecute` + "`" + ` are converted via the Terraform CLI
+* Cloud Foundry manifests, e.g. credentials in environment variables or images using the ` + "`" + `latest` + "`" + ` tag
+* Dockerfiles, e.g. base images without fixed tag, containers running as root or credentials in ` + "`" + `ENV` + "`" + ` instructions
+
+The type of a file is detected based on its name and content, files of other types are ignored.
+Additional policies checking arbitrary values of Kubernetes, Terraform or Cloud Foundry resources can be defined via ` + "`" + `policiesFile` + "`" + `.
+The results are provided as HTML report and as SARIF file. The step fails if misconfigurations with at least the severity ` + "`" + `failOnSeverity` + "`" + ` are found.`,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			startTime = time.Now()
+			log.SetStepName(STEP_NAME)
+			log.SetVerbose(GeneralConfig.Verbose)
+
+			GeneralConfig.GitHubAccessTokens = ResolveAccessTokens(GeneralConfig.GitHubTokens)
+
+			path, _ := os.Getwd()
+			fatalHook := &log.FatalHook{CorrelationID: GeneralConfig.CorrelationID, Path: path}
+			log.RegisterHook(fatalHook)
+
+			err := PrepareConfig(cmd, &metadata, STEP_NAME, &stepConfig, config.OpenPiperFile)
+			if err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+
+			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
+				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
+				log.RegisterHook(&sentryHook)
+			}
+
+			if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+				splunkClient = &splunk.Splunk{}
+				logCollector = &log.CollectorHook{CorrelationID: GeneralConfig.CorrelationID}
+				log.RegisterHook(logCollector)
+			}
+
+			if err = log.RegisterANSHookIfConfigured(GeneralConfig.CorrelationID); err != nil {
+				log.Entry().WithError(err).Warn("failed to set up SAP Alert Notification Service log hook")
+			}
+
+			validation, err := validation.New(validation.WithJSONNamesForStructFields(), validation.WithPredefinedErrorMessages())
+			if err != nil {
+				return err
+			}
+			if err = validation.ValidateStruct(stepConfig); err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+
+			return nil
+		},
+		Run: func(_ *cobra.Command, _ []string) {
+			stepTelemetryData := telemetry.CustomData{}
+			stepTelemetryData.ErrorCode = "1"
+			handler := func() {
+				influx.persist(GeneralConfig.EnvRootPath, "influx")
+				reports.persist(stepConfig, GeneralConfig.GCPJsonKeyFilePath, GeneralConfig.GCSBucketId, GeneralConfig.GCSFolderPath, GeneralConfig.GCSSubFolder)
+				config.RemoveVaultSecretFiles()
+				stepTelemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
+				stepTelemetryData.ErrorCategory = log.GetErrorCategory().String()
+				stepTelemetryData.PiperCommitHash = GitCommit
+				telemetryClient.SetData(&stepTelemetryData)
+				telemetryClient.Send()
+				if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+					splunkClient.Send(telemetryClient.GetData(), logCollector)
+				}
+			}
+			log.DeferExitHandler(handler)
+			defer handler()
+			telemetryClient.Initialize(GeneralConfig.NoTelemetry, STEP_NAME)
+			if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+				splunkClient.Initialize(GeneralConfig.CorrelationID,
+					GeneralConfig.HookConfig.SplunkConfig.Dsn,
+					GeneralConfig.HookConfig.SplunkConfig.Token,
+					GeneralConfig.HookConfig.SplunkConfig.Index,
+					GeneralConfig.HookConfig.SplunkConfig.SendLogs)
+			}
+			iacExecuteScan(stepConfig, &stepTelemetryData, &influx)
+			stepTelemetryData.ErrorCode = "0"
+			log.Entry().Info("SUCCESS")
+		},
+	}
+
+	addIacExecuteScanFlags(createIacExecuteScanCmd, &stepConfig)
+	return createIacExecuteScanCmd
+}
+
+func addIacExecuteScanFlags(cmd *cobra.Command, stepConfig *iacExecuteScanOptions) {
+	cmd.Flags().StringSliceVar(&stepConfig.FilePatterns, "filePatterns", []string{`**/Dockerfile*`, `**/*.dockerfile`, `**/*.yaml`, `**/*.yml`, `**/*.json`}, "List of file patterns of the files to be checked.")
+	cmd.Flags().StringSliceVar(&stepConfig.Excludes, "excludes", []string{`**/node_modules/**`, `.pipeline/**`, `iac/**`}, "List of file patterns which are excluded from `filePatterns`.")
+	cmd.Flags().StringSliceVar(&stepConfig.HelmChartPaths, "helmChartPaths", []string{}, "List of paths of Helm charts to be rendered and checked.")
+	cmd.Flags().StringSliceVar(&stepConfig.HelmValues, "helmValues", []string{}, "List of values files used for rendering the Helm charts.")
+	cmd.Flags().StringSliceVar(&stepConfig.TerraformPlanFiles, "terraformPlanFiles", []string{}, "List of binary Terraform plan files, e.g. created via `terraform plan -out=tfplan`, to be converted to JSON and checked.")
+	cmd.Flags().StringVar(&stepConfig.PoliciesFile, "policiesFile", os.Getenv("PIPER_policiesFile"), "Path of a YAML file containing custom policies. Custom policies replace built-in policies with the same id.\nEach policy consists of `id`, `title`, `severity`, `type` (kubernetes, terraform or cloudfoundry), `kinds`, `path`, `condition` (missing, present, equals, notEquals or matches) and `value`.")
+	cmd.Flags().StringSliceVar(&stepConfig.SkipPolicies, "skipPolicies", []string{}, "List of ids of policies which are not checked.")
+	cmd.Flags().StringVar(&stepConfig.FailOnSeverity, "failOnSeverity", `high`, "Minimum severity of findings which fails the step, `none` only reports the findings.")
+
+}
+
+// retrieve step metadata
+func iacExecuteScanMetadata() config.StepData {
+	var theMetaData = config.StepData{
+		Metadata: config.StepMetadata{
+			Name:        "iacExecuteScan",
+			Aliases:     []config.Alias{},
+			Description: "Checks Dockerfiles and infrastructure as code for security misconfigurations before deployment.",
+		},
+		Spec: config.StepSpec{
+			Inputs: config.StepInputs{
+				Parameters: []config.StepParameters{
+					{
+						Name:        "filePatterns",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{`**/Dockerfile*`, `**/*.dockerfile`, `**/*.yaml`, `**/*.yml`, `**/*.json`},
+					},
+					{
+						Name:        "excludes",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{`**/node_modules/**`, `.pipeline/**`, `iac/**`},
+					},
+					{
+						Name:        "helmChartPaths",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{},
+					},
+					{
+						Name:        "helmValues",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{},
+					},
+					{
+						Name:        "terraformPlanFiles",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{},
+					},
+					{
+						Name:        "policiesFile",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_policiesFile"),
+					},
+					{
+						Name:        "skipPolicies",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{},
+					},
+					{
+						Name:        "failOnSeverity",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `high`,
+					},
+				},
+			},
+			Outputs: config.StepOutputs{
+				Resources: []config.StepResources{
+					{
+						Name: "influx",
+						Type: "influx",
+						Parameters: []map[string]interface{}{
+							{"name": "iac_data", "fields": []map[string]string{{"name": "findings"}, {"name": "severe_findings"}}},
+						},
+					},
+					{
+						Name: "reports",
+						Type: "reports",
+						Parameters: []map[string]interface{}{
+							{"filePattern": "**/piper_iac_report.html", "type": "iac"},
+							{"filePattern": "**/piper_iac.sarif", "type": "iac"},
+						},
+					},
+				},
+			},
+		},
+	}
+	return theMetaData
+}
@@ -0,0 +1,17 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIacExecuteScanCommand(t *testing.T) {
+	t.Parallel()
+
+	testCmd := IacExecuteScanCommand()
+
+	// only high level testing performed - details are tested in step generation procedure
+	assert.Equal(t, "iacExecuteScan", testCmd.Use, "command name incorrect")
+
+}
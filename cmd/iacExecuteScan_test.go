@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+type iacExecuteScanMockUtils struct {
+	*mock.ExecMockRunner
+	*mock.FilesMock
+}
+
+const iacTestDockerfile = `FROM alpine:3.17
+COPY app /app
+USER app
+`
+
+const iacTestDeployment = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+spec:
+  template:
+    spec:
+      containers:
+        - name: app
+          image: registry.example.com/app:1.0.0
+          securityContext:
+            allowPrivilegeEscalation: false
+            readOnlyRootFilesystem: true
+            runAsNonRoot: true
+          resources:
+            limits:
+              cpu: 100m
+              memory: 64Mi
+`
+
+const iacTestRenderedChart = `---
+# Source: app/templates/pod.yaml
+apiVersion: v1
+kind: Pod
+metadata:
+  name: debug
+spec:
+  hostPID: true
+  containers:
+    - name: debug
+      image: busybox
+      securityContext:
+        privileged: true
+`
+
+const iacTestPlan = `{"planned_values": {"root_module": {"resources": [
+  {"address": "aws_db_instance.main", "mode": "managed", "type": "aws_db_instance", "values": {"publicly_accessible": true, "storage_encrypted": true}}
+]}}}`
+
+func newIacExecuteScanTestsUtils() iacExecuteScanMockUtils {
+	utils := iacExecuteScanMockUtils{
+		ExecMockRunner: &mock.ExecMockRunner{},
+		FilesMock:      &mock.FilesMock{},
+	}
+	utils.AddFile("Dockerfile", []byte(iacTestDockerfile))
+	utils.AddFile("k8s/deployment.yaml", []byte(iacTestDeployment))
+	utils.AddFile("package.json", []byte(`{"name": "app"}`))
+	return utils
+}
+
+func defaultIacExecuteScanOptions() iacExecuteScanOptions {
+	return iacExecuteScanOptions{
+		FilePatterns:   []string{"**/Dockerfile*", "**/*.yaml", "**/*.json"},
+		Excludes:       []string{"**/node_modules/**", ".pipeline/**", "iac/**"},
+		FailOnSeverity: "high",
+	}
+}
+
+func TestRunIacExecuteScan(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no misconfigurations", func(t *testing.T) {
+		t.Parallel()
+		config := defaultIacExecuteScanOptions()
+		utils := newIacExecuteScanTestsUtils()
+		influx := iacExecuteScanInflux{}
+
+		err := runIacExecuteScan(&config, utils, &influx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 0, influx.iac_data.fields.findings)
+		assert.True(t, utils.HasWrittenFile("iac/piper_iac_report.html"))
+		assert.True(t, utils.HasWrittenFile("iac/piper_iac.sarif"))
+	})
+
+	t.Run("misconfigurations below threshold", func(t *testing.T) {
+		t.Parallel()
+		config := defaultIacExecuteScanOptions()
+		utils := newIacExecuteScanTestsUtils()
+		utils.AddFile("build/Dockerfile.dev", []byte("FROM alpine\n"))
+		influx := iacExecuteScanInflux{}
+
+		err := runIacExecuteScan(&config, utils, &influx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, influx.iac_data.fields.findings)
+		assert.Equal(t, 0, influx.iac_data.fields.severe_findings)
+	})
+
+	t.Run("misconfigurations above threshold", func(t *testing.T) {
+		t.Parallel()
+		config := defaultIacExecuteScanOptions()
+		config.FailOnSeverity = "medium"
+		utils := newIacExecuteScanTestsUtils()
+		utils.AddFile("build/Dockerfile.dev", []byte("FROM alpine\n"))
+		influx := iacExecuteScanInflux{}
+
+		err := runIacExecuteScan(&config, utils, &influx)
+
+		assert.EqualError(t, err, "2 misconfiguration(s) with severity medium or higher found")
+	})
+
+	t.Run("Helm chart and Terraform plan", func(t *testing.T) {
+		t.Parallel()
+		config := defaultIacExecuteScanOptions()
+		config.HelmChartPaths = []string{"charts/app"}
+		config.HelmValues = []string{"values-prod.yaml"}
+		config.TerraformPlanFiles = []string{"tfplan"}
+		config.SkipPolicies = []string{"K8S002", "K8S003", "K8S004", "K8S006", "K8S007"}
+		utils := newIacExecuteScanTestsUtils()
+		utils.StdoutReturn = map[string]string{
+			"helm template app charts/app --values values-prod.yaml": iacTestRenderedChart,
+			"terraform show -json tfplan":                            iacTestPlan,
+		}
+		influx := iacExecuteScanInflux{}
+
+		err := runIacExecuteScan(&config, utils, &influx)
+
+		assert.EqualError(t, err, "3 misconfiguration(s) with severity high or higher found")
+		assert.Equal(t, 3, influx.iac_data.fields.severe_findings)
+		sarif, _ := utils.FileRead("iac/piper_iac.sarif")
+		assert.Contains(t, string(sarif), `"uri":"charts/app/templates/pod.yaml"`)
+		assert.Contains(t, string(sarif), `"ruleId":"TF003"`)
+	})
+
+	t.Run("Helm chart rendering fails", func(t *testing.T) {
+		t.Parallel()
+		config := defaultIacExecuteScanOptions()
+		config.HelmChartPaths = []string{"charts/app"}
+		utils := newIacExecuteScanTestsUtils()
+		utils.ShouldFailOnCommand = map[string]error{"helm template": fmt.Errorf("chart not found")}
+
+		err := runIacExecuteScan(&config, utils, &iacExecuteScanInflux{})
+
+		assert.EqualError(t, err, "failed to render Helm chart 'charts/app': chart not found")
+	})
+
+	t.Run("custom policies", func(t *testing.T) {
+		t.Parallel()
+		config := defaultIacExecuteScanOptions()
+		config.PoliciesFile = "policies.yaml"
+		utils := newIacExecuteScanTestsUtils()
+		utils.AddFile("policies.yaml", []byte(`policies:
+  - id: ORG001
+    title: Team label missing
+    severity: high
+    type: kubernetes
+    kinds: [Deployment]
+    path: metadata.labels.team
+    condition: missing
+`))
+		influx := iacExecuteScanInflux{}
+
+		err := runIacExecuteScan(&config, utils, &influx)
+
+		assert.EqualError(t, err, "1 misconfiguration(s) with severity high or higher found")
+	})
+
+	t.Run("invalid custom policies", func(t *testing.T) {
+		t.Parallel()
+		config := defaultIacExecuteScanOptions()
+		config.PoliciesFile = "policies.yaml"
+		utils := newIacExecuteScanTestsUtils()
+		utils.AddFile("policies.yaml", []byte("policies:\n  - id: ORG001\n    severity: critical\n"))
+
+		err := runIacExecuteScan(&config, utils, &iacExecuteScanInflux{})
+
+		assert.EqualError(t, err, "failed to read policies file 'policies.yaml': invalid severity 'critical' of policy 'ORG001', supported are low, medium and high")
+	})
+}
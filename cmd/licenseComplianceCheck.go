@@ -0,0 +1,207 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/license"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/reporting"
+	"github.com/SAP/jenkins-library/pkg/sbom"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/pkg/errors"
+)
+
+const licenseReportsDirectory = "license"
+
+type licenseComplianceCheckUtils interface {
+	piperutils.FileUtils
+	piperhttp.Sender
+}
+
+type licenseComplianceCheckUtilsBundle struct {
+	*piperhttp.Client
+	*piperutils.Files
+}
+
+func newLicenseComplianceCheckUtils(config *licenseComplianceCheckOptions) licenseComplianceCheckUtils {
+	utils := licenseComplianceCheckUtilsBundle{
+		Client: &piperhttp.Client{},
+		Files:  &piperutils.Files{},
+	}
+	utils.SetOptions(piperhttp.ClientOptions{TrustedCerts: config.CustomTLSCertificateLinks})
+	return &utils
+}
+
+func licenseComplianceCheck(config licenseComplianceCheckOptions, telemetryData *telemetry.CustomData) {
+	utils := newLicenseComplianceCheckUtils(&config)
+
+	err := runLicenseComplianceCheck(&config, utils)
+	if err != nil {
+		log.Entry().WithError(err).Fatal("step execution failed")
+	}
+}
+
+func runLicenseComplianceCheck(config *licenseComplianceCheckOptions, utils licenseComplianceCheckUtils) error {
+	policy, err := readLicensePolicy(config, utils)
+	if err != nil {
+		return err
+	}
+
+	bomFiles, err := findLicenseBomFiles(config, utils)
+	if err != nil {
+		return err
+	}
+	if len(bomFiles) == 0 {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return fmt.Errorf("no BOM files found matching the patterns %v", config.BomFilePatterns)
+	}
+
+	results := []license.Result{}
+	found := map[string]bool{}
+	for _, bomFile := range bomFiles {
+		log.Entry().Infof("Reading BOM file '%v'", bomFile)
+		bom, err := sbom.ReadBOM(bomFile, utils)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return err
+		}
+		// components contained in several BOMs, e.g. in the product BOM and the BOM of a module, are only listed once
+		for _, result := range policy.EvaluateBOM(bom) {
+			key := result.PackageURL
+			if len(key) == 0 {
+				key = result.Name + "@" + result.Version
+			}
+			if !found[key] {
+				found[key] = true
+				results = append(results, result)
+			}
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Name < results[j].Name
+	})
+
+	for _, result := range results {
+		switch result.Decision {
+		case license.Denied:
+			log.Entry().Errorf("Component %v %v: %v", result.Name, result.Version, result.Reason)
+		case license.Review:
+			log.Entry().Warningf("Component %v %v: %v", result.Name, result.Version, result.Reason)
+		}
+	}
+
+	reportPaths, err := writeLicenseReports(config, bomFiles, results, utils)
+	if err != nil {
+		return err
+	}
+	piperutils.PersistReportsAndLinks("licenseComplianceCheck", "./", utils, reportPaths, nil)
+
+	denied := license.Count(results, license.Denied)
+	review := license.Count(results, license.Review)
+	log.Entry().Infof("%v components evaluated: %v denied, %v to be reviewed", len(results), denied, review)
+	if denied > 0 && config.FailOnDeniedLicenses {
+		log.SetErrorCategory(log.ErrorCompliance)
+		return fmt.Errorf("%v component(s) with denied licenses found", denied)
+	}
+	if review > 0 && config.FailOnReviewLicenses {
+		log.SetErrorCategory(log.ErrorCompliance)
+		return fmt.Errorf("%v component(s) with licenses requiring a review found", review)
+	}
+	return nil
+}
+
+// licenseDefaultPolicyFile is used if it exists and no policy file is configured
+const licenseDefaultPolicyFile = ".pipeline/license-policy.yaml"
+
+func readLicensePolicy(config *licenseComplianceCheckOptions, utils licenseComplianceCheckUtils) (*license.Policy, error) {
+	policy := &license.Policy{}
+	policyFile := config.PolicyFile
+	if len(policyFile) == 0 {
+		if exists, _ := utils.FileExists(licenseDefaultPolicyFile); exists {
+			policyFile = licenseDefaultPolicyFile
+		}
+	}
+	content, err := readLicensePolicyContent(policyFile, utils)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return nil, err
+	}
+	if content != nil {
+		if policy, err = license.ReadPolicy(content); err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return nil, errors.Wrapf(err, "failed to read license policy '%v'", policyFile)
+		}
+	}
+
+	policy.Merge(license.Policy{
+		Allow:   config.AllowedLicenses,
+		Deny:    config.DeniedLicenses,
+		Review:  config.ReviewLicenses,
+		Unknown: license.Decision(config.UnknownLicenses),
+	})
+	if err := policy.Validate(); err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return nil, err
+	}
+	if len(policy.Allow) == 0 && len(policy.Deny) == 0 && len(policy.Review) == 0 {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return nil, fmt.Errorf("no license policy available, please provide a policyFile or configure allowedLicenses, deniedLicenses or reviewLicenses")
+	}
+	return policy, nil
+}
+
+// readLicensePolicyContent downloads or reads the policy, nil is returned if no policy file is given
+func readLicensePolicyContent(policyFile string, utils licenseComplianceCheckUtils) ([]byte, error) {
+	if len(policyFile) == 0 {
+		return nil, nil
+	}
+	if strings.HasPrefix(policyFile, "http://") || strings.HasPrefix(policyFile, "https://") {
+		log.Entry().Infof("Downloading license policy from '%v'", policyFile)
+		response, err := utils.SendRequest(http.MethodGet, policyFile, nil, nil, nil)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to download license policy from '%v'", policyFile)
+		}
+		defer response.Body.Close()
+		content, err := io.ReadAll(response.Body)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read license policy from '%v'", policyFile)
+		}
+		return content, nil
+	}
+	content, err := utils.FileRead(policyFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read license policy '%v'", policyFile)
+	}
+	return content, nil
+}
+
+func findLicenseBomFiles(config *licenseComplianceCheckOptions, utils licenseComplianceCheckUtils) ([]string, error) {
+	return piperutils.FindFiles(config.BomFilePatterns, config.Excludes, utils)
+}
+
+func writeLicenseReports(config *licenseComplianceCheckOptions, bomFiles []string, results []license.Result, utils licenseComplianceCheckUtils) ([]piperutils.Path, error) {
+	reportPaths, err := reporting.WriteScanReports(license.CreateScanReport(bomFiles, results), reporting.ScanReportFiles{
+		Directory: licenseReportsDirectory,
+		Name:      "License Compliance",
+		HTMLFile:  "piper_license_compliance_report.html",
+		JSONFile:  "licenseComplianceCheck_license.json",
+	}, utils)
+	if err != nil {
+		return reportPaths, err
+	}
+
+	if len(config.NoticeFile) > 0 {
+		if err := utils.FileWrite(config.NoticeFile, license.CreateNotice(config.ProductName, results), 0666); err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return reportPaths, errors.Wrapf(err, "failed to write attribution notice '%v'", config.NoticeFile)
+		}
+		reportPaths = append(reportPaths, piperutils.Path{Name: "Attribution Notice", Target: config.NoticeFile})
+	}
+	return reportPaths, nil
+}
@@ -0,0 +1,356 @@
+// Code generated by piper's step-generator. DO NOT EDIT.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/gcs"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/splunk"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/SAP/jenkins-library/pkg/validation"
+	"github.com/bmatcuk/doublestar"
+	"github.com/spf13/cobra"
+)
+
+type licenseComplianceCheckOptions struct {
+	BomFilePatterns           []string `json:"bomFilePatterns,omitempty"`
+	Excludes                  []string `json:"excludes,omitempty"`
+	PolicyFile                string   `json:"policyFile,omitempty"`
+	AllowedLicenses           []string `json:"allowedLicenses,omitempty"`
+	DeniedLicenses            []string `json:"deniedLicenses,omitempty"`
+	ReviewLicenses            []string `json:"reviewLicenses,omitempty"`
+	UnknownLicenses           string   `json:"unknownLicenses,omitempty" validate:"possible-values=allow review deny"`
+	FailOnDeniedLicenses      bool     `json:"failOnDeniedLicenses,omitempty"`
+	FailOnReviewLicenses      bool     `json:"failOnReviewLicenses,omitempty"`
+	NoticeFile                string   `json:"noticeFile,omitempty"`
+	ProductName               string   `json:"productName,omitempty"`
+	CustomTLSCertificateLinks []string `json:"customTlsCertificateLinks,omitempty"`
+}
+
+type licenseComplianceCheckReports struct {
+}
+
+func (p *licenseComplianceCheckReports) persist(stepConfig licenseComplianceCheckOptions, gcpJsonKeyFilePath string, gcsBucketId string, gcsFolderPath string, gcsSubFolder string) {
+	if gcsBucketId == "" {
+		log.Entry().Info("persisting reports to GCS is disabled, because gcsBucketId is empty")
+		return
+	}
+	log.Entry().Info("Uploading reports to Google Cloud Storage...")
+	content := []gcs.ReportOutputParam{
+		{FilePattern: "**/piper_license_compliance_report.html", ParamRef: "", StepResultType: "license"},
+		{FilePattern: "NOTICE", ParamRef: "", StepResultType: "license"},
+	}
+	envVars := []gcs.EnvVar{
+		{Name: "GOOGLE_APPLICATION_CREDENTIALS", Value: gcpJsonKeyFilePath, Modified: false},
+	}
+	gcsClient, err := gcs.NewClient(gcs.WithEnvVars(envVars))
+	if err != nil {
+		log.Entry().Errorf("creation of GCS client failed: %v", err)
+		return
+	}
+	defer gcsClient.Close()
+	structVal := reflect.ValueOf(&stepConfig).Elem()
+	inputParameters := map[string]string{}
+	for i := 0; i < structVal.NumField(); i++ {
+		field := structVal.Type().Field(i)
+		if field.Type.String() == "string" {
+			paramName := strings.Split(field.Tag.Get("json"), ",")
+			paramValue, _ := structVal.Field(i).Interface().(string)
+			inputParameters[paramName[0]] = paramValue
+		}
+	}
+	if err := gcs.PersistReportsToGCS(gcsClient, content, inputParameters, gcsFolderPath, gcsBucketId, gcsSubFolder, doublestar.Glob, os.Stat); err != nil {
+		log.Entry().Errorf("failed to persist reports: %v", err)
+	}
+}
+
+// LicenseComplianceCheckCommand Evaluates the licenses of the components listed in software bills of materials (SBOM) against a license policy.
+func LicenseComplianceCheckCommand() *cobra.Command {
+	const STEP_NAME = "licenseComplianceCheck"
+
+	metadata := licenseComplianceCheckMetadata()
+	var stepConfig licenseComplianceCheckOptions
+	var startTime time.Time
+	var reports licenseComplianceCheckReports
+	var logCollector *log.CollectorHook
+	var splunkClient *splunk.Splunk
+	telemetryClient := &telemetry.Telemetry{}
+
+	var createLicenseComplianceCheckCmd = &cobra.Command{
+		Use:   STEP_NAME,
+		Short: "Evaluates the licenses of the components listed in software bills of materials (SBOM) against a license policy.",
+		Long: `This step checks the licenses of all components listed in CycloneDX or SPDX BOMs (e.g. created by ` + "`" + `sbomProcess` + "`" + ` or the build steps)
+against a license policy independent of the scanner used to create the BOMs.
+
+License names, URLs and deprecated ids are normalized to SPDX ids and license expressions are evaluated:
+for ` + "`" + `OR` + "`" + ` expressions the most permissive license is taken into account, for ` + "`" + `AND` + "`" + ` expressions all licenses need to be acceptable.
+Each component is either allowed, needs to be reviewed or is denied.
+
+The policy is read from ` + "`" + `policyFile` + "`" + `, which can be a local file or a URL, e.g. of a central policy maintained by the legal department:
+
+` + "`" + `` + "`" + `` + "`" + `yaml
+allow:
+  - MIT
+  - Apache-2.0
+  - BSD-*
+deny:
+  - GPL-*
+  - AGPL-*
+review:
+  - LGPL-*
+# decision for licenses not covered by the lists and for components without license information
+unknown: review
+exceptions:
+  - component: pkg:npm/legacy-lib          # package URL, all versions if version is omitted, wildcards are supported
+    licenses:                              # licenses allowed for the component, all if omitted
+      - GPL-3.0-only
+    reason: approved by legal
+  - component: pkg:maven/com.sap.internal/*    # all artifacts of the group
+  - component: pkg:golang/github.com/sap/**   # all modules and packages below github.com/sap
+` + "`" + `` + "`" + `` + "`" + `
+
+The ` + "`" + `component` + "`" + ` of an exception is matched against the package URL of a component without its qualifiers (` + "`" + `?...` + "`" + `) and subpath (` + "`" + `#...` + "`" + `),
+i.e. ` + "`" + `pkg:npm/legacy-lib` + "`" + ` also matches ` + "`" + `pkg:npm/legacy-lib@1.0.0?repository_url=...` + "`" + `.
+If the version is omitted, the exception applies to all versions of the component.
+` + "`" + `*` + "`" + ` matches any characters within a path segment of the package URL and ` + "`" + `**` + "`" + ` matches any characters across path segments.
+
+Entries of ` + "`" + `allowedLicenses` + "`" + `, ` + "`" + `deniedLicenses` + "`" + ` and ` + "`" + `reviewLicenses` + "`" + ` are added to the policy.
+The results are provided as step report and an attribution notice listing all components with their licenses is written to ` + "`" + `noticeFile` + "`" + `.`,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			startTime = time.Now()
+			log.SetStepName(STEP_NAME)
+			log.SetVerbose(GeneralConfig.Verbose)
+
+			GeneralConfig.GitHubAccessTokens = ResolveAccessTokens(GeneralConfig.GitHubTokens)
+
+			path, _ := os.Getwd()
+			fatalHook := &log.FatalHook{CorrelationID: GeneralConfig.CorrelationID, Path: path}
+			log.RegisterHook(fatalHook)
+
+			err := PrepareConfig(cmd, &metadata, STEP_NAME, &stepConfig, config.OpenPiperFile)
+			if err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+
+			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
+				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
+				log.RegisterHook(&sentryHook)
+			}
+
+			if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+				splunkClient = &splunk.Splunk{}
+				logCollector = &log.CollectorHook{CorrelationID: GeneralConfig.CorrelationID}
+				log.RegisterHook(logCollector)
+			}
+
+			if err = log.RegisterANSHookIfConfigured(GeneralConfig.CorrelationID); err != nil {
+				log.Entry().WithError(err).Warn("failed to set up SAP Alert Notification Service log hook")
+			}
+
+			validation, err := validation.New(validation.WithJSONNamesForStructFields(), validation.WithPredefinedErrorMessages())
+			if err != nil {
+				return err
+			}
+			if err = validation.ValidateStruct(stepConfig); err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+
+			return nil
+		},
+		Run: func(_ *cobra.Command, _ []string) {
+			stepTelemetryData := telemetry.CustomData{}
+			stepTelemetryData.ErrorCode = "1"
+			handler := func() {
+				reports.persist(stepConfig, GeneralConfig.GCPJsonKeyFilePath, GeneralConfig.GCSBucketId, GeneralConfig.GCSFolderPath, GeneralConfig.GCSSubFolder)
+				config.RemoveVaultSecretFiles()
+				stepTelemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
+				stepTelemetryData.ErrorCategory = log.GetErrorCategory().String()
+				stepTelemetryData.PiperCommitHash = GitCommit
+				telemetryClient.SetData(&stepTelemetryData)
+				telemetryClient.Send()
+				if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+					splunkClient.Send(telemetryClient.GetData(), logCollector)
+				}
+			}
+			log.DeferExitHandler(handler)
+			defer handler()
+			telemetryClient.Initialize(GeneralConfig.NoTelemetry, STEP_NAME)
+			if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+				splunkClient.Initialize(GeneralConfig.CorrelationID,
+					GeneralConfig.HookConfig.SplunkConfig.Dsn,
+					GeneralConfig.HookConfig.SplunkConfig.Token,
+					GeneralConfig.HookConfig.SplunkConfig.Index,
+					GeneralConfig.HookConfig.SplunkConfig.SendLogs)
+			}
+			licenseComplianceCheck(stepConfig, &stepTelemetryData)
+			stepTelemetryData.ErrorCode = "0"
+			log.Entry().Info("SUCCESS")
+		},
+	}
+
+	addLicenseComplianceCheckFlags(createLicenseComplianceCheckCmd, &stepConfig)
+	return createLicenseComplianceCheckCmd
+}
+
+func addLicenseComplianceCheckFlags(cmd *cobra.Command, stepConfig *licenseComplianceCheckOptions) {
+	cmd.Flags().StringSliceVar(&stepConfig.BomFilePatterns, "bomFilePatterns", []string{`**/bom-*.xml`, `**/bom-*.json`, `product-bom*`}, "List of file patterns of the BOMs to be evaluated. CycloneDX XML/JSON and SPDX JSON files are supported.")
+	cmd.Flags().StringSliceVar(&stepConfig.Excludes, "excludes", []string{`**/node_modules/**`}, "List of file patterns which are excluded from `bomFilePatterns`.")
+	cmd.Flags().StringVar(&stepConfig.PolicyFile, "policyFile", os.Getenv("PIPER_policyFile"), "Path or URL of the license policy. The step fails if the configured policy cannot be read.\nIf no policy is configured, `.pipeline/license-policy.yaml` is used if it exists, otherwise only the licenses configured via the step parameters are evaluated.")
+	cmd.Flags().StringSliceVar(&stepConfig.AllowedLicenses, "allowedLicenses", []string{}, "SPDX ids of licenses which are allowed in addition to the licenses allowed by the policy. Wildcards are supported, e.g. `BSD-*`.")
+	cmd.Flags().StringSliceVar(&stepConfig.DeniedLicenses, "deniedLicenses", []string{}, "SPDX ids of licenses which are denied in addition to the licenses denied by the policy. Wildcards are supported, e.g. `GPL-*`.")
+	cmd.Flags().StringSliceVar(&stepConfig.ReviewLicenses, "reviewLicenses", []string{}, "SPDX ids of licenses which need to be reviewed in addition to the licenses listed by the policy. Wildcards are supported.")
+	cmd.Flags().StringVar(&stepConfig.UnknownLicenses, "unknownLicenses", os.Getenv("PIPER_unknownLicenses"), "Decision for licenses not covered by the policy and for components without license information. Overrides the decision of the policy.")
+	cmd.Flags().BoolVar(&stepConfig.FailOnDeniedLicenses, "failOnDeniedLicenses", true, "Whether the step fails in case components with denied licenses are found.")
+	cmd.Flags().BoolVar(&stepConfig.FailOnReviewLicenses, "failOnReviewLicenses", false, "Whether the step fails in case components with licenses requiring a review are found.")
+	cmd.Flags().StringVar(&stepConfig.NoticeFile, "noticeFile", `NOTICE`, "Path of the attribution notice listing all components with their licenses and copyright notices.")
+	cmd.Flags().StringVar(&stepConfig.ProductName, "productName", os.Getenv("PIPER_productName"), "Name of the product used as title of the attribution notice.")
+	cmd.Flags().StringSliceVar(&stepConfig.CustomTLSCertificateLinks, "customTlsCertificateLinks", []string{}, "List of download links to custom TLS certificates. This is required to ensure trusted connections when downloading the policy.")
+
+}
+
+// retrieve step metadata
+func licenseComplianceCheckMetadata() config.StepData {
+	var theMetaData = config.StepData{
+		Metadata: config.StepMetadata{
+			Name:        "licenseComplianceCheck",
+			Aliases:     []config.Alias{},
+			Description: "Evaluates the licenses of the components listed in software bills of materials (SBOM) against a license policy.",
+		},
+		Spec: config.StepSpec{
+			Inputs: config.StepInputs{
+				Parameters: []config.StepParameters{
+					{
+						Name:        "bomFilePatterns",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{`**/bom-*.xml`, `**/bom-*.json`, `product-bom*`},
+					},
+					{
+						Name:        "excludes",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{`**/node_modules/**`},
+					},
+					{
+						Name:        "policyFile",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_policyFile"),
+					},
+					{
+						Name:        "allowedLicenses",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{},
+					},
+					{
+						Name:        "deniedLicenses",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{},
+					},
+					{
+						Name:        "reviewLicenses",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{},
+					},
+					{
+						Name:        "unknownLicenses",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_unknownLicenses"),
+					},
+					{
+						Name:        "failOnDeniedLicenses",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     true,
+					},
+					{
+						Name:        "failOnReviewLicenses",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name:        "noticeFile",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `NOTICE`,
+					},
+					{
+						Name:        "productName",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_productName"),
+					},
+					{
+						Name:        "customTlsCertificateLinks",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{},
+					},
+				},
+			},
+			Outputs: config.StepOutputs{
+				Resources: []config.StepResources{
+					{
+						Name: "reports",
+						Type: "reports",
+						Parameters: []map[string]interface{}{
+							{"filePattern": "**/piper_license_compliance_report.html", "type": "license"},
+							{"filePattern": "NOTICE", "type": "license"},
+						},
+					},
+				},
+			},
+		},
+	}
+	return theMetaData
+}
@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+type licenseComplianceCheckMockUtils struct {
+	*mock.FilesMock
+	responses map[string]string
+}
+
+func (l *licenseComplianceCheckMockUtils) SetOptions(options piperhttp.ClientOptions) {}
+
+func (l *licenseComplianceCheckMockUtils) SendRequest(method string, url string, r io.Reader, header http.Header, cookies []*http.Cookie) (*http.Response, error) {
+	content, ok := l.responses[url]
+	if !ok {
+		return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(bytes.NewReader([]byte{}))}, fmt.Errorf("request to %v returned with response 404 Not Found", url)
+	}
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader([]byte(content)))}, nil
+}
+
+const licenseTestBom = `{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.4",
+  "version": 1,
+  "components": [
+    {"type": "library", "name": "lodash", "version": "4.17.21", "purl": "pkg:npm/lodash@4.17.21", "licenses": [{"license": {"id": "MIT"}}], "copyright": "Copyright OpenJS Foundation"},
+    {"type": "library", "name": "readline", "version": "1.0.0", "purl": "pkg:npm/readline@1.0.0", "licenses": [{"expression": "GPL-3.0"}]},
+    {"type": "library", "name": "chardet", "version": "1.0.0", "purl": "pkg:npm/chardet@1.0.0", "licenses": [{"license": {"name": "GNU Lesser General Public License v2.1"}}]}
+  ]
+}`
+
+const licenseTestPolicy = `allow:
+  - MIT
+  - Apache-2.0
+deny:
+  - GPL-*
+review:
+  - LGPL-*
+`
+
+func newLicenseComplianceCheckTestsUtils() *licenseComplianceCheckMockUtils {
+	utils := licenseComplianceCheckMockUtils{
+		FilesMock: &mock.FilesMock{},
+		responses: map[string]string{},
+	}
+	utils.AddFile("bom-npm.json", []byte(licenseTestBom))
+	utils.AddFile(".pipeline/license-policy.yaml", []byte(licenseTestPolicy))
+	return &utils
+}
+
+func defaultLicenseComplianceCheckOptions() licenseComplianceCheckOptions {
+	return licenseComplianceCheckOptions{
+		BomFilePatterns:      []string{"**/bom-*.json"},
+		PolicyFile:           ".pipeline/license-policy.yaml",
+		FailOnDeniedLicenses: true,
+		NoticeFile:           "NOTICE",
+		ProductName:          "My Product",
+	}
+}
+
+func TestRunLicenseComplianceCheck(t *testing.T) {
+	t.Parallel()
+
+	t.Run("denied licenses", func(t *testing.T) {
+		t.Parallel()
+		config := defaultLicenseComplianceCheckOptions()
+		utils := newLicenseComplianceCheckTestsUtils()
+
+		err := runLicenseComplianceCheck(&config, utils)
+
+		assert.EqualError(t, err, "1 component(s) with denied licenses found")
+		assert.True(t, utils.HasWrittenFile("license/piper_license_compliance_report.html"))
+		notice, err := utils.FileRead("NOTICE")
+		assert.NoError(t, err)
+		assert.Contains(t, string(notice), "My Product")
+		assert.Contains(t, string(notice), "lodash 4.17.21\nPackage URL: pkg:npm/lodash@4.17.21\nLicense: MIT\nCopyright: Copyright OpenJS Foundation\n")
+		assert.Contains(t, string(notice), "chardet 1.0.0\nPackage URL: pkg:npm/chardet@1.0.0\nLicense: LGPL-2.1-only\n")
+	})
+
+	t.Run("licenses requiring review", func(t *testing.T) {
+		t.Parallel()
+		config := defaultLicenseComplianceCheckOptions()
+		config.AllowedLicenses = []string{"GPL-3.0-only"}
+		config.FailOnReviewLicenses = true
+		utils := newLicenseComplianceCheckTestsUtils()
+		utils.AddFile(".pipeline/license-policy.yaml", []byte("allow: [MIT]\nreview: [LGPL-*]\nexceptions:\n  - component: pkg:npm/readline\n"))
+
+		err := runLicenseComplianceCheck(&config, utils)
+
+		assert.EqualError(t, err, "1 component(s) with licenses requiring a review found")
+	})
+
+	t.Run("policy from URL", func(t *testing.T) {
+		t.Parallel()
+		config := defaultLicenseComplianceCheckOptions()
+		config.PolicyFile = "https://legal.example.com/license-policy.yaml"
+		utils := newLicenseComplianceCheckTestsUtils()
+		utils.responses[config.PolicyFile] = "allow: ['*']"
+
+		err := runLicenseComplianceCheck(&config, utils)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("default policy file", func(t *testing.T) {
+		t.Parallel()
+		config := defaultLicenseComplianceCheckOptions()
+		config.PolicyFile = ""
+		utils := newLicenseComplianceCheckTestsUtils()
+
+		err := runLicenseComplianceCheck(&config, utils)
+
+		assert.EqualError(t, err, "1 component(s) with denied licenses found")
+	})
+
+	t.Run("policy from step parameters", func(t *testing.T) {
+		t.Parallel()
+		config := defaultLicenseComplianceCheckOptions()
+		config.PolicyFile = ""
+		config.AllowedLicenses = []string{"MIT", "GPL-3.0-only"}
+		config.UnknownLicenses = "allow"
+		utils := newLicenseComplianceCheckTestsUtils()
+		assert.NoError(t, utils.FileRemove(".pipeline/license-policy.yaml"))
+
+		err := runLicenseComplianceCheck(&config, utils)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("no policy", func(t *testing.T) {
+		t.Parallel()
+		config := defaultLicenseComplianceCheckOptions()
+		config.PolicyFile = ""
+		utils := newLicenseComplianceCheckTestsUtils()
+		assert.NoError(t, utils.FileRemove(".pipeline/license-policy.yaml"))
+
+		err := runLicenseComplianceCheck(&config, utils)
+
+		assert.EqualError(t, err, "no license policy available, please provide a policyFile or configure allowedLicenses, deniedLicenses or reviewLicenses")
+	})
+
+	t.Run("configured policy file not readable", func(t *testing.T) {
+		t.Parallel()
+		config := defaultLicenseComplianceCheckOptions()
+		config.PolicyFile = "not-existing.yaml"
+		config.AllowedLicenses = []string{"MIT", "GPL-3.0-only"}
+		utils := newLicenseComplianceCheckTestsUtils()
+
+		err := runLicenseComplianceCheck(&config, utils)
+
+		assert.Contains(t, fmt.Sprint(err), "failed to read license policy 'not-existing.yaml'")
+	})
+
+	t.Run("policy download fails", func(t *testing.T) {
+		t.Parallel()
+		config := defaultLicenseComplianceCheckOptions()
+		config.PolicyFile = "https://legal.example.com/missing.yaml"
+		utils := newLicenseComplianceCheckTestsUtils()
+
+		err := runLicenseComplianceCheck(&config, utils)
+
+		assert.Contains(t, err.Error(), "failed to download license policy from 'https://legal.example.com/missing.yaml'")
+	})
+
+	t.Run("invalid policy", func(t *testing.T) {
+		t.Parallel()
+		config := defaultLicenseComplianceCheckOptions()
+		utils := newLicenseComplianceCheckTestsUtils()
+		utils.AddFile(".pipeline/license-policy.yaml", []byte("unknown: ignore"))
+
+		err := runLicenseComplianceCheck(&config, utils)
+
+		assert.EqualError(t, err, "failed to read license policy '.pipeline/license-policy.yaml': invalid decision 'ignore' for unknown licenses, supported are allow, review and deny")
+	})
+
+	t.Run("no BOM files", func(t *testing.T) {
+		t.Parallel()
+		config := defaultLicenseComplianceCheckOptions()
+		config.BomFilePatterns = []string{"**/sbom.xml"}
+		utils := newLicenseComplianceCheckTestsUtils()
+
+		err := runLicenseComplianceCheck(&config, utils)
+
+		assert.EqualError(t, err, "no BOM files found matching the patterns [**/sbom.xml]")
+	})
+}
@@ -3,20 +3,27 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
 	piperDocker "github.com/SAP/jenkins-library/pkg/docker"
 	piperhttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/malwarescan"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/reporting"
+	"github.com/SAP/jenkins-library/pkg/scancache"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 	"github.com/SAP/jenkins-library/pkg/toolrecord"
 	"github.com/pkg/errors"
-	"io"
-	"os"
-	"strings"
-	"time"
 )
 
+// malwarescanReportsDirectory defines the subfolder for the malware scan reports which are generated
+const malwarescanReportsDirectory = "malwarescan"
+
 type malwareScanUtils interface {
 	OpenFile(name string, flag int, perm os.FileMode) (io.ReadCloser, error)
 	SHA256(path string) (string, error)
@@ -77,69 +84,142 @@ func malwareExecuteScan(config malwareExecuteScanOptions, telemetryData *telemet
 	}
 }
 
+// malwareScanRetryInterval is the time to wait before a failed upload is repeated
+var malwareScanRetryInterval = 10 * time.Second
+
 func runMalwareScan(config *malwareExecuteScanOptions, telemetryData *telemetry.CustomData, utils malwareScanUtils) error {
-	file, err := selectAndPrepareFileForMalwareScan(config, utils)
+	files, err := selectAndPrepareFilesForMalwareScan(config, utils)
 	if err != nil {
 		return err
 	}
 
-	log.Entry().Infof("Scanning file \"%s\" for malware using service \"%s\"", file, config.Host)
+	log.Entry().Infof("Scanning %d file(s) for malware using service \"%s\"", len(files), config.Host)
 
-	candidate, err := utils.OpenFile(file, os.O_RDONLY, 0666)
+	scannerInfo, err := utils.Info()
 	if err != nil {
-		return err
+		return errors.Wrap(err, "failed to retrieve information about the malware scan engine")
 	}
-	defer candidate.Close()
-
-	scannerInfo, err := utils.Info()
 
 	log.Entry().Infof("***************************************")
 	log.Entry().Infof("* Engine:     %s", scannerInfo.EngineVersion)
 	log.Entry().Infof("* Signatures: %s", scannerInfo.SignatureTimestamp)
 	log.Entry().Infof("***************************************")
 
-	if _, err = createToolRecordMalwareScan(utils, "./", config, scannerInfo); err != nil {
-		return err
+	cache, closeCache := newScanCache(config.ScanCache, config.ScanCacheLocation, config.ScanCacheMaxAge, utils)
+	defer closeCache()
+	cacheKey := ""
+	if cache != nil {
+		if cacheKey, err = malwareScanCacheKey(config, files, scannerInfo, utils); err != nil {
+			log.Entry().WithError(err).Warn("scan cache is disabled")
+			cache = nil
+		}
+	}
+	if entry := cache.Lookup(cacheKey); entry != nil {
+		if restored, err := restoreScanResult(entry, utils); restored {
+			return err
+		}
 	}
 
-	scanResponse, err := utils.Scan(candidate)
+	completed, err := executeMalwareScan(config, files, scannerInfo, utils)
+	if completed {
+		saveScanResult(cache, scancache.NewEntry("malwareExecuteScan", cacheKey), err, utils,
+			config.ReportFileName,
+			filepath.Join(malwarescanReportsDirectory, "*"),
+			"toolrun_malwarescan_*.json",
+			filepath.Join(reporting.StepReportDirectory, "malwareExecuteScan_malware.json"))
+	}
+	return err
+}
 
-	if err != nil {
-		return err
+// executeMalwareScan scans the files and returns whether all files could be scanned, i.e. the result can be cached
+func executeMalwareScan(config *malwareExecuteScanOptions, files []string, scannerInfo *malwarescan.Info, utils malwareScanUtils) (bool, error) {
+	if _, err := createToolRecordMalwareScan(utils, "./", config, scannerInfo); err != nil {
+		return false, err
 	}
 
-	if err = createMalwareScanReport(config, scanResponse, utils); err != nil {
-		return err
+	unpackDir := filepath.Join("cache", "malwarescan")
+	targets, err := prepareMalwareScanTargets(config, files, unpackDir)
+	defer func() { _ = utils.RemoveAll(unpackDir) }()
+	if err != nil {
+		return false, err
 	}
 
-	log.Entry().Debugf(
-		"File '%s' has been scanned. MalwareDetected: %t, EncryptedContentDetected: %t, ScanSize: %d, MimeType: '%s', SHA256: '%s', Finding: '%s'",
-		file,
-		scanResponse.MalwareDetected,
-		scanResponse.EncryptedContentDetected,
-		scanResponse.ScanSize,
-		scanResponse.MimeType,
-		scanResponse.SHA256,
-		scanResponse.Finding)
+	verdicts := malwarescan.ScanTargets(utils, targets, utils, malwarescan.ScanOptions{
+		Concurrency:   config.ScanConcurrency,
+		Retries:       config.ScanRetries,
+		RetryInterval: malwareScanRetryInterval,
+		Engine:        scannerInfo.EngineVersion,
+	})
 
-	if err = validateHash(scanResponse.SHA256, file, utils); err != nil {
-		return err
+	if err = createMalwareScanReport(config, verdicts, utils); err != nil {
+		return false, err
+	}
+	// only results of files which could be scanned are worth caching
+	completed := true
+	if _, err = writeMalwareScanReports(malwarescan.CreateScanReport(verdicts, scannerInfo), verdicts, utils); err != nil {
+		log.Entry().WithError(err).Warning("failed to write malware scan reports")
+		completed = false
 	}
 
-	if scanResponse.MalwareDetected || scanResponse.EncryptedContentDetected {
-		return fmt.Errorf("Malware scan failed for file '%s'. Malware detected: %t, encrypted content detected: %t, finding: %v",
-			file, scanResponse.MalwareDetected, scanResponse.EncryptedContentDetected, scanResponse.Finding)
+	failureMessages := []string{}
+	for _, verdict := range verdicts {
+		if verdict.Failed() {
+			failureMessages = append(failureMessages, verdict.FailureMessage())
+		}
+		if verdict.Error != nil {
+			completed = false
+		}
+	}
+	if len(failureMessages) == 1 && len(verdicts) == 1 {
+		return completed, errors.New(failureMessages[0])
+	}
+	if len(failureMessages) > 0 {
+		return completed, fmt.Errorf("Malware scan failed for %d of %d files: %s", len(failureMessages), len(verdicts), strings.Join(failureMessages, "; "))
 	}
 
-	log.Entry().Infof("Malware scan succeeded for file '%s'. Malware detected: %t, encrypted content detected: %t",
-		file, scanResponse.MalwareDetected, scanResponse.EncryptedContentDetected)
+	log.Entry().Infof("Malware scan succeeded for %d file(s). Malware detected: false, encrypted content detected: false", len(verdicts))
 
-	return nil
+	return completed, nil
 }
 
-func selectAndPrepareFileForMalwareScan(config *malwareExecuteScanOptions, utils malwareScanUtils) (string, error) {
-	if len(config.ScanFile) > 0 {
-		return config.ScanFile, nil
+// malwareScanCacheKey identifies a scan by the content of the files, the configuration which influences the result and
+// the version of the scan engine and its signatures, since a file may be detected as malware after a signature update
+func malwareScanCacheKey(config *malwareExecuteScanOptions, files []string, scannerInfo *malwarescan.Info, utils malwareScanUtils) (string, error) {
+	contentHash, err := scancache.HashFiles(utils, files)
+	if err != nil {
+		return "", err
+	}
+	return scancache.Key("malwareExecuteScan", contentHash, struct {
+		Host               string `json:"host"`
+		UnpackArchives     bool   `json:"unpackArchives"`
+		MaxUnpackSize      int    `json:"maxUnpackSize"`
+		EngineVersion      string `json:"engineVersion"`
+		SignatureTimestamp string `json:"signatureTimestamp"`
+	}{config.Host, config.UnpackArchives, config.MaxUnpackSize, scannerInfo.EngineVersion, scannerInfo.SignatureTimestamp})
+}
+
+func selectAndPrepareFilesForMalwareScan(config *malwareExecuteScanOptions, utils malwareScanUtils) ([]string, error) {
+	if len(config.ScanFile) > 0 || len(config.ScanFiles) > 0 {
+		files := []string{}
+		if len(config.ScanFile) > 0 {
+			files = append(files, config.ScanFile)
+		}
+		for _, pattern := range config.ScanFiles {
+			matches, err := utils.Glob(pattern)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to find files matching '%v'", pattern)
+			}
+			for _, match := range matches {
+				if !piperutils.ContainsString(files, match) {
+					files = append(files, match)
+				}
+			}
+		}
+		if len(files) == 0 {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return nil, fmt.Errorf("no files found matching %v", config.ScanFiles)
+		}
+		return files, nil
 	}
 
 	// automatically detect the file to be scanned depending on the buildtool
@@ -162,28 +242,72 @@ func selectAndPrepareFileForMalwareScan(config *malwareExecuteScanOptions, utils
 			if strings.Contains(fmt.Sprint(err), "no image found") {
 				log.SetErrorCategory(log.ErrorConfiguration)
 			}
-			return "", errors.Wrapf(err, "failed to download Docker image %v", config.ScanImage)
+			return nil, errors.Wrapf(err, "failed to download Docker image %v", config.ScanImage)
 		}
-		return tarFile, nil
+		return []string{tarFile}, nil
 	}
 
-	return "", fmt.Errorf("Please specify a file to be scanned")
+	// use the artifacts of the build
+	if len(config.Artifacts) > 0 {
+		return findMalwareScanArtifacts(config.Artifacts, utils)
+	}
+
+	return nil, fmt.Errorf("Please specify a file to be scanned")
 }
 
-func validateHash(remoteHash, fileName string, utils malwareScanUtils) error {
-	hash, err := utils.SHA256(fileName)
-	if err != nil {
-		return err
+// findMalwareScanArtifacts resolves the files of the artifacts reported by the build. Artifacts which do not provide
+// the path of their file, e.g. artifacts which were published to a repository, are skipped.
+func findMalwareScanArtifacts(artifacts []map[string]interface{}, utils malwareScanUtils) ([]string, error) {
+	files := []string{}
+	for _, artifact := range artifacts {
+		path, _ := artifact["path"].(string)
+		if len(path) == 0 {
+			log.Entry().Debugf("Skipping artifact '%v' without file", artifact["name"])
+			continue
+		}
+		if exists, _ := utils.FileExists(path); !exists {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return nil, fmt.Errorf("file '%v' of artifact '%v' not found", path, artifact["name"])
+		}
+		if !piperutils.ContainsString(files, path) {
+			files = append(files, path)
+		}
 	}
-
-	if hash == remoteHash {
-		log.Entry().Infof("Hash returned from malwarescan service matches file hash for file '%s' (%s)", fileName, hash)
-	} else {
-		return fmt.Errorf("Hash returned from malwarescan service ('%s') does not match file hash ('%s') for file '%s'",
-			remoteHash, hash, fileName)
+	if len(files) == 0 {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return nil, fmt.Errorf("none of the build artifacts provides a file to be scanned")
 	}
+	return files, nil
+}
 
-	return nil
+// prepareMalwareScanTargets unpacks archives if configured, their content is scanned instead of the archive itself.
+// Archives exceeding the unpack limit are scanned as a whole.
+func prepareMalwareScanTargets(config *malwareExecuteScanOptions, files []string, unpackDir string) ([]malwarescan.Target, error) {
+	targets := []malwarescan.Target{}
+	for index, file := range files {
+		target := malwarescan.Target{Path: file, Name: file}
+		if !config.UnpackArchives || !malwarescan.IsArchive(file) {
+			targets = append(targets, target)
+			continue
+		}
+		unpacker := malwarescan.Unpacker{MaxSize: int64(config.MaxUnpackSize) * 1024 * 1024, MaxDepth: 3}
+		destination := filepath.Join(unpackDir, fmt.Sprint(index))
+		parts, err := unpacker.Unpack(target, destination)
+		if errors.Is(err, malwarescan.ErrUnpackLimit) {
+			log.Entry().Warningf("Content of '%v' exceeds the unpack limit of %v MB, the archive is scanned as a single file", file, config.MaxUnpackSize)
+			if err := os.RemoveAll(destination); err != nil {
+				return nil, errors.Wrapf(err, "failed to remove unpacked content of '%v'", file)
+			}
+			targets = append(targets, target)
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to unpack '%v'", file)
+		}
+		log.Entry().Infof("Unpacked %d file(s) from '%v'", len(parts), file)
+		targets = append(targets, parts...)
+	}
+	return targets, nil
 }
 
 // create toolrecord file for malwarescan
@@ -206,8 +330,13 @@ func createToolRecordMalwareScan(utils malwareScanUtils, workspace string, confi
 	return record.GetFileName(), nil
 }
 
-func createMalwareScanReport(config *malwareExecuteScanOptions, scanResult *malwarescan.ScanResult, utils malwareScanUtils) error {
-	scanResultJSON, err := json.Marshal(scanResult)
+func createMalwareScanReport(config *malwareExecuteScanOptions, verdicts []malwarescan.Verdict, utils malwareScanUtils) error {
+	// the result of a single file is reported as before to stay compatible with consumers of the report
+	var report interface{} = verdicts
+	if len(verdicts) == 1 && verdicts[0].Error == nil {
+		report = verdicts[0].ScanResult
+	}
+	scanResultJSON, err := json.Marshal(report)
 
 	if err != nil {
 		return err
@@ -215,3 +344,14 @@ func createMalwareScanReport(config *malwareExecuteScanOptions, scanResult *malw
 
 	return utils.FileWrite(config.ReportFileName, scanResultJSON, 0666)
 }
+
+func writeMalwareScanReports(scanReport reporting.ScanReport, verdicts []malwarescan.Verdict, utils malwareScanUtils) ([]piperutils.Path, error) {
+	return reporting.WriteScanReports(scanReport, reporting.ScanReportFiles{
+		Directory: malwarescanReportsDirectory,
+		Name:      "Malware Scan",
+		HTMLFile:  "piper_malwarescan_report.html",
+		SarifFile: "piper_malwarescan.sarif",
+		Sarif:     malwarescan.CreateSarif(verdicts),
+		JSONFile:  "malwareExecuteScan_malware.json",
+	}, utils)
+}
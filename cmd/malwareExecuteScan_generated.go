@@ -20,18 +20,27 @@ import (
 )
 
 type malwareExecuteScanOptions struct {
-	BuildTool                 string `json:"buildTool,omitempty"`
-	DockerConfigJSON          string `json:"dockerConfigJSON,omitempty"`
-	ContainerRegistryPassword string `json:"containerRegistryPassword,omitempty"`
-	ContainerRegistryUser     string `json:"containerRegistryUser,omitempty"`
-	Host                      string `json:"host,omitempty"`
-	Username                  string `json:"username,omitempty"`
-	Password                  string `json:"password,omitempty"`
-	ScanImage                 string `json:"scanImage,omitempty"`
-	ScanImageRegistryURL      string `json:"scanImageRegistryUrl,omitempty"`
-	ScanFile                  string `json:"scanFile,omitempty"`
-	Timeout                   string `json:"timeout,omitempty"`
-	ReportFileName            string `json:"reportFileName,omitempty"`
+	BuildTool                 string                   `json:"buildTool,omitempty"`
+	DockerConfigJSON          string                   `json:"dockerConfigJSON,omitempty"`
+	ContainerRegistryPassword string                   `json:"containerRegistryPassword,omitempty"`
+	ContainerRegistryUser     string                   `json:"containerRegistryUser,omitempty"`
+	Host                      string                   `json:"host,omitempty"`
+	Username                  string                   `json:"username,omitempty"`
+	Password                  string                   `json:"password,omitempty"`
+	ScanImage                 string                   `json:"scanImage,omitempty"`
+	ScanImageRegistryURL      string                   `json:"scanImageRegistryUrl,omitempty"`
+	ScanFile                  string                   `json:"scanFile,omitempty"`
+	ScanFiles                 []string                 `json:"scanFiles,omitempty"`
+	Artifacts                 []map[string]interface{} `json:"artifacts,omitempty"`
+	UnpackArchives            bool                     `json:"unpackArchives,omitempty"`
+	MaxUnpackSize             int                      `json:"maxUnpackSize,omitempty"`
+	ScanConcurrency           int                      `json:"scanConcurrency,omitempty"`
+	ScanRetries               int                      `json:"scanRetries,omitempty"`
+	Timeout                   string                   `json:"timeout,omitempty"`
+	ReportFileName            string                   `json:"reportFileName,omitempty"`
+	ScanCache                 bool                     `json:"scanCache,omitempty"`
+	ScanCacheLocation         string                   `json:"scanCacheLocation,omitempty"`
+	ScanCacheMaxAge           int                      `json:"scanCacheMaxAge,omitempty"`
 }
 
 type malwareExecuteScanReports struct {
@@ -46,6 +55,7 @@ func (p *malwareExecuteScanReports) persist(stepConfig malwareExecuteScanOptions
 	content := []gcs.ReportOutputParam{
 		{FilePattern: "**/toolrun_malwarescan_*.json", ParamRef: "", StepResultType: "malwarescan"},
 		{FilePattern: "", ParamRef: "reportFileName", StepResultType: "malwarescan"},
+		{FilePattern: "**/malwarescan/piper_malwarescan.sarif", ParamRef: "", StepResultType: "malwarescan"},
 	}
 	envVars := []gcs.EnvVar{
 		{Name: "GOOGLE_APPLICATION_CREDENTIALS", Value: gcpJsonKeyFilePath, Modified: false},
@@ -181,8 +191,17 @@ func addMalwareExecuteScanFlags(cmd *cobra.Command, stepConfig *malwareExecuteSc
 	cmd.Flags().StringVar(&stepConfig.ScanImage, "scanImage", os.Getenv("PIPER_scanImage"), "For `buildTool: docker`: Defines the docker image which should be scanned.")
 	cmd.Flags().StringVar(&stepConfig.ScanImageRegistryURL, "scanImageRegistryUrl", os.Getenv("PIPER_scanImageRegistryUrl"), "For `buildTool: docker`: Defines the registry where the scanImage is located.")
 	cmd.Flags().StringVar(&stepConfig.ScanFile, "scanFile", os.Getenv("PIPER_scanFile"), "The file which is scanned for malware")
+	cmd.Flags().StringSliceVar(&stepConfig.ScanFiles, "scanFiles", []string{}, "List of glob patterns of files which are scanned for malware, e.g. `**/target/*.jar`. Takes precedence over `scanImage`.")
+
+	cmd.Flags().BoolVar(&stepConfig.UnpackArchives, "unpackArchives", false, "Unpack archives (e.g. zip, jar, war, mtar, tar) and image layers and scan their content file by file.")
+	cmd.Flags().IntVar(&stepConfig.MaxUnpackSize, "maxUnpackSize", 2048, "Maximum size in MB of the content which is unpacked from a single archive or image. Archives exceeding the limit are not unpacked but scanned as a single file.")
+	cmd.Flags().IntVar(&stepConfig.ScanConcurrency, "scanConcurrency", 4, "Maximum number of files which are uploaded to the malware scanning service in parallel.")
+	cmd.Flags().IntVar(&stepConfig.ScanRetries, "scanRetries", 2, "Number of retries if the upload of a file to the malware scanning service fails.")
 	cmd.Flags().StringVar(&stepConfig.Timeout, "timeout", `600`, "timeout for http layer in seconds")
 	cmd.Flags().StringVar(&stepConfig.ReportFileName, "reportFileName", `malwarescan_report.json`, "The file name of the report to be created")
+	cmd.Flags().BoolVar(&stepConfig.ScanCache, "scanCache", false, "Reuse the scan result of a previous run if the scanned content and the configuration are unchanged.")
+	cmd.Flags().StringVar(&stepConfig.ScanCacheLocation, "scanCacheLocation", os.Getenv("PIPER_scanCacheLocation"), "Location of the scan cache: a local directory, `gs://<bucket>/<folder>` or `s3://<bucket>/<prefix>`.")
+	cmd.Flags().IntVar(&stepConfig.ScanCacheMaxAge, "scanCacheMaxAge", 72, "Time to live of a cached scan result in hours.")
 
 	cmd.MarkFlagRequired("buildTool")
 	cmd.MarkFlagRequired("host")
@@ -369,6 +388,64 @@ func malwareExecuteScanMetadata() config.StepData {
 						Aliases:     []config.Alias{{Name: "file", Deprecated: true}},
 						Default:     os.Getenv("PIPER_scanFile"),
 					},
+					{
+						Name:        "scanFiles",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{},
+					},
+					{
+						Name: "artifacts",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "custom/artifacts",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "[]map[string]interface{}",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+					},
+					{
+						Name:        "unpackArchives",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name:        "maxUnpackSize",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     2048,
+					},
+					{
+						Name:        "scanConcurrency",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     4,
+					},
+					{
+						Name:        "scanRetries",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     2,
+					},
 					{
 						Name:        "timeout",
 						ResourceRef: []config.ResourceReference{},
@@ -387,6 +464,38 @@ func malwareExecuteScanMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     `malwarescan_report.json`,
 					},
+					{
+						Name: "scanCache",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "custom/isOptimizedAndScheduled",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "bool",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   false,
+					},
+					{
+						Name:        "scanCacheLocation",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_scanCacheLocation"),
+					},
+					{
+						Name:        "scanCacheMaxAge",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     72,
+					},
 				},
 			},
 			Outputs: config.StepOutputs{
@@ -397,6 +506,7 @@ func malwareExecuteScanMetadata() config.StepData {
 						Parameters: []map[string]interface{}{
 							{"filePattern": "**/toolrun_malwarescan_*.json", "type": "malwarescan"},
 							{"type": "malwarescan"},
+							{"filePattern": "**/malwarescan/piper_malwarescan.sarif", "type": "malwarescan"},
 						},
 					},
 				},
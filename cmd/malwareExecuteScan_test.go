@@ -1,8 +1,12 @@
 package cmd
 
 import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
 	"io"
 	"os"
+	"path/filepath"
 	"testing"
 
 	v1 "github.com/google/go-containerregistry/pkg/v1"
@@ -27,9 +31,20 @@ type malwareScanUtilsMockBundle struct {
 
 	returnScanResult *malwarescan.ScanResult
 	returnSHA256     string
+	// scan results per file content, used to scan several files
+	returnScanResultForContent map[string]*malwarescan.ScanResult
+	failingScans               int
+	signatureTimestamp         string
 }
 
 func (utils *malwareScanUtilsMockBundle) SHA256(filePath string) (string, error) {
+	if utils.returnScanResultForContent != nil {
+		content, err := utils.FileRead(filePath)
+		if err != nil {
+			return "", err
+		}
+		return utils.returnScanResultForContent[string(content)].SHA256, nil
+	}
 	if utils.returnSHA256 == "" {
 		return utils.returnScanResult.SHA256, nil
 	}
@@ -46,10 +61,25 @@ func (utils *malwareScanUtilsMockBundle) FileWrite(path string, content []byte,
 }
 
 func (utils *malwareScanUtilsMockBundle) Info() (*malwarescan.Info, error) {
-	return &malwarescan.Info{EngineVersion: "Mock Malware Scanner", SignatureTimestamp: "n/a"}, nil
+	signatureTimestamp := "n/a"
+	if len(utils.signatureTimestamp) > 0 {
+		signatureTimestamp = utils.signatureTimestamp
+	}
+	return &malwarescan.Info{EngineVersion: "Mock Malware Scanner", SignatureTimestamp: signatureTimestamp}, nil
 }
 
 func (utils *malwareScanUtilsMockBundle) Scan(candidate io.Reader) (*malwarescan.ScanResult, error) {
+	if utils.failingScans > 0 {
+		utils.failingScans--
+		return nil, errors.New("MalwareService returned with status code 503, no further information available")
+	}
+	if utils.returnScanResultForContent != nil {
+		content, err := io.ReadAll(candidate)
+		if err != nil {
+			return nil, err
+		}
+		return utils.returnScanResultForContent[string(content)], nil
+	}
 	return utils.returnScanResult, nil
 }
 
@@ -224,6 +254,209 @@ func TestMalwareScanWithOtherBuildtoolTests(t *testing.T) {
 	})
 }
 
+func TestMalwareScanWithMultipleFiles(t *testing.T) {
+	malwareScanRetryInterval = 0
+	cleanResult := &malwarescan.ScanResult{MimeType: "application/java-archive", SHA256: "1111"}
+	infectedResult := &malwarescan.ScanResult{MalwareDetected: true, Finding: "Win.Test.EICAR_HDB-1", SHA256: "2222"}
+
+	newUtils := func() *malwareScanUtilsMockBundle {
+		files := &mock.FilesMock{}
+		files.AddFile("module1/target/module1.jar", []byte("clean1"))
+		files.AddFile("module2/target/module2.jar", []byte("clean2"))
+		files.AddFile("module3/target/module3.war", []byte("infected"))
+		return &malwareScanUtilsMockBundle{
+			FilesMock: files,
+			returnScanResultForContent: map[string]*malwarescan.ScanResult{
+				"clean1":   cleanResult,
+				"clean2":   cleanResult,
+				"infected": infectedResult,
+			},
+		}
+	}
+
+	t.Run("glob patterns", func(t *testing.T) {
+		utils := newUtils()
+		config := malwareExecuteScanOptions{Host: "https://example.org/malwarescanner", ScanFiles: []string{"**/target/*.jar"}, ScanConcurrency: 2, ReportFileName: "malwarescan_report.json"}
+
+		err := runMalwareScan(&config, nil, utils)
+
+		assert.NoError(t, err)
+		content, err := utils.FileRead("malwarescan_report.json")
+		if assert.NoError(t, err) {
+			verdicts := []malwarescan.Verdict{}
+			assert.NoError(t, json.Unmarshal(content, &verdicts))
+			if assert.Len(t, verdicts, 2) {
+				assert.Equal(t, "module1/target/module1.jar", verdicts[0].File)
+				assert.Equal(t, "Mock Malware Scanner", verdicts[0].Engine)
+				assert.Equal(t, "1111", verdicts[0].SHA256)
+			}
+		}
+		assert.True(t, utils.HasWrittenFile("malwarescan/piper_malwarescan_report.html"))
+		assert.True(t, utils.HasWrittenFile("malwarescan/piper_malwarescan.sarif"))
+	})
+
+	t.Run("malware detected in one of several files", func(t *testing.T) {
+		utils := newUtils()
+		config := malwareExecuteScanOptions{Host: "https://example.org/malwarescanner", ScanFiles: []string{"**/target/*.jar", "**/target/*.war"}, ScanConcurrency: 3, ReportFileName: "malwarescan_report.json"}
+
+		err := runMalwareScan(&config, nil, utils)
+
+		assert.EqualError(t, err, "Malware scan failed for 1 of 3 files: Malware scan failed for file 'module3/target/module3.war'. Malware detected: true, encrypted content detected: false, finding: Win.Test.EICAR_HDB-1")
+		sarif, err := utils.FileRead("malwarescan/piper_malwarescan.sarif")
+		if assert.NoError(t, err) {
+			assert.Contains(t, string(sarif), `"ruleId":"malware"`)
+			assert.Contains(t, string(sarif), "module3/target/module3.war")
+		}
+	})
+
+	t.Run("no files matching", func(t *testing.T) {
+		utils := newUtils()
+		config := malwareExecuteScanOptions{Host: "https://example.org/malwarescanner", ScanFiles: []string{"**/*.mtar"}}
+
+		err := runMalwareScan(&config, nil, utils)
+
+		assert.EqualError(t, err, "no files found matching [**/*.mtar]")
+	})
+
+	t.Run("artifacts of the build", func(t *testing.T) {
+		utils := newUtils()
+		config := malwareExecuteScanOptions{
+			Host: "https://example.org/malwarescanner",
+			Artifacts: []map[string]interface{}{
+				{"id": "module1", "name": "module1.jar", "path": "module1/target/module1.jar"},
+				{"name": "module2.jar", "path": "module2/target/module2.jar"},
+				{"id": "module3", "name": "module3.war"},
+			},
+			ReportFileName: "malwarescan_report.json",
+		}
+
+		err := runMalwareScan(&config, nil, utils)
+
+		assert.NoError(t, err)
+		content, _ := utils.FileRead("malwarescan_report.json")
+		assert.Contains(t, string(content), "module1/target/module1.jar")
+		assert.Contains(t, string(content), "module2/target/module2.jar")
+		assert.NotContains(t, string(content), "module3")
+	})
+
+	t.Run("artifact not found", func(t *testing.T) {
+		utils := newUtils()
+		config := malwareExecuteScanOptions{Host: "https://example.org/malwarescanner", Artifacts: []map[string]interface{}{{"name": "module4.jar", "path": "module4/target/module4.jar"}}}
+
+		err := runMalwareScan(&config, nil, utils)
+
+		assert.EqualError(t, err, "file 'module4/target/module4.jar' of artifact 'module4.jar' not found")
+	})
+
+	t.Run("artifacts without file", func(t *testing.T) {
+		utils := newUtils()
+		config := malwareExecuteScanOptions{Host: "https://example.org/malwarescanner", Artifacts: []map[string]interface{}{{"id": "module1", "name": "module1.jar"}}}
+
+		err := runMalwareScan(&config, nil, utils)
+
+		assert.EqualError(t, err, "none of the build artifacts provides a file to be scanned")
+	})
+
+	t.Run("retry failed upload", func(t *testing.T) {
+		utils := newUtils()
+		utils.failingScans = 2
+		config := malwareExecuteScanOptions{Host: "https://example.org/malwarescanner", ScanFile: "module1/target/module1.jar", ScanRetries: 2, ReportFileName: "malwarescan_report.json"}
+
+		err := runMalwareScan(&config, nil, utils)
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("upload fails after retries", func(t *testing.T) {
+		utils := newUtils()
+		utils.failingScans = 2
+		config := malwareExecuteScanOptions{Host: "https://example.org/malwarescanner", ScanFile: "module1/target/module1.jar", ScanRetries: 1, ReportFileName: "malwarescan_report.json"}
+
+		err := runMalwareScan(&config, nil, utils)
+
+		assert.EqualError(t, err, "MalwareService returned with status code 503, no further information available")
+	})
+}
+
+func TestMalwareScanWithCache(t *testing.T) {
+	malwareScanRetryInterval = 0
+	infectedResult := &malwarescan.ScanResult{MalwareDetected: true, Finding: "Win.Test.EICAR_HDB-1", SHA256: "2222"}
+	config := malwareExecuteScanOptions{
+		Host:              "https://example.org/malwarescanner",
+		ScanFile:          "target/app.war",
+		ReportFileName:    "malwarescan_report.json",
+		ScanCache:         true,
+		ScanCacheLocation: "scancache",
+		ScanCacheMaxAge:   24,
+	}
+
+	t.Run("restore cached result", func(t *testing.T) {
+		files := &mock.FilesMock{}
+		files.AddFile("target/app.war", []byte("infected"))
+		utils := &malwareScanUtilsMockBundle{FilesMock: files, returnScanResultForContent: map[string]*malwarescan.ScanResult{"infected": infectedResult}}
+
+		err := runMalwareScan(&config, nil, utils)
+
+		expectedError := "Malware scan failed for file 'target/app.war'. Malware detected: true, encrypted content detected: false, finding: Win.Test.EICAR_HDB-1"
+		assert.EqualError(t, err, expectedError)
+		entries, _ := files.Glob("scancache/*.json")
+		assert.Len(t, entries, 1)
+
+		// a scan would fail since the service is not available
+		utils.failingScans = 1
+		assert.NoError(t, files.FileRemove("malwarescan/piper_malwarescan_report.html"))
+
+		err = runMalwareScan(&config, nil, utils)
+
+		assert.EqualError(t, err, expectedError)
+		assert.Equal(t, 1, utils.failingScans)
+		assert.True(t, files.HasFile("malwarescan/piper_malwarescan_report.html"))
+	})
+
+	t.Run("signature update invalidates cached result", func(t *testing.T) {
+		files := &mock.FilesMock{}
+		files.AddFile("target/app.war", []byte("infected"))
+		utils := &malwareScanUtilsMockBundle{FilesMock: files, returnScanResultForContent: map[string]*malwarescan.ScanResult{"infected": infectedResult}}
+		utils.signatureTimestamp = "2023-03-01T00:00:00Z"
+
+		_ = runMalwareScan(&config, nil, utils)
+
+		utils.signatureTimestamp = "2023-03-02T00:00:00Z"
+		utils.failingScans = 1
+
+		err := runMalwareScan(&config, nil, utils)
+
+		assert.EqualError(t, err, "MalwareService returned with status code 503, no further information available")
+		assert.Equal(t, 0, utils.failingScans)
+	})
+
+	t.Run("failed scans are not cached", func(t *testing.T) {
+		files := &mock.FilesMock{}
+		files.AddFile("target/app.war", []byte("infected"))
+		utils := &malwareScanUtilsMockBundle{FilesMock: files, returnScanResultForContent: map[string]*malwarescan.ScanResult{"infected": infectedResult}}
+		utils.failingScans = 1
+
+		err := runMalwareScan(&config, nil, utils)
+
+		assert.EqualError(t, err, "MalwareService returned with status code 503, no further information available")
+		entries, _ := files.Glob("scancache/*.json")
+		assert.Empty(t, entries)
+	})
+
+	t.Run("cache disabled", func(t *testing.T) {
+		files := &mock.FilesMock{}
+		files.AddFile("target/app.war", []byte("infected"))
+		utils := &malwareScanUtilsMockBundle{FilesMock: files, returnScanResultForContent: map[string]*malwarescan.ScanResult{"infected": infectedResult}}
+		disabledConfig := config
+		disabledConfig.ScanCache = false
+
+		_ = runMalwareScan(&disabledConfig, nil, utils)
+
+		entries, _ := files.Glob("scancache/*.json")
+		assert.Empty(t, entries)
+	})
+}
+
 type dockerClientMock struct {
 	imageName   string
 	registryURL string
@@ -244,3 +477,42 @@ func (c *dockerClientMock) DownloadImageContent(imageSource, filePath string) (v
 func (c *dockerClientMock) GetRemoteImageInfo(imageSoure string) (v1.Image, error) {
 	return &fake.FakeImage{}, nil
 }
+
+func TestPrepareMalwareScanTargets(t *testing.T) {
+	dir := t.TempDir()
+	archivePath := filepath.Join(dir, "app.zip")
+	archive, err := os.Create(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writer := zip.NewWriter(archive)
+	entry, _ := writer.Create("large.bin")
+	_, _ = entry.Write(make([]byte, 2*1024*1024))
+	entry, _ = writer.Create("small.txt")
+	_, _ = entry.Write([]byte("small"))
+	assert.NoError(t, writer.Close())
+	assert.NoError(t, archive.Close())
+
+	t.Run("unpack archive", func(t *testing.T) {
+		config := malwareExecuteScanOptions{UnpackArchives: true, MaxUnpackSize: 3}
+
+		targets, err := prepareMalwareScanTargets(&config, []string{archivePath}, filepath.Join(dir, "unpacked"))
+
+		if assert.NoError(t, err) && assert.Len(t, targets, 2) {
+			assert.Equal(t, archivePath+"!large.bin", targets[0].Name)
+			assert.Equal(t, archivePath+"!small.txt", targets[1].Name)
+		}
+	})
+
+	t.Run("archive exceeding the unpack limit", func(t *testing.T) {
+		config := malwareExecuteScanOptions{UnpackArchives: true, MaxUnpackSize: 1}
+		unpackDir := filepath.Join(dir, "limited")
+
+		targets, err := prepareMalwareScanTargets(&config, []string{archivePath}, unpackDir)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, []malwarescan.Target{{Path: archivePath, Name: archivePath}}, targets)
+		}
+		assert.NoDirExists(t, filepath.Join(unpackDir, "0"))
+	})
+}
@@ -1,16 +1,20 @@
 package cmd
 
 import (
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path"
 	"path/filepath"
 	"strings"
 
+	"github.com/SAP/jenkins-library/pkg/buildcache"
 	"github.com/SAP/jenkins-library/pkg/buildsettings"
 	"github.com/SAP/jenkins-library/pkg/command"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/maven"
+	"github.com/SAP/jenkins-library/pkg/monorepo"
+	"github.com/SAP/jenkins-library/pkg/piperenv"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 	"github.com/pkg/errors"
@@ -25,13 +29,27 @@ const (
 func mavenBuild(config mavenBuildOptions, telemetryData *telemetry.CustomData, commonPipelineEnvironment *mavenBuildCommonPipelineEnvironment) {
 	utils := maven.NewUtilsBundle()
 
-	err := runMavenBuild(&config, telemetryData, utils, commonPipelineEnvironment)
+	files := &piperutils.Files{}
+	cache, closeCache := newBuildCache(config.BuildCache, config.BuildCacheLocation, config.BuildCacheMaxSize, files)
+	defer closeCache()
+	cacheDirectories := []*buildcache.Directory{}
+	if cacheDirectory, err := buildcache.MavenDirectory(files, config.M2Path); err != nil {
+		log.Entry().WithError(err).Warn("failed to determine Maven cache directory")
+	} else {
+		cacheDirectories = append(cacheDirectories, cacheDirectory)
+	}
+	err := runWithBuildCache(cache, cacheDirectories, func() error {
+		return runMavenBuild(&config, telemetryData, utils, commonPipelineEnvironment)
+	})
 	if err != nil {
 		log.Entry().WithError(err).Fatal("step execution failed")
 	}
 }
 
 func runMavenBuild(config *mavenBuildOptions, telemetryData *telemetry.CustomData, utils maven.Utils, commonPipelineEnvironment *mavenBuildCommonPipelineEnvironment) error {
+	if len(config.WorkspaceProjects) > 0 {
+		return runMavenBuildForWorkspace(config, telemetryData, utils, commonPipelineEnvironment)
+	}
 
 	var flags = []string{"-update-snapshots", "--batch-mode"}
 
@@ -94,6 +112,13 @@ func runMavenBuild(config *mavenBuildOptions, telemetryData *telemetry.CustomDat
 		return errors.Wrapf(err, "failed to execute maven build for goal(s) '%v'", goals)
 	}
 
+	// the build succeeded already, hence missing artifact information does not fail the step
+	if artifacts, err := mavenBuildArtifacts(config.PomPath, utils); err != nil {
+		log.Entry().WithError(err).Warn("failed to determine the built artifacts")
+	} else {
+		commonPipelineEnvironment.custom.artifacts = append(commonPipelineEnvironment.custom.artifacts, artifacts...)
+	}
+
 	log.Entry().Debugf("creating build settings information...")
 	stepName := "mavenBuild"
 	dockerImage, err := GetDockerImageValue(stepName)
@@ -159,6 +184,93 @@ func runMavenBuild(config *mavenBuildOptions, telemetryData *telemetry.CustomDat
 	return err
 }
 
+// runMavenBuildForWorkspace builds and publishes the changed Maven projects of a monorepo one after the other in dependency order
+func runMavenBuildForWorkspace(config *mavenBuildOptions, telemetryData *telemetry.CustomData, utils maven.Utils, commonPipelineEnvironment *mavenBuildCommonPipelineEnvironment) error {
+	projects, err := monorepo.ChangedProjects(config.WorkspaceProjects, "maven")
+	if err != nil {
+		return err
+	}
+	if len(projects) == 0 {
+		log.Entry().Info("no Maven project of the workspace changed since its last release, skipping maven build")
+		return nil
+	}
+
+	for _, project := range projects {
+		log.Entry().Infof("building project '%v' with version '%v'", project.Name, project.Version)
+		projectConfig := *config
+		projectConfig.WorkspaceProjects = nil
+		projectConfig.PomPath = path.Join(project.Path, "pom.xml")
+		if err := runMavenBuild(&projectConfig, telemetryData, utils, commonPipelineEnvironment); err != nil {
+			return errors.Wrapf(err, "failed to build project '%v'", project.Name)
+		}
+	}
+	return nil
+}
+
+// mavenBuildArtifacts returns the archives built for the modules of the project, e.g. target/app-1.0.0.jar
+func mavenBuildArtifacts(pomPath string, utils maven.Utils) (piperenv.Artifacts, error) {
+	artifacts := piperenv.Artifacts{}
+	err := maven.VisitAllMavenModules(filepath.Dir(pomPath), utils, []string{filepath.Join("integration-tests", "pom.xml")}, func(info maven.ModuleInfo) error {
+		file, err := mavenModuleArtifact(info, utils)
+		if err != nil || len(file) == 0 {
+			return err
+		}
+		artifacts = append(artifacts, piperenv.Artifact{Id: info.Project.ArtifactID, Name: filepath.Base(file), Path: filepath.ToSlash(file), Type: "archive"})
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to determine the built artifacts")
+	}
+	return artifacts, nil
+}
+
+// mavenModuleArtifact finds the archive of a module in its build directory. If the file name cannot be derived from the
+// POM, e.g. since it depends on properties, the archive without classifier is searched.
+func mavenModuleArtifact(info maven.ModuleInfo, utils maven.Utils) (string, error) {
+	project := info.Project
+	if project.Packaging == "pom" {
+		return "", nil
+	}
+	buildDir := project.Build.Directory
+	if len(buildDir) == 0 || strings.Contains(buildDir, "${") {
+		buildDir = "target"
+	}
+	buildDir = filepath.Join(filepath.Dir(info.PomXMLPath), buildDir)
+	extension := mavenArtifactExtension(project.Packaging)
+
+	finalName := project.Build.FinalName
+	if len(finalName) == 0 && len(project.Version) > 0 {
+		finalName = fmt.Sprintf("%v-%v", project.ArtifactID, project.Version)
+	}
+	if len(finalName) > 0 && !strings.Contains(finalName, "${") {
+		file := filepath.Join(buildDir, finalName+"."+extension)
+		if exists, _ := utils.FileExists(file); exists {
+			return file, nil
+		}
+	}
+
+	matches, err := utils.Glob(filepath.Join(buildDir, project.ArtifactID+"*."+extension))
+	if err != nil {
+		return "", err
+	}
+	for _, match := range matches {
+		name := strings.TrimSuffix(filepath.Base(match), "."+extension)
+		if !strings.HasSuffix(name, "-sources") && !strings.HasSuffix(name, "-javadoc") && !strings.HasSuffix(name, "-tests") {
+			return match, nil
+		}
+	}
+	log.Entry().Debugf("no %v archive found for module '%v'", extension, project.ArtifactID)
+	return "", nil
+}
+
+func mavenArtifactExtension(packaging string) string {
+	switch packaging {
+	case "war", "ear":
+		return packaging
+	}
+	return "jar"
+}
+
 func createOrUpdateProjectSettingsXML(projectSettingsFile string, altDeploymentRepositoryID string, altDeploymentRepositoryUser string, altDeploymentRepositoryPassword string, utils maven.Utils) (string, error) {
 	if len(projectSettingsFile) > 0 {
 		projectSettingsFilePath, err := maven.UpdateProjectSettingsXML(projectSettingsFile, altDeploymentRepositoryID, altDeploymentRepositoryUser, altDeploymentRepositoryPassword, utils)
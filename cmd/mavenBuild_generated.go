@@ -22,28 +22,33 @@ import (
 )
 
 type mavenBuildOptions struct {
-	PomPath                         string   `json:"pomPath,omitempty"`
-	Profiles                        []string `json:"profiles,omitempty"`
-	Flatten                         bool     `json:"flatten,omitempty"`
-	Verify                          bool     `json:"verify,omitempty"`
-	ProjectSettingsFile             string   `json:"projectSettingsFile,omitempty"`
-	GlobalSettingsFile              string   `json:"globalSettingsFile,omitempty"`
-	M2Path                          string   `json:"m2Path,omitempty"`
-	LogSuccessfulMavenTransfers     bool     `json:"logSuccessfulMavenTransfers,omitempty"`
-	CreateBOM                       bool     `json:"createBOM,omitempty"`
-	AltDeploymentRepositoryPassword string   `json:"altDeploymentRepositoryPassword,omitempty"`
-	AltDeploymentRepositoryUser     string   `json:"altDeploymentRepositoryUser,omitempty"`
-	AltDeploymentRepositoryURL      string   `json:"altDeploymentRepositoryUrl,omitempty"`
-	AltDeploymentRepositoryID       string   `json:"altDeploymentRepositoryID,omitempty"`
-	CustomTLSCertificateLinks       []string `json:"customTlsCertificateLinks,omitempty"`
-	Publish                         bool     `json:"publish,omitempty"`
-	JavaCaCertFilePath              string   `json:"javaCaCertFilePath,omitempty"`
-	BuildSettingsInfo               string   `json:"buildSettingsInfo,omitempty"`
+	PomPath                         string                   `json:"pomPath,omitempty"`
+	Profiles                        []string                 `json:"profiles,omitempty"`
+	Flatten                         bool                     `json:"flatten,omitempty"`
+	Verify                          bool                     `json:"verify,omitempty"`
+	ProjectSettingsFile             string                   `json:"projectSettingsFile,omitempty"`
+	GlobalSettingsFile              string                   `json:"globalSettingsFile,omitempty"`
+	M2Path                          string                   `json:"m2Path,omitempty"`
+	LogSuccessfulMavenTransfers     bool                     `json:"logSuccessfulMavenTransfers,omitempty"`
+	CreateBOM                       bool                     `json:"createBOM,omitempty"`
+	AltDeploymentRepositoryPassword string                   `json:"altDeploymentRepositoryPassword,omitempty"`
+	AltDeploymentRepositoryUser     string                   `json:"altDeploymentRepositoryUser,omitempty"`
+	AltDeploymentRepositoryURL      string                   `json:"altDeploymentRepositoryUrl,omitempty"`
+	AltDeploymentRepositoryID       string                   `json:"altDeploymentRepositoryID,omitempty"`
+	CustomTLSCertificateLinks       []string                 `json:"customTlsCertificateLinks,omitempty"`
+	Publish                         bool                     `json:"publish,omitempty"`
+	JavaCaCertFilePath              string                   `json:"javaCaCertFilePath,omitempty"`
+	BuildSettingsInfo               string                   `json:"buildSettingsInfo,omitempty"`
+	WorkspaceProjects               []map[string]interface{} `json:"workspaceProjects,omitempty"`
+	BuildCache                      bool                     `json:"buildCache,omitempty"`
+	BuildCacheLocation              string                   `json:"buildCacheLocation,omitempty"`
+	BuildCacheMaxSize               int                      `json:"buildCacheMaxSize,omitempty"`
 }
 
 type mavenBuildCommonPipelineEnvironment struct {
 	custom struct {
 		buildSettingsInfo string
+		artifacts         piperenv.Artifacts
 	}
 }
 
@@ -54,6 +59,7 @@ func (p *mavenBuildCommonPipelineEnvironment) persist(path, resourceName string)
 		value    interface{}
 	}{
 		{category: "custom", name: "buildSettingsInfo", value: p.custom.buildSettingsInfo},
+		{category: "custom", name: "artifacts", value: p.custom.artifacts},
 	}
 
 	errCount := 0
@@ -262,6 +268,10 @@ func addMavenBuildFlags(cmd *cobra.Command, stepConfig *mavenBuildOptions) {
 	cmd.Flags().StringVar(&stepConfig.JavaCaCertFilePath, "javaCaCertFilePath", os.Getenv("PIPER_javaCaCertFilePath"), "path to the cacerts file used by Java. When maven publish is set to True and customTlsCertificateLinks (to deploy the artifact to a repository with a self signed cert) are provided to trust the self signed certs, Piper will extend the existing Java cacerts to include the new self signed certs. if not provided Piper will search for the cacerts in $JAVA_HOME/jre/lib/security/cacerts")
 	cmd.Flags().StringVar(&stepConfig.BuildSettingsInfo, "buildSettingsInfo", os.Getenv("PIPER_buildSettingsInfo"), "build settings info is typically filled by the step automatically to create information about the build settings that were used during the maven build . This information is typically used for compliance related processes.")
 
+	cmd.Flags().BoolVar(&stepConfig.BuildCache, "buildCache", false, "Restore the dependency caches of the build tool before the build and save them afterwards.")
+	cmd.Flags().StringVar(&stepConfig.BuildCacheLocation, "buildCacheLocation", os.Getenv("PIPER_buildCacheLocation"), "Location of the build cache: a local directory, `gs://<bucket>/<folder>` or `s3://<bucket>/<prefix>`.")
+	cmd.Flags().IntVar(&stepConfig.BuildCacheMaxSize, "buildCacheMaxSize", 2048, "Maximum size of a compressed cache directory in MB, larger caches are not saved.")
+
 }
 
 // retrieve step metadata
@@ -487,6 +497,46 @@ func mavenBuildMetadata() config.StepData {
 						Aliases:   []config.Alias{},
 						Default:   os.Getenv("PIPER_buildSettingsInfo"),
 					},
+					{
+						Name: "workspaceProjects",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "custom/workspaceProjects",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "[]map[string]interface{}",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+					},
+					{
+						Name:        "buildCache",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name:        "buildCacheLocation",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_buildCacheLocation"),
+					},
+					{
+						Name:        "buildCacheMaxSize",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     2048,
+					},
 				},
 			},
 			Containers: []config.Container{
@@ -499,6 +549,7 @@ func mavenBuildMetadata() config.StepData {
 						Type: "piperEnvironment",
 						Parameters: []map[string]interface{}{
 							{"name": "custom/buildSettingsInfo"},
+							{"name": "custom/artifacts", "type": "piperenv.Artifacts"},
 						},
 					},
 					{
@@ -3,6 +3,7 @@ package cmd
 import (
 	"testing"
 
+	"github.com/SAP/jenkins-library/pkg/piperenv"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -132,4 +133,73 @@ func TestMavenBuild(t *testing.T) {
 		assert.Contains(t, mockedUtils.Calls[0].Params, "profile1,profile2")
 	})
 
+	t.Run("mavenBuild reports the archives of all modules", func(t *testing.T) {
+		mockedUtils := newMavenMockUtils()
+		mockedUtils.AddFile("pom.xml", []byte(`<project><artifactId>parent</artifactId><version>1.0.0</version><packaging>pom</packaging>
+<modules><module>core</module><module>web</module><module>app</module><module>integration-tests</module></modules></project>`))
+		mockedUtils.AddFile("core/pom.xml", []byte(`<project><artifactId>core</artifactId><version>1.0.0</version></project>`))
+		mockedUtils.AddFile("core/target/core-1.0.0.jar", []byte("core"))
+		mockedUtils.AddFile("core/target/core-1.0.0-sources.jar", []byte("sources"))
+		mockedUtils.AddFile("web/pom.xml", []byte(`<project><artifactId>web</artifactId><packaging>war</packaging><build><finalName>${project.artifactId}</finalName></build></project>`))
+		mockedUtils.AddFile("web/target/web-1.0.0-sources.war", []byte("sources"))
+		mockedUtils.AddFile("web/target/web.war", []byte("web"))
+		mockedUtils.AddFile("app/pom.xml", []byte(`<project><artifactId>app</artifactId><build><finalName>application</finalName><directory>out</directory></build></project>`))
+		mockedUtils.AddFile("app/out/application.jar", []byte("app"))
+		mockedUtils.AddFile("integration-tests/pom.xml", []byte(`<project><artifactId>integration-tests</artifactId></project>`))
+		mockedUtils.AddFile("integration-tests/target/integration-tests.jar", []byte("tests"))
+		pipelineEnv := mavenBuildCommonPipelineEnvironment{}
+
+		err := runMavenBuild(&mavenBuildOptions{PomPath: "pom.xml"}, nil, &mockedUtils, &pipelineEnv)
+
+		assert.NoError(t, err)
+		assert.Equal(t, piperenv.Artifacts{
+			{Id: "core", Name: "core-1.0.0.jar", Path: "core/target/core-1.0.0.jar", Type: "archive"},
+			{Id: "web", Name: "web.war", Path: "web/target/web.war", Type: "archive"},
+			{Id: "app", Name: "application.jar", Path: "app/out/application.jar", Type: "archive"},
+		}, pipelineEnv.custom.artifacts)
+	})
+
+	t.Run("mavenBuild does not fail if the artifacts cannot be determined", func(t *testing.T) {
+		mockedUtils := newMavenMockUtils()
+		mockedUtils.AddFile("pom.xml", []byte(`<project><artifactId>parent</artifactId><packaging>pom</packaging><modules><module>core</module></modules></project>`))
+		mockedUtils.AddFile("core/pom.xml", []byte(`<project><artifactId>core`))
+		pipelineEnv := mavenBuildCommonPipelineEnvironment{}
+
+		err := runMavenBuild(&mavenBuildOptions{PomPath: "pom.xml"}, nil, &mockedUtils, &pipelineEnv)
+
+		assert.NoError(t, err)
+		assert.Empty(t, pipelineEnv.custom.artifacts)
+	})
+
+	t.Run("mavenBuild builds changed workspace projects only", func(t *testing.T) {
+		mockedUtils := newMavenMockUtils()
+
+		config := mavenBuildOptions{WorkspaceProjects: []map[string]interface{}{
+			{"name": "common", "path": "libs/common", "buildTool": "maven", "version": "1.0.1-20221018120000", "changed": true},
+			{"name": "web", "path": "ui/web", "buildTool": "npm", "version": "2.0.0-20221018120000", "changed": true},
+			{"name": "billing", "path": "services/billing", "buildTool": "maven", "version": "3.1.0-20221001120000", "changed": false},
+			{"name": "orders", "path": "services/orders", "buildTool": "maven", "version": "1.2.0-20221018120000", "changed": true},
+		}}
+
+		err := runMavenBuild(&config, nil, &mockedUtils, &cpe)
+
+		assert.Nil(t, err)
+		if assert.Len(t, mockedUtils.Calls, 2) {
+			assert.Contains(t, mockedUtils.Calls[0].Params, "libs/common/pom.xml")
+			assert.Contains(t, mockedUtils.Calls[1].Params, "services/orders/pom.xml")
+		}
+	})
+
+	t.Run("mavenBuild skips build without changed workspace projects", func(t *testing.T) {
+		mockedUtils := newMavenMockUtils()
+
+		config := mavenBuildOptions{WorkspaceProjects: []map[string]interface{}{
+			{"name": "billing", "path": "services/billing", "buildTool": "maven", "version": "3.1.0-20221001120000", "changed": false},
+		}}
+
+		err := runMavenBuild(&config, nil, &mockedUtils, &cpe)
+
+		assert.Nil(t, err)
+		assert.Empty(t, mockedUtils.Calls)
+	})
 }
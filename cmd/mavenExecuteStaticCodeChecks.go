@@ -1,26 +1,54 @@
 package cmd
 
 import (
+	"strconv"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/git"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/maven"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
-	"strconv"
 )
 
+// changedFilesFunc determines the files changed in the current pipeline run
+type changedFilesFunc func() (git.ChangeRange, []string, error)
+
 func mavenExecuteStaticCodeChecks(config mavenExecuteStaticCodeChecksOptions, telemetryData *telemetry.CustomData) {
-	err := runMavenStaticCodeChecks(&config, telemetryData, maven.NewUtilsBundle())
+	changedFiles := func() (git.ChangeRange, []string, error) { return git.ChangedFilesOfCurrentRun(".") }
+	reports, err := runMavenStaticCodeChecks(&config, telemetryData, maven.NewUtilsBundle(), changedFiles)
+	piperutils.PersistReportsAndLinks("mavenExecuteStaticCodeChecks", "", &piperutils.Files{}, reports, nil)
 	if err != nil {
 		log.Entry().WithError(err).Fatal("step execution failed")
 	}
 }
 
-func runMavenStaticCodeChecks(config *mavenExecuteStaticCodeChecksOptions, telemetryData *telemetry.CustomData, utils maven.Utils) error {
+// runMavenStaticCodeChecks executes the static code checks and returns the reports to be persisted, i.e. the change analysis
+func runMavenStaticCodeChecks(config *mavenExecuteStaticCodeChecksOptions, telemetryData *telemetry.CustomData, utils maven.Utils, changedFiles changedFilesFunc) ([]piperutils.Path, error) {
 	var defines []string
 	var goals []string
 
 	if !config.SpotBugs && !config.Pmd {
 		log.Entry().Warnf("Neither SpotBugs nor Pmd are configured. Skipping step execution")
-		return nil
+		return nil, nil
+	}
+
+	reports := []piperutils.Path{}
+	if config.ScanChangedModulesOnly {
+		affectedModules, reportPath, err := determineAffectedMavenModules(config, utils, changedFiles)
+		if err != nil {
+			return reports, err
+		}
+		if reportPath != nil {
+			reports = append(reports, *reportPath)
+		}
+		if affectedModules != nil && len(affectedModules) == 0 {
+			log.Entry().Info("No Maven module is affected by the changes. Skipping step execution")
+			return reports, nil
+		}
+		if len(affectedModules) > 0 {
+			defines = append(defines, "-pl", strings.Join(affectedModules, ","))
+		}
 	}
 
 	if config.InstallArtifacts {
@@ -30,7 +58,7 @@ func runMavenStaticCodeChecks(config *mavenExecuteStaticCodeChecksOptions, telem
 			GlobalSettingsFile:  config.GlobalSettingsFile,
 		}, utils)
 		if err != nil {
-			return err
+			return reports, err
 		}
 	}
 
@@ -63,7 +91,39 @@ func runMavenStaticCodeChecks(config *mavenExecuteStaticCodeChecksOptions, telem
 		LogSuccessfulMavenTransfers: config.LogSuccessfulMavenTransfers,
 	}
 	_, err := maven.Execute(&finalMavenOptions, utils)
-	return err
+	return reports, err
+}
+
+// determineAffectedMavenModules returns the paths of the modules affected by the changes of the current pipeline run
+// together with the change analysis report. If the changes cannot be determined, nil is returned in order to check all modules.
+func determineAffectedMavenModules(config *mavenExecuteStaticCodeChecksOptions, utils maven.Utils, changedFiles changedFilesFunc) ([]string, *piperutils.Path, error) {
+	changeRange, files, err := changedFiles()
+	if err != nil {
+		log.Entry().WithError(err).Warn("Failed to determine the changes of the pipeline run, all modules will be checked")
+		return nil, nil, nil
+	}
+	modules, err := git.DiscoverMavenModules(".", utils)
+	if err != nil {
+		return nil, nil, err
+	}
+	analysis := git.AnalyzeChanges(changeRange, files, modules, config.ChangeAnalysisGlobalFiles)
+	reportPath, err := git.WriteChangeAnalysisReport(analysis, "mavenExecuteStaticCodeChecks", utils)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	affectedModules := []string{}
+	for _, module := range analysis.AffectedModules {
+		affectedModules = append(affectedModules, module.Path)
+	}
+	for _, module := range analysis.SkippedModules {
+		log.Entry().Infof("Skipping Maven module '%v' (%v) since it is not affected by the changes", module.Name, module.Path)
+	}
+	if len(analysis.SkippedModules) == 0 {
+		// all modules are affected, no need to restrict the reactor
+		return nil, &reportPath, nil
+	}
+	return affectedModules, &reportPath, nil
 }
 
 func getSpotBugsMavenParameters(config *mavenExecuteStaticCodeChecksOptions) *maven.ExecuteOptions {
@@ -29,6 +29,8 @@ type mavenExecuteStaticCodeChecksOptions struct {
 	M2Path                       string   `json:"m2Path,omitempty"`
 	LogSuccessfulMavenTransfers  bool     `json:"logSuccessfulMavenTransfers,omitempty"`
 	InstallArtifacts             bool     `json:"installArtifacts,omitempty"`
+	ScanChangedModulesOnly       bool     `json:"scanChangedModulesOnly,omitempty"`
+	ChangeAnalysisGlobalFiles    []string `json:"changeAnalysisGlobalFiles,omitempty"`
 }
 
 // MavenExecuteStaticCodeChecksCommand Execute static code checks for Maven based projects. The plugins SpotBugs and PMD are used.
@@ -144,6 +146,8 @@ func addMavenExecuteStaticCodeChecksFlags(cmd *cobra.Command, stepConfig *mavenE
 	cmd.Flags().StringVar(&stepConfig.M2Path, "m2Path", os.Getenv("PIPER_m2Path"), "Path to the location of the local repository that should be used.")
 	cmd.Flags().BoolVar(&stepConfig.LogSuccessfulMavenTransfers, "logSuccessfulMavenTransfers", false, "Configures maven to log successful downloads. This is set to `false` by default to reduce the noise in build logs.")
 	cmd.Flags().BoolVar(&stepConfig.InstallArtifacts, "installArtifacts", false, "If enabled, it will install all artifacts to the local maven repository to make them available before running the static code checks. This is required if any maven module has dependencies to other modules in the repository and they were not installed before.")
+	cmd.Flags().BoolVar(&stepConfig.ScanChangedModulesOnly, "scanChangedModulesOnly", false, "If enabled, only the Maven modules affected by the changes of the pull request (or of the change set of the build) are checked. Modules depending on changed modules are checked as well. The step is skipped if no module is affected. The analyzed changes as well as the checked and skipped modules are written to `mavenExecuteStaticCodeChecks_changeAnalysis.json`. If the changes cannot be determined, all modules are checked.")
+	cmd.Flags().StringSliceVar(&stepConfig.ChangeAnalysisGlobalFiles, "changeAnalysisGlobalFiles", []string{`.pipeline/**`}, "For `scanChangedModulesOnly`: Glob patterns of files whose modification requires checking all modules.")
 
 }
 
@@ -275,6 +279,24 @@ func mavenExecuteStaticCodeChecksMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     false,
 					},
+					{
+						Name:        "scanChangedModulesOnly",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name:        "changeAnalysisGlobalFiles",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{`.pipeline/**`},
+					},
 				},
 			},
 			Containers: []config.Container{
@@ -6,9 +6,11 @@ import (
 	"os"
 	"testing"
 
+	"github.com/SAP/jenkins-library/pkg/git"
 	"github.com/SAP/jenkins-library/pkg/mock"
 
 	"github.com/SAP/jenkins-library/pkg/maven"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -49,7 +51,7 @@ func TestRunMavenStaticCodeChecks(t *testing.T) {
 		defer func() { _ = os.Chdir(currentDir) }()
 		_ = os.Chdir("../test/resources/maven/")
 
-		err = runMavenStaticCodeChecks(&config, nil, utils)
+		_, err = runMavenStaticCodeChecks(&config, nil, utils, nil)
 
 		assert.Nil(t, err)
 		assert.Equal(t, expected, utils.Calls[0])
@@ -60,10 +62,63 @@ func TestRunMavenStaticCodeChecks(t *testing.T) {
 			SpotBugs: false,
 			Pmd:      false,
 		}
-		err := runMavenStaticCodeChecks(&config, nil, utils)
+		_, err := runMavenStaticCodeChecks(&config, nil, utils, nil)
 		assert.Nil(t, err)
 		assert.Nil(t, utils.Calls)
 	})
+	t.Run("should check only the modules affected by the changes", func(t *testing.T) {
+		utils := newMavenStaticCodeChecksTestUtilsBundle()
+		addStaticCodeChecksTestModules(utils)
+		config := mavenExecuteStaticCodeChecksOptions{Pmd: true, ScanChangedModulesOnly: true}
+		changedFiles := func() (git.ChangeRange, []string, error) {
+			return git.ChangeRange{Base: "main", Head: "HEAD"}, []string{"core/src/main/java/Core.java"}, nil
+		}
+
+		reports, err := runMavenStaticCodeChecks(&config, nil, utils, changedFiles)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []piperutils.Path{{Name: "Change Analysis", Target: "mavenExecuteStaticCodeChecks_changeAnalysis.json"}}, reports)
+		assert.Equal(t, []string{"-pl", "core,app"}, utils.Calls[0].Params[:2])
+		report, err := utils.FileRead("mavenExecuteStaticCodeChecks_changeAnalysis.json")
+		assert.NoError(t, err)
+		assert.Contains(t, string(report), `"name": "com.example:docs"`)
+	})
+	t.Run("should skip execution if no module is affected", func(t *testing.T) {
+		utils := newMavenStaticCodeChecksTestUtilsBundle()
+		addStaticCodeChecksTestModules(utils)
+		config := mavenExecuteStaticCodeChecksOptions{Pmd: true, ScanChangedModulesOnly: true}
+		changedFiles := func() (git.ChangeRange, []string, error) {
+			return git.ChangeRange{Base: "main", Head: "HEAD"}, []string{"README.md"}, nil
+		}
+
+		reports, err := runMavenStaticCodeChecks(&config, nil, utils, changedFiles)
+
+		assert.NoError(t, err)
+		assert.Len(t, reports, 1)
+		assert.Nil(t, utils.Calls)
+	})
+	t.Run("should check all modules if the changes cannot be determined", func(t *testing.T) {
+		utils := newMavenStaticCodeChecksTestUtilsBundle()
+		addStaticCodeChecksTestModules(utils)
+		config := mavenExecuteStaticCodeChecksOptions{Pmd: true, ScanChangedModulesOnly: true}
+		changedFiles := func() (git.ChangeRange, []string, error) {
+			return git.ChangeRange{}, nil, errors.New("no pull request and no change set available")
+		}
+
+		reports, err := runMavenStaticCodeChecks(&config, nil, utils, changedFiles)
+
+		assert.NoError(t, err)
+		assert.Empty(t, reports)
+		assert.NotContains(t, utils.Calls[0].Params, "-pl")
+		assert.False(t, utils.HasWrittenFile("mavenExecuteStaticCodeChecks_changeAnalysis.json"))
+	})
+}
+
+func addStaticCodeChecksTestModules(utils mavenStaticCodeChecksTestUtilsBundle) {
+	utils.AddFile("pom.xml", []byte(`<project><groupId>com.example</groupId><artifactId>root</artifactId><packaging>pom</packaging><modules><module>core</module><module>app</module><module>docs</module></modules></project>`))
+	utils.AddFile("core/pom.xml", []byte(`<project><groupId>com.example</groupId><artifactId>core</artifactId></project>`))
+	utils.AddFile("app/pom.xml", []byte(`<project><groupId>com.example</groupId><artifactId>app</artifactId><dependencies><dependency><groupId>com.example</groupId><artifactId>core</artifactId></dependency></dependencies></project>`))
+	utils.AddFile("docs/pom.xml", []byte(`<project><groupId>com.example</groupId><artifactId>docs</artifactId></project>`))
 }
 
 func TestGetPmdMavenParameters(t *testing.T) {
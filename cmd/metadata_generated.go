@@ -40,6 +40,7 @@ func GetAllStepMetadata() map[string]config.StepData {
 		"azureBlobUpload":                           azureBlobUploadMetadata(),
 		"batsExecuteTests":                          batsExecuteTestsMetadata(),
 		"checkmarxExecuteScan":                      checkmarxExecuteScanMetadata(),
+		"checkmarxOneExecuteScan":                   checkmarxOneExecuteScanMetadata(),
 		"cloudFoundryCreateService":                 cloudFoundryCreateServiceMetadata(),
 		"cloudFoundryCreateServiceKey":              cloudFoundryCreateServiceKeyMetadata(),
 		"cloudFoundryCreateSpace":                   cloudFoundryCreateSpaceMetadata(),
@@ -53,6 +54,7 @@ func GetAllStepMetadata() map[string]config.StepData {
 		"credentialdiggerScan":                      credentialdiggerScanMetadata(),
 		"detectExecuteScan":                         detectExecuteScanMetadata(),
 		"fortifyExecuteScan":                        fortifyExecuteScanMetadata(),
+		"fortifySyncAudits":                         fortifySyncAuditsMetadata(),
 		"gaugeExecuteTests":                         gaugeExecuteTestsMetadata(),
 		"gctsCloneRepository":                       gctsCloneRepositoryMetadata(),
 		"gctsCreateRepository":                      gctsCreateRepositoryMetadata(),
@@ -71,6 +73,7 @@ func GetAllStepMetadata() map[string]config.StepData {
 		"gradleExecuteBuild":                        gradleExecuteBuildMetadata(),
 		"hadolintExecute":                           hadolintExecuteMetadata(),
 		"helmExecute":                               helmExecuteMetadata(),
+		"iacExecuteScan":                            iacExecuteScanMetadata(),
 		"influxWriteData":                           influxWriteDataMetadata(),
 		"integrationArtifactDeploy":                 integrationArtifactDeployMetadata(),
 		"integrationArtifactDownload":               integrationArtifactDownloadMetadata(),
@@ -87,6 +90,7 @@ func GetAllStepMetadata() map[string]config.StepData {
 		"kanikoExecute":                             kanikoExecuteMetadata(),
 		"karmaExecuteTests":                         karmaExecuteTestsMetadata(),
 		"kubernetesDeploy":                          kubernetesDeployMetadata(),
+		"licenseComplianceCheck":                    licenseComplianceCheckMetadata(),
 		"malwareExecuteScan":                        malwareExecuteScanMetadata(),
 		"mavenBuild":                                mavenBuildMetadata(),
 		"mavenExecute":                              mavenExecuteMetadata(),
@@ -97,9 +101,12 @@ func GetAllStepMetadata() map[string]config.StepData {
 		"nexusUpload":                               nexusUploadMetadata(),
 		"npmExecuteLint":                            npmExecuteLintMetadata(),
 		"npmExecuteScripts":                         npmExecuteScriptsMetadata(),
+		"osvExecuteScan":                            osvExecuteScanMetadata(),
 		"pipelineCreateScanSummary":                 pipelineCreateScanSummaryMetadata(),
 		"protecodeExecuteScan":                      protecodeExecuteScanMetadata(),
 		"pythonBuild":                               pythonBuildMetadata(),
+		"sbomProcess":                               sbomProcessMetadata(),
+		"secretExecuteScan":                         secretExecuteScanMetadata(),
 		"shellExecute":                              shellExecuteMetadata(),
 		"sonarExecuteScan":                          sonarExecuteScanMetadata(),
 		"terraformExecute":                          terraformExecuteMetadata(),
@@ -21,6 +21,7 @@ import (
 	piperhttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/maven"
+	"github.com/SAP/jenkins-library/pkg/piperenv"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 	"github.com/ghodss/yaml"
@@ -252,6 +253,7 @@ func runMtaBuild(config mtaBuildOptions,
 	commonPipelineEnvironment.custom.buildSettingsInfo = buildSettingsInfo
 
 	commonPipelineEnvironment.mtarFilePath = filepath.ToSlash(getMtarFilePath(config, mtarName))
+	commonPipelineEnvironment.custom.artifacts = append(commonPipelineEnvironment.custom.artifacts, piperenv.Artifact{Name: filepath.Base(mtarName), Path: commonPipelineEnvironment.mtarFilePath, Type: "archive"})
 	commonPipelineEnvironment.custom.mtaBuildToolDesc = filepath.ToSlash(mtaYamlFile)
 
 	if config.InstallArtifacts {
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/SAP/jenkins-library/pkg/piperenv"
 	"github.com/ghodss/yaml"
 	"github.com/stretchr/testify/assert"
 )
@@ -161,6 +162,7 @@ func TestMtaBuild(t *testing.T) {
 		utilsMock := newMtaBuildTestUtilsBundle()
 
 		cpe.mtarFilePath = ""
+		cpe.custom.artifacts = piperenv.Artifacts{{Name: "app.jar", Path: "srv/target/app.jar", Type: "archive"}}
 
 		options := mtaBuildOptions{ApplicationName: "myApp", Platform: "CF", MtarName: "myName.mtar", Source: "./", Target: "./"}
 
@@ -175,6 +177,10 @@ func TestMtaBuild(t *testing.T) {
 			assert.Equal(t, []string{"build", "--mtar", "myName.mtar", "--platform", "CF", "--source", filepath.FromSlash("./"), "--target", filepath.FromSlash(_ignoreError(os.Getwd()))}, utilsMock.Calls[0].Params)
 		}
 		assert.Equal(t, "myName.mtar", cpe.mtarFilePath)
+		assert.Equal(t, piperenv.Artifacts{
+			{Name: "app.jar", Path: "srv/target/app.jar", Type: "archive"},
+			{Name: "myName.mtar", Path: "myName.mtar", Type: "archive"},
+		}, cpe.custom.artifacts)
 	})
 
 	t.Run("Source and target related tests", func(t *testing.T) {
@@ -183,6 +189,7 @@ func TestMtaBuild(t *testing.T) {
 			utilsMock := newMtaBuildTestUtilsBundle()
 
 			cpe.mtarFilePath = ""
+			cpe.custom.artifacts = nil
 
 			options := mtaBuildOptions{ApplicationName: "myApp", Platform: "CF", MtarName: "myName.mtar", Source: "mySourcePath/", Target: "myTargetPath/"}
 
@@ -200,6 +207,7 @@ func TestMtaBuild(t *testing.T) {
 					utilsMock.Calls[0].Params)
 			}
 			assert.Equal(t, "mySourcePath/myTargetPath/myName.mtar", cpe.mtarFilePath)
+			assert.Equal(t, piperenv.Artifacts{{Name: "myName.mtar", Path: "mySourcePath/myTargetPath/myName.mtar", Type: "archive"}}, cpe.custom.artifacts)
 			assert.Equal(t, "mySourcePath/mta.yaml", cpe.custom.mtaBuildToolDesc)
 		})
 	})
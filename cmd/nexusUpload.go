@@ -13,6 +13,7 @@ import (
 	b64 "encoding/base64"
 
 	"github.com/SAP/jenkins-library/pkg/command"
+	"github.com/SAP/jenkins-library/pkg/gradle"
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/maven"
 	"github.com/SAP/jenkins-library/pkg/nexus"
@@ -44,6 +45,7 @@ type nexusUploadUtils interface {
 	UsesMta() bool
 	UsesMaven() bool
 	UsesNpm() bool
+	UsesGradle() bool
 
 	getEnvParameter(path, name string) string
 	evaluate(options *maven.EvaluateOptions, expression string) (string, error)
@@ -134,6 +136,9 @@ func runNexusUpload(utils nexusUploadUtils, uploader nexus.Uploader, options *ne
 		} else if utils.UsesMaven() {
 			log.Entry().Info("Maven project structure detected")
 			return uploadMaven(utils, uploader, options)
+		} else if utils.UsesGradle() {
+			log.Entry().Info("Gradle project structure detected")
+			return uploadGradle(utils, uploader, options)
 		}
 	} else {
 		log.Entry().Info("Skipping maven and mta upload because mavenRepository option is not provided.")
@@ -448,6 +453,52 @@ func uploadMavenArtifacts(utils nexusUploadUtils, uploader nexus.Uploader, optio
 	return err
 }
 
+// uploadGradle uploads the archives of all projects of a Gradle build, the coordinates are taken from the project model of the build
+func uploadGradle(utils nexusUploadUtils, uploader nexus.Uploader, options *nexusUploadOptions) error {
+	useWrapper, _ := utils.FileExists("gradlew")
+	model, err := gradle.GetProjectModel("", useWrapper, utils)
+	if err != nil {
+		return err
+	}
+	for _, project := range model.Projects {
+		mainArtifact, found := project.MainArtifact()
+		if !found {
+			log.Entry().Infof("Ignoring project '%s' as it does not create a jar", project.Path)
+			continue
+		}
+		if exists, _ := utils.FileExists(mainArtifact.File); !exists {
+			log.Entry().Warnf("Ignoring project '%s' as its artifact '%s' has not been built", project.Path, mainArtifact.File)
+			continue
+		}
+		groupID := project.GroupID
+		if groupID == "" {
+			groupID = options.GroupID
+		}
+		if err := uploader.SetInfo(groupID, project.ArtifactID, project.Version); err != nil {
+			return fmt.Errorf("invalid coordinates of project '%s': %w", project.Path, err)
+		}
+		if err := addArtifact(utils, uploader, mainArtifact.File, "", mainArtifact.Extension); err != nil {
+			return err
+		}
+		for _, artifact := range project.Artifacts {
+			if artifact.Classifier == "" {
+				continue
+			}
+			if exists, _ := utils.FileExists(artifact.File); !exists {
+				log.Entry().Debugf("Skipping artifact '%s' as it has not been built", artifact.File)
+				continue
+			}
+			if err := addArtifact(utils, uploader, artifact.File, artifact.Classifier, artifact.Extension); err != nil {
+				return err
+			}
+		}
+		if err := uploadArtifacts(utils, uploader, options, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func addMavenTargetArtifacts(utils nexusUploadUtils, uploader nexus.Uploader, pomFile, targetFolder, finalBuildName, packaging string) error {
 	fileTypes := []string{packaging}
 	if packaging != "jar" {
@@ -20,6 +20,7 @@ type mockUtilsBundle struct {
 	mta        bool
 	maven      bool
 	npm        bool
+	gradle     bool
 	properties map[string]map[string]string
 	cpe        map[string]string
 }
@@ -53,6 +54,10 @@ func (m *mockUtilsBundle) UsesNpm() bool {
 	return m.npm
 }
 
+func (m *mockUtilsBundle) UsesGradle() bool {
+	return m.gradle
+}
+
 func (m *mockUtilsBundle) getEnvParameter(path, name string) string {
 	path = path + "/" + name
 	return m.cpe[path]
@@ -701,6 +706,63 @@ func TestUploadMavenProjects(t *testing.T) {
 	})
 }
 
+func TestUploadGradleProjects(t *testing.T) {
+	t.Parallel()
+	const gradleModel = `{"projects": [
+		{"path": ":", "name": "service", "dir": ".", "groupId": "", "artifactId": "service", "version": "1.2.3", "packaging": "pom"},
+		{"path": ":api", "name": "api", "dir": "api", "groupId": "com.example", "artifactId": "service-api", "version": "1.2.3", "packaging": "jar",
+			"artifacts": [{"file": "api/build/libs/api-1.2.3.jar", "extension": "jar"}, {"file": "api/build/libs/api-1.2.3-sources.jar", "classifier": "sources", "extension": "jar"},
+				{"file": "api/build/libs/api-1.2.3-javadoc.jar", "classifier": "javadoc", "extension": "jar"}]},
+		{"path": ":app", "name": "app", "dir": "app", "groupId": "", "artifactId": "app", "version": "1.2.3", "packaging": "war",
+			"artifacts": [{"file": "app/build/libs/app-1.2.3.war", "extension": "war"}]},
+		{"path": ":tests", "name": "tests", "dir": "tests", "groupId": "com.example", "artifactId": "tests", "version": "1.2.3", "packaging": "jar",
+			"artifacts": [{"file": "tests/build/libs/tests-1.2.3.jar", "extension": "jar"}]}
+	]}`
+
+	t.Run("Test uploading Gradle multi-project build works", func(t *testing.T) {
+		t.Parallel()
+		utils := newMockUtilsBundle(false, false, false)
+		utils.gradle = true
+		utils.AddFile("build.gradle", []byte{})
+		utils.AddFile("piperProjectModel.json.tmp", []byte(gradleModel))
+		utils.AddFile(filepath.Join("api", "build", "libs", "api-1.2.3.jar"), []byte("contentsOfJar"))
+		utils.AddFile(filepath.Join("api", "build", "libs", "api-1.2.3-sources.jar"), []byte("contentsOfJar"))
+		utils.AddFile(filepath.Join("app", "build", "libs", "app-1.2.3.war"), []byte("contentsOfWar"))
+		uploader := mockUploader{}
+		options := createOptions()
+
+		err := runNexusUpload(utils, &uploader, &options)
+		assert.NoError(t, err, "expected Gradle upload to work")
+
+		artifacts := uploader.uploadedArtifacts
+		if assert.Equal(t, 3, len(artifacts)) {
+			assert.Equal(t, nexus.ArtifactDescription{File: filepath.Join("api", "build", "libs", "api-1.2.3.jar"), Type: "jar"}, artifacts[0])
+			assert.Equal(t, nexus.ArtifactDescription{File: filepath.Join("api", "build", "libs", "api-1.2.3-sources.jar"), Type: "jar", Classifier: "sources"}, artifacts[1])
+			assert.Equal(t, nexus.ArtifactDescription{File: filepath.Join("app", "build", "libs", "app-1.2.3.war"), Type: "war"}, artifacts[2])
+		}
+		if assert.Equal(t, 4, len(utils.Calls)) {
+			assert.Equal(t, "gradle", utils.Calls[1].Exec)
+			assert.Contains(t, utils.Calls[2].Params, "-DgroupId=com.example")
+			assert.Contains(t, utils.Calls[2].Params, "-DartifactId=service-api")
+			assert.Contains(t, utils.Calls[2].Params, "-Dfiles="+filepath.Join("api", "build", "libs", "api-1.2.3-sources.jar"))
+			// the group falls back to the parameter
+			assert.Contains(t, utils.Calls[3].Params, "-DgroupId=my.group.id")
+			assert.Contains(t, utils.Calls[3].Params, "-Dpackaging=war")
+		}
+	})
+
+	t.Run("Uploading Gradle project fails due to failing build", func(t *testing.T) {
+		t.Parallel()
+		utils := newMockUtilsBundle(false, false, false)
+		utils.gradle = true
+		uploader := mockUploader{}
+		options := createOptions()
+
+		err := runNexusUpload(utils, &uploader, &options)
+		assert.EqualError(t, err, "failed to retrieve the project model of the gradle build: the specified gradle build script could not be found: no build script available, supported: [build.gradle build.gradle.kts]")
+	})
+}
+
 func TestSetupNexusCredentialsSettingsFile(t *testing.T) {
 	t.Parallel()
 	utils := newMockUtilsBundle(false, true, false)
@@ -1,24 +1,73 @@
 package cmd
 
 import (
+	"os"
+	"path"
+
+	"github.com/SAP/jenkins-library/pkg/buildcache"
 	"github.com/SAP/jenkins-library/pkg/buildsettings"
+	"github.com/SAP/jenkins-library/pkg/git"
 	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/monorepo"
 	"github.com/SAP/jenkins-library/pkg/npm"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 )
 
+type npmExecuteScriptsUtils interface {
+	git.ModuleUtils
+	git.ReportUtils
+	WriteFile(path string, content []byte, perm os.FileMode) error
+	ChangedFiles() (git.ChangeRange, []string, error)
+}
+
+type npmExecuteScriptsUtilsBundle struct {
+	*piperutils.Files
+}
+
+func (n *npmExecuteScriptsUtilsBundle) ChangedFiles() (git.ChangeRange, []string, error) {
+	return git.ChangedFilesOfCurrentRun(".")
+}
+
 func npmExecuteScripts(config npmExecuteScriptsOptions, telemetryData *telemetry.CustomData, commonPipelineEnvironment *npmExecuteScriptsCommonPipelineEnvironment) {
 	npmExecutorOptions := npm.ExecutorOptions{DefaultNpmRegistry: config.DefaultNpmRegistry}
 	npmExecutor := npm.NewExecutor(npmExecutorOptions)
 
-	err := runNpmExecuteScripts(npmExecutor, &config, commonPipelineEnvironment)
+	utils := &npmExecuteScriptsUtilsBundle{Files: &piperutils.Files{}}
+	cache, closeCache := newBuildCache(config.BuildCache, config.BuildCacheLocation, config.BuildCacheMaxSize, utils)
+	defer closeCache()
+	cacheDirectories := []*buildcache.Directory{}
+	if cacheDirectory, err := buildcache.NodeDirectory(utils); err != nil {
+		log.Entry().WithError(err).Warn("failed to determine npm cache directory")
+	} else {
+		cacheDirectories = append(cacheDirectories, cacheDirectory)
+	}
+	err := runWithBuildCache(cache, cacheDirectories, func() error {
+		return runNpmExecuteScripts(npmExecutor, &config, commonPipelineEnvironment, utils)
+	})
 	if err != nil {
 		log.SetErrorCategory(log.ErrorBuild)
 		log.Entry().WithError(err).Fatal("step execution failed")
 	}
 }
 
-func runNpmExecuteScripts(npmExecutor npm.Executor, config *npmExecuteScriptsOptions, commonPipelineEnvironment *npmExecuteScriptsCommonPipelineEnvironment) error {
+func runNpmExecuteScripts(npmExecutor npm.Executor, config *npmExecuteScriptsOptions, commonPipelineEnvironment *npmExecuteScriptsCommonPipelineEnvironment, utils npmExecuteScriptsUtils) error {
+	if len(config.WorkspaceProjects) > 0 {
+		// only the changed projects of the monorepo are built, they are provided in dependency order
+		projects, err := monorepo.ChangedProjects(config.WorkspaceProjects, "npm", "yarn")
+		if err != nil {
+			return err
+		}
+		if len(projects) == 0 {
+			log.Entry().Info("no npm project of the workspace changed since its last release, skipping npm build")
+			return nil
+		}
+		config.BuildDescriptorList = []string{}
+		for _, project := range projects {
+			config.BuildDescriptorList = append(config.BuildDescriptorList, path.Join(project.Path, "package.json"))
+		}
+	}
+
 	if config.Install {
 		if len(config.BuildDescriptorList) > 0 {
 			if err := npmExecutor.InstallAllDependencies(config.BuildDescriptorList); err != nil {
@@ -53,8 +102,16 @@ func runNpmExecuteScripts(npmExecutor npm.Executor, config *npmExecuteScriptsOpt
 		}
 	}
 
-	err := npmExecutor.RunScriptsInAllPackages(config.RunScripts, nil, config.ScriptOptions, config.VirtualFrameBuffer, config.BuildDescriptorExcludeList, config.BuildDescriptorList)
-	if err != nil {
+	scriptPackages := config.BuildDescriptorList
+	if config.RunChangedWorkspacesOnly && len(scriptPackages) == 0 {
+		var err error
+		if scriptPackages, err = npmChangedWorkspaces(config, utils); err != nil {
+			return err
+		}
+	}
+	if scriptPackages != nil && len(scriptPackages) == 0 {
+		log.Entry().Info("No npm workspace is affected by the changes. Skipping scripts")
+	} else if err := npmExecutor.RunScriptsInAllPackages(config.RunScripts, nil, config.ScriptOptions, config.VirtualFrameBuffer, config.BuildDescriptorExcludeList, scriptPackages); err != nil {
 		return err
 	}
 
@@ -79,9 +136,12 @@ func runNpmExecuteScripts(npmExecutor npm.Executor, config *npmExecuteScriptsOpt
 	commonPipelineEnvironment.custom.buildSettingsInfo = buildSettingsInfo
 
 	if config.Publish {
-		packageJSONFiles, err := npmExecutor.FindPackageJSONFilesWithExcludes(config.BuildDescriptorExcludeList)
-		if err != nil {
-			return err
+		packageJSONFiles := config.BuildDescriptorList
+		if len(config.WorkspaceProjects) == 0 {
+			packageJSONFiles, err = npmExecutor.FindPackageJSONFilesWithExcludes(config.BuildDescriptorExcludeList)
+			if err != nil {
+				return err
+			}
 		}
 
 		err = npmExecutor.PublishAllPackages(packageJSONFiles, config.RepositoryURL, config.RepositoryUsername, config.RepositoryPassword, config.PackBeforePublish)
@@ -92,3 +152,36 @@ func runNpmExecuteScripts(npmExecutor npm.Executor, config *npmExecuteScriptsOpt
 
 	return nil
 }
+
+// npmChangedWorkspaces returns the package.json files of the workspaces to execute the scripts in.
+// It returns nil if all packages are affected and an empty list if no workspace is affected by the changes.
+func npmChangedWorkspaces(config *npmExecuteScriptsOptions, utils npmExecuteScriptsUtils) ([]string, error) {
+	changeRange, files, err := utils.ChangedFiles()
+	if err != nil {
+		log.Entry().WithError(err).Warn("Failed to determine the changes of the pipeline run, the scripts will be executed in all packages")
+		return nil, nil
+	}
+	modules, err := git.DiscoverNpmWorkspaces(utils)
+	if err != nil {
+		return nil, err
+	}
+	analysis := git.AnalyzeChanges(changeRange, files, modules, config.ChangeAnalysisGlobalFiles)
+	reportPath, err := git.WriteChangeAnalysisReport(analysis, "npmExecuteScripts", utils)
+	if err != nil {
+		return nil, err
+	}
+	piperutils.PersistReportsAndLinks("npmExecuteScripts", "", utils, []piperutils.Path{reportPath}, nil)
+
+	if len(analysis.SkippedModules) == 0 {
+		// all workspaces are affected, the packages are discovered considering the buildDescriptorExcludeList
+		return nil, nil
+	}
+	for _, module := range analysis.SkippedModules {
+		log.Entry().Infof("Skipping scripts of npm workspace '%v' since it is not affected by the changes", module.Name)
+	}
+	packageJSONFiles := []string{}
+	for _, module := range analysis.AffectedModules {
+		packageJSONFiles = append(packageJSONFiles, path.Join(module.Path, "package.json"))
+	}
+	return packageJSONFiles, nil
+}
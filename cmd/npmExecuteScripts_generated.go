@@ -22,20 +22,26 @@ import (
 )
 
 type npmExecuteScriptsOptions struct {
-	Install                    bool     `json:"install,omitempty"`
-	RunScripts                 []string `json:"runScripts,omitempty"`
-	DefaultNpmRegistry         string   `json:"defaultNpmRegistry,omitempty"`
-	VirtualFrameBuffer         bool     `json:"virtualFrameBuffer,omitempty"`
-	ScriptOptions              []string `json:"scriptOptions,omitempty"`
-	BuildDescriptorExcludeList []string `json:"buildDescriptorExcludeList,omitempty"`
-	BuildDescriptorList        []string `json:"buildDescriptorList,omitempty"`
-	CreateBOM                  bool     `json:"createBOM,omitempty"`
-	Publish                    bool     `json:"publish,omitempty"`
-	RepositoryURL              string   `json:"repositoryUrl,omitempty"`
-	RepositoryPassword         string   `json:"repositoryPassword,omitempty"`
-	RepositoryUsername         string   `json:"repositoryUsername,omitempty"`
-	BuildSettingsInfo          string   `json:"buildSettingsInfo,omitempty"`
-	PackBeforePublish          bool     `json:"packBeforePublish,omitempty"`
+	Install                    bool                     `json:"install,omitempty"`
+	RunScripts                 []string                 `json:"runScripts,omitempty"`
+	DefaultNpmRegistry         string                   `json:"defaultNpmRegistry,omitempty"`
+	VirtualFrameBuffer         bool                     `json:"virtualFrameBuffer,omitempty"`
+	ScriptOptions              []string                 `json:"scriptOptions,omitempty"`
+	BuildDescriptorExcludeList []string                 `json:"buildDescriptorExcludeList,omitempty"`
+	BuildDescriptorList        []string                 `json:"buildDescriptorList,omitempty"`
+	CreateBOM                  bool                     `json:"createBOM,omitempty"`
+	Publish                    bool                     `json:"publish,omitempty"`
+	RepositoryURL              string                   `json:"repositoryUrl,omitempty"`
+	RepositoryPassword         string                   `json:"repositoryPassword,omitempty"`
+	RepositoryUsername         string                   `json:"repositoryUsername,omitempty"`
+	BuildSettingsInfo          string                   `json:"buildSettingsInfo,omitempty"`
+	PackBeforePublish          bool                     `json:"packBeforePublish,omitempty"`
+	WorkspaceProjects          []map[string]interface{} `json:"workspaceProjects,omitempty"`
+	RunChangedWorkspacesOnly   bool                     `json:"runChangedWorkspacesOnly,omitempty"`
+	ChangeAnalysisGlobalFiles  []string                 `json:"changeAnalysisGlobalFiles,omitempty"`
+	BuildCache                 bool                     `json:"buildCache,omitempty"`
+	BuildCacheLocation         string                   `json:"buildCacheLocation,omitempty"`
+	BuildCacheMaxSize          int                      `json:"buildCacheMaxSize,omitempty"`
 }
 
 type npmExecuteScriptsCommonPipelineEnvironment struct {
@@ -136,7 +142,18 @@ registry=https://registry.npmjs.org
 ` + "`" + `` + "`" + `` + "`" + `
 ` + "`" + `PIPER_VAULTCREDENTIAL_USER` + "`" + ` and ` + "`" + `PIPER_VAULTCREDENTIAL_PASSWORD_BASE64` + "`" + ` (Base64 encoded password) are the username and password for the private repository
 and are exposed are environment variables that must be present in the environment where the Piper step runs or alternatively can be created using :
-[vault general purpose credentials](../infrastructure/vault.md#using-vault-for-general-purpose-and-test-credentials)`,
+[vault general purpose credentials](../infrastructure/vault.md#using-vault-for-general-purpose-and-test-credentials)
+
+### pnpm and Yarn workspaces
+The package manager of a project is detected from its lock file: ` + "`" + `package-lock.json` + "`" + ` (npm), ` + "`" + `yarn.lock` + "`" + ` (Yarn, Yarn 2 and later in case of a ` + "`" + `.yarnrc.yml` + "`" + ` or a Yarn 2 lock file)
+or ` + "`" + `pnpm-lock.yaml` + "`" + ` (pnpm). Without lock file, the ` + "`" + `packageManager` + "`" + ` field of the ` + "`" + `package.json` + "`" + ` is considered.
+pnpm or Yarn need to be available in the execution environment, e.g. via [Corepack](https://nodejs.org/api/corepack.html).
+
+Workspaces defined via ` + "`" + `pnpm-workspace.yaml` + "`" + ` or the ` + "`" + `workspaces` + "`" + ` field of the root ` + "`" + `package.json` + "`" + ` are installed once in the workspace root.
+The scripts of the workspace packages are executed after the scripts of the workspace packages they depend on.
+pnpm and Yarn 2 and later execute the scripts themselves, i.e. ` + "`" + `pnpm run <script>` + "`" + ` or ` + "`" + `yarn run <script>` + "`" + `.
+For Yarn 2 and later, the ` + "`" + `defaultNpmRegistry` + "`" + ` is configured as ` + "`" + `npmRegistryServer` + "`" + ` unless a registry is configured in ` + "`" + `.yarnrc.yml` + "`" + `.
+When publishing, private workspace packages are skipped and the packages are packed by pnpm or Yarn in order to resolve ` + "`" + `workspace:` + "`" + ` dependencies.`,
 		PreRunE: func(cmd *cobra.Command, _ []string) error {
 			startTime = time.Now()
 			log.SetStepName(STEP_NAME)
@@ -234,6 +251,12 @@ func addNpmExecuteScriptsFlags(cmd *cobra.Command, stepConfig *npmExecuteScripts
 	cmd.Flags().StringVar(&stepConfig.BuildSettingsInfo, "buildSettingsInfo", os.Getenv("PIPER_buildSettingsInfo"), "build settings info is typically filled by the step automatically to create information about the build settings that were used during the npm build . This information is typically used for compliance related processes.")
 	cmd.Flags().BoolVar(&stepConfig.PackBeforePublish, "packBeforePublish", false, "used for executing npm pack first, followed by npm publish. This two step maybe required when you are building a scoped packages and have npm dependencies from the same scope")
 
+	cmd.Flags().BoolVar(&stepConfig.RunChangedWorkspacesOnly, "runChangedWorkspacesOnly", false, "If enabled, the scripts are only executed in the npm workspaces affected by the changes of the pull request (or of the change set of the build). Workspaces depending on changed workspaces are affected as well. The scripts are skipped if no workspace is affected. The analyzed changes as well as the affected and skipped workspaces are written to `npmExecuteScripts_changeAnalysis.json`. If the changes cannot be determined, the scripts are executed in all packages. The parameter is ignored if `buildDescriptorList` is provided.")
+	cmd.Flags().StringSliceVar(&stepConfig.ChangeAnalysisGlobalFiles, "changeAnalysisGlobalFiles", []string{`.pipeline/**`}, "For `runChangedWorkspacesOnly`: Glob patterns of files whose modification requires executing the scripts in all workspaces.")
+	cmd.Flags().BoolVar(&stepConfig.BuildCache, "buildCache", false, "Restore the dependency caches of the build tool before the build and save them afterwards.")
+	cmd.Flags().StringVar(&stepConfig.BuildCacheLocation, "buildCacheLocation", os.Getenv("PIPER_buildCacheLocation"), "Location of the build cache: a local directory, `gs://<bucket>/<folder>` or `s3://<bucket>/<prefix>`.")
+	cmd.Flags().IntVar(&stepConfig.BuildCacheMaxSize, "buildCacheMaxSize", 2048, "Maximum size of a compressed cache directory in MB, larger caches are not saved.")
+
 }
 
 // retrieve step metadata
@@ -411,6 +434,64 @@ func npmExecuteScriptsMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     false,
 					},
+					{
+						Name: "workspaceProjects",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "custom/workspaceProjects",
+							},
+						},
+						Scope:     []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:      "[]map[string]interface{}",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+					},
+					{
+						Name:        "runChangedWorkspacesOnly",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"STEPS", "STAGES", "PARAMETERS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name:        "changeAnalysisGlobalFiles",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"STEPS", "STAGES", "PARAMETERS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{`.pipeline/**`},
+					},
+					{
+						Name:        "buildCache",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name:        "buildCacheLocation",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_buildCacheLocation"),
+					},
+					{
+						Name:        "buildCacheMaxSize",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     2048,
+					},
 				},
 			},
 			Containers: []config.Container{
@@ -1,8 +1,10 @@
 package cmd
 
 import (
+	"errors"
 	"testing"
 
+	"github.com/SAP/jenkins-library/pkg/git"
 	"github.com/SAP/jenkins-library/pkg/mock"
 	"github.com/SAP/jenkins-library/pkg/npm"
 	"github.com/stretchr/testify/assert"
@@ -25,6 +27,30 @@ func newNpmMockUtilsBundle() NpmMockUtilsBundle {
 	return utils
 }
 
+type npmExecuteScriptsMockUtils struct {
+	*mock.FilesMock
+	changedFiles      []string
+	changedFilesError error
+}
+
+func (n *npmExecuteScriptsMockUtils) ChangedFiles() (git.ChangeRange, []string, error) {
+	return git.ChangeRange{Base: "main", Head: "HEAD"}, n.changedFiles, n.changedFilesError
+}
+
+func newNpmExecuteScriptsMockUtils() *npmExecuteScriptsMockUtils {
+	return &npmExecuteScriptsMockUtils{FilesMock: &mock.FilesMock{}}
+}
+
+func newNpmWorkspaceMockUtils(changedFiles ...string) *npmExecuteScriptsMockUtils {
+	utils := newNpmExecuteScriptsMockUtils()
+	utils.changedFiles = changedFiles
+	utils.AddFile("package.json", []byte(`{"name": "root", "workspaces": ["packages/*"]}`))
+	utils.AddFile("packages/core/package.json", []byte(`{"name": "core"}`))
+	utils.AddFile("packages/app/package.json", []byte(`{"name": "app", "dependencies": {"core": "*"}}`))
+	utils.AddFile("packages/docs/package.json", []byte(`{"name": "docs"}`))
+	return utils
+}
+
 func TestNpmExecuteScripts(t *testing.T) {
 	cpe := npmExecuteScriptsCommonPipelineEnvironment{}
 
@@ -35,7 +61,7 @@ func TestNpmExecuteScripts(t *testing.T) {
 		utils.AddFile("src/package.json", []byte("{\"name\": \"Test\" }"))
 
 		npmExecutor := npm.NpmExecutorMock{Utils: utils, Config: npm.NpmConfig{Install: config.Install, RunScripts: config.RunScripts, PackagesList: config.BuildDescriptorList}}
-		err := runNpmExecuteScripts(&npmExecutor, &config, &cpe)
+		err := runNpmExecuteScripts(&npmExecutor, &config, &cpe, newNpmExecuteScriptsMockUtils())
 
 		assert.NoError(t, err)
 	})
@@ -47,7 +73,7 @@ func TestNpmExecuteScripts(t *testing.T) {
 		utils.AddFile("src/package.json", []byte("{\"name\": \"Test\" }"))
 
 		npmExecutor := npm.NpmExecutorMock{Utils: utils, Config: npm.NpmConfig{Install: config.Install, RunScripts: config.RunScripts, ExcludeList: config.BuildDescriptorExcludeList}}
-		err := runNpmExecuteScripts(&npmExecutor, &config, &cpe)
+		err := runNpmExecuteScripts(&npmExecutor, &config, &cpe, newNpmExecuteScriptsMockUtils())
 
 		assert.NoError(t, err)
 	})
@@ -59,7 +85,7 @@ func TestNpmExecuteScripts(t *testing.T) {
 		utils.AddFile("src/package.json", []byte("{\"name\": \"Test\" }"))
 
 		npmExecutor := npm.NpmExecutorMock{Utils: utils, Config: npm.NpmConfig{Install: config.Install, RunScripts: config.RunScripts, ScriptOptions: config.ScriptOptions}}
-		err := runNpmExecuteScripts(&npmExecutor, &config, &cpe)
+		err := runNpmExecuteScripts(&npmExecutor, &config, &cpe, newNpmExecuteScriptsMockUtils())
 
 		assert.NoError(t, err)
 	})
@@ -71,7 +97,7 @@ func TestNpmExecuteScripts(t *testing.T) {
 		utils.AddFile("src/package.json", []byte("{\"name\": \"Test\" }"))
 
 		npmExecutor := npm.NpmExecutorMock{Utils: utils, Config: npm.NpmConfig{Install: config.Install, RunScripts: config.RunScripts}}
-		err := runNpmExecuteScripts(&npmExecutor, &config, &cpe)
+		err := runNpmExecuteScripts(&npmExecutor, &config, &cpe, newNpmExecuteScriptsMockUtils())
 
 		assert.NoError(t, err)
 	})
@@ -83,7 +109,7 @@ func TestNpmExecuteScripts(t *testing.T) {
 		utils.AddFile("src/package.json", []byte("{\"name\": \"Test\" }"))
 
 		npmExecutor := npm.NpmExecutorMock{Utils: utils, Config: npm.NpmConfig{Install: config.Install, RunScripts: config.RunScripts}}
-		err := runNpmExecuteScripts(&npmExecutor, &config, &cpe)
+		err := runNpmExecuteScripts(&npmExecutor, &config, &cpe, newNpmExecuteScriptsMockUtils())
 
 		assert.NoError(t, err)
 	})
@@ -95,7 +121,7 @@ func TestNpmExecuteScripts(t *testing.T) {
 		utils.AddFile("src/package.json", []byte("{\"name\": \"Test\" }"))
 
 		npmExecutor := npm.NpmExecutorMock{Utils: utils, Config: npm.NpmConfig{Install: config.Install, RunScripts: config.RunScripts, VirtualFrameBuffer: config.VirtualFrameBuffer}}
-		err := runNpmExecuteScripts(&npmExecutor, &config, &cpe)
+		err := runNpmExecuteScripts(&npmExecutor, &config, &cpe, newNpmExecuteScriptsMockUtils())
 
 		assert.NoError(t, err)
 	})
@@ -111,7 +137,7 @@ func TestNpmExecuteScripts(t *testing.T) {
 
 		npmExecutor := npm.Execute{Utils: &utils, Options: options}
 
-		err := runNpmExecuteScripts(&npmExecutor, &config, &cpe)
+		err := runNpmExecuteScripts(&npmExecutor, &config, &cpe, newNpmExecuteScriptsMockUtils())
 
 		if assert.NoError(t, err) {
 			if assert.Equal(t, 4, len(utils.execRunner.Calls)) {
@@ -132,8 +158,105 @@ func TestNpmExecuteScripts(t *testing.T) {
 		utils.AddFile("src/package.json", []byte("{\"name\": \"Test\" }"))
 
 		npmExecutor := npm.Execute{Utils: &utils, Options: options}
-		err := runNpmExecuteScripts(&npmExecutor, &config, &cpe)
+		err := runNpmExecuteScripts(&npmExecutor, &config, &cpe, newNpmExecuteScriptsMockUtils())
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Call with workspace projects", func(t *testing.T) {
+		config := npmExecuteScriptsOptions{RunScripts: []string{"ci-build"}, Publish: true, WorkspaceProjects: []map[string]interface{}{
+			{"name": "ui-kit", "path": "libs/ui-kit", "buildTool": "npm", "version": "1.0.1-20221018120000", "changed": true},
+			{"name": "orders", "path": "services/orders", "buildTool": "maven", "version": "1.2.0-20221018120000", "changed": true},
+			{"name": "admin", "path": "ui/admin", "buildTool": "npm", "version": "0.9.0-20221001120000", "changed": false},
+			{"name": "shop", "path": "ui/shop", "buildTool": "yarn", "version": "2.0.0-20221018120000", "changed": true},
+		}}
+		utils := npm.NewNpmMockUtilsBundle()
+
+		npmExecutor := npm.NpmExecutorMock{Utils: utils, Config: npm.NpmConfig{RunScripts: config.RunScripts, PackagesList: []string{"libs/ui-kit/package.json", "ui/shop/package.json"}}}
+		err := runNpmExecuteScripts(&npmExecutor, &config, &cpe, newNpmExecuteScriptsMockUtils())
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"libs/ui-kit/package.json", "ui/shop/package.json"}, config.BuildDescriptorList)
+	})
+
+	t.Run("Call with changed workspaces only", func(t *testing.T) {
+		config := npmExecuteScriptsOptions{RunScripts: []string{"ci-build"}, RunChangedWorkspacesOnly: true}
+		utils := npm.NewNpmMockUtilsBundle()
+
+		npmExecutor := npm.NpmExecutorMock{Utils: utils, Config: npm.NpmConfig{RunScripts: config.RunScripts, PackagesList: []string{"packages/app/package.json", "packages/core/package.json"}}}
+		err := runNpmExecuteScripts(&npmExecutor, &config, &cpe, newNpmWorkspaceMockUtils("packages/core/src/index.js"))
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Call without changed workspaces", func(t *testing.T) {
+		config := npmExecuteScriptsOptions{RunScripts: []string{"ci-build"}, RunChangedWorkspacesOnly: true}
+		utils := npm.NewNpmMockUtilsBundle()
+
+		// the mock fails in case scripts are executed
+		npmExecutor := npm.NpmExecutorMock{Utils: utils, Config: npm.NpmConfig{}}
+		err := runNpmExecuteScripts(&npmExecutor, &config, &cpe, newNpmWorkspaceMockUtils("README.md"))
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Call without changed workspace projects", func(t *testing.T) {
+		config := npmExecuteScriptsOptions{RunScripts: []string{"ci-build"}, WorkspaceProjects: []map[string]interface{}{
+			{"name": "admin", "path": "ui/admin", "buildTool": "npm", "version": "0.9.0-20221001120000", "changed": false},
+		}}
+		utils := npm.NewNpmMockUtilsBundle()
+
+		// the mock fails in case scripts are executed
+		npmExecutor := npm.NpmExecutorMock{Utils: utils, Config: npm.NpmConfig{}}
+		err := runNpmExecuteScripts(&npmExecutor, &config, &cpe, newNpmExecuteScriptsMockUtils())
+
+		assert.NoError(t, err)
+		assert.Empty(t, config.BuildDescriptorList)
+	})
+}
+
+func TestNpmChangedWorkspaces(t *testing.T) {
+	t.Run("affected workspaces", func(t *testing.T) {
+		config := npmExecuteScriptsOptions{RunChangedWorkspacesOnly: true, ChangeAnalysisGlobalFiles: []string{".pipeline/**"}}
+		utils := newNpmWorkspaceMockUtils("packages/core/src/index.js")
+
+		packageJSONFiles, err := npmChangedWorkspaces(&config, utils)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"packages/app/package.json", "packages/core/package.json"}, packageJSONFiles)
+		assert.True(t, utils.HasWrittenFile("npmExecuteScripts_changeAnalysis.json"))
+		assert.True(t, utils.HasWrittenFile("npmExecuteScripts_reports.json"))
+	})
+
+	t.Run("no affected workspace", func(t *testing.T) {
+		config := npmExecuteScriptsOptions{RunChangedWorkspacesOnly: true}
+		utils := newNpmWorkspaceMockUtils("README.md")
+
+		packageJSONFiles, err := npmChangedWorkspaces(&config, utils)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{}, packageJSONFiles)
+	})
+
+	t.Run("global file changed", func(t *testing.T) {
+		config := npmExecuteScriptsOptions{RunChangedWorkspacesOnly: true, ChangeAnalysisGlobalFiles: []string{".pipeline/**"}}
+		utils := newNpmWorkspaceMockUtils(".pipeline/config.yml")
+
+		packageJSONFiles, err := npmChangedWorkspaces(&config, utils)
+
+		assert.NoError(t, err)
+		assert.Nil(t, packageJSONFiles)
+	})
+
+	t.Run("changes not available", func(t *testing.T) {
+		config := npmExecuteScriptsOptions{RunChangedWorkspacesOnly: true}
+		utils := newNpmWorkspaceMockUtils()
+		utils.changedFilesError = errors.New("no pull request")
+
+		packageJSONFiles, err := npmChangedWorkspaces(&config, utils)
 
 		assert.NoError(t, err)
+		assert.Nil(t, packageJSONFiles)
+		assert.False(t, utils.HasWrittenFile("npmExecuteScripts_changeAnalysis.json"))
 	})
 }
@@ -0,0 +1,236 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	piperGithub "github.com/SAP/jenkins-library/pkg/github"
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/osv"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/reporting"
+	"github.com/SAP/jenkins-library/pkg/sbom"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/google/go-github/v45/github"
+	"github.com/pkg/errors"
+)
+
+const osvReportsDirectory = "osv"
+
+type osvExecuteScanUtils interface {
+	piperutils.FileUtils
+	piperhttp.Sender
+	GetIssueService() *github.IssuesService
+	GetSearchService() *github.SearchService
+}
+
+type osvExecuteScanUtilsBundle struct {
+	*piperhttp.Client
+	*piperutils.Files
+	issues *github.IssuesService
+	search *github.SearchService
+}
+
+func (o *osvExecuteScanUtilsBundle) GetIssueService() *github.IssuesService {
+	return o.issues
+}
+
+func (o *osvExecuteScanUtilsBundle) GetSearchService() *github.SearchService {
+	return o.search
+}
+
+func newOsvExecuteScanUtils(config *osvExecuteScanOptions, client *github.Client) osvExecuteScanUtils {
+	utils := osvExecuteScanUtilsBundle{
+		Client: &piperhttp.Client{},
+		Files:  &piperutils.Files{},
+	}
+	if client != nil {
+		utils.issues = client.Issues
+		utils.search = client.Search
+	}
+	utils.SetOptions(piperhttp.ClientOptions{TrustedCerts: config.CustomTLSCertificateLinks})
+	return &utils
+}
+
+func osvExecuteScan(config osvExecuteScanOptions, telemetryData *telemetry.CustomData, influx *osvExecuteScanInflux) {
+	ctx, client, err := piperGithub.NewClient(config.GithubToken, config.GithubAPIURL, "", config.CustomTLSCertificateLinks)
+	if err != nil {
+		log.Entry().WithError(err).Warning("Failed to get GitHub client")
+	}
+	utils := newOsvExecuteScanUtils(&config, client)
+
+	err = runOsvExecuteScan(ctx, &config, utils, influx)
+	if err != nil {
+		log.Entry().WithError(err).Fatal("step execution failed")
+	}
+}
+
+func runOsvExecuteScan(ctx context.Context, config *osvExecuteScanOptions, utils osvExecuteScanUtils, influx *osvExecuteScanInflux) error {
+	cvssSeverityLimit, err := strconv.ParseFloat(config.CvssSeverityLimit, 64)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return errors.Wrapf(err, "invalid cvssSeverityLimit '%v'", config.CvssSeverityLimit)
+	}
+
+	bomFiles, err := findOsvBomFiles(config, utils)
+	if err != nil {
+		return err
+	}
+	if len(bomFiles) == 0 {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return fmt.Errorf("no BOM files found matching the patterns %v", config.BomFilePatterns)
+	}
+
+	components, err := readOsvComponents(bomFiles, utils)
+	if err != nil {
+		return err
+	}
+	log.Entry().Infof("Checking %v components of %v BOM file(s)", len(components), len(bomFiles))
+
+	db, err := loadOsvDatabase(config, osv.Ecosystems(components), utils)
+	if err != nil {
+		return err
+	}
+	log.Entry().Infof("Loaded %v vulnerabilities from the OSV database", db.Count())
+
+	findings := db.Match(components)
+	assessments, err := readOsvAssessments(config.AssessmentFile, utils)
+	if err != nil {
+		return err
+	}
+	if err := osv.ApplyAssessments(findings, assessments); err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return err
+	}
+
+	assessed := 0
+	for _, finding := range findings {
+		if finding.IsAssessed() {
+			assessed++
+			log.Entry().Infof("Vulnerability %v of %v assessed as %v", finding.Vulnerability.ID, finding.Component.PackageURL, finding.Assessment.Status)
+			continue
+		}
+		log.Entry().Warningf("Vulnerability %v (%v %v) found in %v", finding.Vulnerability.ID, finding.Severity(), finding.Score, finding.Component.PackageURL)
+	}
+	severe := osv.CountSevereFindings(findings, cvssSeverityLimit)
+	influx.osv_data.fields.vulnerabilities = len(findings)
+	influx.osv_data.fields.severe_vulnerabilities = severe
+	influx.osv_data.fields.assessed_vulnerabilities = assessed
+
+	scanReport := osv.CreateScanReport(bomFiles, db.Count(), findings, cvssSeverityLimit)
+	reportPaths, err := writeOsvReports(scanReport, findings, utils)
+	if err != nil {
+		return err
+	}
+	piperutils.PersistReportsAndLinks("osvExecuteScan", "./", utils, reportPaths, nil)
+
+	if config.CreateResultIssue && len(config.GithubToken) > 0 && len(findings) > assessed {
+		log.Entry().Debugf("Creating result issues for %v vulnerabilities", len(findings)-assessed)
+		issueDetails := []reporting.IssueDetail{}
+		for _, finding := range findings {
+			if !finding.IsAssessed() {
+				issueDetails = append(issueDetails, finding)
+			}
+		}
+		gh := reporting.GitHub{
+			Owner:         &config.Owner,
+			Repository:    &config.Repository,
+			Assignees:     &config.Assignees,
+			IssueService:  utils.GetIssueService(),
+			SearchService: utils.GetSearchService(),
+		}
+		if err := gh.UploadMultipleReports(ctx, &issueDetails); err != nil {
+			return errors.Wrap(err, "failed to upload scan results into GitHub")
+		}
+	}
+
+	if severe > 0 {
+		log.SetErrorCategory(log.ErrorCompliance)
+		return fmt.Errorf("%v vulnerabilities with CVSS score greater or equal to %v found", severe, cvssSeverityLimit)
+	}
+	return nil
+}
+
+func findOsvBomFiles(config *osvExecuteScanOptions, utils osvExecuteScanUtils) ([]string, error) {
+	return piperutils.FindFiles(config.BomFilePatterns, config.Excludes, utils)
+}
+
+// readOsvComponents reads the components of all BOM files, components contained in several BOMs are only checked once
+func readOsvComponents(bomFiles []string, utils osvExecuteScanUtils) ([]osv.Component, error) {
+	components := []osv.Component{}
+	found := map[string]bool{}
+	for _, bomFile := range bomFiles {
+		log.Entry().Infof("Reading BOM file '%v'", bomFile)
+		bom, err := sbom.ReadBOM(bomFile, utils)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return nil, err
+		}
+		for _, component := range osv.ComponentsFromBOM(bom) {
+			if found[component.PackageURL] {
+				continue
+			}
+			found[component.PackageURL] = true
+			component.Location = bomFile
+			components = append(components, component)
+		}
+	}
+	return components, nil
+}
+
+func loadOsvDatabase(config *osvExecuteScanOptions, ecosystems []string, utils osvExecuteScanUtils) (*osv.Database, error) {
+	db := osv.NewDatabase()
+	if len(config.DatabasePath) > 0 {
+		log.Entry().Infof("Loading OSV data from '%v'", config.DatabasePath)
+		if err := db.LoadDirectory(config.DatabasePath, utils); err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return nil, err
+		}
+		return db, nil
+	}
+	if len(config.DatabaseURL) == 0 {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return nil, errors.New("neither databasePath nor databaseUrl configured")
+	}
+	if err := db.LoadMirror(config.DatabaseURL, ecosystems, utils); err != nil {
+		log.SetErrorCategory(log.ErrorInfrastructure)
+		return nil, err
+	}
+	return db, nil
+}
+
+func readOsvAssessments(assessmentFile string, utils osvExecuteScanUtils) ([]format.Assessment, error) {
+	exists, err := utils.FileExists(assessmentFile)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return nil, errors.Wrapf(err, "unable to check existence of assessment file at '%v'", assessmentFile)
+	}
+	if !exists {
+		return []format.Assessment{}, nil
+	}
+	file, err := utils.Open(assessmentFile)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return nil, errors.Wrapf(err, "unable to open assessment file at '%v'", assessmentFile)
+	}
+	assessments, err := format.ReadAssessments(file)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return nil, errors.Wrapf(err, "unable to parse assessment file at '%v'", assessmentFile)
+	}
+	return *assessments, nil
+}
+
+func writeOsvReports(scanReport reporting.ScanReport, findings []osv.Finding, utils osvExecuteScanUtils) ([]piperutils.Path, error) {
+	return reporting.WriteScanReports(scanReport, reporting.ScanReportFiles{
+		Directory: osvReportsDirectory,
+		Name:      "OSV Vulnerability",
+		HTMLFile:  "piper_osv_vulnerability_report.html",
+		SarifFile: "piper_osv_vulnerability.sarif",
+		Sarif:     osv.CreateSarif(findings),
+		JSONFile:  "osvExecuteScan_oss.json",
+	}, utils)
+}
@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/google/go-github/v45/github"
+	"github.com/stretchr/testify/assert"
+)
+
+type osvExecuteScanMockUtils struct {
+	*mock.FilesMock
+	requestedUrls []string
+}
+
+func (o *osvExecuteScanMockUtils) SetOptions(options piperhttp.ClientOptions) {}
+
+func (o *osvExecuteScanMockUtils) SendRequest(method string, url string, r io.Reader, header http.Header, cookies []*http.Cookie) (*http.Response, error) {
+	o.requestedUrls = append(o.requestedUrls, url)
+	return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(bytes.NewReader([]byte{}))}, fmt.Errorf("not found")
+}
+
+func (o *osvExecuteScanMockUtils) GetIssueService() *github.IssuesService {
+	return nil
+}
+
+func (o *osvExecuteScanMockUtils) GetSearchService() *github.SearchService {
+	return nil
+}
+
+const osvTestBom = `{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.4",
+  "version": 1,
+  "components": [
+    {"type": "library", "name": "lodash", "version": "4.17.11", "purl": "pkg:npm/lodash@4.17.11"},
+    {"type": "library", "name": "express", "version": "4.18.2", "purl": "pkg:npm/express@4.18.2"},
+    {"type": "library", "group": "org.apache.logging.log4j", "name": "log4j-core", "version": "2.14.1", "purl": "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1"}
+  ]
+}`
+
+const osvTestAssessments = `ignore:
+  - vulnerability: CVE-2019-10744
+    status: notRelevant
+    analysis: notUsed
+    purls:
+      - purl: pkg:npm/lodash@4.17.11
+`
+
+func newOsvExecuteScanTestsUtils(t *testing.T) *osvExecuteScanMockUtils {
+	utils := osvExecuteScanMockUtils{
+		FilesMock: &mock.FilesMock{},
+	}
+	utils.AddFile("bom-npm.json", []byte(osvTestBom))
+	for _, entry := range []string{"npm/GHSA-jf85-cpcp-j695.json", "maven/GHSA-jfh8-c2jp-5v3q.json"} {
+		content, err := os.ReadFile(filepath.Join("../pkg/osv/testdata", entry))
+		if err != nil {
+			t.Fatal(err)
+		}
+		utils.AddFile(filepath.Join("osv-mirror", entry), content)
+	}
+	return &utils
+}
+
+func defaultOsvExecuteScanOptions() osvExecuteScanOptions {
+	return osvExecuteScanOptions{
+		BomFilePatterns:   []string{"**/bom-*.json"},
+		DatabasePath:      "osv-mirror",
+		AssessmentFile:    "hs-assessments.yaml",
+		CvssSeverityLimit: "-1",
+	}
+}
+
+func TestRunOsvExecuteScan(t *testing.T) {
+	t.Parallel()
+
+	t.Run("vulnerabilities found", func(t *testing.T) {
+		t.Parallel()
+		config := defaultOsvExecuteScanOptions()
+		utils := newOsvExecuteScanTestsUtils(t)
+		influx := osvExecuteScanInflux{}
+
+		err := runOsvExecuteScan(context.Background(), &config, utils, &influx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, influx.osv_data.fields.vulnerabilities)
+		assert.Equal(t, 0, influx.osv_data.fields.severe_vulnerabilities)
+		assert.True(t, utils.HasWrittenFile("osv/piper_osv_vulnerability_report.html"))
+		assert.True(t, utils.HasWrittenFile("osv/piper_osv_vulnerability.sarif"))
+		sarif, err := utils.FileRead("osv/piper_osv_vulnerability.sarif")
+		assert.NoError(t, err)
+		assert.Contains(t, string(sarif), "GHSA-jfh8-c2jp-5v3q")
+	})
+
+	t.Run("severe vulnerabilities", func(t *testing.T) {
+		t.Parallel()
+		config := defaultOsvExecuteScanOptions()
+		config.CvssSeverityLimit = "7"
+		utils := newOsvExecuteScanTestsUtils(t)
+		influx := osvExecuteScanInflux{}
+
+		err := runOsvExecuteScan(context.Background(), &config, utils, &influx)
+
+		assert.EqualError(t, err, "2 vulnerabilities with CVSS score greater or equal to 7 found")
+		assert.Equal(t, 2, influx.osv_data.fields.severe_vulnerabilities)
+	})
+
+	t.Run("assessed vulnerabilities", func(t *testing.T) {
+		t.Parallel()
+		config := defaultOsvExecuteScanOptions()
+		config.CvssSeverityLimit = "7"
+		utils := newOsvExecuteScanTestsUtils(t)
+		utils.AddFile("hs-assessments.yaml", []byte(osvTestAssessments))
+		influx := osvExecuteScanInflux{}
+
+		err := runOsvExecuteScan(context.Background(), &config, utils, &influx)
+
+		assert.EqualError(t, err, "1 vulnerabilities with CVSS score greater or equal to 7 found")
+		assert.Equal(t, 1, influx.osv_data.fields.assessed_vulnerabilities)
+	})
+
+	t.Run("assessment file not accessible", func(t *testing.T) {
+		t.Parallel()
+		config := defaultOsvExecuteScanOptions()
+		utils := newOsvExecuteScanTestsUtils(t)
+		utils.FileExistsErrors = map[string]error{"hs-assessments.yaml": fmt.Errorf("permission denied")}
+
+		err := runOsvExecuteScan(context.Background(), &config, utils, &osvExecuteScanInflux{})
+
+		assert.EqualError(t, err, "unable to check existence of assessment file at 'hs-assessments.yaml': permission denied")
+	})
+
+	t.Run("database mirror", func(t *testing.T) {
+		t.Parallel()
+		config := defaultOsvExecuteScanOptions()
+		config.DatabasePath = ""
+		config.DatabaseURL = "https://osv.example.com"
+		utils := newOsvExecuteScanTestsUtils(t)
+		influx := osvExecuteScanInflux{}
+
+		err := runOsvExecuteScan(context.Background(), &config, utils, &influx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"https://osv.example.com/Maven/all.zip", "https://osv.example.com/npm/all.zip"}, utils.requestedUrls)
+		assert.Equal(t, 0, influx.osv_data.fields.vulnerabilities)
+	})
+
+	t.Run("no BOM files", func(t *testing.T) {
+		t.Parallel()
+		config := defaultOsvExecuteScanOptions()
+		config.BomFilePatterns = []string{"**/sbom.xml"}
+		utils := newOsvExecuteScanTestsUtils(t)
+
+		err := runOsvExecuteScan(context.Background(), &config, utils, &osvExecuteScanInflux{})
+
+		assert.EqualError(t, err, "no BOM files found matching the patterns [**/sbom.xml]")
+	})
+
+	t.Run("invalid severity limit", func(t *testing.T) {
+		t.Parallel()
+		config := defaultOsvExecuteScanOptions()
+		config.CvssSeverityLimit = "high"
+		utils := newOsvExecuteScanTestsUtils(t)
+
+		err := runOsvExecuteScan(context.Background(), &config, utils, &osvExecuteScanInflux{})
+
+		assert.Contains(t, err.Error(), "invalid cvssSeverityLimit 'high'")
+	})
+}
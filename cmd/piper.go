@@ -196,6 +196,13 @@ func Execute() {
 	rootCmd.AddCommand(TmsExportCommand())
 	rootCmd.AddCommand(IntegrationArtifactTransportCommand())
 	rootCmd.AddCommand(AscAppUploadCommand())
+	rootCmd.AddCommand(SbomProcessCommand())
+	rootCmd.AddCommand(OsvExecuteScanCommand())
+	rootCmd.AddCommand(LicenseComplianceCheckCommand())
+	rootCmd.AddCommand(SecretExecuteScanCommand())
+	rootCmd.AddCommand(IacExecuteScanCommand())
+	rootCmd.AddCommand(CheckmarxOneExecuteScanCommand())
+	rootCmd.AddCommand(FortifySyncAuditsCommand())
 
 	addRootFlags(rootCmd)
 
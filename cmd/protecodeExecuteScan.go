@@ -19,6 +19,8 @@ import (
 	"github.com/SAP/jenkins-library/pkg/log"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
 	"github.com/SAP/jenkins-library/pkg/protecode"
+	"github.com/SAP/jenkins-library/pkg/reporting"
+	"github.com/SAP/jenkins-library/pkg/scancache"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 	"github.com/SAP/jenkins-library/pkg/toolrecord"
 	"github.com/SAP/jenkins-library/pkg/versioning"
@@ -30,6 +32,9 @@ const (
 	stepResultFile = "protecodeExecuteScan.json"
 )
 
+// errProtecodeNotCompliant is returned if the scan found severe vulnerabilities
+var errProtecodeNotCompliant = errors.New("the product is not compliant")
+
 type protecodeUtils interface {
 	piperutils.FileUtils
 	piperDocker.Download
@@ -103,8 +108,47 @@ func runProtecodeScan(config *protecodeExecuteScanOptions, influx *protecodeExec
 		log.Entry().Debugf("[DEBUG] ===> Filename from fetch URL: %v", fileName)
 	}
 
+	// a scan via fetch URL has no local content which identifies the scan
+	var cache *scancache.Cache
+	cacheKey := ""
+	if len(config.FetchURL) == 0 {
+		var closeCache func()
+		cache, closeCache = newScanCache(config.ScanCache, config.ScanCacheLocation, config.ScanCacheMaxAge, utils)
+		defer closeCache()
+		if cache != nil {
+			if cacheKey, err = protecodeScanCacheKey(config, fileName, utils); err != nil {
+				log.Entry().WithError(err).Warn("scan cache is disabled")
+				cache = nil
+			}
+		}
+	}
+	if entry := cache.Lookup(cacheKey); entry != nil {
+		if restored, err := restoreScanResult(entry, utils); restored {
+			restoreProtecodeInfluxData(entry, influx)
+			if err := utils.RemoveAll(cachePath); err != nil {
+				log.Entry().Warnf("Error during cleanup folder %v", err)
+			}
+			return err
+		}
+	}
+
 	log.Entry().Debug("Execute protecode scan")
-	if err := executeProtecodeScan(influx, client, config, fileName, utils); err != nil {
+	err = executeProtecodeScan(influx, client, config, fileName, utils)
+	if err == nil || err == errProtecodeNotCompliant {
+		entry := scancache.NewEntry("protecodeExecuteScan", cacheKey)
+		if outputErr := entry.SetOutput("influx", getInfluxData(influx)); outputErr == nil {
+			saveScanResult(cache, entry, err, utils,
+				config.ReportFileName,
+				stepResultFile,
+				scanResultFile,
+				filepath.Join(protecode.ReportsDirectory, "*"),
+				filepath.Join(reporting.StepReportDirectory, "protecodeExecuteScan_*.json"),
+				"toolrun_protecode_*.json",
+				"protecodeExecuteScan_reports.json",
+				"protecodeExecuteScan_links.json")
+		}
+	}
+	if err != nil {
 		return err
 	}
 
@@ -128,10 +172,14 @@ func handleArtifactVersion(artifactVersion string) string {
 	return artifactVersion
 }
 
-func getDockerImage(utils protecodeUtils, config *protecodeExecuteScanOptions, cachePath string) (string, string, error) {
+// getDockerImageFileName returns the name of the archive the image is saved to, the name also identifies the Protecode product
+func getDockerImageFileName(image string) string {
 	m := regexp.MustCompile(`[\s@:/]`)
+	return fmt.Sprintf("%s.tar", m.ReplaceAllString(image, "-"))
+}
 
-	tarFileName := fmt.Sprintf("%s.tar", m.ReplaceAllString(config.ScanImage, "-"))
+func getDockerImage(utils protecodeUtils, config *protecodeExecuteScanOptions, cachePath string) (string, string, error) {
+	tarFileName := getDockerImageFileName(config.ScanImage)
 	tarFilePath, err := filepath.Abs(filepath.Join(cachePath, tarFileName))
 
 	if err != nil {
@@ -261,6 +309,17 @@ func executeProtecodeScan(influx *protecodeExecuteScanInflux, client protecode.P
 		reports = append(reports, paths...)
 	}
 
+	// write delta report
+	if config.PreviousProductID > 0 || len(config.PreviousScanImage) > 0 {
+		deltaPaths, err := writeProtecodeDeltaReport(utils, client, config, fileName, result.Result)
+		if err != nil {
+			// do not fail - the delta report is for information only
+			log.Entry().WithError(err).Warning("failed to create delta report")
+		} else {
+			reports = append(reports, deltaPaths...)
+		}
+	}
+
 	// create toolrecord file
 	toolRecordFileName, err := createToolRecordProtecode(utils, "./", config, productID, webuiURL)
 	if err != nil {
@@ -274,13 +333,76 @@ func executeProtecodeScan(influx *protecodeExecuteScanInflux, client protecode.P
 
 	if config.FailOnSevereVulnerabilities && protecode.HasSevereVulnerabilities(result.Result, config.ExcludeCVEs) {
 		log.SetErrorCategory(log.ErrorCompliance)
-		return fmt.Errorf("the product is not compliant")
+		return errProtecodeNotCompliant
 	} else if protecode.HasSevereVulnerabilities(result.Result, config.ExcludeCVEs) {
 		log.Entry().Infof("policy violation(s) found - step will only create data but not fail due to setting failOnSevereVulnerabilities: false")
 	}
 	return nil
 }
 
+// writeProtecodeDeltaReport compares the result with the result of the previous product and writes the differences as report
+func writeProtecodeDeltaReport(utils protecodeUtils, client protecode.Protecode, config *protecodeExecuteScanOptions, fileName string, result protecode.Result) ([]piperutils.Path, error) {
+	previousProductID := config.PreviousProductID
+	if previousProductID <= 0 {
+		previousProductID = client.LoadExistingProduct(config.Group, getDockerImageFileName(config.PreviousScanImage))
+		if previousProductID <= 0 {
+			return nil, fmt.Errorf("no product found for previous image '%v' in group '%v'", config.PreviousScanImage, config.Group)
+		}
+	}
+	if previousProductID == result.ProductID {
+		return nil, fmt.Errorf("previous product '%v' is the same as the scanned product", previousProductID)
+	}
+
+	previousResult, err := client.LoadResult(previousProductID)
+	if err != nil {
+		return nil, err
+	}
+
+	delta := protecode.CompareResults(result, previousResult, config.ExcludeCVEs)
+	log.Entry().Infof("Compared to product %v: %v new and %v fixed vulnerabilities, %v added and %v removed components",
+		previousProductID, delta.NewVulnerabilities, delta.FixedVulnerabilities, delta.AddedComponents, delta.RemovedComponents)
+
+	return protecode.WriteDeltaReports(delta, protecode.CreateDeltaReport(fileName, delta), utils)
+}
+
+// protecodeScanCacheKey identifies a scan by the content of the scanned file and the configuration which influences the result
+func protecodeScanCacheKey(config *protecodeExecuteScanOptions, fileName string, utils protecodeUtils) (string, error) {
+	contentHash, err := scancache.HashFiles(utils, []string{filepath.Join(config.FilePath, fileName)})
+	if err != nil {
+		return "", err
+	}
+	return scancache.Key("protecodeExecuteScan", contentHash, struct {
+		ServerURL                   string `json:"serverUrl"`
+		Group                       string `json:"group"`
+		ExcludeCVEs                 string `json:"excludeCVEs"`
+		FailOnSevereVulnerabilities bool   `json:"failOnSevereVulnerabilities"`
+		ReportFileName              string `json:"reportFileName"`
+		PreviousProductID           int    `json:"previousProductId"`
+		PreviousScanImage           string `json:"previousScanImage"`
+	}{config.ServerURL, config.Group, config.ExcludeCVEs, config.FailOnSevereVulnerabilities, config.ReportFileName, config.PreviousProductID, config.PreviousScanImage})
+}
+
+// restoreProtecodeInfluxData sets the influx data of a scan restored from the scan cache
+func restoreProtecodeInfluxData(entry *scancache.Entry, influx *protecodeExecuteScanInflux) {
+	result := map[string]int{}
+	if _, err := entry.Output("influx", &result); err != nil {
+		log.Entry().WithError(err).Warn("failed to restore influx data from scan cache")
+		return
+	}
+	setInfluxData(influx, result)
+}
+
+func getInfluxData(influx *protecodeExecuteScanInflux) map[string]int {
+	return map[string]int{
+		"historical_vulnerabilities": influx.protecode_data.fields.historical_vulnerabilities,
+		"triaged_vulnerabilities":    influx.protecode_data.fields.triaged_vulnerabilities,
+		"excluded_vulnerabilities":   influx.protecode_data.fields.excluded_vulnerabilities,
+		"minor_vulnerabilities":      influx.protecode_data.fields.minor_vulnerabilities,
+		"major_vulnerabilities":      influx.protecode_data.fields.major_vulnerabilities,
+		"vulnerabilities":            influx.protecode_data.fields.vulnerabilities,
+	}
+}
+
 func setInfluxData(influx *protecodeExecuteScanInflux, result map[string]int) {
 	influx.protecode_data.fields.historical_vulnerabilities = result["historical_vulnerabilities"]
 	influx.protecode_data.fields.triaged_vulnerabilities = result["triaged_vulnerabilities"]
@@ -36,6 +36,8 @@ type protecodeExecuteScanOptions struct {
 	Group                       string `json:"group,omitempty"`
 	VerifyOnly                  bool   `json:"verifyOnly,omitempty"`
 	ReplaceProductID            int    `json:"replaceProductId,omitempty"`
+	PreviousProductID           int    `json:"previousProductId,omitempty"`
+	PreviousScanImage           string `json:"previousScanImage,omitempty"`
 	Username                    string `json:"username,omitempty"`
 	Password                    string `json:"password,omitempty"`
 	UserAPIKey                  string `json:"userAPIKey,omitempty"`
@@ -44,6 +46,9 @@ type protecodeExecuteScanOptions struct {
 	VersioningModel             string `json:"versioningModel,omitempty" validate:"possible-values=major major-minor semantic full"`
 	PullRequestName             string `json:"pullRequestName,omitempty"`
 	CustomDataJSONMap           string `json:"customDataJSONMap,omitempty"`
+	ScanCache                   bool   `json:"scanCache,omitempty"`
+	ScanCacheLocation           string `json:"scanCacheLocation,omitempty"`
+	ScanCacheMaxAge             int    `json:"scanCacheMaxAge,omitempty"`
 }
 
 type protecodeExecuteScanInflux struct {
@@ -111,6 +116,7 @@ func (p *protecodeExecuteScanReports) persist(stepConfig protecodeExecuteScanOpt
 		{FilePattern: "", ParamRef: "reportFileName", StepResultType: "protecode"},
 		{FilePattern: "**/protecodeExecuteScan.json", ParamRef: "", StepResultType: "protecode"},
 		{FilePattern: "**/protecodescan_vulns.json", ParamRef: "", StepResultType: "protecode"},
+		{FilePattern: "**/piper_protecode_delta_report.json", ParamRef: "", StepResultType: "protecode"},
 	}
 	envVars := []gcs.EnvVar{
 		{Name: "GOOGLE_APPLICATION_CREDENTIALS", Value: gcpJsonKeyFilePath, Modified: false},
@@ -255,6 +261,8 @@ func addProtecodeExecuteScanFlags(cmd *cobra.Command, stepConfig *protecodeExecu
 	cmd.Flags().StringVar(&stepConfig.Group, "group", os.Getenv("PIPER_group"), "The Protecode group ID of your team")
 	cmd.Flags().BoolVar(&stepConfig.VerifyOnly, "verifyOnly", false, "Whether the step shall only apply verification checks or whether it does a full scan and check cycle")
 	cmd.Flags().IntVar(&stepConfig.ReplaceProductID, "replaceProductId", 0, "Specify <replaceProductId> which application binary will be replaced and rescanned and product id remains unchanged. By using this parameter, Protecode avoids creating multiple same products. Note this will affect results and feeds. If product id is not specified, then Piper starts auto detection mechanism, more precisely it searches a product id with scanned product name in that specified group, if there are several scans have been done with the same product name then the latest scan id will be fetched from BDBA backend. After obtaining product id, Piper re-uploads / replaces new binary without affecting already existing product id.")
+	cmd.Flags().IntVar(&stepConfig.PreviousProductID, "previousProductId", 0, "ID of the Protecode product containing the result of the previous version, e.g. of the previous image tag. If set, the step creates a delta report listing the newly introduced and fixed vulnerabilities and components per image layer.")
+	cmd.Flags().StringVar(&stepConfig.PreviousScanImage, "previousScanImage", os.Getenv("PIPER_previousScanImage"), "Docker image (name and tag) of the previous version. If `previousProductId` is not set, the latest product of this image in the Protecode group is used to create the delta report.")
 	cmd.Flags().StringVar(&stepConfig.Username, "username", os.Getenv("PIPER_username"), "User which is used for the protecode scan")
 	cmd.Flags().StringVar(&stepConfig.Password, "password", os.Getenv("PIPER_password"), "Password which is used for the user")
 	cmd.Flags().StringVar(&stepConfig.UserAPIKey, "userAPIKey", os.Getenv("PIPER_userAPIKey"), "User API key which is used for API calls. Replacement for username and password / basic authentication.")
@@ -263,6 +271,9 @@ func addProtecodeExecuteScanFlags(cmd *cobra.Command, stepConfig *protecodeExecu
 	cmd.Flags().StringVar(&stepConfig.VersioningModel, "versioningModel", `major`, "The versioning model used for result reporting (based on the artifact version). Example 1.2.3 using `major` will result in version 1")
 	cmd.Flags().StringVar(&stepConfig.PullRequestName, "pullRequestName", os.Getenv("PIPER_pullRequestName"), "The name of the pull request")
 	cmd.Flags().StringVar(&stepConfig.CustomDataJSONMap, "customDataJSONMap", os.Getenv("PIPER_customDataJSONMap"), "The JSON map of key-value pairs to be included in this scan's Custom Data (See protecode API).")
+	cmd.Flags().BoolVar(&stepConfig.ScanCache, "scanCache", false, "Reuse the scan result of a previous run if the scanned content and the configuration are unchanged.")
+	cmd.Flags().StringVar(&stepConfig.ScanCacheLocation, "scanCacheLocation", os.Getenv("PIPER_scanCacheLocation"), "Location of the scan cache: a local directory, `gs://<bucket>/<folder>` or `s3://<bucket>/<prefix>`.")
+	cmd.Flags().IntVar(&stepConfig.ScanCacheMaxAge, "scanCacheMaxAge", 72, "Time to live of a cached scan result in hours.")
 
 	cmd.MarkFlagRequired("serverUrl")
 	cmd.MarkFlagRequired("group")
@@ -438,6 +449,24 @@ func protecodeExecuteScanMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     0,
 					},
+					{
+						Name:        "previousProductId",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     0,
+					},
+					{
+						Name:        "previousScanImage",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_previousScanImage"),
+					},
 					{
 						Name: "username",
 						ResourceRef: []config.ResourceReference{
@@ -551,6 +580,38 @@ func protecodeExecuteScanMetadata() config.StepData {
 						Aliases:     []config.Alias{},
 						Default:     os.Getenv("PIPER_customDataJSONMap"),
 					},
+					{
+						Name: "scanCache",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "custom/isOptimizedAndScheduled",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "bool",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   false,
+					},
+					{
+						Name:        "scanCacheLocation",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_scanCacheLocation"),
+					},
+					{
+						Name:        "scanCacheMaxAge",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     72,
+					},
 				},
 			},
 			Outputs: config.StepOutputs{
@@ -571,6 +632,7 @@ func protecodeExecuteScanMetadata() config.StepData {
 							{"type": "protecode"},
 							{"filePattern": "**/protecodeExecuteScan.json", "type": "protecode"},
 							{"filePattern": "**/protecodescan_vulns.json", "type": "protecode"},
+							{"filePattern": "**/piper_protecode_delta_report.json", "type": "protecode"},
 						},
 					},
 				},
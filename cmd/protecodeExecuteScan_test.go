@@ -16,6 +16,7 @@ import (
 	piperHttp "github.com/SAP/jenkins-library/pkg/http"
 	"github.com/SAP/jenkins-library/pkg/mock"
 	"github.com/SAP/jenkins-library/pkg/protecode"
+	"github.com/SAP/jenkins-library/pkg/scancache"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -344,6 +345,68 @@ func TestExecuteProtecodeScan(t *testing.T) {
 	}
 }
 
+func TestWriteProtecodeDeltaReport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		var response interface{}
+		switch req.URL.Path {
+		case "/api/apps/group1/":
+			response = protecode.ProductData{Products: []protecode.Product{{ProductID: 4710, FileName: "my-image-1.0.tar"}}}
+		case "/api/product/4710/":
+			response = protecode.ResultData{Result: protecode.Result{ProductID: 4710, Components: []protecode.Component{
+				{Lib: "openssl", Version: "1.1.1k", Vulns: []protecode.Vulnerability{{Exact: true, Vuln: protecode.Vuln{Cve: "CVE-2021-3711", Cvss3Score: "9.8"}}}},
+			}}}
+		default:
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(rw).Encode(response)
+	}))
+	defer server.Close()
+
+	pc := protecode.Protecode{}
+	pc.SetOptions(protecode.Options{ServerURL: server.URL, Duration: time.Minute})
+	result := protecode.Result{ProductID: 4711, Components: []protecode.Component{
+		{Lib: "openssl", Version: "1.1.1n", Vulns: []protecode.Vulnerability{{Exact: true, Vuln: protecode.Vuln{Cve: "CVE-2022-0778", Cvss3Score: "7.5"}}}},
+	}}
+
+	t.Run("previous product id", func(t *testing.T) {
+		utils := protecodeTestUtilsBundle{FilesMock: &mock.FilesMock{}}
+		config := protecodeExecuteScanOptions{Group: "group1", PreviousProductID: 4710}
+
+		paths, err := writeProtecodeDeltaReport(utils, pc, &config, "my-image-1.1.tar", result)
+
+		assert.NoError(t, err)
+		assert.Len(t, paths, 2)
+		content, err := utils.FileRead("protecode/piper_protecode_delta_report.json")
+		if assert.NoError(t, err) {
+			delta := protecode.DeltaReport{}
+			require.NoError(t, json.Unmarshal(content, &delta))
+			assert.Equal(t, 1, delta.NewVulnerabilities)
+			assert.Equal(t, 1, delta.FixedVulnerabilities)
+			assert.Equal(t, 4710, delta.PreviousProductID)
+		}
+	})
+
+	t.Run("previous image", func(t *testing.T) {
+		utils := protecodeTestUtilsBundle{FilesMock: &mock.FilesMock{}}
+		config := protecodeExecuteScanOptions{Group: "group1", PreviousScanImage: "my/image:1.0"}
+
+		_, err := writeProtecodeDeltaReport(utils, pc, &config, "my-image-1.1.tar", result)
+
+		assert.NoError(t, err)
+		assert.True(t, utils.HasWrittenFile("protecode/piper_protecode_delta_report.html"))
+	})
+
+	t.Run("previous image not found", func(t *testing.T) {
+		utils := protecodeTestUtilsBundle{FilesMock: &mock.FilesMock{}}
+		config := protecodeExecuteScanOptions{Group: "group1", PreviousScanImage: "my/image:0.9"}
+
+		_, err := writeProtecodeDeltaReport(utils, pc, &config, "my-image-1.1.tar", result)
+
+		assert.EqualError(t, err, "no product found for previous image 'my/image:0.9' in group 'group1'")
+	})
+}
+
 func TestCorrectDockerConfigEnvVar(t *testing.T) {
 	t.Run("with credentials", func(t *testing.T) {
 		// init
@@ -378,3 +441,31 @@ func TestCorrectDockerConfigEnvVar(t *testing.T) {
 		assert.Equal(t, resetValue, os.Getenv("DOCKER_CONFIG"))
 	})
 }
+
+func TestProtecodeScanCache(t *testing.T) {
+	t.Run("cache key", func(t *testing.T) {
+		utils := protecodeTestUtilsBundle{FilesMock: &mock.FilesMock{}}
+		utils.AddFile("cache/image.tar", []byte("image"))
+		config := protecodeExecuteScanOptions{FilePath: "cache", ServerURL: "https://protecode.example.org", Group: "123"}
+
+		key, err := protecodeScanCacheKey(&config, "image.tar", utils)
+		assert.NoError(t, err)
+
+		config.ExcludeCVEs = "CVE-2022-1234"
+		otherKey, _ := protecodeScanCacheKey(&config, "image.tar", utils)
+		assert.NotEqual(t, key, otherKey)
+	})
+
+	t.Run("influx data", func(t *testing.T) {
+		influx := protecodeExecuteScanInflux{}
+		setInfluxData(&influx, map[string]int{"major_vulnerabilities": 2, "vulnerabilities": 5})
+		entry := scancache.NewEntry("protecodeExecuteScan", "abc")
+		require.NoError(t, entry.SetOutput("influx", getInfluxData(&influx)))
+
+		restoredInflux := protecodeExecuteScanInflux{}
+		restoreProtecodeInfluxData(entry, &restoredInflux)
+
+		assert.Equal(t, 2, restoredInflux.protecode_data.fields.major_vulnerabilities)
+		assert.Equal(t, 5, restoredInflux.protecode_data.fields.vulnerabilities)
+	})
+}
@@ -107,16 +107,45 @@ func buildExecute(config *pythonBuildOptions, utils pythonBuildUtils, pipInstall
 
 	var flags []string
 	flags = append(flags, config.BuildFlags...)
-	flags = append(flags, "setup.py", "sdist", "bdist_wheel")
+
+	pep517, err := isPEP517Project(utils)
+	if err != nil {
+		return err
+	}
+	if pep517 {
+		// pyproject.toml based projects (setuptools, Poetry, Hatch, PDM, ...) are built via their build backend
+		if err := utils.RunExecutable(virutalEnvironmentPathMap["pip"], append(pipInstallFlags, "build")...); err != nil {
+			return err
+		}
+		flags = append(flags, "-m", "build")
+	} else {
+		flags = append(flags, "setup.py", "sdist", "bdist_wheel")
+	}
 
 	log.Entry().Info("starting building python project:")
-	err := utils.RunExecutable(virutalEnvironmentPathMap["python"], flags...)
+	err = utils.RunExecutable(virutalEnvironmentPathMap["python"], flags...)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
+// isPEP517Project returns true for projects described by a pyproject.toml only, i.e. without setup.py
+func isPEP517Project(utils pythonBuildUtils) (bool, error) {
+	setupPy, err := utils.FileExists("setup.py")
+	if err != nil {
+		return false, fmt.Errorf("failed to check for setup.py: %w", err)
+	}
+	if setupPy {
+		return false, nil
+	}
+	pyproject, err := utils.FileExists("pyproject.toml")
+	if err != nil {
+		return false, fmt.Errorf("failed to check for pyproject.toml: %w", err)
+	}
+	return pyproject, nil
+}
+
 func createVirtualEnvironment(utils pythonBuildUtils, config *pythonBuildOptions, virutalEnvironmentPathMap map[string]string) error {
 	virtualEnvironmentFlags := []string{"-m", "venv", config.VirutalEnvironmentName}
 	err := utils.RunExecutable("python3", virtualEnvironmentFlags...)
@@ -150,7 +179,17 @@ func runBOMCreationForPy(utils pythonBuildUtils, pipInstallFlags []string, virut
 	}
 	virutalEnvironmentPathMap["cyclonedx"] = filepath.Join(config.VirutalEnvironmentName, "bin", "cyclonedx-bom")
 
-	if err := utils.RunExecutable(virutalEnvironmentPathMap["cyclonedx"], "--e", "--output", PyBomFilename); err != nil {
+	// Poetry projects define their complete dependency tree in the lock file, while the virtual environment does not contain the dependencies
+	poetryLock, err := utils.FileExists("poetry.lock")
+	if err != nil {
+		return fmt.Errorf("failed to check for poetry.lock: %w", err)
+	}
+	bomFlags := []string{"--e", "--output", PyBomFilename}
+	if poetryLock {
+		bomFlags = []string{"--poetry", "-i", "poetry.lock", "--output", PyBomFilename}
+	}
+
+	if err := utils.RunExecutable(virutalEnvironmentPathMap["cyclonedx"], bomFlags...); err != nil {
 		return err
 	}
 	return nil
@@ -71,7 +71,11 @@ func PythonBuildCommand() *cobra.Command {
 	var createPythonBuildCmd = &cobra.Command{
 		Use:   STEP_NAME,
 		Short: "Step build a python project",
-		Long:  `Step build python project with using test Vault credentials`,
+		Long: `Step build python project with using test Vault credentials
+
+Projects containing a ` + "`" + `setup.py` + "`" + ` are built via ` + "`" + `python setup.py sdist bdist_wheel` + "`" + `.
+Projects described by a ` + "`" + `pyproject.toml` + "`" + ` only ([PEP 621](https://peps.python.org/pep-0621/)), e.g. using setuptools, Poetry, Hatch or PDM,
+are built via their [PEP 517](https://peps.python.org/pep-0517/) build backend using ` + "`" + `python -m build` + "`" + `.`,
 		PreRunE: func(cmd *cobra.Command, _ []string) error {
 			startTime = time.Now()
 			log.SetStepName(STEP_NAME)
@@ -101,4 +101,38 @@ func TestRunPythonBuild(t *testing.T) {
 		assert.Equal(t, filepath.Join("dummy", "bin", "cyclonedx-bom"), utils.ExecMockRunner.Calls[4].Exec)
 		assert.Equal(t, []string{"--e", "--output", "bom-pip.xml"}, utils.ExecMockRunner.Calls[4].Params)
 	})
+	t.Run("success - pyproject.toml", func(t *testing.T) {
+		config := pythonBuildOptions{
+			VirutalEnvironmentName: "dummy",
+		}
+		utils := newPythonBuildTestsUtils()
+		utils.AddDir("dummy")
+		utils.AddFile("pyproject.toml", []byte("[project]\nname = \"simple-python\"\nversion = \"1.2.3\"\n"))
+		telemetryData := telemetry.CustomData{}
+
+		err := runPythonBuild(&config, &telemetryData, utils, &cpe)
+		assert.NoError(t, err)
+		assert.Equal(t, filepath.Join("dummy", "bin", "pip"), utils.ExecMockRunner.Calls[2].Exec)
+		assert.Equal(t, []string{"install", "--upgrade", "build"}, utils.ExecMockRunner.Calls[2].Params)
+		assert.Equal(t, "python", utils.ExecMockRunner.Calls[3].Exec)
+		assert.Equal(t, []string{"-m", "build"}, utils.ExecMockRunner.Calls[3].Params)
+	})
+
+	t.Run("success - create BOM from poetry.lock", func(t *testing.T) {
+		config := pythonBuildOptions{
+			CreateBOM:              true,
+			VirutalEnvironmentName: "dummy",
+		}
+		utils := newPythonBuildTestsUtils()
+		utils.AddDir("dummy")
+		utils.AddFile("pyproject.toml", []byte("[tool.poetry]\nname = \"poetry-python\"\nversion = \"0.4.0\"\n"))
+		utils.AddFile("poetry.lock", []byte("[[package]]\n"))
+		telemetryData := telemetry.CustomData{}
+
+		err := runPythonBuild(&config, &telemetryData, utils, &cpe)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"-m", "build"}, utils.ExecMockRunner.Calls[3].Params)
+		assert.Equal(t, filepath.Join("dummy", "bin", "cyclonedx-bom"), utils.ExecMockRunner.Calls[5].Exec)
+		assert.Equal(t, []string{"--poetry", "-i", "poetry.lock", "--output", "bom-pip.xml"}, utils.ExecMockRunner.Calls[5].Params)
+	})
 }
@@ -0,0 +1,282 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/reporting"
+	"github.com/SAP/jenkins-library/pkg/sbom"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/package-url/packageurl-go"
+	"github.com/pkg/errors"
+)
+
+const (
+	sbomDiffFile         = "sbom-diff.json"
+	sbomReportsDirectory = "sbom"
+)
+
+type sbomProcessUtils interface {
+	piperutils.FileUtils
+}
+
+type sbomProcessUtilsBundle struct {
+	*piperutils.Files
+}
+
+func newSbomProcessUtils() sbomProcessUtils {
+	utils := sbomProcessUtilsBundle{
+		Files: &piperutils.Files{},
+	}
+	return &utils
+}
+
+func sbomProcess(config sbomProcessOptions, telemetryData *telemetry.CustomData) {
+	utils := newSbomProcessUtils()
+
+	err := runSbomProcess(&config, utils)
+	if err != nil {
+		log.Entry().WithError(err).Fatal("step execution failed")
+	}
+}
+
+func runSbomProcess(config *sbomProcessOptions, utils sbomProcessUtils) error {
+	bomFiles, err := findBomFiles(config, utils)
+	if err != nil {
+		return err
+	}
+	if len(bomFiles) == 0 {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return fmt.Errorf("no BOM files found matching the patterns %v", config.BomFilePatterns)
+	}
+
+	schemaValidator := sbom.NewSchemaValidator(config.SchemaLocation)
+	issues := []sbom.ValidationIssue{}
+	boms := []*cdx.BOM{}
+	for _, bomFile := range bomFiles {
+		log.Entry().Infof("Reading BOM file '%v'", bomFile)
+		schemaIssues, err := schemaValidator.ValidateFile(bomFile, utils)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return err
+		}
+		issues = append(issues, schemaIssues...)
+		bom, err := sbom.ReadBOM(bomFile, utils)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return err
+		}
+		boms = append(boms, bom)
+	}
+
+	product := cdx.Component{
+		Type:    cdx.ComponentTypeApplication,
+		Group:   config.ProductGroup,
+		Name:    config.ProductName,
+		Version: config.ArtifactVersion,
+	}
+	product.PackageURL = packageurl.NewPackageURL(packageurl.TypeGeneric, config.ProductGroup, config.ProductName, config.ArtifactVersion, nil, "").ToString()
+	productBom := sbom.Merge(product, boms)
+	productBom.Metadata.Timestamp = time.Now().UTC().Format(time.RFC3339)
+	productBom.Metadata.Tools = &[]cdx.Tool{{Vendor: "SAP", Name: "piper"}}
+
+	if err := writeProductBom(config, productBom, utils); err != nil {
+		return err
+	}
+
+	issues = append(issues, sbom.Validate(productBom, config.RequiredElements)...)
+	for _, issue := range issues {
+		log.Entry().Warningf("BOM validation: %v", issue)
+	}
+
+	var diff *sbom.DiffResult
+	if len(config.PreviousBomFile) > 0 {
+		if exists, _ := utils.FileExists(config.PreviousBomFile); exists {
+			previousBom, err := sbom.ReadBOM(config.PreviousBomFile, utils)
+			if err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+			result := sbom.Diff(previousBom, productBom)
+			diff = &result
+		} else {
+			log.Entry().Infof("Previous BOM file '%v' not found, skipping comparison", config.PreviousBomFile)
+		}
+	}
+
+	reportPaths, err := writeSbomReports(config, len(bomFiles), productBom, issues, diff, utils)
+	if err != nil {
+		return err
+	}
+	piperutils.PersistReportsAndLinks("sbomProcess", "./", utils, reportPaths, nil)
+
+	if len(issues) > 0 && config.FailOnValidationErrors {
+		log.SetErrorCategory(log.ErrorCompliance)
+		return fmt.Errorf("product BOM validation failed with %v issue(s)", len(issues))
+	}
+	return nil
+}
+
+func findBomFiles(config *sbomProcessOptions, utils sbomProcessUtils) ([]string, error) {
+	found, err := piperutils.FindFiles(config.BomFilePatterns, config.Excludes, utils)
+	if err != nil {
+		return nil, err
+	}
+
+	outputFiles := map[string]bool{filepath.Clean(config.OutputFile): true}
+	for _, format := range config.AdditionalOutputFormats {
+		outputFiles[filepath.Clean(sbomOutputFile(config.OutputFile, sbom.Format(format)))] = true
+	}
+	if len(config.PreviousBomFile) > 0 {
+		outputFiles[filepath.Clean(config.PreviousBomFile)] = true
+	}
+
+	files := []string{}
+	for _, file := range found {
+		if !outputFiles[filepath.Clean(file)] {
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+// sbomOutputFile derives the name of the product BOM file for a dedicated format from the configured output file
+func sbomOutputFile(outputFile string, format sbom.Format) string {
+	base := strings.TrimSuffix(outputFile, filepath.Ext(outputFile))
+	switch format {
+	case sbom.FormatCycloneDXXML:
+		return base + ".xml"
+	case sbom.FormatCycloneDXJSON:
+		return base + ".json"
+	}
+	return base + ".spdx.json"
+}
+
+func writeProductBom(config *sbomProcessOptions, productBom *cdx.BOM, utils sbomProcessUtils) error {
+	files := map[string]sbom.Format{config.OutputFile: sbom.Format(config.OutputFormat)}
+	for _, format := range config.AdditionalOutputFormats {
+		file := sbomOutputFile(config.OutputFile, sbom.Format(format))
+		if _, ok := files[file]; !ok {
+			files[file] = sbom.Format(format)
+		}
+	}
+	for file, format := range files {
+		content, err := sbom.Encode(productBom, format)
+		if err != nil {
+			return errors.Wrapf(err, "failed to encode product BOM as %v", format)
+		}
+		if dir := filepath.Dir(file); dir != "." {
+			if err := utils.MkdirAll(dir, 0o777); err != nil {
+				return errors.Wrapf(err, "failed to create directory '%v'", dir)
+			}
+		}
+		if err := utils.FileWrite(file, content, 0o666); err != nil {
+			return errors.Wrapf(err, "failed to write product BOM '%v'", file)
+		}
+		log.Entry().Infof("Product BOM written to '%v' (%v)", file, format)
+	}
+	return nil
+}
+
+func writeSbomReports(config *sbomProcessOptions, bomCount int, productBom *cdx.BOM, issues []sbom.ValidationIssue, diff *sbom.DiffResult, utils sbomProcessUtils) ([]piperutils.Path, error) {
+	reportPaths := []piperutils.Path{{Name: "Product BOM", Target: config.OutputFile}}
+	scanReportPaths, err := reporting.WriteScanReports(createSbomReport(config, bomCount, productBom, issues, diff), reporting.ScanReportFiles{
+		Directory: sbomReportsDirectory,
+		Name:      "SBOM",
+		HTMLFile:  "piper_sbom_report.html",
+		JSONFile:  "sbomProcess_sbom.json",
+	}, utils)
+	reportPaths = append(reportPaths, scanReportPaths...)
+	if err != nil {
+		return reportPaths, err
+	}
+
+	if diff != nil {
+		diffContent, err := json.MarshalIndent(diff, "", "  ")
+		if err != nil {
+			return reportPaths, errors.Wrap(err, "failed to marshal BOM comparison")
+		}
+		if err := utils.FileWrite(sbomDiffFile, diffContent, 0o666); err != nil {
+			return reportPaths, errors.Wrap(err, "failed to write BOM comparison")
+		}
+		reportPaths = append(reportPaths, piperutils.Path{Name: "SBOM Comparison", Target: sbomDiffFile})
+	}
+	return reportPaths, nil
+}
+
+func createSbomReport(config *sbomProcessOptions, bomCount int, productBom *cdx.BOM, issues []sbom.ValidationIssue, diff *sbom.DiffResult) reporting.ScanReport {
+	scanReport := reporting.ScanReport{
+		ReportTitle: "Software Bill of Materials Report",
+		Subheaders: []reporting.Subheader{
+			{Description: "Product", Details: strings.TrimSpace(fmt.Sprintf("%v %v", config.ProductName, config.ArtifactVersion))},
+			{Description: "Product BOM", Details: config.OutputFile},
+		},
+		Overview: []reporting.OverviewRow{
+			{Description: "Number of merged BOMs", Details: fmt.Sprint(bomCount)},
+			{Description: "Number of components", Details: fmt.Sprint(len(sbom.Components(productBom)))},
+			{Description: "Number of validation issues", Details: fmt.Sprint(len(issues))},
+		},
+		SuccessfulScan: len(issues) == 0,
+		ReportTime:     time.Now(),
+	}
+
+	detailTable := reporting.ScanDetailTable{
+		NoRowsMessage: "No validation issues or changes detected",
+		Headers:       []string{"Type", "Component", "Details"},
+		WithCounter:   true,
+		CounterHeader: "Entry #",
+	}
+	for _, issue := range issues {
+		row := reporting.ScanRow{}
+		row.AddColumn(fmt.Sprintf("validation (%v)", issue.Rule), reporting.Red)
+		row.AddColumn(issue.Component, 0)
+		if len(issue.File) > 0 {
+			row.AddColumn(fmt.Sprintf("%v (%v)", issue.Message, issue.File), 0)
+		} else {
+			row.AddColumn(issue.Message, 0)
+		}
+		detailTable.Rows = append(detailTable.Rows, row)
+	}
+
+	if diff != nil {
+		scanReport.Subheaders = append(scanReport.Subheaders, reporting.Subheader{Description: "Compared with", Details: config.PreviousBomFile})
+		scanReport.Overview = append(scanReport.Overview,
+			reporting.OverviewRow{Description: "Added components", Details: fmt.Sprint(len(diff.Added))},
+			reporting.OverviewRow{Description: "Removed components", Details: fmt.Sprint(len(diff.Removed))},
+			reporting.OverviewRow{Description: "Changed components", Details: fmt.Sprint(len(diff.Changed))},
+		)
+		for _, component := range diff.Added {
+			row := reporting.ScanRow{}
+			row.AddColumn("added", reporting.Yellow)
+			row.AddColumn(sbom.ComponentKey(component), 0)
+			row.AddColumn(fmt.Sprintf("version %v", component.Version), 0)
+			detailTable.Rows = append(detailTable.Rows, row)
+		}
+		for _, component := range diff.Removed {
+			row := reporting.ScanRow{}
+			row.AddColumn("removed", reporting.Grey)
+			row.AddColumn(sbom.ComponentKey(component), 0)
+			row.AddColumn(fmt.Sprintf("version %v", component.Version), 0)
+			detailTable.Rows = append(detailTable.Rows, row)
+		}
+		for _, change := range diff.Changed {
+			row := reporting.ScanRow{}
+			row.AddColumn("changed", reporting.Yellow)
+			row.AddColumn(change.Key, 0)
+			details := fmt.Sprintf("version %v -> %v", change.PreviousVersion, change.Version)
+			if strings.Join(change.PreviousLicenses, ",") != strings.Join(change.Licenses, ",") {
+				details += fmt.Sprintf(", licenses %v -> %v", strings.Join(change.PreviousLicenses, ", "), strings.Join(change.Licenses, ", "))
+			}
+			row.AddColumn(details, 0)
+			detailTable.Rows = append(detailTable.Rows, row)
+		}
+	}
+	scanReport.DetailTable = detailTable
+	return scanReport
+}
@@ -0,0 +1,337 @@
+// Code generated by piper's step-generator. DO NOT EDIT.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/gcs"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/splunk"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/SAP/jenkins-library/pkg/validation"
+	"github.com/bmatcuk/doublestar"
+	"github.com/spf13/cobra"
+)
+
+type sbomProcessOptions struct {
+	BomFilePatterns         []string `json:"bomFilePatterns,omitempty"`
+	Excludes                []string `json:"excludes,omitempty"`
+	ProductName             string   `json:"productName,omitempty"`
+	ProductGroup            string   `json:"productGroup,omitempty"`
+	ArtifactVersion         string   `json:"artifactVersion,omitempty"`
+	OutputFile              string   `json:"outputFile,omitempty"`
+	OutputFormat            string   `json:"outputFormat,omitempty" validate:"possible-values=cyclonedx-xml cyclonedx-json spdx-json"`
+	AdditionalOutputFormats []string `json:"additionalOutputFormats,omitempty" validate:"possible-values=cyclonedx-xml cyclonedx-json spdx-json"`
+	RequiredElements        []string `json:"requiredElements,omitempty" validate:"possible-values=purl licenses hashes version supplier"`
+	FailOnValidationErrors  bool     `json:"failOnValidationErrors,omitempty"`
+	PreviousBomFile         string   `json:"previousBomFile,omitempty"`
+	SchemaLocation          string   `json:"schemaLocation,omitempty"`
+}
+
+type sbomProcessReports struct {
+}
+
+func (p *sbomProcessReports) persist(stepConfig sbomProcessOptions, gcpJsonKeyFilePath string, gcsBucketId string, gcsFolderPath string, gcsSubFolder string) {
+	if gcsBucketId == "" {
+		log.Entry().Info("persisting reports to GCS is disabled, because gcsBucketId is empty")
+		return
+	}
+	log.Entry().Info("Uploading reports to Google Cloud Storage...")
+	content := []gcs.ReportOutputParam{
+		{FilePattern: "**/product-bom*", ParamRef: "", StepResultType: "sbom"},
+		{FilePattern: "**/sbom-diff.json", ParamRef: "", StepResultType: "sbom"},
+		{FilePattern: "**/piper_sbom_report.html", ParamRef: "", StepResultType: "sbom"},
+	}
+	envVars := []gcs.EnvVar{
+		{Name: "GOOGLE_APPLICATION_CREDENTIALS", Value: gcpJsonKeyFilePath, Modified: false},
+	}
+	gcsClient, err := gcs.NewClient(gcs.WithEnvVars(envVars))
+	if err != nil {
+		log.Entry().Errorf("creation of GCS client failed: %v", err)
+		return
+	}
+	defer gcsClient.Close()
+	structVal := reflect.ValueOf(&stepConfig).Elem()
+	inputParameters := map[string]string{}
+	for i := 0; i < structVal.NumField(); i++ {
+		field := structVal.Type().Field(i)
+		if field.Type.String() == "string" {
+			paramName := strings.Split(field.Tag.Get("json"), ",")
+			paramValue, _ := structVal.Field(i).Interface().(string)
+			inputParameters[paramName[0]] = paramValue
+		}
+	}
+	if err := gcs.PersistReportsToGCS(gcsClient, content, inputParameters, gcsFolderPath, gcsBucketId, gcsSubFolder, doublestar.Glob, os.Stat); err != nil {
+		log.Entry().Errorf("failed to persist reports: %v", err)
+	}
+}
+
+// SbomProcessCommand Merges, validates, converts and compares the software bills of materials (SBOM) created by the build steps.
+func SbomProcessCommand() *cobra.Command {
+	const STEP_NAME = "sbomProcess"
+
+	metadata := sbomProcessMetadata()
+	var stepConfig sbomProcessOptions
+	var startTime time.Time
+	var reports sbomProcessReports
+	var logCollector *log.CollectorHook
+	var splunkClient *splunk.Splunk
+	telemetryClient := &telemetry.Telemetry{}
+
+	var createSbomProcessCmd = &cobra.Command{
+		Use:   STEP_NAME,
+		Short: "Merges, validates, converts and compares the software bills of materials (SBOM) created by the build steps.",
+		Long: `Steps like ` + "`" + `mavenBuild` + "`" + `, ` + "`" + `npmExecuteScripts` + "`" + `, ` + "`" + `gradleExecuteBuild` + "`" + `, ` + "`" + `golangBuild` + "`" + `, ` + "`" + `pythonBuild` + "`" + `, ` + "`" + `kanikoExecute` + "`" + ` or ` + "`" + `whitesourceExecuteScan` + "`" + ` create CycloneDX BOMs for the parts of a product they build.
+This step combines them into one product BOM:
+
+* all BOMs matching ` + "`" + `bomFilePatterns` + "`" + ` are merged into a single BOM with a joined dependency graph, duplicate components are consolidated based on their package URL
+* each BOM is validated against the official CycloneDX JSON schema of its spec version (BOMs in XML format are validated in their JSON representation, SPDX documents are not validated)
+* the product BOM is validated against the minimum elements defined via ` + "`" + `requiredElements` + "`" + ` and for unique and resolvable ` + "`" + `bom-ref` + "`" + ` references
+* the product BOM is written in ` + "`" + `outputFormat` + "`" + ` as well as in all ` + "`" + `additionalOutputFormats` + "`" + ` (CycloneDX XML/JSON and SPDX JSON)
+* if a ` + "`" + `previousBomFile` + "`" + ` (e.g. the product BOM of the previous release) is available, added, removed and changed components are listed
+
+The results are provided as step report as well as in the file ` + "`" + `sbom-diff.json` + "`" + `.`,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			startTime = time.Now()
+			log.SetStepName(STEP_NAME)
+			log.SetVerbose(GeneralConfig.Verbose)
+
+			GeneralConfig.GitHubAccessTokens = ResolveAccessTokens(GeneralConfig.GitHubTokens)
+
+			path, _ := os.Getwd()
+			fatalHook := &log.FatalHook{CorrelationID: GeneralConfig.CorrelationID, Path: path}
+			log.RegisterHook(fatalHook)
+
+			err := PrepareConfig(cmd, &metadata, STEP_NAME, &stepConfig, config.OpenPiperFile)
+			if err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+
+			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
+				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
+				log.RegisterHook(&sentryHook)
+			}
+
+			if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+				splunkClient = &splunk.Splunk{}
+				logCollector = &log.CollectorHook{CorrelationID: GeneralConfig.CorrelationID}
+				log.RegisterHook(logCollector)
+			}
+
+			if err = log.RegisterANSHookIfConfigured(GeneralConfig.CorrelationID); err != nil {
+				log.Entry().WithError(err).Warn("failed to set up SAP Alert Notification Service log hook")
+			}
+
+			validation, err := validation.New(validation.WithJSONNamesForStructFields(), validation.WithPredefinedErrorMessages())
+			if err != nil {
+				return err
+			}
+			if err = validation.ValidateStruct(stepConfig); err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+
+			return nil
+		},
+		Run: func(_ *cobra.Command, _ []string) {
+			stepTelemetryData := telemetry.CustomData{}
+			stepTelemetryData.ErrorCode = "1"
+			handler := func() {
+				reports.persist(stepConfig, GeneralConfig.GCPJsonKeyFilePath, GeneralConfig.GCSBucketId, GeneralConfig.GCSFolderPath, GeneralConfig.GCSSubFolder)
+				config.RemoveVaultSecretFiles()
+				stepTelemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
+				stepTelemetryData.ErrorCategory = log.GetErrorCategory().String()
+				stepTelemetryData.PiperCommitHash = GitCommit
+				telemetryClient.SetData(&stepTelemetryData)
+				telemetryClient.Send()
+				if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+					splunkClient.Send(telemetryClient.GetData(), logCollector)
+				}
+			}
+			log.DeferExitHandler(handler)
+			defer handler()
+			telemetryClient.Initialize(GeneralConfig.NoTelemetry, STEP_NAME)
+			if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+				splunkClient.Initialize(GeneralConfig.CorrelationID,
+					GeneralConfig.HookConfig.SplunkConfig.Dsn,
+					GeneralConfig.HookConfig.SplunkConfig.Token,
+					GeneralConfig.HookConfig.SplunkConfig.Index,
+					GeneralConfig.HookConfig.SplunkConfig.SendLogs)
+			}
+			sbomProcess(stepConfig, &stepTelemetryData)
+			stepTelemetryData.ErrorCode = "0"
+			log.Entry().Info("SUCCESS")
+		},
+	}
+
+	addSbomProcessFlags(createSbomProcessCmd, &stepConfig)
+	return createSbomProcessCmd
+}
+
+func addSbomProcessFlags(cmd *cobra.Command, stepConfig *sbomProcessOptions) {
+	cmd.Flags().StringSliceVar(&stepConfig.BomFilePatterns, "bomFilePatterns", []string{`**/bom-*.xml`, `**/bom-*.json`}, "List of file patterns of the BOMs to be merged. CycloneDX XML/JSON and SPDX JSON files are supported.")
+	cmd.Flags().StringSliceVar(&stepConfig.Excludes, "excludes", []string{`**/node_modules/**`}, "List of file patterns which are excluded from `bomFilePatterns`.")
+	cmd.Flags().StringVar(&stepConfig.ProductName, "productName", os.Getenv("PIPER_productName"), "Name of the product described by the merged BOM.")
+	cmd.Flags().StringVar(&stepConfig.ProductGroup, "productGroup", os.Getenv("PIPER_productGroup"), "Group (e.g. organization or namespace) of the product described by the merged BOM.")
+	cmd.Flags().StringVar(&stepConfig.ArtifactVersion, "artifactVersion", os.Getenv("PIPER_artifactVersion"), "Version of the product described by the merged BOM.")
+	cmd.Flags().StringVar(&stepConfig.OutputFile, "outputFile", `product-bom.xml`, "Path of the merged product BOM.")
+	cmd.Flags().StringVar(&stepConfig.OutputFormat, "outputFormat", `cyclonedx-xml`, "Format of the merged product BOM.")
+	cmd.Flags().StringSliceVar(&stepConfig.AdditionalOutputFormats, "additionalOutputFormats", []string{}, "Additional formats in which the product BOM is provided. The file names are derived from `outputFile`,\ni.e. `product-bom.json` for `cyclonedx-json` and `product-bom.spdx.json` for `spdx-json`.")
+	cmd.Flags().StringSliceVar(&stepConfig.RequiredElements, "requiredElements", []string{`purl`, `licenses`, `hashes`}, "Elements each component of the product BOM needs to provide in addition to the elements required by the CycloneDX schema.")
+	cmd.Flags().BoolVar(&stepConfig.FailOnValidationErrors, "failOnValidationErrors", false, "Whether the step fails in case a BOM violates the schema or the product BOM misses required elements.")
+	cmd.Flags().StringVar(&stepConfig.PreviousBomFile, "previousBomFile", os.Getenv("PIPER_previousBomFile"), "Path of the BOM the product BOM is compared with, e.g. the BOM of the previous release. The comparison is skipped if the file does not exist.")
+	cmd.Flags().StringVar(&stepConfig.SchemaLocation, "schemaLocation", `https://cyclonedx.org/schema/`, "URL or local directory providing the CycloneDX JSON schemas `bom-<specVersion>.schema.json` together with the schemas they refer to (e.g. `spdx.schema.json`).\nUse a mirror or a directory in the workspace if the official location is not reachable from the build environment.\nIn case a schema cannot be loaded, the schema validation of the affected BOM is skipped and reported as validation issue.")
+
+	cmd.MarkFlagRequired("productName")
+}
+
+// retrieve step metadata
+func sbomProcessMetadata() config.StepData {
+	var theMetaData = config.StepData{
+		Metadata: config.StepMetadata{
+			Name:        "sbomProcess",
+			Aliases:     []config.Alias{},
+			Description: "Merges, validates, converts and compares the software bills of materials (SBOM) created by the build steps.",
+		},
+		Spec: config.StepSpec{
+			Inputs: config.StepInputs{
+				Parameters: []config.StepParameters{
+					{
+						Name:        "bomFilePatterns",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{`**/bom-*.xml`, `**/bom-*.json`},
+					},
+					{
+						Name:        "excludes",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{`**/node_modules/**`},
+					},
+					{
+						Name:        "productName",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   true,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_productName"),
+					},
+					{
+						Name:        "productGroup",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_productGroup"),
+					},
+					{
+						Name: "artifactVersion",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:  "commonPipelineEnvironment",
+								Param: "artifactVersion",
+							},
+						},
+						Scope:     []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_artifactVersion"),
+					},
+					{
+						Name:        "outputFile",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `product-bom.xml`,
+					},
+					{
+						Name:        "outputFormat",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `cyclonedx-xml`,
+					},
+					{
+						Name:        "additionalOutputFormats",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{},
+					},
+					{
+						Name:        "requiredElements",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{`purl`, `licenses`, `hashes`},
+					},
+					{
+						Name:        "failOnValidationErrors",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name:        "previousBomFile",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_previousBomFile"),
+					},
+					{
+						Name:        "schemaLocation",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `https://cyclonedx.org/schema/`,
+					},
+				},
+			},
+			Outputs: config.StepOutputs{
+				Resources: []config.StepResources{
+					{
+						Name: "reports",
+						Type: "reports",
+						Parameters: []map[string]interface{}{
+							{"filePattern": "**/product-bom*", "type": "sbom"},
+							{"filePattern": "**/sbom-diff.json", "type": "sbom"},
+							{"filePattern": "**/piper_sbom_report.html", "type": "sbom"},
+						},
+					},
+				},
+			},
+		},
+	}
+	return theMetaData
+}
@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/SAP/jenkins-library/pkg/sbom"
+	"github.com/stretchr/testify/assert"
+)
+
+type sbomProcessMockUtils struct {
+	*mock.FilesMock
+}
+
+func newSbomProcessTestsUtils(t *testing.T) sbomProcessMockUtils {
+	utils := sbomProcessMockUtils{
+		FilesMock: &mock.FilesMock{},
+	}
+	for target, source := range map[string]string{
+		"backend/target/bom-maven.xml": "../pkg/sbom/testdata/bom-maven.xml",
+		"frontend/bom-npm.json":        "../pkg/sbom/testdata/bom-npm.json",
+	} {
+		content, err := os.ReadFile(source)
+		if err != nil {
+			t.Fatal(err)
+		}
+		utils.AddFile(target, content)
+	}
+	utils.AddFile("frontend/node_modules/lodash/bom-npm.xml", []byte("<invalid"))
+	return utils
+}
+
+func defaultSbomProcessOptions() sbomProcessOptions {
+	return sbomProcessOptions{
+		BomFilePatterns: []string{"**/bom-*.xml", "**/bom-*.json"},
+		Excludes:        []string{"**/node_modules/**"},
+		ProductName:     "product",
+		ArtifactVersion: "1.0.0",
+		OutputFile:      "product-bom.xml",
+		OutputFormat:    "cyclonedx-xml",
+		SchemaLocation:  "../pkg/sbom/testdata/schema",
+	}
+}
+
+func TestRunSbomProcess(t *testing.T) {
+	t.Parallel()
+
+	t.Run("merge BOMs", func(t *testing.T) {
+		t.Parallel()
+		config := defaultSbomProcessOptions()
+		config.AdditionalOutputFormats = []string{"cyclonedx-json", "spdx-json"}
+		utils := newSbomProcessTestsUtils(t)
+
+		err := runSbomProcess(&config, utils)
+
+		assert.NoError(t, err)
+		assert.True(t, utils.HasWrittenFile("product-bom.xml"))
+		assert.True(t, utils.HasWrittenFile("product-bom.json"))
+		assert.True(t, utils.HasWrittenFile("product-bom.spdx.json"))
+		assert.True(t, utils.HasWrittenFile(filepath.Join("sbom", "piper_sbom_report.html")))
+		assert.False(t, utils.HasWrittenFile("sbom-diff.json"))
+
+		productBom, err := sbom.ReadBOM("product-bom.xml", utils)
+		assert.NoError(t, err)
+		assert.Equal(t, "pkg:generic/product@1.0.0", productBom.Metadata.Component.BOMRef)
+		assert.Len(t, sbom.Components(productBom), 5)
+	})
+
+	t.Run("compare with previous BOM", func(t *testing.T) {
+		t.Parallel()
+		config := defaultSbomProcessOptions()
+		config.PreviousBomFile = "previous/product-bom.json"
+		utils := newSbomProcessTestsUtils(t)
+		utils.AddFile("previous/product-bom.json", []byte(`{
+			"bomFormat": "CycloneDX", "specVersion": "1.4", "version": 1,
+			"components": [
+				{"type": "library", "name": "backend", "group": "com.sap", "version": "0.9.0", "purl": "pkg:maven/com.sap/backend@0.9.0"},
+				{"type": "library", "name": "slf4j-api", "group": "org.slf4j", "version": "1.7.36", "purl": "pkg:maven/org.slf4j/slf4j-api@1.7.36", "licenses": [{"license": {"id": "MIT"}}]},
+				{"type": "library", "name": "jackson-databind", "group": "com.fasterxml.jackson.core", "version": "2.13.3", "purl": "pkg:maven/com.fasterxml.jackson.core/jackson-databind@2.13.3"},
+				{"type": "library", "name": "log4j-core", "group": "org.apache.logging.log4j", "version": "2.14.1", "purl": "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1"}
+			]
+		}`))
+
+		err := runSbomProcess(&config, utils)
+
+		assert.NoError(t, err)
+		diffContent, err := utils.FileRead("sbom-diff.json")
+		assert.NoError(t, err)
+		diff := sbom.DiffResult{}
+		assert.NoError(t, json.Unmarshal(diffContent, &diff))
+		assert.Len(t, diff.Added, 2)
+		assert.Equal(t, "frontend", diff.Added[0].Name)
+		assert.Equal(t, "lodash", diff.Added[1].Name)
+		assert.Len(t, diff.Removed, 1)
+		assert.Equal(t, "log4j-core", diff.Removed[0].Name)
+		assert.Len(t, diff.Changed, 1)
+		assert.Equal(t, "pkg:maven/com.sap/backend", diff.Changed[0].Key)
+		assert.Equal(t, "0.9.0", diff.Changed[0].PreviousVersion)
+	})
+
+	t.Run("validation errors", func(t *testing.T) {
+		t.Parallel()
+		config := defaultSbomProcessOptions()
+		config.RequiredElements = []string{"purl", "licenses", "hashes"}
+		config.FailOnValidationErrors = true
+		utils := newSbomProcessTestsUtils(t)
+
+		err := runSbomProcess(&config, utils)
+
+		assert.EqualError(t, err, "product BOM validation failed with 6 issue(s)")
+		assert.True(t, utils.HasWrittenFile("product-bom.xml"))
+	})
+
+	t.Run("schema violations", func(t *testing.T) {
+		t.Parallel()
+		config := defaultSbomProcessOptions()
+		config.FailOnValidationErrors = true
+		utils := newSbomProcessTestsUtils(t)
+		utils.AddFile("tools/bom-tools.json", []byte(`{"bomFormat": "CycloneDX", "specVersion": "1.4", "components": [{"type": "unknown", "name": "tool"}]}`))
+
+		err := runSbomProcess(&config, utils)
+
+		assert.EqualError(t, err, "product BOM validation failed with 1 issue(s)")
+		assert.True(t, utils.HasWrittenFile("product-bom.xml"))
+	})
+
+	t.Run("schema not available", func(t *testing.T) {
+		t.Parallel()
+		config := defaultSbomProcessOptions()
+		config.SchemaLocation = "../pkg/sbom/testdata/missing"
+		utils := newSbomProcessTestsUtils(t)
+
+		err := runSbomProcess(&config, utils)
+
+		assert.NoError(t, err)
+		assert.True(t, utils.HasWrittenFile("product-bom.xml"))
+	})
+
+	t.Run("no BOM files", func(t *testing.T) {
+		t.Parallel()
+		config := defaultSbomProcessOptions()
+		config.BomFilePatterns = []string{"**/bom-none.xml"}
+		utils := newSbomProcessTestsUtils(t)
+
+		err := runSbomProcess(&config, utils)
+
+		assert.EqualError(t, err, "no BOM files found matching the patterns [**/bom-none.xml]")
+	})
+}
+
+func TestSbomOutputFile(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "sbom/product.xml", sbomOutputFile("sbom/product.xml", sbom.FormatCycloneDXXML))
+	assert.Equal(t, "sbom/product.json", sbomOutputFile("sbom/product.xml", sbom.FormatCycloneDXJSON))
+	assert.Equal(t, "sbom/product.spdx.json", sbomOutputFile("sbom/product.xml", sbom.FormatSPDXJSON))
+}
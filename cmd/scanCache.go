@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/scancache"
+	"github.com/pkg/errors"
+)
+
+// newScanCache creates the cache for scan results. It returns a nil cache, which never contains any entry, if caching is
+// disabled or the cache cannot be accessed. The returned function releases the resources of the cache.
+func newScanCache(enabled bool, location string, maxAgeHours int, utils piperutils.FileUtils) (*scancache.Cache, func()) {
+	noop := func() {}
+	if !enabled {
+		return nil, noop
+	}
+	if len(location) == 0 {
+		log.Entry().Info("scan cache is disabled, because scanCacheLocation is empty")
+		return nil, noop
+	}
+	store, closeStore := newCacheStore("scan cache", location, utils)
+	if store == nil {
+		return nil, closeStore
+	}
+	return scancache.New(&scancache.ObjectStore{Objects: store, Files: utils}, time.Duration(maxAgeHours)*time.Hour), closeStore
+}
+
+// restoreScanResult restores the files of a cached scan and returns the error with which the cached scan failed.
+// It returns false if the files cannot be restored, the content has to be scanned again in that case.
+func restoreScanResult(entry *scancache.Entry, utils piperutils.FileUtils) (bool, error) {
+	if err := entry.Restore(utils); err != nil {
+		log.Entry().WithError(err).Warn("failed to restore cached scan result, scanning without cache")
+		return false, nil
+	}
+	if len(entry.Error) > 0 {
+		if len(entry.ErrorCategory) > 0 {
+			log.SetErrorCategory(log.ErrorCategoryByString(entry.ErrorCategory))
+		}
+		return true, errors.New(entry.Error)
+	}
+	return true, nil
+}
+
+// saveScanResult stores the files matching the patterns together with the outcome of the scan in the scan cache
+func saveScanResult(cache *scancache.Cache, entry *scancache.Entry, scanErr error, utils piperutils.FileUtils, patterns ...string) {
+	if cache == nil {
+		return
+	}
+	if err := entry.AddFiles(utils, patterns...); err != nil {
+		log.Entry().WithError(err).Warn("failed to collect scan result for scan cache")
+		return
+	}
+	if scanErr != nil {
+		entry.Error = scanErr.Error()
+		if category := log.GetErrorCategory(); category != log.ErrorUndefined {
+			entry.ErrorCategory = category.String()
+		}
+	}
+	cache.Save(entry)
+}
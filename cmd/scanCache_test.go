@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/SAP/jenkins-library/pkg/scancache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewScanCache(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		cache, closeCache := newScanCache(false, "scancache", 24, &mock.FilesMock{})
+		defer closeCache()
+		assert.Nil(t, cache)
+	})
+
+	t.Run("without location", func(t *testing.T) {
+		cache, closeCache := newScanCache(true, "", 24, &mock.FilesMock{})
+		defer closeCache()
+		assert.Nil(t, cache)
+	})
+
+	t.Run("unsupported location", func(t *testing.T) {
+		cache, closeCache := newScanCache(true, "https://example.org/cache", 24, &mock.FilesMock{})
+		defer closeCache()
+		assert.Nil(t, cache)
+	})
+
+	t.Run("local directory", func(t *testing.T) {
+		files := &mock.FilesMock{}
+		cache, closeCache := newScanCache(true, "scancache", 24, files)
+		defer closeCache()
+
+		if assert.NotNil(t, cache) {
+			saveScanResult(cache, scancache.NewEntry("malwareExecuteScan", "abc"), nil, files)
+			assert.True(t, files.HasFile("scancache/abc.json"))
+			assert.NotNil(t, cache.Lookup("abc"))
+		}
+	})
+}
+
+func TestRestoreScanResult(t *testing.T) {
+	entry := scancache.NewEntry("protecodeExecuteScan", "abc")
+	entry.Files = []scancache.File{{Path: "protecode/piper_protecode_report.html", Content: []byte("<html/>")}}
+	entry.Error = "the product is not compliant"
+	entry.ErrorCategory = "compliance"
+	files := &mock.FilesMock{}
+	defer log.SetErrorCategory(log.ErrorUndefined)
+
+	restored, err := restoreScanResult(entry, files)
+
+	assert.True(t, restored)
+	assert.EqualError(t, err, "the product is not compliant")
+	assert.Equal(t, log.ErrorCompliance, log.GetErrorCategory())
+	assert.True(t, files.HasFile("protecode/piper_protecode_report.html"))
+}
+
+func TestSaveScanResult(t *testing.T) {
+	files := &mock.FilesMock{}
+	files.AddFile("protecode/piper_protecode_report.html", []byte("<html/>"))
+	cache, closeCache := newScanCache(true, "scancache", 24, files)
+	defer closeCache()
+	log.SetErrorCategory(log.ErrorCompliance)
+	defer log.SetErrorCategory(log.ErrorUndefined)
+
+	saveScanResult(cache, scancache.NewEntry("protecodeExecuteScan", "abc"), errors.New("the product is not compliant"), files, "protecode/*.html")
+
+	entry := cache.Lookup("abc")
+	if assert.NotNil(t, entry) {
+		assert.Equal(t, "the product is not compliant", entry.Error)
+		assert.Equal(t, "compliance", entry.ErrorCategory)
+	}
+}
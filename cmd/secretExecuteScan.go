@@ -0,0 +1,280 @@
+package cmd
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/git"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/orchestrator"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/reporting"
+	"github.com/SAP/jenkins-library/pkg/secretscan"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/pkg/errors"
+)
+
+const secretscanReportsDirectory = "secretscan"
+
+type secretExecuteScanUtils interface {
+	piperutils.FileUtils
+	// LogRange provides the commits reachable from 'to' but not from 'from' of the repository in the working directory
+	LogRange(from, to string) (object.CommitIter, error)
+	GetProvider() orchestrator.OrchestratorSpecificConfigProviding
+}
+
+type secretExecuteScanUtilsBundle struct {
+	*piperutils.Files
+	provider orchestrator.OrchestratorSpecificConfigProviding
+}
+
+func (s *secretExecuteScanUtilsBundle) GetProvider() orchestrator.OrchestratorSpecificConfigProviding {
+	return s.provider
+}
+
+func (s *secretExecuteScanUtilsBundle) LogRange(from, to string) (object.CommitIter, error) {
+	repository, err := git.PlainOpen(".")
+	if err != nil {
+		return nil, err
+	}
+	return git.LogRange(repository, from, to)
+}
+
+func newSecretExecuteScanUtils() secretExecuteScanUtils {
+	utils := secretExecuteScanUtilsBundle{
+		Files: &piperutils.Files{},
+	}
+
+	provider, err := orchestrator.NewOrchestratorSpecificConfigProvider()
+	if err != nil {
+		log.Entry().WithError(err).Warning(err)
+		provider = &orchestrator.UnknownOrchestratorConfigProvider{}
+	}
+	utils.provider = provider
+
+	return &utils
+}
+
+func secretExecuteScan(config secretExecuteScanOptions, telemetryData *telemetry.CustomData, influx *secretExecuteScanInflux) {
+	utils := newSecretExecuteScanUtils()
+
+	err := runSecretExecuteScan(&config, utils, influx)
+	if err != nil {
+		log.Entry().WithError(err).Fatal("step execution failed")
+	}
+}
+
+func runSecretExecuteScan(config *secretExecuteScanOptions, utils secretExecuteScanUtils, influx *secretExecuteScanInflux) error {
+	scanner, err := newSecretScanner(config, utils)
+	if err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return err
+	}
+
+	files, err := findSecretScanFiles(config, utils)
+	if err != nil {
+		return err
+	}
+	findings := []secretscan.Finding{}
+	for _, file := range files {
+		content, err := readSecretScanFile(file, config.MaxFileSize, utils)
+		if err != nil {
+			return err
+		}
+		if content == nil {
+			log.Entry().Debugf("Skipping file '%v' exceeding the maximum file size", file)
+			continue
+		}
+		findings = append(findings, scanner.ScanContent(filepath.ToSlash(file), content)...)
+	}
+	log.Entry().Infof("%v files scanned", len(files))
+
+	commits := 0
+	if config.ScanHistory {
+		historyFrom := config.HistoryFrom
+		if len(historyFrom) == 0 && utils.GetProvider().IsPullRequest() {
+			// scan the commits of the pull request only
+			historyFrom = "origin/" + strings.TrimPrefix(utils.GetProvider().GetPullRequestConfig().Base, "refs/heads/")
+		}
+		if len(historyFrom) == 0 {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return errors.New("parameter historyFrom is required for scanHistory outside of pull requests")
+		}
+		log.Entry().Infof("Scanning commits from '%v' to '%v'", historyFrom, config.HistoryTo)
+		commitIter, err := utils.LogRange(historyFrom, config.HistoryTo)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return errors.Wrap(err, "failed to determine the commits to be scanned")
+		}
+		historyFindings, scanned, err := scanner.ScanHistory(commitIter)
+		if err != nil {
+			return errors.Wrap(err, "failed to scan the git history")
+		}
+		commits = scanned
+		// secrets still contained in the workspace are reported for their current location
+		found := map[string]bool{}
+		for _, finding := range findings {
+			found[finding.Fingerprint] = true
+		}
+		for _, finding := range historyFindings {
+			if !found[finding.Fingerprint] {
+				findings = append(findings, finding)
+			}
+		}
+		log.Entry().Infof("%v commits scanned", commits)
+	}
+
+	if len(config.BaselineFile) > 0 {
+		baseline, err := readSecretScanBaseline(config.BaselineFile, utils)
+		if err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return err
+		}
+		baseline.Apply(findings)
+	}
+
+	for _, finding := range findings {
+		location := fmt.Sprintf("%v:%v", finding.File, finding.Line)
+		if len(finding.Commit) > 0 {
+			location = fmt.Sprintf("%v (commit %v)", location, finding.Commit)
+		}
+		if finding.New {
+			log.Entry().Errorf("%v found in %v: %v", finding.Description, location, finding.Match)
+		} else {
+			log.Entry().Infof("%v found in %v: %v (contained in baseline)", finding.Description, location, finding.Match)
+		}
+	}
+
+	newFindings := secretscan.CountNew(findings)
+	influx.secretscan_data.fields.secrets = len(findings)
+	influx.secretscan_data.fields.new_secrets = newFindings
+
+	scanReport := secretscan.CreateScanReport(findings, len(files), commits)
+	reportPaths, err := writeSecretScanReports(scanReport, findings, utils)
+	if err != nil {
+		return err
+	}
+	piperutils.PersistReportsAndLinks("secretExecuteScan", "./", utils, reportPaths, nil)
+
+	if newFindings > 0 && config.FailOnNewSecrets {
+		log.SetErrorCategory(log.ErrorCompliance)
+		return fmt.Errorf("%v new secret(s) found, please remove them and revoke them", newFindings)
+	}
+	return nil
+}
+
+func newSecretScanner(config *secretExecuteScanOptions, utils secretExecuteScanUtils) (*secretscan.Scanner, error) {
+	rules := []secretscan.Rule{}
+	if config.UseDefaultRules {
+		rules = secretscan.DefaultRules()
+	}
+	if len(config.RulesFile) > 0 {
+		content, err := utils.FileRead(config.RulesFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read rules file '%v'", config.RulesFile)
+		}
+		custom, err := secretscan.ReadRules(content)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read rules file '%v'", config.RulesFile)
+		}
+		rules = secretscan.MergeRules(rules, custom)
+	}
+	if len(rules) == 0 {
+		return nil, fmt.Errorf("no rules available, please provide a rulesFile or activate useDefaultRules")
+	}
+
+	var allowlist *secretscan.Allowlist
+	if exists, _ := utils.FileExists(config.AllowlistFile); exists {
+		content, err := utils.FileRead(config.AllowlistFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read allowlist '%v'", config.AllowlistFile)
+		}
+		if allowlist, err = secretscan.ReadAllowlist(content); err != nil {
+			return nil, errors.Wrapf(err, "failed to read allowlist '%v'", config.AllowlistFile)
+		}
+	} else if len(config.AllowlistFile) > 0 {
+		log.Entry().Infof("Allowlist '%v' not found, continuing without allowlist", config.AllowlistFile)
+	}
+
+	fingerprintKey := []byte(config.FingerprintKey)
+	if len(fingerprintKey) == 0 {
+		if len(config.BaselineFile) > 0 || (allowlist != nil && len(allowlist.Fingerprints) > 0) {
+			return nil, fmt.Errorf("parameter fingerprintKey is required for comparing the findings with the baseline or the allowlist fingerprints")
+		}
+		log.Entry().Warn("No fingerprintKey configured, the fingerprints of the findings are only valid for this run")
+		fingerprintKey = make([]byte, 32)
+		if _, err := rand.Read(fingerprintKey); err != nil {
+			return nil, errors.Wrap(err, "failed to create fingerprint key")
+		}
+	}
+	return secretscan.NewScanner(rules, allowlist, fingerprintKey)
+}
+
+func findSecretScanFiles(config *secretExecuteScanOptions, utils secretExecuteScanUtils) ([]string, error) {
+	return piperutils.FindFiles(config.ScanPatterns, config.Excludes, utils)
+}
+
+// readSecretScanFile reads the content of a file, nil is returned if the file exceeds the maximum size
+func readSecretScanFile(file string, maxFileSize int, utils secretExecuteScanUtils) ([]byte, error) {
+	reader, err := utils.Open(file)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open file '%v'", file)
+	}
+	defer reader.Close()
+	var source io.Reader = reader
+	if maxFileSize > 0 {
+		source = io.LimitReader(reader, int64(maxFileSize)+1)
+	}
+	content, err := io.ReadAll(source)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read file '%v'", file)
+	}
+	if maxFileSize > 0 && len(content) > maxFileSize {
+		return nil, nil
+	}
+	return content, nil
+}
+
+func readSecretScanBaseline(baselineFile string, utils secretExecuteScanUtils) (secretscan.Baseline, error) {
+	content, err := utils.FileRead(baselineFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read baseline '%v'", baselineFile)
+	}
+	baseline, err := secretscan.ReadBaseline(content)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read baseline '%v'", baselineFile)
+	}
+	return baseline, nil
+}
+
+func writeSecretScanReports(scanReport reporting.ScanReport, findings []secretscan.Finding, utils secretExecuteScanUtils) ([]piperutils.Path, error) {
+	reportPaths, err := reporting.WriteScanReports(scanReport, reporting.ScanReportFiles{
+		Directory: secretscanReportsDirectory,
+		Name:      "Secret Scan",
+		HTMLFile:  "piper_secretscan_report.html",
+		SarifFile: "piper_secretscan.sarif",
+		Sarif:     secretscan.CreateSarif(findings),
+		JSONFile:  "secretExecuteScan_secrets.json",
+	}, utils)
+	if err != nil {
+		return reportPaths, err
+	}
+
+	// the findings file can be used as baseline for subsequent scans
+	findingsFile, err := json.MarshalIndent(findings, "", "  ")
+	if err != nil {
+		return reportPaths, errors.Wrap(err, "failed to marshall findings")
+	}
+	findingsPath := filepath.Join(secretscanReportsDirectory, "piper_secretscan_findings.json")
+	if err := utils.FileWrite(findingsPath, findingsFile, 0666); err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return reportPaths, errors.Wrap(err, "failed to write findings file")
+	}
+	reportPaths = append(reportPaths, piperutils.Path{Name: "Secret Scan findings", Target: findingsPath})
+	return reportPaths, nil
+}
@@ -0,0 +1,395 @@
+// Code generated by piper's step-generator. DO NOT EDIT.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/config"
+	"github.com/SAP/jenkins-library/pkg/gcs"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperenv"
+	"github.com/SAP/jenkins-library/pkg/splunk"
+	"github.com/SAP/jenkins-library/pkg/telemetry"
+	"github.com/SAP/jenkins-library/pkg/validation"
+	"github.com/bmatcuk/doublestar"
+	"github.com/spf13/cobra"
+)
+
+type secretExecuteScanOptions struct {
+	ScanPatterns     []string `json:"scanPatterns,omitempty"`
+	Excludes         []string `json:"excludes,omitempty"`
+	MaxFileSize      int      `json:"maxFileSize,omitempty"`
+	RulesFile        string   `json:"rulesFile,omitempty"`
+	UseDefaultRules  bool     `json:"useDefaultRules,omitempty"`
+	AllowlistFile    string   `json:"allowlistFile,omitempty"`
+	BaselineFile     string   `json:"baselineFile,omitempty"`
+	FingerprintKey   string   `json:"fingerprintKey,omitempty"`
+	ScanHistory      bool     `json:"scanHistory,omitempty"`
+	HistoryFrom      string   `json:"historyFrom,omitempty"`
+	HistoryTo        string   `json:"historyTo,omitempty"`
+	FailOnNewSecrets bool     `json:"failOnNewSecrets,omitempty"`
+}
+
+type secretExecuteScanInflux struct {
+	secretscan_data struct {
+		fields struct {
+			secrets     int
+			new_secrets int
+		}
+		tags struct {
+		}
+	}
+}
+
+func (i *secretExecuteScanInflux) persist(path, resourceName string) {
+	measurementContent := []struct {
+		measurement string
+		valType     string
+		name        string
+		value       interface{}
+	}{
+		{valType: config.InfluxField, measurement: "secretscan_data", name: "secrets", value: i.secretscan_data.fields.secrets},
+		{valType: config.InfluxField, measurement: "secretscan_data", name: "new_secrets", value: i.secretscan_data.fields.new_secrets},
+	}
+
+	errCount := 0
+	for _, metric := range measurementContent {
+		err := piperenv.SetResourceParameter(path, resourceName, filepath.Join(metric.measurement, fmt.Sprintf("%vs", metric.valType), metric.name), metric.value)
+		if err != nil {
+			log.Entry().WithError(err).Error("Error persisting influx environment.")
+			errCount++
+		}
+	}
+	if errCount > 0 {
+		log.Entry().Error("failed to persist Influx environment")
+	}
+}
+
+type secretExecuteScanReports struct {
+}
+
+func (p *secretExecuteScanReports) persist(stepConfig secretExecuteScanOptions, gcpJsonKeyFilePath string, gcsBucketId string, gcsFolderPath string, gcsSubFolder string) {
+	if gcsBucketId == "" {
+		log.Entry().Info("persisting reports to GCS is disabled, because gcsBucketId is empty")
+		return
+	}
+	log.Entry().Info("Uploading reports to Google Cloud Storage...")
+	content := []gcs.ReportOutputParam{
+		{FilePattern: "**/piper_secretscan_report.html", ParamRef: "", StepResultType: "secretscan"},
+		{FilePattern: "**/piper_secretscan.sarif", ParamRef: "", StepResultType: "secretscan"},
+		{FilePattern: "**/piper_secretscan_findings.json", ParamRef: "", StepResultType: "secretscan"},
+	}
+	envVars := []gcs.EnvVar{
+		{Name: "GOOGLE_APPLICATION_CREDENTIALS", Value: gcpJsonKeyFilePath, Modified: false},
+	}
+	gcsClient, err := gcs.NewClient(gcs.WithEnvVars(envVars))
+	if err != nil {
+		log.Entry().Errorf("creation of GCS client failed: %v", err)
+		return
+	}
+	defer gcsClient.Close()
+	structVal := reflect.ValueOf(&stepConfig).Elem()
+	inputParameters := map[string]string{}
+	for i := 0; i < structVal.NumField(); i++ {
+		field := structVal.Type().Field(i)
+		if field.Type.String() == "string" {
+			paramName := strings.Split(field.Tag.Get("json"), ",")
+			paramValue, _ := structVal.Field(i).Interface().(string)
+			inputParameters[paramName[0]] = paramValue
+		}
+	}
+	if err := gcs.PersistReportsToGCS(gcsClient, content, inputParameters, gcsFolderPath, gcsBucketId, gcsSubFolder, doublestar.Glob, os.Stat); err != nil {
+		log.Entry().Errorf("failed to persist reports: %v", err)
+	}
+}
+
+// SecretExecuteScanCommand Scans the sources and optionally the git history for hard-coded secrets without calling an external service.
+func SecretExecuteScanCommand() *cobra.Command {
+	const STEP_NAME = "secretExecuteScan"
+
+	metadata := secretExecuteScanMetadata()
+	var stepConfig secretExecuteScanOptions
+	var startTime time.Time
+	var influx secretExecuteScanInflux
+	var reports secretExecuteScanReports
+	var logCollector *log.CollectorHook
+	var splunkClient *splunk.Splunk
+	telemetryClient := &telemetry.Telemetry{}
+
+	var createSecretExecuteScanCmd = &cobra.Command{
+		Use:   STEP_NAME,
+		Short: "Scans the sources and optionally the git history for hard-coded secrets without calling an external service.",
+		Long: `This step detects secrets like access tokens, private keys and passwords in the files of the workspace based on a set of
+regular expression rules. Rules may define a minimum Shannon entropy of the secret in order to ignore placeholders.
+Besides the built-in rules, custom rules can be provided via ` + "`" + `rulesFile` + "`" + `.
+
+With ` + "`" + `scanHistory` + "`" + ` the lines added by the commits between ` + "`" + `historyFrom` + "`" + ` and ` + "`" + `historyTo` + "`" + ` (e.g. the commits of a pull request)
+are scanned as well, since secrets removed from the current sources are still contained in the git history.
+
+False positives can be ignored via an allowlist file, via the marker ` + "`" + `secretscan:allow` + "`" + ` within the affected line or via a baseline.
+The baseline is the findings file (` + "`" + `secretscan/piper_secretscan_findings.json` + "`" + `) of a previous run, only secrets not contained
+in the baseline are treated as new and fail the step.
+Fingerprints are keyed with ` + "`" + `fingerprintKey` + "`" + `, which therefore has to stay the same as long as the baseline and the
+allowlist fingerprints are in use.
+The results are provided as HTML report and as SARIF file, secrets are only contained in redacted form.`,
+		PreRunE: func(cmd *cobra.Command, _ []string) error {
+			startTime = time.Now()
+			log.SetStepName(STEP_NAME)
+			log.SetVerbose(GeneralConfig.Verbose)
+
+			GeneralConfig.GitHubAccessTokens = ResolveAccessTokens(GeneralConfig.GitHubTokens)
+
+			path, _ := os.Getwd()
+			fatalHook := &log.FatalHook{CorrelationID: GeneralConfig.CorrelationID, Path: path}
+			log.RegisterHook(fatalHook)
+
+			err := PrepareConfig(cmd, &metadata, STEP_NAME, &stepConfig, config.OpenPiperFile)
+			if err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+			log.RegisterSecret(stepConfig.FingerprintKey)
+
+			if len(GeneralConfig.HookConfig.SentryConfig.Dsn) > 0 {
+				sentryHook := log.NewSentryHook(GeneralConfig.HookConfig.SentryConfig.Dsn, GeneralConfig.CorrelationID)
+				log.RegisterHook(&sentryHook)
+			}
+
+			if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+				splunkClient = &splunk.Splunk{}
+				logCollector = &log.CollectorHook{CorrelationID: GeneralConfig.CorrelationID}
+				log.RegisterHook(logCollector)
+			}
+
+			if err = log.RegisterANSHookIfConfigured(GeneralConfig.CorrelationID); err != nil {
+				log.Entry().WithError(err).Warn("failed to set up SAP Alert Notification Service log hook")
+			}
+
+			validation, err := validation.New(validation.WithJSONNamesForStructFields(), validation.WithPredefinedErrorMessages())
+			if err != nil {
+				return err
+			}
+			if err = validation.ValidateStruct(stepConfig); err != nil {
+				log.SetErrorCategory(log.ErrorConfiguration)
+				return err
+			}
+
+			return nil
+		},
+		Run: func(_ *cobra.Command, _ []string) {
+			stepTelemetryData := telemetry.CustomData{}
+			stepTelemetryData.ErrorCode = "1"
+			handler := func() {
+				influx.persist(GeneralConfig.EnvRootPath, "influx")
+				reports.persist(stepConfig, GeneralConfig.GCPJsonKeyFilePath, GeneralConfig.GCSBucketId, GeneralConfig.GCSFolderPath, GeneralConfig.GCSSubFolder)
+				config.RemoveVaultSecretFiles()
+				stepTelemetryData.Duration = fmt.Sprintf("%v", time.Since(startTime).Milliseconds())
+				stepTelemetryData.ErrorCategory = log.GetErrorCategory().String()
+				stepTelemetryData.PiperCommitHash = GitCommit
+				telemetryClient.SetData(&stepTelemetryData)
+				telemetryClient.Send()
+				if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+					splunkClient.Send(telemetryClient.GetData(), logCollector)
+				}
+			}
+			log.DeferExitHandler(handler)
+			defer handler()
+			telemetryClient.Initialize(GeneralConfig.NoTelemetry, STEP_NAME)
+			if len(GeneralConfig.HookConfig.SplunkConfig.Dsn) > 0 {
+				splunkClient.Initialize(GeneralConfig.CorrelationID,
+					GeneralConfig.HookConfig.SplunkConfig.Dsn,
+					GeneralConfig.HookConfig.SplunkConfig.Token,
+					GeneralConfig.HookConfig.SplunkConfig.Index,
+					GeneralConfig.HookConfig.SplunkConfig.SendLogs)
+			}
+			secretExecuteScan(stepConfig, &stepTelemetryData, &influx)
+			stepTelemetryData.ErrorCode = "0"
+			log.Entry().Info("SUCCESS")
+		},
+	}
+
+	addSecretExecuteScanFlags(createSecretExecuteScanCmd, &stepConfig)
+	return createSecretExecuteScanCmd
+}
+
+func addSecretExecuteScanFlags(cmd *cobra.Command, stepConfig *secretExecuteScanOptions) {
+	cmd.Flags().StringSliceVar(&stepConfig.ScanPatterns, "scanPatterns", []string{`**/*`}, "List of file patterns of the files to be scanned.")
+	cmd.Flags().StringSliceVar(&stepConfig.Excludes, "excludes", []string{`.git/**`, `**/node_modules/**`, `secretscan/**`}, "List of file patterns which are excluded from `scanPatterns`.")
+	cmd.Flags().IntVar(&stepConfig.MaxFileSize, "maxFileSize", 1.048576e+06, "Maximum size of a file in bytes, larger files are not scanned.")
+	cmd.Flags().StringVar(&stepConfig.RulesFile, "rulesFile", os.Getenv("PIPER_rulesFile"), "Path of a YAML file containing custom rules. Custom rules replace built-in rules with the same id.\nEach rule consists of `id`, `description`, `regex`, `secretGroup` (capturing group containing the secret), `entropy` (minimum Shannon entropy), `keywords` and `severity`.")
+	cmd.Flags().BoolVar(&stepConfig.UseDefaultRules, "useDefaultRules", true, "Whether the built-in rules are used in addition to the rules of `rulesFile`.")
+	cmd.Flags().StringVar(&stepConfig.AllowlistFile, "allowlistFile", `.pipeline/secretscan-allowlist.yaml`, "Path of a YAML file defining the findings to be ignored via `paths` (file patterns), `regexes` (matched against the secret) and `fingerprints`.\nThe step continues without allowlist if the file does not exist.")
+	cmd.Flags().StringVar(&stepConfig.BaselineFile, "baselineFile", os.Getenv("PIPER_baselineFile"), "Path of the findings file of a previous run. Secrets contained in the baseline are reported but do not fail the step.")
+	cmd.Flags().StringVar(&stepConfig.FingerprintKey, "fingerprintKey", os.Getenv("PIPER_fingerprintKey"), "Project specific key of the HMAC used for the fingerprints of the findings. Required for comparing findings with `baselineFile` or with the fingerprints of the allowlist. Without it the fingerprints are only valid for the current run.")
+	cmd.Flags().BoolVar(&stepConfig.ScanHistory, "scanHistory", false, "Whether the lines added by the commits between `historyFrom` and `historyTo` are scanned in addition to the workspace.")
+	cmd.Flags().StringVar(&stepConfig.HistoryFrom, "historyFrom", os.Getenv("PIPER_historyFrom"), "Commit or reference from which on the history is scanned (exclusive), e.g. the target branch of a pull request. If not set, the target branch of the pull request is used for pull request builds, outside of pull requests it is required for `scanHistory`.")
+	cmd.Flags().StringVar(&stepConfig.HistoryTo, "historyTo", `HEAD`, "Commit or reference up to which the history is scanned (inclusive).")
+	cmd.Flags().BoolVar(&stepConfig.FailOnNewSecrets, "failOnNewSecrets", true, "Whether the step fails in case secrets are found which are not contained in the baseline.")
+
+}
+
+// retrieve step metadata
+func secretExecuteScanMetadata() config.StepData {
+	var theMetaData = config.StepData{
+		Metadata: config.StepMetadata{
+			Name:        "secretExecuteScan",
+			Aliases:     []config.Alias{},
+			Description: "Scans the sources and optionally the git history for hard-coded secrets without calling an external service.",
+		},
+		Spec: config.StepSpec{
+			Inputs: config.StepInputs{
+				Secrets: []config.StepSecrets{
+					{Name: "fingerprintKeyCredentialsId", Description: "Jenkins 'Secret text' credentials ID containing the project specific key used for the fingerprints of the findings.", Type: "jenkins"},
+				},
+				Parameters: []config.StepParameters{
+					{
+						Name:        "scanPatterns",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{`**/*`},
+					},
+					{
+						Name:        "excludes",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "[]string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     []string{`.git/**`, `**/node_modules/**`, `secretscan/**`},
+					},
+					{
+						Name:        "maxFileSize",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "int",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     1.048576e+06,
+					},
+					{
+						Name:        "rulesFile",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_rulesFile"),
+					},
+					{
+						Name:        "useDefaultRules",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"GENERAL", "PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     true,
+					},
+					{
+						Name:        "allowlistFile",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `.pipeline/secretscan-allowlist.yaml`,
+					},
+					{
+						Name:        "baselineFile",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_baselineFile"),
+					},
+					{
+						Name: "fingerprintKey",
+						ResourceRef: []config.ResourceReference{
+							{
+								Name:    "secretScanVaultSecretName",
+								Type:    "vaultSecret",
+								Default: "secretscan",
+							},
+
+							{
+								Name: "fingerprintKeyCredentialsId",
+								Type: "secret",
+							},
+						},
+						Scope:     []string{"PARAMETERS"},
+						Type:      "string",
+						Mandatory: false,
+						Aliases:   []config.Alias{},
+						Default:   os.Getenv("PIPER_fingerprintKey"),
+					},
+					{
+						Name:        "scanHistory",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     false,
+					},
+					{
+						Name:        "historyFrom",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     os.Getenv("PIPER_historyFrom"),
+					},
+					{
+						Name:        "historyTo",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "string",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     `HEAD`,
+					},
+					{
+						Name:        "failOnNewSecrets",
+						ResourceRef: []config.ResourceReference{},
+						Scope:       []string{"PARAMETERS", "STAGES", "STEPS"},
+						Type:        "bool",
+						Mandatory:   false,
+						Aliases:     []config.Alias{},
+						Default:     true,
+					},
+				},
+			},
+			Outputs: config.StepOutputs{
+				Resources: []config.StepResources{
+					{
+						Name: "influx",
+						Type: "influx",
+						Parameters: []map[string]interface{}{
+							{"name": "secretscan_data", "fields": []map[string]string{{"name": "secrets"}, {"name": "new_secrets"}}},
+						},
+					},
+					{
+						Name: "reports",
+						Type: "reports",
+						Parameters: []map[string]interface{}{
+							{"filePattern": "**/piper_secretscan_report.html", "type": "secretscan"},
+							{"filePattern": "**/piper_secretscan.sarif", "type": "secretscan"},
+							{"filePattern": "**/piper_secretscan_findings.json", "type": "secretscan"},
+						},
+					},
+				},
+			},
+		},
+	}
+	return theMetaData
+}
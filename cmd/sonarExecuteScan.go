@@ -14,6 +14,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/SAP/jenkins-library/pkg/command"
+	piperGithub "github.com/SAP/jenkins-library/pkg/github"
 	piperhttp "github.com/SAP/jenkins-library/pkg/http"
 	keytool "github.com/SAP/jenkins-library/pkg/java"
 	"github.com/SAP/jenkins-library/pkg/log"
@@ -181,6 +182,9 @@ func runSonar(config sonarExecuteScanOptions, client piperhttp.Downloader, runne
 	runner.SetEnv(sonar.environment)
 	err := runner.RunExecutable(sonar.binary, sonar.options...)
 	if err != nil {
+		if config.WaitForQualityGate && len(config.Token) > 0 {
+			logFailedQualityGateConditions(config, apiClient)
+		}
 		return err
 	}
 
@@ -202,6 +206,9 @@ func runSonar(config sonarExecuteScanOptions, client piperhttp.Downloader, runne
 			Mandatory: false,
 		},
 	}
+	if config.ConvertToSarif {
+		reports = append(reports, piperutils.Path{Target: "sonarscan.sarif", Mandatory: false})
+	}
 	// write links JSON
 	links := []piperutils.Path{
 		{
@@ -274,6 +281,22 @@ func runSonar(config sonarExecuteScanOptions, client piperhttp.Downloader, runne
 		reportData.LinesOfCode = loc
 	}
 
+	qualityGateService := SonarUtils.NewQualityGateService(taskReport.ServerURL, config.Token, taskReport.ProjectKey, config.Organization, config.BranchName, config.ChangeID, apiClient)
+	qualityGate, err := qualityGateService.GetStatus(taskService.AnalysisID)
+	if err != nil {
+		log.Entry().Warnf("failed to retrieve sonar quality gate status: %v", err)
+	} else {
+		reportData.QualityGate = qualityGate
+		logQualityGateConditions(qualityGate)
+	}
+
+	newIssues, err := issueService.GetNewIssues()
+	if err != nil {
+		log.Entry().Warnf("failed to retrieve new sonar issues: %v", err)
+	} else {
+		reportData.NewIssues = newIssues
+	}
+
 	log.Entry().Debugf("Influx values: %v", influx.sonarqube_data.fields)
 
 	err = SonarUtils.WriteReport(reportData, sonar.workingDir, ioutil.WriteFile)
@@ -281,9 +304,71 @@ func runSonar(config sonarExecuteScanOptions, client piperhttp.Downloader, runne
 	if err != nil {
 		return err
 	}
+
+	if config.ConvertToSarif {
+		if err := SonarUtils.WriteSarif(SonarUtils.ConvertToSarif(reportData.NewIssues, taskReport.DashboardURL), sonar.workingDir, ioutil.WriteFile); err != nil {
+			return err
+		}
+	}
+
+	if config.PullRequestComment && len(config.ChangeID) > 0 {
+		if err := commentPullRequest(config, reportData, taskReport.DashboardURL); err != nil {
+			log.Entry().WithError(err).Warn("failed to post the analysis summary on the pull-request")
+		}
+	}
 	return nil
 }
 
+// logFailedQualityGateConditions logs the failed conditions of the quality gate in case the scanner failed on the quality gate.
+func logFailedQualityGateConditions(config sonarExecuteScanOptions, apiClient SonarUtils.Sender) {
+	taskReport, err := SonarUtils.ReadTaskReport(sonar.workingDir)
+	if err != nil {
+		return
+	}
+	qualityGateService := SonarUtils.NewQualityGateService(taskReport.ServerURL, config.Token, taskReport.ProjectKey, config.Organization, config.BranchName, config.ChangeID, apiClient)
+	qualityGate, err := qualityGateService.GetStatus("")
+	if err != nil {
+		log.Entry().Debugf("failed to retrieve sonar quality gate status: %v", err)
+		return
+	}
+	logQualityGateConditions(qualityGate)
+}
+
+func logQualityGateConditions(qualityGate *SonarUtils.QualityGateStatus) {
+	if qualityGate.Passed() {
+		log.Entry().Infof("Quality gate status: %v", qualityGate.Status)
+		return
+	}
+	log.Entry().Warnf("Quality gate status: %v", qualityGate.Status)
+	for _, condition := range qualityGate.FailedConditions() {
+		log.Entry().Warnf("Quality gate condition failed: %v is %v (threshold: %v %v)", condition.Metric, condition.Actual, condition.Comparator, condition.Threshold)
+	}
+}
+
+func commentPullRequest(config sonarExecuteScanOptions, reportData SonarUtils.ReportData, dashboardURL string) error {
+	if !strings.EqualFold(config.PullRequestProvider, "github") {
+		log.Entry().Warnf("Pull-Request comments are not supported for provider '%v'", config.PullRequestProvider)
+		return nil
+	}
+	if len(config.GithubToken) == 0 {
+		return errors.New("no githubToken provided")
+	}
+	number, err := strconv.Atoi(config.ChangeID)
+	if err != nil {
+		return errors.Wrapf(err, "invalid pull-request id '%v'", config.ChangeID)
+	}
+	_, err = piperGithub.CreateOrUpdatePullRequestComment(&piperGithub.PullRequestCommentOptions{
+		APIURL:     config.GithubAPIURL,
+		Token:      config.GithubToken,
+		Owner:      config.Owner,
+		Repository: config.Repository,
+		Number:     number,
+		Marker:     "piper-sonarExecuteScan",
+		Body:       SonarUtils.CreatePullRequestSummary(reportData, dashboardURL),
+	})
+	return err
+}
+
 // isInOptions returns true, if the given property is already provided in config.Options.
 func isInOptions(config sonarExecuteScanOptions, property string) bool {
 	property = strings.TrimSuffix(property, "=")
@@ -336,17 +421,22 @@ func handlePullRequest(config sonarExecuteScanOptions) error {
 		} else {
 			// see https://sonarcloud.io/documentation/analysis/pull-request/
 			provider := strings.ToLower(config.PullRequestProvider)
-			if provider == "github" {
+			switch provider {
+			case "github":
 				if len(config.Owner) > 0 && len(config.Repository) > 0 {
 					sonar.addOption("sonar.pullrequest.github.repository=" + config.Owner + "/" + config.Repository)
 				}
-			} else {
+			case "bitbucket":
+				// the Bitbucket repository is bound in the SonarQube project settings
+			default:
 				return errors.New("Pull-Request provider '" + provider + "' is not supported!")
 			}
 			sonar.addOption("sonar.pullrequest.key=" + config.ChangeID)
 			sonar.addOption("sonar.pullrequest.base=" + config.ChangeTarget)
 			sonar.addOption("sonar.pullrequest.branch=" + config.ChangeBranch)
-			sonar.addOption("sonar.pullrequest.provider=" + provider)
+			if provider == "github" {
+				sonar.addOption("sonar.pullrequest.provider=" + provider)
+			}
 		}
 	} else if len(config.BranchName) > 0 {
 		sonar.addOption("sonar.branch.name=" + config.BranchName)
@@ -152,6 +152,7 @@ func TestRunSonar(t *testing.T) {
 	httpmock.RegisterResponder(http.MethodGet, sonarServerURL+"/api/"+SonarUtils.EndpointCeTask+"", httpmock.NewStringResponder(http.StatusOK, `{ "task": { "componentId": "AXERR2JBbm9IiM5TEST", "status": "SUCCESS" }}`))
 	httpmock.RegisterResponder(http.MethodGet, sonarServerURL+"/api/"+SonarUtils.EndpointIssuesSearch+"", httpmock.NewStringResponder(http.StatusOK, `{ "total": 0 }`))
 	httpmock.RegisterResponder(http.MethodGet, sonarServerURL+"/api/"+SonarUtils.EndpointMeasuresComponent+"", httpmock.NewStringResponder(http.StatusOK, measuresComponentResponse))
+	httpmock.RegisterResponder(http.MethodGet, sonarServerURL+"/api/"+SonarUtils.EndpointQualityGatesProjectStatus+"", httpmock.NewStringResponder(http.StatusOK, `{ "projectStatus": { "status": "ERROR", "conditions": [ { "status": "ERROR", "metricKey": "new_coverage", "comparator": "LT", "errorThreshold": "80", "actualValue": "42.0" } ] }}`))
 
 	t.Run("default", func(t *testing.T) {
 		// init
@@ -323,6 +324,35 @@ func TestRunSonar(t *testing.T) {
 		assert.Contains(t, sonar.options, "-Dsonar.coverage.exclusions=one,**/two,three**")
 		assert.Contains(t, sonar.options, "-Dsonar.verbose=true")
 	})
+	t.Run("with quality gate details and SARIF", func(t *testing.T) {
+		// init
+		tmpFolder := t.TempDir()
+		createTaskReportFile(t, tmpFolder)
+
+		sonar = sonarSettings{
+			workingDir:  tmpFolder,
+			binary:      "sonar-scanner",
+			environment: []string{},
+			options:     []string{},
+		}
+		options := sonarExecuteScanOptions{
+			Token:               "secret-ABC",
+			PullRequestProvider: "GitHub",
+			ConvertToSarif:      true,
+		}
+		fileUtilsExists = mockFileUtilsExists(true)
+		defer func() {
+			fileUtilsExists = piperutils.FileExists
+		}()
+		// test
+		err := runSonar(options, &mockDownloadClient, &mockRunner, apiClient, &mock.FilesMock{}, &sonarExecuteScanInflux{})
+		// assert
+		assert.NoError(t, err)
+		report, err := ioutil.ReadFile(filepath.Join(tmpFolder, "sonarscan.json"))
+		require.NoError(t, err)
+		assert.Contains(t, string(report), `"qualityGate":{"status":"ERROR","conditions":[{"metric":"new_coverage","comparator":"LT","threshold":"80","actual":"42.0","status":"ERROR"}]}`)
+		assert.FileExists(t, filepath.Join(tmpFolder, "sonarscan.sarif"))
+	})
 }
 
 func TestSonarHandlePullRequest(t *testing.T) {
@@ -368,6 +398,28 @@ func TestSonarHandlePullRequest(t *testing.T) {
 		assert.Error(t, err)
 		assert.Equal(t, "Pull-Request provider 'gerrit' is not supported!", err.Error())
 	})
+	t.Run("bitbucket", func(t *testing.T) {
+		// init
+		sonar = sonarSettings{
+			binary:      "sonar-scanner",
+			environment: []string{},
+			options:     []string{},
+		}
+		options := sonarExecuteScanOptions{
+			ChangeID:            "123",
+			PullRequestProvider: "Bitbucket",
+			ChangeBranch:        "feat/bogus",
+			ChangeTarget:        "master",
+		}
+		// test
+		err := handlePullRequest(options)
+		// assert
+		assert.NoError(t, err)
+		assert.Contains(t, sonar.options, "sonar.pullrequest.key=123")
+		assert.Contains(t, sonar.options, "sonar.pullrequest.base=master")
+		assert.Contains(t, sonar.options, "sonar.pullrequest.branch=feat/bogus")
+		assert.NotContains(t, sonar.options, "sonar.pullrequest.provider=bitbucket")
+	})
 	t.Run("legacy", func(t *testing.T) {
 		// init
 		sonar = sonarSettings{
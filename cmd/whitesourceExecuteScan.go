@@ -21,6 +21,7 @@ import (
 	"github.com/SAP/jenkins-library/pkg/npm"
 	"github.com/SAP/jenkins-library/pkg/piperutils"
 	"github.com/SAP/jenkins-library/pkg/reporting"
+	"github.com/SAP/jenkins-library/pkg/scancache"
 	"github.com/SAP/jenkins-library/pkg/telemetry"
 	"github.com/SAP/jenkins-library/pkg/toolrecord"
 	"github.com/SAP/jenkins-library/pkg/versioning"
@@ -48,6 +49,7 @@ type whitesource interface {
 	GetProjectAlertsByType(projectToken, alertType string) ([]ws.Alert, error)
 	GetProjectLibraryLocations(projectToken string) ([]ws.Library, error)
 	GetProjectHierarchy(projectToken string, includeInHouse bool) ([]ws.Library, error)
+	UpdateInventory(agentURL, productName, productVersion string, projects []ws.AgentProjectInfo) error
 }
 
 type whitesourceUtils interface {
@@ -186,6 +188,7 @@ func runWhitesourceExecuteScan(ctx context.Context, config *ScanOptions, scan *w
 func runWhitesourceScan(ctx context.Context, config *ScanOptions, scan *ws.Scan, utils whitesourceUtils, sys whitesource, commonPipelineEnvironment *whitesourceExecuteScanCommonPipelineEnvironment, influx *whitesourceExecuteScanInflux) error {
 	// Download Docker image for container scan
 	// ToDo: move it to improve testability
+	imageArchive := ""
 	if config.BuildTool == "docker" {
 		saveImageOptions := containerSaveImageOptions{
 			ContainerImage:            config.ScanImage,
@@ -199,7 +202,8 @@ func runWhitesourceScan(ctx context.Context, config *ScanOptions, scan *ws.Scan,
 		dClientOptions := piperDocker.ClientOptions{ImageName: saveImageOptions.ContainerImage, RegistryURL: saveImageOptions.ContainerRegistryURL, LocalPath: "", ImageFormat: "legacy"}
 		dClient := &piperDocker.Client{}
 		dClient.SetOptions(dClientOptions)
-		if _, err := runContainerSaveImage(&saveImageOptions, &telemetry.CustomData{}, "./cache", "", dClient, utils); err != nil {
+		var err error
+		if imageArchive, err = runContainerSaveImage(&saveImageOptions, &telemetry.CustomData{}, "./cache", "", dClient, utils); err != nil {
 			if strings.Contains(fmt.Sprint(err), "no image found") {
 				log.SetErrorCategory(log.ErrorConfiguration)
 			}
@@ -208,16 +212,58 @@ func runWhitesourceScan(ctx context.Context, config *ScanOptions, scan *ws.Scan,
 
 	}
 
+	cache, closeCache := newScanCache(config.ScanCache, config.ScanCacheLocation, config.ScanCacheMaxAge, utils)
+	defer closeCache()
+	cacheKey := ""
+	if cache != nil {
+		var err error
+		if cacheKey, err = whitesourceScanCacheKey(config, imageArchive, utils); err != nil {
+			log.Entry().WithError(err).Warn("scan cache is disabled")
+			cache = nil
+		}
+	}
+	if entry := cache.Lookup(cacheKey); entry != nil {
+		if restored, err := restoreScanResult(entry, utils); restored {
+			restoreWhitesourceOutputs(entry, commonPipelineEnvironment, influx)
+			return err
+		}
+	}
+
+	reports, err := executeWhitesourceScan(ctx, config, scan, utils, sys, commonPipelineEnvironment, influx, imageArchive)
+	// results with policy violations are complete, while failures of the scan or the reporting are not worth caching
+	cacheable := err == nil || isWhitesourceViolation(err)
+	// the entry of the scan cache contains the list of reports, hence it needs to be written first
+	if persistErr := piperutils.PersistReportsAndLinks("whitesourceExecuteScan", "", utils, reports, nil); persistErr != nil {
+		log.Entry().WithError(persistErr).Warn("failed to persist reports")
+		cacheable = false
+	}
+	if cacheable {
+		entry := scancache.NewEntry("whitesourceExecuteScan", cacheKey)
+		influxErr := entry.SetOutput("influx", getWhitesourceInfluxData(influx))
+		projectsErr := entry.SetOutput("whitesourceProjectNames", commonPipelineEnvironment.custom.whitesourceProjectNames)
+		if influxErr == nil && projectsErr == nil {
+			saveScanResult(cache, entry, err, utils,
+				filepath.Join(ws.ReportsDirectory, "*"),
+				"toolrun_whitesource_*.json",
+				filepath.Join(reporting.StepReportDirectory, "whitesourceExecuteScan_*.json"),
+				"whitesourceExecuteScan_reports.json",
+				"whitesourceExecuteScan_links.json")
+		}
+	}
+	return err
+}
+
+func executeWhitesourceScan(ctx context.Context, config *ScanOptions, scan *ws.Scan, utils whitesourceUtils, sys whitesource, commonPipelineEnvironment *whitesourceExecuteScanCommonPipelineEnvironment, influx *whitesourceExecuteScanInflux, imageArchive string) ([]piperutils.Path, error) {
 	// Start the scan
-	if err := executeScan(config, scan, utils); err != nil {
-		return errors.Wrapf(err, "failed to execute Scan")
+	if err := executeScan(config, scan, utils, sys, imageArchive); err != nil {
+		return nil, errors.Wrapf(err, "failed to execute Scan")
 	}
 
 	// ToDo: Check this:
 	// Why is this required at all, resolveProjectIdentifiers() is already called before the scan in runWhitesourceExecuteScan()
 	// Could perhaps use scan.updateProjects(sys) directly... have not investigated what could break
 	if err := resolveProjectIdentifiers(config, scan, utils, sys); err != nil {
-		return errors.Wrapf(err, "failed to resolve project identifiers")
+		return nil, errors.Wrapf(err, "failed to resolve project identifiers")
 	}
 
 	log.Entry().Info("-----------------------------------------------------")
@@ -229,12 +275,11 @@ func runWhitesourceScan(ctx context.Context, config *ScanOptions, scan *ws.Scan,
 	log.Entry().Info("-----------------------------------------------------")
 
 	paths, err := checkAndReportScanResults(ctx, config, scan, utils, sys, influx)
-	piperutils.PersistReportsAndLinks("whitesourceExecuteScan", "", utils, paths, nil)
 	persistScannedProjects(config, scan, commonPipelineEnvironment)
 	if err != nil {
-		return errors.Wrapf(err, "failed to check and report scan results")
+		return paths, errors.Wrapf(err, "failed to check and report scan results")
 	}
-	return nil
+	return paths, nil
 }
 
 func checkAndReportScanResults(ctx context.Context, config *ScanOptions, scan *ws.Scan, utils whitesourceUtils, sys whitesource, influx *whitesourceExecuteScanInflux) ([]piperutils.Path, error) {
@@ -258,15 +303,15 @@ func checkAndReportScanResults(ctx context.Context, config *ScanOptions, scan *w
 		}
 	}
 
-	checkErrors := []string{}
+	checkErrors := []error{}
 
 	rPath, err := checkPolicyViolations(ctx, config, scan, sys, utils, reportPaths, influx)
 
 	if err != nil {
-		if !config.FailOnSevereVulnerabilities && log.GetErrorCategory() == log.ErrorCompliance {
+		if !config.FailOnSevereVulnerabilities && isWhitesourceViolation(err) {
 			log.Entry().Infof("policy violation(s) found - step will only create data but not fail due to setting failOnSevereVulnerabilities: false")
 		} else {
-			checkErrors = append(checkErrors, fmt.Sprint(err))
+			checkErrors = append(checkErrors, err)
 		}
 	}
 	reportPaths = append(reportPaths, rPath)
@@ -275,10 +320,10 @@ func checkAndReportScanResults(ctx context.Context, config *ScanOptions, scan *w
 		rPaths, err := checkSecurityViolations(ctx, config, scan, sys, utils, influx)
 		reportPaths = append(reportPaths, rPaths...)
 		if err != nil {
-			if !config.FailOnSevereVulnerabilities && log.GetErrorCategory() == log.ErrorCompliance {
+			if !config.FailOnSevereVulnerabilities && isWhitesourceViolation(err) {
 				log.Entry().Infof("policy violation(s) found - step will only create data but not fail due to setting failOnSevereVulnerabilities: false")
 			} else {
-				checkErrors = append(checkErrors, fmt.Sprint(err))
+				checkErrors = append(checkErrors, err)
 			}
 		}
 	}
@@ -294,11 +339,37 @@ func checkAndReportScanResults(ctx context.Context, config *ScanOptions, scan *w
 	}
 
 	if len(checkErrors) > 0 {
-		return reportPaths, fmt.Errorf(strings.Join(checkErrors, ": "))
+		return reportPaths, combineWhitesourceErrors(checkErrors)
 	}
 	return reportPaths, nil
 }
 
+// whitesourceViolationError is returned if policy violations or severe vulnerabilities are found,
+// the results of the scan are complete nevertheless
+type whitesourceViolationError struct {
+	error
+}
+
+func isWhitesourceViolation(err error) bool {
+	var violation whitesourceViolationError
+	return errors.As(err, &violation)
+}
+
+// combineWhitesourceErrors joins the messages of the errors, the result is a violation if all errors are violations
+func combineWhitesourceErrors(errs []error) error {
+	messages := []string{}
+	violations := true
+	for _, err := range errs {
+		messages = append(messages, fmt.Sprint(err))
+		violations = violations && isWhitesourceViolation(err)
+	}
+	err := errors.New(strings.Join(messages, ": "))
+	if violations {
+		return whitesourceViolationError{err}
+	}
+	return err
+}
+
 func createWhiteSourceProduct(config *ScanOptions, sys whitesource) (string, error) {
 	log.Entry().Infof("Attempting to create new WhiteSource product for '%s'..", config.ProductName)
 	productToken, err := sys.CreateProduct(config.ProductName)
@@ -483,10 +554,18 @@ func wsScanOptions(config *ScanOptions) *ws.ScanOptions {
 }
 
 // Unified Agent is the only supported option by WhiteSource going forward:
-// The Unified Agent will be used to perform the scan.
-func executeScan(config *ScanOptions, scan *ws.Scan, utils whitesourceUtils) error {
+// The Unified Agent will be used to perform the scan unless the native scan mode is configured.
+func executeScan(config *ScanOptions, scan *ws.Scan, utils whitesourceUtils, sys whitesource, imageArchive string) error {
 	options := wsScanOptions(config)
 
+	if config.ScanMode == "native" {
+		options.ImageArchive = imageArchive
+		if err := scan.ExecuteNativeScan(options, utils, sys); err != nil {
+			return errors.Wrapf(err, "failed to execute native scan")
+		}
+		return nil
+	}
+
 	// Execute scan with Unified Agent jar file
 	if err := scan.ExecuteUAScan(options, utils); err != nil {
 		return errors.Wrapf(err, "failed to execute Unified Agent scan")
@@ -580,7 +659,7 @@ func checkPolicyViolations(ctx context.Context, config *ScanOptions, scan *ws.Sc
 				return policyReport, fmt.Errorf("failed to upload reports to GitHub for %v policy violations: %w", policyViolationCount, err)
 			}
 		}
-		return policyReport, fmt.Errorf("%v policy violation(s) found", policyViolationCount)
+		return policyReport, whitesourceViolationError{fmt.Errorf("%v policy violation(s) found", policyViolationCount)}
 	}
 
 	return policyReport, nil
@@ -600,7 +679,7 @@ func checkSecurityViolations(ctx context.Context, config *ScanOptions, scan *ws.
 	assessments := readAssessmentsFromFile(config.AssessmentFile, utils)
 
 	vulnerabilitiesCount := 0
-	var allOccurredErrors []string
+	var allOccurredErrors []error
 	allAlerts := []ws.Alert{}
 	allAssessedAlerts := []ws.Alert{}
 	allLibraries := []ws.Library{}
@@ -662,7 +741,7 @@ func checkSecurityViolations(ctx context.Context, config *ScanOptions, scan *ws.
 		if vulnerabilitiesCount > 0 {
 			log.SetErrorCategory(log.ErrorCompliance)
 		}
-		return reportPaths, fmt.Errorf(strings.Join(allOccurredErrors, ": "))
+		return reportPaths, combineWhitesourceErrors(allOccurredErrors)
 	}
 
 	return reportPaths, nil
@@ -680,18 +759,18 @@ func collectVulnsAndLibsForProject(
 	[]ws.Alert,
 	[]ws.Alert,
 	[]ws.Library,
-	[]string,
+	[]error,
 ) {
-	var errorsOccurred []string
+	var errorsOccurred []error
 	vulCount, alerts, assessedAlerts, err := checkProjectSecurityViolations(config, cvssSeverityLimit, project, sys, assessments, influx)
 	if err != nil {
-		errorsOccurred = append(errorsOccurred, fmt.Sprint(err))
+		errorsOccurred = append(errorsOccurred, err)
 	}
 
 	// collect all libraries detected in all related projects and errors
 	libraries, err := sys.GetProjectHierarchy(project.Token, true)
 	if err != nil {
-		errorsOccurred = append(errorsOccurred, fmt.Sprint(err))
+		errorsOccurred = append(errorsOccurred, err)
 	}
 	log.Entry().Debugf("Collected %v libraries for project %v", len(libraries), project.Name)
 
@@ -708,8 +787,8 @@ func reportGitHubIssuesAndCreateReports(
 	allAssessedAlerts []ws.Alert,
 	cvssSeverityLimit float64,
 	vulnerabilitiesCount int,
-) ([]piperutils.Path, []string) {
-	errorsOccured := make([]string, 0)
+) ([]piperutils.Path, []error) {
+	errorsOccured := make([]error, 0)
 	reportPaths := make([]piperutils.Path, 0)
 
 	if config.CreateResultIssue && vulnerabilitiesCount > 0 && len(config.GithubToken) > 0 && len(config.GithubAPIURL) > 0 && len(config.Owner) > 0 && len(config.Repository) > 0 {
@@ -725,14 +804,14 @@ func reportGitHubIssuesAndCreateReports(
 		}
 
 		if err := gh.UploadMultipleReports(ctx, &issueDetails); err != nil {
-			errorsOccured = append(errorsOccured, fmt.Sprint(err))
+			errorsOccured = append(errorsOccured, err)
 		}
 	}
 
 	scanReport := ws.CreateCustomVulnerabilityReport(config.ProductName, scan, &allAlerts, cvssSeverityLimit)
 	paths, err := ws.WriteCustomVulnerabilityReports(config.ProductName, scan, scanReport, utils)
 	if err != nil {
-		errorsOccured = append(errorsOccured, fmt.Sprint(err))
+		errorsOccured = append(errorsOccured, err)
 	}
 
 	reportPaths = append(reportPaths, paths...)
@@ -740,19 +819,19 @@ func reportGitHubIssuesAndCreateReports(
 	sarif := ws.CreateSarifResultFile(scan, &allAlerts)
 	paths, err = ws.WriteSarifFile(sarif, utils)
 	if err != nil {
-		errorsOccured = append(errorsOccured, fmt.Sprint(err))
+		errorsOccured = append(errorsOccured, err)
 	}
 
 	reportPaths = append(reportPaths, paths...)
 
 	sbom, err := ws.CreateCycloneSBOM(scan, &allLibraries, &allAlerts, &allAssessedAlerts)
 	if err != nil {
-		errorsOccured = append(errorsOccured, fmt.Sprint(err))
+		errorsOccured = append(errorsOccured, err)
 	}
 
 	paths, err = ws.WriteCycloneSBOM(sbom, utils)
 	if err != nil {
-		errorsOccured = append(errorsOccured, fmt.Sprint(err))
+		errorsOccured = append(errorsOccured, err)
 	}
 
 	reportPaths = append(reportPaths, paths...)
@@ -824,7 +903,7 @@ func checkProjectSecurityViolations(config *ScanOptions, cvssSeverityLimit float
 	if severeVulnerabilities > 0 {
 		if config.FailOnSevereVulnerabilities {
 			log.SetErrorCategory(log.ErrorCompliance)
-			return severeVulnerabilities, alerts, assessedAlerts, fmt.Errorf("%v Open Source Software Security vulnerabilities with CVSS score greater or equal to %.1f detected in project %s", severeVulnerabilities, cvssSeverityLimit, project.Name)
+			return severeVulnerabilities, alerts, assessedAlerts, whitesourceViolationError{fmt.Errorf("%v Open Source Software Security vulnerabilities with CVSS score greater or equal to %.1f detected in project %s", severeVulnerabilities, cvssSeverityLimit, project.Name)}
 		}
 		log.Entry().Infof("%v Open Source Software Security vulnerabilities with CVSS score greater or equal to %.1f detected in project %s", severeVulnerabilities, cvssSeverityLimit, project.Name)
 		log.Entry().Info("Step will only create data but not fail due to setting failOnSevereVulnerabilities: false")
@@ -1005,6 +1084,93 @@ func persistScannedProjects(config *ScanOptions, scan *ws.Scan, commonPipelineEn
 	commonPipelineEnvironment.custom.whitesourceProjectNames = projectNames
 }
 
+// whitesourceScanCacheKey identifies a scan by the bill of materials of the project, respectively the image,
+// and the configuration which influences the result
+func whitesourceScanCacheKey(config *ScanOptions, imageArchive string, utils whitesourceUtils) (string, error) {
+	var contentHash string
+	var err error
+	if len(imageArchive) > 0 {
+		contentHash, err = scancache.HashFiles(utils, []string{imageArchive})
+	} else {
+		dir := "."
+		if len(config.ScanPath) > 0 {
+			dir = config.ScanPath
+		}
+		if err = scancache.CheckPinnedDependencies(utils, dir); err != nil {
+			return "", err
+		}
+		contentHash, err = scancache.HashDependencyDescriptors(utils, dir)
+		if err == nil && whitesourceFileSystemScan(config.BuildTool) {
+			// the file system scan of the Unified Agent fingerprints the source files, not only the declared dependencies
+			var sourceHash string
+			sourceHash, err = scancache.HashSourceTree(utils, dir)
+			contentHash += sourceHash
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+	if len(config.AssessmentFile) > 0 {
+		if exists, _ := utils.FileExists(config.AssessmentFile); exists {
+			assessmentHash, err := utils.SHA256(config.AssessmentFile)
+			if err != nil {
+				return "", errors.Wrapf(err, "failed to calculate hash of '%v'", config.AssessmentFile)
+			}
+			contentHash += assessmentHash
+		}
+	}
+	return scancache.Key("whitesourceExecuteScan", contentHash, struct {
+		ServiceURL                  string   `json:"serviceUrl"`
+		ProductName                 string   `json:"productName"`
+		ProjectName                 string   `json:"projectName"`
+		Version                     string   `json:"version"`
+		BuildTool                   string   `json:"buildTool"`
+		ScanMode                    string   `json:"scanMode"`
+		Includes                    []string `json:"includes"`
+		Excludes                    []string `json:"excludes"`
+		BuildDescriptorExcludeList  []string `json:"buildDescriptorExcludeList"`
+		CvssSeverityLimit           string   `json:"cvssSeverityLimit"`
+		Reporting                   bool     `json:"reporting"`
+		VulnerabilityReportFormat   string   `json:"vulnerabilityReportFormat"`
+		SecurityVulnerabilities     bool     `json:"securityVulnerabilities"`
+		LicensingVulnerabilities    bool     `json:"licensingVulnerabilities"`
+		FailOnSevereVulnerabilities bool     `json:"failOnSevereVulnerabilities"`
+	}{config.ServiceURL, config.ProductName, config.ProjectName, config.Version, config.BuildTool, config.ScanMode,
+		config.Includes, config.Excludes, config.BuildDescriptorExcludeList, config.CvssSeverityLimit, config.Reporting,
+		config.VulnerabilityReportFormat, config.SecurityVulnerabilities, config.LicensingVulnerabilities, config.FailOnSevereVulnerabilities})
+}
+
+// whitesourceFileSystemScan checks whether the Unified Agent scans the files of the project for the build tool in addition
+// to resolving its dependencies, see the build tool defaults of the Unified Agent configuration
+func whitesourceFileSystemScan(buildTool string) bool {
+	return !piperutils.ContainsString([]string{"dub2", "golang", "gradle", "maven", "npm", "pip", "sbt", "yarn"}, buildTool)
+}
+
+// restoreWhitesourceOutputs sets the influx data and the common pipeline environment of a scan restored from the scan cache
+func restoreWhitesourceOutputs(entry *scancache.Entry, commonPipelineEnvironment *whitesourceExecuteScanCommonPipelineEnvironment, influx *whitesourceExecuteScanInflux) {
+	result := map[string]int{}
+	if _, err := entry.Output("influx", &result); err != nil {
+		log.Entry().WithError(err).Warn("failed to restore influx data from scan cache")
+	} else {
+		influx.whitesource_data.fields.vulnerabilities = result["vulnerabilities"]
+		influx.whitesource_data.fields.major_vulnerabilities = result["major_vulnerabilities"]
+		influx.whitesource_data.fields.minor_vulnerabilities = result["minor_vulnerabilities"]
+		influx.whitesource_data.fields.policy_violations = result["policy_violations"]
+	}
+	if _, err := entry.Output("whitesourceProjectNames", &commonPipelineEnvironment.custom.whitesourceProjectNames); err != nil {
+		log.Entry().WithError(err).Warn("failed to restore scanned projects from scan cache")
+	}
+}
+
+func getWhitesourceInfluxData(influx *whitesourceExecuteScanInflux) map[string]int {
+	return map[string]int{
+		"vulnerabilities":       influx.whitesource_data.fields.vulnerabilities,
+		"major_vulnerabilities": influx.whitesource_data.fields.major_vulnerabilities,
+		"minor_vulnerabilities": influx.whitesource_data.fields.minor_vulnerabilities,
+		"policy_violations":     influx.whitesource_data.fields.policy_violations,
+	}
+}
+
 // create toolrecord file for whitesource
 func createToolRecordWhitesource(utils whitesourceUtils, workspace string, config *whitesourceExecuteScanOptions, scan *ws.Scan) (string, error) {
 	record := toolrecord.New(utils, workspace, "whitesource", config.ServiceURL)
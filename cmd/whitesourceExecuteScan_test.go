@@ -13,6 +13,7 @@ import (
 	"github.com/SAP/jenkins-library/pkg/reporting"
 	"github.com/SAP/jenkins-library/pkg/versioning"
 	ws "github.com/SAP/jenkins-library/pkg/whitesource"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/google/go-github/v45/github"
@@ -141,6 +142,39 @@ func TestRunWhitesourceExecuteScan(t *testing.T) {
 		assert.True(t, utilsMock.HasWrittenFile(filepath.Join(ws.ReportsDirectory, "mock-project - 1-vulnerability-report.pdf")))
 		assert.True(t, utilsMock.HasWrittenFile(filepath.Join(ws.ReportsDirectory, "mock-project - 1-vulnerability-report.pdf")))
 	})
+	t.Run("uploads natively resolved dependencies", func(t *testing.T) {
+		ctx := context.Background()
+		// init
+		config := ScanOptions{
+			BuildTool:       "golang",
+			ScanMode:        "native",
+			VersioningModel: "major",
+			AgentURL:        "https://whitesource.com/agent",
+			ProductName:     "mock-product",
+			ProjectToken:    "mock-project-token",
+		}
+		utilsMock := newWhitesourceUtilsMock()
+		utilsMock.StdoutReturn = map[string]string{
+			"go list -m -json all": `{"Path": "github.com/mock/app", "Main": true}
+{"Path": "golang.org/x/text", "Version": "v0.3.7"}`,
+			"go mod graph": "github.com/mock/app golang.org/x/text@v0.3.7",
+		}
+		systemMock := ws.NewSystemMock(time.Now().Format(ws.DateTimeLayout))
+		systemMock.Alerts = []ws.Alert{}
+		scan := newWhitesourceScan(&config)
+		cpe := whitesourceExecuteScanCommonPipelineEnvironment{}
+		influx := whitesourceExecuteScanInflux{}
+		// test
+		err := runWhitesourceExecuteScan(ctx, &config, scan, utilsMock, systemMock, &cpe, &influx)
+		// assert
+		assert.NoError(t, err)
+		assert.Empty(t, utilsMock.DownloadedFiles)
+		if assert.Len(t, systemMock.Inventory, 1) {
+			assert.Equal(t, ws.AgentCoordinates{ArtifactID: "mock-project", Version: "1"}, systemMock.Inventory[0].Coordinates)
+			assert.Equal(t, []ws.DependencyInfo{{ArtifactID: "golang.org/x/text", Version: "v0.3.7", DependencyType: ws.DependencyTypeGo}}, systemMock.Inventory[0].Dependencies)
+		}
+		assert.Equal(t, []string{"mock-project - 1"}, cpe.custom.whitesourceProjectNames)
+	})
 }
 
 func TestCheckAndReportScanResults(t *testing.T) {
@@ -181,6 +215,7 @@ func TestCheckAndReportScanResults(t *testing.T) {
 		_, err := checkAndReportScanResults(ctx, config, scan, utils, system, &influx)
 		// assert
 		assert.EqualError(t, err, "failed to parse parameter cvssSeverityLimit (invalid) as floating point number: strconv.ParseFloat: parsing \"invalid\": invalid syntax")
+		assert.False(t, isWhitesourceViolation(err))
 	})
 	t.Run("check vulnerabilities - limit not hit", func(t *testing.T) {
 		ctx := context.Background()
@@ -221,6 +256,24 @@ func TestCheckAndReportScanResults(t *testing.T) {
 		_, err := checkAndReportScanResults(ctx, config, scan, utils, system, &influx)
 		// assert
 		assert.EqualError(t, err, "1 Open Source Software Security vulnerabilities with CVSS score greater or equal to 4.0 detected in project mock-project - 1")
+		assert.True(t, isWhitesourceViolation(err))
+	})
+}
+
+func TestCombineWhitesourceErrors(t *testing.T) {
+	t.Parallel()
+	violation := whitesourceViolationError{fmt.Errorf("2 policy violation(s) found")}
+
+	t.Run("violations only", func(t *testing.T) {
+		err := combineWhitesourceErrors([]error{violation, errors.Wrap(violation, "wrapped")})
+		assert.EqualError(t, err, "2 policy violation(s) found: wrapped: 2 policy violation(s) found")
+		assert.True(t, isWhitesourceViolation(err))
+		assert.True(t, isWhitesourceViolation(errors.Wrap(err, "failed to execute WhiteSource scan")))
+	})
+	t.Run("violations and failures", func(t *testing.T) {
+		err := combineWhitesourceErrors([]error{violation, fmt.Errorf("failed to write report")})
+		assert.EqualError(t, err, "2 policy violation(s) found: failed to write report")
+		assert.False(t, isWhitesourceViolation(err))
 	})
 }
 
@@ -490,6 +543,7 @@ func TestCheckPolicyViolations(t *testing.T) {
 
 		path, err := checkPolicyViolations(ctx, &config, scan, systemMock, utilsMock, reportPaths, &influx)
 		assert.Contains(t, fmt.Sprint(err), "2 policy violation(s) found")
+		assert.True(t, isWhitesourceViolation(err))
 
 		fileContent, _ := utilsMock.FileRead(path.Target)
 		content := string(fileContent)
@@ -810,3 +864,36 @@ func TestPersistScannedProjects(t *testing.T) {
 		assert.Equal(t, []string{"project - 1"}, cpe.custom.whitesourceProjectNames)
 	})
 }
+
+func TestWhitesourceScanCacheKey(t *testing.T) {
+	t.Parallel()
+	t.Run("file system scan includes the sources", func(t *testing.T) {
+		t.Parallel()
+		utils := newWhitesourceUtilsMock()
+		utils.AddFile("go.mod", []byte("module example.org/app"))
+		utils.AddFile("main.go", []byte("package main"))
+		config := &ScanOptions{ProjectName: "project", Version: "1", BuildTool: "golang"}
+
+		key, err := whitesourceScanCacheKey(config, "", utils)
+		assert.NoError(t, err)
+		utils.AddFile("util.go", []byte("package main"))
+		unchanged, _ := whitesourceScanCacheKey(config, "", utils)
+		assert.Equal(t, key, unchanged)
+
+		config.BuildTool = "dub"
+		key, err = whitesourceScanCacheKey(config, "", utils)
+		assert.NoError(t, err)
+		utils.AddFile("other.go", []byte("package main"))
+		changed, _ := whitesourceScanCacheKey(config, "", utils)
+		assert.NotEqual(t, key, changed)
+	})
+
+	t.Run("unpinned dependencies", func(t *testing.T) {
+		t.Parallel()
+		utils := newWhitesourceUtilsMock()
+		utils.AddFile("package.json", []byte("{}"))
+
+		_, err := whitesourceScanCacheKey(&ScanOptions{BuildTool: "npm"}, "", utils)
+		assert.Contains(t, fmt.Sprint(err), "'package.json' does not pin its dependencies")
+	})
+}
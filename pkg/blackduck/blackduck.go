@@ -24,6 +24,7 @@ const (
 	HEADER_PROJECT_DETAILS_V4 = "application/vnd.blackducksoftware.project-detail-4+json"
 	HEADER_USER_V4            = "application/vnd.blackducksoftware.user-4+json"
 	HEADER_BOM_V6             = "application/vnd.blackducksoftware.bill-of-materials-6+json"
+	HEADER_COMPONENT_V5       = "application/vnd.blackducksoftware.component-detail-5+json"
 )
 
 // Projects defines the response to a BlackDuck project API request
@@ -224,6 +225,21 @@ type SeverityLevels struct {
 	Value int    `json:"value,omitempty"`
 }
 
+// UpgradeGuidance defines the response to a BlackDuck upgrade guidance API request
+type UpgradeGuidance struct {
+	ComponentName string                  `json:"componentName,omitempty"`
+	VersionName   string                  `json:"versionName,omitempty"`
+	ShortTerm     *UpgradeGuidanceVersion `json:"shortTerm,omitempty"`
+	LongTerm      *UpgradeGuidanceVersion `json:"longTerm,omitempty"`
+}
+
+// UpgradeGuidanceVersion describes a component version recommended as upgrade target
+type UpgradeGuidanceVersion struct {
+	Version           string         `json:"version,omitempty"`
+	VersionName       string         `json:"versionName,omitempty"`
+	VulnerabilityRisk map[string]int `json:"vulnerabilityRisk,omitempty"`
+}
+
 // Client defines a BlackDuck client
 type Client struct {
 	BearerToken                 string `json:"bearerToken,omitempty"`
@@ -479,6 +495,31 @@ func (b *Client) GetPolicyStatus(projectName, versionName string) (*PolicyStatus
 	return &policyStatus, nil
 }
 
+// GetUpgradeGuidance returns the short term and long term upgrade recommendation for a component version.
+// The component version is identified by its link, e.g. /api/components/{componentId}/versions/{versionId}
+func (b *Client) GetUpgradeGuidance(componentVersionLink string) (*UpgradeGuidance, error) {
+	versionPath := urlPath(componentVersionLink)
+	if !strings.Contains(versionPath, "/versions/") {
+		return nil, errors.Errorf("'%v' is not a link to a component version", componentVersionLink)
+	}
+
+	headers := http.Header{}
+	headers.Add("Accept", HEADER_COMPONENT_V5)
+
+	respBody, err := b.sendRequest("GET", path.Join(versionPath, "upgrade-guidance"), map[string]string{}, nil, headers)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get upgrade guidance for component version '%v'", componentVersionLink)
+	}
+
+	upgradeGuidance := UpgradeGuidance{}
+	err = json.Unmarshal(respBody, &upgradeGuidance)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to retrieve upgrade guidance for component version '%v'", componentVersionLink)
+	}
+
+	return &upgradeGuidance, nil
+}
+
 func (b *Client) authenticate() error {
 	headers := http.Header{}
 	headers.Add("Authorization", fmt.Sprintf("token %v", b.token))
@@ -748,3 +748,47 @@ func TestComponentToPackageUrl(t *testing.T) {
 		})
 	}
 }
+
+func TestGetUpgradeGuidance(t *testing.T) {
+	versionLink := "https://my.blackduck.system/api/components/0c5b5ba5-9cd7-4a8f-9c9a-1c8a8c2fbc0b/versions/b3a4d8a6-4a0f-4e7e-8c4d-2a3e3b3c0a1f"
+
+	t.Run("success", func(t *testing.T) {
+		myTestClient := httpMockClient{
+			responseBodyForURL: map[string]string{
+				"https://my.blackduck.system/api/tokens/authenticate": authContent,
+				versionLink + "/upgrade-guidance": `{
+					"componentName": "qs",
+					"versionName": "5.2.1",
+					"shortTerm": {"versionName": "6.0.4", "vulnerabilityRisk": {"critical": 0, "high": 0}},
+					"longTerm": {"versionName": "6.11.0", "vulnerabilityRisk": {"critical": 0, "high": 0}}
+				}`,
+			},
+			header: map[string]http.Header{},
+		}
+		bdClient := NewClient("token", "https://my.blackduck.system", &myTestClient)
+		guidance, err := bdClient.GetUpgradeGuidance(versionLink)
+		assert.NoError(t, err)
+		assert.Equal(t, "qs", guidance.ComponentName)
+		assert.Equal(t, "6.0.4", guidance.ShortTerm.VersionName)
+		assert.Equal(t, "6.11.0", guidance.LongTerm.VersionName)
+	})
+
+	t.Run("Failure - no component version link", func(t *testing.T) {
+		bdClient := NewClient("token", "https://my.blackduck.system", &httpMockClient{})
+		_, err := bdClient.GetUpgradeGuidance("https://my.blackduck.system/api/vulnerabilities/CVE-2017-1000048")
+		assert.EqualError(t, err, "'https://my.blackduck.system/api/vulnerabilities/CVE-2017-1000048' is not a link to a component version")
+	})
+
+	t.Run("Failure - unmarshalling", func(t *testing.T) {
+		myTestClient := httpMockClient{
+			responseBodyForURL: map[string]string{
+				"https://my.blackduck.system/api/tokens/authenticate": authContent,
+				versionLink + "/upgrade-guidance":                     "",
+			},
+			header: map[string]http.Header{},
+		}
+		bdClient := NewClient("token", "https://my.blackduck.system", &myTestClient)
+		_, err := bdClient.GetUpgradeGuidance(versionLink)
+		assert.Contains(t, fmt.Sprint(err), "failed to retrieve upgrade guidance for component version")
+	})
+}
@@ -0,0 +1,126 @@
+package buildcache
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// createArchive writes the content of the directory as gzip compressed tar archive into the file
+func createArchive(dir, file string) error {
+	target, err := os.Create(file)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create archive '%v'", file)
+	}
+	defer target.Close()
+	gzipWriter := gzip.NewWriter(target)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		name, err := filepath.Rel(dir, path)
+		if err != nil || name == "." {
+			return err
+		}
+		// only directories and regular files are cached, e.g. symbolic links are recreated by the build tools
+		if !info.IsDir() && !info.Mode().IsRegular() {
+			return nil
+		}
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(name)
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		source, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer source.Close()
+		_, err = io.Copy(tarWriter, source)
+		return err
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to archive directory '%v'", dir)
+	}
+	if err := tarWriter.Close(); err != nil {
+		return errors.Wrapf(err, "failed to write archive '%v'", file)
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return errors.Wrapf(err, "failed to write archive '%v'", file)
+	}
+	return nil
+}
+
+// extractArchive extracts the archive into the directory. Files which already exist are kept,
+// directories are created writable in order to allow the build tools to add files to them.
+func extractArchive(file, dir string) error {
+	source, err := os.Open(file)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open archive '%v'", file)
+	}
+	defer source.Close()
+	gzipReader, err := gzip.NewReader(source)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read archive '%v'", file)
+	}
+	tarReader := tar.NewReader(gzipReader)
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return errors.Wrapf(err, "failed to create directory '%v'", dir)
+	}
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to read archive '%v'", file)
+		}
+		target := filepath.Join(dir, filepath.FromSlash(header.Name))
+		if !strings.HasPrefix(target, filepath.Clean(dir)+string(filepath.Separator)) {
+			return errors.Errorf("archive '%v' contains invalid path '%v'", file, header.Name)
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0o755); err != nil {
+				return errors.Wrapf(err, "failed to create directory '%v'", target)
+			}
+		case tar.TypeReg:
+			if _, err := os.Stat(target); err == nil {
+				continue
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return errors.Wrapf(err, "failed to create directory '%v'", filepath.Dir(target))
+			}
+			if err := extractFile(tarReader, target, os.FileMode(header.Mode).Perm()); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func extractFile(source io.Reader, target string, mode os.FileMode) error {
+	file, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode|0o200)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create file '%v'", target)
+	}
+	defer file.Close()
+	if _, err := io.Copy(file, source); err != nil {
+		return errors.Wrapf(err, "failed to write file '%v'", target)
+	}
+	// restore read-only files like the ones of the Go module cache after writing them
+	return os.Chmod(target, mode)
+}
@@ -0,0 +1,62 @@
+package buildcache
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchive(t *testing.T) {
+	t.Parallel()
+
+	t.Run("archive and extract", func(t *testing.T) {
+		t.Parallel()
+		source := t.TempDir()
+		require.NoError(t, os.MkdirAll(filepath.Join(source, "cache", "download"), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(source, "cache", "download", "module.zip"), []byte("zip"), 0o444))
+		require.NoError(t, os.WriteFile(filepath.Join(source, "index"), []byte("index"), 0o644))
+		archive := filepath.Join(t.TempDir(), "cache.tar.gz")
+
+		require.NoError(t, createArchive(source, archive))
+
+		target := filepath.Join(t.TempDir(), "restored")
+		require.NoError(t, os.MkdirAll(target, 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(target, "index"), []byte("newer index"), 0o644))
+		assert.NoError(t, extractArchive(archive, target))
+
+		content, err := os.ReadFile(filepath.Join(target, "cache", "download", "module.zip"))
+		assert.NoError(t, err)
+		assert.Equal(t, "zip", string(content))
+		info, err := os.Stat(filepath.Join(target, "cache", "download", "module.zip"))
+		assert.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o444), info.Mode().Perm())
+		// existing files are kept
+		content, err = os.ReadFile(filepath.Join(target, "index"))
+		assert.NoError(t, err)
+		assert.Equal(t, "newer index", string(content))
+	})
+
+	t.Run("invalid path", func(t *testing.T) {
+		t.Parallel()
+		archive := filepath.Join(t.TempDir(), "cache.tar.gz")
+		file, err := os.Create(archive)
+		require.NoError(t, err)
+		gzipWriter := gzip.NewWriter(file)
+		tarWriter := tar.NewWriter(gzipWriter)
+		require.NoError(t, tarWriter.WriteHeader(&tar.Header{Name: "../evil", Typeflag: tar.TypeReg, Mode: 0o644, Size: 4}))
+		_, err = tarWriter.Write([]byte("evil"))
+		require.NoError(t, err)
+		require.NoError(t, tarWriter.Close())
+		require.NoError(t, gzipWriter.Close())
+		require.NoError(t, file.Close())
+
+		err = extractArchive(archive, filepath.Join(t.TempDir(), "target"))
+
+		assert.EqualError(t, err, "archive '"+archive+"' contains invalid path '../evil'")
+	})
+}
@@ -0,0 +1,190 @@
+package buildcache
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/cachestore"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/pkg/errors"
+)
+
+// archiveExtension is appended to the key in order to name the archive in the store
+const archiveExtension = ".tar.gz"
+
+// Directory is a cache directory of a build tool, e.g. the local Maven repository
+type Directory struct {
+	// Name identifies the cache in the store, e.g. "maven"
+	Name string
+	Path string
+	// Key is the hash of the files which define the content of the directory, e.g. the lock files of the project
+	Key string
+	// restoredKey is the key of the archive which has been restored
+	restoredKey string
+}
+
+// keys returns the keys under which the directory is looked up: the exact key and as fallback the archive which has
+// been saved last for the directory within the scope, independent of the files defining its content.
+// Without scope there is no fallback, since unrelated projects would replace each other's archive.
+func (d *Directory) keys(scope string) []string {
+	keys := []string{}
+	if len(d.Key) > 0 {
+		keys = append(keys, d.Name+"-"+d.Key)
+	}
+	if len(scope) > 0 {
+		keys = append(keys, scope+"-"+d.Name)
+	}
+	return keys
+}
+
+// BuildCache restores the cache directories of build tools before a build and saves them afterwards.
+// Errors of the underlying store are only logged since the cache must never fail a build.
+// A nil cache is valid and behaves like a cache without archives.
+type BuildCache struct {
+	store   cachestore.Store
+	maxSize int64
+	// scope prefixes the fallback keys, it identifies the repository or project of the build
+	scope   string
+	tempDir string
+}
+
+// New creates a build cache which does not save archives larger than maxSizeMB megabytes. The scope, e.g. the URL of
+// the repository, restricts the fallback archives to builds of the same repository or project.
+func New(store cachestore.Store, maxSizeMB int, scope string) *BuildCache {
+	cache := &BuildCache{store: store, maxSize: int64(maxSizeMB) * 1024 * 1024}
+	if len(scope) > 0 {
+		cache.scope = fmt.Sprintf("%x", sha256.Sum256([]byte(scope)))[:16]
+	}
+	return cache
+}
+
+// Restore extracts the archive of the directory into the directory. In case there is no archive for the exact key
+// the fallback archive is used.
+func (c *BuildCache) Restore(directory *Directory) {
+	if c == nil {
+		return
+	}
+	tempDir, err := os.MkdirTemp(c.tempDir, "buildcache")
+	if err != nil {
+		log.Entry().WithError(err).Warn("failed to restore build cache")
+		return
+	}
+	defer os.RemoveAll(tempDir)
+
+	for _, key := range directory.keys(c.scope) {
+		archive := filepath.Join(tempDir, key+archiveExtension)
+		found, err := c.store.Download(key+archiveExtension, archive)
+		if err != nil {
+			log.Entry().WithError(err).Warnf("failed to download build cache %v", key)
+			continue
+		}
+		if !found {
+			continue
+		}
+		if err := extractArchive(archive, directory.Path); err != nil {
+			log.Entry().WithError(err).Warnf("failed to restore build cache %v", key)
+			return
+		}
+		log.Entry().Infof("Restored build cache %v into '%v'", key, directory.Path)
+		directory.restoredKey = key
+		return
+	}
+	log.Entry().Infof("No build cache found for '%v'", directory.Path)
+}
+
+// Save archives the directory and stores it under its exact key as well as under the fallback key.
+// Nothing is saved in case the archive of the exact key has been restored, since the content did not change.
+func (c *BuildCache) Save(directory *Directory) {
+	if c == nil {
+		return
+	}
+	keys := directory.keys(c.scope)
+	if len(keys) == 0 {
+		log.Entry().Infof("Build cache of '%v' is not saved, there are neither files defining its content nor a repository", directory.Path)
+		return
+	}
+	if len(directory.Key) > 0 && directory.restoredKey == keys[0] {
+		log.Entry().Infof("Build cache %v is up to date", keys[0])
+		return
+	}
+	if info, err := os.Stat(directory.Path); err != nil || !info.IsDir() {
+		log.Entry().Infof("Build cache directory '%v' does not exist, nothing to save", directory.Path)
+		return
+	}
+	tempDir, err := os.MkdirTemp(c.tempDir, "buildcache")
+	if err != nil {
+		log.Entry().WithError(err).Warn("failed to save build cache")
+		return
+	}
+	defer os.RemoveAll(tempDir)
+
+	archive := filepath.Join(tempDir, directory.Name+archiveExtension)
+	if err := createArchive(directory.Path, archive); err != nil {
+		log.Entry().WithError(err).Warn("failed to save build cache")
+		return
+	}
+	info, err := os.Stat(archive)
+	if err != nil {
+		log.Entry().WithError(err).Warn("failed to save build cache")
+		return
+	}
+	if c.maxSize > 0 && info.Size() > c.maxSize {
+		log.Entry().Warnf("Build cache of '%v' is not saved, its size of %v MB exceeds the maximum size of %v MB", directory.Path, info.Size()/1024/1024, c.maxSize/1024/1024)
+		return
+	}
+	for _, key := range keys {
+		if err := c.store.Upload(archive, key+archiveExtension); err != nil {
+			log.Entry().WithError(err).Warnf("failed to save build cache %v", key)
+			return
+		}
+	}
+	log.Entry().Infof("Saved build cache %v (%v MB)", keys[0], info.Size()/1024/1024)
+}
+
+// HashFiles calculates a combined SHA256 hash of the files matching the patterns. Files within directories
+// containing installed dependencies or build results, e.g. node_modules or target, are ignored.
+// It returns an empty hash in case no file matches.
+func HashFiles(utils piperutils.FileUtils, patterns ...string) (string, error) {
+	files := []string{}
+	for _, pattern := range patterns {
+		matches, err := utils.Glob(pattern)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to find files matching '%v'", pattern)
+		}
+		for _, match := range matches {
+			if !isExcluded(match) && !piperutils.ContainsString(files, match) {
+				files = append(files, match)
+			}
+		}
+	}
+	if len(files) == 0 {
+		return "", nil
+	}
+	sort.Strings(files)
+	hash := sha256.New()
+	for _, file := range files {
+		fileHash, err := utils.SHA256(file)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to calculate hash of '%v'", file)
+		}
+		fmt.Fprintf(hash, "%v %v\n", filepath.ToSlash(file), fileHash)
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
+
+// excludedDirectories contain installed dependencies or build results
+var excludedDirectories = []string{"node_modules", ".git", ".pipeline", "target", "vendor"}
+
+func isExcluded(path string) bool {
+	for _, part := range strings.Split(filepath.ToSlash(path), "/") {
+		if piperutils.ContainsString(excludedDirectories, part) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,161 @@
+package buildcache
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type storeMock struct {
+	archives map[string][]byte
+	uploads  []string
+	err      error
+}
+
+func (s *storeMock) Download(key, file string) (bool, error) {
+	if s.err != nil {
+		return false, s.err
+	}
+	content, ok := s.archives[key]
+	if !ok {
+		return false, nil
+	}
+	return true, os.WriteFile(file, content, 0o644)
+}
+
+func (s *storeMock) Upload(file, key string) error {
+	if s.err != nil {
+		return s.err
+	}
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	s.archives[key] = content
+	s.uploads = append(s.uploads, key)
+	return nil
+}
+
+func TestBuildCache(t *testing.T) {
+	repository := "https://github.com/SAP/jenkins-library"
+	scope := fmt.Sprintf("%x", sha256.Sum256([]byte(repository)))[:16]
+	newDirectory := func(t *testing.T, files map[string]string) string {
+		dir := t.TempDir()
+		for name, content := range files {
+			require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0o755))
+			require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644))
+		}
+		return dir
+	}
+
+	t.Run("save and restore", func(t *testing.T) {
+		store := &storeMock{archives: map[string][]byte{}}
+		cache := New(store, 10, repository)
+		cache.tempDir = t.TempDir()
+
+		source := &Directory{Name: "maven", Path: newDirectory(t, map[string]string{"org/example/lib.jar": "jar"}), Key: "abc"}
+		cache.Restore(source)
+		cache.Save(source)
+		assert.Equal(t, []string{"maven-abc.tar.gz", scope + "-maven.tar.gz"}, store.uploads)
+
+		target := &Directory{Name: "maven", Path: filepath.Join(t.TempDir(), "repository"), Key: "abc"}
+		cache.Restore(target)
+		content, err := os.ReadFile(filepath.Join(target.Path, "org", "example", "lib.jar"))
+		assert.NoError(t, err)
+		assert.Equal(t, "jar", string(content))
+
+		// the content is unchanged, thus nothing is uploaded
+		cache.Save(target)
+		assert.Len(t, store.uploads, 2)
+	})
+
+	t.Run("fallback", func(t *testing.T) {
+		store := &storeMock{archives: map[string][]byte{}}
+		cache := New(store, 10, repository)
+		cache.tempDir = t.TempDir()
+		cache.Save(&Directory{Name: "npm", Path: newDirectory(t, map[string]string{"_cacache/index": "old"}), Key: "old"})
+		store.uploads = nil
+
+		target := &Directory{Name: "npm", Path: t.TempDir(), Key: "new"}
+		cache.Restore(target)
+		assert.Equal(t, scope+"-npm", target.restoredKey)
+		assert.FileExists(t, filepath.Join(target.Path, "_cacache", "index"))
+
+		cache.Save(target)
+		assert.Equal(t, []string{"npm-new.tar.gz", scope + "-npm.tar.gz"}, store.uploads)
+
+		// the archives of other repositories are not used as fallback
+		other := &Directory{Name: "npm", Path: t.TempDir(), Key: "other"}
+		New(store, 10, "https://github.com/SAP/other").Restore(other)
+		assert.Empty(t, other.restoredKey)
+	})
+
+	t.Run("without scope", func(t *testing.T) {
+		store := &storeMock{archives: map[string][]byte{}}
+		cache := New(store, 10, "")
+		cache.tempDir = t.TempDir()
+
+		cache.Save(&Directory{Name: "maven", Path: newDirectory(t, map[string]string{"org/example/lib.jar": "jar"}), Key: "abc"})
+		cache.Save(&Directory{Name: "go-build", Path: newDirectory(t, map[string]string{"00/abc-a": "object"})})
+
+		assert.Equal(t, []string{"maven-abc.tar.gz"}, store.uploads)
+	})
+
+	t.Run("size limit", func(t *testing.T) {
+		store := &storeMock{archives: map[string][]byte{}}
+		cache := New(store, 0, repository)
+		cache.maxSize = 10
+		cache.tempDir = t.TempDir()
+
+		cache.Save(&Directory{Name: "go-mod", Path: newDirectory(t, map[string]string{"cache/download/module.zip": "module"}), Key: "abc"})
+
+		assert.Empty(t, store.uploads)
+	})
+
+	t.Run("store failure", func(t *testing.T) {
+		store := &storeMock{err: errors.New("access denied")}
+		cache := New(store, 10, repository)
+		cache.tempDir = t.TempDir()
+		directory := &Directory{Name: "go-build", Path: newDirectory(t, map[string]string{"00/abc-a": "object"}), Key: "abc"}
+
+		cache.Restore(directory)
+		cache.Save(directory)
+
+		assert.Empty(t, directory.restoredKey)
+	})
+
+	t.Run("nil cache", func(t *testing.T) {
+		var cache *BuildCache
+		directory := &Directory{Name: "maven", Path: t.TempDir()}
+
+		cache.Restore(directory)
+		cache.Save(directory)
+	})
+}
+
+func TestHashFiles(t *testing.T) {
+	utils := &mock.FilesMock{}
+	utils.AddFile("go.sum", []byte("sum"))
+	utils.AddFile("tools/go.sum", []byte("tools"))
+	utils.AddFile("vendor/example.com/lib/go.sum", []byte("vendored"))
+
+	hash, err := HashFiles(utils, "**/go.sum")
+	assert.NoError(t, err)
+	assert.Len(t, hash, 64)
+
+	utils.AddFile("vendor/example.com/other/go.sum", []byte("vendored"))
+	unchanged, err := HashFiles(utils, "**/go.sum")
+	assert.NoError(t, err)
+	assert.Equal(t, hash, unchanged)
+
+	hash, err = HashFiles(utils, "**/package-lock.json")
+	assert.NoError(t, err)
+	assert.Empty(t, hash)
+}
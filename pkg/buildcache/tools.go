@@ -0,0 +1,88 @@
+package buildcache
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+)
+
+// GoDirectories returns the module cache and the build cache of Go, both keyed by the go.sum files of the project
+func GoDirectories(utils piperutils.FileUtils) ([]*Directory, error) {
+	key, err := HashFiles(utils, "**/go.sum")
+	if err != nil {
+		return nil, err
+	}
+	moduleCache := os.Getenv("GOMODCACHE")
+	if len(moduleCache) == 0 {
+		goPath := filepath.SplitList(os.Getenv("GOPATH"))
+		if len(goPath) > 0 && len(goPath[0]) > 0 {
+			moduleCache = filepath.Join(goPath[0], "pkg", "mod")
+		} else {
+			moduleCache = filepath.Join(homeDir(), "go", "pkg", "mod")
+		}
+	}
+	buildCache := os.Getenv("GOCACHE")
+	if len(buildCache) == 0 {
+		buildCache = filepath.Join(userCacheDir(), "go-build")
+	}
+	return []*Directory{
+		{Name: "go-mod", Path: moduleCache, Key: key},
+		{Name: "go-build", Path: buildCache, Key: key},
+	}, nil
+}
+
+// MavenDirectory returns the local Maven repository keyed by the pom.xml files of the project
+func MavenDirectory(utils piperutils.FileUtils, m2Path string) (*Directory, error) {
+	key, err := HashFiles(utils, "**/pom.xml")
+	if err != nil {
+		return nil, err
+	}
+	repository := m2Path
+	if len(repository) == 0 {
+		repository = filepath.Join(homeDir(), ".m2", "repository")
+	}
+	return &Directory{Name: "maven", Path: repository, Key: key}, nil
+}
+
+// NodeDirectory returns the package cache of yarn, if the project uses yarn, or of npm keyed by the lock files of the project.
+// The node_modules directories are not cached since "npm ci" removes them before installing the dependencies.
+func NodeDirectory(utils piperutils.FileUtils) (*Directory, error) {
+	yarnKey, err := HashFiles(utils, "**/yarn.lock")
+	if err != nil {
+		return nil, err
+	}
+	if len(yarnKey) > 0 {
+		cache := os.Getenv("YARN_CACHE_FOLDER")
+		if len(cache) == 0 {
+			cache = filepath.Join(userCacheDir(), "yarn")
+		}
+		return &Directory{Name: "yarn", Path: cache, Key: yarnKey}, nil
+	}
+
+	npmKey, err := HashFiles(utils, "**/package-lock.json", "**/npm-shrinkwrap.json")
+	if err != nil {
+		return nil, err
+	}
+	cache := os.Getenv("npm_config_cache")
+	if len(cache) == 0 {
+		cache = filepath.Join(homeDir(), ".npm")
+	}
+	return &Directory{Name: "npm", Path: cache, Key: npmKey}, nil
+}
+
+func homeDir() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "."
+	}
+	return home
+}
+
+func userCacheDir() string {
+	cache, err := os.UserCacheDir()
+	if err != nil {
+		return filepath.Join(homeDir(), ".cache")
+	}
+	return cache
+}
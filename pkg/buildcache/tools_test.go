@@ -0,0 +1,70 @@
+package buildcache
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGoDirectories(t *testing.T) {
+	t.Setenv("GOMODCACHE", "")
+	t.Setenv("GOPATH", "/go")
+	t.Setenv("GOCACHE", "/tmp/go-build")
+	utils := &mock.FilesMock{}
+	utils.AddFile("go.sum", []byte("sum"))
+
+	directories, err := GoDirectories(utils)
+
+	assert.NoError(t, err)
+	if assert.Len(t, directories, 2) {
+		assert.Equal(t, "go-mod", directories[0].Name)
+		assert.Equal(t, filepath.Join("/go", "pkg", "mod"), directories[0].Path)
+		assert.Len(t, directories[0].Key, 64)
+		assert.Equal(t, "go-build", directories[1].Name)
+		assert.Equal(t, "/tmp/go-build", directories[1].Path)
+		assert.Equal(t, directories[0].Key, directories[1].Key)
+	}
+}
+
+func TestMavenDirectory(t *testing.T) {
+	utils := &mock.FilesMock{}
+	utils.AddFile("pom.xml", []byte("<project/>"))
+	utils.AddFile("module/pom.xml", []byte("<project/>"))
+
+	directory, err := MavenDirectory(utils, "/m2")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "maven", directory.Name)
+	assert.Equal(t, "/m2", directory.Path)
+	assert.Len(t, directory.Key, 64)
+}
+
+func TestNodeDirectory(t *testing.T) {
+	t.Setenv("npm_config_cache", "/npm-cache")
+	t.Setenv("YARN_CACHE_FOLDER", "/yarn-cache")
+
+	t.Run("npm", func(t *testing.T) {
+		utils := &mock.FilesMock{}
+		utils.AddFile("package-lock.json", []byte("{}"))
+		utils.AddFile("node_modules/lib/package-lock.json", []byte("{}"))
+
+		directory, err := NodeDirectory(utils)
+
+		assert.NoError(t, err)
+		assert.Equal(t, &Directory{Name: "npm", Path: "/npm-cache", Key: directory.Key}, directory)
+		assert.Len(t, directory.Key, 64)
+	})
+
+	t.Run("yarn", func(t *testing.T) {
+		utils := &mock.FilesMock{}
+		utils.AddFile("yarn.lock", []byte(""))
+
+		directory, err := NodeDirectory(utils)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "yarn", directory.Name)
+		assert.Equal(t, "/yarn-cache", directory.Path)
+	})
+}
@@ -0,0 +1,167 @@
+package cachestore
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/gcs"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/pkg/errors"
+)
+
+// Store persists the objects of a cache, e.g. the archives of the build cache or the entries of the scan cache
+type Store interface {
+	// Download writes the object into the file, it returns false if there is no object with the name
+	Download(name, file string) (bool, error)
+	// Upload stores the file as object with the name, an existing object is replaced
+	Upload(file, name string) error
+}
+
+// LocalStore keeps the objects in a directory, e.g. on a persistent volume
+type LocalStore struct {
+	Dir   string
+	Files piperutils.FileUtils
+}
+
+func (s *LocalStore) objectPath(name string) string {
+	return filepath.Join(s.Dir, name)
+}
+
+// Download copies the object from the directory
+func (s *LocalStore) Download(name, file string) (bool, error) {
+	if exists, _ := s.Files.FileExists(s.objectPath(name)); !exists {
+		return false, nil
+	}
+	if _, err := s.Files.Copy(s.objectPath(name), file); err != nil {
+		return false, errors.Wrapf(err, "failed to copy '%v' from cache", name)
+	}
+	return true, nil
+}
+
+// Upload copies the object into the directory
+func (s *LocalStore) Upload(file, name string) error {
+	if err := s.Files.MkdirAll(s.Dir, 0777); err != nil {
+		return errors.Wrapf(err, "failed to create cache directory '%v'", s.Dir)
+	}
+	// copy to a temporary file first, parallel pipelines must not read a partially written object
+	temporary := s.objectPath(name) + ".tmp"
+	if _, err := s.Files.Copy(file, temporary); err != nil {
+		return errors.Wrapf(err, "failed to copy '%v' into cache", name)
+	}
+	if err := s.Files.FileRename(temporary, s.objectPath(name)); err != nil {
+		return errors.Wrapf(err, "failed to store '%v' in cache", name)
+	}
+	return nil
+}
+
+// GCSStore keeps the objects in a Google Cloud Storage bucket
+type GCSStore struct {
+	Client gcs.Client
+	Bucket string
+	Folder string
+}
+
+// Download downloads the object from the bucket. An object which cannot be downloaded is treated as missing.
+func (s *GCSStore) Download(name, file string) (bool, error) {
+	if err := s.Client.DownloadFile(s.Bucket, path.Join(s.Folder, name), file); err != nil {
+		// the client does not allow to distinguish a missing object from other failures
+		return false, nil
+	}
+	return true, nil
+}
+
+// Upload uploads the object into the bucket
+func (s *GCSStore) Upload(file, name string) error {
+	if err := s.Client.UploadFile(s.Bucket, file, path.Join(s.Folder, name)); err != nil {
+		return errors.Wrapf(err, "failed to upload '%v' into cache", name)
+	}
+	return nil
+}
+
+// S3API defines the functions of the S3 client which are used by the store
+type S3API interface {
+	GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error)
+	PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error)
+}
+
+// S3Store keeps the objects in an S3 compatible bucket
+type S3Store struct {
+	Client S3API
+	Bucket string
+	Prefix string
+	Files  piperutils.FileUtils
+}
+
+// Download downloads the object from the bucket
+func (s *S3Store) Download(name, file string) (bool, error) {
+	objectKey := path.Join(s.Prefix, name)
+	output, err := s.Client.GetObject(context.TODO(), &s3.GetObjectInput{Bucket: &s.Bucket, Key: &objectKey})
+	if err != nil {
+		var noSuchKey *types.NoSuchKey
+		if errors.As(err, &noSuchKey) {
+			return false, nil
+		}
+		return false, errors.Wrapf(err, "failed to download '%v' from cache", name)
+	}
+	defer output.Body.Close()
+	target, err := s.Files.Create(file)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to create file '%v'", file)
+	}
+	defer target.Close()
+	if _, err := io.Copy(target, output.Body); err != nil {
+		return false, errors.Wrapf(err, "failed to download '%v' from cache", name)
+	}
+	return true, nil
+}
+
+// Upload uploads the object into the bucket
+func (s *S3Store) Upload(file, name string) error {
+	source, err := s.Files.Open(file)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open file '%v'", file)
+	}
+	defer source.Close()
+	objectKey := path.Join(s.Prefix, name)
+	if _, err := s.Client.PutObject(context.TODO(), &s3.PutObjectInput{Bucket: &s.Bucket, Key: &objectKey, Body: source}); err != nil {
+		return errors.Wrapf(err, "failed to upload '%v' into cache", name)
+	}
+	return nil
+}
+
+// Location describes where the objects are stored
+type Location struct {
+	// Scheme is either "gs", "s3" or empty for a local directory
+	Scheme string
+	// Bucket is empty for a local directory
+	Bucket string
+	Path   string
+}
+
+// ParseLocation parses a location like gs://bucket/folder, s3://bucket/prefix or a local directory
+func ParseLocation(location string) (Location, error) {
+	for _, scheme := range []string{"gs", "s3"} {
+		if !strings.HasPrefix(location, scheme+"://") {
+			continue
+		}
+		parts := strings.SplitN(strings.TrimPrefix(location, scheme+"://"), "/", 2)
+		if len(parts[0]) == 0 {
+			return Location{}, fmt.Errorf("cache location '%v' does not contain a bucket", location)
+		}
+		result := Location{Scheme: scheme, Bucket: parts[0]}
+		if len(parts) > 1 {
+			result.Path = strings.Trim(parts[1], "/")
+		}
+		return result, nil
+	}
+	if strings.Contains(location, "://") && !strings.HasPrefix(location, "file://") {
+		return Location{}, fmt.Errorf("cache location '%v' is not supported, use a directory, gs://<bucket>/<folder> or s3://<bucket>/<prefix>", location)
+	}
+	return Location{Path: strings.TrimPrefix(location, "file://")}, nil
+}
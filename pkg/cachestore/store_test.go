@@ -0,0 +1,148 @@
+package cachestore
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/gcs/mocks"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStore(t *testing.T) {
+	dir := t.TempDir()
+	store := &LocalStore{Dir: filepath.Join(dir, "cache"), Files: &piperutils.Files{}}
+	archive := filepath.Join(dir, "upload.tar.gz")
+	require.NoError(t, os.WriteFile(archive, []byte("archive"), 0o644))
+
+	found, err := store.Download("maven-abc.tar.gz", filepath.Join(dir, "download.tar.gz"))
+	assert.NoError(t, err)
+	assert.False(t, found)
+
+	assert.NoError(t, store.Upload(archive, "maven-abc.tar.gz"))
+	assert.FileExists(t, filepath.Join(dir, "cache", "maven-abc.tar.gz"))
+	assert.NoFileExists(t, filepath.Join(dir, "cache", "maven-abc.tar.gz.tmp"))
+
+	found, err = store.Download("maven-abc.tar.gz", filepath.Join(dir, "download.tar.gz"))
+	assert.NoError(t, err)
+	assert.True(t, found)
+	content, _ := os.ReadFile(filepath.Join(dir, "download.tar.gz"))
+	assert.Equal(t, "archive", string(content))
+}
+
+func TestGCSStore(t *testing.T) {
+	t.Run("upload and download", func(t *testing.T) {
+		client := &mocks.Client{}
+		client.On("UploadFile", "bucket", "upload.tar.gz", "cache/npm-abc.tar.gz").Return(nil)
+		client.On("DownloadFile", "bucket", "cache/npm-abc.tar.gz", "download.tar.gz").Return(nil)
+		store := &GCSStore{Client: client, Bucket: "bucket", Folder: "cache"}
+
+		assert.NoError(t, store.Upload("upload.tar.gz", "npm-abc.tar.gz"))
+		found, err := store.Download("npm-abc.tar.gz", "download.tar.gz")
+
+		assert.NoError(t, err)
+		assert.True(t, found)
+		client.AssertExpectations(t)
+	})
+
+	t.Run("missing archive", func(t *testing.T) {
+		client := &mocks.Client{}
+		client.On("DownloadFile", "bucket", "npm-abc.tar.gz", "download.tar.gz").Return(errors.New("storage: object doesn't exist"))
+		store := &GCSStore{Client: client, Bucket: "bucket"}
+
+		found, err := store.Download("npm-abc.tar.gz", "download.tar.gz")
+
+		assert.NoError(t, err)
+		assert.False(t, found)
+	})
+}
+
+type s3Mock struct {
+	objects map[string][]byte
+	err     error
+}
+
+func (s *s3Mock) GetObject(ctx context.Context, params *s3.GetObjectInput, optFns ...func(*s3.Options)) (*s3.GetObjectOutput, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	content, ok := s.objects[*params.Bucket+"/"+*params.Key]
+	if !ok {
+		return nil, &types.NoSuchKey{}
+	}
+	return &s3.GetObjectOutput{Body: io.NopCloser(bytes.NewReader(content))}, nil
+}
+
+func (s *s3Mock) PutObject(ctx context.Context, params *s3.PutObjectInput, optFns ...func(*s3.Options)) (*s3.PutObjectOutput, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	content, _ := io.ReadAll(params.Body)
+	s.objects[*params.Bucket+"/"+*params.Key] = content
+	return &s3.PutObjectOutput{}, nil
+}
+
+func TestS3Store(t *testing.T) {
+	t.Run("upload and download", func(t *testing.T) {
+		dir := t.TempDir()
+		archive := filepath.Join(dir, "upload.tar.gz")
+		require.NoError(t, os.WriteFile(archive, []byte("archive"), 0o644))
+		client := &s3Mock{objects: map[string][]byte{}}
+		store := &S3Store{Client: client, Bucket: "bucket", Prefix: "piper/build-cache", Files: &piperutils.Files{}}
+
+		found, err := store.Download("go-mod-abc.tar.gz", filepath.Join(dir, "download.tar.gz"))
+		assert.NoError(t, err)
+		assert.False(t, found)
+
+		assert.NoError(t, store.Upload(archive, "go-mod-abc.tar.gz"))
+		assert.Equal(t, []byte("archive"), client.objects["bucket/piper/build-cache/go-mod-abc.tar.gz"])
+
+		found, err = store.Download("go-mod-abc.tar.gz", filepath.Join(dir, "download.tar.gz"))
+		assert.NoError(t, err)
+		assert.True(t, found)
+		content, _ := os.ReadFile(filepath.Join(dir, "download.tar.gz"))
+		assert.Equal(t, "archive", string(content))
+	})
+
+	t.Run("access denied", func(t *testing.T) {
+		store := &S3Store{Client: &s3Mock{err: errors.New("AccessDenied")}, Bucket: "bucket", Files: &piperutils.Files{}}
+
+		_, err := store.Download("go-mod-abc.tar.gz", filepath.Join(t.TempDir(), "download.tar.gz"))
+
+		assert.EqualError(t, err, "failed to download 'go-mod-abc.tar.gz' from cache: AccessDenied")
+	})
+}
+
+func TestParseLocation(t *testing.T) {
+	tt := []struct {
+		location string
+		expected Location
+		err      string
+	}{
+		{location: "/var/cache/piper", expected: Location{Path: "/var/cache/piper"}},
+		{location: "file:///var/cache/piper", expected: Location{Path: "/var/cache/piper"}},
+		{location: "gs://bucket/build-cache/", expected: Location{Scheme: "gs", Bucket: "bucket", Path: "build-cache"}},
+		{location: "s3://bucket", expected: Location{Scheme: "s3", Bucket: "bucket"}},
+		{location: "s3:///prefix", err: "cache location 's3:///prefix' does not contain a bucket"},
+		{location: "https://cache.example.com", err: "cache location 'https://cache.example.com' is not supported, use a directory, gs://<bucket>/<folder> or s3://<bucket>/<prefix>"},
+	}
+	for _, test := range tt {
+		t.Run(test.location, func(t *testing.T) {
+			location, err := ParseLocation(test.location)
+			if len(test.err) > 0 {
+				assert.EqualError(t, err, test.err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expected, location)
+			}
+		})
+	}
+}
@@ -0,0 +1,510 @@
+package checkmarxone
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	piperHttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// ReportsDirectory defines the subfolder for the Checkmarx One reports which are generated
+const ReportsDirectory = "checkmarxOne"
+
+// Scan types supported by Checkmarx One
+const (
+	ScanTypeSAST = "sast"
+	ScanTypeSCA  = "sca"
+	ScanTypeKICS = "kics"
+)
+
+// Scan status values reported by Checkmarx One
+const (
+	ScanStatusQueued    = "Queued"
+	ScanStatusRunning   = "Running"
+	ScanStatusCompleted = "Completed"
+	ScanStatusPartial   = "Partial"
+	ScanStatusFailed    = "Failed"
+	ScanStatusCanceled  = "Canceled"
+)
+
+// the page size used when fetching results
+const resultsPageSize = 500
+
+// AuthToken - Structure to store OAuth2 token
+type AuthToken struct {
+	TokenType   string `json:"token_type"`
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+// Application - Application Structure
+type Application struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Criticality int      `json:"criticality"`
+	ProjectIDs  []string `json:"projectIds"`
+}
+
+// Project - Project Structure
+type Project struct {
+	ID             string   `json:"id"`
+	Name           string   `json:"name"`
+	Groups         []string `json:"groups"`
+	ApplicationIDs []string `json:"applicationIds"`
+	MainBranch     string   `json:"mainBranch"`
+}
+
+// ScanConfiguration - configuration of one scanner of a scan
+type ScanConfiguration struct {
+	Type  string            `json:"type"`
+	Value map[string]string `json:"value"`
+}
+
+// ScanStatusDetail - ScanStatusDetail Structure
+type ScanStatusDetail struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Details string `json:"details"`
+}
+
+// Scan - Scan Structure
+type Scan struct {
+	ID            string             `json:"id"`
+	Status        string             `json:"status"`
+	StatusDetails []ScanStatusDetail `json:"statusDetails"`
+	Branch        string             `json:"branch"`
+	CreatedAt     string             `json:"createdAt"`
+	UpdatedAt     string             `json:"updatedAt"`
+	ProjectID     string             `json:"projectId"`
+	ProjectName   string             `json:"projectName"`
+	Initiator     string             `json:"initiator"`
+	Engines       []string           `json:"engines"`
+}
+
+// ResultNode - one node of the data flow of a SAST result
+type ResultNode struct {
+	FileName   string `json:"fileName"`
+	Line       int    `json:"line"`
+	Column     int    `json:"column"`
+	Length     int    `json:"length"`
+	Name       string `json:"name"`
+	Method     string `json:"method"`
+	DomType    string `json:"domType"`
+	NodeID     int    `json:"nodeID"`
+	FullName   string `json:"fullName"`
+	MethodLine int    `json:"methodLine"`
+}
+
+// ResultData - scanner specific data of a result
+type ResultData struct {
+	QueryID            json.Number  `json:"queryId"`
+	QueryName          string       `json:"queryName"`
+	Group              string       `json:"group"`
+	LanguageName       string       `json:"languageName"`
+	Nodes              []ResultNode `json:"nodes"`
+	PackageIdentifier  string       `json:"packageIdentifier"`
+	RecommendedVersion string       `json:"recommendedVersion"`
+	Platform           string       `json:"platform"`
+	FileName           string       `json:"filename"`
+	Line               int          `json:"line"`
+	ExpectedValue      string       `json:"expectedValue"`
+	Value              string       `json:"value"`
+}
+
+// VulnerabilityDetails - vulnerability classification of a result
+type VulnerabilityDetails struct {
+	CweID     int     `json:"cweId"`
+	CvssScore float64 `json:"cvssScore"`
+	CveName   string  `json:"cveName"`
+}
+
+// ScanResult - one result of a scan, regardless of the scanner which produced it
+type ScanResult struct {
+	Type                 string               `json:"type"`
+	ID                   string               `json:"id"`
+	SimilarityID         string               `json:"similarityId"`
+	Status               string               `json:"status"`
+	State                string               `json:"state"`
+	Severity             string               `json:"severity"`
+	Description          string               `json:"description"`
+	Data                 ResultData           `json:"data"`
+	VulnerabilityDetails VulnerabilityDetails `json:"vulnerabilityDetails"`
+	FirstFoundAt         string               `json:"firstFoundAt"`
+}
+
+// ScanResults - a page of results
+type ScanResults struct {
+	Results    []ScanResult `json:"results"`
+	TotalCount int          `json:"totalCount"`
+}
+
+// SystemInstance is the client communicating with the Checkmarx One backend
+type SystemInstance struct {
+	serverURL   string
+	iamURL      string
+	tenant      string
+	apiKey      string
+	token       string
+	tokenExpiry time.Time
+	client      piperHttp.Uploader
+	logger      *logrus.Entry
+}
+
+// System is the interface abstraction of a specific SystemInstance
+type System interface {
+	GetApplicationsByName(name string) ([]Application, error)
+	CreateApplication(name string) (Application, error)
+	GetProjectsByName(name string) ([]Project, error)
+	CreateProject(name string, applicationID string, groups []string) (Project, error)
+	UploadProjectSourceCode(zipFile string) (string, error)
+	ScanProject(projectID, uploadURL, branch string, configurations []ScanConfiguration) (Scan, error)
+	GetScan(scanID string) (Scan, error)
+	GetLastScans(projectID, branch string, limit int) ([]Scan, error)
+	GetResults(scanID string) ([]ScanResult, error)
+	DeepLink(projectID, scanID, branch string) string
+}
+
+// NewSystemInstance returns a new Checkmarx One client for communicating with the backend
+func NewSystemInstance(client piperHttp.Uploader, serverURL, iamURL, tenant, apiKey string) (*SystemInstance, error) {
+	loggerInstance := log.Entry().WithField("package", "SAP/jenkins-library/pkg/checkmarxone")
+	sys := &SystemInstance{
+		serverURL: strings.TrimSuffix(serverURL, "/"),
+		iamURL:    strings.TrimSuffix(iamURL, "/"),
+		tenant:    tenant,
+		apiKey:    apiKey,
+		client:    client,
+		logger:    loggerInstance,
+	}
+
+	// the token is sent per request instead of via the client options since the pre-signed upload URL must not receive it
+	if err := sys.refreshToken(); err != nil {
+		return sys, errors.Wrap(err, "Error fetching oAuth token")
+	}
+	return sys, nil
+}
+
+// refreshToken exchanges the API key, which is an OAuth refresh token, for an access token
+func (sys *SystemInstance) refreshToken() error {
+	body := url.Values{
+		"grant_type":    {"refresh_token"},
+		"client_id":     {"ast-app"},
+		"refresh_token": {sys.apiKey},
+	}
+	header := http.Header{}
+	header.Add("Content-Type", "application/x-www-form-urlencoded")
+	tokenURL := fmt.Sprintf("%v/auth/realms/%v/protocol/openid-connect/token", sys.iamURL, sys.tenant)
+	response, err := sys.client.SendRequest(http.MethodPost, tokenURL, strings.NewReader(body.Encode()), header, nil)
+	if err != nil {
+		sys.recordRequestDetailsInErrorCase(nil, response)
+		return err
+	}
+	defer response.Body.Close()
+	data, _ := ioutil.ReadAll(response.Body)
+
+	var token AuthToken
+	if err := json.Unmarshal(data, &token); err != nil {
+		return errors.Wrap(err, "failed to parse oAuth token")
+	}
+	if len(token.AccessToken) == 0 {
+		return errors.New("no access token received")
+	}
+	log.RegisterSecret(token.AccessToken)
+	sys.token = token.TokenType + " " + token.AccessToken
+	// refresh the token a minute before it expires, scans may take longer than the token lifetime
+	sys.tokenExpiry = time.Now().Add(time.Duration(token.ExpiresIn)*time.Second - time.Minute)
+	return nil
+}
+
+func sendRequest(sys *SystemInstance, method, url string, body io.Reader, header http.Header) ([]byte, error) {
+	return sendRequestInternal(sys, method, url, body, header, []int{})
+}
+
+func sendRequestInternal(sys *SystemInstance, method, url string, body io.Reader, header http.Header, acceptedErrorCodes []int) ([]byte, error) {
+	if time.Now().After(sys.tokenExpiry) {
+		if err := sys.refreshToken(); err != nil {
+			return nil, errors.Wrap(err, "failed to refresh oAuth token")
+		}
+	}
+	if header == nil {
+		header = http.Header{}
+	}
+	header.Set("Authorization", sys.token)
+	header.Set("Accept", "application/json; version=1.0")
+
+	var requestBody io.Reader
+	var requestBodyCopy io.Reader
+	if body != nil {
+		closer := ioutil.NopCloser(body)
+		bodyBytes, _ := ioutil.ReadAll(closer)
+		requestBody = bytes.NewBuffer(bodyBytes)
+		requestBodyCopy = bytes.NewBuffer(bodyBytes)
+		defer closer.Close()
+	}
+	response, err := sys.client.SendRequest(method, fmt.Sprintf("%v/api%v", sys.serverURL, url), requestBody, header, nil)
+	if err != nil && (response == nil || !piperutils.ContainsInt(acceptedErrorCodes, response.StatusCode)) {
+		sys.recordRequestDetailsInErrorCase(requestBodyCopy, response)
+		sys.logger.Errorf("HTTP request failed with error: %s", err)
+		return nil, err
+	}
+
+	data, _ := ioutil.ReadAll(response.Body)
+	sys.logger.Debugf("Valid response body: %v", string(data))
+	defer response.Body.Close()
+	return data, nil
+}
+
+func (sys *SystemInstance) recordRequestDetailsInErrorCase(requestBody io.Reader, response *http.Response) {
+	if requestBody != nil {
+		data, _ := ioutil.ReadAll(ioutil.NopCloser(requestBody))
+		sys.logger.Errorf("Request body: %s", data)
+	}
+	if response != nil && response.Body != nil {
+		data, _ := ioutil.ReadAll(response.Body)
+		sys.logger.Errorf("Response body: %s", data)
+		response.Body.Close()
+	}
+}
+
+// GetApplicationsByName returns the applications with the given name
+func (sys *SystemInstance) GetApplicationsByName(name string) ([]Application, error) {
+	sys.logger.Debugf("Getting applications with name %v...", name)
+	var response struct {
+		Applications []Application `json:"applications"`
+	}
+
+	data, err := sendRequest(sys, http.MethodGet, fmt.Sprintf("/applications?%v", url.Values{"name": {name}}.Encode()), nil, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "fetching application %v failed", name)
+	}
+
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse applications with name %v", name)
+	}
+	// the name filter matches substrings
+	applications := []Application{}
+	for _, application := range response.Applications {
+		if application.Name == name {
+			applications = append(applications, application)
+		}
+	}
+	return applications, nil
+}
+
+// CreateApplication creates a new application
+func (sys *SystemInstance) CreateApplication(name string) (Application, error) {
+	var application Application
+	jsonValue, err := json.Marshal(map[string]interface{}{
+		"name":        name,
+		"criticality": 3,
+		"rules":       []interface{}{},
+	})
+	if err != nil {
+		return application, errors.Wrap(err, "failed to marshal application data")
+	}
+
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	data, err := sendRequest(sys, http.MethodPost, "/applications", bytes.NewBuffer(jsonValue), header)
+	if err != nil {
+		return application, errors.Wrapf(err, "failed to create application %v", name)
+	}
+
+	if err := json.Unmarshal(data, &application); err != nil {
+		return application, errors.Wrapf(err, "failed to parse created application %v", name)
+	}
+	return application, nil
+}
+
+// GetProjectsByName returns the projects with the given name
+func (sys *SystemInstance) GetProjectsByName(name string) ([]Project, error) {
+	sys.logger.Debugf("Getting projects with name %v...", name)
+	var response struct {
+		Projects []Project `json:"projects"`
+	}
+
+	data, err := sendRequestInternal(sys, http.MethodGet, fmt.Sprintf("/projects?%v", url.Values{"names": {name}}.Encode()), nil, nil, []int{404})
+	if err != nil {
+		return nil, errors.Wrapf(err, "fetching project %v failed", name)
+	}
+
+	// the response of a 404 has no body
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &response); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse projects with name %v", name)
+		}
+	}
+	if response.Projects == nil {
+		return []Project{}, nil
+	}
+	return response.Projects, nil
+}
+
+// CreateProject creates a new project which is assigned to the application, if provided, and to the groups
+func (sys *SystemInstance) CreateProject(name string, applicationID string, groups []string) (Project, error) {
+	var project Project
+	projectData := map[string]interface{}{
+		"name":   name,
+		"groups": groups,
+		"origin": "Piper",
+	}
+	if len(applicationID) > 0 {
+		projectData["applicationIds"] = []string{applicationID}
+	}
+	jsonValue, err := json.Marshal(projectData)
+	if err != nil {
+		return project, errors.Wrap(err, "failed to marshal project data")
+	}
+
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	data, err := sendRequest(sys, http.MethodPost, "/projects", bytes.NewBuffer(jsonValue), header)
+	if err != nil {
+		return project, errors.Wrapf(err, "failed to create project %v", name)
+	}
+
+	if err := json.Unmarshal(data, &project); err != nil {
+		return project, errors.Wrapf(err, "failed to parse created project %v", name)
+	}
+	return project, nil
+}
+
+// UploadProjectSourceCode uploads the zipped sources and returns the URL to be used for triggering the scan
+func (sys *SystemInstance) UploadProjectSourceCode(zipFile string) (string, error) {
+	sys.logger.Debug("Starting to upload files...")
+	data, err := sendRequest(sys, http.MethodPost, "/uploads", nil, nil)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to request upload URL")
+	}
+	var upload struct {
+		URL string `json:"url"`
+	}
+	if err := json.Unmarshal(data, &upload); err != nil {
+		return "", errors.Wrap(err, "failed to parse upload URL")
+	}
+	if len(upload.URL) == 0 {
+		return "", errors.New("no upload URL received")
+	}
+
+	// the upload URL is pre-signed, thus no authorization header is sent
+	response, err := sys.client.UploadRequest(http.MethodPut, upload.URL, zipFile, "", http.Header{}, nil, "binary")
+	if err != nil {
+		sys.recordRequestDetailsInErrorCase(nil, response)
+		return "", errors.Wrap(err, "failed to upload zipped sources")
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to upload zipped sources, status: %v", response.Status)
+	}
+	return upload.URL, nil
+}
+
+// ScanProject triggers a scan of the uploaded sources with the given scanners
+func (sys *SystemInstance) ScanProject(projectID, uploadURL, branch string, configurations []ScanConfiguration) (Scan, error) {
+	var scan Scan
+	jsonValue, err := json.Marshal(map[string]interface{}{
+		"type": "upload",
+		"handler": map[string]string{
+			"uploadUrl": uploadURL,
+			"branch":    branch,
+		},
+		"project": map[string]string{"id": projectID},
+		"config":  configurations,
+		"tags":    map[string]string{"origin": "Piper"},
+	})
+	if err != nil {
+		return scan, errors.Wrap(err, "failed to marshal scan data")
+	}
+
+	header := http.Header{}
+	header.Set("Content-Type", "application/json")
+	data, err := sendRequest(sys, http.MethodPost, "/scans", bytes.NewBuffer(jsonValue), header)
+	if err != nil {
+		return scan, errors.Wrapf(err, "failed to trigger scan of project %v", projectID)
+	}
+
+	if err := json.Unmarshal(data, &scan); err != nil {
+		return scan, errors.Wrapf(err, "failed to parse triggered scan of project %v", projectID)
+	}
+	return scan, nil
+}
+
+// GetScan returns the scan including its status
+func (sys *SystemInstance) GetScan(scanID string) (Scan, error) {
+	var scan Scan
+	data, err := sendRequest(sys, http.MethodGet, fmt.Sprintf("/scans/%v", scanID), nil, nil)
+	if err != nil {
+		return scan, errors.Wrapf(err, "fetching scan %v failed", scanID)
+	}
+
+	if err := json.Unmarshal(data, &scan); err != nil {
+		return scan, errors.Wrapf(err, "failed to parse scan %v", scanID)
+	}
+	return scan, nil
+}
+
+// GetLastScans returns the latest completed scans of a branch of the project, newest first
+func (sys *SystemInstance) GetLastScans(projectID, branch string, limit int) ([]Scan, error) {
+	var response struct {
+		Scans []Scan `json:"scans"`
+	}
+	query := url.Values{
+		"project-id": {projectID},
+		"statuses":   {ScanStatusCompleted},
+		"sort":       {"-created_at"},
+		"limit":      {fmt.Sprint(limit)},
+	}
+	if len(branch) > 0 {
+		query.Set("branch", branch)
+	}
+	data, err := sendRequest(sys, http.MethodGet, fmt.Sprintf("/scans?%v", query.Encode()), nil, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "fetching scans of project %v failed", projectID)
+	}
+
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse scans of project %v", projectID)
+	}
+	return response.Scans, nil
+}
+
+// GetResults returns all results of all scanners of the scan
+func (sys *SystemInstance) GetResults(scanID string) ([]ScanResult, error) {
+	results := []ScanResult{}
+	for offset := 0; ; offset += resultsPageSize {
+		query := url.Values{
+			"scan-id": {scanID},
+			"limit":   {fmt.Sprint(resultsPageSize)},
+			"offset":  {fmt.Sprint(offset)},
+		}
+		data, err := sendRequest(sys, http.MethodGet, fmt.Sprintf("/results?%v", query.Encode()), nil, nil)
+		if err != nil {
+			return nil, errors.Wrapf(err, "fetching results of scan %v failed", scanID)
+		}
+		var page ScanResults
+		if err := json.Unmarshal(data, &page); err != nil {
+			return nil, errors.Wrapf(err, "failed to parse results of scan %v", scanID)
+		}
+		results = append(results, page.Results...)
+		if len(page.Results) == 0 || len(results) >= page.TotalCount {
+			break
+		}
+	}
+	return results, nil
+}
+
+// DeepLink returns the link to the scan in the Checkmarx One UI
+func (sys *SystemInstance) DeepLink(projectID, scanID, branch string) string {
+	return fmt.Sprintf("%v/projects/%v/scans?id=%v&branch=%v", sys.serverURL, projectID, scanID, url.QueryEscape(branch))
+}
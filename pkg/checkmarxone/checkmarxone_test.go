@@ -0,0 +1,258 @@
+package checkmarxone
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	piperHttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/stretchr/testify/assert"
+)
+
+type senderMock struct {
+	// responses maps the beginning of the URL to the response body
+	responses      map[string]string
+	httpStatusCode int
+	requests       []string
+	requestBodies  []string
+	headers        []http.Header
+	uploadURL      string
+	uploadHeader   http.Header
+}
+
+func (sm *senderMock) SendRequest(method, url string, body io.Reader, header http.Header, cookies []*http.Cookie) (*http.Response, error) {
+	sm.requests = append(sm.requests, method+" "+url)
+	sm.headers = append(sm.headers, header)
+	requestBody := ""
+	if body != nil {
+		buf := new(bytes.Buffer)
+		buf.ReadFrom(body)
+		requestBody = buf.String()
+	}
+	sm.requestBodies = append(sm.requestBodies, requestBody)
+	if sm.httpStatusCode > 399 {
+		return &http.Response{StatusCode: sm.httpStatusCode, Body: ioutil.NopCloser(strings.NewReader(""))}, fmt.Errorf("http error %v", sm.httpStatusCode)
+	}
+	for prefix, response := range sm.responses {
+		if strings.HasPrefix(method+" "+url, prefix) {
+			return &http.Response{StatusCode: http.StatusOK, Body: ioutil.NopCloser(strings.NewReader(response))}, nil
+		}
+	}
+	return &http.Response{StatusCode: http.StatusNotFound, Body: ioutil.NopCloser(strings.NewReader(""))}, fmt.Errorf("http error 404")
+}
+
+func (sm *senderMock) UploadFile(url, file, fieldName string, header http.Header, cookies []*http.Cookie, uploadType string) (*http.Response, error) {
+	return sm.UploadRequest(http.MethodPost, url, file, fieldName, header, cookies, uploadType)
+}
+
+func (sm *senderMock) UploadRequest(method, url, file, fieldName string, header http.Header, cookies []*http.Cookie, uploadType string) (*http.Response, error) {
+	sm.uploadURL = url
+	sm.uploadHeader = header
+	return &http.Response{StatusCode: http.StatusOK, Status: "200 OK", Body: ioutil.NopCloser(strings.NewReader(""))}, nil
+}
+
+func (sm *senderMock) Upload(_ piperHttp.UploadRequestData) (*http.Response, error) {
+	return &http.Response{}, fmt.Errorf("not implemented")
+}
+
+func (sm *senderMock) SetOptions(opts piperHttp.ClientOptions) {}
+
+func newTestSystem(responses map[string]string) (*SystemInstance, *senderMock) {
+	client := &senderMock{responses: responses}
+	sys := &SystemInstance{
+		serverURL:   "https://cx1.server.com",
+		iamURL:      "https://iam.server.com",
+		tenant:      "tenant",
+		apiKey:      "apiKey",
+		token:       "Bearer token",
+		tokenExpiry: time.Now().Add(time.Hour),
+		client:      client,
+		logger:      log.Entry().WithField("package", "SAP/jenkins-library/pkg/checkmarxone_test"),
+	}
+	return sys, client
+}
+
+func TestNewSystemInstance(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		client := &senderMock{responses: map[string]string{
+			"POST https://iam.server.com/auth/realms/tenant/protocol/openid-connect/token": `{"token_type": "Bearer", "access_token": "abc", "expires_in": 1800}`,
+		}}
+
+		sys, err := NewSystemInstance(client, "https://cx1.server.com/", "https://iam.server.com", "tenant", "apiKey")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Bearer abc", sys.token)
+		assert.Equal(t, "https://cx1.server.com", sys.serverURL)
+		assert.True(t, sys.tokenExpiry.After(time.Now()))
+		assert.Equal(t, "client_id=ast-app&grant_type=refresh_token&refresh_token=apiKey", client.requestBodies[0])
+	})
+
+	t.Run("invalid API key", func(t *testing.T) {
+		client := &senderMock{httpStatusCode: 400}
+
+		_, err := NewSystemInstance(client, "https://cx1.server.com", "https://iam.server.com", "tenant", "apiKey")
+
+		assert.EqualError(t, err, "Error fetching oAuth token: http error 400")
+	})
+}
+
+func TestSendRequest(t *testing.T) {
+	t.Run("token is sent", func(t *testing.T) {
+		sys, client := newTestSystem(map[string]string{"GET https://cx1.server.com/api/test": `{}`})
+
+		_, err := sendRequest(sys, http.MethodGet, "/test", nil, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Bearer token", client.headers[0].Get("Authorization"))
+	})
+
+	t.Run("expired token is refreshed", func(t *testing.T) {
+		sys, client := newTestSystem(map[string]string{
+			"POST https://iam.server.com/":        `{"token_type": "Bearer", "access_token": "new", "expires_in": 1800}`,
+			"GET https://cx1.server.com/api/test": `{}`,
+		})
+		sys.tokenExpiry = time.Now().Add(-time.Minute)
+
+		_, err := sendRequest(sys, http.MethodGet, "/test", nil, nil)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(client.requests))
+		assert.Equal(t, "Bearer new", client.headers[1].Get("Authorization"))
+	})
+}
+
+func TestGetProjectsByName(t *testing.T) {
+	t.Run("found", func(t *testing.T) {
+		sys, client := newTestSystem(map[string]string{"GET https://cx1.server.com/api/projects": `{"totalCount": 1, "projects": [{"id": "p-1", "name": "my-project", "groups": ["g-1"]}]}`})
+
+		projects, err := sys.GetProjectsByName("my-project")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []Project{{ID: "p-1", Name: "my-project", Groups: []string{"g-1"}}}, projects)
+		assert.Equal(t, "GET https://cx1.server.com/api/projects?names=my-project", client.requests[0])
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		sys, _ := newTestSystem(map[string]string{"GET https://cx1.server.com/api/projects": `{"totalCount": 0, "projects": null}`})
+
+		projects, err := sys.GetProjectsByName("my-project")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []Project{}, projects)
+	})
+
+	t.Run("invalid response", func(t *testing.T) {
+		sys, _ := newTestSystem(map[string]string{"GET https://cx1.server.com/api/projects": `<html>maintenance</html>`})
+
+		_, err := sys.GetProjectsByName("my-project")
+
+		assert.ErrorContains(t, err, "failed to parse projects with name my-project")
+	})
+}
+
+func TestGetApplicationsByName(t *testing.T) {
+	sys, _ := newTestSystem(map[string]string{"GET https://cx1.server.com/api/applications": `{"applications": [{"id": "a-1", "name": "my-app"}, {"id": "a-2", "name": "my-app-2"}]}`})
+
+	applications, err := sys.GetApplicationsByName("my-app")
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Application{{ID: "a-1", Name: "my-app"}}, applications)
+}
+
+func TestCreateProject(t *testing.T) {
+	sys, client := newTestSystem(map[string]string{"POST https://cx1.server.com/api/projects": `{"id": "p-2", "name": "new-project"}`})
+
+	project, err := sys.CreateProject("new-project", "a-1", []string{"g-1"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "p-2", project.ID)
+	assert.JSONEq(t, `{"name": "new-project", "groups": ["g-1"], "origin": "Piper", "applicationIds": ["a-1"]}`, client.requestBodies[0])
+}
+
+func TestUploadProjectSourceCode(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		sys, client := newTestSystem(map[string]string{"POST https://cx1.server.com/api/uploads": `{"url": "https://upload.server.com/signed"}`})
+
+		uploadURL, err := sys.UploadProjectSourceCode("sources.zip")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "https://upload.server.com/signed", uploadURL)
+		assert.Equal(t, "https://upload.server.com/signed", client.uploadURL)
+		assert.Empty(t, client.uploadHeader.Get("Authorization"))
+	})
+
+	t.Run("no upload URL", func(t *testing.T) {
+		sys, _ := newTestSystem(map[string]string{"POST https://cx1.server.com/api/uploads": `{}`})
+
+		_, err := sys.UploadProjectSourceCode("sources.zip")
+
+		assert.EqualError(t, err, "no upload URL received")
+	})
+}
+
+func TestScanProject(t *testing.T) {
+	sys, client := newTestSystem(map[string]string{"POST https://cx1.server.com/api/scans": `{"id": "s-1", "status": "Queued"}`})
+
+	scan, err := sys.ScanProject("p-1", "https://upload.server.com/signed", "main", []ScanConfiguration{{Type: "sast", Value: map[string]string{"incremental": "false"}}, {Type: "sca", Value: map[string]string{}}})
+
+	assert.NoError(t, err)
+	assert.Equal(t, Scan{ID: "s-1", Status: "Queued"}, scan)
+	assert.JSONEq(t, `{
+		"type": "upload",
+		"handler": {"uploadUrl": "https://upload.server.com/signed", "branch": "main"},
+		"project": {"id": "p-1"},
+		"config": [{"type": "sast", "value": {"incremental": "false"}}, {"type": "sca", "value": {}}],
+		"tags": {"origin": "Piper"}
+	}`, client.requestBodies[0])
+}
+
+func TestGetScan(t *testing.T) {
+	t.Run("invalid response", func(t *testing.T) {
+		sys, _ := newTestSystem(map[string]string{"GET https://cx1.server.com/api/scans/s-1": `{"id": `})
+
+		_, err := sys.GetScan("s-1")
+
+		assert.ErrorContains(t, err, "failed to parse scan s-1")
+	})
+}
+
+func TestGetLastScans(t *testing.T) {
+	sys, client := newTestSystem(map[string]string{"GET https://cx1.server.com/api/scans": `{"scans": [{"id": "s-1", "status": "Completed", "branch": "main"}]}`})
+
+	scans, err := sys.GetLastScans("p-1", "main", 1)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Scan{{ID: "s-1", Status: "Completed", Branch: "main"}}, scans)
+	assert.Equal(t, "GET https://cx1.server.com/api/scans?branch=main&limit=1&project-id=p-1&sort=-created_at&statuses=Completed", client.requests[0])
+}
+
+func TestGetResults(t *testing.T) {
+	t.Run("single page", func(t *testing.T) {
+		sys, _ := newTestSystem(map[string]string{"GET https://cx1.server.com/api/results": `{"totalCount": 2, "results": [{"type": "sast", "id": "r-1"}, {"type": "sca", "id": "CVE-2022-1"}]}`})
+
+		results, err := sys.GetResults("s-1")
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(results))
+	})
+
+	t.Run("error", func(t *testing.T) {
+		sys, _ := newTestSystem(map[string]string{})
+
+		_, err := sys.GetResults("s-1")
+
+		assert.EqualError(t, err, "fetching results of scan s-1 failed: http error 404")
+	})
+}
+
+func TestDeepLink(t *testing.T) {
+	sys, _ := newTestSystem(nil)
+
+	assert.Equal(t, "https://cx1.server.com/projects/p-1/scans?id=s-1&branch=feature%2Fabc", sys.DeepLink("p-1", "s-1", "feature/abc"))
+}
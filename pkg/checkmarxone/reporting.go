@@ -0,0 +1,175 @@
+package checkmarxone
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/checkmarx"
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/reporting"
+	"github.com/pkg/errors"
+)
+
+var auditStates = []struct{ key, description string }{
+	{"Issues", "issues"},
+	{"NotFalsePositive", "not false positive issues"},
+	{"NotExploitable", "not exploitable issues"},
+	{"Confirmed", "confirmed issues"},
+	{"Urgent", "urgent issues"},
+	{"ProposedNotExploitable", "proposed not exploitable issues"},
+	{"ToVerify", "to verify issues"},
+}
+
+// CreateCustomReport creates the Checkmarx One ScanReport, insecure and neutral contain the threshold evaluation of all scan types
+func CreateCustomReport(data map[string]interface{}, counts map[string]int, insecure, neutral []string) reporting.ScanReport {
+	deepLink := fmt.Sprintf(`<a href="%v" target="_blank">Link to scan in Checkmarx One UI</a>`, data["DeepLink"])
+
+	scanReport := reporting.ScanReport{
+		ReportTitle: "Checkmarx One Report",
+		Subheaders: []reporting.Subheader{
+			{Description: "Project name", Details: fmt.Sprint(data["ProjectName"])},
+			{Description: "Project ID", Details: fmt.Sprint(data["ProjectId"])},
+			{Description: "Branch", Details: fmt.Sprint(data["Branch"])},
+			{Description: "Scan ID", Details: fmt.Sprint(data["ScanId"])},
+			{Description: "Groups", Details: fmt.Sprint(data["Team"])},
+			{Description: "Scan start", Details: fmt.Sprint(data["ScanStart"])},
+			{Description: "Scan end", Details: fmt.Sprint(data["ScanTime"])},
+			{Description: "Scan types", Details: fmt.Sprint(data["ScanType"])},
+			{Description: "Deep link", Details: deepLink},
+		},
+		Overview:   []reporting.OverviewRow{},
+		ReportTime: time.Now(),
+	}
+
+	for _, issue := range insecure {
+		scanReport.Overview = append(scanReport.Overview, reporting.OverviewRow{Description: issue, Style: reporting.Red})
+	}
+	for _, issue := range neutral {
+		scanReport.Overview = append(scanReport.Overview, reporting.OverviewRow{Description: issue})
+	}
+
+	detailTable := reporting.ScanDetailTable{
+		Headers: []string{
+			"KPI",
+			"Count",
+		},
+		WithCounter: false,
+	}
+	for _, severity := range []string{"High", "Medium", "Low", "Information"} {
+		values, _ := data[severity].(map[string]int)
+		for _, state := range auditStates {
+			row := reporting.ScanRow{}
+			row.AddColumn(fmt.Sprintf("SAST %v %v", strings.ToLower(severity), state.description), 0)
+			row.AddColumn(fmt.Sprint(values[state.key]), 0)
+			detailTable.Rows = append(detailTable.Rows, row)
+		}
+	}
+	for _, scanType := range []string{ScanTypeSCA, ScanTypeKICS} {
+		row := reporting.ScanRow{}
+		row.AddColumn(fmt.Sprintf("%v issues", strings.ToUpper(scanType)), 0)
+		row.AddColumn(fmt.Sprint(counts[scanType]), 0)
+		detailTable.Rows = append(detailTable.Rows, row)
+	}
+	scanReport.DetailTable = detailTable
+
+	return scanReport
+}
+
+// CreateJSONReport creates the JSON compliance report which has the same structure as the one of Checkmarx SAST
+func CreateJSONReport(data map[string]interface{}) checkmarx.CheckmarxReportData {
+	jsonReport := checkmarx.CreateJSONReport(data)
+	jsonReport.ToolName = "checkmarxone"
+	return jsonReport
+}
+
+// WriteJSONReport writes the JSON compliance report into the reports directory of the workspace
+func WriteJSONReport(jsonReport checkmarx.CheckmarxReportData, workspace string) ([]piperutils.Path, error) {
+	utils := piperutils.Files{}
+	reportPaths := []piperutils.Path{}
+
+	reportsDirectory := filepath.Join(workspace, ReportsDirectory)
+	if err := utils.MkdirAll(reportsDirectory, 0777); err != nil {
+		return reportPaths, errors.Wrap(err, "failed to create report directory")
+	}
+
+	jsonComplianceReportPath := filepath.Join(reportsDirectory, "piper_checkmarxone_report.json")
+	file, _ := json.Marshal(jsonReport)
+	if err := utils.FileWrite(jsonComplianceReportPath, file, 0666); err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return reportPaths, errors.Wrap(err, "failed to write Checkmarx One JSON compliance report")
+	}
+	reportPaths = append(reportPaths, piperutils.Path{Name: "Checkmarx One JSON Compliance Report", Target: jsonComplianceReportPath})
+
+	return reportPaths, nil
+}
+
+// WriteSarif writes the SARIF file into the reports directory of the workspace
+func WriteSarif(sarif format.SARIF, workspace string) ([]piperutils.Path, error) {
+	utils := piperutils.Files{}
+	reportPaths := []piperutils.Path{}
+
+	reportsDirectory := filepath.Join(workspace, ReportsDirectory)
+	if err := utils.MkdirAll(reportsDirectory, 0777); err != nil {
+		return reportPaths, errors.Wrap(err, "failed to create report directory")
+	}
+
+	// HTML characters will most likely be present: we need to use encode: create a buffer to hold JSON data
+	buffer := new(bytes.Buffer)
+	bufEncoder := json.NewEncoder(buffer)
+	bufEncoder.SetEscapeHTML(false)
+	bufEncoder.SetIndent("", "  ")
+	bufEncoder.Encode(sarif)
+
+	sarifReportPath := filepath.Join(reportsDirectory, "result.sarif")
+	log.Entry().Info("Writing file to disk: ", sarifReportPath)
+	if err := utils.FileWrite(sarifReportPath, buffer.Bytes(), 0666); err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return reportPaths, errors.Wrap(err, "failed to write Checkmarx One SARIF report")
+	}
+	reportPaths = append(reportPaths, piperutils.Path{Name: "Checkmarx One SARIF Report", Target: sarifReportPath})
+
+	return reportPaths, nil
+}
+
+// WriteCustomReports writes the HTML report and the JSON report used by step pipelineCreateSummary
+func WriteCustomReports(scanReport reporting.ScanReport, projectName, projectID, workspace string) ([]piperutils.Path, error) {
+	utils := piperutils.Files{}
+	reportPaths := []piperutils.Path{}
+
+	reportsDirectory := filepath.Join(workspace, ReportsDirectory)
+	if err := utils.MkdirAll(reportsDirectory, 0777); err != nil {
+		return reportPaths, errors.Wrap(err, "failed to create report directory")
+	}
+
+	// ignore templating errors since template is in our hands and issues will be detected with the automated tests
+	htmlReport, _ := scanReport.ToHTML()
+	htmlReportPath := filepath.Join(reportsDirectory, "piper_checkmarxone_report.html")
+	if err := utils.FileWrite(htmlReportPath, htmlReport, 0666); err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return reportPaths, errors.Wrap(err, "failed to write html report")
+	}
+	reportPaths = append(reportPaths, piperutils.Path{Name: "Checkmarx One Report", Target: htmlReportPath})
+
+	// JSON reports are used by step pipelineCreateSummary in order to e.g. prepare an issue creation in GitHub
+	// ignore JSON errors since structure is in our hands
+	jsonReport, _ := scanReport.ToJSON()
+	stepReportDirectory := filepath.Join(workspace, reporting.StepReportDirectory)
+	if exists, _ := utils.DirExists(stepReportDirectory); !exists {
+		if err := utils.MkdirAll(stepReportDirectory, 0777); err != nil {
+			return reportPaths, errors.Wrap(err, "failed to create reporting directory")
+		}
+	}
+	reportSha := fmt.Sprintf("%x", sha1.Sum([]byte(strings.Join([]string{projectName, projectID}, ","))))
+	if err := utils.FileWrite(filepath.Join(stepReportDirectory, fmt.Sprintf("checkmarxOneExecuteScan_sast_%v.json", reportSha)), jsonReport, 0666); err != nil {
+		return reportPaths, errors.Wrap(err, "failed to write json report")
+	}
+
+	return reportPaths, nil
+}
@@ -0,0 +1,61 @@
+package checkmarxone
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/reporting"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateCustomReport(t *testing.T) {
+	t.Parallel()
+	results := DetailedResults(Project{ID: "p-1", Name: "my-project"}, Scan{ID: "s-1", Branch: "main"}, FilterResults(testResults, []string{"sast"}), "https://link", false)
+
+	scanReport := CreateCustomReport(results, CountByType(testResults), []string{"High 50% <-- 50 % deviation"}, []string{"Medium 100%"})
+
+	assert.Equal(t, "Checkmarx One Report", scanReport.ReportTitle)
+	assert.Equal(t, reporting.Subheader{Description: "Branch", Details: "main"}, scanReport.Subheaders[2])
+	assert.Equal(t, 2, len(scanReport.Overview))
+	assert.Equal(t, "High 50% <-- 50 % deviation", scanReport.Overview[0].Description)
+	assert.Equal(t, 4*7+2, len(scanReport.DetailTable.Rows))
+	assert.Equal(t, "SAST high issues", scanReport.DetailTable.Rows[0].Columns[0].Content)
+	assert.Equal(t, "2", scanReport.DetailTable.Rows[0].Columns[1].Content)
+	assert.Equal(t, "SCA issues", scanReport.DetailTable.Rows[28].Columns[0].Content)
+	assert.Equal(t, "1", scanReport.DetailTable.Rows[28].Columns[1].Content)
+}
+
+func TestCreateJSONReport(t *testing.T) {
+	t.Parallel()
+	results := DetailedResults(Project{ID: "p-1", Name: "my-project"}, Scan{ID: "s-1"}, FilterResults(testResults, []string{"sast"}), "https://link", false)
+
+	jsonReport := CreateJSONReport(results)
+
+	assert.Equal(t, "checkmarxone", jsonReport.ToolName)
+	assert.Equal(t, "my-project", jsonReport.ProjectName)
+	assert.Equal(t, 2, jsonReport.HighTotal)
+	assert.Equal(t, 1, jsonReport.HighAudited)
+	assert.Equal(t, 1, jsonReport.LowAudited)
+}
+
+func TestWriteReports(t *testing.T) {
+	t.Parallel()
+	workspace := t.TempDir()
+	results := DetailedResults(Project{ID: "p-1", Name: "my-project"}, Scan{ID: "s-1"}, testResults, "https://link", false)
+
+	paths, err := WriteCustomReports(CreateCustomReport(results, nil, nil, nil), "my-project", "p-1", workspace)
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(workspace, "checkmarxOne", "piper_checkmarxone_report.html"), paths[0].Target)
+	stepReports, _ := filepath.Glob(filepath.Join(workspace, reporting.StepReportDirectory, "checkmarxOneExecuteScan_sast_*.json"))
+	assert.Equal(t, 1, len(stepReports))
+
+	paths, err = WriteSarif(ConvertToSarif(testResults, "https://link"), workspace)
+	assert.NoError(t, err)
+	sarif, _ := os.ReadFile(paths[0].Target)
+	assert.Contains(t, string(sarif), `"ruleId": "checkmarx-Java/1001"`)
+
+	paths, err = WriteJSONReport(CreateJSONReport(results), workspace)
+	assert.NoError(t, err)
+	assert.FileExists(t, paths[0].Target)
+}
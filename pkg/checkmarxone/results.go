@@ -0,0 +1,132 @@
+package checkmarxone
+
+import (
+	"strings"
+)
+
+// result states as reported by Checkmarx One
+const (
+	StateToVerify               = "TO_VERIFY"
+	StateNotExploitable         = "NOT_EXPLOITABLE"
+	StateProposedNotExploitable = "PROPOSED_NOT_EXPLOITABLE"
+	StateConfirmed              = "CONFIRMED"
+	StateUrgent                 = "URGENT"
+)
+
+// Severity returns the severity in the notation of Checkmarx SAST, i.e. High, Medium, Low or Information
+func Severity(severity string) string {
+	switch strings.ToUpper(severity) {
+	case "CRITICAL", "HIGH":
+		return "High"
+	case "MEDIUM":
+		return "Medium"
+	case "LOW":
+		return "Low"
+	}
+	return "Information"
+}
+
+// AuditState returns the audit state in the notation of Checkmarx SAST
+func AuditState(state string) string {
+	switch strings.ToUpper(state) {
+	case StateNotExploitable:
+		return "NotExploitable"
+	case StateConfirmed:
+		return "Confirmed"
+	case StateUrgent:
+		return "Urgent"
+	case StateProposedNotExploitable:
+		return "ProposedNotExploitable"
+	}
+	return "ToVerify"
+}
+
+// FilterResults returns the results produced by one of the given scanners
+func FilterResults(results []ScanResult, scanTypes []string) []ScanResult {
+	filtered := []ScanResult{}
+	for _, result := range results {
+		for _, scanType := range scanTypes {
+			if strings.EqualFold(result.Type, scanType) {
+				filtered = append(filtered, result)
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+// CountByType returns the number of results per scanner
+func CountByType(results []ScanResult) map[string]int {
+	counts := map[string]int{}
+	for _, result := range results {
+		counts[strings.ToLower(result.Type)]++
+	}
+	return counts
+}
+
+// DetailedResults aggregates the results in the structure used by step checkmarxExecuteScan,
+// so that thresholds and reports can be evaluated the same way for both platforms
+func DetailedResults(project Project, scan Scan, results []ScanResult, deepLink string, lowPerQuery bool) map[string]interface{} {
+	resultMap := map[string]interface{}{}
+	resultMap["InitiatorName"] = scan.Initiator
+	resultMap["Owner"] = scan.Initiator
+	resultMap["ScanId"] = scan.ID
+	resultMap["ProjectId"] = project.ID
+	resultMap["ProjectName"] = project.Name
+	resultMap["Team"] = strings.Join(project.Groups, ", ")
+	resultMap["TeamFullPathOnReportDate"] = strings.Join(project.Groups, ", ")
+	resultMap["ScanStart"] = scan.CreatedAt
+	resultMap["ScanTime"] = scan.UpdatedAt
+	resultMap["LinesOfCodeScanned"] = 0
+	resultMap["FilesScanned"] = 0
+	resultMap["CheckmarxVersion"] = "Checkmarx One"
+	resultMap["ScanType"] = strings.Join(scan.Engines, ", ")
+	resultMap["Preset"] = ""
+	resultMap["DeepLink"] = deepLink
+	resultMap["ReportCreationTime"] = scan.UpdatedAt
+	resultMap["Branch"] = scan.Branch
+	resultMap["High"] = map[string]int{}
+	resultMap["Medium"] = map[string]int{}
+	resultMap["Low"] = map[string]int{}
+	resultMap["Information"] = map[string]int{}
+
+	lowPerQueryMap := map[string]map[string]int{}
+	for _, result := range results {
+		severity := Severity(result.Severity)
+		auditState := AuditState(result.State)
+		submap := resultMap[severity].(map[string]int)
+		submap["Issues"]++
+		submap[auditState]++
+		// Checkmarx One has no separate false positive flag, 'not exploitable' is used instead
+		if auditState != "NotExploitable" {
+			submap["NotFalsePositive"]++
+		}
+
+		if lowPerQuery && severity == "Low" {
+			query := queryName(result)
+			if lowPerQueryMap[query] == nil {
+				lowPerQueryMap[query] = map[string]int{}
+			}
+			lowPerQueryMap[query]["Issues"]++
+			lowPerQueryMap[query][auditState]++
+			if auditState != "NotExploitable" {
+				lowPerQueryMap[query]["NotFalsePositive"]++
+			}
+		}
+	}
+	if lowPerQuery {
+		resultMap["LowPerQuery"] = lowPerQueryMap
+	}
+	return resultMap
+}
+
+// queryName returns the name of the rule which produced the result
+func queryName(result ScanResult) string {
+	switch {
+	case len(result.Data.QueryName) > 0:
+		return result.Data.QueryName
+	case len(result.Data.PackageIdentifier) > 0:
+		return result.ID
+	}
+	return result.Description
+}
@@ -0,0 +1,67 @@
+package checkmarxone
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testResults = []ScanResult{
+	{Type: "sast", ID: "r-1", SimilarityID: "123", Severity: "HIGH", State: "TO_VERIFY", Data: ResultData{QueryID: "1001", QueryName: "SQL_Injection", LanguageName: "Java", Nodes: []ResultNode{{FileName: "/src/Main.java", Line: 10, Column: 5}, {FileName: "/src/Dao.java", Line: 20, Column: 7}}}, VulnerabilityDetails: VulnerabilityDetails{CweID: 89}},
+	{Type: "sast", ID: "r-2", SimilarityID: "456", Severity: "HIGH", State: "NOT_EXPLOITABLE", Data: ResultData{QueryID: "1001", QueryName: "SQL_Injection", LanguageName: "Java", Nodes: []ResultNode{{FileName: "/src/Other.java", Line: 3, Column: 1}}}, VulnerabilityDetails: VulnerabilityDetails{CweID: 89}},
+	{Type: "sast", ID: "r-3", SimilarityID: "789", Severity: "LOW", State: "CONFIRMED", Data: ResultData{QueryID: "2002", QueryName: "Log_Forging", LanguageName: "Java"}},
+	{Type: "sast", ID: "r-4", SimilarityID: "790", Severity: "INFO", State: "TO_VERIFY", Data: ResultData{QueryID: "3003", QueryName: "Dead_Code", LanguageName: "Java"}},
+	{Type: "sca", ID: "CVE-2022-1234", SimilarityID: "sca-1", Severity: "MEDIUM", State: "TO_VERIFY", Description: "Prototype pollution", Data: ResultData{PackageIdentifier: "npm-lodash-4.17.15"}},
+	{Type: "kics", ID: "k-1", SimilarityID: "kics-1", Severity: "MEDIUM", State: "TO_VERIFY", Data: ResultData{QueryID: "k-1001", QueryName: "Container Running As Root", FileName: "/Dockerfile", Line: 1}},
+}
+
+func TestSeverity(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "High", Severity("CRITICAL"))
+	assert.Equal(t, "High", Severity("HIGH"))
+	assert.Equal(t, "Medium", Severity("medium"))
+	assert.Equal(t, "Low", Severity("LOW"))
+	assert.Equal(t, "Information", Severity("INFO"))
+}
+
+func TestAuditState(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "NotExploitable", AuditState("NOT_EXPLOITABLE"))
+	assert.Equal(t, "ProposedNotExploitable", AuditState("PROPOSED_NOT_EXPLOITABLE"))
+	assert.Equal(t, "Confirmed", AuditState("CONFIRMED"))
+	assert.Equal(t, "Urgent", AuditState("URGENT"))
+	assert.Equal(t, "ToVerify", AuditState("TO_VERIFY"))
+}
+
+func TestFilterResults(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, 4, len(FilterResults(testResults, []string{"sast"})))
+	assert.Equal(t, 2, len(FilterResults(testResults, []string{"sca", "kics"})))
+	assert.Equal(t, map[string]int{"sast": 4, "sca": 1, "kics": 1}, CountByType(testResults))
+}
+
+func TestDetailedResults(t *testing.T) {
+	t.Parallel()
+	project := Project{ID: "p-1", Name: "my-project", Groups: []string{"g-1"}}
+	scan := Scan{ID: "s-1", Branch: "main", Engines: []string{"sast"}, Initiator: "piper"}
+
+	t.Run("without low per query", func(t *testing.T) {
+		results := DetailedResults(project, scan, FilterResults(testResults, []string{"sast"}), "https://link", false)
+
+		assert.Equal(t, "s-1", results["ScanId"])
+		assert.Equal(t, "p-1", results["ProjectId"])
+		assert.Equal(t, "main", results["Branch"])
+		assert.Equal(t, "https://link", results["DeepLink"])
+		assert.Equal(t, map[string]int{"Issues": 2, "ToVerify": 1, "NotExploitable": 1, "NotFalsePositive": 1}, results["High"])
+		assert.Equal(t, map[string]int{}, results["Medium"])
+		assert.Equal(t, map[string]int{"Issues": 1, "Confirmed": 1, "NotFalsePositive": 1}, results["Low"])
+		assert.Equal(t, map[string]int{"Issues": 1, "ToVerify": 1, "NotFalsePositive": 1}, results["Information"])
+		assert.NotContains(t, results, "LowPerQuery")
+	})
+
+	t.Run("with low per query", func(t *testing.T) {
+		results := DetailedResults(project, scan, testResults, "https://link", true)
+
+		assert.Equal(t, map[string]map[string]int{"Log_Forging": {"Issues": 1, "Confirmed": 1, "NotFalsePositive": 1}}, results["LowPerQuery"])
+	})
+}
@@ -0,0 +1,209 @@
+package checkmarxone
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+)
+
+// ConvertToSarif converts the results of all scanners of a Checkmarx One scan into SARIF
+func ConvertToSarif(results []ScanResult, deepLink string) format.SARIF {
+	start := time.Now() // For the conversion start time
+	log.Entry().Debug("[SARIF] Now handling results.")
+
+	var sarif format.SARIF
+	sarif.Schema = "https://docs.oasis-open.org/sarif/sarif/v2.1.0/cos02/schemas/sarif-schema-2.1.0.json"
+	sarif.Version = "2.1.0"
+	var checkmarxRun format.Runs
+	checkmarxRun.ColumnKind = "utf16CodeUnits"
+	sarif.Runs = append(sarif.Runs, checkmarxRun)
+
+	rulesArray := []format.SarifRule{}
+	ruleIndices := map[string]int{}
+	cweIDs := map[string]bool{}
+	for _, cxResult := range results {
+		ruleID := sarifRuleID(cxResult)
+		if _, ok := ruleIndices[ruleID]; !ok {
+			ruleIndices[ruleID] = len(rulesArray)
+			rulesArray = append(rulesArray, sarifRule(ruleID, cxResult))
+		}
+		if cxResult.VulnerabilityDetails.CweID > 0 {
+			cweIDs[fmt.Sprint(cxResult.VulnerabilityDetails.CweID)] = true
+		}
+
+		result := format.Results{
+			RuleID:    ruleID,
+			RuleIndex: ruleIndices[ruleID],
+			Level:     "none",
+			Message:   &format.Message{Text: sarifMessage(cxResult)},
+		}
+
+		for k, node := range sarifNodes(cxResult) {
+			loc := format.Location{}
+			loc.PhysicalLocation.ArtifactLocation.URI = strings.TrimPrefix(node.FileName, "/")
+			loc.PhysicalLocation.Region.StartLine = node.Line
+			loc.PhysicalLocation.Region.EndLine = node.Line
+			loc.PhysicalLocation.Region.StartColumn = node.Column
+			// only the source is saved as location as in the web view
+			if k == 0 {
+				result.Locations = append(result.Locations, loc)
+			}
+
+			relatedLocation := format.RelatedLocation{ID: k + 1}
+			relatedLocation.PhysicalLocation.ArtifactLocation = loc.PhysicalLocation.ArtifactLocation
+			relatedLocation.PhysicalLocation.Region.StartLine = node.Line
+			relatedLocation.PhysicalLocation.Region.StartColumn = node.Column
+			result.RelatedLocations = append(result.RelatedLocations, relatedLocation)
+		}
+
+		result.PartialFingerprints.CheckmarxSimilarityID = cxResult.SimilarityID
+		result.PartialFingerprints.PrimaryLocationLineHash = cxResult.SimilarityID
+
+		props := new(format.SarifProperties)
+		props.InstanceID = cxResult.ID
+		props.CheckmarxSimilarityID = cxResult.SimilarityID
+		props.ToolSeverity = Severity(cxResult.Severity)
+		props.ToolSeverityIndex = severityIndex(cxResult.Severity)
+		switch props.ToolSeverity {
+		case "High", "Medium":
+			props.AuditRequirement = format.AUDIT_REQUIREMENT_GROUP_1_DESC
+			props.AuditRequirementIndex = format.AUDIT_REQUIREMENT_GROUP_1_INDEX
+		case "Low":
+			props.AuditRequirement = format.AUDIT_REQUIREMENT_GROUP_2_DESC
+			props.AuditRequirementIndex = format.AUDIT_REQUIREMENT_GROUP_2_INDEX
+		default:
+			props.AuditRequirement = format.AUDIT_REQUIREMENT_GROUP_3_DESC
+			props.AuditRequirementIndex = format.AUDIT_REQUIREMENT_GROUP_3_INDEX
+		}
+		props.ToolState = AuditState(cxResult.State)
+		props.ToolStateIndex = stateIndex(props.ToolState)
+		props.Audited = props.ToolState != "ToVerify"
+		props.RuleGUID = string(cxResult.Data.QueryID)
+		result.Properties = props
+
+		sarif.Runs[0].Results = append(sarif.Runs[0].Results, result)
+	}
+
+	log.Entry().Debug("[SARIF] Now handling driver object.")
+	tool := format.Tool{}
+	tool.Driver.Name = "Checkmarx One"
+	tool.Driver.InformationUri = "https://checkmarx.com/resource/documents/en/34965-68621-checkmarx-one.html"
+	tool.Driver.Rules = rulesArray
+	sarif.Runs[0].Tool = tool
+
+	sarif.Runs[0].AutomationDetails = &format.AutomationDetails{Id: deepLink}
+
+	taxonomy := format.Taxonomies{Name: "CWE", Organization: "MITRE"}
+	taxonomy.ShortDescription.Text = "The MITRE Common Weakness Enumeration"
+	taxa := piperutils.Keys(cweIDs)
+	sort.Strings(taxa)
+	for _, cweID := range taxa {
+		taxonomy.Taxa = append(taxonomy.Taxa, format.Taxa{Id: cweID})
+	}
+	sarif.Runs[0].Taxonomies = append(sarif.Runs[0].Taxonomies, taxonomy)
+
+	// Add a conversion object to highlight this isn't native SARIF
+	conversion := new(format.Conversion)
+	conversion.Tool.Driver.Name = "Piper Checkmarx One results to SARIF converter"
+	conversion.Tool.Driver.InformationUri = "https://github.com/SAP/jenkins-library"
+	conversion.Invocation.ExecutionSuccessful = true
+	conversion.Invocation.StartTimeUtc = start.Format("2006-01-02T15:04:05.000Z")
+	sarif.Runs[0].Conversion = conversion
+
+	return sarif
+}
+
+func sarifRuleID(result ScanResult) string {
+	switch strings.ToLower(result.Type) {
+	case ScanTypeSAST:
+		return fmt.Sprintf("checkmarx-%v/%v", result.Data.LanguageName, result.Data.QueryID)
+	case ScanTypeSCA:
+		return fmt.Sprintf("checkmarx-sca/%v", result.ID)
+	}
+	return fmt.Sprintf("checkmarx-%v/%v", strings.ToLower(result.Type), result.Data.QueryID)
+}
+
+func sarifRule(ruleID string, result ScanResult) format.SarifRule {
+	rule := format.SarifRule{ID: ruleID}
+	name := queryName(result)
+	words := strings.Split(name, "_")
+	for w := range words {
+		words[w] = piperutils.Title(strings.ToLower(words[w]))
+	}
+	rule.Name = strings.Join(words, "")
+	rule.ShortDescription = &format.Message{Text: name}
+	if len(result.Description) > 0 {
+		rule.FullDescription = &format.Message{Text: result.Description}
+	}
+	rule.Properties = &format.SarifRuleProperties{Tags: []string{strings.ToLower(result.Type)}}
+	if len(result.Data.Group) > 0 {
+		rule.Properties.Tags = append(rule.Properties.Tags, result.Data.Group)
+	}
+	if result.VulnerabilityDetails.CweID > 0 {
+		rule.Properties.Tags = append(rule.Properties.Tags, fmt.Sprintf("external/cwe/cwe-%v", result.VulnerabilityDetails.CweID))
+	}
+	switch severityIndex(result.Severity) {
+	case 0:
+		rule.Properties.SecuritySeverity = "0.0"
+	case 1:
+		rule.Properties.SecuritySeverity = "2.0"
+	case 2:
+		rule.Properties.SecuritySeverity = "5.0"
+	case 3:
+		rule.Properties.SecuritySeverity = "7.0"
+	default:
+		rule.Properties.SecuritySeverity = "10.0"
+	}
+	return rule
+}
+
+func sarifMessage(result ScanResult) string {
+	if strings.EqualFold(result.Type, ScanTypeSCA) {
+		return fmt.Sprintf("%v in package %v", result.ID, result.Data.PackageIdentifier)
+	}
+	return queryName(result)
+}
+
+// sarifNodes returns the locations of a result, SCA results have no location in the sources
+func sarifNodes(result ScanResult) []ResultNode {
+	if len(result.Data.Nodes) > 0 {
+		return result.Data.Nodes
+	}
+	if len(result.Data.FileName) > 0 {
+		return []ResultNode{{FileName: result.Data.FileName, Line: result.Data.Line}}
+	}
+	return nil
+}
+
+func severityIndex(severity string) int {
+	switch strings.ToUpper(severity) {
+	case "CRITICAL":
+		return 4
+	case "HIGH":
+		return 3
+	case "MEDIUM":
+		return 2
+	case "LOW":
+		return 1
+	}
+	return 0
+}
+
+func stateIndex(auditState string) int {
+	switch auditState {
+	case "NotExploitable":
+		return 1
+	case "Confirmed":
+		return 2
+	case "Urgent":
+		return 3
+	case "ProposedNotExploitable":
+		return 4
+	}
+	return 0
+}
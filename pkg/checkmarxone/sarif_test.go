@@ -0,0 +1,53 @@
+package checkmarxone
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertToSarif(t *testing.T) {
+	t.Parallel()
+
+	sarif := ConvertToSarif(testResults, "https://link")
+
+	assert.Equal(t, "2.1.0", sarif.Version)
+	assert.Equal(t, 6, len(sarif.Runs[0].Results))
+	assert.Equal(t, 5, len(sarif.Runs[0].Tool.Driver.Rules))
+	assert.Equal(t, "https://link", sarif.Runs[0].AutomationDetails.Id)
+
+	sqlInjection := sarif.Runs[0].Results[0]
+	assert.Equal(t, "checkmarx-Java/1001", sqlInjection.RuleID)
+	assert.Equal(t, 0, sqlInjection.RuleIndex)
+	assert.Equal(t, "SQL_Injection", sqlInjection.Message.Text)
+	assert.Equal(t, 1, len(sqlInjection.Locations))
+	assert.Equal(t, "src/Main.java", sqlInjection.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, 10, sqlInjection.Locations[0].PhysicalLocation.Region.StartLine)
+	assert.Equal(t, 2, len(sqlInjection.RelatedLocations))
+	assert.Equal(t, "123", sqlInjection.PartialFingerprints.CheckmarxSimilarityID)
+	assert.Equal(t, "High", sqlInjection.Properties.ToolSeverity)
+	assert.Equal(t, "ToVerify", sqlInjection.Properties.ToolState)
+	assert.False(t, sqlInjection.Properties.Audited)
+
+	notExploitable := sarif.Runs[0].Results[1]
+	assert.Equal(t, 0, notExploitable.RuleIndex)
+	assert.Equal(t, "NotExploitable", notExploitable.Properties.ToolState)
+	assert.Equal(t, 1, notExploitable.Properties.ToolStateIndex)
+	assert.True(t, notExploitable.Properties.Audited)
+
+	sca := sarif.Runs[0].Results[4]
+	assert.Equal(t, "checkmarx-sca/CVE-2022-1234", sca.RuleID)
+	assert.Equal(t, "CVE-2022-1234 in package npm-lodash-4.17.15", sca.Message.Text)
+	assert.Empty(t, sca.Locations)
+
+	kics := sarif.Runs[0].Results[5]
+	assert.Equal(t, "checkmarx-kics/k-1001", kics.RuleID)
+	assert.Equal(t, "Dockerfile", kics.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+
+	rule := sarif.Runs[0].Tool.Driver.Rules[0]
+	assert.Equal(t, "SqlInjection", rule.Name)
+	assert.Equal(t, "7.0", rule.Properties.SecuritySeverity)
+	assert.Contains(t, rule.Properties.Tags, "external/cwe/cwe-89")
+
+	assert.Equal(t, "89", sarif.Runs[0].Taxonomies[0].Taxa[0].Id)
+}
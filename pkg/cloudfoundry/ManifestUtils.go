@@ -41,19 +41,30 @@ func ReadManifest(name string) (Manifest, error) {
 
 	log.Entry().Infof("Reading manifest file  '%s'", name)
 
-	m := &manifest{self: make(map[string]interface{}), name: name, modified: false}
-
 	content, err := _readFile(name)
 	if err != nil {
-		return m, errors.Wrapf(err, "cannot read file '%v'", m.name)
+		return &manifest{self: make(map[string]interface{}), name: name, modified: false}, errors.Wrapf(err, "cannot read file '%v'", name)
 	}
 
-	err = yaml.Unmarshal(content, &m.self)
+	m, err := ParseManifest(name, content)
 	if err != nil {
-		return m, errors.Wrapf(err, "Cannot parse yaml file '%s': %s", m.name, string(content))
+		return m, err
 	}
 
-	log.Entry().Infof("Manifest file '%s' has been parsed", m.name)
+	log.Entry().Infof("Manifest file '%s' has been parsed", name)
+
+	return m, nil
+}
+
+// ParseManifest Parses the manifest content which has been read from the file denoted by 'name'
+func ParseManifest(name string, content []byte) (Manifest, error) {
+
+	m := &manifest{self: make(map[string]interface{}), name: name, modified: false}
+
+	err := yaml.Unmarshal(content, &m.self)
+	if err != nil {
+		return m, errors.Wrapf(err, "Cannot parse yaml file '%s': %s", m.name, string(content))
+	}
 
 	return m, nil
 }
@@ -0,0 +1,173 @@
+package cnbutils
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/buildpacks/lifecycle/platform"
+	"github.com/google/go-containerregistry/pkg/name"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/pkg/errors"
+)
+
+// RebaseResult describes the outcome of a rebase of an app image
+type RebaseResult struct {
+	// Digest is the digest of the app image after the rebase
+	Digest string
+	// RunImage is the digest reference of the run image the app image is based on after the rebase
+	RunImage string
+	// Rebased is false in case the app image was already based on the latest run image
+	Rebased bool
+}
+
+// Rebase replaces the run image layers of an app image created by the lifecycle with the layers of the latest version of
+// its run image without rebuilding the app layers, similar to the rebaser of the lifecycle. In case runImage is empty, the
+// run image recorded in the lifecycle metadata of the app image is used. The rebased image is pushed to all given tags,
+// an app image already based on the latest run image is pushed to them unchanged.
+func Rebase(appImage, runImage string, tags []string, options ...remote.Option) (RebaseResult, error) {
+	appRef, err := name.ParseReference(appImage)
+	if err != nil {
+		return RebaseResult{}, errors.Wrapf(err, "invalid app image reference '%s'", appImage)
+	}
+	app, err := remote.Image(appRef, options...)
+	if err != nil {
+		return RebaseResult{}, errors.Wrapf(err, "failed to retrieve app image '%s'", appImage)
+	}
+	appConfig, err := app.ConfigFile()
+	if err != nil {
+		return RebaseResult{}, errors.Wrapf(err, "failed to read config of app image '%s'", appImage)
+	}
+
+	rawMetadata, ok := appConfig.Config.Labels[platform.LayerMetadataLabel]
+	if !ok {
+		return RebaseResult{}, fmt.Errorf("app image '%s' has no label '%s', only images created by the lifecycle can be rebased", appImage, platform.LayerMetadataLabel)
+	}
+	metadata := platform.LayersMetadata{}
+	if err := json.Unmarshal([]byte(rawMetadata), &metadata); err != nil {
+		return RebaseResult{}, errors.Wrapf(err, "failed to parse label '%s' of app image '%s'", platform.LayerMetadataLabel, appImage)
+	}
+
+	if runImage == "" {
+		runImage, err = metadata.Stack.BestRunImageMirror(appRef.Context().RegistryStr())
+		if err != nil {
+			return RebaseResult{}, errors.Wrapf(err, "failed to determine run image of app image '%s'", appImage)
+		}
+	}
+	runRef, err := name.ParseReference(runImage)
+	if err != nil {
+		return RebaseResult{}, errors.Wrapf(err, "invalid run image reference '%s'", runImage)
+	}
+	run, err := remote.Image(runRef, options...)
+	if err != nil {
+		return RebaseResult{}, errors.Wrapf(err, "failed to retrieve run image '%s'", runImage)
+	}
+	runConfig, err := run.ConfigFile()
+	if err != nil {
+		return RebaseResult{}, errors.Wrapf(err, "failed to read config of run image '%s'", runImage)
+	}
+	runDigest, err := run.Digest()
+	if err != nil {
+		return RebaseResult{}, errors.Wrapf(err, "failed to get digest of run image '%s'", runImage)
+	}
+	if appStack, runStack := appConfig.Config.Labels[platform.StackIDLabel], runConfig.Config.Labels[platform.StackIDLabel]; appStack != runStack {
+		return RebaseResult{}, fmt.Errorf("stack of run image '%s' ('%s') does not match stack of app image '%s' ('%s')", runImage, runStack, appImage, appStack)
+	}
+	if len(runConfig.RootFS.DiffIDs) == 0 {
+		return RebaseResult{}, fmt.Errorf("run image '%s' has no layers", runImage)
+	}
+
+	result := RebaseResult{RunImage: runRef.Context().Digest(runDigest.String()).String()}
+	newTopLayer := runConfig.RootFS.DiffIDs[len(runConfig.RootFS.DiffIDs)-1].String()
+	if newTopLayer == metadata.RunImage.TopLayer {
+		log.Entry().Infof("App image '%s' is already based on the latest run image '%s'", appImage, result.RunImage)
+		digest, err := app.Digest()
+		if err != nil {
+			return RebaseResult{}, errors.Wrapf(err, "failed to get digest of app image '%s'", appImage)
+		}
+		result.Digest = digest.String()
+		// the app image is still tagged as requested, e.g. with the version of the current pipeline run
+		if err := pushImage(app, tags, options...); err != nil {
+			return RebaseResult{}, err
+		}
+		return result, nil
+	}
+
+	appLayers, err := appLayers(app, appConfig, metadata.RunImage.TopLayer)
+	if err != nil {
+		return RebaseResult{}, errors.Wrapf(err, "failed to determine app layers of app image '%s'", appImage)
+	}
+	addendums := []mutate.Addendum{}
+	for _, layer := range appLayers {
+		addendums = append(addendums, mutate.Addendum{Layer: layer})
+	}
+	rebased, err := mutate.Append(run, addendums...)
+	if err != nil {
+		return RebaseResult{}, errors.Wrap(err, "failed to append app layers to run image")
+	}
+	rebasedConfig, err := rebased.ConfigFile()
+	if err != nil {
+		return RebaseResult{}, errors.Wrap(err, "failed to read config of rebased image")
+	}
+
+	// the rebased image keeps the configuration of the app image, only the file system changes
+	metadata.RunImage = platform.RunImageMetadata{TopLayer: newTopLayer, Reference: result.RunImage}
+	newMetadata, err := json.Marshal(metadata)
+	if err != nil {
+		return RebaseResult{}, errors.Wrapf(err, "failed to marshal label '%s'", platform.LayerMetadataLabel)
+	}
+	newConfig := appConfig.DeepCopy()
+	newConfig.RootFS = rebasedConfig.RootFS
+	newConfig.History = rebasedConfig.History
+	newConfig.Config.Labels[platform.LayerMetadataLabel] = string(newMetadata)
+	if mixins, ok := runConfig.Config.Labels[platform.MixinsLabel]; ok {
+		newConfig.Config.Labels[platform.MixinsLabel] = mixins
+	}
+	rebased, err = mutate.ConfigFile(rebased, newConfig)
+	if err != nil {
+		return RebaseResult{}, errors.Wrap(err, "failed to set config of rebased image")
+	}
+
+	if err := pushImage(rebased, tags, options...); err != nil {
+		return RebaseResult{}, err
+	}
+
+	digest, err := rebased.Digest()
+	if err != nil {
+		return RebaseResult{}, errors.Wrap(err, "failed to get digest of rebased image")
+	}
+	result.Digest = digest.String()
+	result.Rebased = true
+	return result, nil
+}
+
+// pushImage pushes the image to all given tags
+func pushImage(image v1.Image, tags []string, options ...remote.Option) error {
+	for _, tag := range tags {
+		tagRef, err := name.ParseReference(tag)
+		if err != nil {
+			return errors.Wrapf(err, "invalid image reference '%s'", tag)
+		}
+		log.Entry().Infof("Pushing image '%s'", tag)
+		if err := remote.Write(tagRef, image, options...); err != nil {
+			return errors.Wrapf(err, "failed to push image '%s'", tag)
+		}
+	}
+	return nil
+}
+
+// appLayers returns the layers of the app image above the top layer of the run image it was built on
+func appLayers(app v1.Image, appConfig *v1.ConfigFile, runImageTopLayer string) ([]v1.Layer, error) {
+	layers, err := app.Layers()
+	if err != nil {
+		return nil, err
+	}
+	for i, diffID := range appConfig.RootFS.DiffIDs {
+		if diffID.String() == runImageTopLayer {
+			return layers[i+1:], nil
+		}
+	}
+	return nil, fmt.Errorf("top layer '%s' of the run image not found", runImageTopLayer)
+}
@@ -0,0 +1,165 @@
+package cnbutils
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	stdlog "log"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/buildpacks/lifecycle/platform"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/registry"
+	v1 "github.com/google/go-containerregistry/pkg/v1"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/random"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRegistry starts an in-memory container registry and returns its host
+func newTestRegistry(t *testing.T) string {
+	server := httptest.NewServer(registry.New(registry.Logger(stdlog.New(io.Discard, "", 0))))
+	t.Cleanup(server.Close)
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	return u.Host
+}
+
+func pushTestImage(t *testing.T, image v1.Image, reference string) {
+	ref, err := name.ParseReference(reference)
+	require.NoError(t, err)
+	require.NoError(t, remote.Write(ref, image))
+}
+
+func testRunImage(t *testing.T, stack string) v1.Image {
+	image, err := random.Image(64, 2)
+	require.NoError(t, err)
+	image, err = mutate.Config(image, v1.Config{Labels: map[string]string{platform.StackIDLabel: stack}})
+	require.NoError(t, err)
+	return image
+}
+
+// testAppImage creates an app image on top of the given run image like the exporter of the lifecycle does
+func testAppImage(t *testing.T, run v1.Image, runImage string) v1.Image {
+	appLayer, err := random.Layer(64, "application/vnd.docker.image.rootfs.diff.tar.gzip")
+	require.NoError(t, err)
+	app, err := mutate.AppendLayers(run, appLayer)
+	require.NoError(t, err)
+	runConfig, err := run.ConfigFile()
+	require.NoError(t, err)
+	metadata := platform.LayersMetadata{
+		RunImage: platform.RunImageMetadata{TopLayer: runConfig.RootFS.DiffIDs[1].String(), Reference: runImage},
+		Stack:    platform.StackMetadata{RunImage: platform.StackRunImageMetadata{Image: runImage}},
+	}
+	rawMetadata, err := json.Marshal(metadata)
+	require.NoError(t, err)
+	app, err = mutate.Config(app, v1.Config{
+		Entrypoint: []string{"/cnb/process/web"},
+		Labels:     map[string]string{platform.LayerMetadataLabel: string(rawMetadata), platform.StackIDLabel: "io.buildpacks.stacks.bionic"},
+	})
+	require.NoError(t, err)
+	return app
+}
+
+func TestRebase(t *testing.T) {
+	host := newTestRegistry(t)
+	runImage := fmt.Sprintf("%s/run:base", host)
+	appImage := fmt.Sprintf("%s/app:1.0.0", host)
+
+	oldRun := testRunImage(t, "io.buildpacks.stacks.bionic")
+	pushTestImage(t, oldRun, runImage)
+	app := testAppImage(t, oldRun, runImage)
+	pushTestImage(t, app, appImage)
+
+	t.Run("already based on latest run image", func(t *testing.T) {
+		versionTag := fmt.Sprintf("%s/app:1.0.1", host)
+
+		result, err := Rebase(appImage, "", []string{appImage, versionTag})
+
+		if assert.NoError(t, err) {
+			digest, _ := app.Digest()
+			assert.False(t, result.Rebased)
+			assert.Equal(t, digest.String(), result.Digest)
+
+			ref, _ := name.ParseReference(versionTag)
+			tagged, err := remote.Image(ref)
+			require.NoError(t, err)
+			taggedDigest, _ := tagged.Digest()
+			assert.Equal(t, digest, taggedDigest)
+		}
+	})
+
+	t.Run("rebase on patched run image", func(t *testing.T) {
+		newRun := testRunImage(t, "io.buildpacks.stacks.bionic")
+		pushTestImage(t, newRun, runImage)
+		latestTag := fmt.Sprintf("%s/app:latest", host)
+
+		result, err := Rebase(appImage, "", []string{appImage, latestTag})
+
+		require.NoError(t, err)
+		assert.True(t, result.Rebased)
+		newRunDigest, _ := newRun.Digest()
+		assert.Equal(t, fmt.Sprintf("%s/run@%s", host, newRunDigest), result.RunImage)
+
+		for _, tag := range []string{appImage, latestTag} {
+			ref, _ := name.ParseReference(tag)
+			rebased, err := remote.Image(ref)
+			require.NoError(t, err)
+			digest, _ := rebased.Digest()
+			assert.Equal(t, result.Digest, digest.String())
+
+			config, err := rebased.ConfigFile()
+			require.NoError(t, err)
+			newRunConfig, _ := newRun.ConfigFile()
+			appConfig, _ := app.ConfigFile()
+			assert.Equal(t, append(newRunConfig.RootFS.DiffIDs, appConfig.RootFS.DiffIDs[2]), config.RootFS.DiffIDs)
+			assert.Equal(t, []string{"/cnb/process/web"}, config.Config.Entrypoint)
+
+			metadata := platform.LayersMetadata{}
+			require.NoError(t, json.Unmarshal([]byte(config.Config.Labels[platform.LayerMetadataLabel]), &metadata))
+			assert.Equal(t, newRunConfig.RootFS.DiffIDs[1].String(), metadata.RunImage.TopLayer)
+			assert.Equal(t, result.RunImage, metadata.RunImage.Reference)
+			assert.Equal(t, runImage, metadata.Stack.RunImage.Image)
+		}
+	})
+
+	t.Run("explicit run image", func(t *testing.T) {
+		mirror := fmt.Sprintf("%s/mirror/run:base", host)
+		pushTestImage(t, testRunImage(t, "io.buildpacks.stacks.bionic"), mirror)
+
+		result, err := Rebase(appImage, mirror, []string{appImage})
+
+		if assert.NoError(t, err) {
+			assert.True(t, result.Rebased)
+			assert.Contains(t, result.RunImage, fmt.Sprintf("%s/mirror/run@sha256:", host))
+		}
+	})
+
+	t.Run("error - stack mismatch", func(t *testing.T) {
+		other := fmt.Sprintf("%s/other/run:base", host)
+		pushTestImage(t, testRunImage(t, "io.buildpacks.stacks.jammy"), other)
+
+		_, err := Rebase(appImage, other, []string{appImage})
+
+		assert.ErrorContains(t, err, "does not match stack of app image")
+	})
+
+	t.Run("error - not built by lifecycle", func(t *testing.T) {
+		plain := fmt.Sprintf("%s/plain:1.0.0", host)
+		pushTestImage(t, testRunImage(t, "io.buildpacks.stacks.bionic"), plain)
+
+		_, err := Rebase(plain, "", []string{plain})
+
+		assert.ErrorContains(t, err, "only images created by the lifecycle can be rebased")
+	})
+
+	t.Run("error - app image not found", func(t *testing.T) {
+		_, err := Rebase(fmt.Sprintf("%s/missing:1.0.0", host), "", nil)
+
+		assert.ErrorContains(t, err, "failed to retrieve app image")
+	})
+}
@@ -0,0 +1,127 @@
+package codeql
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/pkg/errors"
+)
+
+// sarifReport contains the parts of a SARIF report which are relevant for comparing alerts
+type sarifReport struct {
+	Runs []struct {
+		Results []struct {
+			RuleID  string `json:"ruleId"`
+			Message struct {
+				Text string `json:"text"`
+			} `json:"message"`
+			Locations []struct {
+				PhysicalLocation struct {
+					ArtifactLocation struct {
+						URI string `json:"uri"`
+					} `json:"artifactLocation"`
+					Region struct {
+						StartLine int `json:"startLine"`
+					} `json:"region"`
+				} `json:"physicalLocation"`
+			} `json:"locations"`
+			PartialFingerprints map[string]string `json:"partialFingerprints"`
+			Suppressions        []json.RawMessage `json:"suppressions"`
+		} `json:"results"`
+	} `json:"runs"`
+}
+
+// SarifAlert describes an alert reported in a SARIF report.
+type SarifAlert struct {
+	RuleID      string `json:"ruleId"`
+	Message     string `json:"message"`
+	Location    string `json:"location"`
+	Fingerprint string `json:"fingerprint,omitempty"`
+}
+
+// key identifies an alert across scans. The line hash calculated by CodeQL is stable against unrelated line shifts,
+// the location is only used if no fingerprint is available.
+func (a SarifAlert) key() string {
+	if len(a.Fingerprint) > 0 {
+		return a.RuleID + "|" + a.Fingerprint
+	}
+	return a.RuleID + "|" + a.Location + "|" + a.Message
+}
+
+// BaselineComparison contains the result of comparing the alerts of a scan against a baseline.
+type BaselineComparison struct {
+	BaselineRef    string       `json:"baselineRef"`
+	NewAlerts      []SarifAlert `json:"newAlerts"`
+	ExistingAlerts int          `json:"existingAlerts"`
+	FixedAlerts    int          `json:"fixedAlerts"`
+}
+
+// ReadSarifAlerts returns the unsuppressed alerts of a SARIF report.
+func ReadSarifAlerts(content []byte) ([]SarifAlert, error) {
+	report := sarifReport{}
+	if err := json.Unmarshal(content, &report); err != nil {
+		return nil, errors.Wrap(err, "failed to parse SARIF report")
+	}
+	alerts := []SarifAlert{}
+	for _, run := range report.Runs {
+		for _, result := range run.Results {
+			if len(result.Suppressions) > 0 {
+				continue
+			}
+			alert := SarifAlert{RuleID: result.RuleID, Message: result.Message.Text, Fingerprint: result.PartialFingerprints["primaryLocationLineHash"]}
+			if len(result.Locations) > 0 {
+				location := result.Locations[0].PhysicalLocation
+				alert.Location = fmt.Sprintf("%v:%v", location.ArtifactLocation.URI, location.Region.StartLine)
+			}
+			alerts = append(alerts, alert)
+		}
+	}
+	return alerts, nil
+}
+
+// CompareWithBaseline determines which of the current alerts are not contained in the baseline.
+func CompareWithBaseline(current, baseline []SarifAlert, baselineRef string) BaselineComparison {
+	baselineKeys := map[string]bool{}
+	for _, alert := range baseline {
+		baselineKeys[alert.key()] = true
+	}
+	currentKeys := map[string]bool{}
+	comparison := BaselineComparison{BaselineRef: baselineRef, NewAlerts: []SarifAlert{}}
+	for _, alert := range current {
+		currentKeys[alert.key()] = true
+		if baselineKeys[alert.key()] {
+			comparison.ExistingAlerts++
+		} else {
+			comparison.NewAlerts = append(comparison.NewAlerts, alert)
+		}
+	}
+	for key := range baselineKeys {
+		if !currentKeys[key] {
+			comparison.FixedAlerts++
+		}
+	}
+	sort.SliceStable(comparison.NewAlerts, func(i, j int) bool {
+		return comparison.NewAlerts[i].Location < comparison.NewAlerts[j].Location
+	})
+	return comparison
+}
+
+// WriteBaselineReport writes the result of the baseline comparison as JSON report.
+func WriteBaselineReport(comparison BaselineComparison, modulePath string, utils piperutils.FileUtils) ([]piperutils.Path, error) {
+	reportsDirectory := filepath.Join(modulePath, "codeql")
+	reportPath := filepath.Join(reportsDirectory, "piper_codeql_baseline_report.json")
+	if err := utils.MkdirAll(reportsDirectory, 0777); err != nil {
+		return nil, errors.Wrapf(err, "failed to create report directory")
+	}
+
+	file, _ := json.MarshalIndent(comparison, "", "  ")
+	if err := utils.FileWrite(reportPath, file, 0666); err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return nil, errors.Wrapf(err, "failed to write codeql baseline report")
+	}
+	return []piperutils.Path{{Name: "Codeql Baseline Comparison", Target: reportPath}}, nil
+}
@@ -0,0 +1,76 @@
+package codeql
+
+import (
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+const baselineSarif = `{"runs": [{"results": [
+	{"ruleId": "java/sql-injection", "message": {"text": "Query built from user input"}, "partialFingerprints": {"primaryLocationLineHash": "aaa:1"},
+	 "locations": [{"physicalLocation": {"artifactLocation": {"uri": "src/Dao.java"}, "region": {"startLine": 10}}}]},
+	{"ruleId": "java/path-injection", "message": {"text": "Path built from user input"}, "partialFingerprints": {"primaryLocationLineHash": "bbb:1"},
+	 "locations": [{"physicalLocation": {"artifactLocation": {"uri": "src/Files.java"}, "region": {"startLine": 20}}}]}
+]}]}`
+
+const pullRequestSarif = `{"runs": [{"results": [
+	{"ruleId": "java/sql-injection", "message": {"text": "Query built from user input"}, "partialFingerprints": {"primaryLocationLineHash": "aaa:1"},
+	 "locations": [{"physicalLocation": {"artifactLocation": {"uri": "src/Dao.java"}, "region": {"startLine": 12}}}]},
+	{"ruleId": "java/xss", "message": {"text": "Cross-site scripting"}, "partialFingerprints": {"primaryLocationLineHash": "ccc:1"},
+	 "locations": [{"physicalLocation": {"artifactLocation": {"uri": "src/Web.java"}, "region": {"startLine": 5}}}]},
+	{"ruleId": "java/weak-crypto", "message": {"text": "Weak crypto"}, "suppressions": [{"kind": "inSource"}],
+	 "locations": [{"physicalLocation": {"artifactLocation": {"uri": "src/Crypto.java"}, "region": {"startLine": 7}}}]}
+]}, {"results": [
+	{"ruleId": "py/code-injection", "message": {"text": "Code injection"},
+	 "locations": [{"physicalLocation": {"artifactLocation": {"uri": "tools/run.py"}, "region": {"startLine": 3}}}]}
+]}]}`
+
+func TestReadSarifAlerts(t *testing.T) {
+	t.Parallel()
+	t.Run("success", func(t *testing.T) {
+		alerts, err := ReadSarifAlerts([]byte(pullRequestSarif))
+		assert.NoError(t, err)
+		assert.Equal(t, []SarifAlert{
+			{RuleID: "java/sql-injection", Message: "Query built from user input", Location: "src/Dao.java:12", Fingerprint: "aaa:1"},
+			{RuleID: "java/xss", Message: "Cross-site scripting", Location: "src/Web.java:5", Fingerprint: "ccc:1"},
+			{RuleID: "py/code-injection", Message: "Code injection", Location: "tools/run.py:3"},
+		}, alerts)
+	})
+
+	t.Run("invalid report", func(t *testing.T) {
+		_, err := ReadSarifAlerts([]byte("no json"))
+		assert.Contains(t, err.Error(), "failed to parse SARIF report")
+	})
+}
+
+func TestCompareWithBaseline(t *testing.T) {
+	t.Parallel()
+	current, _ := ReadSarifAlerts([]byte(pullRequestSarif))
+	baseline, _ := ReadSarifAlerts([]byte(baselineSarif))
+
+	comparison := CompareWithBaseline(current, baseline, "refs/heads/main")
+
+	assert.Equal(t, "refs/heads/main", comparison.BaselineRef)
+	assert.Equal(t, 1, comparison.ExistingAlerts)
+	assert.Equal(t, 1, comparison.FixedAlerts)
+	assert.Equal(t, []SarifAlert{
+		{RuleID: "java/xss", Message: "Cross-site scripting", Location: "src/Web.java:5", Fingerprint: "ccc:1"},
+		{RuleID: "py/code-injection", Message: "Code injection", Location: "tools/run.py:3"},
+	}, comparison.NewAlerts)
+}
+
+func TestWriteBaselineReport(t *testing.T) {
+	t.Parallel()
+	utils := &mock.FilesMock{}
+	comparison := BaselineComparison{BaselineRef: "refs/heads/main", NewAlerts: []SarifAlert{{RuleID: "java/xss", Location: "src/Web.java:5"}}, ExistingAlerts: 2}
+
+	paths, err := WriteBaselineReport(comparison, "./", utils)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "codeql/piper_codeql_baseline_report.json", paths[0].Target)
+	content, err := utils.FileRead("codeql/piper_codeql_baseline_report.json")
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), `"existingAlerts": 2`)
+	assert.Contains(t, string(content), `"ruleId": "java/xss"`)
+}
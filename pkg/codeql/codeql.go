@@ -1,7 +1,11 @@
 package codeql
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
+	"net/http"
 
 	sapgithub "github.com/SAP/jenkins-library/pkg/github"
 	"github.com/google/go-github/v45/github"
@@ -15,8 +19,19 @@ type githubCodeqlScanningService interface {
 	ListAlertsForRepo(ctx context.Context, owner, repo string, opts *github.AlertListOptions) ([]*github.Alert, *github.Response, error)
 }
 
+type githubCodeqlAnalysesService interface {
+	ListAnalysesForRepo(ctx context.Context, owner, repo string, opts *github.AnalysesListOptions) ([]*github.ScanningAnalysis, *github.Response, error)
+}
+
+type githubRequestService interface {
+	NewRequest(method, urlStr string, body interface{}) (*http.Request, error)
+	Do(ctx context.Context, req *http.Request, v interface{}) (*github.Response, error)
+}
+
 const auditStateOpen = "open"
 
+const sarifMediaType = "application/sarif+json"
+
 func NewCodeqlScanAuditInstance(apiURL, owner, repository, token string, trustedCerts []string) CodeqlScanAuditInstance {
 	return CodeqlScanAuditInstance{apiURL: apiURL, owner: owner, repository: repository, token: token, trustedCerts: trustedCerts}
 }
@@ -63,3 +78,49 @@ func getVulnerabilitiesFromClient(ctx context.Context, codeScanning githubCodeql
 	codeqlScanning.Audited = (codeqlScanning.Total - openStateCount)
 	return codeqlScanning, nil
 }
+
+// GetSarifReports downloads the SARIF reports of the most recent CodeQL analyses of the given ref.
+// One report is returned per analysis category, e.g. per analyzed language. No report is returned in case the ref has not been analyzed yet.
+func (codeqlScanAudit *CodeqlScanAuditInstance) GetSarifReports(analyzedRef string) ([][]byte, error) {
+	ctx, client, err := sapgithub.NewClient(codeqlScanAudit.token, codeqlScanAudit.apiURL, "", codeqlScanAudit.trustedCerts)
+	if err != nil {
+		return nil, err
+	}
+
+	return getSarifReportsFromClient(ctx, client.CodeScanning, client, analyzedRef, codeqlScanAudit)
+}
+
+func getSarifReportsFromClient(ctx context.Context, analyses githubCodeqlAnalysesService, requests githubRequestService, analyzedRef string, codeqlScanAudit *CodeqlScanAuditInstance) ([][]byte, error) {
+	options := github.AnalysesListOptions{Ref: &analyzedRef, ListOptions: github.ListOptions{PerPage: 100}}
+	analysisList, _, err := analyses.ListAnalysesForRepo(ctx, codeqlScanAudit.owner, codeqlScanAudit.repository, &options)
+	var errorResponse *github.ErrorResponse
+	if errors.As(err, &errorResponse) && errorResponse.Response != nil && errorResponse.Response.StatusCode == http.StatusNotFound {
+		// GitHub responds with 404 in case the ref has not been analyzed yet
+		return [][]byte{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	reports := [][]byte{}
+	categories := map[string]bool{}
+	// analyses are sorted by creation date starting with the most recent one
+	for _, analysis := range analysisList {
+		if analysis.Tool == nil || analysis.Tool.GetName() != "CodeQL" || categories[analysis.GetCategory()] {
+			continue
+		}
+		categories[analysis.GetCategory()] = true
+
+		req, err := requests.NewRequest(http.MethodGet, fmt.Sprintf("repos/%v/%v/code-scanning/analyses/%v", codeqlScanAudit.owner, codeqlScanAudit.repository, analysis.GetID()), nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept", sarifMediaType)
+		var buffer bytes.Buffer
+		if _, err := requests.Do(ctx, req, &buffer); err != nil {
+			return nil, fmt.Errorf("failed to download SARIF of analysis %v: %w", analysis.GetID(), err)
+		}
+		reports = append(reports, buffer.Bytes())
+	}
+	return reports, nil
+}
@@ -1,8 +1,11 @@
 package codeql
 
 import (
+	"bytes"
 	"context"
 	"errors"
+	"io"
+	"net/http"
 	"testing"
 
 	"github.com/google/go-github/v45/github"
@@ -45,3 +48,71 @@ func TestGetVulnerabilitiesFromClient(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+type githubCodeqlAnalysesMock struct {
+	analyses []*github.ScanningAnalysis
+	err      error
+}
+
+func (g *githubCodeqlAnalysesMock) ListAnalysesForRepo(ctx context.Context, owner, repo string, opts *github.AnalysesListOptions) ([]*github.ScanningAnalysis, *github.Response, error) {
+	return g.analyses, nil, g.err
+}
+
+type githubRequestMock struct {
+	requestedURLs []string
+	acceptHeaders []string
+}
+
+func (g *githubRequestMock) NewRequest(method, urlStr string, body interface{}) (*http.Request, error) {
+	return http.NewRequest(method, urlStr, nil)
+}
+
+func (g *githubRequestMock) Do(ctx context.Context, req *http.Request, v interface{}) (*github.Response, error) {
+	g.requestedURLs = append(g.requestedURLs, req.URL.String())
+	g.acceptHeaders = append(g.acceptHeaders, req.Header.Get("Accept"))
+	_, err := io.Copy(v.(io.Writer), bytes.NewBufferString(`{"runs": []}`))
+	return nil, err
+}
+
+func TestGetSarifReportsFromClient(t *testing.T) {
+	ctx := context.Background()
+	t.Parallel()
+	analysis := func(id int64, category, tool string) *github.ScanningAnalysis {
+		return &github.ScanningAnalysis{ID: &id, Category: &category, Tool: &github.Tool{Name: &tool}}
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		analysesMock := githubCodeqlAnalysesMock{analyses: []*github.ScanningAnalysis{
+			analysis(3, "java", "CodeQL"), analysis(2, "python", "CodeQL"), analysis(1, "java", "CodeQL"), analysis(4, "", "Other"),
+		}}
+		requestMock := githubRequestMock{}
+		codeqlScanAuditInstance := NewCodeqlScanAuditInstance("", "owner", "repo", "", []string{})
+		reports, err := getSarifReportsFromClient(ctx, &analysesMock, &requestMock, "refs/heads/main", &codeqlScanAuditInstance)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(reports))
+		assert.Equal(t, []string{"repos/owner/repo/code-scanning/analyses/3", "repos/owner/repo/code-scanning/analyses/2"}, requestMock.requestedURLs)
+		assert.Equal(t, []string{"application/sarif+json", "application/sarif+json"}, requestMock.acceptHeaders)
+	})
+
+	t.Run("No analysis", func(t *testing.T) {
+		codeqlScanAuditInstance := NewCodeqlScanAuditInstance("", "owner", "repo", "", []string{})
+		reports, err := getSarifReportsFromClient(ctx, &githubCodeqlAnalysesMock{}, &githubRequestMock{}, "refs/heads/main", &codeqlScanAuditInstance)
+		assert.NoError(t, err)
+		assert.Empty(t, reports)
+	})
+
+	t.Run("Ref not analyzed", func(t *testing.T) {
+		codeqlScanAuditInstance := NewCodeqlScanAuditInstance("", "owner", "repo", "", []string{})
+		notFound := &github.ErrorResponse{Response: &http.Response{StatusCode: http.StatusNotFound}, Message: "no analysis found"}
+		reports, err := getSarifReportsFromClient(ctx, &githubCodeqlAnalysesMock{err: notFound}, &githubRequestMock{}, "refs/heads/release", &codeqlScanAuditInstance)
+		assert.NoError(t, err)
+		assert.NotNil(t, reports)
+		assert.Empty(t, reports)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		codeqlScanAuditInstance := NewCodeqlScanAuditInstance("", "owner", "repo", "", []string{})
+		_, err := getSarifReportsFromClient(ctx, &githubCodeqlAnalysesMock{err: errors.New("Some error")}, &githubRequestMock{}, "refs/heads/main", &codeqlScanAuditInstance)
+		assert.EqualError(t, err, "Some error")
+	})
+}
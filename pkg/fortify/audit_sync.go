@@ -0,0 +1,362 @@
+package fortify
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/piper-validation/fortify-client-go/models"
+	"github.com/pkg/errors"
+)
+
+const (
+	// SyncDirectionBoth synchronizes audits from the pull-request into the main version and vice versa
+	SyncDirectionBoth = "both"
+	// SyncDirectionToMaster only synchronizes audits from the pull-request into the main version
+	SyncDirectionToMaster = "toMaster"
+	// SyncDirectionToPullRequest only synchronizes audits from the main version into the pull-request
+	SyncDirectionToPullRequest = "toPullRequest"
+
+	// ConflictResolutionSkip leaves conflicting tag values untouched and reports them
+	ConflictResolutionSkip = "skip"
+	// ConflictResolutionMaster overwrites conflicting tag values of the pull-request with the ones of the main version
+	ConflictResolutionMaster = "master"
+	// ConflictResolutionPullRequest overwrites conflicting tag values of the main version with the ones of the pull-request
+	ConflictResolutionPullRequest = "pullRequest"
+
+	auditSyncReportName = "fortifyAuditSync.json"
+)
+
+var syncedCommentPrefix = regexp.MustCompile(`^\[synced from [^\]]*\] `)
+
+// AuditSyncOptions controls which parts of an audit are synchronized and how conflicts are resolved
+type AuditSyncOptions struct {
+	Direction          string
+	ConflictResolution string
+	SyncTags           bool
+	SyncSuppressions   bool
+	SyncComments       bool
+}
+
+// AuditSyncConflict describes a custom tag which is set to different values in both project versions
+type AuditSyncConflict struct {
+	IssueInstanceID  string `json:"issueInstanceId"`
+	IssueName        string `json:"issueName"`
+	CustomTagGUID    string `json:"customTagGuid"`
+	MasterValue      string `json:"masterValue"`
+	PullRequestValue string `json:"pullRequestValue"`
+}
+
+// AuditSyncVersionResult contains the outcome of synchronizing one pull-request version with the main version
+type AuditSyncVersionResult struct {
+	PullRequestVersion        string              `json:"pullRequestVersion"`
+	PullRequestVersionID      int64               `json:"pullRequestVersionId"`
+	MatchedIssues             int                 `json:"matchedIssues"`
+	IssuesAuditedMaster       []string            `json:"issuesAuditedMaster"`
+	IssuesAuditedPullRequest  []string            `json:"issuesAuditedPullRequest"`
+	CommentsCopiedMaster      int                 `json:"commentsCopiedMaster"`
+	CommentsCopiedPullRequest int                 `json:"commentsCopiedPullRequest"`
+	Conflicts                 []AuditSyncConflict `json:"conflicts"`
+}
+
+// AuditSyncResult is the report of a fortifySyncAudits run
+type AuditSyncResult struct {
+	ProjectName      string                   `json:"projectName"`
+	MasterVersion    string                   `json:"masterVersion"`
+	MasterVersionID  int64                    `json:"masterVersionId"`
+	Versions         []AuditSyncVersionResult `json:"versions"`
+	DeletedVersions  []string                 `json:"deletedVersions"`
+	ConflictsSkipped int                      `json:"conflictsSkipped"`
+}
+
+// issueSide bundles an issue with the version it belongs to
+type issueSide struct {
+	versionID   int64
+	versionName string
+	issue       *models.ProjectVersionIssue
+	state       *IssueAuditState
+}
+
+// SyncAudits matches the issues of the pull-request version with the ones of the main version by their instance ID
+// and copies custom tag values, suppressions and comments between them as configured by options
+func SyncAudits(sys System, master, pullRequest *models.ProjectVersion, options AuditSyncOptions) (*AuditSyncVersionResult, error) {
+	result := &AuditSyncVersionResult{
+		PullRequestVersion:       *pullRequest.Name,
+		PullRequestVersionID:     pullRequest.ID,
+		IssuesAuditedMaster:      []string{},
+		IssuesAuditedPullRequest: []string{},
+		Conflicts:                []AuditSyncConflict{},
+	}
+	masterIssues, err := sys.GetAllIssueDetails(master.ID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load issues of project version %v", *master.Name)
+	}
+	pullRequestIssues, err := sys.GetAllIssueDetails(pullRequest.ID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load issues of project version %v", *pullRequest.Name)
+	}
+
+	masterByInstanceID := map[string]*models.ProjectVersionIssue{}
+	for _, issue := range masterIssues {
+		if issue.IssueInstanceID != nil {
+			masterByInstanceID[*issue.IssueInstanceID] = issue
+		}
+	}
+
+	for _, prIssue := range pullRequestIssues {
+		if prIssue.IssueInstanceID == nil {
+			continue
+		}
+		masterIssue, ok := masterByInstanceID[*prIssue.IssueInstanceID]
+		if !ok {
+			continue
+		}
+		result.MatchedIssues++
+		if !hasAuditInformation(masterIssue) && !hasAuditInformation(prIssue) {
+			continue
+		}
+		m := &issueSide{versionID: master.ID, versionName: *master.Name, issue: masterIssue}
+		p := &issueSide{versionID: pullRequest.ID, versionName: *pullRequest.Name, issue: prIssue}
+
+		if options.SyncTags || options.SyncSuppressions {
+			if err := syncIssueAudit(sys, m, p, options, result); err != nil {
+				return nil, err
+			}
+		}
+		if options.SyncComments {
+			if err := syncIssueComments(sys, m, p, options, result); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return result, nil
+}
+
+func hasAuditInformation(issue *models.ProjectVersionIssue) bool {
+	return issue.Audited ||
+		(issue.Suppressed != nil && *issue.Suppressed) ||
+		(issue.HasComments != nil && *issue.HasComments) ||
+		(issue.PrimaryTag != nil && *issue.PrimaryTag != "")
+}
+
+func syncIssueAudit(sys System, master, pullRequest *issueSide, options AuditSyncOptions, result *AuditSyncVersionResult) error {
+	var err error
+	if master.state, err = sys.GetIssueAuditState(master.issue.ID); err != nil {
+		return errors.Wrapf(err, "failed to load audit of issue %v in project version %v", *master.issue.IssueInstanceID, master.versionName)
+	}
+	if pullRequest.state, err = sys.GetIssueAuditState(pullRequest.issue.ID); err != nil {
+		return errors.Wrapf(err, "failed to load audit of issue %v in project version %v", *pullRequest.issue.IssueInstanceID, pullRequest.versionName)
+	}
+
+	toMaster := []CustomTagValue{}
+	toPullRequest := []CustomTagValue{}
+	if options.SyncTags {
+		masterValues := tagValuesByGUID(master.state.CustomTagValues)
+		pullRequestValues := tagValuesByGUID(pullRequest.state.CustomTagValues)
+		guids := piperutils.Keys(masterValues)
+		for guid := range pullRequestValues {
+			if _, ok := masterValues[guid]; !ok {
+				guids = append(guids, guid)
+			}
+		}
+		sort.Strings(guids)
+
+		for _, guid := range guids {
+			masterValue, pullRequestValue := masterValues[guid], pullRequestValues[guid]
+			switch {
+			case masterValue.String() == pullRequestValue.String():
+			case masterValue.IsEmpty():
+				toMaster = append(toMaster, pullRequestValue)
+			case pullRequestValue.IsEmpty():
+				toPullRequest = append(toPullRequest, masterValue)
+			case options.ConflictResolution == ConflictResolutionMaster:
+				toPullRequest = append(toPullRequest, masterValue)
+			case options.ConflictResolution == ConflictResolutionPullRequest:
+				toMaster = append(toMaster, pullRequestValue)
+			default:
+				result.Conflicts = append(result.Conflicts, AuditSyncConflict{
+					IssueInstanceID:  *master.issue.IssueInstanceID,
+					IssueName:        issueName(master.issue),
+					CustomTagGUID:    guid,
+					MasterValue:      masterValue.String(),
+					PullRequestValue: pullRequestValue.String(),
+				})
+			}
+		}
+	}
+
+	// suppressions are only propagated, an issue is never unsuppressed by the synchronization
+	suppressMaster := options.SyncSuppressions && pullRequest.state.Suppressed && !master.state.Suppressed
+	suppressPullRequest := options.SyncSuppressions && master.state.Suppressed && !pullRequest.state.Suppressed
+
+	if options.Direction != SyncDirectionToPullRequest && (len(toMaster) > 0 || suppressMaster) {
+		if err := auditIssue(sys, master, toMaster, suppressMaster); err != nil {
+			return err
+		}
+		result.IssuesAuditedMaster = append(result.IssuesAuditedMaster, *master.issue.IssueInstanceID)
+	}
+	if options.Direction != SyncDirectionToMaster && (len(toPullRequest) > 0 || suppressPullRequest) {
+		if err := auditIssue(sys, pullRequest, toPullRequest, suppressPullRequest); err != nil {
+			return err
+		}
+		result.IssuesAuditedPullRequest = append(result.IssuesAuditedPullRequest, *pullRequest.issue.IssueInstanceID)
+	}
+	return nil
+}
+
+func issueName(issue *models.ProjectVersionIssue) string {
+	if issue.IssueName == nil {
+		return ""
+	}
+	return *issue.IssueName
+}
+
+func tagValuesByGUID(values []CustomTagValue) map[string]CustomTagValue {
+	result := map[string]CustomTagValue{}
+	for _, value := range values {
+		result[value.CustomTagGUID] = value
+	}
+	return result
+}
+
+func auditIssue(sys System, target *issueSide, tags []CustomTagValue, suppress bool) error {
+	log.Entry().Debugf("Updating audit of issue %v in project version %v", *target.issue.IssueInstanceID, target.versionName)
+	audit := IssueAuditRequest{
+		IssueID:        target.issue.ID,
+		Revision:       target.state.Revision,
+		Suppressed:     target.state.Suppressed || suppress,
+		CustomTagAudit: tags,
+	}
+	if err := sys.AuditIssue(target.versionID, audit); err != nil {
+		return errors.Wrapf(err, "failed to audit issue %v in project version %v", *target.issue.IssueInstanceID, target.versionName)
+	}
+	return nil
+}
+
+func syncIssueComments(sys System, master, pullRequest *issueSide, options AuditSyncOptions, result *AuditSyncVersionResult) error {
+	masterComments, err := loadComments(sys, master)
+	if err != nil {
+		return err
+	}
+	pullRequestComments, err := loadComments(sys, pullRequest)
+	if err != nil {
+		return err
+	}
+	if options.Direction != SyncDirectionToPullRequest {
+		count, err := copyComments(sys, pullRequest, pullRequestComments, master, masterComments)
+		if err != nil {
+			return err
+		}
+		result.CommentsCopiedMaster += count
+	}
+	if options.Direction != SyncDirectionToMaster {
+		count, err := copyComments(sys, master, masterComments, pullRequest, pullRequestComments)
+		if err != nil {
+			return err
+		}
+		result.CommentsCopiedPullRequest += count
+	}
+	return nil
+}
+
+func loadComments(sys System, side *issueSide) ([]*models.IssueAuditComment, error) {
+	if side.issue.HasComments != nil && !*side.issue.HasComments {
+		return []*models.IssueAuditComment{}, nil
+	}
+	comments, err := sys.GetIssueComments(side.issue.ID)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load comments of issue %v in project version %v", *side.issue.IssueInstanceID, side.versionName)
+	}
+	return comments, nil
+}
+
+// copyComments adds all comments of source to target which target does not contain yet
+// Comments which were synchronized before are not copied again to avoid ping-pong between the versions.
+func copyComments(sys System, source *issueSide, sourceComments []*models.IssueAuditComment, target *issueSide, targetComments []*models.IssueAuditComment) (int, error) {
+	existing := map[string]bool{}
+	for _, comment := range targetComments {
+		if comment.Comment != nil {
+			existing[normalizeComment(*comment.Comment)] = true
+		}
+	}
+	count := 0
+	for _, comment := range sourceComments {
+		if comment.Comment == nil || syncedCommentPrefix.MatchString(*comment.Comment) || existing[normalizeComment(*comment.Comment)] {
+			continue
+		}
+		text := fmt.Sprintf("[synced from %v, author %v] %v", source.versionName, comment.UserName, *comment.Comment)
+		if err := sys.AddIssueComment(target.issue.ID, text); err != nil {
+			return count, errors.Wrapf(err, "failed to add comment to issue %v in project version %v", *target.issue.IssueInstanceID, target.versionName)
+		}
+		existing[normalizeComment(*comment.Comment)] = true
+		count++
+	}
+	return count, nil
+}
+
+func normalizeComment(comment string) string {
+	return strings.TrimSpace(syncedCommentPrefix.ReplaceAllString(comment, ""))
+}
+
+// StalePullRequestVersions returns the project versions whose name matches pattern and which did not receive a scan
+// result since maxAge. The project versions with the IDs given in keep, e.g. the master version, are never stale.
+func StalePullRequestVersions(versions []*models.ProjectVersion, pattern *regexp.Regexp, maxAge time.Duration, now time.Time, keep ...int64) []*models.ProjectVersion {
+	kept := map[int64]bool{}
+	for _, id := range keep {
+		kept[id] = true
+	}
+	stale := []*models.ProjectVersion{}
+	for _, version := range versions {
+		if version.Name == nil || kept[version.ID] || !pattern.MatchString(*version.Name) {
+			continue
+		}
+		lastActivity := time.Time{}
+		if version.CreationDate != nil {
+			lastActivity = time.Time(*version.CreationDate)
+		}
+		if version.CurrentState != nil && version.CurrentState.LastFprUploadDate != nil && !time.Time(*version.CurrentState.LastFprUploadDate).IsZero() {
+			lastActivity = time.Time(*version.CurrentState.LastFprUploadDate)
+		}
+		if lastActivity.IsZero() || now.Sub(lastActivity) < maxAge {
+			continue
+		}
+		log.Entry().Infof("Project version %v is stale, last activity at %v", *version.Name, lastActivity.Format(time.RFC3339))
+		stale = append(stale, version)
+	}
+	return stale
+}
+
+// DeleteProjectVersions deletes the given project versions and returns the names of the deleted versions
+func DeleteProjectVersions(sys System, versions []*models.ProjectVersion) ([]string, error) {
+	deleted := []string{}
+	for _, version := range versions {
+		log.Entry().Infof("Deleting project version %v", *version.Name)
+		if err := sys.DeleteProjectVersion(version.ID); err != nil {
+			return deleted, errors.Wrapf(err, "failed to delete project version %v", *version.Name)
+		}
+		deleted = append(deleted, *version.Name)
+	}
+	return deleted, nil
+}
+
+// WriteAuditSyncReport writes the synchronization result as JSON into the Fortify reports directory
+func WriteAuditSyncReport(result *AuditSyncResult, fileUtils piperutils.FileUtils) (piperutils.Path, error) {
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return piperutils.Path{}, errors.Wrap(err, "failed to marshal audit synchronization report")
+	}
+	if err := fileUtils.MkdirAll(ReportsDirectory, 0777); err != nil {
+		return piperutils.Path{}, errors.Wrapf(err, "failed to create report directory")
+	}
+	reportPath := filepath.Join(ReportsDirectory, auditSyncReportName)
+	if err := fileUtils.FileWrite(reportPath, data, 0666); err != nil {
+		return piperutils.Path{}, errors.Wrapf(err, "failed to write audit synchronization report")
+	}
+	return piperutils.Path{Name: "Fortify Audit Synchronization", Target: reportPath, Mandatory: true}, nil
+}
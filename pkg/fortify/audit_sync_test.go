@@ -0,0 +1,251 @@
+package fortify
+
+import (
+	"encoding/json"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/piper-validation/fortify-client-go/models"
+	"github.com/stretchr/testify/assert"
+)
+
+type auditSyncSystemMock struct {
+	System
+	issues          map[int64][]*models.ProjectVersionIssue
+	auditStates     map[int64]*IssueAuditState
+	comments        map[int64][]*models.IssueAuditComment
+	versions        []*models.ProjectVersion
+	audits          map[int64][]IssueAuditRequest
+	addedComments   map[int64][]string
+	deletedVersions []int64
+}
+
+func newAuditSyncSystemMock() *auditSyncSystemMock {
+	return &auditSyncSystemMock{
+		issues:        map[int64][]*models.ProjectVersionIssue{},
+		auditStates:   map[int64]*IssueAuditState{},
+		comments:      map[int64][]*models.IssueAuditComment{},
+		audits:        map[int64][]IssueAuditRequest{},
+		addedComments: map[int64][]string{},
+	}
+}
+
+func (m *auditSyncSystemMock) GetAllIssueDetails(projectVersionID int64) ([]*models.ProjectVersionIssue, error) {
+	if projectVersionID == 666 {
+		return nil, errors.New("failed to load issues")
+	}
+	return m.issues[projectVersionID], nil
+}
+
+func (m *auditSyncSystemMock) GetIssueAuditState(issueID int64) (*IssueAuditState, error) {
+	if state, ok := m.auditStates[issueID]; ok {
+		return state, nil
+	}
+	return &IssueAuditState{ID: issueID}, nil
+}
+
+func (m *auditSyncSystemMock) AuditIssue(projectVersionID int64, audit IssueAuditRequest) error {
+	m.audits[projectVersionID] = append(m.audits[projectVersionID], audit)
+	return nil
+}
+
+func (m *auditSyncSystemMock) GetIssueComments(issueID int64) ([]*models.IssueAuditComment, error) {
+	return m.comments[issueID], nil
+}
+
+func (m *auditSyncSystemMock) AddIssueComment(issueID int64, comment string) error {
+	m.addedComments[issueID] = append(m.addedComments[issueID], comment)
+	return nil
+}
+
+func (m *auditSyncSystemMock) GetProjectVersionsOfProject(projectID int64) ([]*models.ProjectVersion, error) {
+	return m.versions, nil
+}
+
+func (m *auditSyncSystemMock) DeleteProjectVersion(id int64) error {
+	m.deletedVersions = append(m.deletedVersions, id)
+	return nil
+}
+
+func syncTestIssue(id int64, instanceID string, audited bool) *models.ProjectVersionIssue {
+	name := "SQL Injection"
+	hasComments := audited
+	return &models.ProjectVersionIssue{ID: id, IssueInstanceID: &instanceID, IssueName: &name, Audited: audited, HasComments: &hasComments}
+}
+
+func syncTestVersion(id int64, name string) *models.ProjectVersion {
+	return &models.ProjectVersion{ID: id, Name: &name}
+}
+
+func syncTestComment(text, user string) *models.IssueAuditComment {
+	return &models.IssueAuditComment{Comment: &text, UserName: user}
+}
+
+func allSyncOptions() AuditSyncOptions {
+	return AuditSyncOptions{Direction: SyncDirectionBoth, ConflictResolution: ConflictResolutionSkip, SyncTags: true, SyncSuppressions: true, SyncComments: true}
+}
+
+func TestSyncAudits(t *testing.T) {
+	master := syncTestVersion(1, "master")
+	pullRequest := syncTestVersion(2, "PR-42")
+	analysisTag := "87f2364f-dcd4-49e6-861d-f8d3f351686b"
+	text := "reviewed"
+
+	t.Run("copies tags, suppression and comments in both directions", func(t *testing.T) {
+		sys := newAuditSyncSystemMock()
+		sys.issues[1] = []*models.ProjectVersionIssue{syncTestIssue(11, "AAA", true), syncTestIssue(12, "BBB", false), syncTestIssue(13, "ONLYMASTER", true)}
+		sys.issues[2] = []*models.ProjectVersionIssue{syncTestIssue(21, "AAA", false), syncTestIssue(22, "BBB", true), syncTestIssue(23, "ONLYPR", true)}
+		sys.auditStates[11] = &IssueAuditState{ID: 11, Revision: 5, Suppressed: true, CustomTagValues: []CustomTagValue{{CustomTagGUID: analysisTag, ValueGUID: "not-an-issue"}}}
+		sys.auditStates[21] = &IssueAuditState{ID: 21, Revision: 1, CustomTagValues: []CustomTagValue{{CustomTagGUID: analysisTag}}}
+		sys.auditStates[22] = &IssueAuditState{ID: 22, Revision: 2, CustomTagValues: []CustomTagValue{{CustomTagGUID: "comment-tag", TextValue: &text}}}
+		sys.comments[11] = []*models.IssueAuditComment{syncTestComment("false positive", "jdoe"), syncTestComment("[synced from PR-41, author jane] old", "piper")}
+		sys.comments[22] = []*models.IssueAuditComment{syncTestComment("fixed in PR", "jane")}
+
+		result, err := SyncAudits(sys, master, pullRequest, allSyncOptions())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, result.MatchedIssues)
+		assert.Equal(t, []string{"BBB"}, result.IssuesAuditedMaster)
+		assert.Equal(t, []string{"AAA"}, result.IssuesAuditedPullRequest)
+		assert.Equal(t, []IssueAuditRequest{{IssueID: 21, Revision: 1, Suppressed: true, CustomTagAudit: []CustomTagValue{{CustomTagGUID: analysisTag, ValueGUID: "not-an-issue"}}}}, sys.audits[2])
+		assert.Equal(t, []IssueAuditRequest{{IssueID: 12, Revision: 0, Suppressed: false, CustomTagAudit: []CustomTagValue{{CustomTagGUID: "comment-tag", TextValue: &text}}}}, sys.audits[1])
+		assert.Equal(t, []string{"[synced from master, author jdoe] false positive"}, sys.addedComments[21])
+		assert.Equal(t, []string{"[synced from PR-42, author jane] fixed in PR"}, sys.addedComments[12])
+		assert.Equal(t, 1, result.CommentsCopiedMaster)
+		assert.Equal(t, 1, result.CommentsCopiedPullRequest)
+		assert.Empty(t, result.Conflicts)
+	})
+
+	t.Run("does not duplicate synced comments", func(t *testing.T) {
+		sys := newAuditSyncSystemMock()
+		sys.issues[1] = []*models.ProjectVersionIssue{syncTestIssue(11, "AAA", true)}
+		sys.issues[2] = []*models.ProjectVersionIssue{syncTestIssue(21, "AAA", true)}
+		sys.comments[11] = []*models.IssueAuditComment{syncTestComment("false positive", "jdoe")}
+		sys.comments[21] = []*models.IssueAuditComment{syncTestComment("[synced from master, author jdoe] false positive", "piper")}
+
+		result, err := SyncAudits(sys, master, pullRequest, allSyncOptions())
+
+		assert.NoError(t, err)
+		assert.Empty(t, sys.addedComments)
+		assert.Equal(t, 0, result.CommentsCopiedMaster+result.CommentsCopiedPullRequest)
+	})
+
+	t.Run("conflicts", func(t *testing.T) {
+		setup := func() *auditSyncSystemMock {
+			sys := newAuditSyncSystemMock()
+			sys.issues[1] = []*models.ProjectVersionIssue{syncTestIssue(11, "AAA", true)}
+			sys.issues[2] = []*models.ProjectVersionIssue{syncTestIssue(21, "AAA", true)}
+			sys.auditStates[11] = &IssueAuditState{ID: 11, CustomTagValues: []CustomTagValue{{CustomTagGUID: analysisTag, ValueGUID: "not-an-issue"}}}
+			sys.auditStates[21] = &IssueAuditState{ID: 21, CustomTagValues: []CustomTagValue{{CustomTagGUID: analysisTag, ValueGUID: "exploitable"}}}
+			return sys
+		}
+		options := allSyncOptions()
+		options.SyncComments = false
+
+		sys := setup()
+		result, err := SyncAudits(sys, master, pullRequest, options)
+		assert.NoError(t, err)
+		assert.Empty(t, sys.audits)
+		assert.Equal(t, []AuditSyncConflict{{IssueInstanceID: "AAA", IssueName: "SQL Injection", CustomTagGUID: analysisTag, MasterValue: "not-an-issue", PullRequestValue: "exploitable"}}, result.Conflicts)
+
+		sys = setup()
+		options.ConflictResolution = ConflictResolutionMaster
+		result, err = SyncAudits(sys, master, pullRequest, options)
+		assert.NoError(t, err)
+		assert.Empty(t, result.Conflicts)
+		assert.Equal(t, "not-an-issue", sys.audits[2][0].CustomTagAudit[0].ValueGUID)
+		assert.Empty(t, sys.audits[1])
+
+		sys = setup()
+		options.ConflictResolution = ConflictResolutionPullRequest
+		result, err = SyncAudits(sys, master, pullRequest, options)
+		assert.NoError(t, err)
+		assert.Empty(t, result.Conflicts)
+		assert.Equal(t, "exploitable", sys.audits[1][0].CustomTagAudit[0].ValueGUID)
+		assert.Empty(t, sys.audits[2])
+	})
+
+	t.Run("respects direction", func(t *testing.T) {
+		sys := newAuditSyncSystemMock()
+		sys.issues[1] = []*models.ProjectVersionIssue{syncTestIssue(11, "AAA", true)}
+		sys.issues[2] = []*models.ProjectVersionIssue{syncTestIssue(21, "AAA", true)}
+		sys.auditStates[11] = &IssueAuditState{ID: 11, Suppressed: true}
+		sys.auditStates[21] = &IssueAuditState{ID: 21, CustomTagValues: []CustomTagValue{{CustomTagGUID: analysisTag, ValueGUID: "exploitable"}}}
+		sys.comments[11] = []*models.IssueAuditComment{syncTestComment("false positive", "jdoe")}
+		sys.comments[21] = []*models.IssueAuditComment{syncTestComment("fixed in PR", "jane")}
+		options := allSyncOptions()
+		options.Direction = SyncDirectionToMaster
+
+		result, err := SyncAudits(sys, master, pullRequest, options)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"AAA"}, result.IssuesAuditedMaster)
+		assert.Empty(t, result.IssuesAuditedPullRequest)
+		assert.Equal(t, true, sys.audits[1][0].Suppressed)
+		assert.Empty(t, sys.audits[2])
+		assert.Equal(t, 1, len(sys.addedComments[11]))
+		assert.Empty(t, sys.addedComments[21])
+	})
+
+	t.Run("error loading issues", func(t *testing.T) {
+		sys := newAuditSyncSystemMock()
+		_, err := SyncAudits(sys, syncTestVersion(666, "master"), pullRequest, allSyncOptions())
+		assert.EqualError(t, err, "failed to load issues of project version master: failed to load issues")
+	})
+}
+
+func TestStalePullRequestVersions(t *testing.T) {
+	now := time.Date(2023, 3, 20, 0, 0, 0, 0, time.UTC)
+	date := func(daysAgo int) *models.Iso8601MilliDateTime {
+		d := models.Iso8601MilliDateTime(now.AddDate(0, 0, -daysAgo))
+		return &d
+	}
+	version := func(id int64, name string, created, uploaded *models.Iso8601MilliDateTime) *models.ProjectVersion {
+		v := syncTestVersion(id, name)
+		v.CreationDate = created
+		if uploaded != nil {
+			v.CurrentState = &models.ProjectVersionState{LastFprUploadDate: uploaded}
+		}
+		return v
+	}
+	versions := []*models.ProjectVersion{
+		version(1, "PR-0", date(100), date(100)),
+		version(2, "PR-1", date(40), date(35)),
+		version(3, "PR-2", date(40), date(5)),
+		version(4, "PR-3", date(31), nil),
+		version(5, "PR-4", date(40), date(40)),
+		version(6, "feature", date(40), date(40)),
+	}
+
+	stale := StalePullRequestVersions(versions, regexp.MustCompile(`^PR-\d+$`), 30*24*time.Hour, now, 1, 5)
+
+	assert.Equal(t, []*models.ProjectVersion{versions[1], versions[3]}, stale)
+}
+
+func TestDeleteProjectVersions(t *testing.T) {
+	sys := newAuditSyncSystemMock()
+
+	deleted, err := DeleteProjectVersions(sys, []*models.ProjectVersion{syncTestVersion(2, "PR-1"), syncTestVersion(4, "PR-3")})
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"PR-1", "PR-3"}, deleted)
+	assert.Equal(t, []int64{2, 4}, sys.deletedVersions)
+}
+
+func TestWriteAuditSyncReport(t *testing.T) {
+	fileUtils := mock.FilesMock{}
+	result := AuditSyncResult{ProjectName: "project", MasterVersion: "master", Versions: []AuditSyncVersionResult{{PullRequestVersion: "PR-42", MatchedIssues: 3}}}
+
+	path, err := WriteAuditSyncReport(&result, &fileUtils)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "fortify/fortifyAuditSync.json", path.Target)
+	content, err := fileUtils.FileRead("fortify/fortifyAuditSync.json")
+	assert.NoError(t, err)
+	written := AuditSyncResult{}
+	assert.NoError(t, json.Unmarshal(content, &written))
+	assert.Equal(t, result, written)
+}
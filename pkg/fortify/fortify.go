@@ -3,6 +3,7 @@ package fortify
 import (
 	"bytes"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -68,6 +69,11 @@ type System interface {
 	GetIssueDetails(projectVersionId int64, issueInstanceId string) ([]*models.ProjectVersionIssue, error)
 	GetAllIssueDetails(projectVersionId int64) ([]*models.ProjectVersionIssue, error)
 	GetIssueComments(parentId int64) ([]*models.IssueAuditComment, error)
+	AddIssueComment(issueID int64, comment string) error
+	GetIssueAuditState(issueID int64) (*IssueAuditState, error)
+	AuditIssue(projectVersionID int64, audit IssueAuditRequest) error
+	GetProjectVersionsOfProject(projectID int64) ([]*models.ProjectVersion, error)
+	DeleteProjectVersion(id int64) error
 	UploadResultFile(endpoint, file string, projectVersionID int64) error
 	DownloadReportFile(endpoint string, reportID int64) ([]byte, error)
 	DownloadResultFile(endpoint string, projectVersionID int64) ([]byte, error)
@@ -75,12 +81,13 @@ type System interface {
 
 // SystemInstance is the specific instance
 type SystemInstance struct {
-	timeout    time.Duration
-	token      string
-	serverURL  string
-	client     *ff.Fortify
-	httpClient *piperHttp.Client
-	logger     *logrus.Entry
+	timeout     time.Duration
+	token       string
+	serverURL   string
+	apiEndpoint string
+	client      *ff.Fortify
+	httpClient  *piperHttp.Client
+	logger      *logrus.Entry
 }
 
 // NewSystemInstance - creates an returns a new SystemInstance
@@ -98,7 +105,7 @@ func NewSystemInstance(serverURL, apiEndpoint, authToken string, timeout time.Du
 	httpClientOptions := piperHttp.ClientOptions{Token: "FortifyToken " + encodedAuthToken, TransportTimeout: timeout}
 	httpClientInstance.SetOptions(httpClientOptions)
 
-	return NewSystemInstanceForClient(clientInstance, httpClientInstance, serverURL, encodedAuthToken, timeout)
+	return NewSystemInstanceForClient(clientInstance, httpClientInstance, serverURL, apiEndpoint, encodedAuthToken, timeout)
 }
 
 func createTransportConfig(serverURL, apiEndpoint string) *ff.TransportConfig {
@@ -146,14 +153,19 @@ func base64EndodePlainToken(authToken string) (encodedAuthToken string) {
 }
 
 // NewSystemInstanceForClient - creates a new SystemInstance
-func NewSystemInstanceForClient(clientInstance *ff.Fortify, httpClientInstance *piperHttp.Client, serverURL, authToken string, requestTimeout time.Duration) *SystemInstance {
+func NewSystemInstanceForClient(clientInstance *ff.Fortify, httpClientInstance *piperHttp.Client, serverURL, apiEndpoint, authToken string, requestTimeout time.Duration) *SystemInstance {
+	// the REST endpoints not covered by the generated client are called directly and thus need the API base path
+	if apiEndpoint = strings.Trim(apiEndpoint, "/"); len(apiEndpoint) > 0 {
+		apiEndpoint = "/" + apiEndpoint
+	}
 	return &SystemInstance{
-		timeout:    requestTimeout,
-		token:      authToken,
-		serverURL:  serverURL,
-		client:     clientInstance,
-		httpClient: httpClientInstance,
-		logger:     log.Entry().WithField("package", "SAP/jenkins-library/pkg/fortify"),
+		timeout:     requestTimeout,
+		token:       authToken,
+		serverURL:   serverURL,
+		apiEndpoint: apiEndpoint,
+		client:      clientInstance,
+		httpClient:  httpClientInstance,
+		logger:      log.Entry().WithField("package", "SAP/jenkins-library/pkg/fortify"),
 	}
 }
 
@@ -672,6 +684,142 @@ func (sys *SystemInstance) GetIssueComments(parentId int64) ([]*models.IssueAudi
 	return result.GetPayload().Data, nil
 }
 
+// AddIssueComment adds an audit comment to the issue identified by issueID
+func (sys *SystemInstance) AddIssueComment(issueID int64, comment string) error {
+	params := &issue_audit_comment_of_issue_controller.CreateIssueAuditCommentOfIssueParams{ParentID: issueID, Resource: &models.IssueAuditComment{Comment: &comment}}
+	params.WithTimeout(sys.timeout)
+	_, err := sys.client.IssueAuditCommentOfIssueController.CreateIssueAuditCommentOfIssue(params, sys)
+	if err != nil {
+		return fmt.Errorf("Error from url %s %w", sys.serverURL, err)
+	}
+	return nil
+}
+
+// CustomTagValue is the value of a custom tag (e.g. "Analysis") set on an issue
+type CustomTagValue struct {
+	CustomTagGUID string   `json:"customTagGuid"`
+	ValueGUID     string   `json:"valueGuid,omitempty"`
+	TextValue     *string  `json:"textValue,omitempty"`
+	DecimalValue  *float64 `json:"decimalValue,omitempty"`
+	DateValue     *string  `json:"dateValue,omitempty"`
+}
+
+// IsEmpty returns true if no value is set for the custom tag
+func (v CustomTagValue) IsEmpty() bool {
+	return v.ValueGUID == "" && (v.TextValue == nil || *v.TextValue == "") && v.DecimalValue == nil && (v.DateValue == nil || *v.DateValue == "")
+}
+
+// String returns a comparable representation of the value
+func (v CustomTagValue) String() string {
+	switch {
+	case v.ValueGUID != "":
+		return v.ValueGUID
+	case v.TextValue != nil && *v.TextValue != "":
+		return *v.TextValue
+	case v.DecimalValue != nil:
+		return fmt.Sprintf("%v", *v.DecimalValue)
+	case v.DateValue != nil:
+		return *v.DateValue
+	}
+	return ""
+}
+
+// IssueAuditState holds the audit relevant details of an issue
+type IssueAuditState struct {
+	ID              int64            `json:"id"`
+	IssueInstanceID string           `json:"issueInstanceId"`
+	Revision        int32            `json:"revision"`
+	Suppressed      bool             `json:"suppressed"`
+	CustomTagValues []CustomTagValue `json:"customTagValues"`
+}
+
+// IssueAuditRequest describes an audit action on an issue of a project version
+type IssueAuditRequest struct {
+	IssueID        int64
+	Revision       int32
+	Suppressed     bool
+	CustomTagAudit []CustomTagValue
+}
+
+// GetIssueAuditState returns the custom tag values and the suppression state of the issue identified by issueID
+// The generated client does not expose the custom tag values, hence the REST endpoint is called directly.
+func (sys *SystemInstance) GetIssueAuditState(issueID int64) (*IssueAuditState, error) {
+	header := http.Header{}
+	header.Add("Accept", "application/json")
+	response, err := sys.httpClient.SendRequest(http.MethodGet, fmt.Sprintf("%v%v/issueDetails/%v", sys.serverURL, sys.apiEndpoint, issueID), nil, header, nil)
+	if err != nil {
+		return nil, fmt.Errorf("Error from url %s %w", sys.serverURL, err)
+	}
+	defer response.Body.Close()
+	var result struct {
+		Data IssueAuditState `json:"data"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&result); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse details of issue %v", issueID)
+	}
+	return &result.Data, nil
+}
+
+// AuditIssue sets the custom tag values and the suppression state of an issue of the project version identified by projectVersionID
+func (sys *SystemInstance) AuditIssue(projectVersionID int64, audit IssueAuditRequest) error {
+	type issueReference struct {
+		ID       int64 `json:"id"`
+		Revision int32 `json:"revision"`
+	}
+	request := struct {
+		Issues         []issueReference `json:"issues"`
+		Suppressed     bool             `json:"suppressed"`
+		CustomTagAudit []CustomTagValue `json:"customTagAudit,omitempty"`
+	}{
+		Issues:         []issueReference{{ID: audit.IssueID, Revision: audit.Revision}},
+		Suppressed:     audit.Suppressed,
+		CustomTagAudit: audit.CustomTagAudit,
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal audit request")
+	}
+	header := http.Header{}
+	header.Add("Accept", "application/json")
+	header.Add("Content-Type", "application/json")
+	response, err := sys.httpClient.SendRequest(http.MethodPost, fmt.Sprintf("%v%v/projectVersions/%v/issues/action/audit", sys.serverURL, sys.apiEndpoint, projectVersionID), bytes.NewReader(body), header, nil)
+	if err != nil {
+		return fmt.Errorf("Error from url %s %w", sys.serverURL, err)
+	}
+	response.Body.Close()
+	return nil
+}
+
+// GetProjectVersionsOfProject returns all project versions of the project identified by projectID
+func (sys *SystemInstance) GetProjectVersionsOfProject(projectID int64) ([]*models.ProjectVersion, error) {
+	versions := []*models.ProjectVersion{}
+	limit := int32(200)
+	for start := int32(0); ; start += limit {
+		params := &project_version_of_project_controller.ListProjectVersionOfProjectParams{ParentID: projectID}
+		params.WithStart(&start).WithLimit(&limit).WithTimeout(sys.timeout)
+		result, err := sys.client.ProjectVersionOfProjectController.ListProjectVersionOfProject(params, sys)
+		if err != nil {
+			return nil, fmt.Errorf("Error from url %s %w", sys.serverURL, err)
+		}
+		data := result.GetPayload().Data
+		versions = append(versions, data...)
+		if len(data) == 0 || int64(len(versions)) >= result.GetPayload().Count {
+			return versions, nil
+		}
+	}
+}
+
+// DeleteProjectVersion deletes the project version identified by id
+func (sys *SystemInstance) DeleteProjectVersion(id int64) error {
+	params := &project_version_controller.DeleteProjectVersionParams{ID: id}
+	params.WithTimeout(sys.timeout)
+	_, err := sys.client.ProjectVersionController.DeleteProjectVersion(params, sys)
+	if err != nil {
+		return fmt.Errorf("Error from url %s %w", sys.serverURL, err)
+	}
+	return nil
+}
+
 func (sys *SystemInstance) invalidateFileTokens() error {
 	log.Entry().Debug("invalidating file tokens")
 	params := &file_token_controller.MultiDeleteFileTokenParams{}
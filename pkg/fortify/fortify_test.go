@@ -20,20 +20,24 @@ import (
 )
 
 func spinUpServer(f func(http.ResponseWriter, *http.Request)) (*SystemInstance, *httptest.Server) {
+	return spinUpServerWithAPIEndpoint("", f)
+}
+
+func spinUpServerWithAPIEndpoint(apiEndpoint string, f func(http.ResponseWriter, *http.Request)) (*SystemInstance, *httptest.Server) {
 	server := httptest.NewServer(http.HandlerFunc(f))
 
 	parts := strings.Split(server.URL, "://")
 	client := ff.NewHTTPClientWithConfig(strfmt.Default, &ff.TransportConfig{
 		Host:     parts[1],
 		Schemes:  []string{parts[0]},
-		BasePath: ""},
+		BasePath: apiEndpoint},
 	)
 
 	httpClient := &piperHttp.Client{}
 	httpClientOptions := piperHttp.ClientOptions{Token: "test2456", TransportTimeout: 60 * time.Second}
 	httpClient.SetOptions(httpClientOptions)
 
-	sys := NewSystemInstanceForClient(client, httpClient, server.URL, "test2456", 60*time.Second)
+	sys := NewSystemInstanceForClient(client, httpClient, server.URL, apiEndpoint, "test2456", 60*time.Second)
 	return sys, server
 }
 
@@ -1406,3 +1410,130 @@ func TestCreateJSONReport(t *testing.T) {
 		assert.Equal(t, true, jsonReport.IsSpotChecksPerCategoryAudited)
 	})
 }
+
+func TestAddIssueComment(t *testing.T) {
+	// Start a local HTTP server
+	var bodyContent string
+	sys, server := spinUpServer(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/issues/4711/comments" && req.Method == "POST" {
+			body, _ := ioutil.ReadAll(req.Body)
+			bodyContent = string(body)
+			header := rw.Header()
+			header.Add("Content-type", "application/json")
+			rw.WriteHeader(201)
+			rw.Write([]byte(`{"data": {"comment": "looks fine", "seqNumber": 1}, "responseCode": 201}`))
+			return
+		}
+	})
+	// Close the server when test finishes
+	defer server.Close()
+
+	t.Run("test success", func(t *testing.T) {
+		err := sys.AddIssueComment(4711, "looks fine")
+		assert.NoError(t, err, "AddIssueComment call not successful")
+		assert.Contains(t, bodyContent, `"comment":"looks fine"`)
+	})
+}
+
+func TestGetIssueAuditState(t *testing.T) {
+	// Start a local HTTP server
+	response := `{"data": {"id": 4711, "issueInstanceId": "ABC123", "revision": 3, "suppressed": true,
+		"customTagValues": [{"customTagGuid": "87f2364f-dcd4-49e6-861d-f8d3f351686b", "valueGuid": "bb824e8d-b26c-4b5a-a2ea-c94d2d4fd3d1", "textValue": null},
+		{"customTagGuid": "a1b2", "textValue": "some text"}]}, "responseCode": 200}`
+	sys, server := spinUpServerWithAPIEndpoint("/api/v1", func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/api/v1/issueDetails/4711" {
+			header := rw.Header()
+			header.Add("Content-type", "application/json")
+			rw.Write([]byte(response))
+			return
+		}
+	})
+	// Close the server when test finishes
+	defer server.Close()
+
+	t.Run("test success", func(t *testing.T) {
+		result, err := sys.GetIssueAuditState(4711)
+		assert.NoError(t, err, "GetIssueAuditState call not successful")
+		assert.Equal(t, int64(4711), result.ID)
+		assert.Equal(t, int32(3), result.Revision)
+		assert.True(t, result.Suppressed)
+		assert.Equal(t, 2, len(result.CustomTagValues))
+		assert.Equal(t, "bb824e8d-b26c-4b5a-a2ea-c94d2d4fd3d1", result.CustomTagValues[0].String())
+		assert.Equal(t, "some text", result.CustomTagValues[1].String())
+		assert.False(t, result.CustomTagValues[1].IsEmpty())
+	})
+}
+
+func TestAuditIssue(t *testing.T) {
+	// Start a local HTTP server
+	var bodyContent string
+	sys, server := spinUpServerWithAPIEndpoint("/api/v1", func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/api/v1/projectVersions/10172/issues/action/audit" && req.Method == "POST" {
+			body, _ := ioutil.ReadAll(req.Body)
+			bodyContent = string(body)
+			header := rw.Header()
+			header.Add("Content-type", "application/json")
+			rw.Write([]byte(`{"data": {"message": "ok"}, "responseCode": 200}`))
+			return
+		}
+	})
+	// Close the server when test finishes
+	defer server.Close()
+
+	t.Run("test success", func(t *testing.T) {
+		err := sys.AuditIssue(10172, IssueAuditRequest{IssueID: 4711, Revision: 3, Suppressed: true, CustomTagAudit: []CustomTagValue{{CustomTagGUID: "87f2364f", ValueGUID: "bb824e8d"}}})
+		assert.NoError(t, err, "AuditIssue call not successful")
+		assert.JSONEq(t, `{"issues":[{"id":4711,"revision":3}],"suppressed":true,"customTagAudit":[{"customTagGuid":"87f2364f","valueGuid":"bb824e8d"}]}`, bodyContent)
+	})
+}
+
+func TestGetProjectVersionsOfProject(t *testing.T) {
+	// Start a local HTTP server
+	var starts []string
+	sys, server := spinUpServer(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/projects/4711/versions" {
+			start := req.URL.Query().Get("start")
+			starts = append(starts, start)
+			header := rw.Header()
+			header.Add("Content-type", "application/json")
+			if start == "0" {
+				rw.Write([]byte(fmt.Sprintf(`{"data": [%v], "count": 201, "responseCode": 200}`, strings.TrimSuffix(strings.Repeat(`{"id": 1, "name": "PR-1"},`, 200), ","))))
+				return
+			}
+			rw.Write([]byte(`{"data": [{"id": 2, "name": "master"}], "count": 201, "responseCode": 200}`))
+			return
+		}
+	})
+	// Close the server when test finishes
+	defer server.Close()
+
+	t.Run("test success", func(t *testing.T) {
+		result, err := sys.GetProjectVersionsOfProject(4711)
+		assert.NoError(t, err, "GetProjectVersionsOfProject call not successful")
+		assert.Equal(t, 201, len(result))
+		assert.Equal(t, "master", *result[200].Name)
+		assert.Equal(t, []string{"0", "200"}, starts)
+	})
+}
+
+func TestDeleteProjectVersion(t *testing.T) {
+	// Start a local HTTP server
+	deleted := false
+	sys, server := spinUpServer(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/projectVersions/10172" && req.Method == "DELETE" {
+			deleted = true
+			header := rw.Header()
+			header.Add("Content-type", "application/json")
+			rw.Write([]byte(`{"responseCode": 200}`))
+			return
+		}
+	})
+	// Close the server when test finishes
+	defer server.Close()
+
+	t.Run("test success", func(t *testing.T) {
+		err := sys.DeleteProjectVersion(10172)
+		assert.NoError(t, err, "DeleteProjectVersion call not successful")
+		assert.True(t, deleted)
+	})
+}
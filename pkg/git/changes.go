@@ -0,0 +1,167 @@
+package git
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/orchestrator"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/pkg/errors"
+)
+
+// ChangeRange describes the revisions between which the changes of a pipeline run are analyzed.
+// Base is empty if the changes start with the root commit of the repository, all files of the head revision are changed then.
+type ChangeRange struct {
+	Base string `json:"base"`
+	Head string `json:"head"`
+}
+
+// DetermineChangeRange returns the change range of the current pipeline run.
+// For pull requests the changes are determined against the target branch, otherwise the change set
+// of the build as provided by the orchestrator is resolved in the repository.
+func DetermineChangeRange(provider orchestrator.OrchestratorSpecificConfigProviding, repo *git.Repository) (ChangeRange, error) {
+	if provider.IsPullRequest() {
+		base := strings.TrimPrefix(provider.GetPullRequestConfig().Base, "refs/heads/")
+		if len(base) == 0 || base == "n/a" {
+			return ChangeRange{}, errors.New("target branch of the pull request is not available")
+		}
+		return ChangeRange{Base: base, Head: "HEAD"}, nil
+	}
+	changeSet := provider.GetChangeSet()
+	if len(changeSet) == 0 {
+		return ChangeRange{}, errors.New("no pull request and no change set available")
+	}
+	base, err := changeSetBase(repo, changeSet)
+	if err != nil {
+		return ChangeRange{}, err
+	}
+	return ChangeRange{Base: base, Head: "HEAD"}, nil
+}
+
+// changeSetBase returns the parent of the oldest commit of the change set independent of the order in which the
+// orchestrator lists the commits. It returns an empty revision if the oldest commit is the root commit of the repository.
+func changeSetBase(repo *git.Repository, changeSet []orchestrator.ChangeSet) (string, error) {
+	var oldest *object.Commit
+	for _, change := range changeSet {
+		commit, err := getCommitObject(change.CommitId, repo)
+		if err != nil {
+			return "", errors.Wrapf(err, "Cannot determine changes (commit '%s' of the change set not found)", change.CommitId)
+		}
+		if oldest == nil {
+			oldest = commit
+			continue
+		}
+		isAncestor, err := commit.IsAncestor(oldest)
+		if err != nil {
+			return "", errors.Wrap(err, "Cannot determine the oldest commit of the change set")
+		}
+		if isAncestor {
+			oldest = commit
+			continue
+		}
+		// commits of merged branches are not related to each other, the earlier one is considered
+		isDescendant, err := oldest.IsAncestor(commit)
+		if err != nil {
+			return "", errors.Wrap(err, "Cannot determine the oldest commit of the change set")
+		}
+		if !isDescendant && commit.Committer.When.Before(oldest.Committer.When) {
+			oldest = commit
+		}
+	}
+	if oldest.NumParents() == 0 {
+		return "", nil
+	}
+	return oldest.ParentHashes[0].String(), nil
+}
+
+// ChangedFilesOfCurrentRun opens the git repository at the given path and returns the files changed in the current pipeline run.
+func ChangedFilesOfCurrentRun(path string) (ChangeRange, []string, error) {
+	provider, err := orchestrator.NewOrchestratorSpecificConfigProvider()
+	if err != nil {
+		return ChangeRange{}, nil, err
+	}
+	repo, err := PlainOpen(path)
+	if err != nil {
+		return ChangeRange{}, nil, err
+	}
+	changeRange, err := DetermineChangeRange(provider, repo)
+	if err != nil {
+		return ChangeRange{}, nil, err
+	}
+	files, err := ChangedFiles(repo, changeRange)
+	return changeRange, files, err
+}
+
+// ChangedFiles returns the files which have been added, modified, deleted or renamed on the way from the
+// merge base of both revisions to the head revision, i.e. the changes of a pull request.
+// The base revision is looked up as remote branch of "origin" first. All files of the head revision are returned if the base is empty.
+func ChangedFiles(repo *git.Repository, changeRange ChangeRange) ([]string, error) {
+	head, err := getCommitObject(changeRange.Head, repo)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Cannot determine changes (head: '%s' not found)", changeRange.Head)
+	}
+	if len(changeRange.Base) == 0 {
+		headTree, err := head.Tree()
+		if err != nil {
+			return nil, errors.Wrap(err, "Cannot read tree of head")
+		}
+		return diffTrees(&object.Tree{}, headTree)
+	}
+	base, err := getCommitObject("origin/"+changeRange.Base, repo)
+	if err != nil {
+		base, err = getCommitObject(changeRange.Base, repo)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Cannot determine changes (base: '%s' not found)", changeRange.Base)
+		}
+	}
+	mergeBases, err := base.MergeBase(head)
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot determine merge base")
+	}
+	if len(mergeBases) == 0 {
+		return nil, errors.Errorf("'%s' and '%s' do not have a common ancestor", changeRange.Base, changeRange.Head)
+	}
+
+	files, err := diffFiles(mergeBases[0], head)
+	if err != nil {
+		return nil, err
+	}
+	log.Entry().Debugf("%v files changed between '%s' and '%s'", len(files), changeRange.Base, changeRange.Head)
+	return files, nil
+}
+
+// diffFiles returns the names of the files which differ between both commits
+func diffFiles(from, to *object.Commit) ([]string, error) {
+	fromTree, err := from.Tree()
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot read tree of base")
+	}
+	toTree, err := to.Tree()
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot read tree of head")
+	}
+	return diffTrees(fromTree, toTree)
+}
+
+// diffTrees returns the names of the files which differ between both trees
+func diffTrees(fromTree, toTree *object.Tree) ([]string, error) {
+	changes, err := object.DiffTree(fromTree, toTree)
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot compare trees")
+	}
+
+	known := map[string]bool{}
+	files := []string{}
+	for _, change := range changes {
+		for _, name := range []string{change.From.Name, change.To.Name} {
+			if len(name) > 0 && !known[name] {
+				known[name] = true
+				files = append(files, name)
+			}
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
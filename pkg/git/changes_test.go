@@ -0,0 +1,171 @@
+package git
+
+import (
+	"testing"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/orchestrator"
+	"github.com/go-git/go-billy/v5"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type orchestratorMock struct {
+	orchestrator.OrchestratorSpecificConfigProviding
+	pullRequest bool
+	base        string
+	changeSet   []orchestrator.ChangeSet
+}
+
+func (o *orchestratorMock) IsPullRequest() bool {
+	return o.pullRequest
+}
+
+func (o *orchestratorMock) GetPullRequestConfig() orchestrator.PullRequestConfig {
+	return orchestrator.PullRequestConfig{Base: o.base}
+}
+
+func (o *orchestratorMock) GetChangeSet() []orchestrator.ChangeSet {
+	return o.changeSet
+}
+
+// commitFiles commits the given files and the removal of the given files
+func commitFiles(t *testing.T, r *git.Repository, fs billy.Filesystem, files map[string]string, removed ...string) plumbing.Hash {
+	w, err := r.Worktree()
+	require.NoError(t, err)
+	for name, content := range files {
+		f, err := fs.Create(name)
+		require.NoError(t, err)
+		_, err = f.Write([]byte(content))
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+		_, err = w.Add(name)
+		require.NoError(t, err)
+	}
+	for _, name := range removed {
+		_, err = w.Remove(name)
+		require.NoError(t, err)
+	}
+	hash, err := w.Commit("change", &git.CommitOptions{Author: &object.Signature{Name: "test", When: time.Now()}})
+	require.NoError(t, err)
+	return hash
+}
+
+func TestDetermineChangeRange(t *testing.T) {
+	t.Parallel()
+	t.Run("pull request", func(t *testing.T) {
+		t.Parallel()
+		changeRange, err := DetermineChangeRange(&orchestratorMock{pullRequest: true, base: "refs/heads/main"}, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, ChangeRange{Base: "main", Head: "HEAD"}, changeRange)
+	})
+
+	t.Run("pull request without target branch", func(t *testing.T) {
+		t.Parallel()
+		_, err := DetermineChangeRange(&orchestratorMock{pullRequest: true, base: "n/a"}, nil)
+		assert.EqualError(t, err, "target branch of the pull request is not available")
+	})
+
+	fs := memfs.New()
+	r, err := git.Init(memory.NewStorage(), fs)
+	require.NoError(t, err)
+	root := commitFiles(t, r, fs, map[string]string{"README.md": "readme"})
+	first := commitFiles(t, r, fs, map[string]string{"a/pom.xml": "<project/>"})
+	second := commitFiles(t, r, fs, map[string]string{"b/main.go": "package main"})
+	third := commitFiles(t, r, fs, map[string]string{"README.md": "updated readme"})
+	changeSet := func(commits ...plumbing.Hash) []orchestrator.ChangeSet {
+		changes := []orchestrator.ChangeSet{}
+		for _, commit := range commits {
+			changes = append(changes, orchestrator.ChangeSet{CommitId: commit.String()})
+		}
+		return changes
+	}
+
+	t.Run("change set oldest first", func(t *testing.T) {
+		t.Parallel()
+		changeRange, err := DetermineChangeRange(&orchestratorMock{changeSet: changeSet(first, second, third)}, r)
+		assert.NoError(t, err)
+		assert.Equal(t, ChangeRange{Base: root.String(), Head: "HEAD"}, changeRange)
+	})
+
+	t.Run("change set newest first", func(t *testing.T) {
+		t.Parallel()
+		changeRange, err := DetermineChangeRange(&orchestratorMock{changeSet: changeSet(third, second, first)}, r)
+		assert.NoError(t, err)
+		assert.Equal(t, ChangeRange{Base: root.String(), Head: "HEAD"}, changeRange)
+
+		files, err := ChangedFiles(r, changeRange)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"README.md", "a/pom.xml", "b/main.go"}, files)
+	})
+
+	t.Run("change set starting with the root commit", func(t *testing.T) {
+		t.Parallel()
+		changeRange, err := DetermineChangeRange(&orchestratorMock{changeSet: changeSet(second, root, first)}, r)
+		assert.NoError(t, err)
+		assert.Equal(t, ChangeRange{Base: "", Head: "HEAD"}, changeRange)
+
+		files, err := ChangedFiles(r, changeRange)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"README.md", "a/pom.xml", "b/main.go"}, files)
+	})
+
+	t.Run("change set with unknown commit", func(t *testing.T) {
+		t.Parallel()
+		_, err := DetermineChangeRange(&orchestratorMock{changeSet: []orchestrator.ChangeSet{{CommitId: "abc"}}}, r)
+		assert.Contains(t, err.Error(), "Cannot determine changes (commit 'abc' of the change set not found)")
+	})
+
+	t.Run("no changes available", func(t *testing.T) {
+		t.Parallel()
+		_, err := DetermineChangeRange(&orchestratorMock{}, nil)
+		assert.EqualError(t, err, "no pull request and no change set available")
+	})
+}
+
+func TestChangedFiles(t *testing.T) {
+	t.Parallel()
+
+	checkout := func(t *testing.T, r *git.Repository, branch string, create bool) {
+		w, err := r.Worktree()
+		require.NoError(t, err)
+		require.NoError(t, w.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName(branch), Create: create}))
+	}
+
+	fs := memfs.New()
+	r, err := git.Init(memory.NewStorage(), fs)
+	require.NoError(t, err)
+	commitFiles(t, r, fs, map[string]string{"README.md": "readme", "a/pom.xml": "<project/>", "b/main.go": "package main"})
+	checkout(t, r, "master", false)
+	checkout(t, r, "feature", true)
+	commitFiles(t, r, fs, map[string]string{"a/App.java": "class App {}"}, "b/main.go")
+	checkout(t, r, "master", false)
+	// changes on the target branch after branching off must not be reported
+	commitFiles(t, r, fs, map[string]string{"README.md": "updated readme"})
+	checkout(t, r, "feature", false)
+
+	t.Run("changes of the branch", func(t *testing.T) {
+		t.Parallel()
+		files, err := ChangedFiles(r, ChangeRange{Base: "master", Head: "HEAD"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a/App.java", "b/main.go"}, files)
+	})
+
+	t.Run("no changes", func(t *testing.T) {
+		t.Parallel()
+		files, err := ChangedFiles(r, ChangeRange{Base: "feature", Head: "feature"})
+		assert.NoError(t, err)
+		assert.Empty(t, files)
+	})
+
+	t.Run("unknown base", func(t *testing.T) {
+		t.Parallel()
+		_, err := ChangedFiles(r, ChangeRange{Base: "develop", Head: "HEAD"})
+		assert.Contains(t, err.Error(), "Cannot determine changes (base: 'develop' not found)")
+	})
+}
@@ -0,0 +1,349 @@
+package git
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/maven"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/bmatcuk/doublestar"
+	"golang.org/x/mod/modfile"
+)
+
+// Module types supported by the change analysis
+const (
+	ModuleTypeMaven  = "maven"
+	ModuleTypeNpm    = "npm"
+	ModuleTypeGolang = "golang"
+)
+
+// buildDescriptors are files whose modification affects the module in the same directory and all modules below
+var buildDescriptors = map[string][]string{
+	ModuleTypeMaven:  {"pom.xml"},
+	ModuleTypeNpm:    {"package.json", "package-lock.json", "npm-shrinkwrap.json", "yarn.lock"},
+	ModuleTypeGolang: {"go.mod", "go.sum"},
+}
+
+// Module describes a build unit of the repository, i.e. a Maven module, an npm workspace or a Go package.
+type Module struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+	// Path is the directory of the module relative to the repository root using forward slashes
+	Path string `json:"path"`
+	// Dependencies contains the names of the modules of the same type this module depends on
+	Dependencies []string `json:"-"`
+	// Aggregator modules only bundle other modules, changes of their build descriptors affect all bundled modules
+	Aggregator bool `json:"-"`
+}
+
+// ChangeAnalysis contains the result of mapping the changed files to the modules of the repository.
+type ChangeAnalysis struct {
+	ChangeRange     ChangeRange `json:"changeRange"`
+	ChangedFiles    []string    `json:"changedFiles"`
+	AffectedModules []Module    `json:"affectedModules"`
+	SkippedModules  []Module    `json:"skippedModules"`
+}
+
+// ModuleUtils provides the file system access required for discovering modules.
+type ModuleUtils interface {
+	FileExists(path string) (bool, error)
+	FileRead(path string) ([]byte, error)
+	Glob(pattern string) (matches []string, err error)
+}
+
+// DiscoverMavenModules returns the modules of the Maven project in the given directory.
+func DiscoverMavenModules(dir string, utils ModuleUtils) ([]Module, error) {
+	modules := []Module{}
+	err := maven.VisitAllMavenModules(dir, utils, nil, func(info maven.ModuleInfo) error {
+		groupID := info.Project.GroupID
+		if len(groupID) == 0 {
+			groupID = info.Project.Parent.GroupID
+		}
+		module := Module{
+			Type:       ModuleTypeMaven,
+			Name:       groupID + ":" + info.Project.ArtifactID,
+			Path:       modulePath(filepath.Dir(info.PomXMLPath)),
+			Aggregator: info.Project.Packaging == "pom",
+		}
+		for _, dependency := range info.Project.Dependencies {
+			module.Dependencies = append(module.Dependencies, dependency.GroupID+":"+dependency.ArtifactID)
+		}
+		modules = append(modules, module)
+		return nil
+	})
+	return modules, err
+}
+
+type packageJSON struct {
+	Name                 string            `json:"name"`
+	Workspaces           json.RawMessage   `json:"workspaces"`
+	Dependencies         map[string]string `json:"dependencies"`
+	DevDependencies      map[string]string `json:"devDependencies"`
+	PeerDependencies     map[string]string `json:"peerDependencies"`
+	OptionalDependencies map[string]string `json:"optionalDependencies"`
+}
+
+// workspacePatterns supports both the array notation and the object notation with "packages"
+func (p packageJSON) workspacePatterns() []string {
+	patterns := []string{}
+	if len(p.Workspaces) == 0 || json.Unmarshal(p.Workspaces, &patterns) == nil {
+		return patterns
+	}
+	workspaces := struct {
+		Packages []string `json:"packages"`
+	}{}
+	_ = json.Unmarshal(p.Workspaces, &workspaces)
+	return workspaces.Packages
+}
+
+// DiscoverNpmWorkspaces returns the npm project in the root directory and its workspaces.
+func DiscoverNpmWorkspaces(utils ModuleUtils) ([]Module, error) {
+	if exists, _ := utils.FileExists("package.json"); !exists {
+		return []Module{}, nil
+	}
+	root, err := readPackageJSON("package.json", utils)
+	if err != nil {
+		return nil, err
+	}
+	rootModule := root.toModule(".")
+	rootModule.Aggregator = len(root.workspacePatterns()) > 0
+	modules := []Module{rootModule}
+	for _, pattern := range root.workspacePatterns() {
+		matches, err := utils.Glob(path.Join(pattern, "package.json"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve workspace pattern '%s': %w", pattern, err)
+		}
+		sort.Strings(matches)
+		for _, match := range matches {
+			if strings.Contains(filepath.ToSlash(match), "node_modules/") {
+				continue
+			}
+			workspace, err := readPackageJSON(match, utils)
+			if err != nil {
+				return nil, err
+			}
+			modules = append(modules, workspace.toModule(filepath.Dir(match)))
+		}
+	}
+	return modules, nil
+}
+
+func readPackageJSON(file string, utils ModuleUtils) (packageJSON, error) {
+	descriptor := packageJSON{}
+	content, err := utils.FileRead(file)
+	if err != nil {
+		return descriptor, fmt.Errorf("failed to read file contents of '%s': %w", file, err)
+	}
+	if err := json.Unmarshal(content, &descriptor); err != nil {
+		return descriptor, fmt.Errorf("failed to parse file contents of '%s': %w", file, err)
+	}
+	return descriptor, nil
+}
+
+func (p packageJSON) toModule(dir string) Module {
+	module := Module{Type: ModuleTypeNpm, Name: p.Name, Path: modulePath(dir)}
+	for _, dependencies := range []map[string]string{p.Dependencies, p.DevDependencies, p.PeerDependencies, p.OptionalDependencies} {
+		module.Dependencies = append(module.Dependencies, piperutils.Keys(dependencies)...)
+	}
+	sort.Strings(module.Dependencies)
+	return module
+}
+
+// DiscoverGoPackages returns the packages of the Go module in the root directory.
+// Vendored packages, test data and packages of nested Go modules are not considered.
+func DiscoverGoPackages(utils ModuleUtils) ([]Module, error) {
+	if exists, _ := utils.FileExists("go.mod"); !exists {
+		return []Module{}, nil
+	}
+	goMod, err := utils.FileRead("go.mod")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file contents of 'go.mod': %w", err)
+	}
+	modulePathName := modfile.ModulePath(goMod)
+	if len(modulePathName) == 0 {
+		return nil, fmt.Errorf("failed to determine module path from 'go.mod'")
+	}
+
+	nestedModules, err := utils.Glob("**/go.mod")
+	if err != nil {
+		return nil, fmt.Errorf("failed to search nested Go modules: %w", err)
+	}
+	excludes := []string{}
+	for _, nested := range nestedModules {
+		if dir := modulePath(filepath.Dir(nested)); dir != "." {
+			excludes = append(excludes, dir+"/")
+		}
+	}
+
+	goFiles, err := utils.Glob("**/*.go")
+	if err != nil {
+		return nil, fmt.Errorf("failed to search Go files: %w", err)
+	}
+	sort.Strings(goFiles)
+	packages := map[string]*Module{}
+	dirs := []string{}
+	for _, file := range goFiles {
+		dir := modulePath(filepath.Dir(file))
+		if isIgnoredGoPackage(dir) || hasAnyPrefix(dir+"/", excludes) {
+			continue
+		}
+		pkg, ok := packages[dir]
+		if !ok {
+			name := modulePathName
+			if dir != "." {
+				name = modulePathName + "/" + dir
+			}
+			pkg = &Module{Type: ModuleTypeGolang, Name: name, Path: dir}
+			packages[dir] = pkg
+			dirs = append(dirs, dir)
+		}
+		content, err := utils.FileRead(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file contents of '%s': %w", file, err)
+		}
+		parsed, err := parser.ParseFile(token.NewFileSet(), file, content, parser.ImportsOnly)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse imports of '%s': %w", file, err)
+		}
+		for _, spec := range parsed.Imports {
+			imported, _ := strconv.Unquote(spec.Path.Value)
+			if (imported == modulePathName || strings.HasPrefix(imported, modulePathName+"/")) && !piperutils.ContainsString(pkg.Dependencies, imported) {
+				pkg.Dependencies = append(pkg.Dependencies, imported)
+			}
+		}
+	}
+
+	modules := []Module{}
+	for _, dir := range dirs {
+		modules = append(modules, *packages[dir])
+	}
+	return modules, nil
+}
+
+func isIgnoredGoPackage(dir string) bool {
+	for _, element := range strings.Split(dir, "/") {
+		if element == "vendor" || element == "testdata" || (element != "." && (strings.HasPrefix(element, ".") || strings.HasPrefix(element, "_"))) {
+			return true
+		}
+	}
+	return false
+}
+
+// AnalyzeChanges determines the modules affected by the changed files.
+// A file affects the innermost module of each type containing it unless this is an aggregator. A changed build descriptor affects all modules
+// of the respective type in and below its directory, a file matching one of the global patterns affects all modules.
+// Modules depending on an affected module of the same type are affected as well.
+func AnalyzeChanges(changeRange ChangeRange, changedFiles []string, modules []Module, globalPatterns []string) *ChangeAnalysis {
+	affected := map[int]bool{}
+	for _, file := range changedFiles {
+		if matchesAny(file, globalPatterns) {
+			for i := range modules {
+				affected[i] = true
+			}
+			break
+		}
+		dir, name := path.Split(file)
+		dir = modulePath(dir)
+		innermost := map[string]int{}
+		for i, module := range modules {
+			if piperutils.ContainsString(buildDescriptors[module.Type], name) && isWithin(module.Path, dir) {
+				affected[i] = true
+			}
+			if isWithin(file, module.Path) {
+				if current, ok := innermost[module.Type]; !ok || len(modules[current].Path) < len(module.Path) || modules[current].Path == "." {
+					innermost[module.Type] = i
+				}
+			}
+		}
+		for _, i := range innermost {
+			if !modules[i].Aggregator {
+				affected[i] = true
+			}
+		}
+	}
+
+	// propagate to dependent modules until nothing changes anymore
+	for propagated := true; propagated; {
+		propagated = false
+		for i, module := range modules {
+			if affected[i] {
+				continue
+			}
+			for j, other := range modules {
+				if affected[j] && other.Type == module.Type && piperutils.ContainsString(module.Dependencies, other.Name) {
+					affected[i] = true
+					propagated = true
+					break
+				}
+			}
+		}
+	}
+
+	analysis := &ChangeAnalysis{ChangeRange: changeRange, ChangedFiles: changedFiles, AffectedModules: []Module{}, SkippedModules: []Module{}}
+	for i, module := range modules {
+		if affected[i] {
+			analysis.AffectedModules = append(analysis.AffectedModules, module)
+		} else {
+			analysis.SkippedModules = append(analysis.SkippedModules, module)
+		}
+	}
+	return analysis
+}
+
+// ReportUtils provides the file system access required for writing the change analysis report.
+type ReportUtils interface {
+	FileWrite(path string, content []byte, perm os.FileMode) error
+}
+
+// WriteChangeAnalysisReport writes the result of the change analysis as JSON file for the given step.
+func WriteChangeAnalysisReport(analysis *ChangeAnalysis, stepName string, utils ReportUtils) (piperutils.Path, error) {
+	content, err := json.MarshalIndent(analysis, "", "  ")
+	if err != nil {
+		return piperutils.Path{}, fmt.Errorf("failed to marshal change analysis: %w", err)
+	}
+	reportPath := fmt.Sprintf("%v_changeAnalysis.json", stepName)
+	if err := utils.FileWrite(reportPath, content, 0o666); err != nil {
+		return piperutils.Path{}, fmt.Errorf("failed to write change analysis report: %w", err)
+	}
+	return piperutils.Path{Name: "Change Analysis", Target: reportPath}, nil
+}
+
+// modulePath normalizes a directory to a slash separated path relative to the repository root
+func modulePath(dir string) string {
+	dir = strings.TrimSuffix(path.Clean("/"+filepath.ToSlash(dir)), "/")
+	if len(dir) == 0 {
+		return "."
+	}
+	return strings.TrimPrefix(dir, "/")
+}
+
+// isWithin reports whether the file or directory is located in the given directory
+func isWithin(file, dir string) bool {
+	return dir == "." || file == dir || strings.HasPrefix(file, dir+"/")
+}
+
+func hasAnyPrefix(value string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(value, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAny(file string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := doublestar.Match(pattern, file); matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,181 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscoverMavenModules(t *testing.T) {
+	t.Parallel()
+	utils := &mock.FilesMock{}
+	utils.AddFile("pom.xml", []byte(`<project><groupId>com.example</groupId><artifactId>root</artifactId><packaging>pom</packaging><modules><module>core</module><module>app</module></modules></project>`))
+	utils.AddFile("core/pom.xml", []byte(`<project><parent><groupId>com.example</groupId></parent><artifactId>core</artifactId></project>`))
+	utils.AddFile("app/pom.xml", []byte(`<project><parent><groupId>com.example</groupId></parent><artifactId>app</artifactId>
+<dependencies><dependency><groupId>com.example</groupId><artifactId>core</artifactId></dependency></dependencies></project>`))
+
+	modules, err := DiscoverMavenModules(".", utils)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Module{
+		{Type: ModuleTypeMaven, Name: "com.example:root", Path: ".", Aggregator: true},
+		{Type: ModuleTypeMaven, Name: "com.example:core", Path: "core"},
+		{Type: ModuleTypeMaven, Name: "com.example:app", Path: "app", Dependencies: []string{"com.example:core"}},
+	}, modules)
+}
+
+func TestDiscoverNpmWorkspaces(t *testing.T) {
+	t.Parallel()
+	t.Run("workspaces", func(t *testing.T) {
+		t.Parallel()
+		utils := &mock.FilesMock{}
+		utils.AddFile("package.json", []byte(`{"name": "root", "workspaces": ["packages/*"]}`))
+		utils.AddFile("packages/ui/package.json", []byte(`{"name": "@example/ui", "dependencies": {"@example/utils": "*", "react": "^18"}}`))
+		utils.AddFile("packages/utils/package.json", []byte(`{"name": "@example/utils"}`))
+		utils.AddFile("packages/utils/node_modules/foo/package.json", []byte(`{"name": "foo"}`))
+
+		modules, err := DiscoverNpmWorkspaces(utils)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []Module{
+			{Type: ModuleTypeNpm, Name: "root", Path: ".", Aggregator: true},
+			{Type: ModuleTypeNpm, Name: "@example/ui", Path: "packages/ui", Dependencies: []string{"@example/utils", "react"}},
+			{Type: ModuleTypeNpm, Name: "@example/utils", Path: "packages/utils"},
+		}, modules)
+	})
+
+	t.Run("workspaces in object notation", func(t *testing.T) {
+		t.Parallel()
+		utils := &mock.FilesMock{}
+		utils.AddFile("package.json", []byte(`{"name": "root", "workspaces": {"packages": ["lib"]}}`))
+		utils.AddFile("lib/package.json", []byte(`{"name": "lib"}`))
+
+		modules, err := DiscoverNpmWorkspaces(utils)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, len(modules))
+		assert.Equal(t, "lib", modules[1].Path)
+	})
+
+	t.Run("no npm project", func(t *testing.T) {
+		t.Parallel()
+		modules, err := DiscoverNpmWorkspaces(&mock.FilesMock{})
+		assert.NoError(t, err)
+		assert.Empty(t, modules)
+	})
+}
+
+func TestDiscoverGoPackages(t *testing.T) {
+	t.Parallel()
+	utils := &mock.FilesMock{}
+	utils.AddFile("go.mod", []byte("module github.com/example/app\n\ngo 1.18\n"))
+	utils.AddFile("main.go", []byte("package main\n\nimport (\n\t\"fmt\"\n\t\"github.com/example/app/pkg/util\"\n)\n"))
+	utils.AddFile("pkg/util/util.go", []byte("package util\n"))
+	utils.AddFile("pkg/util/util_test.go", []byte("package util\n\nimport \"testing\"\n"))
+	utils.AddFile("pkg/util/testdata/sample.go", []byte("package sample\n"))
+	utils.AddFile("vendor/github.com/lib/lib.go", []byte("package lib\n"))
+	utils.AddFile("tools/go.mod", []byte("module github.com/example/tools\n"))
+	utils.AddFile("tools/tool.go", []byte("package tools\n"))
+
+	modules, err := DiscoverGoPackages(utils)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []Module{
+		{Type: ModuleTypeGolang, Name: "github.com/example/app", Path: ".", Dependencies: []string{"github.com/example/app/pkg/util"}},
+		{Type: ModuleTypeGolang, Name: "github.com/example/app/pkg/util", Path: "pkg/util"},
+	}, modules)
+}
+
+func TestAnalyzeChanges(t *testing.T) {
+	t.Parallel()
+	changeRange := ChangeRange{Base: "main", Head: "HEAD"}
+	modules := []Module{
+		{Type: ModuleTypeMaven, Name: "root", Path: ".", Aggregator: true},
+		{Type: ModuleTypeMaven, Name: "core", Path: "core"},
+		{Type: ModuleTypeMaven, Name: "app", Path: "app", Dependencies: []string{"core"}},
+		{Type: ModuleTypeMaven, Name: "docs", Path: "docs"},
+		{Type: ModuleTypeNpm, Name: "ui", Path: "app/ui"},
+	}
+	names := func(modules []Module) []string {
+		result := []string{}
+		for _, module := range modules {
+			result = append(result, module.Name)
+		}
+		return result
+	}
+
+	t.Run("file of a module", func(t *testing.T) {
+		t.Parallel()
+		analysis := AnalyzeChanges(changeRange, []string{"docs/src/index.md"}, modules, nil)
+		assert.Equal(t, []string{"docs"}, names(analysis.AffectedModules))
+		assert.Equal(t, []string{"root", "core", "app", "ui"}, names(analysis.SkippedModules))
+	})
+
+	t.Run("dependent modules are affected", func(t *testing.T) {
+		t.Parallel()
+		analysis := AnalyzeChanges(changeRange, []string{"core/src/main/java/Core.java"}, modules, nil)
+		assert.Equal(t, []string{"core", "app"}, names(analysis.AffectedModules))
+	})
+
+	t.Run("innermost module per type", func(t *testing.T) {
+		t.Parallel()
+		analysis := AnalyzeChanges(changeRange, []string{"app/ui/index.js"}, modules, nil)
+		assert.Equal(t, []string{"app", "ui"}, names(analysis.AffectedModules))
+	})
+
+	t.Run("build descriptor affects modules below", func(t *testing.T) {
+		t.Parallel()
+		analysis := AnalyzeChanges(changeRange, []string{"pom.xml"}, modules, nil)
+		assert.Equal(t, []string{"root", "core", "app", "docs"}, names(analysis.AffectedModules))
+		assert.Equal(t, []string{"ui"}, names(analysis.SkippedModules))
+	})
+
+	t.Run("file outside of the modules", func(t *testing.T) {
+		t.Parallel()
+		analysis := AnalyzeChanges(changeRange, []string{"README.md"}, modules[1:], nil)
+		assert.Empty(t, analysis.AffectedModules)
+		assert.Equal(t, 4, len(analysis.SkippedModules))
+	})
+
+	t.Run("file of an aggregator", func(t *testing.T) {
+		t.Parallel()
+		analysis := AnalyzeChanges(changeRange, []string{"README.md", "src/site/index.md"}, modules, nil)
+		assert.Empty(t, analysis.AffectedModules)
+		assert.Equal(t, 5, len(analysis.SkippedModules))
+	})
+
+	t.Run("file of a module without aggregator", func(t *testing.T) {
+		t.Parallel()
+		analysis := AnalyzeChanges(changeRange, []string{"README.md"}, []Module{{Type: ModuleTypeMaven, Name: "single", Path: "."}}, nil)
+		assert.Equal(t, []string{"single"}, names(analysis.AffectedModules))
+	})
+
+	t.Run("global file", func(t *testing.T) {
+		t.Parallel()
+		analysis := AnalyzeChanges(changeRange, []string{"docs/index.md", ".pipeline/config.yml"}, modules, []string{".pipeline/**"})
+		assert.Equal(t, 5, len(analysis.AffectedModules))
+		assert.Empty(t, analysis.SkippedModules)
+	})
+}
+
+func TestWriteChangeAnalysisReport(t *testing.T) {
+	t.Parallel()
+	utils := &mock.FilesMock{}
+	analysis := &ChangeAnalysis{
+		ChangeRange:     ChangeRange{Base: "main", Head: "HEAD"},
+		ChangedFiles:    []string{"core/Core.java"},
+		AffectedModules: []Module{{Type: ModuleTypeMaven, Name: "core", Path: "core", Dependencies: []string{"lib"}}},
+		SkippedModules:  []Module{},
+	}
+
+	report, err := WriteChangeAnalysisReport(analysis, "mavenExecuteStaticCodeChecks", utils)
+
+	require.NoError(t, err)
+	assert.Equal(t, "mavenExecuteStaticCodeChecks_changeAnalysis.json", report.Target)
+	content, err := utils.FileRead(report.Target)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"changeRange": {"base": "main", "head": "HEAD"}, "changedFiles": ["core/Core.java"],
+		"affectedModules": [{"type": "maven", "name": "core", "path": "core"}], "skippedModules": []}`, string(content))
+}
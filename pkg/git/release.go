@@ -0,0 +1,109 @@
+package git
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/pkg/errors"
+)
+
+// ReleaseTags returns the names of all tags of the repository which start with the given prefix.
+func ReleaseTags(repo *git.Repository, prefix string) ([]string, error) {
+	tags, err := repo.Tags()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list tags")
+	}
+	names := []string{}
+	err = tags.ForEach(func(ref *plumbing.Reference) error {
+		name := ref.Name().Short()
+		if strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list tags")
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// CommitsSince returns the commits which are reachable from HEAD, but not from the given tag.
+// In case the tag is empty all commits reachable from HEAD are returned.
+func CommitsSince(repo *git.Repository, tag string) ([]*object.Commit, error) {
+	var iter object.CommitIter
+	var err error
+	if len(tag) > 0 {
+		iter, err = LogRange(repo, "refs/tags/"+tag, "HEAD")
+	} else {
+		iter, err = repo.Log(&git.LogOptions{})
+	}
+	if err != nil {
+		return nil, err
+	}
+	commits := []*object.Commit{}
+	err = iter.ForEach(func(c *object.Commit) error {
+		commits = append(commits, c)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read commits")
+	}
+	return commits, nil
+}
+
+// FilesChangedSince returns the files which differ between the given tag and HEAD.
+// In case the tagged commit is not part of the history of HEAD, like the version commit of a release created with
+// versioning type "cloud", the files are compared with its parent, i.e. the commit the release was built from.
+// In case the tag is empty all files of HEAD are returned.
+func FilesChangedSince(repo *git.Repository, tag string) ([]string, error) {
+	head, err := getCommitObject("HEAD", repo)
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot determine changes (head: 'HEAD' not found)")
+	}
+	if len(tag) > 0 {
+		base, err := getCommitObject("refs/tags/"+tag, repo)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Cannot determine changes (base: '%s' not found)", tag)
+		}
+		base, err = releaseSource(base, head)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Cannot determine changes (base: source of '%s' not found)", tag)
+		}
+		return diffFiles(base, head)
+	}
+
+	tree, err := head.Tree()
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot read tree of head")
+	}
+	files := []string{}
+	err = tree.Files().ForEach(func(file *object.File) error {
+		files = append(files, file.Name)
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Cannot list files of head")
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// releaseSource returns the commit a release was built from. This is the released commit itself in case it is part of the
+// history of HEAD, otherwise the released commit only updates the version and its parent is the source.
+func releaseSource(released, head *object.Commit) (*object.Commit, error) {
+	if released.NumParents() == 0 {
+		return released, nil
+	}
+	isAncestor, err := released.IsAncestor(head)
+	if err != nil {
+		return nil, err
+	}
+	if isAncestor {
+		return released, nil
+	}
+	return released.Parent(0)
+}
@@ -0,0 +1,132 @@
+package git
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-billy/v5/util"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReleaseHistory(t *testing.T) {
+	t.Parallel()
+	fs := memfs.New()
+	r, err := git.Init(memory.NewStorage(), fs)
+	require.NoError(t, err)
+	w, err := r.Worktree()
+	require.NoError(t, err)
+	signature := &object.Signature{Name: "test", When: time.Now()}
+	commit := func(t *testing.T, message string) plumbing.Hash {
+		require.NoError(t, util.WriteFile(fs, "CHANGELOG.md", []byte(message), 0644))
+		_, err := w.Add("CHANGELOG.md")
+		require.NoError(t, err)
+		hash, err := w.Commit(message, &git.CommitOptions{Author: signature})
+		require.NoError(t, err)
+		return hash
+	}
+
+	commit(t, "feat: initial version")
+	release := commit(t, "fix: first bug")
+	_, err = r.CreateTag("v1.0.0", release, &git.CreateTagOptions{Tagger: signature, Message: "release 1.0.0"})
+	require.NoError(t, err)
+	_, err = r.CreateTag("build_20221001", release, nil)
+	require.NoError(t, err)
+	commit(t, "feat: new feature")
+	commit(t, "docs: describe new feature")
+
+	t.Run("release tags", func(t *testing.T) {
+		t.Parallel()
+		tags, err := ReleaseTags(r, "v")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"v1.0.0"}, tags)
+	})
+
+	t.Run("commits since release", func(t *testing.T) {
+		t.Parallel()
+		commits, err := CommitsSince(r, "v1.0.0")
+		assert.NoError(t, err)
+		if assert.Len(t, commits, 2) {
+			assert.Equal(t, "docs: describe new feature", commits[0].Message)
+			assert.Equal(t, "feat: new feature", commits[1].Message)
+		}
+	})
+
+	t.Run("all commits", func(t *testing.T) {
+		t.Parallel()
+		commits, err := CommitsSince(r, "")
+		assert.NoError(t, err)
+		assert.Len(t, commits, 4)
+	})
+
+	t.Run("unknown tag", func(t *testing.T) {
+		t.Parallel()
+		_, err := CommitsSince(r, "v2.0.0")
+		assert.Contains(t, err.Error(), "Cannot provide log range (from: 'refs/tags/v2.0.0' not found)")
+	})
+
+	t.Run("files changed since release", func(t *testing.T) {
+		t.Parallel()
+		files, err := FilesChangedSince(r, "v1.0.0")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"CHANGELOG.md"}, files)
+	})
+
+	t.Run("files changed since lightweight tag", func(t *testing.T) {
+		t.Parallel()
+		files, err := FilesChangedSince(r, "build_20221001")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"CHANGELOG.md"}, files)
+	})
+
+	t.Run("all files", func(t *testing.T) {
+		t.Parallel()
+		files, err := FilesChangedSince(r, "")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"CHANGELOG.md"}, files)
+	})
+}
+
+func TestFilesChangedSinceVersionCommit(t *testing.T) {
+	fs := memfs.New()
+	r, err := git.Init(memory.NewStorage(), fs)
+	require.NoError(t, err)
+	w, err := r.Worktree()
+	require.NoError(t, err)
+	signature := &object.Signature{Name: "test", When: time.Now()}
+	commit := func(t *testing.T, file, content, message string) plumbing.Hash {
+		require.NoError(t, util.WriteFile(fs, file, []byte(content), 0644))
+		_, err := w.Add(file)
+		require.NoError(t, err)
+		hash, err := w.Commit(message, &git.CommitOptions{Author: signature})
+		require.NoError(t, err)
+		return hash
+	}
+
+	commit(t, "pom.xml", "<version>1.0.0-SNAPSHOT</version>", "feat: initial version")
+	source := commit(t, "src/App.java", "class App {}", "feat: app")
+	// the version commit of cloud versioning is tagged, but not pushed to the branch
+	release := commit(t, "pom.xml", "<version>1.0.0-20221001</version>", "update version 1.0.0-20221001")
+	_, err = r.CreateTag("build_20221001", release, nil)
+	require.NoError(t, err)
+	require.NoError(t, w.Reset(&git.ResetOptions{Commit: source, Mode: git.HardReset}))
+
+	t.Run("no changes since release", func(t *testing.T) {
+		files, err := FilesChangedSince(r, "build_20221001")
+		assert.NoError(t, err)
+		assert.Empty(t, files)
+	})
+
+	t.Run("changes since release", func(t *testing.T) {
+		commit(t, "src/Util.java", "class Util {}", "feat: util")
+
+		files, err := FilesChangedSince(r, "build_20221001")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"src/Util.java"}, files)
+	})
+}
@@ -0,0 +1,93 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/google/go-github/v45/github"
+	"github.com/pkg/errors"
+)
+
+type githubIssueCommentService interface {
+	ListComments(ctx context.Context, owner string, repo string, number int, opts *github.IssueListCommentsOptions) ([]*github.IssueComment, *github.Response, error)
+	CreateComment(ctx context.Context, owner string, repo string, number int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error)
+	EditComment(ctx context.Context, owner string, repo string, commentID int64, comment *github.IssueComment) (*github.IssueComment, *github.Response, error)
+}
+
+// PullRequestCommentOptions to configure the comment on a pull-request
+type PullRequestCommentOptions struct {
+	APIURL       string   `json:"apiUrl,omitempty"`
+	Body         []byte   `json:"body,omitempty"`
+	Owner        string   `json:"owner,omitempty"`
+	Repository   string   `json:"repository,omitempty"`
+	Number       int      `json:"number,omitempty"`
+	Marker       string   `json:"marker,omitempty"`
+	Token        string   `json:"token,omitempty"`
+	TrustedCerts []string `json:"trustedCerts,omitempty"`
+}
+
+// CreateOrUpdatePullRequestComment adds a comment to a pull-request.
+// If a marker is provided, an existing comment containing the marker is updated instead of adding another comment on each run.
+func CreateOrUpdatePullRequestComment(options *PullRequestCommentOptions) (*github.IssueComment, error) {
+	ctx, client, err := NewClient(options.Token, options.APIURL, "", options.TrustedCerts)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get GitHub client")
+	}
+	return createOrUpdatePullRequestCommentLocal(ctx, options, client.Issues)
+}
+
+func createOrUpdatePullRequestCommentLocal(ctx context.Context, options *PullRequestCommentOptions, ghCommentService githubIssueCommentService) (*github.IssueComment, error) {
+	body := string(options.Body)
+	var existingComment *github.IssueComment
+
+	if len(options.Marker) > 0 {
+		marker := fmt.Sprintf("<!-- %v -->", options.Marker)
+		body = marker + "\n" + body
+
+		listOptions := &github.IssueListCommentsOptions{ListOptions: github.ListOptions{PerPage: 100}}
+		for existingComment == nil {
+			comments, resp, err := ghCommentService.ListComments(ctx, options.Owner, options.Repository, options.Number, listOptions)
+			if err != nil {
+				if resp != nil {
+					log.Entry().Errorf("GitHub list comments returned response code %v", resp.Status)
+				}
+				return nil, errors.Wrap(err, "error occurred when looking for existing comment")
+			}
+			for _, comment := range comments {
+				if comment != nil && strings.Contains(comment.GetBody(), marker) {
+					existingComment = comment
+					break
+				}
+			}
+			if resp == nil || resp.NextPage == 0 {
+				break
+			}
+			listOptions.Page = resp.NextPage
+		}
+	}
+
+	comment := &github.IssueComment{Body: &body}
+	if existingComment != nil {
+		updatedComment, resp, err := ghCommentService.EditComment(ctx, options.Owner, options.Repository, existingComment.GetID(), comment)
+		if err != nil {
+			if resp != nil {
+				log.Entry().Errorf("GitHub edit comment returned response code %v", resp.Status)
+			}
+			return nil, errors.Wrap(err, "error occurred when updating existing comment")
+		}
+		log.Entry().Debugf("Comment %v updated on pull-request %v", existingComment.GetID(), options.Number)
+		return updatedComment, nil
+	}
+
+	newComment, resp, err := ghCommentService.CreateComment(ctx, options.Owner, options.Repository, options.Number, comment)
+	if err != nil {
+		if resp != nil {
+			log.Entry().Errorf("GitHub create comment returned response code %v", resp.Status)
+		}
+		return nil, errors.Wrap(err, "error occurred when adding comment to pull-request")
+	}
+	log.Entry().Debugf("Comment added on pull-request %v", options.Number)
+	return newComment, nil
+}
@@ -0,0 +1,117 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-github/v45/github"
+	"github.com/stretchr/testify/assert"
+)
+
+type ghIssueCommentMock struct {
+	comments     [][]*github.IssueComment
+	listError    error
+	createError  error
+	created      *github.IssueComment
+	edited       *github.IssueComment
+	editedID     int64
+	listedPages  []int
+	commentOwner string
+	commentRepo  string
+	number       int
+}
+
+func (g *ghIssueCommentMock) ListComments(ctx context.Context, owner string, repo string, number int, opts *github.IssueListCommentsOptions) ([]*github.IssueComment, *github.Response, error) {
+	g.listedPages = append(g.listedPages, opts.Page)
+	if g.listError != nil {
+		return nil, &github.Response{Response: &http.Response{Status: "401"}}, g.listError
+	}
+	page := opts.Page
+	if page == 0 {
+		page = 1
+	}
+	resp := &github.Response{Response: &http.Response{Status: "200"}}
+	if page < len(g.comments) {
+		resp.NextPage = page + 1
+	}
+	return g.comments[page-1], resp, nil
+}
+
+func (g *ghIssueCommentMock) CreateComment(ctx context.Context, owner string, repo string, number int, comment *github.IssueComment) (*github.IssueComment, *github.Response, error) {
+	g.commentOwner = owner
+	g.commentRepo = repo
+	g.number = number
+	g.created = comment
+	return comment, &github.Response{Response: &http.Response{Status: "200"}}, g.createError
+}
+
+func (g *ghIssueCommentMock) EditComment(ctx context.Context, owner string, repo string, commentID int64, comment *github.IssueComment) (*github.IssueComment, *github.Response, error) {
+	g.editedID = commentID
+	g.edited = comment
+	return comment, &github.Response{Response: &http.Response{Status: "200"}}, nil
+}
+
+func TestCreateOrUpdatePullRequestComment(t *testing.T) {
+	ctx := context.Background()
+	existingID := int64(42)
+	otherBody := "some other comment"
+	existingBody := "<!-- piper-sonar -->\nold summary"
+
+	t.Run("create without marker", func(t *testing.T) {
+		commentService := &ghIssueCommentMock{}
+		options := &PullRequestCommentOptions{Owner: "owner", Repository: "repo", Number: 7, Body: []byte("summary")}
+
+		comment, err := createOrUpdatePullRequestCommentLocal(ctx, options, commentService)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "summary", comment.GetBody())
+		assert.Equal(t, "owner", commentService.commentOwner)
+		assert.Equal(t, "repo", commentService.commentRepo)
+		assert.Equal(t, 7, commentService.number)
+		assert.Empty(t, commentService.listedPages)
+	})
+
+	t.Run("create with marker", func(t *testing.T) {
+		commentService := &ghIssueCommentMock{comments: [][]*github.IssueComment{{{Body: &otherBody}}}}
+		options := &PullRequestCommentOptions{Owner: "owner", Repository: "repo", Number: 7, Body: []byte("summary"), Marker: "piper-sonar"}
+
+		_, err := createOrUpdatePullRequestCommentLocal(ctx, options, commentService)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "<!-- piper-sonar -->\nsummary", commentService.created.GetBody())
+		assert.Nil(t, commentService.edited)
+	})
+
+	t.Run("update existing comment on second page", func(t *testing.T) {
+		commentService := &ghIssueCommentMock{comments: [][]*github.IssueComment{{{Body: &otherBody}}, {{ID: &existingID, Body: &existingBody}}}}
+		options := &PullRequestCommentOptions{Owner: "owner", Repository: "repo", Number: 7, Body: []byte("new summary"), Marker: "piper-sonar"}
+
+		_, err := createOrUpdatePullRequestCommentLocal(ctx, options, commentService)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []int{0, 2}, commentService.listedPages)
+		assert.Equal(t, existingID, commentService.editedID)
+		assert.Equal(t, "<!-- piper-sonar -->\nnew summary", commentService.edited.GetBody())
+		assert.Nil(t, commentService.created)
+	})
+
+	t.Run("list error", func(t *testing.T) {
+		commentService := &ghIssueCommentMock{listError: fmt.Errorf("list error")}
+		options := &PullRequestCommentOptions{Marker: "piper-sonar"}
+
+		_, err := createOrUpdatePullRequestCommentLocal(ctx, options, commentService)
+
+		assert.EqualError(t, err, "error occurred when looking for existing comment: list error")
+	})
+
+	t.Run("create error", func(t *testing.T) {
+		commentService := &ghIssueCommentMock{createError: fmt.Errorf("create error")}
+		options := &PullRequestCommentOptions{}
+
+		_, err := createOrUpdatePullRequestCommentLocal(ctx, options, commentService)
+
+		assert.EqualError(t, err, "error occurred when adding comment to pull-request: create error")
+	})
+}
@@ -0,0 +1,181 @@
+package gradle
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	modelTaskName = "piperProjectModel"
+	modelFileName = "piperProjectModel.json.tmp"
+)
+
+// modelInitScriptContent registers a task on the root project which writes the coordinates
+// and the archives of all projects of the build as JSON into the root project directory
+const modelInitScriptContent = `
+rootProject {
+    tasks.register('` + modelTaskName + `') {
+        doLast {
+            def projects = rootProject.allprojects.collect { p ->
+                def publication = p.extensions.findByName('publishing')?.publications?.withType(org.gradle.api.publish.maven.MavenPublication)?.find { true }
+                def archives = p.tasks.withType(org.gradle.api.tasks.bundling.Jar).collect { task ->
+                    [
+                        file: rootProject.relativePath(task.archiveFile.get().asFile),
+                        classifier: task.archiveClassifier.getOrElse(''),
+                        extension: task.archiveExtension.getOrElse('jar'),
+                    ]
+                }
+                def version = p.version.toString()
+                [
+                    path: p.path,
+                    name: p.name,
+                    dir: rootProject.relativePath(p.projectDir) ?: '.',
+                    groupId: publication?.groupId ?: p.group.toString(),
+                    artifactId: publication?.artifactId ?: p.name,
+                    version: publication?.version ?: (version == 'unspecified' ? '' : version),
+                    packaging: p.plugins.hasPlugin('war') ? 'war' : (p.plugins.hasPlugin('java') ? 'jar' : 'pom'),
+                    artifacts: archives,
+                ]
+            }
+            new File(rootProject.projectDir, '` + modelFileName + `').text = groovy.json.JsonOutput.toJson([projects: projects])
+        }
+    }
+}
+`
+
+// ModelUtils provides the functionality required for extracting the project model
+type ModelUtils interface {
+	Utils
+	FileRead(path string) ([]byte, error)
+}
+
+// Model describes the projects of a Gradle build
+type Model struct {
+	Projects []Project `json:"projects"`
+}
+
+// Project describes a project of a Gradle build, i.e. the root project or a subproject
+type Project struct {
+	// Path is the Gradle path of the project, e.g. ":" for the root project or ":lib" for a subproject
+	Path string `json:"path"`
+	Name string `json:"name"`
+	// Dir is the directory of the project
+	Dir        string `json:"dir"`
+	GroupID    string `json:"groupId"`
+	ArtifactID string `json:"artifactId"`
+	Version    string `json:"version"`
+	Packaging  string `json:"packaging"`
+	// Artifacts are the archives created by the jar tasks of the project
+	Artifacts []Artifact `json:"artifacts,omitempty"`
+}
+
+// Artifact describes an archive created by the build
+type Artifact struct {
+	File       string `json:"file"`
+	Classifier string `json:"classifier,omitempty"`
+	Extension  string `json:"extension"`
+}
+
+// GetProjectModel runs the Gradle build located in buildGradlePath with an init script in order to retrieve the coordinates
+// and archives of all its projects. The file paths of the returned model are relative to the current working directory.
+func GetProjectModel(buildGradlePath string, useWrapper bool, utils ModelUtils) (*Model, error) {
+	options := &ExecuteOptions{
+		BuildGradlePath:   buildGradlePath,
+		Task:              modelTaskName,
+		UseWrapper:        useWrapper,
+		InitScriptContent: modelInitScriptContent,
+	}
+	if _, err := Execute(options, utils); err != nil {
+		return nil, fmt.Errorf("failed to retrieve the project model of the gradle build: %w", err)
+	}
+	modelFile := filepath.Join(buildGradlePath, modelFileName)
+	defer utils.FileRemove(modelFile)
+	content, err := utils.FileRead(modelFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the project model of the gradle build: %w", err)
+	}
+	model := &Model{}
+	if err := json.Unmarshal(content, model); err != nil {
+		return nil, fmt.Errorf("failed to parse the project model of the gradle build: %w", err)
+	}
+	for i := range model.Projects {
+		project := &model.Projects[i]
+		project.Dir = filepath.Join(buildGradlePath, filepath.FromSlash(project.Dir))
+		for j := range project.Artifacts {
+			project.Artifacts[j].File = filepath.Join(buildGradlePath, filepath.FromSlash(project.Artifacts[j].File))
+		}
+	}
+	return model, nil
+}
+
+// RootProject returns the root project of the build
+func (m *Model) RootProject() (Project, error) {
+	for _, project := range m.Projects {
+		if project.Path == ":" {
+			return project, nil
+		}
+	}
+	return Project{}, fmt.Errorf("the project model of the gradle build does not contain a root project")
+}
+
+// MainArtifact returns the archive without classifier, it returns false in case the project does not create such an archive
+func (p Project) MainArtifact() (Artifact, bool) {
+	for _, artifact := range p.Artifacts {
+		if len(artifact.Classifier) == 0 {
+			return artifact, true
+		}
+	}
+	return Artifact{}, false
+}
+
+// PublishedPath returns the path of the directory in a Maven repository the project is published to.
+// The POM file of a release version is located in this directory, snapshot versions contain a maven-metadata.xml instead.
+func (p Project) PublishedPath() string {
+	return path.Join(strings.ReplaceAll(p.GroupID, ".", "/"), p.ArtifactID, p.Version)
+}
+
+// PublishedDescriptor returns the path of the file proving the publication of the project in a Maven repository
+func (p Project) PublishedDescriptor() string {
+	if p.IsSnapshot() {
+		return path.Join(p.PublishedPath(), "maven-metadata.xml")
+	}
+	return path.Join(p.PublishedPath(), fmt.Sprintf("%v-%v.pom", p.ArtifactID, p.Version))
+}
+
+// PublishedArtifact returns the path of an archive of the project in a Maven repository. The archives of snapshot versions
+// are published with a timestamp instead, they are listed in the maven-metadata.xml of the version.
+func (p Project) PublishedArtifact(artifact Artifact) string {
+	name := fmt.Sprintf("%v-%v", p.ArtifactID, p.Version)
+	if len(artifact.Classifier) > 0 {
+		name += "-" + artifact.Classifier
+	}
+	return path.Join(p.PublishedPath(), name+"."+artifact.Extension)
+}
+
+// IsSnapshot returns true if the project has a snapshot version
+func (p Project) IsSnapshot() bool {
+	return strings.HasSuffix(p.Version, "-SNAPSHOT")
+}
+
+// SnapshotContainsArtifact checks whether the maven-metadata.xml of a snapshot version lists the archive
+func SnapshotContainsArtifact(metadata []byte, artifact Artifact) (bool, error) {
+	snapshot := struct {
+		Versions []struct {
+			Classifier string `xml:"classifier"`
+			Extension  string `xml:"extension"`
+		} `xml:"versioning>snapshotVersions>snapshotVersion"`
+	}{}
+	if err := xml.Unmarshal(metadata, &snapshot); err != nil {
+		return false, fmt.Errorf("failed to parse the metadata of the snapshot version: %w", err)
+	}
+	for _, version := range snapshot.Versions {
+		if version.Classifier == artifact.Classifier && version.Extension == artifact.Extension {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,109 @@
+package gradle
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+const modelFileContent = `{"projects": [
+	{"path": ":", "name": "root", "dir": ".", "groupId": "com.example", "artifactId": "root", "version": "1.2.3", "packaging": "jar",
+		"artifacts": [{"file": "build/libs/root-1.2.3.jar", "extension": "jar"}]},
+	{"path": ":lib", "name": "lib", "dir": "lib", "groupId": "com.example", "artifactId": "lib", "version": "1.2.3", "packaging": "jar",
+		"artifacts": [{"file": "lib/build/libs/lib-1.2.3-sources.jar", "classifier": "sources", "extension": "jar"}, {"file": "lib/build/libs/lib-1.2.3.jar", "extension": "jar"}]}
+]}`
+
+type modelMockUtils struct {
+	*mock.FilesMock
+	*mock.ExecMockRunner
+}
+
+func TestGetProjectModel(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		utils := modelMockUtils{FilesMock: &mock.FilesMock{}, ExecMockRunner: &mock.ExecMockRunner{}}
+		utils.AddFile("path/to/build.gradle", []byte{})
+		utils.AddFile("path/to/piperProjectModel.json.tmp", []byte(modelFileContent))
+
+		model, err := GetProjectModel("path/to", false, utils)
+
+		assert.NoError(t, err)
+		if assert.Len(t, utils.Calls, 2) {
+			assert.Equal(t, mock.ExecCall{Exec: "gradle", Params: []string{"piperProjectModel", "-p", "path/to", "--init-script", "initScript.gradle.tmp"}}, utils.Calls[1])
+		}
+		assert.False(t, utils.HasFile("path/to/piperProjectModel.json.tmp"))
+		assert.False(t, utils.HasFile("initScript.gradle.tmp"))
+		if assert.Len(t, model.Projects, 2) {
+			assert.Equal(t, "path/to/lib", model.Projects[1].Dir)
+			artifact, found := model.Projects[1].MainArtifact()
+			assert.True(t, found)
+			assert.Equal(t, Artifact{File: "path/to/lib/build/libs/lib-1.2.3.jar", Extension: "jar"}, artifact)
+		}
+		root, err := model.RootProject()
+		assert.NoError(t, err)
+		assert.Equal(t, "root", root.ArtifactID)
+	})
+
+	t.Run("failed build", func(t *testing.T) {
+		utils := modelMockUtils{FilesMock: &mock.FilesMock{}, ExecMockRunner: &mock.ExecMockRunner{
+			ShouldFailOnCommand: map[string]error{"gradle piperProjectModel": errors.New("build failed")},
+		}}
+		utils.AddFile("build.gradle", []byte{})
+
+		_, err := GetProjectModel("", false, utils)
+
+		assert.EqualError(t, err, "failed to retrieve the project model of the gradle build: failed to run executable, command: '[gradle piperProjectModel --init-script initScript.gradle.tmp]', error: build failed")
+	})
+
+	t.Run("missing root project", func(t *testing.T) {
+		model := &Model{Projects: []Project{{Path: ":lib"}}}
+
+		_, err := model.RootProject()
+
+		assert.EqualError(t, err, "the project model of the gradle build does not contain a root project")
+	})
+}
+
+func TestPublishedDescriptor(t *testing.T) {
+	release := Project{GroupID: "com.example.tools", ArtifactID: "lib", Version: "1.2.3"}
+	assert.Equal(t, "com/example/tools/lib/1.2.3/lib-1.2.3.pom", release.PublishedDescriptor())
+
+	snapshot := Project{GroupID: "com.example", ArtifactID: "lib", Version: "1.3.0-SNAPSHOT"}
+	assert.Equal(t, "com/example/lib/1.3.0-SNAPSHOT/maven-metadata.xml", snapshot.PublishedDescriptor())
+}
+
+func TestPublishedArtifact(t *testing.T) {
+	project := Project{GroupID: "com.example.tools", ArtifactID: "lib", Version: "1.2.3"}
+	assert.Equal(t, "com/example/tools/lib/1.2.3/lib-1.2.3.jar", project.PublishedArtifact(Artifact{Extension: "jar"}))
+	assert.Equal(t, "com/example/tools/lib/1.2.3/lib-1.2.3-sources.jar", project.PublishedArtifact(Artifact{Classifier: "sources", Extension: "jar"}))
+}
+
+func TestSnapshotContainsArtifact(t *testing.T) {
+	metadata := []byte(`<metadata>
+  <groupId>com.example</groupId>
+  <artifactId>lib</artifactId>
+  <version>1.3.0-SNAPSHOT</version>
+  <versioning>
+    <snapshot><timestamp>20240102.030405</timestamp><buildNumber>1</buildNumber></snapshot>
+    <snapshotVersions>
+      <snapshotVersion><extension>jar</extension><value>1.3.0-20240102.030405-1</value></snapshotVersion>
+      <snapshotVersion><classifier>sources</classifier><extension>jar</extension><value>1.3.0-20240102.030405-1</value></snapshotVersion>
+      <snapshotVersion><extension>pom</extension><value>1.3.0-20240102.030405-1</value></snapshotVersion>
+    </snapshotVersions>
+  </versioning>
+</metadata>`)
+
+	listed, err := SnapshotContainsArtifact(metadata, Artifact{Extension: "jar"})
+	assert.NoError(t, err)
+	assert.True(t, listed)
+	listed, err = SnapshotContainsArtifact(metadata, Artifact{Classifier: "sources", Extension: "jar"})
+	assert.NoError(t, err)
+	assert.True(t, listed)
+	listed, err = SnapshotContainsArtifact(metadata, Artifact{Classifier: "javadoc", Extension: "jar"})
+	assert.NoError(t, err)
+	assert.False(t, listed)
+
+	_, err = SnapshotContainsArtifact([]byte("not xml"), Artifact{Extension: "jar"})
+	assert.Error(t, err)
+}
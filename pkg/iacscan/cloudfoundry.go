@@ -0,0 +1,108 @@
+package iacscan
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/cloudfoundry"
+	"github.com/pkg/errors"
+)
+
+var credentialName = regexp.MustCompile(`(?i)(password|passwd|secret|token|api_?key|credentials?)`)
+
+func readCloudFoundryResources(file string, content []byte) ([]Resource, error) {
+	manifest, err := cloudfoundry.ParseManifest(file, content)
+	if err != nil {
+		return nil, err
+	}
+	return ResourcesFromManifest(manifest)
+}
+
+// ResourcesFromManifest provides the applications of a Cloud Foundry manifest as resources
+func ResourcesFromManifest(manifest cloudfoundry.Manifest) ([]Resource, error) {
+	apps, err := manifest.GetApplications()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read applications of manifest '%v'", manifest.GetFileName())
+	}
+	resources := []Resource{}
+	for i, app := range apps {
+		name, err := manifest.GetAppName(i)
+		if err != nil {
+			name = fmt.Sprint(i)
+		}
+		resources = append(resources, Resource{
+			Type:    TypeCloudFoundry,
+			Kind:    "application",
+			Name:    name,
+			File:    manifest.GetFileName(),
+			Content: app,
+		})
+	}
+	return resources, nil
+}
+
+func cloudFoundryPolicies() []Policy {
+	return []Policy{
+		{
+			ID:       "CF001",
+			Title:    "Docker image without fixed tag",
+			Severity: SeverityMedium,
+			Type:     TypeCloudFoundry,
+			check: func(resource Resource) []violation {
+				if image, ok := lookupString(resource.Content, "docker", "image"); ok && isFloatingImage(image) {
+					return []violation{{message: fmt.Sprintf("docker image '%v' uses the latest tag or no tag", image)}}
+				}
+				return nil
+			},
+		},
+		{
+			ID:       "CF002",
+			Title:    "Credentials in environment variables",
+			Severity: SeverityHigh,
+			Type:     TypeCloudFoundry,
+			check: func(resource Resource) []violation {
+				env, _ := resource.Content["env"].(map[string]interface{})
+				names := []string{}
+				for name, value := range env {
+					literal, ok := value.(string)
+					// values provided as variables, e.g. ((password)), are not contained in the manifest
+					if ok && len(literal) > 0 && !strings.Contains(literal, "((") && credentialName.MatchString(name) {
+						names = append(names, name)
+					}
+				}
+				sort.Strings(names)
+				violations := []violation{}
+				for _, name := range names {
+					violations = append(violations, violation{message: fmt.Sprintf("environment variable '%v' contains a literal value", name)})
+				}
+				return violations
+			},
+		},
+		{
+			ID:       "CF003",
+			Title:    "Health check disabled",
+			Severity: SeverityLow,
+			Type:     TypeCloudFoundry,
+			check: func(resource Resource) []violation {
+				if healthCheck, _ := lookupString(resource.Content, "health-check-type"); healthCheck == "none" {
+					return []violation{{message: "health-check-type is 'none'"}}
+				}
+				return nil
+			},
+		},
+		{
+			ID:       "CF004",
+			Title:    "Missing memory limit",
+			Severity: SeverityLow,
+			Type:     TypeCloudFoundry,
+			check: func(resource Resource) []violation {
+				if _, ok := resource.Content["memory"]; !ok {
+					return []violation{{message: "memory is not set"}}
+				}
+				return nil
+			},
+		},
+	}
+}
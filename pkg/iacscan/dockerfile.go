@@ -0,0 +1,195 @@
+package iacscan
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Instruction is a Dockerfile instruction, continued lines are joined
+type Instruction struct {
+	Command   string
+	Arguments string
+	Line      int
+}
+
+var pipeToShell = regexp.MustCompile(`(curl|wget)\s[^|;&]*\|\s*(sudo\s+)?(ba|z)?sh\b`)
+
+// ParseDockerfile splits a Dockerfile into its instructions
+func ParseDockerfile(content string) []Instruction {
+	instructions := []Instruction{}
+	var current *Instruction
+	for i, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(strings.TrimRight(line, "\r"))
+		if strings.HasPrefix(line, "#") || (len(line) == 0 && current == nil) {
+			continue
+		}
+		continued := strings.HasSuffix(line, "\\")
+		line = strings.TrimSpace(strings.TrimSuffix(line, "\\"))
+		if current == nil {
+			fields := strings.SplitN(line, " ", 2)
+			current = &Instruction{Command: strings.ToUpper(fields[0]), Line: i + 1}
+			if len(fields) > 1 {
+				current.Arguments = strings.TrimSpace(fields[1])
+			}
+		} else if len(line) > 0 {
+			current.Arguments = strings.TrimSpace(current.Arguments + " " + line)
+		}
+		if !continued {
+			instructions = append(instructions, *current)
+			current = nil
+		}
+	}
+	if current != nil {
+		instructions = append(instructions, *current)
+	}
+	return instructions
+}
+
+// keyValues parses the arguments of ENV and ARG instructions, i.e. 'key=value key2=value2', 'key value' or 'key'
+func keyValues(arguments string) map[string]string {
+	values := map[string]string{}
+	fields := strings.Fields(arguments)
+	if len(fields) > 0 && !strings.Contains(fields[0], "=") {
+		values[fields[0]] = strings.Join(fields[1:], " ")
+		return values
+	}
+	for _, field := range fields {
+		parts := strings.SplitN(field, "=", 2)
+		if len(parts) == 2 {
+			values[parts[0]] = strings.Trim(parts[1], `"'`)
+		} else {
+			values[parts[0]] = ""
+		}
+	}
+	return values
+}
+
+func dockerfilePolicies() []Policy {
+	return []Policy{
+		{
+			ID:       "DF001",
+			Title:    "Base image without fixed tag",
+			Severity: SeverityMedium,
+			Type:     TypeDockerfile,
+			check: func(resource Resource) []violation {
+				violations := []violation{}
+				stages := map[string]bool{}
+				for _, instruction := range resource.Instructions {
+					if instruction.Command != "FROM" {
+						continue
+					}
+					fields := []string{}
+					for _, field := range strings.Fields(instruction.Arguments) {
+						if !strings.HasPrefix(field, "--") {
+							fields = append(fields, field)
+						}
+					}
+					if len(fields) == 0 {
+						continue
+					}
+					image := fields[0]
+					if len(fields) == 3 && strings.EqualFold(fields[1], "AS") {
+						stages[strings.ToLower(fields[2])] = true
+					}
+					// images defined via build arguments can't be checked
+					if image == "scratch" || strings.Contains(image, "$") || stages[strings.ToLower(image)] {
+						continue
+					}
+					if isFloatingImage(image) {
+						violations = append(violations, violation{message: fmt.Sprintf("base image '%v' uses the latest tag or no tag", image), line: instruction.Line})
+					}
+				}
+				return violations
+			},
+		},
+		{
+			ID:       "DF002",
+			Title:    "Container running as root",
+			Severity: SeverityMedium,
+			Type:     TypeDockerfile,
+			check: func(resource Resource) []violation {
+				// only the user of the final stage is relevant for the resulting image
+				var user *Instruction
+				line := 0
+				for i, instruction := range resource.Instructions {
+					switch instruction.Command {
+					case "FROM":
+						user = nil
+						line = instruction.Line
+					case "USER":
+						user = &resource.Instructions[i]
+					}
+				}
+				if line == 0 {
+					return nil
+				}
+				if user == nil {
+					return []violation{{message: "no USER defined, the container runs as root", line: line}}
+				}
+				name := strings.SplitN(user.Arguments, ":", 2)[0]
+				if name == "root" || name == "0" {
+					return []violation{{message: fmt.Sprintf("USER is '%v'", user.Arguments), line: user.Line}}
+				}
+				return nil
+			},
+		},
+		{
+			ID:       "DF003",
+			Title:    "ADD used instead of COPY",
+			Severity: SeverityLow,
+			Type:     TypeDockerfile,
+			check: func(resource Resource) []violation {
+				violations := []violation{}
+				for _, instruction := range resource.Instructions {
+					if instruction.Command == "ADD" {
+						violations = append(violations, violation{message: "ADD may download remote files or extract archives unnoticed, use COPY instead", line: instruction.Line})
+					}
+				}
+				return violations
+			},
+		},
+		{
+			ID:       "DF004",
+			Title:    "Credentials in ENV or ARG",
+			Severity: SeverityHigh,
+			Type:     TypeDockerfile,
+			check: func(resource Resource) []violation {
+				violations := []violation{}
+				for _, instruction := range resource.Instructions {
+					if instruction.Command != "ENV" && instruction.Command != "ARG" {
+						continue
+					}
+					values := keyValues(instruction.Arguments)
+					names := []string{}
+					for name := range values {
+						names = append(names, name)
+					}
+					sort.Strings(names)
+					for _, name := range names {
+						if value := values[name]; len(value) > 0 && !strings.HasPrefix(value, "$") && credentialName.MatchString(name) {
+							violations = append(violations, violation{message: fmt.Sprintf("%v '%v' contains a literal value which is part of the image", instruction.Command, name), line: instruction.Line})
+						}
+					}
+				}
+				return violations
+			},
+		},
+		{
+			ID:       "DF005",
+			Title:    "Remote script piped to shell",
+			Severity: SeverityMedium,
+			Type:     TypeDockerfile,
+			check: func(resource Resource) []violation {
+				violations := []violation{}
+				for _, instruction := range resource.Instructions {
+					if instruction.Command == "RUN" && pipeToShell.MatchString(instruction.Arguments) {
+						violations = append(violations, violation{message: "downloaded script is executed without verification", line: instruction.Line})
+					}
+				}
+				return violations
+			},
+		},
+	}
+}
@@ -0,0 +1,29 @@
+package iacscan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDockerfile(t *testing.T) {
+	t.Parallel()
+
+	instructions := ParseDockerfile("# syntax=docker/dockerfile:1\nFROM alpine:3.17\n\nrun apk add \\\n    curl \\\n    git\nUSER app\n")
+
+	assert.Equal(t, []Instruction{
+		{Command: "FROM", Arguments: "alpine:3.17", Line: 2},
+		{Command: "RUN", Arguments: "apk add curl git", Line: 4},
+		{Command: "USER", Arguments: "app", Line: 7},
+	}, instructions)
+}
+
+func TestIsFloatingImage(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, isFloatingImage("alpine"))
+	assert.True(t, isFloatingImage("registry.example.com:5000/app"))
+	assert.True(t, isFloatingImage("registry.example.com/app:latest"))
+	assert.False(t, isFloatingImage("registry.example.com:5000/app:1.0"))
+	assert.False(t, isFloatingImage("alpine@sha256:1234"))
+}
@@ -0,0 +1,203 @@
+package iacscan
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+)
+
+// File types supported by the scanner
+const (
+	TypeKubernetes   = "kubernetes"
+	TypeTerraform    = "terraform"
+	TypeCloudFoundry = "cloudfoundry"
+	TypeDockerfile   = "dockerfile"
+)
+
+// Severities of policies, ordered by increasing severity
+const (
+	SeverityLow    = "low"
+	SeverityMedium = "medium"
+	SeverityHigh   = "high"
+)
+
+// Resource is an entity checked by the policies, e.g. a Kubernetes object, a Terraform resource or a Cloud Foundry application
+type Resource struct {
+	// Type is the file type the resource has been read from
+	Type string
+	// Kind is the Kubernetes kind or the Terraform resource type
+	Kind string
+	Name string
+	File string
+	// Line is the line the resource starts at, 0 if unknown
+	Line    int
+	Content map[string]interface{}
+	// Instructions are only set for Dockerfiles
+	Instructions []Instruction
+}
+
+// Finding is a policy violation of a resource
+type Finding struct {
+	PolicyID string `json:"policyId"`
+	Title    string `json:"title"`
+	Severity string `json:"severity"`
+	File     string `json:"file"`
+	Line     int    `json:"line,omitempty"`
+	Resource string `json:"resource"`
+	Message  string `json:"message"`
+}
+
+// SeverityRank maps a severity to a number, unknown severities are ranked lowest
+func SeverityRank(severity string) int {
+	switch strings.ToLower(severity) {
+	case SeverityLow:
+		return 1
+	case SeverityMedium:
+		return 2
+	case SeverityHigh:
+		return 3
+	}
+	return 0
+}
+
+// IsSevere returns true if the finding has at least the given severity, no finding is severe if the threshold is empty or "none"
+func (f Finding) IsSevere(threshold string) bool {
+	rank := SeverityRank(threshold)
+	return rank > 0 && SeverityRank(f.Severity) >= rank
+}
+
+// CountSevere returns the number of findings having at least the given severity
+func CountSevere(findings []Finding, threshold string) int {
+	count := 0
+	for _, finding := range findings {
+		if finding.IsSevere(threshold) {
+			count++
+		}
+	}
+	return count
+}
+
+// DetectType determines the type of a file based on its name and content, an empty string is returned for unsupported files
+func DetectType(file string, content []byte) string {
+	base := strings.ToLower(filepath.Base(file))
+	if base == "dockerfile" || strings.HasPrefix(base, "dockerfile.") || strings.HasSuffix(base, ".dockerfile") {
+		return TypeDockerfile
+	}
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".json":
+		document := map[string]interface{}{}
+		if err := json.Unmarshal(content, &document); err != nil {
+			return ""
+		}
+		if _, ok := document["planned_values"]; ok {
+			return TypeTerraform
+		}
+		if _, ok := document["resource_changes"]; ok {
+			return TypeTerraform
+		}
+		if isKubernetesObject(document) {
+			return TypeKubernetes
+		}
+	case ".yml", ".yaml":
+		for _, document := range splitYAML(content) {
+			content := map[string]interface{}{}
+			if err := yaml.Unmarshal([]byte(document.content), &content); err != nil {
+				continue
+			}
+			if _, ok := content["applications"]; ok {
+				return TypeCloudFoundry
+			}
+			if isKubernetesObject(content) {
+				return TypeKubernetes
+			}
+		}
+	}
+	return ""
+}
+
+// ReadResources parses a file of the given type into resources
+func ReadResources(fileType, file string, content []byte) ([]Resource, error) {
+	switch fileType {
+	case TypeKubernetes:
+		return readKubernetesResources(file, content)
+	case TypeTerraform:
+		return readTerraformResources(file, content)
+	case TypeCloudFoundry:
+		return readCloudFoundryResources(file, content)
+	case TypeDockerfile:
+		return []Resource{{Type: TypeDockerfile, Kind: "Dockerfile", Name: file, File: file, Instructions: ParseDockerfile(string(content))}}, nil
+	}
+	return nil, fmt.Errorf("unsupported file type '%v'", fileType)
+}
+
+// Scan checks the resources against the policies
+func Scan(resources []Resource, policies []Policy) []Finding {
+	findings := []Finding{}
+	for _, resource := range resources {
+		for _, policy := range policies {
+			if !policy.appliesTo(resource) {
+				continue
+			}
+			for _, violation := range policy.evaluate(resource) {
+				line := violation.line
+				if line == 0 {
+					line = resource.Line
+				}
+				findings = append(findings, Finding{
+					PolicyID: policy.ID,
+					Title:    policy.Title,
+					Severity: policy.Severity,
+					File:     resource.File,
+					Line:     line,
+					Resource: resourceName(resource),
+					Message:  violation.message,
+				})
+			}
+		}
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		return SeverityRank(findings[i].Severity) > SeverityRank(findings[j].Severity)
+	})
+	return findings
+}
+
+func resourceName(resource Resource) string {
+	if resource.Type == TypeDockerfile {
+		return resource.Name
+	}
+	return fmt.Sprintf("%v/%v", resource.Kind, resource.Name)
+}
+
+type yamlDocument struct {
+	content string
+	line    int
+}
+
+var documentSeparator = regexp.MustCompile(`^---(\s.*)?$`)
+
+// splitYAML splits a multi-document YAML file, e.g. a rendered Helm chart, and keeps the start line of each document
+func splitYAML(content []byte) []yamlDocument {
+	documents := []yamlDocument{}
+	current := []string{}
+	start := 1
+	for i, line := range strings.Split(string(content), "\n") {
+		if documentSeparator.MatchString(strings.TrimRight(line, "\r")) {
+			if len(strings.TrimSpace(strings.Join(current, ""))) > 0 {
+				documents = append(documents, yamlDocument{content: strings.Join(current, "\n"), line: start})
+			}
+			current = []string{}
+			start = i + 2
+			continue
+		}
+		current = append(current, line)
+	}
+	if len(strings.TrimSpace(strings.Join(current, ""))) > 0 {
+		documents = append(documents, yamlDocument{content: strings.Join(current, "\n"), line: start})
+	}
+	return documents
+}
@@ -0,0 +1,129 @@
+package iacscan
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func scanTestFile(t *testing.T, name string) []Finding {
+	file := filepath.Join("testdata", name)
+	content, err := os.ReadFile(file)
+	assert.NoError(t, err)
+	resources, err := ReadResources(DetectType(file, content), file, content)
+	assert.NoError(t, err)
+	return Scan(resources, BuiltInPolicies())
+}
+
+func policyIDs(findings []Finding) []string {
+	ids := []string{}
+	for _, finding := range findings {
+		ids = append(ids, finding.PolicyID)
+	}
+	return ids
+}
+
+func TestDetectType(t *testing.T) {
+	t.Parallel()
+
+	tt := []struct {
+		file     string
+		content  string
+		expected string
+	}{
+		{file: "Dockerfile", expected: TypeDockerfile},
+		{file: "build/Dockerfile.alpine", expected: TypeDockerfile},
+		{file: "app.dockerfile", expected: TypeDockerfile},
+		{file: "tfplan.json", content: `{"format_version": "1.1", "planned_values": {}}`, expected: TypeTerraform},
+		{file: "pod.json", content: `{"apiVersion": "v1", "kind": "Pod"}`, expected: TypeKubernetes},
+		{file: "package.json", content: `{"name": "app"}`},
+		{file: "manifest.yml", content: "applications:\n- name: app\n", expected: TypeCloudFoundry},
+		{file: "chart.yaml", content: "# comment\n---\napiVersion: v1\nkind: Service\n", expected: TypeKubernetes},
+		{file: "mta.yaml", content: "ID: app\nmodules: []\n"},
+		{file: "main.go", content: "package main"},
+	}
+
+	for _, test := range tt {
+		assert.Equal(t, test.expected, DetectType(test.file, []byte(test.content)), test.file)
+	}
+}
+
+func TestScan(t *testing.T) {
+	t.Parallel()
+
+	t.Run("rendered Helm chart", func(t *testing.T) {
+		t.Parallel()
+		findings := scanTestFile(t, "rendered-chart.yaml")
+
+		assert.ElementsMatch(t, []string{"K8S001", "K8S005", "K8S002", "K8S003", "K8S004", "K8S006", "K8S007"}, policyIDs(findings))
+		for _, finding := range findings {
+			assert.Equal(t, "Deployment/app", finding.Resource)
+			assert.Equal(t, 11, finding.Line)
+		}
+		// findings are sorted by severity
+		assert.Equal(t, SeverityHigh, findings[0].Severity)
+		assert.Equal(t, SeverityLow, findings[len(findings)-1].Severity)
+	})
+
+	t.Run("Terraform plan", func(t *testing.T) {
+		t.Parallel()
+		findings := scanTestFile(t, "tfplan.json")
+
+		if assert.Len(t, findings, 2) {
+			assert.Equal(t, Finding{PolicyID: "TF001", Title: "Public S3 bucket", Severity: SeverityHigh, File: filepath.Join("testdata", "tfplan.json"), Resource: "aws_s3_bucket/aws_s3_bucket.assets", Message: "acl is 'public-read'"}, findings[0])
+			assert.Equal(t, "TF002", findings[1].PolicyID)
+			assert.Equal(t, "ports 5432-5432 are open to 0.0.0.0/0", findings[1].Message)
+		}
+	})
+
+	t.Run("Cloud Foundry manifest", func(t *testing.T) {
+		t.Parallel()
+		findings := scanTestFile(t, "manifest.yml")
+
+		assert.Equal(t, []string{"CF002", "CF001", "CF003", "CF004"}, policyIDs(findings))
+		assert.Equal(t, "environment variable 'DB_PASSWORD' contains a literal value", findings[0].Message)
+		assert.Equal(t, "application/worker", findings[2].Resource)
+	})
+
+	t.Run("Dockerfile", func(t *testing.T) {
+		t.Parallel()
+		findings := scanTestFile(t, "Dockerfile")
+
+		lines := map[string]int{}
+		for _, finding := range findings {
+			lines[finding.PolicyID] = finding.Line
+		}
+		assert.Equal(t, map[string]int{"DF001": 6, "DF002": 12, "DF003": 9, "DF004": 7, "DF005": 10}, lines)
+	})
+}
+
+func TestIsSevere(t *testing.T) {
+	t.Parallel()
+
+	findings := []Finding{{Severity: SeverityHigh}, {Severity: SeverityMedium}, {Severity: SeverityLow}}
+
+	assert.Equal(t, 1, CountSevere(findings, "high"))
+	assert.Equal(t, 2, CountSevere(findings, "medium"))
+	assert.Equal(t, 3, CountSevere(findings, "low"))
+	assert.Equal(t, 0, CountSevere(findings, "none"))
+	assert.Equal(t, 0, CountSevere(findings, ""))
+}
+
+func TestReadRenderedChart(t *testing.T) {
+	t.Parallel()
+
+	content, err := os.ReadFile(filepath.Join("testdata", "rendered-chart.yaml"))
+	assert.NoError(t, err)
+
+	resources, err := ReadRenderedChart("charts/app", content)
+
+	assert.NoError(t, err)
+	if assert.Len(t, resources, 2) {
+		assert.Equal(t, "charts/app/templates/service.yaml", resources[0].File)
+		assert.Equal(t, "Deployment", resources[1].Kind)
+		assert.Equal(t, "charts/app/templates/deployment.yaml", resources[1].File)
+		assert.Equal(t, 0, resources[1].Line)
+	}
+}
@@ -0,0 +1,326 @@
+package iacscan
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+)
+
+var workloadKinds = []string{"Pod", "Deployment", "StatefulSet", "DaemonSet", "ReplicaSet", "ReplicationController", "Job", "CronJob"}
+
+func isKubernetesObject(document map[string]interface{}) bool {
+	_, hasAPIVersion := document["apiVersion"]
+	_, hasKind := document["kind"]
+	return hasAPIVersion && hasKind
+}
+
+func readKubernetesResources(file string, content []byte) ([]Resource, error) {
+	documents := []yamlDocument{}
+	if strings.HasSuffix(strings.ToLower(file), ".json") {
+		documents = append(documents, yamlDocument{content: string(content), line: 1})
+	} else {
+		documents = splitYAML(content)
+	}
+
+	resources := []Resource{}
+	for _, document := range documents {
+		object := map[string]interface{}{}
+		var err error
+		if strings.HasSuffix(strings.ToLower(file), ".json") {
+			err = json.Unmarshal([]byte(document.content), &object)
+		} else {
+			err = yaml.Unmarshal([]byte(document.content), &object)
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse Kubernetes manifest '%v'", file)
+		}
+		objects := []map[string]interface{}{object}
+		// lists like the output of kubectl get -o yaml contain the objects as items
+		if object["kind"] == "List" {
+			objects = asMaps(object["items"])
+		}
+		for _, object := range objects {
+			if !isKubernetesObject(object) {
+				continue
+			}
+			name, _ := lookupString(object, "metadata", "name")
+			resources = append(resources, Resource{
+				Type:    TypeKubernetes,
+				Kind:    fmt.Sprint(object["kind"]),
+				Name:    name,
+				File:    file,
+				Line:    document.line,
+				Content: object,
+			})
+		}
+	}
+	return resources, nil
+}
+
+var helmSource = regexp.MustCompile(`(?m)^# Source: (.+)$`)
+
+// ReadRenderedChart reads the Kubernetes resources of a chart rendered via 'helm template'.
+// The resources refer to the template they have been rendered from.
+func ReadRenderedChart(chartPath string, content []byte) ([]Resource, error) {
+	resources, err := readKubernetesResources(chartPath, content)
+	if err != nil {
+		return nil, err
+	}
+	documents := splitYAML(content)
+	for i := range resources {
+		for _, document := range documents {
+			if document.line != resources[i].Line {
+				continue
+			}
+			// the source is prefixed with the chart name, which is the directory name of the chart
+			if match := helmSource.FindStringSubmatch(document.content); match != nil {
+				resources[i].File = filepath.ToSlash(filepath.Join(filepath.Dir(chartPath), strings.TrimSpace(match[1])))
+				resources[i].Line = 0
+			}
+		}
+	}
+	return resources, nil
+}
+
+// podSpec returns the pod specification of a workload
+func podSpec(resource Resource) map[string]interface{} {
+	var path []string
+	switch resource.Kind {
+	case "Pod":
+		path = []string{"spec"}
+	case "CronJob":
+		path = []string{"spec", "jobTemplate", "spec", "template", "spec"}
+	default:
+		path = []string{"spec", "template", "spec"}
+	}
+	spec, _ := lookupValue(resource.Content, path...).(map[string]interface{})
+	return spec
+}
+
+// containers returns the containers and init containers of a workload
+func containers(resource Resource) []map[string]interface{} {
+	spec := podSpec(resource)
+	if spec == nil {
+		return nil
+	}
+	return append(asMaps(spec["initContainers"]), asMaps(spec["containers"])...)
+}
+
+// forEachContainer creates a check applying the container check to all containers of a workload
+func forEachContainer(check func(spec, container map[string]interface{}) string) func(resource Resource) []violation {
+	return func(resource Resource) []violation {
+		violations := []violation{}
+		spec := podSpec(resource)
+		for _, container := range containers(resource) {
+			if message := check(spec, container); len(message) > 0 {
+				name, _ := container["name"].(string)
+				violations = append(violations, violation{message: fmt.Sprintf("container '%v': %v", name, message)})
+			}
+		}
+		return violations
+	}
+}
+
+func kubernetesPolicies() []Policy {
+	return []Policy{
+		{
+			ID:       "K8S001",
+			Title:    "Privileged container",
+			Severity: SeverityHigh,
+			Type:     TypeKubernetes,
+			Kinds:    workloadKinds,
+			check: forEachContainer(func(spec, container map[string]interface{}) string {
+				if lookupValue(container, "securityContext", "privileged") == true {
+					return "securityContext.privileged is true"
+				}
+				return ""
+			}),
+		},
+		{
+			ID:       "K8S002",
+			Title:    "Privilege escalation allowed",
+			Severity: SeverityMedium,
+			Type:     TypeKubernetes,
+			Kinds:    workloadKinds,
+			check: forEachContainer(func(spec, container map[string]interface{}) string {
+				if lookupValue(container, "securityContext", "allowPrivilegeEscalation") != false {
+					return "securityContext.allowPrivilegeEscalation is not set to false"
+				}
+				return ""
+			}),
+		},
+		{
+			ID:       "K8S003",
+			Title:    "Missing resource limits",
+			Severity: SeverityMedium,
+			Type:     TypeKubernetes,
+			Kinds:    workloadKinds,
+			check: forEachContainer(func(spec, container map[string]interface{}) string {
+				missing := []string{}
+				for _, resource := range []string{"cpu", "memory"} {
+					if lookupValue(container, "resources", "limits", resource) == nil {
+						missing = append(missing, resource)
+					}
+				}
+				if len(missing) > 0 {
+					return fmt.Sprintf("no %v limit defined", strings.Join(missing, " and "))
+				}
+				return ""
+			}),
+		},
+		{
+			ID:       "K8S004",
+			Title:    "Image without fixed tag",
+			Severity: SeverityMedium,
+			Type:     TypeKubernetes,
+			Kinds:    workloadKinds,
+			check: forEachContainer(func(spec, container map[string]interface{}) string {
+				image, _ := container["image"].(string)
+				if isFloatingImage(image) {
+					return fmt.Sprintf("image '%v' uses the latest tag or no tag", image)
+				}
+				return ""
+			}),
+		},
+		{
+			ID:       "K8S005",
+			Title:    "Host namespaces shared",
+			Severity: SeverityHigh,
+			Type:     TypeKubernetes,
+			Kinds:    workloadKinds,
+			check: func(resource Resource) []violation {
+				violations := []violation{}
+				spec := podSpec(resource)
+				for _, field := range []string{"hostNetwork", "hostPID", "hostIPC"} {
+					if spec != nil && spec[field] == true {
+						violations = append(violations, violation{message: fmt.Sprintf("%v is true", field)})
+					}
+				}
+				return violations
+			},
+		},
+		{
+			ID:       "K8S006",
+			Title:    "Container running as root",
+			Severity: SeverityMedium,
+			Type:     TypeKubernetes,
+			Kinds:    workloadKinds,
+			check: forEachContainer(func(spec, container map[string]interface{}) string {
+				runAsNonRoot := lookupValue(container, "securityContext", "runAsNonRoot")
+				if runAsNonRoot == nil {
+					runAsNonRoot = lookupValue(spec, "securityContext", "runAsNonRoot")
+				}
+				runAsUser := lookupValue(container, "securityContext", "runAsUser")
+				if runAsUser == nil {
+					runAsUser = lookupValue(spec, "securityContext", "runAsUser")
+				}
+				if runAsUser != nil && fmt.Sprint(runAsUser) == "0" {
+					return "runAsUser is 0"
+				}
+				if runAsNonRoot != true && runAsUser == nil {
+					return "runAsNonRoot is not set to true"
+				}
+				return ""
+			}),
+		},
+		{
+			ID:       "K8S007",
+			Title:    "Writable root filesystem",
+			Severity: SeverityLow,
+			Type:     TypeKubernetes,
+			Kinds:    workloadKinds,
+			check: forEachContainer(func(spec, container map[string]interface{}) string {
+				if lookupValue(container, "securityContext", "readOnlyRootFilesystem") != true {
+					return "securityContext.readOnlyRootFilesystem is not set to true"
+				}
+				return ""
+			}),
+		},
+		{
+			ID:       "K8S008",
+			Title:    "Host path volume",
+			Severity: SeverityMedium,
+			Type:     TypeKubernetes,
+			Kinds:    workloadKinds,
+			check: func(resource Resource) []violation {
+				violations := []violation{}
+				spec := podSpec(resource)
+				if spec == nil {
+					return violations
+				}
+				for _, volume := range asMaps(spec["volumes"]) {
+					if path, ok := lookupString(volume, "hostPath", "path"); ok {
+						violations = append(violations, violation{message: fmt.Sprintf("volume '%v' mounts host path '%v'", volume["name"], path)})
+					}
+				}
+				return violations
+			},
+		},
+		{
+			ID:       "K8S009",
+			Title:    "Dangerous capabilities added",
+			Severity: SeverityHigh,
+			Type:     TypeKubernetes,
+			Kinds:    workloadKinds,
+			check: forEachContainer(func(spec, container map[string]interface{}) string {
+				added, _ := lookupValue(container, "securityContext", "capabilities", "add").([]interface{})
+				dangerous := []string{}
+				for _, capability := range added {
+					switch strings.TrimPrefix(strings.ToUpper(fmt.Sprint(capability)), "CAP_") {
+					case "ALL", "SYS_ADMIN", "NET_ADMIN", "SYS_PTRACE", "SYS_MODULE":
+						dangerous = append(dangerous, fmt.Sprint(capability))
+					}
+				}
+				if len(dangerous) > 0 {
+					return fmt.Sprintf("capabilities %v added", strings.Join(dangerous, ", "))
+				}
+				return ""
+			}),
+		},
+	}
+}
+
+// isFloatingImage returns true if the image reference has no tag or the latest tag and no digest
+func isFloatingImage(image string) bool {
+	if len(image) == 0 || strings.Contains(image, "@") {
+		return false
+	}
+	name := image[strings.LastIndex(image, "/")+1:]
+	if !strings.Contains(name, ":") {
+		return true
+	}
+	return strings.HasSuffix(name, ":latest")
+}
+
+func lookupValue(object map[string]interface{}, path ...string) interface{} {
+	var value interface{} = object
+	for _, key := range path {
+		current, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value = current[key]
+	}
+	return value
+}
+
+func lookupString(object map[string]interface{}, path ...string) (string, bool) {
+	value, ok := lookupValue(object, path...).(string)
+	return value, ok
+}
+
+func asMaps(value interface{}) []map[string]interface{} {
+	maps := []map[string]interface{}{}
+	list, _ := value.([]interface{})
+	for _, entry := range list {
+		if object, ok := entry.(map[string]interface{}); ok {
+			maps = append(maps, object)
+		}
+	}
+	return maps
+}
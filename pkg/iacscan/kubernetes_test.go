@@ -0,0 +1,53 @@
+package iacscan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKubernetesPolicies(t *testing.T) {
+	t.Parallel()
+
+	content := []byte(`apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: backup
+spec:
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          securityContext:
+            runAsUser: 0
+          volumes:
+            - name: data
+              hostPath:
+                path: /var/lib/data
+          containers:
+            - name: backup
+              image: registry.example.com/backup:1.0.0@sha256:1234
+              securityContext:
+                allowPrivilegeEscalation: false
+                readOnlyRootFilesystem: true
+                capabilities:
+                  add: [NET_BIND_SERVICE, SYS_ADMIN]
+              resources:
+                limits:
+                  cpu: 1
+                  memory: 1Gi
+`)
+	resources, err := ReadResources(TypeKubernetes, "cronjob.yaml", content)
+	assert.NoError(t, err)
+
+	messages := map[string]string{}
+	for _, finding := range Scan(resources, BuiltInPolicies()) {
+		messages[finding.PolicyID] = finding.Message
+	}
+
+	assert.Equal(t, map[string]string{
+		"K8S006": "container 'backup': runAsUser is 0",
+		"K8S008": "volume 'data' mounts host path '/var/lib/data'",
+		"K8S009": "container 'backup': capabilities SYS_ADMIN added",
+	}, messages)
+}
@@ -0,0 +1,207 @@
+package iacscan
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/ghodss/yaml"
+)
+
+// Conditions of custom policies, the policy is violated if the condition is met
+const (
+	ConditionMissing   = "missing"
+	ConditionPresent   = "present"
+	ConditionEquals    = "equals"
+	ConditionNotEquals = "notEquals"
+	ConditionMatches   = "matches"
+)
+
+// Policy describes a misconfiguration.
+// Built-in policies implement their check in Go, custom policies define the value to check via Path and Condition.
+type Policy struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Severity    string `json:"severity"`
+	// Type is the file type the policy applies to
+	Type string `json:"type"`
+	// Kinds restricts the policy to Kubernetes kinds or Terraform resource types, the policy applies to all resources if empty
+	Kinds []string `json:"kinds,omitempty"`
+	// Path is the dot separated path of the checked value, "[]" iterates over all entries of a list, e.g. spec.template.spec.containers[].image
+	Path      string      `json:"path,omitempty"`
+	Condition string      `json:"condition,omitempty"`
+	Value     interface{} `json:"value,omitempty"`
+
+	check func(resource Resource) []violation
+	regex *regexp.Regexp
+}
+
+// PolicySet is the content of a custom policies file
+type PolicySet struct {
+	Policies []Policy `json:"policies"`
+}
+
+type violation struct {
+	message string
+	line    int
+}
+
+// ReadPolicies parses a custom policies file in YAML or JSON format
+func ReadPolicies(content []byte) ([]Policy, error) {
+	policySet := PolicySet{}
+	if err := yaml.Unmarshal(content, &policySet); err != nil {
+		return nil, fmt.Errorf("invalid policies file: %w", err)
+	}
+	for i := range policySet.Policies {
+		if err := policySet.Policies[i].validate(); err != nil {
+			return nil, err
+		}
+	}
+	return policySet.Policies, nil
+}
+
+// BuiltInPolicies returns the policies shipped with the scanner
+func BuiltInPolicies() []Policy {
+	policies := kubernetesPolicies()
+	policies = append(policies, terraformPolicies()...)
+	policies = append(policies, cloudFoundryPolicies()...)
+	return append(policies, dockerfilePolicies()...)
+}
+
+// SelectPolicies returns the policies not contained in skip, custom policies replace built-in policies with the same id
+func SelectPolicies(builtIn, custom []Policy, skip []string) []Policy {
+	excluded := map[string]bool{}
+	for _, id := range skip {
+		excluded[id] = true
+	}
+	for _, policy := range custom {
+		excluded[policy.ID] = true
+	}
+	selected := []Policy{}
+	for _, policy := range builtIn {
+		if !excluded[policy.ID] {
+			selected = append(selected, policy)
+		}
+	}
+	for _, policy := range custom {
+		if !piperutils.ContainsString(skip, policy.ID) {
+			selected = append(selected, policy)
+		}
+	}
+	return selected
+}
+
+func (p *Policy) validate() error {
+	if len(p.ID) == 0 {
+		return fmt.Errorf("policy without id")
+	}
+	if SeverityRank(p.Severity) == 0 {
+		return fmt.Errorf("invalid severity '%v' of policy '%v', supported are %v, %v and %v", p.Severity, p.ID, SeverityLow, SeverityMedium, SeverityHigh)
+	}
+	switch p.Type {
+	case TypeKubernetes, TypeTerraform, TypeCloudFoundry:
+	default:
+		return fmt.Errorf("invalid type '%v' of policy '%v', supported are %v, %v and %v", p.Type, p.ID, TypeKubernetes, TypeTerraform, TypeCloudFoundry)
+	}
+	if len(p.Path) == 0 {
+		return fmt.Errorf("policy '%v' without path", p.ID)
+	}
+	switch p.Condition {
+	case ConditionMissing, ConditionPresent, ConditionEquals, ConditionNotEquals:
+	case ConditionMatches:
+		regex, err := regexp.Compile(fmt.Sprint(p.Value))
+		if err != nil {
+			return fmt.Errorf("invalid regex of policy '%v': %w", p.ID, err)
+		}
+		p.regex = regex
+	default:
+		return fmt.Errorf("invalid condition '%v' of policy '%v'", p.Condition, p.ID)
+	}
+	return nil
+}
+
+func (p *Policy) appliesTo(resource Resource) bool {
+	if p.Type != resource.Type {
+		return false
+	}
+	return len(p.Kinds) == 0 || piperutils.ContainsString(p.Kinds, resource.Kind)
+}
+
+func (p *Policy) evaluate(resource Resource) []violation {
+	if p.check != nil {
+		return p.check(resource)
+	}
+	values := lookup(resource.Content, strings.Split(p.Path, "."))
+	switch p.Condition {
+	case ConditionMissing:
+		if len(values) == 0 {
+			return []violation{{message: fmt.Sprintf("%v is not set", p.Path)}}
+		}
+		return nil
+	case ConditionPresent:
+		if len(values) > 0 {
+			return []violation{{message: fmt.Sprintf("%v is set", p.Path)}}
+		}
+		return nil
+	}
+	violations := []violation{}
+	for _, value := range values {
+		var violated bool
+		switch p.Condition {
+		case ConditionEquals:
+			violated = equals(value, p.Value)
+		case ConditionNotEquals:
+			violated = !equals(value, p.Value)
+		case ConditionMatches:
+			violated = p.regex.MatchString(fmt.Sprint(value))
+		}
+		if violated {
+			violations = append(violations, violation{message: fmt.Sprintf("%v is '%v'", p.Path, value)})
+		}
+	}
+	return violations
+}
+
+// lookup returns all values found for the path, "[]" iterates over the entries of a list
+func lookup(value interface{}, path []string) []interface{} {
+	if len(path) == 0 {
+		if value == nil {
+			return nil
+		}
+		return []interface{}{value}
+	}
+	key := path[0]
+	iterate := strings.HasSuffix(key, "[]")
+	key = strings.TrimSuffix(key, "[]")
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	child, ok := object[key]
+	if !ok {
+		return nil
+	}
+	if !iterate {
+		return lookup(child, path[1:])
+	}
+	list, ok := child.([]interface{})
+	if !ok {
+		return nil
+	}
+	values := []interface{}{}
+	for _, entry := range list {
+		values = append(values, lookup(entry, path[1:])...)
+	}
+	return values
+}
+
+// equals compares values independent of their type, e.g. the number 1 equals the string "1" from the policies file
+func equals(value, expected interface{}) bool {
+	if reflect.DeepEqual(value, expected) {
+		return true
+	}
+	return fmt.Sprint(value) == fmt.Sprint(expected)
+}
@@ -0,0 +1,102 @@
+package iacscan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadPolicies(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid policies", func(t *testing.T) {
+		t.Parallel()
+		policies, err := ReadPolicies([]byte(`policies:
+  - id: ORG001
+    title: Team label missing
+    severity: low
+    type: kubernetes
+    kinds: [Deployment]
+    path: metadata.labels.team
+    condition: missing
+`))
+		assert.NoError(t, err)
+		if assert.Len(t, policies, 1) {
+			assert.Equal(t, "ORG001", policies[0].ID)
+			assert.Equal(t, []string{"Deployment"}, policies[0].Kinds)
+		}
+	})
+
+	t.Run("invalid policies", func(t *testing.T) {
+		t.Parallel()
+		tt := []struct {
+			policy   string
+			expected string
+		}{
+			{policy: "severity: low", expected: "policy without id"},
+			{policy: "id: A\n    severity: critical", expected: "invalid severity 'critical' of policy 'A', supported are low, medium and high"},
+			{policy: "id: A\n    severity: low\n    type: dockerfile", expected: "invalid type 'dockerfile' of policy 'A', supported are kubernetes, terraform and cloudfoundry"},
+			{policy: "id: A\n    severity: low\n    type: terraform", expected: "policy 'A' without path"},
+			{policy: "id: A\n    severity: low\n    type: terraform\n    path: acl\n    condition: like", expected: "invalid condition 'like' of policy 'A'"},
+		}
+		for _, test := range tt {
+			_, err := ReadPolicies([]byte("policies:\n  - " + test.policy + "\n"))
+			assert.EqualError(t, err, test.expected)
+		}
+	})
+}
+
+func TestSelectPolicies(t *testing.T) {
+	t.Parallel()
+
+	builtIn := []Policy{{ID: "A"}, {ID: "B"}, {ID: "C"}}
+	custom := []Policy{{ID: "B", Title: "custom"}, {ID: "D"}}
+
+	selected := SelectPolicies(builtIn, custom, []string{"C", "D"})
+
+	assert.Equal(t, []Policy{{ID: "A"}, {ID: "B", Title: "custom"}}, selected)
+}
+
+func TestCustomPolicies(t *testing.T) {
+	t.Parallel()
+
+	resource := Resource{
+		Type: TypeKubernetes,
+		Kind: "Deployment",
+		Name: "app",
+		Content: map[string]interface{}{
+			"metadata": map[string]interface{}{"labels": map[string]interface{}{"app": "backend"}},
+			"spec": map[string]interface{}{
+				"replicas": float64(1),
+				"template": map[string]interface{}{"spec": map[string]interface{}{"containers": []interface{}{
+					map[string]interface{}{"image": "docker.io/library/nginx:1.23"},
+					map[string]interface{}{"image": "registry.example.com/app:1.0.0"},
+				}}},
+			},
+		},
+	}
+	tt := []struct {
+		name     string
+		policy   Policy
+		expected []string
+	}{
+		{name: "missing", policy: Policy{Path: "metadata.labels.team", Condition: ConditionMissing}, expected: []string{"metadata.labels.team is not set"}},
+		{name: "present", policy: Policy{Path: "metadata.labels.app", Condition: ConditionPresent}, expected: []string{"metadata.labels.app is set"}},
+		{name: "equals", policy: Policy{Path: "spec.replicas", Condition: ConditionEquals, Value: "1"}, expected: []string{"spec.replicas is '1'"}},
+		{name: "notEquals", policy: Policy{Path: "spec.replicas", Condition: ConditionNotEquals, Value: 1}, expected: []string{}},
+		{name: "matches", policy: Policy{Path: "spec.template.spec.containers[].image", Condition: ConditionMatches, Value: "^docker.io/"}, expected: []string{"spec.template.spec.containers[].image is 'docker.io/library/nginx:1.23'"}},
+		{name: "other kind", policy: Policy{Kinds: []string{"StatefulSet"}, Path: "metadata.labels.team", Condition: ConditionMissing}, expected: []string{}},
+	}
+
+	for _, test := range tt {
+		policy := test.policy
+		policy.ID, policy.Severity, policy.Type = "ORG001", SeverityLow, TypeKubernetes
+		assert.NoError(t, policy.validate(), test.name)
+
+		messages := []string{}
+		for _, finding := range Scan([]Resource{resource}, []Policy{policy}) {
+			messages = append(messages, finding.Message)
+		}
+		assert.Equal(t, test.expected, messages, test.name)
+	}
+}
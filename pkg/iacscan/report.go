@@ -0,0 +1,152 @@
+package iacscan
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/reporting"
+)
+
+// CreateScanReport creates an IaC scan ScanReport to be used for uploading into various sinks
+func CreateScanReport(files []string, findings []Finding, threshold string) reporting.ScanReport {
+	severe := CountSevere(findings, threshold)
+	counts := map[string]int{}
+	for _, finding := range findings {
+		counts[finding.Severity]++
+	}
+
+	scanReport := reporting.ScanReport{
+		ReportTitle: "IaC Misconfiguration Report",
+		Subheaders: []reporting.Subheader{
+			{Description: "Scanned files", Details: strings.Join(files, ", ")},
+		},
+		Overview: []reporting.OverviewRow{
+			{Description: "Total number of findings", Details: fmt.Sprint(len(findings))},
+			{Description: "High severity findings", Details: fmt.Sprint(counts[SeverityHigh])},
+			{Description: "Medium severity findings", Details: fmt.Sprint(counts[SeverityMedium])},
+			{Description: "Low severity findings", Details: fmt.Sprint(counts[SeverityLow])},
+		},
+		SuccessfulScan: severe == 0,
+		ReportTime:     time.Now(),
+	}
+
+	detailTable := reporting.ScanDetailTable{
+		NoRowsMessage: "No misconfigurations found",
+		Headers: []string{
+			"Policy",
+			"Severity",
+			"File",
+			"Resource",
+			"Message",
+		},
+		WithCounter:   true,
+		CounterHeader: "Entry #",
+	}
+	for _, finding := range findings {
+		var style reporting.ColumnStyle = reporting.Yellow
+		if finding.IsSevere(threshold) {
+			style = reporting.Red
+		}
+		location := finding.File
+		if finding.Line > 0 {
+			location = fmt.Sprintf("%v:%v", finding.File, finding.Line)
+		}
+		row := reporting.ScanRow{}
+		row.AddColumn(fmt.Sprintf("%v %v", finding.PolicyID, finding.Title), 0)
+		row.AddColumn(finding.Severity, style)
+		row.AddColumn(location, 0)
+		row.AddColumn(finding.Resource, 0)
+		row.AddColumn(finding.Message, 0)
+		detailTable.Rows = append(detailTable.Rows, row)
+	}
+	scanReport.DetailTable = detailTable
+
+	return scanReport
+}
+
+// CreateSarif creates a SARIF result from the findings
+func CreateSarif(findings []Finding) *format.SARIF {
+	log.Entry().Debug("Creating SARIF file for data transfer")
+	sarif := format.SARIF{
+		Schema:  "https://docs.oasis-open.org/sarif/sarif/v2.1.0/cos02/schemas/sarif-schema-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []format.Runs{{}},
+	}
+
+	tool := format.Tool{Driver: format.Driver{
+		Name:           "Piper IaC scanner",
+		InformationUri: "https://github.com/SAP/jenkins-library",
+	}}
+
+	collectedRules := []string{}
+	for _, finding := range findings {
+		hash := sha256.Sum256([]byte(fmt.Sprintf("%v:%v:%v:%v", finding.PolicyID, finding.File, finding.Resource, finding.Message)))
+		result := format.Results{
+			RuleID:         finding.PolicyID,
+			Level:          sarifLevel(finding.Severity),
+			Message:        &format.Message{Text: fmt.Sprintf("%v: %v", finding.Resource, finding.Message)},
+			AnalysisTarget: &format.ArtifactLocation{URI: finding.File},
+			Locations: []format.Location{{PhysicalLocation: format.PhysicalLocation{
+				ArtifactLocation: format.ArtifactLocation{URI: finding.File},
+				Region:           format.Region{StartLine: finding.Line},
+			}}},
+			PartialFingerprints: format.PartialFingerprints{PrimaryLocationLineHash: hex.EncodeToString(hash[:])},
+		}
+		sarif.Runs[0].Results = append(sarif.Runs[0].Results, result)
+
+		// only create rule on new policy
+		if !piperutils.ContainsString(collectedRules, finding.PolicyID) {
+			collectedRules = append(collectedRules, finding.PolicyID)
+			tool.Driver.Rules = append(tool.Driver.Rules, format.SarifRule{
+				ID:                   finding.PolicyID,
+				Name:                 finding.Title,
+				ShortDescription:     &format.Message{Text: finding.Title},
+				FullDescription:      &format.Message{Text: finding.Title},
+				DefaultConfiguration: &format.DefaultConfiguration{Level: sarifLevel(finding.Severity)},
+				Properties: &format.SarifRuleProperties{
+					Tags:             []string{"security", "misconfiguration"},
+					Precision:        "very-high",
+					SecuritySeverity: securitySeverity(finding.Severity),
+				},
+			})
+		}
+	}
+	sarif.Runs[0].Tool = tool
+
+	conversion := new(format.Conversion)
+	conversion.Tool.Driver.Name = "Piper IaC scan to SARIF converter"
+	conversion.Tool.Driver.InformationUri = "https://github.com/SAP/jenkins-library"
+	conversion.Invocation.ExecutionSuccessful = true
+	conversion.Invocation.Properties = &format.InvocationProperties{Platform: runtime.GOOS}
+	sarif.Runs[0].Conversion = conversion
+
+	return &sarif
+}
+
+func sarifLevel(severity string) string {
+	switch severity {
+	case SeverityHigh:
+		return "error"
+	case SeverityMedium:
+		return "warning"
+	}
+	return "note"
+}
+
+// securitySeverity maps the severity to a score as used by GitHub code scanning
+func securitySeverity(severity string) string {
+	switch severity {
+	case SeverityHigh:
+		return "8.0"
+	case SeverityMedium:
+		return "5.0"
+	}
+	return "2.0"
+}
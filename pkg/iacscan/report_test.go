@@ -0,0 +1,48 @@
+package iacscan
+
+import (
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/reporting"
+	"github.com/stretchr/testify/assert"
+)
+
+var testFindings = []Finding{
+	{PolicyID: "K8S001", Title: "Privileged container", Severity: SeverityHigh, File: "chart.yaml", Line: 11, Resource: "Deployment/app", Message: "container 'app': securityContext.privileged is true"},
+	{PolicyID: "CF004", Title: "Missing memory limit", Severity: SeverityLow, File: "manifest.yml", Resource: "application/worker", Message: "memory is not set"},
+}
+
+func TestCreateScanReport(t *testing.T) {
+	t.Parallel()
+
+	scanReport := CreateScanReport([]string{"chart.yaml", "manifest.yml"}, testFindings, SeverityHigh)
+
+	assert.Equal(t, "IaC Misconfiguration Report", scanReport.ReportTitle)
+	assert.False(t, scanReport.SuccessfulScan)
+	assert.Equal(t, "1", scanReport.Overview[1].Details)
+	if assert.Len(t, scanReport.DetailTable.Rows, 2) {
+		assert.Equal(t, "chart.yaml:11", scanReport.DetailTable.Rows[0].Columns[2].Content)
+		assert.Equal(t, reporting.ColumnStyle(reporting.Red), scanReport.DetailTable.Rows[0].Columns[1].Style)
+		assert.Equal(t, "manifest.yml", scanReport.DetailTable.Rows[1].Columns[2].Content)
+		assert.Equal(t, reporting.ColumnStyle(reporting.Yellow), scanReport.DetailTable.Rows[1].Columns[1].Style)
+	}
+
+	assert.True(t, CreateScanReport(nil, testFindings, "none").SuccessfulScan)
+}
+
+func TestCreateSarif(t *testing.T) {
+	t.Parallel()
+
+	sarif := CreateSarif(testFindings)
+
+	results := sarif.Runs[0].Results
+	if assert.Len(t, results, 2) {
+		assert.Equal(t, "error", results[0].Level)
+		assert.Equal(t, "Deployment/app: container 'app': securityContext.privileged is true", results[0].Message.Text)
+		assert.Equal(t, 11, results[0].Locations[0].PhysicalLocation.Region.StartLine)
+		assert.Equal(t, "note", results[1].Level)
+	}
+	if assert.Len(t, sarif.Runs[0].Tool.Driver.Rules, 2) {
+		assert.Equal(t, "8.0", sarif.Runs[0].Tool.Driver.Rules[0].Properties.SecuritySeverity)
+	}
+}
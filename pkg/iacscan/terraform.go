@@ -0,0 +1,147 @@
+package iacscan
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// terraformPlan is the relevant part of the JSON representation of a plan as created by 'terraform show -json <plan>'
+type terraformPlan struct {
+	PlannedValues struct {
+		RootModule terraformModule `json:"root_module"`
+	} `json:"planned_values"`
+}
+
+type terraformModule struct {
+	Resources []struct {
+		Address string                 `json:"address"`
+		Mode    string                 `json:"mode"`
+		Type    string                 `json:"type"`
+		Values  map[string]interface{} `json:"values"`
+	} `json:"resources"`
+	ChildModules []terraformModule `json:"child_modules"`
+}
+
+func readTerraformResources(file string, content []byte) ([]Resource, error) {
+	plan := terraformPlan{}
+	if err := json.Unmarshal(content, &plan); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse Terraform plan '%v'", file)
+	}
+	resources := []Resource{}
+	var collect func(module terraformModule)
+	collect = func(module terraformModule) {
+		for _, resource := range module.Resources {
+			// data sources are only read and not created
+			if resource.Mode == "data" {
+				continue
+			}
+			resources = append(resources, Resource{
+				Type:    TypeTerraform,
+				Kind:    resource.Type,
+				Name:    resource.Address,
+				File:    file,
+				Content: resource.Values,
+			})
+		}
+		for _, child := range module.ChildModules {
+			collect(child)
+		}
+	}
+	collect(plan.PlannedValues.RootModule)
+	return resources, nil
+}
+
+func terraformPolicies() []Policy {
+	return []Policy{
+		{
+			ID:       "TF001",
+			Title:    "Public S3 bucket",
+			Severity: SeverityHigh,
+			Type:     TypeTerraform,
+			Kinds:    []string{"aws_s3_bucket", "aws_s3_bucket_acl"},
+			check: func(resource Resource) []violation {
+				acl, _ := lookupString(resource.Content, "acl")
+				switch acl {
+				case "public-read", "public-read-write", "authenticated-read":
+					return []violation{{message: fmt.Sprintf("acl is '%v'", acl)}}
+				}
+				return nil
+			},
+		},
+		{
+			ID:       "TF002",
+			Title:    "Ingress open to the internet",
+			Severity: SeverityHigh,
+			Type:     TypeTerraform,
+			Kinds:    []string{"aws_security_group", "aws_security_group_rule"},
+			check: func(resource Resource) []violation {
+				rules := []map[string]interface{}{}
+				if resource.Kind == "aws_security_group" {
+					rules = asMaps(resource.Content["ingress"])
+				} else if resource.Content["type"] == "ingress" {
+					rules = append(rules, resource.Content)
+				}
+				violations := []violation{}
+				for _, rule := range rules {
+					cidrs, _ := rule["cidr_blocks"].([]interface{})
+					ipv6, _ := rule["ipv6_cidr_blocks"].([]interface{})
+					for _, cidr := range append(cidrs, ipv6...) {
+						if cidr == "0.0.0.0/0" || cidr == "::/0" {
+							violations = append(violations, violation{message: fmt.Sprintf("ports %v-%v are open to %v", rule["from_port"], rule["to_port"], cidr)})
+						}
+					}
+				}
+				return violations
+			},
+		},
+		{
+			ID:       "TF003",
+			Title:    "Publicly accessible database",
+			Severity: SeverityHigh,
+			Type:     TypeTerraform,
+			Kinds:    []string{"aws_db_instance", "aws_rds_cluster_instance"},
+			check: func(resource Resource) []violation {
+				if resource.Content["publicly_accessible"] == true {
+					return []violation{{message: "publicly_accessible is true"}}
+				}
+				return nil
+			},
+		},
+		{
+			ID:       "TF004",
+			Title:    "Unencrypted storage",
+			Severity: SeverityMedium,
+			Type:     TypeTerraform,
+			Kinds:    []string{"aws_db_instance", "aws_rds_cluster", "aws_ebs_volume"},
+			check: func(resource Resource) []violation {
+				field := "storage_encrypted"
+				if resource.Kind == "aws_ebs_volume" {
+					field = "encrypted"
+				}
+				if resource.Content[field] != true {
+					return []violation{{message: fmt.Sprintf("%v is not set to true", field)}}
+				}
+				return nil
+			},
+		},
+		{
+			ID:       "TF005",
+			Title:    "Insecure transport to storage account",
+			Severity: SeverityMedium,
+			Type:     TypeTerraform,
+			Kinds:    []string{"azurerm_storage_account"},
+			check: func(resource Resource) []violation {
+				violations := []violation{}
+				if resource.Content["enable_https_traffic_only"] == false {
+					violations = append(violations, violation{message: "enable_https_traffic_only is false"})
+				}
+				if version, _ := lookupString(resource.Content, "min_tls_version"); version == "TLS1_0" || version == "TLS1_1" {
+					violations = append(violations, violation{message: fmt.Sprintf("min_tls_version is '%v'", version)})
+				}
+				return violations
+			},
+		},
+	}
+}
@@ -0,0 +1,214 @@
+package license
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	operatorAnd  = "AND"
+	operatorOr   = "OR"
+	operatorWith = "WITH"
+)
+
+// Expression is a parsed SPDX license expression, see https://spdx.github.io/spdx-spec/v2.3/SPDX-license-expressions/
+type Expression struct {
+	// Operator is AND or OR for compound expressions and empty for a single license
+	Operator string
+	// License is the SPDX id of a single license, optionally followed by WITH and the id of a license exception
+	License string
+	// Known is false if the license could not be mapped to an SPDX id
+	Known    bool
+	Operands []*Expression
+}
+
+// Parse parses a license expression and normalizes the contained licenses to SPDX ids.
+// Besides SPDX expressions, license names (e.g. "Apache License, Version 2.0") and lower case operators are supported.
+func Parse(expression string) (*Expression, error) {
+	if len(strings.TrimSpace(expression)) == 0 {
+		return nil, fmt.Errorf("empty license expression")
+	}
+	if id, known := Normalize(expression); known {
+		return &Expression{License: id, Known: true}, nil
+	}
+	p := parser{tokens: tokenize(expression)}
+	result, err := p.parseOr()
+	if err != nil {
+		return nil, fmt.Errorf("invalid license expression '%v': %w", expression, err)
+	}
+	if p.position < len(p.tokens) {
+		return nil, fmt.Errorf("invalid license expression '%v': unexpected '%v'", expression, p.tokens[p.position])
+	}
+	return result, nil
+}
+
+// And combines expressions with the AND operator, e.g. for components declaring several licenses
+func And(expressions ...*Expression) *Expression {
+	if len(expressions) == 1 {
+		return expressions[0]
+	}
+	return (&Expression{Operator: operatorAnd, Operands: expressions}).flatten()
+}
+
+// String returns the SPDX representation of the expression
+func (e *Expression) String() string {
+	if len(e.Operator) == 0 {
+		return e.License
+	}
+	operands := []string{}
+	for _, operand := range e.Operands {
+		if e.Operator == operatorAnd && operand.Operator == operatorOr {
+			operands = append(operands, "("+operand.String()+")")
+		} else {
+			operands = append(operands, operand.String())
+		}
+	}
+	return strings.Join(operands, " "+e.Operator+" ")
+}
+
+// Licenses returns the distinct licenses contained in the expression
+func (e *Expression) Licenses() []string {
+	licenses := []string{}
+	found := map[string]bool{}
+	var collect func(expression *Expression)
+	collect = func(expression *Expression) {
+		if len(expression.Operator) == 0 {
+			if !found[expression.License] {
+				found[expression.License] = true
+				licenses = append(licenses, expression.License)
+			}
+			return
+		}
+		for _, operand := range expression.Operands {
+			collect(operand)
+		}
+	}
+	collect(e)
+	return licenses
+}
+
+// flatten merges nested operands using the same operator, i.e. (A AND B) AND C becomes A AND B AND C
+func (e *Expression) flatten() *Expression {
+	operands := []*Expression{}
+	for _, operand := range e.Operands {
+		if operand.Operator == e.Operator {
+			operands = append(operands, operand.Operands...)
+		} else {
+			operands = append(operands, operand)
+		}
+	}
+	e.Operands = operands
+	return e
+}
+
+func tokenize(expression string) []string {
+	expression = strings.NewReplacer("(", " ( ", ")", " ) ").Replace(expression)
+	return strings.Fields(expression)
+}
+
+func isOperator(token string) bool {
+	switch strings.ToUpper(token) {
+	case operatorAnd, operatorOr, operatorWith:
+		return true
+	}
+	return false
+}
+
+type parser struct {
+	tokens   []string
+	position int
+}
+
+func (p *parser) peek() string {
+	if p.position < len(p.tokens) {
+		return p.tokens[p.position]
+	}
+	return ""
+}
+
+func (p *parser) parseOr() (*Expression, error) {
+	return p.parseBinary(operatorOr, p.parseAnd)
+}
+
+func (p *parser) parseAnd() (*Expression, error) {
+	return p.parseBinary(operatorAnd, p.parseWith)
+}
+
+func (p *parser) parseBinary(operator string, parseOperand func() (*Expression, error)) (*Expression, error) {
+	first, err := parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	operands := []*Expression{first}
+	for strings.ToUpper(p.peek()) == operator {
+		p.position++
+		operand, err := parseOperand()
+		if err != nil {
+			return nil, err
+		}
+		operands = append(operands, operand)
+	}
+	if len(operands) == 1 {
+		return first, nil
+	}
+	return (&Expression{Operator: operator, Operands: operands}).flatten(), nil
+}
+
+func (p *parser) parseWith() (*Expression, error) {
+	license, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	if strings.ToUpper(p.peek()) != operatorWith {
+		return license, nil
+	}
+	p.position++
+	if len(license.Operator) > 0 {
+		return nil, fmt.Errorf("WITH needs to follow a single license")
+	}
+	name := p.parseName()
+	if len(name) == 0 {
+		return nil, fmt.Errorf("missing license exception after WITH")
+	}
+	exception, ok := knownExceptions[strings.ToLower(name)]
+	if !ok {
+		exception = name
+		license.Known = false
+	}
+	license.License = fmt.Sprintf("%v WITH %v", license.License, exception)
+	return license, nil
+}
+
+func (p *parser) parsePrimary() (*Expression, error) {
+	switch p.peek() {
+	case "":
+		return nil, fmt.Errorf("unexpected end of expression")
+	case "(":
+		p.position++
+		expression, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, fmt.Errorf("missing closing parenthesis")
+		}
+		p.position++
+		return expression, nil
+	}
+	name := p.parseName()
+	if len(name) == 0 {
+		return nil, fmt.Errorf("unexpected '%v'", p.peek())
+	}
+	id, known := Normalize(name)
+	return &Expression{License: id, Known: known}, nil
+}
+
+// parseName consumes all tokens up to the next operator or parenthesis, this allows license names containing spaces
+func (p *parser) parseName() string {
+	words := []string{}
+	for token := p.peek(); len(token) > 0 && token != "(" && token != ")" && !isOperator(token); token = p.peek() {
+		words = append(words, token)
+		p.position++
+	}
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,57 @@
+package license
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	tt := []struct {
+		expression string
+		expected   string
+		licenses   []string
+	}{
+		{expression: "MIT", expected: "MIT", licenses: []string{"MIT"}},
+		{expression: "Apache License, Version 2.0", expected: "Apache-2.0", licenses: []string{"Apache-2.0"}},
+		{expression: "MIT OR Apache-2.0", expected: "MIT OR Apache-2.0", licenses: []string{"MIT", "Apache-2.0"}},
+		{expression: "mit or apache 2.0", expected: "MIT OR Apache-2.0", licenses: []string{"MIT", "Apache-2.0"}},
+		{expression: "(MIT AND BSD-3-Clause) AND ISC", expected: "MIT AND BSD-3-Clause AND ISC", licenses: []string{"MIT", "BSD-3-Clause", "ISC"}},
+		{expression: "MIT AND (GPL-2.0 OR Apache-2.0)", expected: "MIT AND (GPL-2.0-only OR Apache-2.0)", licenses: []string{"MIT", "GPL-2.0-only", "Apache-2.0"}},
+		{expression: "MIT OR GPL-3.0 AND ISC", expected: "MIT OR GPL-3.0-only AND ISC", licenses: []string{"MIT", "GPL-3.0-only", "ISC"}},
+		{expression: "GPL-2.0-only WITH classpath-exception-2.0", expected: "GPL-2.0-only WITH Classpath-exception-2.0", licenses: []string{"GPL-2.0-only WITH Classpath-exception-2.0"}},
+	}
+	for _, test := range tt {
+		t.Run(test.expression, func(t *testing.T) {
+			expression, err := Parse(test.expression)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, expression.String())
+			assert.Equal(t, test.licenses, expression.Licenses())
+		})
+	}
+
+	t.Run("operator precedence", func(t *testing.T) {
+		expression, err := Parse("MIT OR GPL-3.0 AND ISC")
+		assert.NoError(t, err)
+		assert.Equal(t, operatorOr, expression.Operator)
+		assert.Equal(t, operatorAnd, expression.Operands[1].Operator)
+	})
+
+	t.Run("unknown license", func(t *testing.T) {
+		expression, err := Parse("Custom License OR MIT")
+		assert.NoError(t, err)
+		assert.False(t, expression.Operands[0].Known)
+		assert.Equal(t, "LicenseRef-custom-license OR MIT", expression.String())
+	})
+
+	t.Run("invalid expressions", func(t *testing.T) {
+		_, err := Parse("")
+		assert.EqualError(t, err, "empty license expression")
+		_, err = Parse("(MIT OR Apache-2.0")
+		assert.EqualError(t, err, "invalid license expression '(MIT OR Apache-2.0': missing closing parenthesis")
+		_, err = Parse("MIT AND")
+		assert.EqualError(t, err, "invalid license expression 'MIT AND': unexpected end of expression")
+		_, err = Parse("MIT)")
+		assert.EqualError(t, err, "invalid license expression 'MIT)': unexpected ')'")
+	})
+}
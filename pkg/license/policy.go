@@ -0,0 +1,304 @@
+package license
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/SAP/jenkins-library/pkg/sbom"
+	"github.com/bmatcuk/doublestar"
+	"github.com/ghodss/yaml"
+)
+
+// Decision is the result of the policy evaluation for a license or component
+type Decision string
+
+const (
+	// Allowed licenses can be used without further checks
+	Allowed Decision = "allow"
+	// Review licenses need to be checked by the legal department
+	Review Decision = "review"
+	// Denied licenses must not be used
+	Denied Decision = "deny"
+)
+
+func (d Decision) rank() int {
+	switch d {
+	case Allowed:
+		return 0
+	case Review:
+		return 1
+	}
+	return 2
+}
+
+// Policy defines which licenses are allowed, denied or need to be reviewed.
+// License entries are SPDX ids and may contain wildcards, e.g. GPL-*.
+type Policy struct {
+	Allow []string `json:"allow,omitempty"`
+	Deny  []string `json:"deny,omitempty"`
+	// Review lists licenses which need to be reviewed, licenses neither allowed, denied nor reviewed are handled according to Unknown
+	Review []string `json:"review,omitempty"`
+	// Unknown is the decision for licenses not covered by the lists and for components without license information
+	Unknown    Decision    `json:"unknown,omitempty"`
+	Exceptions []Exception `json:"exceptions,omitempty"`
+}
+
+// Exception allows licenses for dedicated components
+type Exception struct {
+	// Component is the package URL of the component, it applies to all versions if the version is omitted.
+	// Qualifiers and subpath are ignored. Wildcards are supported, * matches within a path segment and ** across segments.
+	Component string `json:"component"`
+	// Licenses are the licenses allowed for the component, all licenses are allowed if empty
+	Licenses []string `json:"licenses,omitempty"`
+	Reason   string   `json:"reason,omitempty"`
+}
+
+// Result is the policy evaluation result for a component
+type Result struct {
+	Name       string
+	Version    string
+	PackageURL string
+	Copyright  string
+	// DeclaredLicenses are the licenses as listed in the BOM
+	DeclaredLicenses []string
+	// Expression is the normalized SPDX license expression, empty if no license is declared
+	Expression string
+	Decision   Decision
+	Reason     string
+	Exception  *Exception
+}
+
+// ReadPolicy parses a policy in YAML or JSON format
+func ReadPolicy(content []byte) (*Policy, error) {
+	policy := Policy{}
+	if err := yaml.Unmarshal(content, &policy); err != nil {
+		return nil, fmt.Errorf("invalid license policy: %w", err)
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+// Validate checks the consistency of the policy
+func (p *Policy) Validate() error {
+	switch p.Unknown {
+	case "":
+		p.Unknown = Review
+	case Allowed, Review, Denied:
+	default:
+		return fmt.Errorf("invalid decision '%v' for unknown licenses, supported are %v, %v and %v", p.Unknown, Allowed, Review, Denied)
+	}
+	for _, pattern := range append(append(append([]string{}, p.Allow...), p.Deny...), p.Review...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid license pattern '%v': %w", pattern, err)
+		}
+	}
+	for _, exception := range p.Exceptions {
+		if len(exception.Component) == 0 {
+			return fmt.Errorf("license exception without component")
+		}
+	}
+	return nil
+}
+
+// Merge adds the entries of another policy, the decision for unknown licenses is taken over if set
+func (p *Policy) Merge(other Policy) {
+	p.Allow = append(p.Allow, other.Allow...)
+	p.Deny = append(p.Deny, other.Deny...)
+	p.Review = append(p.Review, other.Review...)
+	p.Exceptions = append(p.Exceptions, other.Exceptions...)
+	if len(other.Unknown) > 0 {
+		p.Unknown = other.Unknown
+	}
+}
+
+// EvaluateBOM evaluates the licenses of all components of a BOM, components are sorted by name
+func (p *Policy) EvaluateBOM(bom *cdx.BOM) []Result {
+	results := []Result{}
+	for _, component := range sbom.Components(bom) {
+		results = append(results, p.Evaluate(component))
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Name != results[j].Name {
+			return results[i].Name < results[j].Name
+		}
+		return results[i].Version < results[j].Version
+	})
+	return results
+}
+
+// Evaluate evaluates the licenses of a component against the policy
+func (p *Policy) Evaluate(component cdx.Component) Result {
+	result := Result{
+		Name:             component.Name,
+		Version:          component.Version,
+		PackageURL:       component.PackageURL,
+		Copyright:        component.Copyright,
+		DeclaredLicenses: sbom.LicenseIDs(component),
+	}
+	if len(component.Group) > 0 && len(component.PackageURL) == 0 {
+		result.Name = component.Group + ":" + component.Name
+	}
+	exceptions := p.exceptionsFor(component)
+
+	if len(result.DeclaredLicenses) == 0 {
+		result.Decision = p.unknownDecision()
+		result.Reason = "no license declared"
+		if len(exceptions) > 0 && len(exceptions[0].Licenses) == 0 {
+			result.Decision, result.Reason, result.Exception = Allowed, exceptionReason(exceptions[0]), &exceptions[0]
+		}
+		return result
+	}
+
+	expressions := []*Expression{}
+	for _, declared := range result.DeclaredLicenses {
+		expression, err := Parse(declared)
+		if err != nil {
+			id, _ := Normalize(declared)
+			expression = &Expression{License: id}
+		}
+		expressions = append(expressions, expression)
+	}
+	expression := And(expressions...)
+	result.Expression = expression.String()
+	result.Decision, result.Reason, result.Exception = p.evaluate(expression, exceptions)
+	return result
+}
+
+func (p *Policy) unknownDecision() Decision {
+	if len(p.Unknown) == 0 {
+		return Review
+	}
+	return p.Unknown
+}
+
+// evaluate determines the decision for an expression: AND requires all licenses to be acceptable, OR allows to choose the best license
+func (p *Policy) evaluate(expression *Expression, exceptions []Exception) (Decision, string, *Exception) {
+	if len(expression.Operator) == 0 {
+		return p.evaluateLicense(expression, exceptions)
+	}
+	var decision Decision
+	var reason string
+	var exception *Exception
+	for i, operand := range expression.Operands {
+		operandDecision, operandReason, operandException := p.evaluate(operand, exceptions)
+		worse := operandDecision.rank() > decision.rank()
+		better := operandDecision.rank() < decision.rank()
+		if i == 0 || (expression.Operator == operatorAnd && worse) || (expression.Operator == operatorOr && better) {
+			decision, reason, exception = operandDecision, operandReason, operandException
+		}
+	}
+	return decision, reason, exception
+}
+
+func (p *Policy) evaluateLicense(expression *Expression, exceptions []Exception) (Decision, string, *Exception) {
+	license := expression.License
+	baseLicense := strings.SplitN(license, " WITH ", 2)[0]
+	for i, exception := range exceptions {
+		if len(exception.Licenses) == 0 || matchesAny(exception.Licenses, license, baseLicense) {
+			return Allowed, exceptionReason(exception), &exceptions[i]
+		}
+	}
+	// entries for a license including its exception take precedence over entries for the license itself
+	candidates := []string{baseLicense}
+	if baseLicense != license {
+		candidates = []string{license, baseLicense}
+	}
+	for _, candidate := range candidates {
+		with := candidate != baseLicense
+		switch {
+		case matchesAny(withFilter(p.Deny, with), candidate):
+			return Denied, fmt.Sprintf("license %v is denied", license), nil
+		case matchesAny(withFilter(p.Allow, with), candidate):
+			return Allowed, fmt.Sprintf("license %v is allowed", license), nil
+		case matchesAny(withFilter(p.Review, with), candidate):
+			return Review, fmt.Sprintf("license %v requires a review", license), nil
+		}
+	}
+	if !expression.Known {
+		return p.unknownDecision(), fmt.Sprintf("license %v is unknown", license), nil
+	}
+	return p.unknownDecision(), fmt.Sprintf("license %v is not covered by the policy", license), nil
+}
+
+func (p *Policy) exceptionsFor(component cdx.Component) []Exception {
+	exceptions := []Exception{}
+	if len(component.PackageURL) == 0 {
+		return exceptions
+	}
+	purl := trimPackageURL(component.PackageURL)
+	candidates := []string{purl}
+	if versionless := trimPackageURLVersion(purl); versionless != purl {
+		candidates = append(candidates, versionless)
+	}
+	for _, exception := range p.Exceptions {
+		pattern := trimPackageURL(exception.Component)
+		for _, candidate := range candidates {
+			if matched, _ := doublestar.Match(pattern, candidate); matched || pattern == candidate {
+				exceptions = append(exceptions, exception)
+				break
+			}
+		}
+	}
+	return exceptions
+}
+
+// trimPackageURL removes the qualifiers and the subpath from a package URL since they do not identify the component
+func trimPackageURL(purl string) string {
+	if i := strings.IndexAny(purl, "?#"); i >= 0 {
+		purl = purl[:i]
+	}
+	return strings.TrimSpace(purl)
+}
+
+// trimPackageURLVersion removes the version from a package URL without qualifiers and subpath
+func trimPackageURLVersion(purl string) string {
+	if i := strings.LastIndex(purl, "@"); i > strings.LastIndex(purl, "/") {
+		return purl[:i]
+	}
+	return purl
+}
+
+func exceptionReason(exception Exception) string {
+	if len(exception.Reason) > 0 {
+		return fmt.Sprintf("exception for %v: %v", exception.Component, exception.Reason)
+	}
+	return fmt.Sprintf("exception for %v", exception.Component)
+}
+
+// withFilter returns the patterns containing a license exception if with is true, otherwise the patterns without license exception
+func withFilter(patterns []string, with bool) []string {
+	filtered := []string{}
+	for _, pattern := range patterns {
+		if strings.Contains(strings.ToUpper(pattern), " WITH ") == with {
+			filtered = append(filtered, pattern)
+		}
+	}
+	return filtered
+}
+
+func matchesAny(patterns []string, licenses ...string) bool {
+	for _, pattern := range patterns {
+		for _, license := range licenses {
+			if matched, _ := path.Match(strings.ToLower(pattern), strings.ToLower(license)); matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Count returns the number of results with the given decision
+func Count(results []Result, decision Decision) int {
+	count := 0
+	for _, result := range results {
+		if result.Decision == decision {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,128 @@
+package license
+
+import (
+	"testing"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+const testPolicy = `allow:
+  - MIT
+  - Apache-2.0
+  - BSD-*
+  - GPL-2.0-only WITH Classpath-exception-2.0
+deny:
+  - GPL-*
+  - AGPL-*
+review:
+  - LGPL-*
+  - MPL-2.0
+exceptions:
+  - component: pkg:npm/legacy-lib
+    licenses:
+      - GPL-3.0-only
+    reason: approved by legal, see ticket 4711
+  - component: pkg:maven/com.sap.internal/*
+    reason: internal component
+  - component: pkg:golang/github.com/sap/**
+    reason: SAP module
+  - component: pkg:npm/pinned-lib@1.0.0?vcs_url=git
+    reason: pinned version
+`
+
+func component(name, purl string, licenses ...string) cdx.Component {
+	choices := cdx.Licenses{}
+	for _, license := range licenses {
+		choices = append(choices, cdx.LicenseChoice{Expression: license})
+	}
+	return cdx.Component{Name: name, Version: "1.0.0", PackageURL: purl, Licenses: &choices}
+}
+
+func TestReadPolicy(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		policy, err := ReadPolicy([]byte(testPolicy))
+
+		assert.NoError(t, err)
+		assert.Equal(t, Review, policy.Unknown)
+		assert.Len(t, policy.Exceptions, 4)
+	})
+
+	t.Run("invalid unknown decision", func(t *testing.T) {
+		_, err := ReadPolicy([]byte("unknown: ignore"))
+
+		assert.EqualError(t, err, "invalid decision 'ignore' for unknown licenses, supported are allow, review and deny")
+	})
+
+	t.Run("exception without component", func(t *testing.T) {
+		_, err := ReadPolicy([]byte("exceptions:\n  - reason: missing component"))
+
+		assert.EqualError(t, err, "license exception without component")
+	})
+}
+
+func TestEvaluate(t *testing.T) {
+	policy, err := ReadPolicy([]byte(testPolicy))
+	assert.NoError(t, err)
+
+	tt := []struct {
+		name      string
+		component cdx.Component
+		decision  Decision
+		reason    string
+	}{
+		{name: "allowed", component: component("lodash", "pkg:npm/lodash@4.17.21", "MIT"), decision: Allowed, reason: "license MIT is allowed"},
+		{name: "allowed name", component: component("commons-io", "pkg:maven/commons-io/commons-io@2.11.0", "The Apache Software License, Version 2.0"), decision: Allowed},
+		{name: "denied", component: component("gpl-lib", "pkg:npm/gpl-lib@1.0.0", "GPL-3.0"), decision: Denied, reason: "license GPL-3.0-only is denied"},
+		{name: "review", component: component("lgpl-lib", "pkg:npm/lgpl-lib@1.0.0", "LGPL-2.1-or-later"), decision: Review, reason: "license LGPL-2.1-or-later requires a review"},
+		{name: "unknown", component: component("custom", "pkg:npm/custom@1.0.0", "Custom License"), decision: Review, reason: "license LicenseRef-custom-license is unknown"},
+		{name: "not covered", component: component("zlib", "pkg:npm/zlib@1.0.0", "Zlib"), decision: Review, reason: "license Zlib is not covered by the policy"},
+		{name: "no license", component: cdx.Component{Name: "nolicense", PackageURL: "pkg:npm/nolicense@1.0.0"}, decision: Review, reason: "no license declared"},
+		{name: "OR chooses best", component: component("dual", "pkg:npm/dual@1.0.0", "GPL-2.0-only OR MIT"), decision: Allowed},
+		{name: "AND requires all", component: component("both", "pkg:npm/both@1.0.0", "MIT AND GPL-2.0-only"), decision: Denied},
+		{name: "several licenses are combined", component: component("several", "pkg:npm/several@1.0.0", "MIT", "MPL-2.0"), decision: Review},
+		{name: "license exception allowed", component: component("jdk-lib", "pkg:maven/org/jdk-lib@1.0.0", "GPL-2.0-only WITH Classpath-exception-2.0"), decision: Allowed},
+		{name: "component exception", component: component("legacy-lib", "pkg:npm/legacy-lib@2.0.0", "GPL-3.0-only"), decision: Allowed, reason: "exception for pkg:npm/legacy-lib: approved by legal, see ticket 4711"},
+		{name: "component exception other license", component: component("legacy-lib", "pkg:npm/legacy-lib@2.0.0", "AGPL-3.0-only"), decision: Denied},
+		{name: "component exception wildcard", component: cdx.Component{Name: "internal", PackageURL: "pkg:maven/com.sap.internal/internal@1.0.0"}, decision: Allowed, reason: "exception for pkg:maven/com.sap.internal/*: internal component"},
+		{name: "component exception wildcard single path segment", component: component("nested", "pkg:maven/com.sap.internal/nested/lib@1.0.0", "GPL-3.0-only"), decision: Denied},
+		{name: "component exception wildcard path segments", component: component("module", "pkg:golang/github.com/sap/jenkins-library/pkg/log@v1.0.0", "GPL-3.0-only"), decision: Allowed, reason: "exception for pkg:golang/github.com/sap/**: SAP module"},
+		{name: "component exception with qualifiers", component: component("legacy-lib", "pkg:npm/legacy-lib@2.0.0?repository_url=https://registry.example.com#lib/index.js", "GPL-3.0-only"), decision: Allowed},
+		{name: "component exception for version", component: component("pinned-lib", "pkg:npm/pinned-lib@1.0.0?arch=x86", "GPL-3.0-only"), decision: Allowed, reason: "exception for pkg:npm/pinned-lib@1.0.0?vcs_url=git: pinned version"},
+		{name: "component exception other version", component: component("pinned-lib", "pkg:npm/pinned-lib@1.0.1", "GPL-3.0-only"), decision: Denied},
+	}
+	for _, test := range tt {
+		t.Run(test.name, func(t *testing.T) {
+			result := policy.Evaluate(test.component)
+			assert.Equal(t, test.decision, result.Decision)
+			if len(test.reason) > 0 {
+				assert.Equal(t, test.reason, result.Reason)
+			}
+		})
+	}
+
+	t.Run("deny unknown licenses", func(t *testing.T) {
+		strictPolicy := *policy
+		strictPolicy.Merge(Policy{Unknown: Denied, Allow: []string{"Zlib"}})
+
+		assert.Equal(t, Denied, strictPolicy.Evaluate(component("custom", "pkg:npm/custom@1.0.0", "Custom License")).Decision)
+		assert.Equal(t, Allowed, strictPolicy.Evaluate(component("zlib", "pkg:npm/zlib@1.0.0", "Zlib")).Decision)
+	})
+}
+
+func TestEvaluateBOM(t *testing.T) {
+	policy, err := ReadPolicy([]byte(testPolicy))
+	assert.NoError(t, err)
+	bom := cdx.NewBOM()
+	bom.Components = &[]cdx.Component{
+		component("lodash", "pkg:npm/lodash@4.17.21", "MIT"),
+		component("gpl-lib", "pkg:npm/gpl-lib@1.0.0", "GPL-3.0-only"),
+	}
+
+	results := policy.EvaluateBOM(bom)
+
+	assert.Len(t, results, 2)
+	assert.Equal(t, "gpl-lib", results[0].Name)
+	assert.Equal(t, 1, Count(results, Denied))
+	assert.Equal(t, 1, Count(results, Allowed))
+}
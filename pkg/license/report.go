@@ -0,0 +1,128 @@
+package license
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/reporting"
+)
+
+// CreateScanReport creates a license compliance ScanReport to be used for uploading into various sinks
+func CreateScanReport(bomFiles []string, results []Result) reporting.ScanReport {
+	denied := Count(results, Denied)
+	review := Count(results, Review)
+	exceptions := 0
+	for _, result := range results {
+		if result.Exception != nil {
+			exceptions++
+		}
+	}
+
+	scanReport := reporting.ScanReport{
+		ReportTitle: "License Compliance Report",
+		Subheaders: []reporting.Subheader{
+			{Description: "Evaluated BOMs", Details: strings.Join(bomFiles, ", ")},
+		},
+		Overview: []reporting.OverviewRow{
+			{Description: "Total number of components", Details: fmt.Sprint(len(results))},
+			{Description: "Components with denied licenses", Details: fmt.Sprint(denied)},
+			{Description: "Components with licenses requiring a review", Details: fmt.Sprint(review)},
+			{Description: "Components allowed via exception", Details: fmt.Sprint(exceptions)},
+		},
+		SuccessfulScan: denied == 0,
+		ReportTime:     time.Now(),
+	}
+
+	detailTable := reporting.ScanDetailTable{
+		NoRowsMessage: "No components found",
+		Headers: []string{
+			"Component",
+			"Version",
+			"Package URL",
+			"Declared licenses",
+			"SPDX expression",
+			"Decision",
+			"Reason",
+		},
+		WithCounter:   true,
+		CounterHeader: "Entry #",
+	}
+
+	// list the findings first
+	sorted := make([]Result, len(results))
+	copy(sorted, results)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Decision.rank() > sorted[j].Decision.rank()
+	})
+	for _, result := range sorted {
+		var style reporting.ColumnStyle = reporting.Green
+		switch result.Decision {
+		case Denied:
+			style = reporting.Red
+		case Review:
+			style = reporting.Yellow
+		}
+		row := reporting.ScanRow{}
+		row.AddColumn(result.Name, 0)
+		row.AddColumn(result.Version, 0)
+		row.AddColumn(result.PackageURL, 0)
+		row.AddColumn(strings.Join(result.DeclaredLicenses, ", "), 0)
+		row.AddColumn(result.Expression, 0)
+		row.AddColumn(result.Decision, style)
+		row.AddColumn(result.Reason, 0)
+		detailTable.Rows = append(detailTable.Rows, row)
+	}
+	scanReport.DetailTable = detailTable
+
+	return scanReport
+}
+
+// CreateNotice creates an attribution notice listing all components with their licenses and copyright notices
+func CreateNotice(productName string, results []Result) []byte {
+	notice := bytes.Buffer{}
+	if len(productName) > 0 {
+		notice.WriteString(fmt.Sprintf("%v\n\n", productName))
+	}
+	notice.WriteString("This product includes the following third-party components:\n")
+
+	licenses := map[string]bool{}
+	for _, result := range results {
+		notice.WriteString("\n--------------------------------------------------------------------------------\n")
+		notice.WriteString(fmt.Sprintf("%v %v\n", result.Name, result.Version))
+		if len(result.PackageURL) > 0 {
+			notice.WriteString(fmt.Sprintf("Package URL: %v\n", result.PackageURL))
+		}
+		license := result.Expression
+		if len(license) == 0 {
+			license = "NOASSERTION"
+		}
+		notice.WriteString(fmt.Sprintf("License: %v\n", license))
+		if len(result.Copyright) > 0 {
+			notice.WriteString(fmt.Sprintf("Copyright: %v\n", result.Copyright))
+		}
+		if expression, err := Parse(result.Expression); err == nil {
+			for _, id := range expression.Licenses() {
+				if !strings.HasPrefix(id, "LicenseRef-") {
+					licenses[strings.SplitN(id, " WITH ", 2)[0]] = true
+				}
+			}
+		}
+	}
+
+	ids := []string{}
+	for id := range licenses {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	if len(ids) > 0 {
+		notice.WriteString("\n================================================================================\n")
+		notice.WriteString("The license texts are available at:\n\n")
+		for _, id := range ids {
+			notice.WriteString(fmt.Sprintf("%v: https://spdx.org/licenses/%v.html\n", id, id))
+		}
+	}
+	return notice.Bytes()
+}
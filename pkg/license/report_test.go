@@ -0,0 +1,42 @@
+package license
+
+import (
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/reporting"
+	"github.com/stretchr/testify/assert"
+)
+
+func testResults() []Result {
+	return []Result{
+		{Name: "lodash", Version: "4.17.21", PackageURL: "pkg:npm/lodash@4.17.21", DeclaredLicenses: []string{"MIT"}, Expression: "MIT", Decision: Allowed, Copyright: "Copyright OpenJS Foundation"},
+		{Name: "gpl-lib", Version: "1.0.0", PackageURL: "pkg:npm/gpl-lib@1.0.0", DeclaredLicenses: []string{"GPL-3.0"}, Expression: "GPL-3.0-only", Decision: Denied},
+		{Name: "custom", Version: "1.0.0", DeclaredLicenses: []string{"Custom"}, Expression: "LicenseRef-custom", Decision: Review},
+		{Name: "legacy-lib", Version: "2.0.0", Expression: "GPL-2.0-only WITH Classpath-exception-2.0", Decision: Allowed, Exception: &Exception{Component: "pkg:npm/legacy-lib"}},
+	}
+}
+
+func TestCreateScanReport(t *testing.T) {
+	scanReport := CreateScanReport([]string{"product-bom.xml"}, testResults())
+
+	assert.False(t, scanReport.SuccessfulScan)
+	assert.Equal(t, "4", scanReport.Overview[0].Details)
+	assert.Equal(t, "1", scanReport.Overview[1].Details)
+	assert.Equal(t, "1", scanReport.Overview[2].Details)
+	assert.Equal(t, "1", scanReport.Overview[3].Details)
+	assert.Len(t, scanReport.DetailTable.Rows, 4)
+	// denied components are listed first
+	assert.Equal(t, "gpl-lib", scanReport.DetailTable.Rows[0].Columns[0].Content)
+	assert.Equal(t, reporting.ColumnStyle(reporting.Red), scanReport.DetailTable.Rows[0].Columns[5].Style)
+	assert.Equal(t, reporting.ColumnStyle(reporting.Yellow), scanReport.DetailTable.Rows[1].Columns[5].Style)
+}
+
+func TestCreateNotice(t *testing.T) {
+	notice := string(CreateNotice("My Product", testResults()))
+
+	assert.Contains(t, notice, "My Product\n\nThis product includes the following third-party components:")
+	assert.Contains(t, notice, "lodash 4.17.21\nPackage URL: pkg:npm/lodash@4.17.21\nLicense: MIT\nCopyright: Copyright OpenJS Foundation\n")
+	assert.Contains(t, notice, "custom 1.0.0\nLicense: LicenseRef-custom\n")
+	assert.Contains(t, notice, "GPL-2.0-only: https://spdx.org/licenses/GPL-2.0-only.html\nGPL-3.0-only: https://spdx.org/licenses/GPL-3.0-only.html\nMIT: https://spdx.org/licenses/MIT.html\n")
+	assert.NotContains(t, notice, "LicenseRef-custom:")
+}
@@ -0,0 +1,149 @@
+package license
+
+import (
+	"regexp"
+	"strings"
+)
+
+// spdxIDs contains the SPDX license ids commonly used by open source components, see https://spdx.org/licenses/
+var spdxIDs = []string{
+	"0BSD", "AFL-3.0", "AGPL-1.0-only", "AGPL-1.0-or-later", "AGPL-3.0-only", "AGPL-3.0-or-later", "Apache-1.0", "Apache-1.1", "Apache-2.0",
+	"APSL-2.0", "Artistic-1.0", "Artistic-2.0", "BlueOak-1.0.0", "BSD-1-Clause", "BSD-2-Clause", "BSD-2-Clause-Patent", "BSD-3-Clause",
+	"BSD-3-Clause-Clear", "BSD-4-Clause", "BSL-1.0", "BUSL-1.1", "CC-BY-3.0", "CC-BY-4.0", "CC-BY-NC-4.0", "CC-BY-SA-3.0", "CC-BY-SA-4.0",
+	"CC0-1.0", "CDDL-1.0", "CDDL-1.1", "CPL-1.0", "EPL-1.0", "EPL-2.0", "EUPL-1.1", "EUPL-1.2", "GPL-1.0-only", "GPL-1.0-or-later",
+	"GPL-2.0-only", "GPL-2.0-or-later", "GPL-3.0-only", "GPL-3.0-or-later", "ISC", "JSON", "LGPL-2.0-only", "LGPL-2.0-or-later",
+	"LGPL-2.1-only", "LGPL-2.1-or-later", "LGPL-3.0-only", "LGPL-3.0-or-later", "MIT", "MIT-0", "MPL-1.0", "MPL-1.1", "MPL-2.0",
+	"MPL-2.0-no-copyleft-exception", "MS-PL", "MS-RL", "NCSA", "ODbL-1.0", "OFL-1.1", "OpenSSL", "OSL-3.0", "PHP-3.01", "PostgreSQL",
+	"Python-2.0", "Ruby", "SSPL-1.0", "Unicode-DFS-2016", "Unlicense", "UPL-1.0", "W3C", "WTFPL", "X11", "Zlib", "ZPL-2.1",
+}
+
+// spdxExceptions contains the SPDX license exceptions commonly used together with the WITH operator
+var spdxExceptions = []string{
+	"Autoconf-exception-3.0", "Bison-exception-2.2", "Classpath-exception-2.0", "GCC-exception-3.1", "LLVM-exception", "OpenJDK-assembly-exception-1.0",
+}
+
+// aliases maps license names and license URLs used in package manifests to SPDX ids.
+// Keys are lower case with all non alphanumeric characters removed, see aliasKey.
+var aliases = map[string]string{
+	"apache2":                           "Apache-2.0",
+	"apache20":                          "Apache-2.0",
+	"apachelicense20":                   "Apache-2.0",
+	"apachelicenseversion20":            "Apache-2.0",
+	"apachesoftwarelicense20":           "Apache-2.0",
+	"theapachesoftwarelicenseversion20": "Apache-2.0",
+	"theapachelicenseversion20":         "Apache-2.0",
+	"asl20":                             "Apache-2.0",
+	"apacheorglicenseslicense20":        "Apache-2.0",
+	"apacheorglicenseslicense20txt":     "Apache-2.0",
+	"mitlicense":                        "MIT",
+	"themitlicense":                     "MIT",
+	"opensourceorglicensesmit":          "MIT",
+	"opensourceorglicensesmitlicense":   "MIT",
+	"bsd":                               "BSD-3-Clause",
+	"newbsdlicense":                     "BSD-3-Clause",
+	"bsd3clauselicense":                 "BSD-3-Clause",
+	"thebsd3clauselicense":              "BSD-3-Clause",
+	"revisedbsd":                        "BSD-3-Clause",
+	"simplifiedbsd":                     "BSD-2-Clause",
+	"bsd2clauselicense":                 "BSD-2-Clause",
+	"freebsd":                           "BSD-2-Clause",
+	"isclicense":                        "ISC",
+	"eclipsepubliclicense10":            "EPL-1.0",
+	"eclipsepubliclicensev10":           "EPL-1.0",
+	"eclipsepubliclicense20":            "EPL-2.0",
+	"eclipsepubliclicensev20":           "EPL-2.0",
+	"mozillapubliclicense20":            "MPL-2.0",
+	"mozillapubliclicenseversion20":     "MPL-2.0",
+	"cddl10":                            "CDDL-1.0",
+	"cddl11":                            "CDDL-1.1",
+	"commondevelopmentanddistributionlicensecddlv10": "CDDL-1.0",
+	"commondevelopmentanddistributionlicensecddlv11": "CDDL-1.1",
+	"gpl2":                                   "GPL-2.0-only",
+	"gpl20":                                  "GPL-2.0-only",
+	"gplv2":                                  "GPL-2.0-only",
+	"gnugeneralpubliclicensev20":             "GPL-2.0-only",
+	"gnugeneralpubliclicenseversion2":        "GPL-2.0-only",
+	"gpl3":                                   "GPL-3.0-only",
+	"gpl30":                                  "GPL-3.0-only",
+	"gplv3":                                  "GPL-3.0-only",
+	"gnugeneralpubliclicensev30":             "GPL-3.0-only",
+	"gnugeneralpubliclicenseversion3":        "GPL-3.0-only",
+	"lgpl21":                                 "LGPL-2.1-only",
+	"lgplv21":                                "LGPL-2.1-only",
+	"gnulessergeneralpubliclicensev21":       "LGPL-2.1-only",
+	"gnulessergeneralpubliclicenseversion21": "LGPL-2.1-only",
+	"lgpl3":                                  "LGPL-3.0-only",
+	"lgpl30":                                 "LGPL-3.0-only",
+	"lgplv3":                                 "LGPL-3.0-only",
+	"gnulessergeneralpubliclicensev30":       "LGPL-3.0-only",
+	"agpl3":                                  "AGPL-3.0-only",
+	"agplv3":                                 "AGPL-3.0-only",
+	"cc0":                                    "CC0-1.0",
+	"publicdomaincc010":                      "CC0-1.0",
+	"theunlicense":                           "Unlicense",
+	"boostsoftwarelicense10":                 "BSL-1.0",
+	"python20":                               "Python-2.0",
+	"psf":                                    "Python-2.0",
+	"psflicense":                             "Python-2.0",
+	"zliblicense":                            "Zlib",
+	"universalpermissivelicense10":           "UPL-1.0",
+}
+
+// deprecatedIDs maps deprecated SPDX ids to their successors
+var deprecatedIDs = map[string]string{
+	"GPL-1.0":   "GPL-1.0-only",
+	"GPL-1.0+":  "GPL-1.0-or-later",
+	"GPL-2.0":   "GPL-2.0-only",
+	"GPL-2.0+":  "GPL-2.0-or-later",
+	"GPL-3.0":   "GPL-3.0-only",
+	"GPL-3.0+":  "GPL-3.0-or-later",
+	"LGPL-2.0":  "LGPL-2.0-only",
+	"LGPL-2.0+": "LGPL-2.0-or-later",
+	"LGPL-2.1":  "LGPL-2.1-only",
+	"LGPL-2.1+": "LGPL-2.1-or-later",
+	"LGPL-3.0":  "LGPL-3.0-only",
+	"LGPL-3.0+": "LGPL-3.0-or-later",
+	"AGPL-1.0":  "AGPL-1.0-only",
+	"AGPL-3.0":  "AGPL-3.0-only",
+}
+
+var (
+	knownIDs        = map[string]string{}
+	knownExceptions = map[string]string{}
+	nonAlphanumeric = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+func init() {
+	for _, id := range spdxIDs {
+		knownIDs[strings.ToLower(id)] = id
+	}
+	for deprecated, id := range deprecatedIDs {
+		knownIDs[strings.ToLower(deprecated)] = id
+	}
+	for _, id := range spdxExceptions {
+		knownExceptions[strings.ToLower(id)] = id
+	}
+}
+
+func aliasKey(name string) string {
+	key := strings.ToLower(strings.TrimSpace(name))
+	key = strings.TrimPrefix(strings.TrimPrefix(key, "https://"), "http://")
+	key = strings.TrimPrefix(key, "www.")
+	return nonAlphanumeric.ReplaceAllString(key, "")
+}
+
+// Normalize maps a license id, name or URL to its SPDX id.
+// The second return value is false if the license is unknown, in this case a LicenseRef derived from the name is returned.
+func Normalize(name string) (string, bool) {
+	name = strings.TrimSpace(name)
+	if id, ok := knownIDs[strings.ToLower(name)]; ok {
+		return id, true
+	}
+	if strings.HasPrefix(name, "LicenseRef-") {
+		return name, false
+	}
+	if id, ok := aliases[aliasKey(name)]; ok {
+		return id, true
+	}
+	return "LicenseRef-" + strings.Trim(nonAlphanumeric.ReplaceAllString(strings.ToLower(name), "-"), "-"), false
+}
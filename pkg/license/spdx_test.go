@@ -0,0 +1,33 @@
+package license
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	tt := []struct {
+		name     string
+		expected string
+		known    bool
+	}{
+		{name: "MIT", expected: "MIT", known: true},
+		{name: "apache-2.0", expected: "Apache-2.0", known: true},
+		{name: "GPL-2.0+", expected: "GPL-2.0-or-later", known: true},
+		{name: "LGPL-2.1", expected: "LGPL-2.1-only", known: true},
+		{name: "The Apache Software License, Version 2.0", expected: "Apache-2.0", known: true},
+		{name: "http://www.apache.org/licenses/LICENSE-2.0.txt", expected: "Apache-2.0", known: true},
+		{name: "https://opensource.org/licenses/MIT", expected: "MIT", known: true},
+		{name: "Eclipse Public License - v 2.0", expected: "EPL-2.0", known: true},
+		{name: "LicenseRef-scancode-proprietary", expected: "LicenseRef-scancode-proprietary", known: false},
+		{name: "My Custom License 1.0", expected: "LicenseRef-my-custom-license-1-0", known: false},
+	}
+	for _, test := range tt {
+		t.Run(test.name, func(t *testing.T) {
+			id, known := Normalize(test.name)
+			assert.Equal(t, test.expected, id)
+			assert.Equal(t, test.known, known)
+		})
+	}
+}
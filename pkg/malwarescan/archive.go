@@ -0,0 +1,199 @@
+package malwarescan
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Target is a file to be scanned. Files extracted from archives are named after the archive, e.g. app.mtar!lib/app.jar
+type Target struct {
+	Path string `json:"-"`
+	Name string `json:"name"`
+}
+
+// entrySeparator separates the name of an archive from the name of an entry within the archive
+const entrySeparator = "!"
+
+var zipExtensions = []string{".zip", ".jar", ".war", ".ear", ".mtar", ".aar", ".apk", ".nupkg", ".whl"}
+var tarExtensions = []string{".tar", ".tar.gz", ".tgz"}
+
+// IsArchive returns true for files which can be unpacked. Image layers are handled as tar archives.
+func IsArchive(name string) bool {
+	return isZip(name) || isTar(name)
+}
+
+func isZip(name string) bool {
+	return hasExtension(name, zipExtensions)
+}
+
+func isTar(name string) bool {
+	return hasExtension(name, tarExtensions)
+}
+
+func hasExtension(name string, extensions []string) bool {
+	lowerName := strings.ToLower(name)
+	for _, extension := range extensions {
+		if strings.HasSuffix(lowerName, extension) {
+			return true
+		}
+	}
+	return false
+}
+
+// Unpacker extracts archives recursively, e.g. an image into its layers and the layers into their files.
+// The size of all extracted files is limited to protect the build environment against archive bombs.
+type Unpacker struct {
+	// MaxSize limits the number of bytes extracted in total
+	MaxSize int64
+	// MaxDepth limits the nesting of archives which are unpacked, more deeply nested archives are scanned as a whole
+	MaxDepth  int
+	extracted int64
+}
+
+// Unpack extracts the archive into the destination directory and returns the files to be scanned.
+// Nested archives are replaced by their content.
+func (u *Unpacker) Unpack(archive Target, destination string) ([]Target, error) {
+	return u.unpack(archive, destination, 1)
+}
+
+func (u *Unpacker) unpack(archive Target, destination string, depth int) ([]Target, error) {
+	var entries []Target
+	var err error
+	if isZip(archive.Name) {
+		entries, err = u.unzip(archive, destination)
+	} else {
+		entries, err = u.untar(archive, destination)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	targets := []Target{}
+	for _, entry := range entries {
+		if !IsArchive(entry.Name) || depth >= u.MaxDepth {
+			targets = append(targets, entry)
+			continue
+		}
+		nestedTargets, err := u.unpack(entry, entry.Path+"_content", depth+1)
+		if errors.Is(err, ErrUnpackLimit) {
+			return nil, err
+		}
+		if err != nil {
+			// e.g. a file with an archive extension which is not a valid archive, it is scanned as a whole
+			targets = append(targets, entry)
+			continue
+		}
+		if err := os.Remove(entry.Path); err != nil {
+			return nil, errors.Wrapf(err, "failed to remove unpacked archive '%v'", entry.Name)
+		}
+		targets = append(targets, nestedTargets...)
+	}
+	return targets, nil
+}
+
+// ErrUnpackLimit is returned if the content of an archive exceeds the size limit
+var ErrUnpackLimit = errors.New("unpack size limit exceeded")
+
+func (u *Unpacker) unzip(archive Target, destination string) ([]Target, error) {
+	reader, err := zip.OpenReader(archive.Path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open archive '%v'", archive.Name)
+	}
+	defer reader.Close()
+
+	targets := []Target{}
+	for _, file := range reader.File {
+		if file.FileInfo().IsDir() {
+			continue
+		}
+		content, err := file.Open()
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read '%v' of archive '%v'", file.Name, archive.Name)
+		}
+		target, err := u.extract(archive, file.Name, content, destination)
+		content.Close()
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, target)
+	}
+	return targets, nil
+}
+
+func (u *Unpacker) untar(archive Target, destination string) ([]Target, error) {
+	file, err := os.Open(archive.Path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open archive '%v'", archive.Name)
+	}
+	defer file.Close()
+
+	var reader io.Reader = bufio.NewReader(file)
+	if header, err := reader.(*bufio.Reader).Peek(2); err == nil && header[0] == 0x1f && header[1] == 0x8b {
+		gzipReader, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to decompress archive '%v'", archive.Name)
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+	}
+
+	targets := []Target{}
+	tarReader := tar.NewReader(reader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read archive '%v'", archive.Name)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		target, err := u.extract(archive, header.Name, tarReader, destination)
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, target)
+	}
+	return targets, nil
+}
+
+// extract writes the content of an archive entry into the destination directory while accounting for the size limit
+func (u *Unpacker) extract(archive Target, entryName string, content io.Reader, destination string) (Target, error) {
+	// entry names must not leave the destination directory
+	relativePath := filepath.Clean(string(filepath.Separator) + filepath.FromSlash(entryName))
+	target := Target{
+		Path: filepath.Join(destination, relativePath),
+		Name: archive.Name + entrySeparator + strings.TrimPrefix(filepath.ToSlash(relativePath), "/"),
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target.Path), 0755); err != nil {
+		return target, errors.Wrapf(err, "failed to create directory for '%v'", target.Name)
+	}
+	file, err := os.Create(target.Path)
+	if err != nil {
+		return target, errors.Wrapf(err, "failed to create '%v'", target.Name)
+	}
+	defer file.Close()
+
+	remaining := u.MaxSize - u.extracted
+	written, err := io.Copy(file, io.LimitReader(content, remaining+1))
+	u.extracted += written
+	if err != nil {
+		return target, errors.Wrapf(err, "failed to extract '%v'", target.Name)
+	}
+	if written > remaining {
+		return target, fmt.Errorf("failed to extract '%v': %w of %v bytes", target.Name, ErrUnpackLimit, u.MaxSize)
+	}
+	return target, nil
+}
@@ -0,0 +1,155 @@
+package malwarescan
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func zipContent(t *testing.T, files map[string][]byte) []byte {
+	var buffer bytes.Buffer
+	writer := zip.NewWriter(&buffer)
+	for name, content := range files {
+		file, err := writer.Create(name)
+		require.NoError(t, err)
+		_, err = file.Write(content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+	return buffer.Bytes()
+}
+
+func tarGzContent(t *testing.T, files map[string][]byte) []byte {
+	var buffer bytes.Buffer
+	gzipWriter := gzip.NewWriter(&buffer)
+	writer := tar.NewWriter(gzipWriter)
+	for name, content := range files {
+		require.NoError(t, writer.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}))
+		_, err := writer.Write(content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+	require.NoError(t, gzipWriter.Close())
+	return buffer.Bytes()
+}
+
+func TestIsArchive(t *testing.T) {
+	assert.True(t, IsArchive("app.mtar"))
+	assert.True(t, IsArchive("target/app.JAR"))
+	assert.True(t, IsArchive("image.tar"))
+	assert.True(t, IsArchive("3a4f.tar.gz"))
+	assert.False(t, IsArchive("app.exe"))
+}
+
+func TestUnpack(t *testing.T) {
+	t.Run("nested archives", func(t *testing.T) {
+		dir := t.TempDir()
+		archivePath := filepath.Join(dir, "app.mtar")
+		content := zipContent(t, map[string][]byte{
+			"META-INF/MANIFEST.MF": []byte("Manifest-Version: 1.0"),
+			"srv/srv.jar":          zipContent(t, map[string][]byte{"com/example/App.class": []byte("class")}),
+		})
+		require.NoError(t, os.WriteFile(archivePath, content, 0644))
+
+		unpacker := Unpacker{MaxSize: 1024 * 1024, MaxDepth: 3}
+		targets, err := unpacker.Unpack(Target{Path: archivePath, Name: "app.mtar"}, filepath.Join(dir, "content"))
+
+		assert.NoError(t, err)
+		names := []string{}
+		for _, target := range targets {
+			names = append(names, target.Name)
+			assert.FileExists(t, target.Path)
+		}
+		assert.ElementsMatch(t, []string{"app.mtar!META-INF/MANIFEST.MF", "app.mtar!srv/srv.jar!com/example/App.class"}, names)
+		assert.NoFileExists(t, filepath.Join(dir, "content", "srv", "srv.jar"))
+	})
+
+	t.Run("image layers", func(t *testing.T) {
+		dir := t.TempDir()
+		archivePath := filepath.Join(dir, "image.tar")
+		var buffer bytes.Buffer
+		writer := tar.NewWriter(&buffer)
+		layer := tarGzContent(t, map[string][]byte{"usr/bin/app": []byte("binary")})
+		require.NoError(t, writer.WriteHeader(&tar.Header{Name: "manifest.json", Mode: 0644, Size: 2, Typeflag: tar.TypeReg}))
+		_, _ = writer.Write([]byte("[]"))
+		require.NoError(t, writer.WriteHeader(&tar.Header{Name: "4f3a.tar.gz", Mode: 0644, Size: int64(len(layer)), Typeflag: tar.TypeReg}))
+		_, _ = writer.Write(layer)
+		require.NoError(t, writer.Close())
+		require.NoError(t, os.WriteFile(archivePath, buffer.Bytes(), 0644))
+
+		unpacker := Unpacker{MaxSize: 1024 * 1024, MaxDepth: 3}
+		targets, err := unpacker.Unpack(Target{Path: archivePath, Name: "image.tar"}, filepath.Join(dir, "content"))
+
+		assert.NoError(t, err)
+		if assert.Len(t, targets, 2) {
+			assert.Equal(t, "image.tar!manifest.json", targets[0].Name)
+			assert.Equal(t, "image.tar!4f3a.tar.gz!usr/bin/app", targets[1].Name)
+		}
+	})
+
+	t.Run("maximum depth", func(t *testing.T) {
+		dir := t.TempDir()
+		archivePath := filepath.Join(dir, "app.zip")
+		content := zipContent(t, map[string][]byte{"lib/lib.jar": zipContent(t, map[string][]byte{"Lib.class": []byte("class")})})
+		require.NoError(t, os.WriteFile(archivePath, content, 0644))
+
+		unpacker := Unpacker{MaxSize: 1024 * 1024, MaxDepth: 1}
+		targets, err := unpacker.Unpack(Target{Path: archivePath, Name: "app.zip"}, filepath.Join(dir, "content"))
+
+		assert.NoError(t, err)
+		if assert.Len(t, targets, 1) {
+			assert.Equal(t, "app.zip!lib/lib.jar", targets[0].Name)
+		}
+	})
+
+	t.Run("size limit", func(t *testing.T) {
+		dir := t.TempDir()
+		archivePath := filepath.Join(dir, "app.zip")
+		content := zipContent(t, map[string][]byte{"large.bin": bytes.Repeat([]byte("0"), 2048)})
+		require.NoError(t, os.WriteFile(archivePath, content, 0644))
+
+		unpacker := Unpacker{MaxSize: 1024, MaxDepth: 3}
+		_, err := unpacker.Unpack(Target{Path: archivePath, Name: "app.zip"}, filepath.Join(dir, "content"))
+
+		assert.EqualError(t, err, "failed to extract 'app.zip!large.bin': unpack size limit exceeded of 1024 bytes")
+		assert.ErrorIs(t, err, ErrUnpackLimit)
+	})
+
+	t.Run("entries must not leave the destination", func(t *testing.T) {
+		dir := t.TempDir()
+		archivePath := filepath.Join(dir, "app.zip")
+		content := zipContent(t, map[string][]byte{"../../evil.sh": []byte("rm -rf")})
+		require.NoError(t, os.WriteFile(archivePath, content, 0644))
+
+		unpacker := Unpacker{MaxSize: 1024, MaxDepth: 3}
+		targets, err := unpacker.Unpack(Target{Path: archivePath, Name: "app.zip"}, filepath.Join(dir, "content"))
+
+		assert.NoError(t, err)
+		if assert.Len(t, targets, 1) {
+			assert.Equal(t, filepath.Join(dir, "content", "evil.sh"), targets[0].Path)
+			assert.Equal(t, "app.zip!evil.sh", targets[0].Name)
+		}
+	})
+
+	t.Run("invalid nested archive", func(t *testing.T) {
+		dir := t.TempDir()
+		archivePath := filepath.Join(dir, "app.zip")
+		content := zipContent(t, map[string][]byte{"broken.jar": []byte("no zip")})
+		require.NoError(t, os.WriteFile(archivePath, content, 0644))
+
+		unpacker := Unpacker{MaxSize: 1024, MaxDepth: 3}
+		targets, err := unpacker.Unpack(Target{Path: archivePath, Name: "app.zip"}, filepath.Join(dir, "content"))
+
+		assert.NoError(t, err)
+		if assert.Len(t, targets, 1) {
+			assert.Equal(t, "app.zip!broken.jar", targets[0].Name)
+		}
+	})
+}
@@ -0,0 +1,145 @@
+package malwarescan
+
+import (
+	"fmt"
+	"runtime"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/reporting"
+)
+
+// CreateScanReport creates a malware scan ScanReport to be used for uploading into various sinks
+func CreateScanReport(verdicts []Verdict, scannerInfo *Info) reporting.ScanReport {
+	failed := 0
+	for _, verdict := range verdicts {
+		if verdict.Failed() {
+			failed++
+		}
+	}
+	scanReport := reporting.ScanReport{
+		ReportTitle: "Malware Scan Report",
+		Subheaders: []reporting.Subheader{
+			{Description: "Engine", Details: scannerInfo.EngineVersion},
+			{Description: "Signatures", Details: scannerInfo.SignatureTimestamp},
+		},
+		Overview: []reporting.OverviewRow{
+			{Description: "Scanned files", Details: fmt.Sprint(len(verdicts))},
+			{Description: "Failed files", Details: fmt.Sprint(failed)},
+		},
+		SuccessfulScan: failed == 0,
+		ReportTime:     time.Now(),
+	}
+
+	detailTable := reporting.ScanDetailTable{
+		NoRowsMessage: "No files scanned",
+		Headers: []string{
+			"File",
+			"SHA256",
+			"Malware",
+			"Encrypted content",
+			"Finding",
+		},
+		WithCounter:   true,
+		CounterHeader: "Entry #",
+	}
+	for _, verdict := range verdicts {
+		finding, style := verdict.Finding, reporting.ColumnStyle(reporting.Green)
+		if verdict.Error != nil {
+			finding, style = verdict.ErrorMessage, reporting.Yellow
+		} else if verdict.Failed() {
+			style = reporting.Red
+		}
+		row := reporting.ScanRow{}
+		row.AddColumn(verdict.File, 0)
+		row.AddColumn(verdict.SHA256, 0)
+		row.AddColumn(verdict.MalwareDetected, 0)
+		row.AddColumn(verdict.EncryptedContentDetected, 0)
+		row.AddColumn(finding, style)
+		detailTable.Rows = append(detailTable.Rows, row)
+	}
+	scanReport.DetailTable = detailTable
+
+	return scanReport
+}
+
+// CreateSarif creates a SARIF result containing the files in which malware or encrypted content has been detected or which could not be scanned
+func CreateSarif(verdicts []Verdict) *format.SARIF {
+	log.Entry().Debug("Creating SARIF file for data transfer")
+	sarif := format.SARIF{
+		Schema:  "https://docs.oasis-open.org/sarif/sarif/v2.1.0/cos02/schemas/sarif-schema-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []format.Runs{{Results: []format.Results{}}},
+	}
+
+	tool := format.Tool{Driver: format.Driver{
+		Name:           "Malware scanner",
+		InformationUri: "https://api.sap.com/api/MalwareScanAPI/overview",
+	}}
+
+	rules := map[string]string{
+		"malware":           "Malware detected",
+		"encrypted-content": "Encrypted content detected",
+		"scan-error":        "File could not be scanned",
+	}
+	collectedRules := []string{}
+	for _, verdict := range verdicts {
+		ruleIDs := []string{}
+		if verdict.MalwareDetected {
+			ruleIDs = append(ruleIDs, "malware")
+		}
+		if verdict.EncryptedContentDetected {
+			ruleIDs = append(ruleIDs, "encrypted-content")
+		}
+		if verdict.Error != nil {
+			ruleIDs = append(ruleIDs, "scan-error")
+		}
+		for _, ruleID := range ruleIDs {
+			message := fmt.Sprintf("%v in file %v", rules[ruleID], verdict.File)
+			if ruleID == "malware" && len(verdict.Finding) > 0 {
+				message = fmt.Sprintf("%v: %v", message, verdict.Finding)
+			}
+			if ruleID == "scan-error" {
+				message = fmt.Sprintf("%v: %v", message, verdict.ErrorMessage)
+			}
+			sarif.Runs[0].Results = append(sarif.Runs[0].Results, format.Results{
+				RuleID:         ruleID,
+				Level:          "error",
+				Message:        &format.Message{Text: message},
+				AnalysisTarget: &format.ArtifactLocation{URI: verdict.File},
+				Locations: []format.Location{{PhysicalLocation: format.PhysicalLocation{
+					ArtifactLocation: format.ArtifactLocation{URI: verdict.File},
+				}}},
+				PartialFingerprints: format.PartialFingerprints{PrimaryLocationLineHash: verdict.SHA256},
+			})
+
+			// only create rule on new rule id
+			if !piperutils.ContainsString(collectedRules, ruleID) {
+				collectedRules = append(collectedRules, ruleID)
+				tool.Driver.Rules = append(tool.Driver.Rules, format.SarifRule{
+					ID:                   ruleID,
+					Name:                 ruleID,
+					ShortDescription:     &format.Message{Text: rules[ruleID]},
+					FullDescription:      &format.Message{Text: fmt.Sprintf("%v. The file must not be published.", rules[ruleID])},
+					DefaultConfiguration: &format.DefaultConfiguration{Level: "error"},
+					Properties: &format.SarifRuleProperties{
+						Tags:      []string{"security", "malware"},
+						Precision: "high",
+					},
+				})
+			}
+		}
+	}
+	sarif.Runs[0].Tool = tool
+
+	conversion := new(format.Conversion)
+	conversion.Tool.Driver.Name = "Piper malware scan to SARIF converter"
+	conversion.Tool.Driver.InformationUri = "https://github.com/SAP/jenkins-library"
+	conversion.Invocation.ExecutionSuccessful = true
+	conversion.Invocation.Properties = &format.InvocationProperties{Platform: runtime.GOOS}
+	sarif.Runs[0].Conversion = conversion
+
+	return &sarif
+}
@@ -0,0 +1,136 @@
+package malwarescan
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+)
+
+// FileAccess provides access to the files to be scanned
+type FileAccess interface {
+	OpenFile(name string, flag int, perm os.FileMode) (io.ReadCloser, error)
+	SHA256(path string) (string, error)
+}
+
+// ScanOptions configures the scan of several files
+type ScanOptions struct {
+	// Concurrency limits the number of parallel uploads
+	Concurrency int
+	// Retries defines how often a failed upload is repeated
+	Retries       int
+	RetryInterval time.Duration
+	// Engine is the version of the scan engine which is recorded with each verdict
+	Engine string
+}
+
+// Verdict is the result of scanning a single file
+type Verdict struct {
+	File   string `json:"file"`
+	Engine string `json:"engine,omitempty"`
+	ScanResult
+	Error        error  `json:"-"`
+	ErrorMessage string `json:"error,omitempty"`
+}
+
+func (v *Verdict) setError(err error) {
+	v.Error = err
+	v.ErrorMessage = err.Error()
+}
+
+// Failed returns true if the file could not be scanned or the scan found malware or encrypted content
+func (v Verdict) Failed() bool {
+	return v.Error != nil || v.MalwareDetected || v.EncryptedContentDetected
+}
+
+// FailureMessage describes why the verdict failed
+func (v Verdict) FailureMessage() string {
+	if v.Error != nil {
+		return v.Error.Error()
+	}
+	return fmt.Sprintf("Malware scan failed for file '%s'. Malware detected: %t, encrypted content detected: %t, finding: %v",
+		v.File, v.MalwareDetected, v.EncryptedContentDetected, v.Finding)
+}
+
+// ScanTargets uploads the targets in parallel and returns the verdicts in the order of the targets.
+// The hash returned by the service is validated against the hash of the local file.
+func ScanTargets(client Client, targets []Target, files FileAccess, options ScanOptions) []Verdict {
+	concurrency := options.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	verdicts := make([]Verdict, len(targets))
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for worker := 0; worker < concurrency; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				verdicts[index] = scanTarget(client, targets[index], files, options)
+			}
+		}()
+	}
+	for index := range targets {
+		indexes <- index
+	}
+	close(indexes)
+	wg.Wait()
+
+	return verdicts
+}
+
+func scanTarget(client Client, target Target, files FileAccess, options ScanOptions) Verdict {
+	verdict := Verdict{File: target.Name, Engine: options.Engine}
+
+	var scanResult *ScanResult
+	var err error
+	for attempt := 0; attempt <= options.Retries; attempt++ {
+		if attempt > 0 {
+			log.Entry().WithError(err).Warnf("Scan of file '%s' failed, retrying (%d/%d)", target.Name, attempt, options.Retries)
+			time.Sleep(options.RetryInterval)
+		}
+		var candidate io.ReadCloser
+		candidate, err = files.OpenFile(target.Path, os.O_RDONLY, 0666)
+		if err != nil {
+			// not worth a retry
+			verdict.setError(err)
+			return verdict
+		}
+		scanResult, err = client.Scan(candidate)
+		candidate.Close()
+		if err == nil {
+			break
+		}
+	}
+	if err != nil {
+		verdict.setError(err)
+		return verdict
+	}
+	verdict.ScanResult = *scanResult
+
+	log.Entry().Debugf(
+		"File '%s' has been scanned. MalwareDetected: %t, EncryptedContentDetected: %t, ScanSize: %d, MimeType: '%s', SHA256: '%s', Finding: '%s'",
+		target.Name,
+		scanResult.MalwareDetected,
+		scanResult.EncryptedContentDetected,
+		scanResult.ScanSize,
+		scanResult.MimeType,
+		scanResult.SHA256,
+		scanResult.Finding)
+
+	hash, err := files.SHA256(target.Path)
+	if err != nil {
+		verdict.setError(err)
+		return verdict
+	}
+	if hash != scanResult.SHA256 {
+		verdict.setError(fmt.Errorf("Hash returned from malwarescan service ('%s') does not match file hash ('%s') for file '%s'",
+			scanResult.SHA256, hash, target.Name))
+	}
+	return verdict
+}
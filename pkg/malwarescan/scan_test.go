@@ -0,0 +1,144 @@
+package malwarescan
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type scanClientMock struct {
+	mutex        sync.Mutex
+	failingScans map[string]int
+	running      int
+	maxRunning   int
+}
+
+func (c *scanClientMock) Scan(candidate io.Reader) (*ScanResult, error) {
+	content, _ := io.ReadAll(candidate)
+	c.mutex.Lock()
+	c.running++
+	if c.running > c.maxRunning {
+		c.maxRunning = c.running
+	}
+	failing := c.failingScans[string(content)]
+	if failing > 0 {
+		c.failingScans[string(content)]--
+	}
+	c.mutex.Unlock()
+	defer func() {
+		c.mutex.Lock()
+		c.running--
+		c.mutex.Unlock()
+	}()
+
+	if failing > 0 {
+		return nil, errors.New("MalwareService returned with status code 503, no further information available")
+	}
+	return &ScanResult{MalwareDetected: strings.Contains(string(content), "EICAR"), SHA256: "sha-" + string(content)}, nil
+}
+
+func (c *scanClientMock) Info() (*Info, error) {
+	return &Info{EngineVersion: "Mock Engine"}, nil
+}
+
+type fileAccessMock map[string]string
+
+func (f fileAccessMock) OpenFile(name string, flag int, perm os.FileMode) (io.ReadCloser, error) {
+	content, ok := f[name]
+	if !ok {
+		return nil, fmt.Errorf("the file '%s' does not exist: file does not exist", name)
+	}
+	return io.NopCloser(strings.NewReader(content)), nil
+}
+
+func (f fileAccessMock) SHA256(path string) (string, error) {
+	if path == "tampered" {
+		return "other", nil
+	}
+	return "sha-" + f[path], nil
+}
+
+func TestScanTargets(t *testing.T) {
+	files := fileAccessMock{"a": "clean a", "b": "EICAR", "c": "clean c", "tampered": "clean"}
+
+	t.Run("parallel scan", func(t *testing.T) {
+		client := &scanClientMock{}
+		targets := []Target{{Path: "a", Name: "a"}, {Path: "b", Name: "b"}, {Path: "c", Name: "app.zip!c"}}
+
+		verdicts := ScanTargets(client, targets, files, ScanOptions{Concurrency: 2, Engine: "Mock Engine"})
+
+		if assert.Len(t, verdicts, 3) {
+			assert.Equal(t, "a", verdicts[0].File)
+			assert.False(t, verdicts[0].Failed())
+			assert.Equal(t, "sha-clean a", verdicts[0].SHA256)
+			assert.Equal(t, "Mock Engine", verdicts[0].Engine)
+			assert.True(t, verdicts[1].Failed())
+			assert.Equal(t, "Malware scan failed for file 'b'. Malware detected: true, encrypted content detected: false, finding: ", verdicts[1].FailureMessage())
+			assert.Equal(t, "app.zip!c", verdicts[2].File)
+		}
+		assert.LessOrEqual(t, client.maxRunning, 2)
+	})
+
+	t.Run("retries", func(t *testing.T) {
+		client := &scanClientMock{failingScans: map[string]int{"clean a": 2, "clean c": 3}}
+		targets := []Target{{Path: "a", Name: "a"}, {Path: "c", Name: "c"}}
+
+		verdicts := ScanTargets(client, targets, files, ScanOptions{Concurrency: 2, Retries: 2})
+
+		assert.False(t, verdicts[0].Failed())
+		assert.True(t, verdicts[1].Failed())
+		assert.Equal(t, "MalwareService returned with status code 503, no further information available", verdicts[1].ErrorMessage)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		verdicts := ScanTargets(&scanClientMock{}, []Target{{Path: "d", Name: "d"}}, files, ScanOptions{})
+
+		assert.EqualError(t, verdicts[0].Error, "the file 'd' does not exist: file does not exist")
+	})
+
+	t.Run("hash mismatch", func(t *testing.T) {
+		verdicts := ScanTargets(&scanClientMock{}, []Target{{Path: "tampered", Name: "tampered"}}, files, ScanOptions{})
+
+		assert.EqualError(t, verdicts[0].Error, "Hash returned from malwarescan service ('sha-clean') does not match file hash ('other') for file 'tampered'")
+	})
+}
+
+func TestCreateScanReport(t *testing.T) {
+	verdicts := []Verdict{
+		{File: "a", ScanResult: ScanResult{SHA256: "1"}},
+		{File: "b", ScanResult: ScanResult{SHA256: "2", MalwareDetected: true, Finding: "Win.Test.EICAR_HDB-1"}},
+	}
+
+	scanReport := CreateScanReport(verdicts, &Info{EngineVersion: "Mock Engine", SignatureTimestamp: "n/a"})
+
+	assert.False(t, scanReport.SuccessfulScan)
+	assert.Equal(t, "1", scanReport.Overview[1].Details)
+	assert.Len(t, scanReport.DetailTable.Rows, 2)
+	assert.Equal(t, "Win.Test.EICAR_HDB-1", scanReport.DetailTable.Rows[1].Columns[4].Content)
+}
+
+func TestCreateSarif(t *testing.T) {
+	verdicts := []Verdict{
+		{File: "a", ScanResult: ScanResult{SHA256: "1"}},
+		{File: "b", ScanResult: ScanResult{SHA256: "2", MalwareDetected: true, EncryptedContentDetected: true, Finding: "Win.Test.EICAR_HDB-1"}},
+	}
+	failed := Verdict{File: "c"}
+	failed.setError(errors.New("upload failed"))
+	verdicts = append(verdicts, failed)
+
+	sarif := CreateSarif(verdicts)
+
+	if assert.Len(t, sarif.Runs[0].Results, 3) {
+		assert.Equal(t, "malware", sarif.Runs[0].Results[0].RuleID)
+		assert.Equal(t, "Malware detected in file b: Win.Test.EICAR_HDB-1", sarif.Runs[0].Results[0].Message.Text)
+		assert.Equal(t, "encrypted-content", sarif.Runs[0].Results[1].RuleID)
+		assert.Equal(t, "File could not be scanned in file c: upload failed", sarif.Runs[0].Results[2].Message.Text)
+	}
+	assert.Len(t, sarif.Runs[0].Tool.Driver.Rules, 3)
+}
@@ -0,0 +1,389 @@
+package maven
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/orchestrator"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+)
+
+const (
+	analysisDirectory  = ".pipeline/mavenAnalysis"
+	effectivePOMFile   = "effective-pom.xml"
+	dependencyTreeFile = "dependency-tree.json"
+
+	effectivePOMGoal   = "org.apache.maven.plugins:maven-help-plugin:3.4.0:effective-pom"
+	dependencyTreeGoal = "org.apache.maven.plugins:maven-dependency-plugin:3.7.0:tree"
+)
+
+// analysisCache holds the analyses of the current process by their cache key
+var analysisCache = map[string]*Analysis{}
+
+// buildIdentity identifies the current pipeline run. The analyses cached in the workspace are only shared between the steps
+// of the same run, since remote parent POMs or the environment of the next run may differ.
+var buildIdentity = func() string {
+	provider, err := orchestrator.NewOrchestratorSpecificConfigProvider()
+	if err != nil {
+		return ""
+	}
+	return provider.GetBuildURL()
+}
+
+// envActivation matches the environment variables which activate profiles, e.g. <name>env.CI</name>
+var envActivation = regexp.MustCompile(`<name>\s*!?env\.([^<\s]+)\s*</name>`)
+
+// AnalysisOptions are used by Analyze() to construct the Maven command line.
+type AnalysisOptions struct {
+	PomPath             string   `json:"pomPath,omitempty"`
+	ProjectSettingsFile string   `json:"projectSettingsFile,omitempty"`
+	GlobalSettingsFile  string   `json:"globalSettingsFile,omitempty"`
+	M2Path              string   `json:"m2Path,omitempty"`
+	Defines             []string `json:"defines,omitempty"`
+	// DependencyTrees enables the resolution of the dependency trees, which requires all dependencies to be downloaded
+	DependencyTrees bool `json:"dependencyTrees,omitempty"`
+	// NonRecursive restricts the analysis to the project of the POM, i.e. its modules are not analyzed
+	NonRecursive bool `json:"nonRecursive,omitempty"`
+}
+
+// Analysis contains the effective POMs and the resolved dependency trees of all modules of a Maven build.
+type Analysis struct {
+	// Root is the directory of the POM the analysis was started with
+	Root    string           `json:"root"`
+	Modules []AnalyzedModule `json:"modules"`
+}
+
+// AnalyzedModule describes a module of a Maven build.
+type AnalyzedModule struct {
+	// Path is the directory of the module relative to the current working directory
+	Path string `json:"path"`
+	// Project is the effective POM of the module, i.e. inherited values and properties are resolved
+	Project *Project `json:"project"`
+	// DependencyTree is only available if the dependency trees were requested
+	DependencyTree *DependencyNode `json:"dependencyTree,omitempty"`
+}
+
+// DependencyNode describes an artifact within a resolved dependency tree.
+type DependencyNode struct {
+	GroupID    string           `json:"groupId"`
+	ArtifactID string           `json:"artifactId"`
+	Version    string           `json:"version"`
+	Type       string           `json:"type,omitempty"`
+	Classifier string           `json:"classifier,omitempty"`
+	Scope      string           `json:"scope,omitempty"`
+	Optional   bool             `json:"optional,omitempty"`
+	Children   []DependencyNode `json:"children,omitempty"`
+}
+
+// UnmarshalJSON accepts the "optional" flag both as boolean and as string since the maven-dependency-plugin writes it as string.
+func (n *DependencyNode) UnmarshalJSON(data []byte) error {
+	type dependencyNode DependencyNode
+	node := struct {
+		*dependencyNode
+		Optional interface{} `json:"optional"`
+	}{dependencyNode: (*dependencyNode)(n)}
+	if err := json.Unmarshal(data, &node); err != nil {
+		return err
+	}
+	switch optional := node.Optional.(type) {
+	case bool:
+		n.Optional = optional
+	case string:
+		n.Optional = optional == "true"
+	}
+	return nil
+}
+
+// Coordinate returns the coordinate of the artifact in the format groupId:artifactId:type[:classifier]:version
+func (n DependencyNode) Coordinate() string {
+	parts := []string{n.GroupID, n.ArtifactID, n.Type}
+	if len(n.Classifier) > 0 {
+		parts = append(parts, n.Classifier)
+	}
+	return strings.Join(append(parts, n.Version), ":")
+}
+
+// TransitiveDependencies returns all direct and transitive dependencies of the artifact, each dependency is contained once.
+func (n DependencyNode) TransitiveDependencies() []DependencyNode {
+	dependencies := []DependencyNode{}
+	known := map[string]bool{}
+	var collect func(node DependencyNode)
+	collect = func(node DependencyNode) {
+		for _, child := range node.Children {
+			if !known[child.Coordinate()] {
+				known[child.Coordinate()] = true
+				dependencies = append(dependencies, child)
+			}
+			collect(child)
+		}
+	}
+	collect(n)
+	return dependencies
+}
+
+// Module returns the module with the given coordinates.
+func (a *Analysis) Module(groupID, artifactID string) (AnalyzedModule, bool) {
+	for _, module := range a.Modules {
+		if module.Project.GroupID == groupID && module.Project.ArtifactID == artifactID {
+			return module, true
+		}
+	}
+	return AnalyzedModule{}, false
+}
+
+// RootModule returns the module of the POM the analysis was started with.
+func (a *Analysis) RootModule() (AnalyzedModule, error) {
+	for _, module := range a.Modules {
+		if module.Path == a.Root {
+			return module, nil
+		}
+	}
+	if len(a.Modules) == 1 {
+		return a.Modules[0], nil
+	}
+	return AnalyzedModule{}, fmt.Errorf("the analysis does not contain the module in '%v'", a.Root)
+}
+
+// Analyze runs a single Maven invocation in order to retrieve the effective POMs and, if requested, the resolved dependency
+// trees of all modules. The result is cached in the workspace for the current pipeline run and the current state of the POM
+// and settings files, i.e. subsequent analyses by other steps of the same run do not call Maven unless one of them changed.
+func Analyze(options *AnalysisOptions, utils Utils) (*Analysis, error) {
+	pomPath := options.PomPath
+	if len(pomPath) == 0 {
+		pomPath = "pom.xml"
+	}
+
+	key, err := analysisCacheKey(options, pomPath, utils)
+	if err != nil {
+		return nil, err
+	}
+	if analysis, cached := analysisCache[key]; cached {
+		return analysis, nil
+	}
+	cacheFile := ""
+	if build := buildIdentity(); len(build) > 0 && build != "n/a" {
+		cacheFile = filepath.Join(analysisDirectory, fmt.Sprintf("%x.json", sha256.Sum256([]byte(build+"|"+key))))
+	} else {
+		log.Entry().Debug("the pipeline run is unknown, the Maven analysis is not cached in the workspace")
+	}
+	if len(cacheFile) > 0 {
+		if analysis, err := readCachedAnalysis(cacheFile, utils); err != nil {
+			log.Entry().WithError(err).Debug("ignoring cached Maven analysis")
+		} else if analysis != nil {
+			log.Entry().Infof("using cached Maven analysis of '%v'", pomPath)
+			analysisCache[key] = analysis
+			return analysis, nil
+		}
+	}
+
+	analysis, err := runAnalysis(options, pomPath, utils)
+	if err != nil {
+		return nil, err
+	}
+	analysisCache[key] = analysis
+	if len(cacheFile) == 0 {
+		return analysis, nil
+	}
+	if content, err := json.Marshal(analysis); err != nil {
+		log.Entry().WithError(err).Warn("failed to serialize Maven analysis")
+	} else if err := utils.FileWrite(cacheFile, content, 0666); err != nil {
+		log.Entry().WithError(err).Warn("failed to cache Maven analysis")
+	}
+	return analysis, nil
+}
+
+// analysisCacheKey hashes the options, the content of the settings files and all POM files of the workspace
+// as well as the environment variables which activate profiles of the POM files
+func analysisCacheKey(options *AnalysisOptions, pomPath string, utils Utils) (string, error) {
+	hash := sha256.New()
+	hash.Write([]byte(fmt.Sprintf("%v|%v|%v|%v|%v|%v|%v", pomPath, options.ProjectSettingsFile, options.GlobalSettingsFile, options.M2Path,
+		options.Defines, options.DependencyTrees, options.NonRecursive)))
+
+	settingsFiles := []string{options.ProjectSettingsFile, options.GlobalSettingsFile}
+	if home, err := os.UserHomeDir(); err == nil {
+		settingsFiles = append(settingsFiles, filepath.Join(home, ".m2", "settings.xml"))
+	}
+	for _, settingsFile := range settingsFiles {
+		// settings given as URL are downloaded by each Maven call, their content is covered by the URL
+		if exists, _ := utils.FileExists(settingsFile); len(settingsFile) == 0 || !exists {
+			continue
+		}
+		content, err := utils.FileRead(settingsFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to read '%v': %w", settingsFile, err)
+		}
+		hash.Write([]byte(settingsFile))
+		hash.Write(content)
+	}
+
+	pomFiles, err := utils.Glob("**/pom.xml")
+	if err != nil {
+		return "", fmt.Errorf("failed to find POM files: %w", err)
+	}
+	pomFiles, err = piperutils.ExcludeFiles(pomFiles, []string{"**/target/**", "**/node_modules/**"})
+	if err != nil {
+		return "", err
+	}
+	sort.Strings(pomFiles)
+	envVars := map[string]bool{}
+	for _, pomFile := range pomFiles {
+		content, err := utils.FileRead(pomFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to read '%v': %w", pomFile, err)
+		}
+		hash.Write([]byte(pomFile))
+		hash.Write(content)
+		for _, match := range envActivation.FindAllSubmatch(content, -1) {
+			envVars[string(match[1])] = true
+		}
+	}
+	names := []string{}
+	for name := range envVars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value, set := os.LookupEnv(name)
+		hash.Write([]byte(fmt.Sprintf("%v=%v|%v", name, value, set)))
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
+
+func readCachedAnalysis(cacheFile string, utils Utils) (*Analysis, error) {
+	if exists, _ := utils.FileExists(cacheFile); !exists {
+		return nil, nil
+	}
+	content, err := utils.FileRead(cacheFile)
+	if err != nil {
+		return nil, err
+	}
+	analysis := &Analysis{}
+	if err := json.Unmarshal(content, analysis); err != nil {
+		return nil, err
+	}
+	return analysis, nil
+}
+
+func runAnalysis(options *AnalysisOptions, pomPath string, utils Utils) (*Analysis, error) {
+	outputDirectory, err := filepath.Abs(analysisDirectory)
+	if err != nil {
+		return nil, err
+	}
+	if err := utils.MkdirAll(outputDirectory, 0777); err != nil {
+		return nil, fmt.Errorf("failed to create directory '%v': %w", outputDirectory, err)
+	}
+	effectivePOMPath := filepath.Join(outputDirectory, effectivePOMFile)
+	dependencyTreePath := filepath.Join(outputDirectory, dependencyTreeFile)
+
+	executeOptions := ExecuteOptions{
+		PomPath:             pomPath,
+		ProjectSettingsFile: options.ProjectSettingsFile,
+		GlobalSettingsFile:  options.GlobalSettingsFile,
+		M2Path:              options.M2Path,
+		Goals:               []string{effectivePOMGoal},
+		Defines:             append([]string{"-Doutput=" + effectivePOMPath}, options.Defines...),
+	}
+	if options.NonRecursive {
+		executeOptions.Flags = []string{"--non-recursive"}
+	}
+	if options.DependencyTrees {
+		// the trees of all modules are appended to the same file, it must not contain the trees of a previous analysis
+		if err := utils.FileWrite(dependencyTreePath, []byte{}, 0666); err != nil {
+			return nil, fmt.Errorf("failed to initialize '%v': %w", dependencyTreePath, err)
+		}
+		executeOptions.Goals = append(executeOptions.Goals, dependencyTreeGoal)
+		executeOptions.Defines = append(executeOptions.Defines, "-DoutputType=json", "-DoutputFile="+dependencyTreePath, "-DappendOutput=true")
+	}
+	if _, err := Execute(&executeOptions, utils); err != nil {
+		return nil, fmt.Errorf("failed to analyze Maven project '%v': %w", pomPath, err)
+	}
+
+	content, err := utils.FileRead(effectivePOMPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read effective POM: %w", err)
+	}
+	projects, err := parseEffectivePOMs(content)
+	if err != nil {
+		return nil, err
+	}
+
+	trees := map[string]*DependencyNode{}
+	if options.DependencyTrees {
+		content, err := utils.FileRead(dependencyTreePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read dependency trees: %w", err)
+		}
+		if trees, err = parseDependencyTrees(content); err != nil {
+			return nil, err
+		}
+	}
+
+	workingDirectory, err := filepath.Abs(".")
+	if err != nil {
+		return nil, err
+	}
+	analysis := &Analysis{Root: filepath.Dir(filepath.Clean(pomPath))}
+	for _, project := range projects {
+		module := AnalyzedModule{Project: project, Path: modulePath(project, workingDirectory)}
+		module.DependencyTree = trees[project.GroupID+":"+project.ArtifactID]
+		analysis.Modules = append(analysis.Modules, module)
+	}
+	return analysis, nil
+}
+
+// parseEffectivePOMs parses the output of the effective-pom goal which contains a single project or,
+// in case of a multi-module build, all projects of the reactor
+func parseEffectivePOMs(content []byte) ([]*Project, error) {
+	reactor := struct {
+		XMLName  xml.Name   `xml:"projects"`
+		Projects []*Project `xml:"project"`
+	}{}
+	if err := xml.Unmarshal(content, &reactor); err == nil {
+		return reactor.Projects, nil
+	}
+	project, err := ParsePOM(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse effective POM: %w", err)
+	}
+	return []*Project{project}, nil
+}
+
+// parseDependencyTrees parses the JSON output of the tree goal, the trees of all modules are concatenated,
+// they are returned by the coordinates groupId:artifactId of the module
+func parseDependencyTrees(content []byte) (map[string]*DependencyNode, error) {
+	trees := map[string]*DependencyNode{}
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	for {
+		tree := &DependencyNode{}
+		err := decoder.Decode(tree)
+		if err == io.EOF {
+			return trees, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse dependency trees: %w", err)
+		}
+		trees[tree.GroupID+":"+tree.ArtifactID] = tree
+	}
+}
+
+// modulePath derives the directory of the module from its build directory which is absolute in the effective POM
+func modulePath(project *Project, workingDirectory string) string {
+	if len(project.Build.Directory) == 0 {
+		return ""
+	}
+	moduleDirectory := filepath.Dir(filepath.Clean(project.Build.Directory))
+	if relativePath, err := filepath.Rel(workingDirectory, moduleDirectory); err == nil && !strings.HasPrefix(relativePath, "..") {
+		return relativePath
+	}
+	return moduleDirectory
+}
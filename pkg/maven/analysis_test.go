@@ -0,0 +1,222 @@
+package maven
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyze(t *testing.T) {
+	build := "https://jenkins.example.org/job/app/1/"
+	defer func(identity func() string) { buildIdentity = identity }(buildIdentity)
+	buildIdentity = func() string { return build }
+
+	workingDirectory, _ := filepath.Abs(".")
+	outputDirectory := filepath.Join(workingDirectory, analysisDirectory)
+
+	effectivePOMs := fmt.Sprintf(`<?xml version="1.0" encoding="UTF-8"?>
+<!-- Effective POMs for the reactor -->
+<projects>
+  <project xmlns="http://maven.apache.org/POM/4.0.0">
+    <groupId>com.example</groupId>
+    <artifactId>parent</artifactId>
+    <version>1.0.0</version>
+    <packaging>pom</packaging>
+    <modules><module>lib</module></modules>
+    <build><directory>%v</directory></build>
+  </project>
+  <project xmlns="http://maven.apache.org/POM/4.0.0">
+    <parent><groupId>com.example</groupId><artifactId>parent</artifactId><version>1.0.0</version></parent>
+    <groupId>com.example</groupId>
+    <artifactId>lib</artifactId>
+    <version>1.0.0</version>
+    <dependencies>
+      <dependency><groupId>org.slf4j</groupId><artifactId>slf4j-api</artifactId><version>2.0.9</version></dependency>
+    </dependencies>
+    <build><directory>%v</directory><finalName>lib-1.0.0</finalName></build>
+  </project>
+</projects>`, filepath.Join(workingDirectory, "target"), filepath.Join(workingDirectory, "lib", "target"))
+
+	dependencyTrees := `{"groupId": "com.example", "artifactId": "parent", "version": "1.0.0", "type": "pom", "scope": "", "classifier": "", "optional": "false"}
+{
+  "groupId": "com.example", "artifactId": "lib", "version": "1.0.0", "type": "jar", "scope": "", "classifier": "", "optional": "false",
+  "children": [
+    {"groupId": "ch.qos.logback", "artifactId": "logback-classic", "version": "1.4.11", "type": "jar", "scope": "compile", "classifier": "", "optional": "false",
+      "children": [{"groupId": "org.slf4j", "artifactId": "slf4j-api", "version": "2.0.9", "type": "jar", "scope": "compile", "classifier": "", "optional": "false"}]},
+    {"groupId": "org.slf4j", "artifactId": "slf4j-api", "version": "2.0.9", "type": "jar", "scope": "compile", "classifier": "", "optional": "true"}
+  ]
+}`
+
+	newAnalysisUtils := func() MockUtils {
+		utils := NewMockUtils(false)
+		utils.AddFile("pom.xml", []byte("<project><modules><module>lib</module></modules></project>"))
+		utils.AddFile(filepath.Join("lib", "pom.xml"), []byte("<project><artifactId>lib</artifactId></project>"))
+		utils.AddFile(filepath.Join("lib", "target", "classes", "META-INF", "maven", "pom.xml"), []byte("<project/>"))
+		// This stub simulates the outputs of the effective-pom and tree goals
+		utils.Stub = func(call string, stdoutReturn map[string]string, shouldFailOnCommand map[string]error, stdout io.Writer) error {
+			if matches := regexp.MustCompile(`-Doutput=([^\s]+)`).FindStringSubmatch(call); len(matches) > 0 {
+				utils.AddFile(matches[1], []byte(effectivePOMs))
+			}
+			if matches := regexp.MustCompile(`-DoutputFile=([^\s]+)`).FindStringSubmatch(call); len(matches) > 0 {
+				utils.AddFile(matches[1], []byte(dependencyTrees))
+			}
+			return nil
+		}
+		return utils
+	}
+
+	t.Run("effective POMs and dependency trees", func(t *testing.T) {
+		analysisCache = map[string]*Analysis{}
+		utils := newAnalysisUtils()
+
+		analysis, err := Analyze(&AnalysisOptions{DependencyTrees: true, M2Path: ".m2", Defines: []string{"-Pci"}}, &utils)
+
+		if assert.NoError(t, err) {
+			if assert.Len(t, utils.Calls, 1) {
+				assert.Equal(t, []string{"-Dmaven.repo.local=.m2", "--file", "pom.xml",
+					"-Doutput=" + filepath.Join(outputDirectory, "effective-pom.xml"), "-Pci",
+					"-DoutputType=json", "-DoutputFile=" + filepath.Join(outputDirectory, "dependency-tree.json"), "-DappendOutput=true",
+					"-Dorg.slf4j.simpleLogger.log.org.apache.maven.cli.transfer.Slf4jMavenTransferListener=warn", "--batch-mode",
+					"org.apache.maven.plugins:maven-help-plugin:3.4.0:effective-pom",
+					"org.apache.maven.plugins:maven-dependency-plugin:3.7.0:tree"}, utils.Calls[0].Params)
+			}
+			if assert.Len(t, analysis.Modules, 2) {
+				root, err := analysis.RootModule()
+				assert.NoError(t, err)
+				assert.Equal(t, "parent", root.Project.ArtifactID)
+				assert.Equal(t, "pom", root.Project.Packaging)
+
+				lib, found := analysis.Module("com.example", "lib")
+				if assert.True(t, found) {
+					assert.Equal(t, "lib", lib.Path)
+					assert.Equal(t, "lib-1.0.0", lib.Project.Build.FinalName)
+					assert.Len(t, lib.Project.Dependencies, 1)
+					if assert.NotNil(t, lib.DependencyTree) {
+						dependencies := lib.DependencyTree.TransitiveDependencies()
+						assert.Equal(t, []string{"ch.qos.logback:logback-classic:jar:1.4.11", "org.slf4j:slf4j-api:jar:2.0.9"},
+							[]string{dependencies[0].Coordinate(), dependencies[1].Coordinate()})
+						assert.True(t, lib.DependencyTree.Children[1].Optional)
+					}
+				}
+			}
+		}
+	})
+
+	t.Run("cached analysis", func(t *testing.T) {
+		analysisCache = map[string]*Analysis{}
+		utils := newAnalysisUtils()
+
+		first, err := Analyze(&AnalysisOptions{DependencyTrees: true}, &utils)
+		assert.NoError(t, err)
+
+		// another step of the same build uses the analysis cached in the workspace
+		analysisCache = map[string]*Analysis{}
+		second, err := Analyze(&AnalysisOptions{DependencyTrees: true}, &utils)
+
+		if assert.NoError(t, err) {
+			assert.Len(t, utils.Calls, 1)
+			assert.Equal(t, first.Modules[1].DependencyTree, second.Modules[1].DependencyTree)
+			assert.Equal(t, first.Modules[1].Project.ArtifactID, second.Modules[1].Project.ArtifactID)
+		}
+
+		// a changed POM invalidates the cache
+		utils.AddFile(filepath.Join("lib", "pom.xml"), []byte("<project><artifactId>lib</artifactId><version>2.0.0</version></project>"))
+		_, err = Analyze(&AnalysisOptions{DependencyTrees: true}, &utils)
+
+		if assert.NoError(t, err) {
+			assert.Len(t, utils.Calls, 2)
+		}
+
+		// changed settings invalidate the cache
+		utils.AddFile("settings.xml", []byte("<settings/>"))
+		_, err = Analyze(&AnalysisOptions{DependencyTrees: true, ProjectSettingsFile: "settings.xml"}, &utils)
+		assert.NoError(t, err)
+		analysisCache = map[string]*Analysis{}
+		utils.AddFile("settings.xml", []byte("<settings><mirrors/></settings>"))
+		_, err = Analyze(&AnalysisOptions{DependencyTrees: true, ProjectSettingsFile: "settings.xml"}, &utils)
+
+		if assert.NoError(t, err) {
+			assert.Len(t, utils.Calls, 4)
+		}
+	})
+
+	t.Run("analysis of a previous run", func(t *testing.T) {
+		analysisCache = map[string]*Analysis{}
+		utils := newAnalysisUtils()
+
+		_, err := Analyze(&AnalysisOptions{}, &utils)
+		assert.NoError(t, err)
+
+		analysisCache = map[string]*Analysis{}
+		build = "https://jenkins.example.org/job/app/2/"
+		defer func() { build = "https://jenkins.example.org/job/app/1/" }()
+		_, err = Analyze(&AnalysisOptions{}, &utils)
+
+		if assert.NoError(t, err) {
+			assert.Len(t, utils.Calls, 2)
+		}
+	})
+
+	t.Run("unknown run", func(t *testing.T) {
+		analysisCache = map[string]*Analysis{}
+		build = "n/a"
+		defer func() { build = "https://jenkins.example.org/job/app/1/" }()
+		utils := newAnalysisUtils()
+
+		_, err := Analyze(&AnalysisOptions{}, &utils)
+
+		if assert.NoError(t, err) {
+			files, _ := utils.Glob(filepath.Join(analysisDirectory, "*.json"))
+			assert.Empty(t, files)
+		}
+	})
+
+	t.Run("profile activated by environment", func(t *testing.T) {
+		analysisCache = map[string]*Analysis{}
+		utils := newAnalysisUtils()
+		utils.AddFile("pom.xml", []byte("<project><profiles><profile><activation><property><name>env.PIPER_TEST_PROFILE</name></property></activation></profile></profiles></project>"))
+
+		t.Setenv("PIPER_TEST_PROFILE", "a")
+		_, err := Analyze(&AnalysisOptions{}, &utils)
+		assert.NoError(t, err)
+		t.Setenv("PIPER_TEST_PROFILE", "b")
+		_, err = Analyze(&AnalysisOptions{}, &utils)
+
+		if assert.NoError(t, err) {
+			assert.Len(t, utils.Calls, 2)
+		}
+	})
+
+	t.Run("single project", func(t *testing.T) {
+		analysisCache = map[string]*Analysis{}
+		utils := newAnalysisUtils()
+		effectivePOMs = `<project><groupId>com.example</groupId><artifactId>parent</artifactId><version>1.0.0</version></project>`
+
+		analysis, err := Analyze(&AnalysisOptions{PomPath: "pom.xml", NonRecursive: true}, &utils)
+
+		if assert.NoError(t, err) && assert.Len(t, utils.Calls, 1) {
+			assert.Contains(t, utils.Calls[0].Params, "--non-recursive")
+			assert.NotContains(t, utils.Calls[0].Params, "org.apache.maven.plugins:maven-dependency-plugin:3.7.0:tree")
+			root, err := analysis.RootModule()
+			assert.NoError(t, err)
+			assert.Equal(t, "parent", root.Project.ArtifactID)
+			assert.Nil(t, root.DependencyTree)
+		}
+	})
+
+	t.Run("error - maven fails", func(t *testing.T) {
+		analysisCache = map[string]*Analysis{}
+		utils := newAnalysisUtils()
+		utils.Stub = func(call string, stdoutReturn map[string]string, shouldFailOnCommand map[string]error, stdout io.Writer) error {
+			return fmt.Errorf("build failure")
+		}
+
+		_, err := Analyze(&AnalysisOptions{}, &utils)
+
+		assert.ErrorContains(t, err, "failed to analyze Maven project 'pom.xml': failed to run executable")
+	})
+}
@@ -182,19 +182,43 @@ func doInstallMavenArtifacts(options *EvaluateOptions, utils Utils) error {
 		}
 	}
 
+	// A single analysis provides the packaging and final name of all modules of the build,
+	// only POM files which are not part of the build are evaluated separately.
+	analyzedProjects := map[string]*Project{}
+	analysis, err := Analyze(&AnalysisOptions{
+		PomPath:             options.PomPath,
+		ProjectSettingsFile: options.ProjectSettingsFile,
+		GlobalSettingsFile:  options.GlobalSettingsFile,
+		M2Path:              options.M2Path,
+		Defines:             options.Defines,
+	}, utils)
+	if err != nil {
+		log.Entry().WithError(err).Warn("failed to analyze the Maven project, evaluating the modules separately")
+	} else {
+		for _, module := range analysis.Modules {
+			analyzedProjects[filepath.Clean(module.Path)] = module.Project
+		}
+	}
+
 	for _, pomFile := range pomFiles {
 		log.Entry().Info("Installing maven artifacts from module: " + pomFile)
 
-		// Set this module's pom file as the pom file for evaluating the packaging,
-		// otherwise we would evaluate the root pom in all iterations.
-		evaluateProjectPackagingOptions := *options
-		evaluateProjectPackagingOptions.PomPath = pomFile
-		packaging, err := Evaluate(&evaluateProjectPackagingOptions, "project.packaging", utils)
-		if err != nil {
-			return err
-		}
-
 		currentModuleDir := filepath.Dir(pomFile)
+		project, analyzed := analyzedProjects[filepath.Clean(currentModuleDir)]
+
+		packaging := ""
+		if analyzed {
+			packaging = project.Packaging
+		} else {
+			// Set this module's pom file as the pom file for evaluating the packaging,
+			// otherwise we would evaluate the root pom in all iterations.
+			evaluateProjectPackagingOptions := *options
+			evaluateProjectPackagingOptions.PomPath = pomFile
+			packaging, err = Evaluate(&evaluateProjectPackagingOptions, "project.packaging", utils)
+			if err != nil {
+				return err
+			}
+		}
 
 		// Use flat pom if available to avoid issues with unresolved variables.
 		pathToPomFile := pomFile
@@ -209,25 +233,26 @@ func doInstallMavenArtifacts(options *EvaluateOptions, utils Utils) error {
 				return err
 			}
 		} else {
-
-			err = installJarWarArtifacts(pathToPomFile, currentModuleDir, options, utils)
+			options.PomPath = filepath.Join(currentModuleDir, "pom.xml")
+			finalName := ""
+			if analyzed {
+				finalName = project.Build.FinalName
+			} else if finalName, err = Evaluate(options, "project.build.finalName", utils); err != nil {
+				return err
+			}
+			err = installJarWarArtifacts(pathToPomFile, currentModuleDir, finalName, options, utils)
 			if err != nil {
 				return err
 			}
 		}
 	}
-	return err
+	return nil
 }
 
-func installJarWarArtifacts(pomFile, dir string, options *EvaluateOptions, utils Utils) error {
-	options.PomPath = filepath.Join(dir, "pom.xml")
-	finalName, err := Evaluate(options, "project.build.finalName", utils)
-	if err != nil {
-		return err
-	}
+func installJarWarArtifacts(pomFile, dir, finalName string, options *EvaluateOptions, utils Utils) error {
 	if finalName == "" {
 		log.Entry().Warn("project.build.finalName is empty, skipping install of artifact. Installing only the pom file.")
-		err = InstallFile("", pomFile, options, utils)
+		err := InstallFile("", pomFile, options, utils)
 		if err != nil {
 			return err
 		}
@@ -246,26 +271,26 @@ func installJarWarArtifacts(pomFile, dir string, options *EvaluateOptions, utils
 
 	// Due to spring's jar repackaging we need to check for an "original" jar file because the repackaged one is no suitable source for dependent maven modules
 	if originalJarExists {
-		err = InstallFile(originalJarFile(dir, finalName), pomFile, options, utils)
+		err := InstallFile(originalJarFile(dir, finalName), pomFile, options, utils)
 		if err != nil {
 			return err
 		}
 	} else if jarExists {
-		err = InstallFile(jarFile(dir, finalName), pomFile, options, utils)
+		err := InstallFile(jarFile(dir, finalName), pomFile, options, utils)
 		if err != nil {
 			return err
 		}
 	}
 
 	if warExists {
-		err = InstallFile(warFile(dir, finalName), pomFile, options, utils)
+		err := InstallFile(warFile(dir, finalName), pomFile, options, utils)
 		if err != nil {
 			return err
 		}
 	}
 
 	if classesJarExists {
-		err = InstallFile(classesJarFile(dir, finalName), pomFile, options, utils)
+		err := InstallFile(classesJarFile(dir, finalName), pomFile, options, utils)
 		if err != nil {
 			return err
 		}
@@ -18,6 +18,13 @@ type Project struct {
 	Name         string       `xml:"name"`
 	Dependencies []Dependency `xml:"dependencies>dependency"`
 	Modules      []string     `xml:"modules>module"`
+	Build        Build        `xml:"build"`
+}
+
+// Build describes the build settings of a module.
+type Build struct {
+	Directory string `xml:"directory"`
+	FinalName string `xml:"finalName"`
 }
 
 // Parent describes the coordinates a module's parent POM.
@@ -0,0 +1,214 @@
+package monorepo
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/SAP/jenkins-library/pkg/piperenv"
+	"github.com/ghodss/yaml"
+)
+
+// defaultVersionSources are the build descriptors containing the version of a project in case no version source is defined
+var defaultVersionSources = map[string]string{
+	"cargo":  "Cargo.toml",
+	"gradle": "gradle.properties",
+	"helm":   "Chart.yaml",
+	"maven":  "pom.xml",
+	"mta":    "mta.yaml",
+	"npm":    "package.json",
+	"yarn":   "package.json",
+}
+
+// Project describes an independently versioned and built unit of a monorepo.
+type Project struct {
+	Name string `json:"name"`
+	// Path is the directory of the project relative to the repository root
+	Path      string `json:"path"`
+	BuildTool string `json:"buildTool"`
+	// VersionSource is the file containing the version relative to the project directory, e.g. pom.xml
+	VersionSource string `json:"versionSource,omitempty"`
+	// Dependencies contains the names of the projects of the workspace this project depends on
+	Dependencies []string `json:"dependencies,omitempty"`
+	// TagPrefix is used for the release tags of the project, defaults to "<name>/"
+	TagPrefix string `json:"tagPrefix,omitempty"`
+}
+
+// Workspace describes the projects of a monorepo.
+type Workspace struct {
+	Projects []Project `json:"projects"`
+}
+
+// Utils provides the file system access required for reading the workspace descriptor.
+type Utils interface {
+	FileRead(path string) ([]byte, error)
+}
+
+// Load reads and validates the workspace descriptor. The projects of the returned workspace are sorted
+// in dependency order, i.e. each project is listed after the projects it depends on.
+func Load(descriptor string, utils Utils) (*Workspace, error) {
+	content, err := utils.FileRead(descriptor)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workspace descriptor '%v': %w", descriptor, err)
+	}
+	workspace := &Workspace{}
+	if err := yaml.Unmarshal(content, workspace); err != nil {
+		return nil, fmt.Errorf("failed to parse workspace descriptor '%v': %w", descriptor, err)
+	}
+	if len(workspace.Projects) == 0 {
+		return nil, fmt.Errorf("no projects defined in workspace descriptor '%v'", descriptor)
+	}
+
+	names := map[string]bool{}
+	for i := range workspace.Projects {
+		project := &workspace.Projects[i]
+		if len(project.Name) == 0 || len(project.Path) == 0 || len(project.BuildTool) == 0 {
+			return nil, fmt.Errorf("project #%v of workspace descriptor '%v' requires a name, a path and a buildTool", i+1, descriptor)
+		}
+		if names[project.Name] {
+			return nil, fmt.Errorf("project '%v' is defined multiple times", project.Name)
+		}
+		names[project.Name] = true
+		project.Path = cleanPath(project.Path)
+		if len(project.VersionSource) == 0 {
+			project.VersionSource = defaultVersionSources[project.BuildTool]
+		}
+		if len(project.TagPrefix) == 0 {
+			project.TagPrefix = project.Name + "/"
+		}
+	}
+	for _, project := range workspace.Projects {
+		for _, dependency := range project.Dependencies {
+			if !names[dependency] {
+				return nil, fmt.Errorf("project '%v' depends on unknown project '%v'", project.Name, dependency)
+			}
+		}
+	}
+
+	workspace.Projects, err = sortByDependencies(workspace.Projects)
+	if err != nil {
+		return nil, err
+	}
+	return workspace, nil
+}
+
+// sortByDependencies orders the projects topologically, projects without dependencies between each other keep their order
+func sortByDependencies(projects []Project) ([]Project, error) {
+	sorted := []Project{}
+	done := map[string]bool{}
+	for len(sorted) < len(projects) {
+		progress := false
+		for _, project := range projects {
+			if done[project.Name] || !allDone(project.Dependencies, done) {
+				continue
+			}
+			sorted = append(sorted, project)
+			done[project.Name] = true
+			progress = true
+		}
+		if !progress {
+			cycle := []string{}
+			for _, project := range projects {
+				if !done[project.Name] {
+					cycle = append(cycle, project.Name)
+				}
+			}
+			return nil, fmt.Errorf("cyclic dependencies between projects %v", cycle)
+		}
+	}
+	return sorted, nil
+}
+
+func allDone(names []string, done map[string]bool) bool {
+	for _, name := range names {
+		if !done[name] {
+			return false
+		}
+	}
+	return true
+}
+
+// Descriptor returns the path of the file containing the version of the project relative to the repository root.
+// It is empty in case the build tool has no default version source and none is defined.
+func (p Project) Descriptor() string {
+	if len(p.VersionSource) == 0 {
+		return ""
+	}
+	return path.Join(p.Path, p.VersionSource)
+}
+
+// Contains reports whether the file is located in the project directory
+func (p Project) Contains(file string) bool {
+	return p.Path == "." || file == p.Path || strings.HasPrefix(file, p.Path+"/")
+}
+
+// Affected returns the names of the projects which contain one of the files and of all projects depending on them.
+// The files of each project are the files changed since its last release.
+func (w *Workspace) Affected(changedFiles map[string][]string) map[string]bool {
+	affected := map[string]bool{}
+	for _, project := range w.Projects {
+		for _, file := range changedFiles[project.Name] {
+			if project.Contains(file) {
+				affected[project.Name] = true
+				break
+			}
+		}
+	}
+	// projects are sorted in dependency order, thus a single pass propagates the changes to all dependent projects
+	for _, project := range w.Projects {
+		for _, dependency := range project.Dependencies {
+			if affected[dependency] {
+				affected[project.Name] = true
+			}
+		}
+	}
+	return affected
+}
+
+// LatestTag returns the tag with the highest version among the tags with the given prefix together with its version.
+// Tags which do not contain a version after the prefix are ignored.
+func LatestTag(tags []string, prefix string) (string, string) {
+	var latest *semver.Version
+	latestTag := ""
+	for _, tag := range tags {
+		if !strings.HasPrefix(tag, prefix) {
+			continue
+		}
+		version, err := semver.NewVersion(strings.TrimPrefix(tag, prefix))
+		if err != nil {
+			continue
+		}
+		if latest == nil || version.GreaterThan(latest) {
+			latest = version
+			latestTag = tag
+		}
+	}
+	if latest == nil {
+		return "", ""
+	}
+	return latestTag, latest.Original()
+}
+
+// ChangedProjects returns the changed projects of the common pipeline environment which use one of the build tools
+func ChangedProjects(projects []map[string]interface{}, buildTools ...string) (piperenv.Projects, error) {
+	content, err := json.Marshal(projects)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal workspace projects: %w", err)
+	}
+	result := piperenv.Projects{}
+	if err := json.Unmarshal(content, &result); err != nil {
+		return nil, fmt.Errorf("failed to read workspace projects: %w", err)
+	}
+	return result.FindChanged(buildTools...), nil
+}
+
+func cleanPath(dir string) string {
+	dir = strings.TrimPrefix(path.Clean("/"+filepath.ToSlash(dir)), "/")
+	if len(dir) == 0 {
+		return "."
+	}
+	return dir
+}
@@ -0,0 +1,138 @@
+package monorepo
+
+import (
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/SAP/jenkins-library/pkg/piperenv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const workspaceDescriptor = `projects:
+  - name: web
+    path: ui/web/
+    buildTool: npm
+    dependencies: [api]
+  - name: orders
+    path: services/orders
+    buildTool: maven
+    dependencies: [common]
+    tagPrefix: orders-v
+  - name: common
+    path: ./libs/common
+    buildTool: maven
+  - name: api
+    path: services/api
+    buildTool: golang
+    versionSource: VERSION
+    dependencies: [orders, common]
+`
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success case", func(t *testing.T) {
+		utils := &mock.FilesMock{}
+		utils.AddFile("workspace.yaml", []byte(workspaceDescriptor))
+
+		workspace, err := Load("workspace.yaml", utils)
+
+		require.NoError(t, err)
+		assert.Equal(t, []Project{
+			{Name: "common", Path: "libs/common", BuildTool: "maven", VersionSource: "pom.xml", TagPrefix: "common/"},
+			{Name: "orders", Path: "services/orders", BuildTool: "maven", VersionSource: "pom.xml", Dependencies: []string{"common"}, TagPrefix: "orders-v"},
+			{Name: "api", Path: "services/api", BuildTool: "golang", VersionSource: "VERSION", Dependencies: []string{"orders", "common"}, TagPrefix: "api/"},
+			{Name: "web", Path: "ui/web", BuildTool: "npm", VersionSource: "package.json", Dependencies: []string{"api"}, TagPrefix: "web/"},
+		}, workspace.Projects)
+		assert.Equal(t, "services/api/VERSION", workspace.Projects[2].Descriptor())
+	})
+
+	t.Run("error case - missing file", func(t *testing.T) {
+		_, err := Load("workspace.yaml", &mock.FilesMock{})
+		assert.Contains(t, err.Error(), "failed to read workspace descriptor 'workspace.yaml'")
+	})
+
+	t.Run("error case - no projects", func(t *testing.T) {
+		utils := &mock.FilesMock{}
+		utils.AddFile("workspace.yaml", []byte("projects: []\n"))
+		_, err := Load("workspace.yaml", utils)
+		assert.EqualError(t, err, "no projects defined in workspace descriptor 'workspace.yaml'")
+	})
+
+	t.Run("error case - incomplete project", func(t *testing.T) {
+		utils := &mock.FilesMock{}
+		utils.AddFile("workspace.yaml", []byte("projects:\n  - name: web\n    path: web\n"))
+		_, err := Load("workspace.yaml", utils)
+		assert.EqualError(t, err, "project #1 of workspace descriptor 'workspace.yaml' requires a name, a path and a buildTool")
+	})
+
+	t.Run("error case - duplicate project", func(t *testing.T) {
+		utils := &mock.FilesMock{}
+		utils.AddFile("workspace.yaml", []byte("projects:\n  - {name: web, path: a, buildTool: npm}\n  - {name: web, path: b, buildTool: npm}\n"))
+		_, err := Load("workspace.yaml", utils)
+		assert.EqualError(t, err, "project 'web' is defined multiple times")
+	})
+
+	t.Run("error case - unknown dependency", func(t *testing.T) {
+		utils := &mock.FilesMock{}
+		utils.AddFile("workspace.yaml", []byte("projects:\n  - {name: web, path: web, buildTool: npm, dependencies: [api]}\n"))
+		_, err := Load("workspace.yaml", utils)
+		assert.EqualError(t, err, "project 'web' depends on unknown project 'api'")
+	})
+
+	t.Run("error case - cyclic dependencies", func(t *testing.T) {
+		utils := &mock.FilesMock{}
+		utils.AddFile("workspace.yaml", []byte(`projects:
+  - {name: lib, path: lib, buildTool: maven}
+  - {name: a, path: a, buildTool: maven, dependencies: [b, lib]}
+  - {name: b, path: b, buildTool: maven, dependencies: [a]}
+`))
+		_, err := Load("workspace.yaml", utils)
+		assert.EqualError(t, err, "cyclic dependencies between projects [a b]")
+	})
+}
+
+func TestAffected(t *testing.T) {
+	utils := &mock.FilesMock{}
+	utils.AddFile("workspace.yaml", []byte(workspaceDescriptor))
+	workspace, err := Load("workspace.yaml", utils)
+	require.NoError(t, err)
+
+	t.Run("dependent projects are affected", func(t *testing.T) {
+		affected := workspace.Affected(map[string][]string{
+			"common": {"README.md"},
+			"orders": {"services/orders/src/main/java/Order.java"},
+			"api":    {"services/orders/src/main/java/Order.java"},
+		})
+		assert.Equal(t, map[string]bool{"orders": true, "api": true, "web": true}, affected)
+	})
+
+	t.Run("nothing changed", func(t *testing.T) {
+		affected := workspace.Affected(map[string][]string{"web": {"ui/web-legacy/package.json"}})
+		assert.Empty(t, affected)
+	})
+}
+
+func TestLatestTag(t *testing.T) {
+	tags := []string{"orders/1.0.0", "orders/1.10.0-20221001120000+abc", "orders/1.9.0", "orders/latest", "web/2.0.0"}
+
+	tag, version := LatestTag(tags, "orders/")
+	assert.Equal(t, "orders/1.10.0-20221001120000+abc", tag)
+	assert.Equal(t, "1.10.0-20221001120000+abc", version)
+
+	tag, version = LatestTag(tags, "api/")
+	assert.Empty(t, tag)
+	assert.Empty(t, version)
+}
+
+func TestChangedProjects(t *testing.T) {
+	projects, err := ChangedProjects([]map[string]interface{}{
+		{"name": "common", "path": "libs/common", "buildTool": "maven", "version": "1.0.1", "changed": true},
+		{"name": "orders", "path": "services/orders", "buildTool": "maven", "version": "2.0.0", "changed": false},
+		{"name": "web", "path": "ui/web", "buildTool": "npm", "version": "3.1.0", "changed": true},
+	}, "maven")
+
+	assert.NoError(t, err)
+	assert.Equal(t, piperenv.Projects{{Name: "common", Path: "libs/common", BuildTool: "maven", Version: "1.0.1", Changed: true}}, projects)
+}
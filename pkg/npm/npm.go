@@ -21,6 +21,8 @@ const (
 type Execute struct {
 	Utils   Utils
 	Options ExecutorOptions
+	// workspaces caches the workspaces by the directory of their root, nil denotes a directory without workspace root
+	workspaces map[string]*workspace
 }
 
 // Executor interface to enable mocking for testing
@@ -84,27 +86,41 @@ func (u *utilsBundle) GetExecRunner() ExecRunner {
 }
 
 // SetNpmRegistries configures the given npm registries.
-// CAUTION: This will change the npm configuration in the user's home directory.
+// Yarn 2 and later does not read the npm configuration, its registry is configured in the Yarn configuration instead.
+// CAUTION: This will change the npm or Yarn configuration in the user's home directory.
 func (exec *Execute) SetNpmRegistries() error {
+	manager, err := exec.detectPackageManager(".")
+	if err != nil {
+		return err
+	}
+	return exec.setRegistries(manager)
+}
+
+func (exec *Execute) setRegistries(manager packageManager) error {
 	execRunner := exec.Utils.GetExecRunner()
-	const npmRegistry = "registry"
+	executable, npmRegistry, defaultRegistry := "npm", "registry", "https://registry.npmjs.org"
+	setParams := []string{"config", "set", npmRegistry}
+	if manager == yarnBerryManager {
+		executable, npmRegistry, defaultRegistry = "yarn", "npmRegistryServer", "https://registry.yarnpkg.com"
+		setParams = []string{"config", "set", "-H", npmRegistry}
+	}
 
 	var buffer bytes.Buffer
 	execRunner.Stdout(&buffer)
-	err := execRunner.RunExecutable("npm", "config", "get", npmRegistry)
+	err := execRunner.RunExecutable(executable, "config", "get", npmRegistry)
 	execRunner.Stdout(log.Writer())
 	if err != nil {
 		return err
 	}
-	preConfiguredRegistry := buffer.String()
+	preConfiguredRegistry := strings.TrimSpace(buffer.String())
 
 	if registryIsNonEmpty(preConfiguredRegistry) {
 		log.Entry().Info("Discovered pre-configured npm registry " + npmRegistry + " with value " + preConfiguredRegistry)
 	}
 
-	if exec.Options.DefaultNpmRegistry != "" && registryRequiresConfiguration(preConfiguredRegistry, "https://registry.npmjs.org") {
+	if exec.Options.DefaultNpmRegistry != "" && registryRequiresConfiguration(preConfiguredRegistry, defaultRegistry) {
 		log.Entry().Info("npm registry " + npmRegistry + " was not configured, setting it to " + exec.Options.DefaultNpmRegistry)
-		err = execRunner.RunExecutable("npm", "config", "set", npmRegistry, exec.Options.DefaultNpmRegistry)
+		err = execRunner.RunExecutable(executable, append(setParams, exec.Options.DefaultNpmRegistry)...)
 		if err != nil {
 			return err
 		}
@@ -146,6 +162,12 @@ func (exec *Execute) RunScriptsInAllPackages(runScripts []string, runOptions []s
 		execRunner.SetEnv([]string{"DISPLAY=:99"})
 	}
 
+	// packages of a workspace are processed after the packages they depend on
+	packageJSONFiles, err = exec.sortByWorkspaces(packageJSONFiles)
+	if err != nil {
+		return err
+	}
+
 	for _, script := range runScripts {
 		packagesWithScript, err := exec.FindPackageJSONFilesWithScript(packageJSONFiles, script)
 		if err != nil {
@@ -169,6 +191,11 @@ func (exec *Execute) RunScriptsInAllPackages(runScripts []string, runOptions []s
 
 func (exec *Execute) executeScript(packageJSON string, script string, runOptions []string, scriptOptions []string) error {
 	execRunner := exec.Utils.GetExecRunner()
+	manager, err := exec.packageManagerOf(packageJSON)
+	if err != nil {
+		return err
+	}
+
 	oldWorkingDirectory, err := exec.Utils.Getwd()
 	if err != nil {
 		return fmt.Errorf("failed to get current working directory before executing npm scripts: %w", err)
@@ -181,24 +208,33 @@ func (exec *Execute) executeScript(packageJSON string, script string, runOptions
 	}
 
 	// set in each directory to respect existing config in rc fileUtils
-	err = exec.SetNpmRegistries()
+	err = exec.setRegistries(manager)
 	if err != nil {
 		return err
 	}
 
 	log.Entry().WithField("WorkingDirectory", dir).Info("run-script " + script)
 
+	executable := "npm"
 	npmRunArgs := []string{"run", script}
-	if len(runOptions) > 0 {
-		npmRunArgs = append(npmRunArgs, runOptions...)
-	}
-
-	if len(scriptOptions) > 0 {
-		npmRunArgs = append(npmRunArgs, "--")
+	if manager == pnpmManager || manager == yarnBerryManager {
+		// pnpm and Yarn 2 and later resolve the binaries of the workspace themselves, e.g. from the Plug'n'Play map of Yarn,
+		// and pass all parameters following the script name to the script
+		executable = manager.executable()
+		npmRunArgs = append(append([]string{"run"}, runOptions...), script)
 		npmRunArgs = append(npmRunArgs, scriptOptions...)
+	} else {
+		if len(runOptions) > 0 {
+			npmRunArgs = append(npmRunArgs, runOptions...)
+		}
+
+		if len(scriptOptions) > 0 {
+			npmRunArgs = append(npmRunArgs, "--")
+			npmRunArgs = append(npmRunArgs, scriptOptions...)
+		}
 	}
 
-	err = execRunner.RunExecutable("npm", npmRunArgs...)
+	err = execRunner.RunExecutable(executable, npmRunArgs...)
 	if err != nil {
 		return fmt.Errorf("failed to run npm script %s: %w", script, err)
 	}
@@ -264,8 +300,10 @@ func (exec *Execute) FindPackageJSONFilesWithScript(packageJSONFiles []string, s
 	return packagesWithScript, nil
 }
 
-// InstallAllDependencies executes npm or yarn Install for all package.json fileUtils defined in packageJSONFiles
+// InstallAllDependencies executes npm, yarn or pnpm Install for all package.json fileUtils defined in packageJSONFiles.
+// The members of a workspace are installed once together with the workspace root.
 func (exec *Execute) InstallAllDependencies(packageJSONFiles []string) error {
+	installed := map[string]bool{}
 	for _, packageJSON := range packageJSONFiles {
 		fileExists, err := exec.Utils.FileExists(packageJSON)
 		if err != nil {
@@ -275,6 +313,18 @@ func (exec *Execute) InstallAllDependencies(packageJSONFiles []string) error {
 			return fmt.Errorf("package.json file '%s' not found: %w", packageJSON, err)
 		}
 
+		ws, err := exec.findWorkspace(packageJSON)
+		if err != nil {
+			return err
+		}
+		if ws != nil {
+			packageJSON = ws.rootPackageJSON()
+		}
+		if installed[filepath.Clean(packageJSON)] {
+			continue
+		}
+		installed[filepath.Clean(packageJSON)] = true
+
 		err = exec.install(packageJSON)
 		if err != nil {
 			return err
@@ -283,7 +333,7 @@ func (exec *Execute) InstallAllDependencies(packageJSONFiles []string) error {
 	return nil
 }
 
-// install executes npm or yarn Install for package.json
+// install executes npm, yarn or pnpm Install for package.json
 func (exec *Execute) install(packageJSON string) error {
 	execRunner := exec.Utils.GetExecRunner()
 
@@ -298,81 +348,90 @@ func (exec *Execute) install(packageJSON string) error {
 		return fmt.Errorf("failed to change into directory for executing script: %w", err)
 	}
 
-	err = exec.SetNpmRegistries()
+	manager, err := exec.detectPackageManager(".")
 	if err != nil {
 		return err
 	}
 
-	packageLockExists, yarnLockExists, err := exec.checkIfLockFilesExist()
+	err = exec.setRegistries(manager)
 	if err != nil {
 		return err
 	}
 
+	lockFileExists, err := exec.Utils.FileExists(manager.lockFile())
+	if err != nil {
+		return err
+	}
+	if !lockFileExists {
+		log.Entry().Warnf("No package lock file found. "+
+			"It is recommended to create a `%s` file by running `%s install` locally."+
+			" Add this file to your version control. "+
+			"By doing so, the builds of your application become more reliable.", manager.lockFile(), manager.executable())
+	}
+
 	log.Entry().WithField("WorkingDirectory", dir).Info("Running Install")
-	if packageLockExists {
-		err = execRunner.RunExecutable("npm", "ci")
+	err = execRunner.RunExecutable(manager.executable(), manager.installArgs(lockFileExists)...)
+	if err != nil {
+		return err
+	}
+
+	err = exec.Utils.Chdir(oldWorkingDirectory)
+	if err != nil {
+		return fmt.Errorf("failed to change back into original directory: %w", err)
+	}
+	return nil
+}
+
+// CreateBOM generates BOM file using CycloneDX from all package.json files
+func (exec *Execute) CreateBOM(packageJSONFiles []string) error {
+	execRunner := exec.Utils.GetExecRunner()
+	cycloneDXInstalled := false
+
+	for _, packageJSONFile := range packageJSONFiles {
+		manager, err := exec.packageManagerOf(packageJSONFile)
 		if err != nil {
 			return err
 		}
-	} else if yarnLockExists {
-		err = execRunner.RunExecutable("yarn", "install", "--frozen-lockfile")
-		if err != nil {
-			return err
+		path := filepath.Dir(packageJSONFile)
+
+		switch manager {
+		case pnpmManager:
+			// installing CycloneDX with npm would modify the node_modules directory managed by pnpm
+			err = execRunner.RunExecutable("pnpm", "--package=@cyclonedx/bom@^3.10.6", "dlx", "cyclonedx-bom", path, "--output", filepath.Join(path, npmBomFilename))
+		case yarnBerryManager:
+			// Yarn 2 and later does not necessarily create a node_modules directory, the BOM is created by the Yarn plugin of CycloneDX
+			err = exec.createYarnBerryBOM(path)
+		default:
+			if !cycloneDXInstalled {
+				// Install CycloneDX Node.js module locally without saving in package.json
+				if err := execRunner.RunExecutable("npm", "install", "@cyclonedx/bom@^3.10.6", "--no-save"); err != nil {
+					return err
+				}
+				cycloneDXInstalled = true
+			}
+			err = execRunner.RunExecutable("npx", "cyclonedx-bom", path, "--output", filepath.Join(path, npmBomFilename))
 		}
-	} else {
-		log.Entry().Warn("No package lock file found. " +
-			"It is recommended to create a `package-lock.json` file by running `npm Install` locally." +
-			" Add this file to your version control. " +
-			"By doing so, the builds of your application become more reliable.")
-		err = execRunner.RunExecutable("npm", "install")
 		if err != nil {
 			return err
 		}
 	}
-
-	err = exec.Utils.Chdir(oldWorkingDirectory)
-	if err != nil {
-		return fmt.Errorf("failed to change back into original directory: %w", err)
-	}
 	return nil
 }
 
-// checkIfLockFilesExist checks if yarn/package lock fileUtils exist
-func (exec *Execute) checkIfLockFilesExist() (bool, bool, error) {
-	packageLockExists, err := exec.Utils.FileExists("package-lock.json")
+func (exec *Execute) createYarnBerryBOM(dir string) error {
+	oldWorkingDirectory, err := exec.Utils.Getwd()
 	if err != nil {
-		return false, false, err
+		return fmt.Errorf("failed to get current working directory before creating BOM: %w", err)
 	}
-
-	yarnLockExists, err := exec.Utils.FileExists("yarn.lock")
-	if err != nil {
-		return false, false, err
+	if err := exec.Utils.Chdir(dir); err != nil {
+		return fmt.Errorf("failed to change into directory for creating BOM: %w", err)
 	}
-	return packageLockExists, yarnLockExists, nil
-}
-
-// CreateBOM generates BOM file using CycloneDX from all package.json files
-func (exec *Execute) CreateBOM(packageJSONFiles []string) error {
-	execRunner := exec.Utils.GetExecRunner()
-	// Install CycloneDX Node.js module locally without saving in package.json
-	err := execRunner.RunExecutable("npm", "install", "@cyclonedx/bom@^3.10.6", "--no-save")
+	err = exec.Utils.GetExecRunner().RunExecutable("yarn", "dlx", "-q", "@cyclonedx/yarn-plugin-cyclonedx", "--output-format", "XML", "--output-file", npmBomFilename)
 	if err != nil {
 		return err
 	}
-
-	if len(packageJSONFiles) > 0 {
-		for _, packageJSONFile := range packageJSONFiles {
-			path := filepath.Dir(packageJSONFile)
-			params := []string{
-				"cyclonedx-bom",
-				path,
-				"--output", filepath.Join(path, npmBomFilename),
-			}
-			err := execRunner.RunExecutable("npx", params...)
-			if err != nil {
-				return err
-			}
-		}
+	if err := exec.Utils.Chdir(oldWorkingDirectory); err != nil {
+		return fmt.Errorf("failed to change back into original directory: %w", err)
 	}
 	return nil
 }
@@ -1,7 +1,9 @@
 package npm
 
 import (
+	"io"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/SAP/jenkins-library/pkg/mock"
@@ -191,7 +193,7 @@ func TestNpm(t *testing.T) {
 		}
 	})
 
-	t.Run("check if yarn.lock and package-lock exist", func(t *testing.T) {
+	t.Run("check that package-lock takes precedence over yarn.lock", func(t *testing.T) {
 		utils := newNpmMockUtilsBundle()
 		utils.AddFile("package.json", []byte("{\"scripts\": { \"ci-lint\": \"exit 0\" } }"))
 		utils.AddFile("yarn.lock", []byte("{}"))
@@ -203,15 +205,14 @@ func TestNpm(t *testing.T) {
 			Utils:   &utils,
 			Options: options,
 		}
-		packageLock, yarnLock, err := exec.checkIfLockFilesExist()
+		manager, err := exec.detectPackageManager(".")
 
 		if assert.NoError(t, err) {
-			assert.True(t, packageLock)
-			assert.True(t, yarnLock)
+			assert.Equal(t, npmManager, manager)
 		}
 	})
 
-	t.Run("check that yarn.lock and package-lock do not exist", func(t *testing.T) {
+	t.Run("check that npm is used without lock files", func(t *testing.T) {
 		utils := newNpmMockUtilsBundle()
 		utils.AddFile("package.json", []byte("{\"scripts\": { \"ci-lint\": \"exit 0\" } }"))
 
@@ -221,11 +222,10 @@ func TestNpm(t *testing.T) {
 			Utils:   &utils,
 			Options: options,
 		}
-		packageLock, yarnLock, err := exec.checkIfLockFilesExist()
+		manager, err := exec.detectPackageManager(".")
 
 		if assert.NoError(t, err) {
-			assert.False(t, packageLock)
-			assert.False(t, yarnLock)
+			assert.Equal(t, npmManager, manager)
 		}
 	})
 
@@ -365,4 +365,114 @@ func TestNpm(t *testing.T) {
 			}
 		}
 	})
+
+	t.Run("Install pnpm workspace once", func(t *testing.T) {
+		utils := newNpmMockUtilsBundle()
+		utils.AddFile("package.json", []byte(`{"private": true}`))
+		utils.AddFile("pnpm-lock.yaml", []byte("lockfileVersion: '6.0'"))
+		utils.AddFile("pnpm-workspace.yaml", []byte("packages:\n  - 'packages/*'\n"))
+		utils.AddFile(filepath.Join("packages", "a", "package.json"), []byte(`{"name": "a"}`))
+		utils.AddFile(filepath.Join("packages", "b", "package.json"), []byte(`{"name": "b"}`))
+
+		exec := &Execute{
+			Utils:   &utils,
+			Options: ExecutorOptions{},
+		}
+		err := exec.InstallAllDependencies([]string{filepath.Join("packages", "a", "package.json"), "package.json", filepath.Join("packages", "b", "package.json")})
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, []mock.ExecCall{
+				{Exec: "npm", Params: []string{"config", "get", "registry"}},
+				{Exec: "pnpm", Params: []string{"install", "--frozen-lockfile"}},
+			}, utils.execRunner.Calls)
+		}
+	})
+
+	t.Run("Install deps for package.json with yarn berry", func(t *testing.T) {
+		utils := newNpmMockUtilsBundle()
+		utils.AddFile("package.json", []byte(`{"name": "test"}`))
+		utils.AddFile("yarn.lock", []byte("__metadata:\n  version: 6\n"))
+		utils.AddFile(".yarnrc.yml", []byte("nodeLinker: pnp\n"))
+		utils.execRunner = &mock.ExecMockRunner{StdoutReturn: map[string]string{"yarn config get npmRegistryServer": "https://registry.yarnpkg.com\n"}}
+
+		exec := &Execute{
+			Utils:   &utils,
+			Options: ExecutorOptions{DefaultNpmRegistry: "https://example.org/npm"},
+		}
+		err := exec.install("package.json")
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, []mock.ExecCall{
+				{Exec: "yarn", Params: []string{"config", "get", "npmRegistryServer"}},
+				{Exec: "yarn", Params: []string{"config", "set", "-H", "npmRegistryServer", "https://example.org/npm"}},
+				{Exec: "yarn", Params: []string{"install", "--immutable"}},
+			}, utils.execRunner.Calls)
+		}
+	})
+
+	t.Run("check yarn berry registry is not overwritten", func(t *testing.T) {
+		utils := newNpmMockUtilsBundle()
+		utils.AddFile(".yarnrc.yml", []byte("npmRegistryServer: https://my.registry\n"))
+		utils.execRunner = &mock.ExecMockRunner{StdoutReturn: map[string]string{"yarn config get npmRegistryServer": "https://my.registry\n"}}
+
+		exec := &Execute{
+			Utils:   &utils,
+			Options: ExecutorOptions{DefaultNpmRegistry: "https://example.org/npm"},
+		}
+		err := exec.SetNpmRegistries()
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, []mock.ExecCall{{Exec: "yarn", Params: []string{"config", "get", "npmRegistryServer"}}}, utils.execRunner.Calls)
+		}
+	})
+
+	t.Run("check Execute scripts of pnpm workspace in dependency order", func(t *testing.T) {
+		utils := newNpmMockUtilsBundle()
+		utils.AddFile("package.json", []byte(`{"private": true}`))
+		utils.AddFile("pnpm-lock.yaml", []byte("lockfileVersion: '6.0'"))
+		utils.AddFile("pnpm-workspace.yaml", []byte("packages:\n  - 'packages/*'\n"))
+		utils.AddFile(filepath.Join("packages", "a", "package.json"), []byte(`{"name": "a", "scripts": {"build": "tsc"}, "dependencies": {"b": "workspace:*"}}`))
+		utils.AddFile(filepath.Join("packages", "b", "package.json"), []byte(`{"name": "b", "scripts": {"build": "tsc"}}`))
+
+		directories := []string{}
+		utils.execRunner.Stub = func(call string, stdoutReturn map[string]string, shouldFailOnCommand map[string]error, stdout io.Writer) error {
+			if strings.HasPrefix(call, "pnpm run") {
+				dir, _ := utils.Getwd()
+				directories = append(directories, dir)
+			}
+			return nil
+		}
+
+		exec := &Execute{
+			Utils:   &utils,
+			Options: ExecutorOptions{},
+		}
+		err := exec.RunScriptsInAllPackages([]string{"build"}, []string{"--silent"}, []string{"--watch=false"}, false, nil, nil)
+
+		if assert.NoError(t, err) && assert.Len(t, utils.execRunner.Calls, 4) {
+			assert.Equal(t, mock.ExecCall{Exec: "pnpm", Params: []string{"run", "--silent", "build", "--watch=false"}}, utils.execRunner.Calls[1])
+			assert.Equal(t, []string{filepath.Join("/", "packages", "b"), filepath.Join("/", "packages", "a")}, directories)
+		}
+	})
+
+	t.Run("Create BOM for pnpm and yarn berry workspaces", func(t *testing.T) {
+		utils := newNpmMockUtilsBundle()
+		utils.AddFile(filepath.Join("web", "package.json"), []byte(`{"name": "web"}`))
+		utils.AddFile(filepath.Join("web", "pnpm-lock.yaml"), []byte("lockfileVersion: '6.0'"))
+		utils.AddFile(filepath.Join("app", "package.json"), []byte(`{"name": "app"}`))
+		utils.AddFile(filepath.Join("app", "yarn.lock"), []byte("__metadata:\n  version: 6\n"))
+
+		exec := &Execute{
+			Utils:   &utils,
+			Options: ExecutorOptions{},
+		}
+		err := exec.CreateBOM([]string{filepath.Join("web", "package.json"), filepath.Join("app", "package.json")})
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, []mock.ExecCall{
+				{Exec: "pnpm", Params: []string{"--package=@cyclonedx/bom@^3.10.6", "dlx", "cyclonedx-bom", "web", "--output", filepath.Join("web", "bom-npm.xml")}},
+				{Exec: "yarn", Params: []string{"dlx", "-q", "@cyclonedx/yarn-plugin-cyclonedx", "--output-format", "XML", "--output-file", "bom-npm.xml"}},
+			}, utils.execRunner.Calls)
+		}
+	})
 }
@@ -30,8 +30,14 @@ func (pd *npmMinimalPackageDescriptor) Scope() string {
 	return matches[1]
 }
 
-// PublishAllPackages executes npm publish for all package.json files defined in packageJSONFiles list
+// PublishAllPackages executes npm publish for all package.json files defined in packageJSONFiles list.
+// Packages of a workspace are published after the packages they depend on, private packages of a workspace are skipped.
 func (exec *Execute) PublishAllPackages(packageJSONFiles []string, registry, username, password string, packBeforePublish bool) error {
+	packageJSONFiles, err := exec.sortByWorkspaces(packageJSONFiles)
+	if err != nil {
+		return err
+	}
+
 	for _, packageJSON := range packageJSONFiles {
 		log.Entry().Infof("triggering publish for %s", packageJSON)
 
@@ -43,6 +49,15 @@ func (exec *Execute) PublishAllPackages(packageJSONFiles []string, registry, use
 			return fmt.Errorf("package.json file '%s' not found: %w", packageJSON, err)
 		}
 
+		private, err := exec.isPrivateWorkspacePackage(packageJSON)
+		if err != nil {
+			return err
+		}
+		if private {
+			log.Entry().Infof("skipping publish of private workspace package %s", packageJSON)
+			continue
+		}
+
 		err = exec.publish(packageJSON, registry, username, password, packBeforePublish)
 		if err != nil {
 			return err
@@ -55,6 +70,15 @@ func (exec *Execute) PublishAllPackages(packageJSONFiles []string, registry, use
 func (exec *Execute) publish(packageJSON, registry, username, password string, packBeforePublish bool) error {
 	execRunner := exec.Utils.GetExecRunner()
 
+	manager, err := exec.packageManagerOf(packageJSON)
+	if err != nil {
+		return err
+	}
+	if manager == pnpmManager || manager == yarnBerryManager {
+		// only the package managers replace references via the workspace protocol by the versions of the referenced packages
+		packBeforePublish = true
+	}
+
 	scope, err := exec.readPackageScope(packageJSON)
 
 	if err != nil {
@@ -135,7 +159,7 @@ func (exec *Execute) publish(packageJSON, registry, username, password string, p
 
 		defer exec.Utils.RemoveAll(tmpDirectory)
 
-		err = execRunner.RunExecutable("npm", "pack", "--pack-destination", tmpDirectory)
+		err = exec.pack(packageJSON, manager, tmpDirectory)
 		if err != nil {
 			return err
 		}
@@ -195,6 +219,53 @@ func (exec *Execute) publish(packageJSON, registry, username, password string, p
 	return nil
 }
 
+// pack creates the tarball of the package in the destination directory
+func (exec *Execute) pack(packageJSON string, manager packageManager, destination string) error {
+	execRunner := exec.Utils.GetExecRunner()
+	if manager != pnpmManager && manager != yarnBerryManager {
+		return execRunner.RunExecutable("npm", "pack", "--pack-destination", destination)
+	}
+
+	destination, err := exec.Utils.Abs(destination)
+	if err != nil {
+		return err
+	}
+	if manager == pnpmManager {
+		return execRunner.RunExecutable("pnpm", "--dir", filepath.Dir(packageJSON), "pack", "--pack-destination", destination)
+	}
+
+	oldWorkingDirectory, err := exec.Utils.Getwd()
+	if err != nil {
+		return fmt.Errorf("failed to get current working directory before packing: %w", err)
+	}
+	if err := exec.Utils.Chdir(filepath.Dir(packageJSON)); err != nil {
+		return fmt.Errorf("failed to change into directory for packing: %w", err)
+	}
+	if err := execRunner.RunExecutable("yarn", "pack", "--out", filepath.Join(destination, "package.tgz")); err != nil {
+		return err
+	}
+	if err := exec.Utils.Chdir(oldWorkingDirectory); err != nil {
+		return fmt.Errorf("failed to change back into original directory: %w", err)
+	}
+	return nil
+}
+
+// isPrivateWorkspacePackage checks whether the package is marked as private and belongs to a workspace, e.g. the workspace root
+func (exec *Execute) isPrivateWorkspacePackage(packageJSON string) (bool, error) {
+	ws, err := exec.findWorkspace(packageJSON)
+	if err != nil || ws == nil {
+		return false, err
+	}
+	if pkg, isMember := ws.member(packageJSON); isMember {
+		return pkg.private, nil
+	}
+	descriptor, err := exec.readPackageDescriptor(packageJSON)
+	if err != nil {
+		return false, err
+	}
+	return descriptor.Private, nil
+}
+
 func (exec *Execute) readPackageScope(packageJSON string) (string, error) {
 	b, err := exec.Utils.FileRead(packageJSON)
 
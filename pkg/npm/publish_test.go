@@ -561,3 +561,47 @@ func TestNpmPublish(t *testing.T) {
 		})
 	}
 }
+
+func TestNpmPublishWorkspace(t *testing.T) {
+	utils := newNpmMockUtilsBundle()
+	utils.AddFile("package.json", []byte(`{"name": "root", "private": true}`))
+	utils.AddFile("pnpm-lock.yaml", []byte("lockfileVersion: '6.0'"))
+	utils.AddFile("pnpm-workspace.yaml", []byte("packages:\n  - 'packages/*'\n"))
+	utils.AddFile(filepath.Join("packages", "app", "package.json"), []byte(`{"name": "@piper/app", "version": "1.0.0", "dependencies": {"@piper/lib": "workspace:^"}}`))
+	utils.AddFile(filepath.Join("packages", "lib", "package.json"), []byte(`{"name": "@piper/lib", "version": "1.0.0"}`))
+	utils.AddFile(filepath.Join("packages", "test", "package.json"), []byte(`{"name": "@piper/test", "private": true}`))
+
+	exec := &Execute{
+		Utils:   &utils,
+		Options: ExecutorOptions{},
+	}
+
+	propertiesLoadFile = utils.FileRead
+	propertiesWriteFile = utils.FileWrite
+	writeIgnoreFile = utils.FileWrite
+
+	// This stub simulates the behavior of pnpm pack and puts a tgz into the requested destination
+	utils.execRunner.Stub = func(call string, stdoutReturn map[string]string, shouldFailOnCommand map[string]error, stdout io.Writer) error {
+		matches := regexp.MustCompile(`pnpm\s+.*pack\s+--pack-destination\s+([^\s]+)`).FindStringSubmatch(call)
+		if len(matches) > 0 {
+			utils.AddFile(filepath.Join(matches[1], "package.tgz"), []byte("this is a tgz file"))
+		}
+		return nil
+	}
+
+	packageJSONFiles := []string{
+		"package.json",
+		filepath.Join("packages", "app", "package.json"),
+		filepath.Join("packages", "lib", "package.json"),
+		filepath.Join("packages", "test", "package.json"),
+	}
+	err := exec.PublishAllPackages(packageJSONFiles, "https://my.private.npm.registry/", "ThisIsTheUser", "AndHereIsThePassword", false)
+
+	if assert.NoError(t, err) && assert.Len(t, utils.execRunner.Calls, 4) {
+		assert.Equal(t, "pnpm", utils.execRunner.Calls[0].Exec)
+		assert.Equal(t, []string{"--dir", filepath.Join("packages", "lib"), "pack", "--pack-destination", "/temp-test"}, utils.execRunner.Calls[0].Params)
+		assert.Equal(t, []string{"publish", "--tarball", "/temp-test/package.tgz", "--userconfig", filepath.Join("temp-test", ".piperNpmrc"), "--registry", "https://my.private.npm.registry/"}, utils.execRunner.Calls[1].Params)
+		assert.Equal(t, []string{"--dir", filepath.Join("packages", "app"), "pack", "--pack-destination", "/temp-test"}, utils.execRunner.Calls[2].Params)
+		assert.Equal(t, "npm", utils.execRunner.Calls[3].Exec)
+	}
+}
@@ -0,0 +1,437 @@
+package npm
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/ghodss/yaml"
+)
+
+// packageManager identifies the tool managing the dependencies of a project
+type packageManager string
+
+const (
+	npmManager       packageManager = "npm"
+	yarnManager      packageManager = "yarn"
+	yarnBerryManager packageManager = "yarn-berry"
+	pnpmManager      packageManager = "pnpm"
+
+	pnpmWorkspaceFilename = "pnpm-workspace.yaml"
+	yarnBerryRcFilename   = ".yarnrc.yml"
+)
+
+// executable returns the name of the command line tool of the package manager
+func (m packageManager) executable() string {
+	if m == yarnBerryManager {
+		return "yarn"
+	}
+	return string(m)
+}
+
+// lockFile returns the name of the lock file of the package manager
+func (m packageManager) lockFile() string {
+	switch m {
+	case yarnManager, yarnBerryManager:
+		return "yarn.lock"
+	case pnpmManager:
+		return "pnpm-lock.yaml"
+	default:
+		return "package-lock.json"
+	}
+}
+
+// installArgs returns the parameters for installing the dependencies, the lock file is not updated in case it exists
+func (m packageManager) installArgs(lockFileExists bool) []string {
+	switch m {
+	case yarnManager:
+		if lockFileExists {
+			return []string{"install", "--frozen-lockfile"}
+		}
+	case yarnBerryManager:
+		if lockFileExists {
+			return []string{"install", "--immutable"}
+		}
+	case pnpmManager:
+		if lockFileExists {
+			return []string{"install", "--frozen-lockfile"}
+		}
+		// pnpm refuses to create a lock file on CI servers by default
+		return []string{"install", "--no-frozen-lockfile"}
+	default:
+		if lockFileExists {
+			return []string{"ci"}
+		}
+	}
+	return []string{"install"}
+}
+
+// packageDescriptor holds the parts of a package.json file required for building workspaces
+type packageDescriptor struct {
+	Name                 string            `json:"name"`
+	Version              string            `json:"version"`
+	Private              bool              `json:"private"`
+	PackageManager       string            `json:"packageManager"`
+	Workspaces           json.RawMessage   `json:"workspaces"`
+	Dependencies         map[string]string `json:"dependencies"`
+	DevDependencies      map[string]string `json:"devDependencies"`
+	PeerDependencies     map[string]string `json:"peerDependencies"`
+	OptionalDependencies map[string]string `json:"optionalDependencies"`
+}
+
+// workspacePatterns returns the patterns of the member directories from the "workspaces" field which is either
+// a list of patterns or an object containing them as "packages"
+func (pd *packageDescriptor) workspacePatterns() []string {
+	if len(pd.Workspaces) == 0 {
+		return nil
+	}
+	patterns := []string{}
+	if err := json.Unmarshal(pd.Workspaces, &patterns); err == nil {
+		return patterns
+	}
+	workspaces := struct {
+		Packages []string `json:"packages"`
+	}{}
+	if err := json.Unmarshal(pd.Workspaces, &workspaces); err == nil {
+		return workspaces.Packages
+	}
+	return nil
+}
+
+// allDependencies returns the names of all packages the package depends on
+func (pd *packageDescriptor) allDependencies() []string {
+	names := []string{}
+	for _, dependencies := range []map[string]string{pd.Dependencies, pd.DevDependencies, pd.PeerDependencies, pd.OptionalDependencies} {
+		for name := range dependencies {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// workspace describes a project consisting of a root package.json and member packages which are installed together
+type workspace struct {
+	// root is the directory of the root package.json
+	root    string
+	manager packageManager
+	// packages are the members of the workspace in dependency order, i.e. each package follows the packages it depends on
+	packages []workspacePackage
+}
+
+// workspacePackage describes a member of a workspace
+type workspacePackage struct {
+	name        string
+	packageJSON string
+	private     bool
+	// dependencies contains the names of the members of the workspace the package depends on
+	dependencies []string
+}
+
+// rootPackageJSON returns the path of the package.json file of the workspace root
+func (w *workspace) rootPackageJSON() string {
+	return filepath.Join(w.root, "package.json")
+}
+
+// member returns the member of the workspace described by the given package.json file
+func (w *workspace) member(packageJSON string) (workspacePackage, bool) {
+	packageJSON = filepath.Clean(packageJSON)
+	for _, pkg := range w.packages {
+		if pkg.packageJSON == packageJSON {
+			return pkg, true
+		}
+	}
+	return workspacePackage{}, false
+}
+
+func (exec *Execute) readPackageDescriptor(packageJSON string) (*packageDescriptor, error) {
+	content, err := exec.Utils.FileRead(packageJSON)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", packageJSON, err)
+	}
+	descriptor := &packageDescriptor{}
+	if err := json.Unmarshal(content, descriptor); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s: %w", packageJSON, err)
+	}
+	return descriptor, nil
+}
+
+// detectPackageManager determines the package manager of the project in dir based on its lock file.
+// Without lock file, the pnpm workspace and Yarn configuration files as well as the "packageManager" field
+// of the package.json are considered, npm is used as default.
+func (exec *Execute) detectPackageManager(dir string) (packageManager, error) {
+	exists := func(name string) (bool, error) {
+		return exec.Utils.FileExists(filepath.Join(dir, name))
+	}
+
+	for _, manager := range []packageManager{pnpmManager, npmManager} {
+		if lockFileExists, err := exists(manager.lockFile()); err != nil || lockFileExists {
+			return manager, err
+		}
+	}
+	yarnRcExists, err := exists(yarnBerryRcFilename)
+	if err != nil {
+		return "", err
+	}
+	yarnLockExists, err := exists(yarnManager.lockFile())
+	if err != nil {
+		return "", err
+	}
+	if yarnLockExists {
+		if yarnRcExists {
+			return yarnBerryManager, nil
+		}
+		// the lock file of Yarn 2 and later is a YAML document containing metadata
+		content, err := exec.Utils.FileRead(filepath.Join(dir, yarnManager.lockFile()))
+		if err != nil {
+			return "", fmt.Errorf("failed to read %s: %w", yarnManager.lockFile(), err)
+		}
+		if strings.Contains(string(content), "__metadata:") {
+			return yarnBerryManager, nil
+		}
+		return yarnManager, nil
+	}
+	if yarnRcExists {
+		return yarnBerryManager, nil
+	}
+	if pnpmWorkspaceExists, err := exists(pnpmWorkspaceFilename); err != nil || pnpmWorkspaceExists {
+		return pnpmManager, err
+	}
+
+	if packageJSONExists, _ := exists("package.json"); packageJSONExists {
+		descriptor, err := exec.readPackageDescriptor(filepath.Join(dir, "package.json"))
+		if err != nil {
+			return "", err
+		}
+		return managerFromPackageManagerField(descriptor.PackageManager), nil
+	}
+	return npmManager, nil
+}
+
+// managerFromPackageManagerField evaluates the "packageManager" field used by Corepack, e.g. "pnpm@8.6.0" or "yarn@3.5.1"
+func managerFromPackageManagerField(field string) packageManager {
+	name, version, _ := strings.Cut(field, "@")
+	switch name {
+	case "pnpm":
+		return pnpmManager
+	case "yarn":
+		major, _ := strconv.Atoi(strings.SplitN(version, ".", 2)[0])
+		if major >= 2 {
+			return yarnBerryManager
+		}
+		return yarnManager
+	default:
+		return npmManager
+	}
+}
+
+// packageManagerOf returns the package manager of the workspace the package belongs to or of the package itself
+func (exec *Execute) packageManagerOf(packageJSON string) (packageManager, error) {
+	ws, err := exec.findWorkspace(packageJSON)
+	if err != nil {
+		return "", err
+	}
+	if ws != nil {
+		return ws.manager, nil
+	}
+	return exec.detectPackageManager(filepath.Dir(packageJSON))
+}
+
+// findWorkspace returns the workspace the package.json file belongs to either as root or as member,
+// it returns nil in case the package is not part of a workspace
+func (exec *Execute) findWorkspace(packageJSON string) (*workspace, error) {
+	packageDir := filepath.Dir(filepath.Clean(packageJSON))
+	dir := packageDir
+	for {
+		ws, err := exec.loadWorkspace(dir)
+		if err != nil {
+			return nil, err
+		}
+		if ws != nil {
+			if dir == packageDir {
+				return ws, nil
+			}
+			if _, isMember := ws.member(packageJSON); isMember {
+				return ws, nil
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, nil
+		}
+		dir = parent
+	}
+}
+
+// loadWorkspace reads the workspace with its root in dir, it returns nil in case dir does not contain a workspace root
+func (exec *Execute) loadWorkspace(dir string) (*workspace, error) {
+	if ws, loaded := exec.workspaces[dir]; loaded {
+		return ws, nil
+	}
+	ws, err := exec.readWorkspace(dir)
+	if err != nil {
+		return nil, err
+	}
+	if exec.workspaces == nil {
+		exec.workspaces = map[string]*workspace{}
+	}
+	exec.workspaces[dir] = ws
+	return ws, nil
+}
+
+func (exec *Execute) readWorkspace(dir string) (*workspace, error) {
+	rootPackageJSON := filepath.Join(dir, "package.json")
+	if exists, _ := exec.Utils.FileExists(rootPackageJSON); !exists {
+		return nil, nil
+	}
+
+	var patterns []string
+	pnpmWorkspace := filepath.Join(dir, pnpmWorkspaceFilename)
+	if exists, _ := exec.Utils.FileExists(pnpmWorkspace); exists {
+		content, err := exec.Utils.FileRead(pnpmWorkspace)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", pnpmWorkspace, err)
+		}
+		pnpmConfig := struct {
+			Packages []string `json:"packages"`
+		}{}
+		if err := yaml.Unmarshal(content, &pnpmConfig); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal %s: %w", pnpmWorkspace, err)
+		}
+		patterns = pnpmConfig.Packages
+	} else {
+		descriptor, err := exec.readPackageDescriptor(rootPackageJSON)
+		if err != nil {
+			return nil, err
+		}
+		patterns = descriptor.workspacePatterns()
+	}
+	if len(patterns) == 0 {
+		return nil, nil
+	}
+
+	manager, err := exec.detectPackageManager(dir)
+	if err != nil {
+		return nil, err
+	}
+	packageJSONFiles, err := exec.findWorkspaceMembers(dir, patterns)
+	if err != nil {
+		return nil, err
+	}
+
+	descriptors := map[string]*packageDescriptor{}
+	packages := []workspacePackage{}
+	for _, packageJSON := range packageJSONFiles {
+		descriptor, err := exec.readPackageDescriptor(packageJSON)
+		if err != nil {
+			return nil, err
+		}
+		descriptors[descriptor.Name] = descriptor
+		packages = append(packages, workspacePackage{name: descriptor.Name, packageJSON: packageJSON, private: descriptor.Private})
+	}
+	for i := range packages {
+		for _, dependency := range descriptors[packages[i].name].allDependencies() {
+			if _, isMember := descriptors[dependency]; isMember && dependency != packages[i].name {
+				packages[i].dependencies = append(packages[i].dependencies, dependency)
+			}
+		}
+	}
+
+	log.Entry().Infof("Discovered %s workspace in '%s' with %d package(s)", manager, dir, len(packages))
+	return &workspace{root: dir, manager: manager, packages: sortByDependencies(packages)}, nil
+}
+
+// findWorkspaceMembers returns the package.json files matching the workspace patterns, patterns starting with "!" exclude packages
+func (exec *Execute) findWorkspaceMembers(dir string, patterns []string) ([]string, error) {
+	members := []string{}
+	excludes := []string{"**/node_modules/**"}
+	for _, pattern := range patterns {
+		if strings.HasPrefix(pattern, "!") {
+			excludes = append(excludes, filepath.Join(dir, strings.TrimPrefix(pattern, "!"), "**"))
+			continue
+		}
+		matches, err := exec.Utils.Glob(filepath.Join(dir, pattern, "package.json"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to find workspace packages matching '%s': %w", pattern, err)
+		}
+		for _, match := range matches {
+			if match = filepath.Clean(match); !piperutils.ContainsString(members, match) && filepath.Dir(match) != filepath.Clean(dir) {
+				members = append(members, match)
+			}
+		}
+	}
+	return piperutils.ExcludeFiles(members, excludes)
+}
+
+// sortByDependencies orders the packages topologically, packages without dependencies between each other keep their order.
+// Packages with cyclic dependencies are appended in their original order.
+func sortByDependencies(packages []workspacePackage) []workspacePackage {
+	sorted := []workspacePackage{}
+	done := map[string]bool{}
+	for len(sorted) < len(packages) {
+		progress := false
+		for _, pkg := range packages {
+			if done[pkg.name] || !allDone(pkg.dependencies, done) {
+				continue
+			}
+			sorted = append(sorted, pkg)
+			done[pkg.name] = true
+			progress = true
+		}
+		if !progress {
+			for _, pkg := range packages {
+				if !done[pkg.name] {
+					log.Entry().Warnf("package %s has cyclic dependencies within the workspace", pkg.name)
+					sorted = append(sorted, pkg)
+					done[pkg.name] = true
+				}
+			}
+		}
+	}
+	return sorted
+}
+
+func allDone(names []string, done map[string]bool) bool {
+	for _, name := range names {
+		if !done[name] {
+			return false
+		}
+	}
+	return true
+}
+
+// sortByWorkspaces orders the package.json files such that the members of a workspace are processed in dependency order.
+// The members are placed at the position of the first member in the list, files outside of workspaces keep their position.
+func (exec *Execute) sortByWorkspaces(packageJSONFiles []string) ([]string, error) {
+	original := map[string]string{}
+	for _, file := range packageJSONFiles {
+		original[filepath.Clean(file)] = file
+	}
+
+	sorted := []string{}
+	added := map[string]bool{}
+	for _, file := range packageJSONFiles {
+		if added[filepath.Clean(file)] {
+			continue
+		}
+		ws, err := exec.findWorkspace(file)
+		if err != nil {
+			return nil, err
+		}
+		if ws == nil || filepath.Clean(file) == ws.rootPackageJSON() {
+			sorted = append(sorted, file)
+			added[filepath.Clean(file)] = true
+			continue
+		}
+		for _, pkg := range ws.packages {
+			if originalFile, requested := original[pkg.packageJSON]; requested && !added[pkg.packageJSON] {
+				sorted = append(sorted, originalFile)
+				added[pkg.packageJSON] = true
+			}
+		}
+	}
+	return sorted, nil
+}
@@ -0,0 +1,147 @@
+package npm
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectPackageManager(t *testing.T) {
+	tt := []struct {
+		name     string
+		files    map[string]string
+		expected packageManager
+	}{
+		{name: "pnpm lock file", files: map[string]string{"pnpm-lock.yaml": "lockfileVersion: '6.0'"}, expected: pnpmManager},
+		{name: "pnpm workspace without lock file", files: map[string]string{"pnpm-workspace.yaml": "packages: []"}, expected: pnpmManager},
+		{name: "npm lock file", files: map[string]string{"package-lock.json": "{}"}, expected: npmManager},
+		{name: "yarn classic lock file", files: map[string]string{"yarn.lock": "# yarn lockfile v1\n"}, expected: yarnManager},
+		{name: "yarn berry lock file", files: map[string]string{"yarn.lock": "__metadata:\n  version: 6\n"}, expected: yarnBerryManager},
+		{name: "yarn berry configuration", files: map[string]string{"yarn.lock": "", ".yarnrc.yml": "nodeLinker: pnp"}, expected: yarnBerryManager},
+		{name: "corepack pnpm", files: map[string]string{"package.json": `{"packageManager": "pnpm@8.6.0"}`}, expected: pnpmManager},
+		{name: "corepack yarn classic", files: map[string]string{"package.json": `{"packageManager": "yarn@1.22.19"}`}, expected: yarnManager},
+		{name: "corepack yarn berry", files: map[string]string{"package.json": `{"packageManager": "yarn@3.6.1"}`}, expected: yarnBerryManager},
+		{name: "default", files: map[string]string{"package.json": `{"name": "test"}`}, expected: npmManager},
+	}
+
+	for _, test := range tt {
+		t.Run(test.name, func(t *testing.T) {
+			utils := newNpmMockUtilsBundle()
+			for path, content := range test.files {
+				utils.AddFile(filepath.Join("app", path), []byte(content))
+			}
+			exec := &Execute{Utils: &utils}
+
+			manager, err := exec.detectPackageManager("app")
+
+			if assert.NoError(t, err) {
+				assert.Equal(t, test.expected, manager)
+			}
+		})
+	}
+}
+
+func TestWorkspace(t *testing.T) {
+	newPnpmWorkspace := func() npmMockUtilsBundle {
+		utils := newNpmMockUtilsBundle()
+		utils.AddFile("package.json", []byte(`{"name": "root", "private": true}`))
+		utils.AddFile("pnpm-lock.yaml", []byte("lockfileVersion: '6.0'"))
+		utils.AddFile("pnpm-workspace.yaml", []byte("packages:\n  - 'apps/*'\n  - 'packages/*'\n  - '!packages/internal'\n"))
+		utils.AddFile(filepath.Join("apps", "web", "package.json"), []byte(`{"name": "web", "dependencies": {"ui": "workspace:*", "react": "^18.0.0"}}`))
+		utils.AddFile(filepath.Join("packages", "ui", "package.json"), []byte(`{"name": "ui", "dependencies": {"utils": "workspace:^"}}`))
+		utils.AddFile(filepath.Join("packages", "utils", "package.json"), []byte(`{"name": "utils", "devDependencies": {"utils": "workspace:*"}}`))
+		utils.AddFile(filepath.Join("packages", "internal", "package.json"), []byte(`{"name": "internal", "private": true}`))
+		utils.AddFile(filepath.Join("node_modules", "ui", "package.json"), []byte(`{"name": "ui"}`))
+		return utils
+	}
+
+	t.Run("pnpm workspace in dependency order", func(t *testing.T) {
+		utils := newPnpmWorkspace()
+		exec := &Execute{Utils: &utils}
+
+		ws, err := exec.loadWorkspace(".")
+
+		if assert.NoError(t, err) && assert.NotNil(t, ws) {
+			assert.Equal(t, pnpmManager, ws.manager)
+			assert.Equal(t, "package.json", ws.rootPackageJSON())
+			assert.Equal(t, []workspacePackage{
+				{name: "utils", packageJSON: filepath.Join("packages", "utils", "package.json")},
+				{name: "ui", packageJSON: filepath.Join("packages", "ui", "package.json"), dependencies: []string{"utils"}},
+				{name: "web", packageJSON: filepath.Join("apps", "web", "package.json"), dependencies: []string{"ui"}},
+			}, ws.packages)
+		}
+	})
+
+	t.Run("yarn workspaces object", func(t *testing.T) {
+		utils := newNpmMockUtilsBundle()
+		utils.AddFile(filepath.Join("frontend", "package.json"), []byte(`{"private": true, "workspaces": {"packages": ["packages/*"]}}`))
+		utils.AddFile(filepath.Join("frontend", "yarn.lock"), []byte("__metadata:\n  version: 6\n"))
+		utils.AddFile(filepath.Join("frontend", "packages", "a", "package.json"), []byte(`{"name": "a"}`))
+		exec := &Execute{Utils: &utils}
+
+		ws, err := exec.findWorkspace(filepath.Join("frontend", "packages", "a", "package.json"))
+
+		if assert.NoError(t, err) && assert.NotNil(t, ws) {
+			assert.Equal(t, "frontend", ws.root)
+			assert.Equal(t, yarnBerryManager, ws.manager)
+			assert.Len(t, ws.packages, 1)
+		}
+	})
+
+	t.Run("no workspace", func(t *testing.T) {
+		utils := newNpmMockUtilsBundle()
+		utils.AddFile("package.json", []byte(`{"name": "root"}`))
+		utils.AddFile(filepath.Join("src", "package.json"), []byte(`{"name": "src"}`))
+		exec := &Execute{Utils: &utils}
+
+		ws, err := exec.findWorkspace(filepath.Join("src", "package.json"))
+
+		assert.NoError(t, err)
+		assert.Nil(t, ws)
+	})
+
+	t.Run("excluded package is not a member", func(t *testing.T) {
+		utils := newPnpmWorkspace()
+		exec := &Execute{Utils: &utils}
+
+		ws, err := exec.findWorkspace(filepath.Join("packages", "internal", "package.json"))
+
+		assert.NoError(t, err)
+		assert.Nil(t, ws)
+	})
+
+	t.Run("sort package.json files by workspace dependencies", func(t *testing.T) {
+		utils := newPnpmWorkspace()
+		utils.AddFile(filepath.Join("tools", "package.json"), []byte(`{"name": "tools"}`))
+		exec := &Execute{Utils: &utils}
+
+		sorted, err := exec.sortByWorkspaces([]string{
+			filepath.Join("apps", "web", "package.json"),
+			"package.json",
+			filepath.Join("packages", "ui", "package.json"),
+			filepath.Join("packages", "utils", "package.json"),
+			filepath.Join("tools", "package.json"),
+		})
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, []string{
+				filepath.Join("packages", "utils", "package.json"),
+				filepath.Join("packages", "ui", "package.json"),
+				filepath.Join("apps", "web", "package.json"),
+				"package.json",
+				filepath.Join("tools", "package.json"),
+			}, sorted)
+		}
+	})
+
+	t.Run("cyclic dependencies keep their order", func(t *testing.T) {
+		sorted := sortByDependencies([]workspacePackage{
+			{name: "a", dependencies: []string{"b"}},
+			{name: "b", dependencies: []string{"a"}},
+			{name: "c"},
+		})
+
+		assert.Equal(t, []string{"c", "a", "b"}, []string{sorted[0].name, sorted[1].name, sorted[2].name})
+	})
+}
@@ -0,0 +1,125 @@
+package osv
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+var cvssWeights = map[string]map[string]float64{
+	"AV": {"N": 0.85, "A": 0.62, "L": 0.55, "P": 0.2},
+	"AC": {"L": 0.77, "H": 0.44},
+	"UI": {"N": 0.85, "R": 0.62},
+	"C":  {"H": 0.56, "L": 0.22, "N": 0},
+	"I":  {"H": 0.56, "L": 0.22, "N": 0},
+	"A":  {"H": 0.56, "L": 0.22, "N": 0},
+}
+
+// CVSS3BaseScore calculates the base score of a CVSS v3.x vector, e.g. CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H
+func CVSS3BaseScore(vector string) (float64, error) {
+	parts := strings.Split(vector, "/")
+	if len(parts) < 9 || !strings.HasPrefix(parts[0], "CVSS:3") {
+		return 0, fmt.Errorf("invalid CVSS v3 vector '%v'", vector)
+	}
+	metrics := map[string]string{}
+	for _, part := range parts[1:] {
+		keyValue := strings.SplitN(part, ":", 2)
+		if len(keyValue) != 2 {
+			return 0, fmt.Errorf("invalid CVSS v3 vector '%v'", vector)
+		}
+		metrics[keyValue[0]] = keyValue[1]
+	}
+
+	values := map[string]float64{}
+	for metric, weights := range cvssWeights {
+		value, ok := weights[metrics[metric]]
+		if !ok {
+			return 0, fmt.Errorf("invalid value '%v' for metric %v in CVSS v3 vector '%v'", metrics[metric], metric, vector)
+		}
+		values[metric] = value
+	}
+	scopeChanged := metrics["S"] == "C"
+	if !scopeChanged && metrics["S"] != "U" {
+		return 0, fmt.Errorf("invalid value '%v' for metric S in CVSS v3 vector '%v'", metrics["S"], vector)
+	}
+	var privileges float64
+	switch metrics["PR"] {
+	case "N":
+		privileges = 0.85
+	case "L":
+		privileges = 0.62
+		if scopeChanged {
+			privileges = 0.68
+		}
+	case "H":
+		privileges = 0.27
+		if scopeChanged {
+			privileges = 0.5
+		}
+	default:
+		return 0, fmt.Errorf("invalid value '%v' for metric PR in CVSS v3 vector '%v'", metrics["PR"], vector)
+	}
+
+	iss := 1 - (1-values["C"])*(1-values["I"])*(1-values["A"])
+	impact := 6.42 * iss
+	if scopeChanged {
+		impact = 7.52*(iss-0.029) - 3.25*math.Pow(iss-0.02, 15)
+	}
+	exploitability := 8.22 * values["AV"] * values["AC"] * privileges * values["UI"]
+	if impact <= 0 {
+		return 0, nil
+	}
+	if scopeChanged {
+		return roundUp(math.Min(1.08*(impact+exploitability), 10)), nil
+	}
+	return roundUp(math.Min(impact+exploitability, 10)), nil
+}
+
+// roundUp implements the rounding as defined in the CVSS v3.1 specification
+func roundUp(value float64) float64 {
+	intValue := int(math.Round(value * 100000))
+	if intValue%10000 == 0 {
+		return float64(intValue) / 100000
+	}
+	return (math.Floor(float64(intValue)/10000) + 1) / 10
+}
+
+// SeverityName returns the qualitative severity rating of a CVSS score
+func SeverityName(score float64) string {
+	switch {
+	case score >= 9:
+		return "critical"
+	case score >= 7:
+		return "high"
+	case score >= 4:
+		return "medium"
+	case score > 0:
+		return "low"
+	}
+	return "none"
+}
+
+// Score returns the CVSS v3 base score of the vulnerability.
+// If no CVSS v3 vector is available the score is derived from the severity provided by the database (e.g. GitHub advisories).
+func (v Vulnerability) Score() float64 {
+	for _, severity := range v.Severity {
+		if severity.Type == "CVSS_V3" {
+			if score, err := CVSS3BaseScore(severity.Score); err == nil {
+				return score
+			}
+		}
+	}
+	if severity, ok := v.DatabaseSpecific["severity"].(string); ok {
+		switch strings.ToUpper(severity) {
+		case "CRITICAL":
+			return 9.0
+		case "HIGH":
+			return 7.0
+		case "MODERATE", "MEDIUM":
+			return 4.0
+		case "LOW":
+			return 0.1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,60 @@
+package osv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCVSS3BaseScore(t *testing.T) {
+	tt := []struct {
+		vector   string
+		expected float64
+	}{
+		{vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:C/C:H/I:H/A:H", expected: 10.0},
+		{vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H", expected: 9.8},
+		{vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:H/A:H", expected: 9.1},
+		{vector: "CVSS:3.0/AV:N/AC:H/PR:N/UI:N/S:U/C:H/I:N/A:N", expected: 5.9},
+		{vector: "CVSS:3.1/AV:N/AC:L/PR:L/UI:R/S:C/C:L/I:L/A:N", expected: 5.4},
+		{vector: "CVSS:3.1/AV:L/AC:L/PR:H/UI:N/S:U/C:L/I:N/A:N", expected: 2.3},
+		{vector: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:N/I:N/A:N", expected: 0},
+	}
+	for _, test := range tt {
+		t.Run(test.vector, func(t *testing.T) {
+			score, err := CVSS3BaseScore(test.vector)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, score)
+		})
+	}
+
+	t.Run("invalid vectors", func(t *testing.T) {
+		_, err := CVSS3BaseScore("AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H")
+		assert.EqualError(t, err, "invalid CVSS v3 vector 'AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H'")
+		_, err = CVSS3BaseScore("CVSS:3.1/AV:X/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H")
+		assert.EqualError(t, err, "invalid value 'X' for metric AV in CVSS v3 vector 'CVSS:3.1/AV:X/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H'")
+	})
+}
+
+func TestSeverityName(t *testing.T) {
+	assert.Equal(t, "critical", SeverityName(9.8))
+	assert.Equal(t, "high", SeverityName(7.0))
+	assert.Equal(t, "medium", SeverityName(5.9))
+	assert.Equal(t, "low", SeverityName(0.1))
+	assert.Equal(t, "none", SeverityName(0))
+}
+
+func TestVulnerabilityScore(t *testing.T) {
+	t.Run("CVSS v3 vector", func(t *testing.T) {
+		v := Vulnerability{Severity: []Severity{{Type: "CVSS_V3", Score: "CVSS:3.1/AV:N/AC:L/PR:N/UI:N/S:U/C:H/I:H/A:H"}}, DatabaseSpecific: map[string]interface{}{"severity": "LOW"}}
+		assert.Equal(t, 9.8, v.Score())
+	})
+
+	t.Run("database specific severity", func(t *testing.T) {
+		v := Vulnerability{DatabaseSpecific: map[string]interface{}{"severity": "MODERATE"}}
+		assert.Equal(t, 4.0, v.Score())
+	})
+
+	t.Run("no severity", func(t *testing.T) {
+		assert.Equal(t, 0.0, Vulnerability{}.Score())
+	})
+}
@@ -0,0 +1,234 @@
+package osv
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/sbom"
+	"github.com/package-url/packageurl-go"
+)
+
+// ecosystems maps package URL types to OSV ecosystems
+var ecosystems = map[string]string{
+	packageurl.TypeMaven:    "Maven",
+	packageurl.TypeNPM:      "npm",
+	packageurl.TypePyPi:     "PyPI",
+	packageurl.TypeGolang:   "Go",
+	packageurl.TypeNuget:    "NuGet",
+	packageurl.TypeGem:      "RubyGems",
+	packageurl.TypeComposer: "Packagist",
+	"hex":                   "Hex",
+	packageurl.TypeDebian:   "Debian",
+	"deb":                   "Debian",
+	"cargo":                 "crates.io",
+	"pub":                   "Pub",
+	"apk":                   "Alpine",
+}
+
+// Component is a package which is checked for vulnerabilities, identified by its OSV ecosystem and name
+type Component struct {
+	Ecosystem  string
+	Name       string
+	Version    string
+	PackageURL string
+	// Location is the file the component has been found in, e.g. the BOM file
+	Location string
+}
+
+// Finding is a vulnerability affecting a component
+type Finding struct {
+	Component     Component
+	Vulnerability *Vulnerability
+	Score         float64
+	FixedVersions []string
+	Assessment    *format.Assessment
+}
+
+// ComponentFromPurl determines the OSV ecosystem and package name of a package URL
+func ComponentFromPurl(purl string) (Component, error) {
+	parsed, err := packageurl.FromString(purl)
+	if err != nil {
+		return Component{}, err
+	}
+	ecosystem, ok := ecosystems[parsed.Type]
+	if !ok {
+		return Component{}, fmt.Errorf("package type '%v' is not supported", parsed.Type)
+	}
+	name := parsed.Name
+	switch parsed.Type {
+	case packageurl.TypeMaven:
+		name = fmt.Sprintf("%v:%v", parsed.Namespace, parsed.Name)
+	case packageurl.TypePyPi:
+		name = strings.ReplaceAll(strings.ToLower(parsed.Name), "_", "-")
+	case packageurl.TypeNPM, packageurl.TypeGolang, packageurl.TypeComposer:
+		if len(parsed.Namespace) > 0 {
+			name = fmt.Sprintf("%v/%v", parsed.Namespace, parsed.Name)
+		}
+	}
+	if ecosystem == "Debian" || ecosystem == "Alpine" {
+		ecosystem = distributionEcosystem(ecosystem, parsed.Qualifiers.Map()["distro"])
+	}
+	return Component{Ecosystem: ecosystem, Name: name, Version: parsed.Version, PackageURL: purl}, nil
+}
+
+// debianReleases maps the code names of Debian releases to their version
+var debianReleases = map[string]string{"stretch": "9", "buster": "10", "bullseye": "11", "bookworm": "12", "trixie": "13"}
+
+// distributionEcosystem adds the release of a Linux distribution to the ecosystem since OSV tracks the packages
+// of each release separately, e.g. Debian:11 for distro=debian-11.5 or Alpine:v3.16 for distro=alpine-3.16.2
+func distributionEcosystem(ecosystem, distro string) string {
+	release := strings.TrimPrefix(strings.ToLower(distro), strings.ToLower(ecosystem)+"-")
+	switch ecosystem {
+	case "Debian":
+		if version, ok := debianReleases[release]; ok {
+			release = version
+		}
+		release = strings.SplitN(release, ".", 2)[0]
+		if isNumeric(release) {
+			return ecosystem + ":" + release
+		}
+	case "Alpine":
+		segments := strings.Split(strings.TrimPrefix(release, "v"), ".")
+		if len(segments) >= 2 && isNumeric(segments[0]) && isNumeric(segments[1]) {
+			return fmt.Sprintf("%v:v%v.%v", ecosystem, segments[0], segments[1])
+		}
+	}
+	return ecosystem
+}
+
+// ComponentsFromBOM returns all components of a BOM which can be checked against the OSV database
+func ComponentsFromBOM(bom *cdx.BOM) []Component {
+	components := []Component{}
+	for _, bomComponent := range sbom.Components(bom) {
+		if len(bomComponent.PackageURL) == 0 {
+			continue
+		}
+		component, err := ComponentFromPurl(bomComponent.PackageURL)
+		if err != nil || len(component.Version) == 0 {
+			log.Entry().Debugf("Skipping component '%v': %v", bomComponent.PackageURL, err)
+			continue
+		}
+		components = append(components, component)
+	}
+	return components
+}
+
+// Ecosystems returns the distinct OSV ecosystems of the components, releases of Linux distributions are omitted
+func Ecosystems(components []Component) []string {
+	found := map[string]bool{}
+	result := []string{}
+	for _, component := range components {
+		ecosystem := baseEcosystem(component.Ecosystem)
+		if !found[ecosystem] {
+			found[ecosystem] = true
+			result = append(result, ecosystem)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Match returns the vulnerabilities of the database affecting the components.
+// Vulnerabilities reported for the same component under different ids (e.g. a GHSA and a PYSEC entry aliasing each other) are reported only once.
+func (db *Database) Match(components []Component) []Finding {
+	findings := []Finding{}
+	reported := map[string]bool{}
+	for _, component := range components {
+		for _, vulnerability := range db.Lookup(component.Ecosystem, component.Name) {
+			for _, affected := range vulnerability.Affected {
+				if !matchesEcosystem(affected.Package.Ecosystem, component.Ecosystem) || !strings.EqualFold(affected.Package.Name, component.Name) || !affected.IsAffected(component.Version) {
+					continue
+				}
+				if alreadyReported(reported, component, vulnerability) {
+					break
+				}
+				findings = append(findings, Finding{
+					Component:     component,
+					Vulnerability: vulnerability,
+					Score:         vulnerability.Score(),
+					FixedVersions: fixedVersionsAfter(affected, component.Version),
+				})
+				break
+			}
+		}
+	}
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].Score != findings[j].Score {
+			return findings[i].Score > findings[j].Score
+		}
+		return findings[i].Vulnerability.ID < findings[j].Vulnerability.ID
+	})
+	return findings
+}
+
+// fixedVersionsAfter returns the fixed versions which are an upgrade of the given version
+func fixedVersionsAfter(affected Affected, version string) []string {
+	fixed := []string{}
+	compare := versionComparison(affected.Package.Ecosystem)
+	for _, fixedVersion := range affected.FixedVersions() {
+		if compare(fixedVersion, version) > 0 {
+			fixed = append(fixed, fixedVersion)
+		}
+	}
+	return fixed
+}
+
+func alreadyReported(reported map[string]bool, component Component, vulnerability *Vulnerability) bool {
+	found := false
+	for _, id := range vulnerability.IDs() {
+		key := component.PackageURL + "|" + id
+		if reported[key] {
+			found = true
+		}
+		reported[key] = true
+	}
+	return found
+}
+
+// IDs returns the id and all aliases of the vulnerability
+func (v Vulnerability) IDs() []string {
+	return append([]string{v.ID}, v.Aliases...)
+}
+
+// ApplyAssessments attaches matching assessments to the findings.
+// An assessment matches if it refers to the id or an alias of the vulnerability and to the package URL of the component.
+func ApplyAssessments(findings []Finding, assessments []format.Assessment) error {
+	for i := range findings {
+		for a := range assessments {
+			assessment := assessments[a]
+			if !piperutils.ContainsString(findings[i].Vulnerability.IDs(), assessment.Vulnerability) {
+				continue
+			}
+			for _, purl := range assessment.Purls {
+				assessmentPurl, err := purl.ToPackageUrl()
+				if err != nil {
+					return fmt.Errorf("invalid package URL '%v' in assessment of %v: %w", purl.Purl, assessment.Vulnerability, err)
+				}
+				findingPurl, err := packageurl.FromString(findings[i].Component.PackageURL)
+				if err != nil {
+					continue
+				}
+				if assessmentPurl.Type == findingPurl.Type && assessmentPurl.Namespace == findingPurl.Namespace &&
+					assessmentPurl.Name == findingPurl.Name && assessmentPurl.Version == findingPurl.Version {
+					findings[i].Assessment = &assessments[a]
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// IsAssessed returns true if the finding has been assessed as not relevant or is still in process
+func (f Finding) IsAssessed() bool {
+	return f.Assessment != nil && f.Assessment.Status != format.Relevant
+}
+
+// Severity returns the qualitative severity of the finding
+func (f Finding) Severity() string {
+	return SeverityName(f.Score)
+}
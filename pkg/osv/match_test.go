@@ -0,0 +1,150 @@
+package osv
+
+import (
+	"testing"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComponentFromPurl(t *testing.T) {
+	tt := []struct {
+		purl     string
+		expected Component
+	}{
+		{purl: "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1", expected: Component{Ecosystem: "Maven", Name: "org.apache.logging.log4j:log4j-core", Version: "2.14.1"}},
+		{purl: "pkg:npm/%40angular/core@15.0.0", expected: Component{Ecosystem: "npm", Name: "@angular/core", Version: "15.0.0"}},
+		{purl: "pkg:pypi/Flask_Cors@3.0.8", expected: Component{Ecosystem: "PyPI", Name: "flask-cors", Version: "3.0.8"}},
+		{purl: "pkg:golang/github.com/gin-gonic/gin@v1.8.1", expected: Component{Ecosystem: "Go", Name: "github.com/gin-gonic/gin", Version: "v1.8.1"}},
+		{purl: "pkg:cargo/smallvec@1.6.0", expected: Component{Ecosystem: "crates.io", Name: "smallvec", Version: "1.6.0"}},
+		{purl: "pkg:deb/debian/openssl@1.1.1n-0%2Bdeb11u3?distro=debian-11.5", expected: Component{Ecosystem: "Debian:11", Name: "openssl", Version: "1.1.1n-0+deb11u3"}},
+		{purl: "pkg:deb/debian/openssl@1.1.1n-0%2Bdeb10u3?distro=buster", expected: Component{Ecosystem: "Debian:10", Name: "openssl", Version: "1.1.1n-0+deb10u3"}},
+		{purl: "pkg:deb/debian/openssl@1.1.1n-0%2Bdeb11u3", expected: Component{Ecosystem: "Debian", Name: "openssl", Version: "1.1.1n-0+deb11u3"}},
+		{purl: "pkg:apk/alpine/curl@7.83.1-r3?distro=alpine-3.16.2", expected: Component{Ecosystem: "Alpine:v3.16", Name: "curl", Version: "7.83.1-r3"}},
+	}
+	for _, test := range tt {
+		t.Run(test.purl, func(t *testing.T) {
+			component, err := ComponentFromPurl(test.purl)
+			assert.NoError(t, err)
+			test.expected.PackageURL = test.purl
+			assert.Equal(t, test.expected, component)
+		})
+	}
+
+	t.Run("unsupported type", func(t *testing.T) {
+		_, err := ComponentFromPurl("pkg:github/SAP/jenkins-library@v1.0.0")
+		assert.EqualError(t, err, "package type 'github' is not supported")
+	})
+}
+
+func TestComponentsFromBOM(t *testing.T) {
+	bom := cdx.NewBOM()
+	bom.Components = &[]cdx.Component{
+		{Name: "lodash", PackageURL: "pkg:npm/lodash@4.17.11", Components: &[]cdx.Component{
+			{Name: "log4j-core", PackageURL: "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1"},
+		}},
+		{Name: "no purl"},
+		{Name: "no version", PackageURL: "pkg:npm/left-pad"},
+		{Name: "unsupported", PackageURL: "pkg:github/SAP/jenkins-library@v1.0.0"},
+	}
+
+	components := ComponentsFromBOM(bom)
+
+	assert.Len(t, components, 2)
+	assert.Equal(t, []string{"Maven", "npm"}, Ecosystems(components))
+}
+
+func TestMatch(t *testing.T) {
+	db := NewDatabase()
+	assert.NoError(t, db.LoadDirectory("testdata", &piperutils.Files{}))
+
+	components := []Component{}
+	for _, purl := range []string{
+		"pkg:npm/lodash@4.17.11",
+		"pkg:npm/lodash-es@4.17.11",
+		"pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1",
+		"pkg:maven/org.apache.logging.log4j/log4j-api@2.14.1",
+		"pkg:pypi/pygments@2.7.3",
+	} {
+		component, err := ComponentFromPurl(purl)
+		assert.NoError(t, err)
+		components = append(components, component)
+	}
+
+	findings := db.Match(components)
+
+	// pygments is reported only once although listed by two advisories
+	assert.Len(t, findings, 3)
+	assert.Equal(t, "GHSA-jfh8-c2jp-5v3q", findings[0].Vulnerability.ID)
+	assert.Equal(t, 10.0, findings[0].Score)
+	assert.Equal(t, []string{"2.15.0"}, findings[0].FixedVersions)
+	assert.Equal(t, "GHSA-jf85-cpcp-j695", findings[1].Vulnerability.ID)
+	assert.Equal(t, "pkg:npm/lodash@4.17.11", findings[1].Component.PackageURL)
+	assert.Equal(t, 9.1, findings[1].Score)
+	assert.Equal(t, "pkg:pypi/pygments@2.7.3", findings[2].Component.PackageURL)
+	assert.Equal(t, "critical", findings[1].Severity())
+}
+
+func TestMatchDistributionReleases(t *testing.T) {
+	db := NewDatabase()
+	db.Add(Vulnerability{ID: "DSA-0001-1", Affected: []Affected{
+		{Package: Package{Ecosystem: "Debian:10", Name: "openssl"}, Ranges: []Range{{Type: "ECOSYSTEM", Events: []Event{{Introduced: "0"}, {Fixed: "1.1.1n-0+deb10u4"}}}}},
+		{Package: Package{Ecosystem: "Debian:11", Name: "openssl"}, Ranges: []Range{{Type: "ECOSYSTEM", Events: []Event{{Introduced: "0"}, {Fixed: "1.1.1n-0+deb11u10"}}}}},
+	}})
+	db.Add(Vulnerability{ID: "ALPINE-CVE-0002", Affected: []Affected{
+		{Package: Package{Ecosystem: "Alpine:v3.16", Name: "curl"}, Ranges: []Range{{Type: "ECOSYSTEM", Events: []Event{{Introduced: "0"}, {Fixed: "7.83.1-r10"}}}}},
+	}})
+
+	components := []Component{
+		{Ecosystem: "Debian:11", Name: "openssl", Version: "1.1.1n-0+deb11u9", PackageURL: "pkg:deb/debian/openssl@1.1.1n-0+deb11u9"},
+		{Ecosystem: "Debian:10", Name: "openssl", Version: "1.1.1n-0+deb10u5", PackageURL: "pkg:deb/debian/openssl@1.1.1n-0+deb10u5"},
+		{Ecosystem: "Debian:12", Name: "openssl", Version: "1.1.1n-0+deb11u9", PackageURL: "pkg:deb/debian/openssl@1.1.1n-0+deb12u1"},
+		{Ecosystem: "Alpine:v3.16", Name: "curl", Version: "7.83.1-r9", PackageURL: "pkg:apk/alpine/curl@7.83.1-r9"},
+		{Ecosystem: "Alpine:v3.17", Name: "curl", Version: "7.83.1-r9", PackageURL: "pkg:apk/alpine/curl@7.83.1-r9?distro=alpine-3.17"},
+	}
+
+	findings := db.Match(components)
+
+	if assert.Len(t, findings, 2) {
+		assert.Equal(t, "pkg:apk/alpine/curl@7.83.1-r9", findings[0].Component.PackageURL)
+		assert.Equal(t, []string{"7.83.1-r10"}, findings[0].FixedVersions)
+		assert.Equal(t, "pkg:deb/debian/openssl@1.1.1n-0+deb11u9", findings[1].Component.PackageURL)
+		assert.Equal(t, []string{"1.1.1n-0+deb11u10"}, findings[1].FixedVersions)
+	}
+	assert.Equal(t, []string{"Alpine", "Debian"}, Ecosystems(components))
+
+	t.Run("component without release", func(t *testing.T) {
+		findings := db.Match([]Component{{Ecosystem: "Debian", Name: "openssl", Version: "1.1.1n-0+deb10u3", PackageURL: "pkg:deb/debian/openssl@1.1.1n-0+deb10u3"}})
+
+		assert.Len(t, findings, 1)
+	})
+}
+
+func TestApplyAssessments(t *testing.T) {
+	findings := []Finding{
+		{Component: Component{PackageURL: "pkg:npm/lodash@4.17.11"}, Vulnerability: &Vulnerability{ID: "GHSA-jf85-cpcp-j695", Aliases: []string{"CVE-2019-10744"}}},
+		{Component: Component{PackageURL: "pkg:npm/lodash-es@4.17.11"}, Vulnerability: &Vulnerability{ID: "GHSA-jf85-cpcp-j695", Aliases: []string{"CVE-2019-10744"}}},
+	}
+
+	t.Run("success", func(t *testing.T) {
+		assessments := []format.Assessment{
+			{Vulnerability: "CVE-2019-10744", Status: format.NotRelevant, Analysis: format.NotUsed, Purls: []format.Purl{{Purl: "pkg:npm/lodash@4.17.11"}}},
+		}
+
+		err := ApplyAssessments(findings, assessments)
+
+		assert.NoError(t, err)
+		assert.True(t, findings[0].IsAssessed())
+		assert.False(t, findings[1].IsAssessed())
+	})
+
+	t.Run("invalid purl", func(t *testing.T) {
+		assessments := []format.Assessment{{Vulnerability: "CVE-2019-10744", Purls: []format.Purl{{Purl: "lodash"}}}}
+
+		err := ApplyAssessments(findings, assessments)
+
+		assert.Contains(t, err.Error(), "invalid package URL 'lodash' in assessment of CVE-2019-10744")
+	})
+}
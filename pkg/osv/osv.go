@@ -0,0 +1,296 @@
+package osv
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/pkg/errors"
+)
+
+// Vulnerability contains the relevant parts of an entry in the OSV format, see https://ossf.github.io/osv-schema/
+type Vulnerability struct {
+	ID               string                 `json:"id"`
+	Modified         string                 `json:"modified,omitempty"`
+	Published        string                 `json:"published,omitempty"`
+	Withdrawn        string                 `json:"withdrawn,omitempty"`
+	Aliases          []string               `json:"aliases,omitempty"`
+	Summary          string                 `json:"summary,omitempty"`
+	Details          string                 `json:"details,omitempty"`
+	Severity         []Severity             `json:"severity,omitempty"`
+	Affected         []Affected             `json:"affected,omitempty"`
+	References       []Reference            `json:"references,omitempty"`
+	DatabaseSpecific map[string]interface{} `json:"database_specific,omitempty"`
+}
+
+// Severity contains a severity score of a vulnerability, e.g. a CVSS vector
+type Severity struct {
+	Type  string `json:"type"`
+	Score string `json:"score"`
+}
+
+// Affected describes a package affected by a vulnerability
+type Affected struct {
+	Package          Package                `json:"package"`
+	Ranges           []Range                `json:"ranges,omitempty"`
+	Versions         []string               `json:"versions,omitempty"`
+	DatabaseSpecific map[string]interface{} `json:"database_specific,omitempty"`
+}
+
+// Package identifies a package within an ecosystem
+type Package struct {
+	Ecosystem string `json:"ecosystem"`
+	Name      string `json:"name"`
+	Purl      string `json:"purl,omitempty"`
+}
+
+// Range describes a range of affected versions via events
+type Range struct {
+	Type   string  `json:"type"`
+	Events []Event `json:"events"`
+}
+
+// Event marks the start or the end of an affected version range
+type Event struct {
+	Introduced   string `json:"introduced,omitempty"`
+	Fixed        string `json:"fixed,omitempty"`
+	LastAffected string `json:"last_affected,omitempty"`
+	Limit        string `json:"limit,omitempty"`
+}
+
+// Reference contains a link to further information
+type Reference struct {
+	Type string `json:"type"`
+	URL  string `json:"url"`
+}
+
+// Database contains the vulnerabilities indexed by ecosystem and package name
+type Database struct {
+	entries map[string][]*Vulnerability
+	count   int
+}
+
+// FileUtils abstracts the file system access needed to load a database
+type FileUtils interface {
+	Glob(pattern string) ([]string, error)
+	FileRead(path string) ([]byte, error)
+}
+
+// NewDatabase creates an empty database
+func NewDatabase() *Database {
+	return &Database{entries: map[string][]*Vulnerability{}}
+}
+
+func packageKey(ecosystem, name string) string {
+	return strings.ToLower(ecosystem) + "|" + strings.ToLower(name)
+}
+
+// baseEcosystem removes the release from the ecosystem of a Linux distribution, e.g. "Debian:11" becomes "Debian"
+func baseEcosystem(ecosystem string) string {
+	return strings.SplitN(ecosystem, ":", 2)[0]
+}
+
+// matchesEcosystem checks whether a package of the ecosystem of a component is affected by an entry of the given ecosystem.
+// Components of a distribution without release are checked against the entries of all releases.
+func matchesEcosystem(affectedEcosystem, componentEcosystem string) bool {
+	if strings.EqualFold(affectedEcosystem, componentEcosystem) {
+		return true
+	}
+	return baseEcosystem(componentEcosystem) == componentEcosystem && strings.EqualFold(baseEcosystem(affectedEcosystem), componentEcosystem)
+}
+
+// Add adds a vulnerability to the database, withdrawn entries are ignored
+func (db *Database) Add(vulnerability Vulnerability) {
+	if len(vulnerability.Withdrawn) > 0 {
+		return
+	}
+	v := vulnerability
+	added := map[string]bool{}
+	for _, affected := range v.Affected {
+		keys := []string{packageKey(affected.Package.Ecosystem, affected.Package.Name)}
+		if base := baseEcosystem(affected.Package.Ecosystem); base != affected.Package.Ecosystem {
+			keys = append(keys, packageKey(base, affected.Package.Name))
+		}
+		for _, key := range keys {
+			if added[key] {
+				continue
+			}
+			added[key] = true
+			db.entries[key] = append(db.entries[key], &v)
+		}
+	}
+	db.count++
+}
+
+// Count returns the number of vulnerabilities contained in the database
+func (db *Database) Count() int {
+	return db.count
+}
+
+// Lookup returns all vulnerabilities affecting any version of the given package. The ecosystem of a Linux distribution
+// may contain a release, e.g. "Debian:11", without release the vulnerabilities of all releases are returned.
+func (db *Database) Lookup(ecosystem, name string) []*Vulnerability {
+	return db.entries[packageKey(ecosystem, name)]
+}
+
+// LoadDirectory loads all OSV entries (*.json) and OSV archives (*.zip, e.g. all.zip of the OSV GCS bucket) contained in a directory
+func (db *Database) LoadDirectory(path string, utils FileUtils) error {
+	files, err := utils.Glob(filepath.Join(path, "**", "*.json"))
+	if err != nil {
+		return errors.Wrapf(err, "failed to search OSV entries in '%v'", path)
+	}
+	for _, file := range files {
+		content, err := utils.FileRead(file)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read OSV entry '%v'", file)
+		}
+		if err := db.loadEntry(content); err != nil {
+			return errors.Wrapf(err, "failed to parse OSV entry '%v'", file)
+		}
+	}
+
+	archives, err := utils.Glob(filepath.Join(path, "**", "*.zip"))
+	if err != nil {
+		return errors.Wrapf(err, "failed to search OSV archives in '%v'", path)
+	}
+	for _, archive := range archives {
+		content, err := utils.FileRead(archive)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read OSV archive '%v'", archive)
+		}
+		if err := db.LoadArchive(content); err != nil {
+			return errors.Wrapf(err, "failed to load OSV archive '%v'", archive)
+		}
+	}
+	return nil
+}
+
+// LoadArchive loads all OSV entries contained in a zip archive
+func (db *Database) LoadArchive(content []byte) error {
+	reader, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return err
+	}
+	for _, file := range reader.File {
+		if file.FileInfo().IsDir() || !strings.HasSuffix(file.Name, ".json") {
+			continue
+		}
+		entry, err := file.Open()
+		if err != nil {
+			return err
+		}
+		entryContent, err := io.ReadAll(entry)
+		entry.Close()
+		if err != nil {
+			return err
+		}
+		if err := db.loadEntry(entryContent); err != nil {
+			return errors.Wrapf(err, "failed to parse OSV entry '%v'", file.Name)
+		}
+	}
+	return nil
+}
+
+// LoadMirror downloads the archives of the given ecosystems from an HTTP mirror of the OSV database.
+// The mirror needs to follow the layout of the OSV GCS bucket, i.e. <mirrorURL>/<ecosystem>/all.zip.
+func (db *Database) LoadMirror(mirrorURL string, ecosystems []string, client piperhttp.Sender) error {
+	for _, ecosystem := range ecosystems {
+		archiveURL := fmt.Sprintf("%v/%v/all.zip", strings.TrimSuffix(mirrorURL, "/"), ecosystem)
+		log.Entry().Infof("Downloading OSV data from '%v'", archiveURL)
+		response, err := client.SendRequest(http.MethodGet, archiveURL, nil, nil, nil)
+		if response != nil && response.StatusCode == http.StatusNotFound {
+			log.Entry().Warningf("No OSV data available for ecosystem '%v'", ecosystem)
+			continue
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to download OSV data from '%v'", archiveURL)
+		}
+		content, err := io.ReadAll(response.Body)
+		response.Body.Close()
+		if err != nil {
+			return errors.Wrapf(err, "failed to read OSV data from '%v'", archiveURL)
+		}
+		if err := db.LoadArchive(content); err != nil {
+			return errors.Wrapf(err, "failed to load OSV data from '%v'", archiveURL)
+		}
+	}
+	return nil
+}
+
+func (db *Database) loadEntry(content []byte) error {
+	vulnerability := Vulnerability{}
+	if err := json.Unmarshal(content, &vulnerability); err != nil {
+		return err
+	}
+	if len(vulnerability.ID) == 0 {
+		return errors.New("entry does not contain an id")
+	}
+	db.Add(vulnerability)
+	return nil
+}
+
+// IsAffected checks whether the given version of the package is affected by the vulnerability.
+// Versions are compared following the rules of the ecosystem of the package.
+func (a Affected) IsAffected(version string) bool {
+	for _, affectedVersion := range a.Versions {
+		if affectedVersion == version {
+			return true
+		}
+	}
+	compare := versionComparison(a.Package.Ecosystem)
+	for _, r := range a.Ranges {
+		if r.Type == "GIT" {
+			// commit ranges cannot be evaluated on package versions
+			continue
+		}
+		if r.contains(version, compare) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r Range) contains(version string, compare func(a, b string) int) bool {
+	affected := false
+	for _, event := range sortEvents(r.Events, compare) {
+		switch {
+		case len(event.Introduced) > 0:
+			if event.Introduced == "0" || compare(version, event.Introduced) >= 0 {
+				affected = true
+			}
+		case len(event.Fixed) > 0:
+			if compare(version, event.Fixed) >= 0 {
+				affected = false
+			}
+		case len(event.LastAffected) > 0:
+			if compare(version, event.LastAffected) > 0 {
+				affected = false
+			}
+		case len(event.Limit) > 0:
+			if compare(version, event.Limit) >= 0 {
+				affected = false
+			}
+		}
+	}
+	return affected
+}
+
+// FixedVersions returns the versions fixing the vulnerability for the package
+func (a Affected) FixedVersions() []string {
+	fixed := []string{}
+	for _, r := range a.Ranges {
+		for _, event := range r.Events {
+			if len(event.Fixed) > 0 && r.Type != "GIT" {
+				fixed = append(fixed, event.Fixed)
+			}
+		}
+	}
+	return fixed
+}
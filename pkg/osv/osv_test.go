@@ -0,0 +1,145 @@
+package osv
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"net/http"
+	"testing"
+
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type httpMock struct {
+	responses map[string][]byte
+	requested []string
+}
+
+func (c *httpMock) SetOptions(options piperhttp.ClientOptions) {}
+
+func (c *httpMock) SendRequest(method string, url string, r io.Reader, header http.Header, cookies []*http.Cookie) (*http.Response, error) {
+	c.requested = append(c.requested, url)
+	content, ok := c.responses[url]
+	if !ok {
+		return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(bytes.NewReader([]byte{}))}, errors.New("not found")
+	}
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(content))}, nil
+}
+
+func createArchive(t *testing.T, entries map[string]string) []byte {
+	buffer := bytes.Buffer{}
+	writer := zip.NewWriter(&buffer)
+	for name, content := range entries {
+		file, err := writer.Create(name)
+		assert.NoError(t, err)
+		_, err = file.Write([]byte(content))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, writer.Close())
+	return buffer.Bytes()
+}
+
+const log4jEntry = `{"id": "GHSA-jfh8-c2jp-5v3q", "affected": [{"package": {"ecosystem": "Maven", "name": "org.apache.logging.log4j:log4j-core"}, "ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "2.0-beta9"}, {"fixed": "2.15.0"}]}]}]}`
+
+func TestLoadDirectory(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		db := NewDatabase()
+		err := db.LoadDirectory("testdata", &piperutils.Files{})
+
+		assert.NoError(t, err)
+		// withdrawn entry is ignored
+		assert.Equal(t, 4, db.Count())
+		assert.Len(t, db.Lookup("npm", "lodash"), 1)
+		assert.Len(t, db.Lookup("npm", "lodash-es"), 1)
+		assert.Len(t, db.Lookup("PyPI", "pygments"), 2)
+		assert.Len(t, db.Lookup("Maven", "org.apache.logging.log4j:log4j-core"), 1)
+	})
+
+	t.Run("archive", func(t *testing.T) {
+		utils := mock.FilesMock{}
+		utils.AddFile("osv/Maven/all.zip", createArchive(t, map[string]string{"GHSA-jfh8-c2jp-5v3q.json": log4jEntry}))
+
+		db := NewDatabase()
+		err := db.LoadDirectory("osv", &utils)
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, db.Count())
+	})
+
+	t.Run("invalid entry", func(t *testing.T) {
+		utils := mock.FilesMock{}
+		utils.AddFile("osv/entry.json", []byte(`{"summary": "no id"}`))
+
+		err := NewDatabase().LoadDirectory("osv", &utils)
+
+		assert.EqualError(t, err, "failed to parse OSV entry 'osv/entry.json': entry does not contain an id")
+	})
+}
+
+func TestLoadMirror(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		client := httpMock{responses: map[string][]byte{
+			"https://osv.example.com/Maven/all.zip": createArchive(t, map[string]string{"GHSA-jfh8-c2jp-5v3q.json": log4jEntry}),
+		}}
+
+		db := NewDatabase()
+		err := db.LoadMirror("https://osv.example.com/", []string{"Maven", "npm"}, &client)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"https://osv.example.com/Maven/all.zip", "https://osv.example.com/npm/all.zip"}, client.requested)
+		assert.Equal(t, 1, db.Count())
+	})
+
+	t.Run("invalid archive", func(t *testing.T) {
+		client := httpMock{responses: map[string][]byte{"https://osv.example.com/npm/all.zip": []byte("no zip")}}
+
+		err := NewDatabase().LoadMirror("https://osv.example.com", []string{"npm"}, &client)
+
+		assert.Contains(t, err.Error(), "failed to load OSV data from 'https://osv.example.com/npm/all.zip'")
+	})
+}
+
+func TestIsAffected(t *testing.T) {
+	affected := Affected{
+		Ranges: []Range{
+			{Type: "ECOSYSTEM", Events: []Event{{Introduced: "2.0-beta9"}, {Fixed: "2.3.1"}, {Introduced: "2.4"}, {Fixed: "2.12.2"}, {Introduced: "2.13.0"}, {Fixed: "2.15.0"}}},
+			{Type: "GIT", Events: []Event{{Introduced: "0"}, {Fixed: "abcdef"}}},
+		},
+		Versions: []string{"1.2.17-custom"},
+	}
+
+	tt := []struct {
+		version  string
+		expected bool
+	}{
+		{version: "1.2.17", expected: false},
+		{version: "1.2.17-custom", expected: true},
+		{version: "2.0-beta9", expected: true},
+		{version: "2.3.0", expected: true},
+		{version: "2.3.1", expected: false},
+		{version: "2.12.1", expected: true},
+		{version: "2.12.4", expected: false},
+		{version: "2.14.1", expected: true},
+		{version: "2.15.0", expected: false},
+		{version: "2.17.1", expected: false},
+	}
+	for _, test := range tt {
+		t.Run(test.version, func(t *testing.T) {
+			assert.Equal(t, test.expected, affected.IsAffected(test.version))
+		})
+	}
+
+	t.Run("last affected", func(t *testing.T) {
+		affected := Affected{Ranges: []Range{{Type: "SEMVER", Events: []Event{{Introduced: "0"}, {LastAffected: "1.4.0"}}}}}
+		assert.True(t, affected.IsAffected("1.4.0"))
+		assert.False(t, affected.IsAffected("1.4.1"))
+	})
+
+	t.Run("fixed versions", func(t *testing.T) {
+		assert.Equal(t, []string{"2.3.1", "2.12.2", "2.15.0"}, affected.FixedVersions())
+	})
+}
@@ -0,0 +1,252 @@
+package osv
+
+import (
+	"encoding/base64"
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/reporting"
+)
+
+// Link returns the link to the vulnerability in the OSV database
+func (v Vulnerability) Link() string {
+	return fmt.Sprintf("https://osv.dev/vulnerability/%v", v.ID)
+}
+
+// Description returns the details of the vulnerability, or its summary if no details are available
+func (v Vulnerability) Description() string {
+	if len(v.Details) > 0 {
+		return v.Details
+	}
+	return v.Summary
+}
+
+// Resolution returns the fix recommendation for the finding
+func (f Finding) Resolution() string {
+	if len(f.FixedVersions) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("Upgrade to version %v", strings.Join(f.FixedVersions, " or "))
+}
+
+// IsSevere checks whether the score of the finding reaches the CVSS severity limit, a negative limit disables the check
+func (f Finding) IsSevere(cvssSeverityLimit float64) bool {
+	return cvssSeverityLimit >= 0 && f.Score >= cvssSeverityLimit
+}
+
+// Title returns the issue title representation of the contents
+func (f Finding) Title() string {
+	return fmt.Sprintf("Security Vulnerability %v %v", f.Vulnerability.ID, f.Component.Name)
+}
+
+// ToMarkdown returns the markdown representation of the contents
+func (f Finding) ToMarkdown() ([]byte, error) {
+	vul := reporting.VulnerabilityReport{
+		ArtifactID:        f.Component.Name,
+		Description:       f.Vulnerability.Description(),
+		Origin:            f.Component.Ecosystem,
+		PublishDate:       f.Vulnerability.Published,
+		Resolution:        f.Resolution(),
+		Score:             f.Score,
+		Severity:          f.Severity(),
+		Version:           f.Component.Version,
+		PackageURL:        f.Component.PackageURL,
+		VulnerabilityLink: f.Vulnerability.Link(),
+		VulnerabilityName: f.Vulnerability.ID,
+	}
+	return vul.ToMarkdown()
+}
+
+// ToTxt returns the textual representation of the contents
+func (f Finding) ToTxt() string {
+	return fmt.Sprintf(`Vulnerability %v
+Aliases: %v
+Severity: %v
+Score: %v
+Package: %v
+Installed Version: %v
+Package URL: %v
+Description: %v
+Fix Resolution: %v
+Link: [%v](%v)`,
+		f.Vulnerability.ID,
+		strings.Join(f.Vulnerability.Aliases, ", "),
+		f.Severity(),
+		f.Score,
+		f.Component.Name,
+		f.Component.Version,
+		f.Component.PackageURL,
+		f.Vulnerability.Description(),
+		f.Resolution(),
+		f.Vulnerability.ID,
+		f.Vulnerability.Link(),
+	)
+}
+
+// CountSevereFindings counts the findings reaching the CVSS severity limit which have not been assessed
+func CountSevereFindings(findings []Finding, cvssSeverityLimit float64) int {
+	severe := 0
+	for _, finding := range findings {
+		if !finding.IsAssessed() && finding.IsSevere(cvssSeverityLimit) {
+			severe++
+		}
+	}
+	return severe
+}
+
+// CreateScanReport creates a vulnerability ScanReport to be used for uploading into various sinks
+func CreateScanReport(bomFiles []string, databaseSize int, findings []Finding, cvssSeverityLimit float64) reporting.ScanReport {
+	severe := CountSevereFindings(findings, cvssSeverityLimit)
+	assessed := 0
+	for _, finding := range findings {
+		if finding.IsAssessed() {
+			assessed++
+		}
+	}
+
+	scanReport := reporting.ScanReport{
+		ReportTitle: "OSV Security Vulnerability Report",
+		Subheaders: []reporting.Subheader{
+			{Description: "Scanned BOMs", Details: strings.Join(bomFiles, ", ")},
+			{Description: "Vulnerabilities in OSV database", Details: fmt.Sprint(databaseSize)},
+		},
+		Overview: []reporting.OverviewRow{
+			{Description: "Total number of vulnerabilities", Details: fmt.Sprint(len(findings))},
+			{Description: fmt.Sprintf("Total number of vulnerabilities with CVSS score >= %v", cvssSeverityLimit), Details: fmt.Sprint(severe)},
+			{Description: "Total number of assessed vulnerabilities", Details: fmt.Sprint(assessed)},
+		},
+		SuccessfulScan: severe == 0,
+		ReportTime:     time.Now(),
+	}
+
+	detailTable := reporting.ScanDetailTable{
+		NoRowsMessage: "No publicly known vulnerabilities detected",
+		Headers: []string{
+			"Vulnerability",
+			"Aliases",
+			"Score",
+			"Severity",
+			"Package",
+			"Version",
+			"Package URL",
+			"Summary",
+			"Fixed versions",
+			"Assessment",
+		},
+		WithCounter:   true,
+		CounterHeader: "Entry #",
+	}
+
+	for _, finding := range findings {
+		var scoreStyle reporting.ColumnStyle = reporting.Yellow
+		if finding.IsSevere(cvssSeverityLimit) && !finding.IsAssessed() {
+			scoreStyle = reporting.Red
+		}
+		var assessment string
+		if finding.Assessment != nil {
+			assessment = fmt.Sprintf("%v (%v)", finding.Assessment.Status, finding.Assessment.Analysis)
+		}
+
+		row := reporting.ScanRow{}
+		row.AddColumn(fmt.Sprintf(`<a href="%v">%v</a>`, finding.Vulnerability.Link(), finding.Vulnerability.ID), 0)
+		row.AddColumn(strings.Join(finding.Vulnerability.Aliases, ", "), 0)
+		row.AddColumn(finding.Score, scoreStyle)
+		row.AddColumn(finding.Severity(), 0)
+		row.AddColumn(finding.Component.Name, 0)
+		row.AddColumn(finding.Component.Version, 0)
+		row.AddColumn(finding.Component.PackageURL, 0)
+		row.AddColumn(finding.Vulnerability.Summary, 0)
+		row.AddColumn(strings.Join(finding.FixedVersions, ", "), 0)
+		row.AddColumn(assessment, 0)
+
+		detailTable.Rows = append(detailTable.Rows, row)
+	}
+	scanReport.DetailTable = detailTable
+
+	return scanReport
+}
+
+// CreateSarif creates a SARIF result from the findings, the location of the affected component is used as location of the result
+func CreateSarif(findings []Finding) *format.SARIF {
+	log.Entry().Debug("Creating SARIF file for data transfer")
+	sarif := format.SARIF{
+		Schema:  "https://docs.oasis-open.org/sarif/sarif/v2.1.0/cos02/schemas/sarif-schema-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []format.Runs{{}},
+	}
+
+	tool := format.Tool{Driver: format.Driver{
+		Name:           "Piper OSV scanner",
+		InformationUri: "https://osv.dev",
+	}}
+
+	collectedRules := []string{}
+	for _, finding := range findings {
+		ruleID := finding.Vulnerability.ID
+		location := finding.Component.Location
+		result := format.Results{
+			RuleID:         ruleID,
+			Level:          sarifLevel(finding.Severity()),
+			Message:        &format.Message{Text: fmt.Sprintf("%v affects %v", ruleID, finding.Component.PackageURL)},
+			AnalysisTarget: &format.ArtifactLocation{URI: location},
+			Locations:      []format.Location{{PhysicalLocation: format.PhysicalLocation{ArtifactLocation: format.ArtifactLocation{URI: location}}}},
+			PartialFingerprints: format.PartialFingerprints{
+				PackageURLPlusCVEHash: base64.URLEncoding.EncodeToString([]byte(fmt.Sprintf("%v+%v", finding.Component.PackageURL, ruleID))),
+			},
+		}
+		if finding.Assessment != nil {
+			result.Properties = &format.SarifProperties{
+				Audited:           true,
+				ToolAuditMessage:  string(finding.Assessment.Analysis),
+				ToolState:         string(finding.Assessment.Status),
+				UnifiedAuditState: string(finding.Assessment.Status),
+			}
+		}
+		sarif.Runs[0].Results = append(sarif.Runs[0].Results, result)
+
+		// only create rule on new vulnerability
+		if !piperutils.ContainsString(collectedRules, ruleID) {
+			collectedRules = append(collectedRules, ruleID)
+			markdown, _ := finding.ToMarkdown()
+			tool.Driver.Rules = append(tool.Driver.Rules, format.SarifRule{
+				ID:                   ruleID,
+				Name:                 ruleID,
+				ShortDescription:     &format.Message{Text: fmt.Sprintf("%v Package %v", ruleID, finding.Component.Name)},
+				FullDescription:      &format.Message{Text: finding.Vulnerability.Description()},
+				DefaultConfiguration: &format.DefaultConfiguration{Level: sarifLevel(finding.Severity())},
+				HelpURI:              finding.Vulnerability.Link(),
+				Help:                 &format.Help{Text: finding.ToTxt(), Markdown: string(markdown)},
+				Properties: &format.SarifRuleProperties{
+					Tags:             append([]string{finding.Component.PackageURL}, finding.Vulnerability.Aliases...),
+					SecuritySeverity: fmt.Sprint(finding.Score),
+					Precision:        "very-high",
+				},
+			})
+		}
+	}
+	sarif.Runs[0].Tool = tool
+
+	conversion := new(format.Conversion)
+	conversion.Tool.Driver.Name = "Piper OSV to SARIF converter"
+	conversion.Tool.Driver.InformationUri = "https://github.com/SAP/jenkins-library"
+	conversion.Invocation.ExecutionSuccessful = true
+	conversion.Invocation.Properties = &format.InvocationProperties{Platform: runtime.GOOS}
+	sarif.Runs[0].Conversion = conversion
+
+	return &sarif
+}
+
+func sarifLevel(severity string) string {
+	switch severity {
+	case "critical", "high":
+		return "error"
+	case "medium", "low":
+		return "warning"
+	}
+	return "none"
+}
@@ -0,0 +1,77 @@
+package osv
+
+import (
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/SAP/jenkins-library/pkg/reporting"
+	"github.com/stretchr/testify/assert"
+)
+
+func testFindings() []Finding {
+	return []Finding{
+		{
+			Component:     Component{Ecosystem: "Maven", Name: "org.apache.logging.log4j:log4j-core", Version: "2.14.1", PackageURL: "pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1", Location: "bom-maven.xml"},
+			Vulnerability: &Vulnerability{ID: "GHSA-jfh8-c2jp-5v3q", Aliases: []string{"CVE-2021-44228"}, Summary: "Remote code injection in Log4j"},
+			Score:         10.0,
+			FixedVersions: []string{"2.15.0"},
+		},
+		{
+			Component:     Component{Ecosystem: "npm", Name: "lodash", Version: "4.17.11", PackageURL: "pkg:npm/lodash@4.17.11"},
+			Vulnerability: &Vulnerability{ID: "GHSA-jf85-cpcp-j695", Aliases: []string{"CVE-2019-10744"}, Details: "Prototype Pollution"},
+			Score:         9.1,
+			Assessment:    &format.Assessment{Vulnerability: "CVE-2019-10744", Status: format.NotRelevant, Analysis: format.NotUsed},
+		},
+		{
+			Component:     Component{Ecosystem: "PyPI", Name: "pygments", Version: "2.7.3", PackageURL: "pkg:pypi/pygments@2.7.3"},
+			Vulnerability: &Vulnerability{ID: "PYSEC-2021-19"},
+			Score:         4.0,
+		},
+	}
+}
+
+func TestFindingIssueDetail(t *testing.T) {
+	finding := testFindings()[0]
+
+	assert.Equal(t, "Security Vulnerability GHSA-jfh8-c2jp-5v3q org.apache.logging.log4j:log4j-core", finding.Title())
+	assert.Contains(t, finding.ToTxt(), "Fix Resolution: Upgrade to version 2.15.0")
+	assert.Contains(t, finding.ToTxt(), "Link: [GHSA-jfh8-c2jp-5v3q](https://osv.dev/vulnerability/GHSA-jfh8-c2jp-5v3q)")
+
+	markdown, err := finding.ToMarkdown()
+	assert.NoError(t, err)
+	assert.Contains(t, string(markdown), "# Critical (10) Vulnerability GHSA-jfh8-c2jp-5v3q - org.apache.logging.log4j:log4j-core")
+	assert.Contains(t, string(markdown), "**Package URL:** pkg:maven/org.apache.logging.log4j/log4j-core@2.14.1")
+}
+
+func TestCreateScanReport(t *testing.T) {
+	findings := testFindings()
+
+	t.Run("severe findings", func(t *testing.T) {
+		scanReport := CreateScanReport([]string{"bom-maven.xml"}, 4, findings, 7.0)
+
+		assert.False(t, scanReport.SuccessfulScan)
+		assert.Equal(t, "1", scanReport.Overview[1].Details)
+		assert.Equal(t, "1", scanReport.Overview[2].Details)
+		assert.Len(t, scanReport.DetailTable.Rows, 3)
+		assert.Equal(t, reporting.ColumnStyle(reporting.Red), scanReport.DetailTable.Rows[0].Columns[2].Style)
+		assert.Equal(t, reporting.ColumnStyle(reporting.Yellow), scanReport.DetailTable.Rows[1].Columns[2].Style)
+	})
+
+	t.Run("severity check disabled", func(t *testing.T) {
+		scanReport := CreateScanReport([]string{"bom-maven.xml"}, 4, findings, -1)
+
+		assert.True(t, scanReport.SuccessfulScan)
+	})
+}
+
+func TestCreateSarif(t *testing.T) {
+	sarif := CreateSarif(testFindings())
+
+	assert.Len(t, sarif.Runs[0].Results, 3)
+	assert.Len(t, sarif.Runs[0].Tool.Driver.Rules, 3)
+	assert.Equal(t, "error", sarif.Runs[0].Results[0].Level)
+	assert.Equal(t, "bom-maven.xml", sarif.Runs[0].Results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.True(t, sarif.Runs[0].Results[1].Properties.Audited)
+	assert.Equal(t, "warning", sarif.Runs[0].Results[2].Level)
+	assert.Equal(t, "10", sarif.Runs[0].Tool.Driver.Rules[0].Properties.SecuritySeverity)
+}
@@ -0,0 +1,663 @@
+package osv
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// CompareVersions compares two versions and returns -1, 0 or 1.
+// It follows the semantic versioning rules and is lenient enough for the versioning schemes
+// of most ecosystems (e.g. Maven, PyPI, npm, Go): numeric segments are compared numerically,
+// a pre-release (e.g. 1.0.0-rc.1, 1.0.0rc1) is lower than the release and build metadata is ignored.
+func CompareVersions(a, b string) int {
+	mainA, preA := splitVersion(a)
+	mainB, preB := splitVersion(b)
+
+	if result := compareSegments(mainA, mainB); result != 0 {
+		return result
+	}
+	switch {
+	case len(preA) == 0 && len(preB) == 0:
+		return 0
+	case len(preA) == 0:
+		return 1
+	case len(preB) == 0:
+		return -1
+	}
+	return compareSegments(preA, preB)
+}
+
+func splitVersion(version string) ([]string, []string) {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if index := strings.Index(version, "+"); index >= 0 {
+		version = version[:index]
+	}
+	main, pre := version, ""
+	if index := strings.IndexAny(version, "-~"); index >= 0 {
+		main, pre = version[:index], version[index+1:]
+	}
+	mainSegments := []string{}
+	for _, segment := range segments(main) {
+		// release qualifiers, e.g. 5.3.0.RELEASE or 1.0.0.Final, are equal to the plain release
+		if qualifier := strings.ToLower(segment); qualifier != "final" && qualifier != "release" && qualifier != "ga" {
+			mainSegments = append(mainSegments, segment)
+		}
+	}
+	// pre-releases without separator, e.g. 1.0.0rc1 or 1.0.0.beta1
+	for i, segment := range mainSegments {
+		if !isNumeric(segment) && i > 0 {
+			preSegments := append(mainSegments[i:], segments(pre)...)
+			return mainSegments[:i], preSegments
+		}
+	}
+	return mainSegments, segments(pre)
+}
+
+// segments splits a version at separators and at transitions between digits and letters
+func segments(version string) []string {
+	result := []string{}
+	current := strings.Builder{}
+	lastDigit := false
+	for _, r := range version {
+		if r == '.' || r == '-' || r == '_' || r == '~' {
+			if current.Len() > 0 {
+				result = append(result, current.String())
+				current.Reset()
+			}
+			continue
+		}
+		digit := unicode.IsDigit(r)
+		if current.Len() > 0 && digit != lastDigit {
+			result = append(result, current.String())
+			current.Reset()
+		}
+		current.WriteRune(r)
+		lastDigit = digit
+	}
+	if current.Len() > 0 {
+		result = append(result, current.String())
+	}
+	return result
+}
+
+func compareSegments(a, b []string) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var segmentA, segmentB string
+		if i < len(a) {
+			segmentA = a[i]
+		}
+		if i < len(b) {
+			segmentB = b[i]
+		}
+		if result := compareSegment(segmentA, segmentB); result != 0 {
+			return result
+		}
+	}
+	return 0
+}
+
+func compareSegment(a, b string) int {
+	if a == b {
+		return 0
+	}
+	// missing segments count as zero, i.e. 1.0 == 1.0.0
+	if len(a) == 0 {
+		a = "0"
+	}
+	if len(b) == 0 {
+		b = "0"
+	}
+	numericA, numericB := isNumeric(a), isNumeric(b)
+	switch {
+	case numericA && numericB:
+		// compare without conversion to support arbitrary long numbers, e.g. timestamps
+		a, b = strings.TrimLeft(a, "0"), strings.TrimLeft(b, "0")
+		if len(a) != len(b) {
+			if len(a) < len(b) {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a, b)
+	case numericA:
+		return 1
+	case numericB:
+		return -1
+	}
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
+
+func isNumeric(segment string) bool {
+	if len(segment) == 0 {
+		return false
+	}
+	for _, r := range segment {
+		if !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// versionComparison returns the comparison of versions of the ecosystem, e.g. following the rules of dpkg for Debian
+func versionComparison(ecosystem string) func(a, b string) int {
+	switch baseEcosystem(ecosystem) {
+	case "Debian":
+		return compareDebianVersions
+	case "Alpine":
+		return compareAlpineVersions
+	case "PyPI":
+		return comparePythonVersions
+	case "Maven":
+		return compareMavenVersions
+	}
+	return CompareVersions
+}
+
+// compareDebianVersions compares versions following the rules of dpkg, i.e. [epoch:]upstream_version[-debian_revision]
+func compareDebianVersions(a, b string) int {
+	epochA, upstreamA, revisionA := splitDebianVersion(a)
+	epochB, upstreamB, revisionB := splitDebianVersion(b)
+	if result := compareSegment(epochA, epochB); result != 0 {
+		return result
+	}
+	if result := compareDpkgParts(upstreamA, upstreamB); result != 0 {
+		return result
+	}
+	return compareDpkgParts(revisionA, revisionB)
+}
+
+func splitDebianVersion(version string) (string, string, string) {
+	version = strings.TrimSpace(version)
+	epoch := "0"
+	if index := strings.Index(version, ":"); index >= 0 {
+		epoch, version = version[:index], version[index+1:]
+	}
+	if index := strings.LastIndex(version, "-"); index >= 0 {
+		return epoch, version[:index], version[index+1:]
+	}
+	return epoch, version, ""
+}
+
+// compareDpkgParts compares alternating non-digit and digit parts like dpkg, a tilde sorts before anything, even the end of the part
+func compareDpkgParts(a, b string) int {
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		for (i < len(a) && !isDigit(a[i])) || (j < len(b) && !isDigit(b[j])) {
+			orderA, orderB := dpkgOrder(a, i), dpkgOrder(b, j)
+			if orderA != orderB {
+				if orderA < orderB {
+					return -1
+				}
+				return 1
+			}
+			i, j = i+1, j+1
+		}
+		for i < len(a) && a[i] == '0' {
+			i++
+		}
+		for j < len(b) && b[j] == '0' {
+			j++
+		}
+		firstDiff := 0
+		for i < len(a) && isDigit(a[i]) && j < len(b) && isDigit(b[j]) {
+			if firstDiff == 0 && a[i] != b[j] {
+				firstDiff = int(a[i]) - int(b[j])
+			}
+			i, j = i+1, j+1
+		}
+		if i < len(a) && isDigit(a[i]) {
+			return 1
+		}
+		if j < len(b) && isDigit(b[j]) {
+			return -1
+		}
+		if firstDiff != 0 {
+			if firstDiff < 0 {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+func dpkgOrder(part string, index int) int {
+	if index >= len(part) {
+		return 0
+	}
+	c := part[index]
+	switch {
+	case isDigit(c):
+		return 0
+	case unicode.IsLetter(rune(c)):
+		return int(c)
+	case c == '~':
+		return -1
+	}
+	return int(c) + 256
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// alpineSuffixes lists the suffixes of Alpine package versions in ascending order, a version without suffix sorts between _rc and _cvs
+var alpineSuffixes = []string{"alpha", "beta", "pre", "rc", "", "cvs", "svn", "git", "hg", "p"}
+
+type alpineVersion struct {
+	numbers  []string
+	letter   string
+	suffixes []string
+	release  string
+}
+
+// compareAlpineVersions compares versions following the rules of apk, e.g. 1.1.1q_rc1-r2
+func compareAlpineVersions(a, b string) int {
+	versionA, versionB := parseAlpineVersion(a), parseAlpineVersion(b)
+	if result := compareSegments(versionA.numbers, versionB.numbers); result != 0 {
+		return result
+	}
+	if result := strings.Compare(versionA.letter, versionB.letter); result != 0 {
+		return result
+	}
+	for i := 0; i < len(versionA.suffixes) || i < len(versionB.suffixes); i++ {
+		if result := compareAlpineSuffix(versionA.suffixes, versionB.suffixes, i); result != 0 {
+			return result
+		}
+	}
+	return compareSegment(versionA.release, versionB.release)
+}
+
+func parseAlpineVersion(version string) alpineVersion {
+	parsed := alpineVersion{release: "0"}
+	version = strings.TrimSpace(version)
+	if index := strings.LastIndex(version, "-r"); index >= 0 && isNumeric(version[index+2:]) {
+		version, parsed.release = version[:index], version[index+2:]
+	}
+	parts := strings.Split(version, "_")
+	main := parts[0]
+	if length := len(main); length > 0 && unicode.IsLetter(rune(main[length-1])) {
+		main, parsed.letter = main[:length-1], main[length-1:]
+	}
+	parsed.numbers = strings.Split(main, ".")
+	parsed.suffixes = parts[1:]
+	return parsed
+}
+
+func compareAlpineSuffix(a, b []string, index int) int {
+	nameA, numberA := alpineSuffix(a, index)
+	nameB, numberB := alpineSuffix(b, index)
+	rankA, rankB := alpineSuffixRank(nameA), alpineSuffixRank(nameB)
+	if rankA != rankB {
+		if rankA < rankB {
+			return -1
+		}
+		return 1
+	}
+	return compareSegment(numberA, numberB)
+}
+
+func alpineSuffix(suffixes []string, index int) (string, string) {
+	if index >= len(suffixes) {
+		return "", ""
+	}
+	name := strings.TrimRightFunc(suffixes[index], unicode.IsDigit)
+	return name, suffixes[index][len(name):]
+}
+
+func alpineSuffixRank(name string) int {
+	for rank, suffix := range alpineSuffixes {
+		if suffix == name {
+			return rank
+		}
+	}
+	return len(alpineSuffixes)
+}
+
+// pythonVersionPattern matches versions as defined in PEP 440 including the permitted variations of the normalized form
+var pythonVersionPattern = regexp.MustCompile(`^v?(?:(\d+)!)?(\d+(?:\.\d+)*)` +
+	`(?:[-_.]?(a|b|c|rc|alpha|beta|pre|preview)[-_.]?(\d*))?` +
+	`(?:-(\d+)|[-_.]?(post|rev|r)[-_.]?(\d*))?` +
+	`(?:[-_.]?(dev)[-_.]?(\d*))?` +
+	`(?:\+([a-z0-9]+(?:[-_.][a-z0-9]+)*))?$`)
+
+type pythonVersion struct {
+	epoch   string
+	release []string
+	// pre is the phase of the pre-release (a, b or rc) followed by its number
+	pre          []string
+	post, dev    string
+	hasPost      bool
+	hasDev       bool
+	localVersion []string
+}
+
+// comparePythonVersions compares versions following the rules of PEP 440, e.g. 1.0.dev1 < 1.0a1 < 1.0 < 1.0.post1.
+// Versions which do not conform to PEP 440 are compared leniently.
+func comparePythonVersions(a, b string) int {
+	versionA, okA := parsePythonVersion(a)
+	versionB, okB := parsePythonVersion(b)
+	if !okA || !okB {
+		return CompareVersions(a, b)
+	}
+	if result := compareSegment(versionA.epoch, versionB.epoch); result != 0 {
+		return result
+	}
+	if result := compareSegments(versionA.release, versionB.release); result != 0 {
+		return result
+	}
+	if result := comparePythonPreRelease(versionA, versionB); result != 0 {
+		return result
+	}
+	if versionA.hasPost != versionB.hasPost {
+		return boolOrder(versionA.hasPost)
+	}
+	if result := compareSegment(versionA.post, versionB.post); result != 0 {
+		return result
+	}
+	// a development release sorts before the release
+	if versionA.hasDev != versionB.hasDev {
+		return -boolOrder(versionA.hasDev)
+	}
+	if result := compareSegment(versionA.dev, versionB.dev); result != 0 {
+		return result
+	}
+	if len(versionA.localVersion) == 0 || len(versionB.localVersion) == 0 {
+		return compareSegment(strconv.Itoa(len(versionA.localVersion)), strconv.Itoa(len(versionB.localVersion)))
+	}
+	return compareSegments(versionA.localVersion, versionB.localVersion)
+}
+
+func parsePythonVersion(version string) (pythonVersion, bool) {
+	match := pythonVersionPattern.FindStringSubmatch(strings.ToLower(strings.TrimSpace(version)))
+	if match == nil {
+		return pythonVersion{}, false
+	}
+	parsed := pythonVersion{epoch: match[1], release: strings.Split(match[2], "."), dev: match[9], hasDev: len(match[8]) > 0}
+	if len(match[3]) > 0 {
+		phase := map[string]string{"alpha": "a", "beta": "b", "c": "rc", "pre": "rc", "preview": "rc"}[match[3]]
+		if len(phase) == 0 {
+			phase = match[3]
+		}
+		parsed.pre = []string{phase, match[4]}
+	}
+	switch {
+	case len(match[5]) > 0:
+		parsed.hasPost, parsed.post = true, match[5]
+	case len(match[6]) > 0:
+		parsed.hasPost, parsed.post = true, match[7]
+	}
+	if len(match[10]) > 0 {
+		parsed.localVersion = segments(match[10])
+	}
+	return parsed, true
+}
+
+// comparePythonPreRelease orders pre-releases before the release. A development release of the release itself,
+// e.g. 1.0.dev1, sorts before all pre-releases of the release.
+func comparePythonPreRelease(a, b pythonVersion) int {
+	rank := func(version pythonVersion) int {
+		switch {
+		case len(version.pre) > 0:
+			return 1
+		case version.hasDev && !version.hasPost:
+			return 0
+		}
+		return 2
+	}
+	if rankA, rankB := rank(a), rank(b); rankA != rankB {
+		return compareSegment(strconv.Itoa(rankA), strconv.Itoa(rankB))
+	}
+	if len(a.pre) == 0 {
+		return 0
+	}
+	// the phases a, b and rc sort alphabetically
+	if result := strings.Compare(a.pre[0], b.pre[0]); result != 0 {
+		return result
+	}
+	return compareSegment(a.pre[1], b.pre[1])
+}
+
+func boolOrder(value bool) int {
+	if value {
+		return 1
+	}
+	return -1
+}
+
+// mavenQualifiers lists the well-known qualifiers of Maven versions in ascending order, the empty qualifier is the release
+var mavenQualifiers = []string{"alpha", "beta", "milestone", "rc", "snapshot", "", "sp"}
+
+var mavenQualifierAliases = map[string]string{"ga": "", "final": "", "release": "", "cr": "rc"}
+
+// mavenItem is an item of a Maven version like ComparableVersion of Maven defines them, nil is the missing item
+type mavenItem interface {
+	compareTo(other mavenItem) int
+	isNull() bool
+}
+
+type mavenIntItem string
+
+type mavenStringItem string
+
+type mavenListItem []mavenItem
+
+// compareMavenVersions compares versions following the rules of ComparableVersion of Maven,
+// e.g. 1.0-alpha-1 < 1.0-SNAPSHOT < 1.0 = 1.0.Final < 1.0-sp1 < 1.0.1
+func compareMavenVersions(a, b string) int {
+	return parseMavenVersion(a).compareTo(parseMavenVersion(b))
+}
+
+func parseMavenVersion(version string) mavenListItem {
+	version = strings.ToLower(strings.TrimSpace(version))
+	root := &mavenListItem{}
+	list := root
+	stack := []*mavenListItem{root}
+	start := 0
+	digit := false
+	addSubList := func() {
+		sub := &mavenListItem{}
+		*list = append(*list, sub)
+		list = sub
+		stack = append(stack, sub)
+	}
+	for i := 0; i < len(version); i++ {
+		c := version[i]
+		switch {
+		case c == '.' || c == '-':
+			if i == start {
+				*list = append(*list, mavenIntItem("0"))
+			} else {
+				*list = append(*list, parseMavenItem(digit, version[start:i], false))
+			}
+			start = i + 1
+			if c == '-' {
+				addSubList()
+			}
+		case isDigit(c):
+			if !digit && i > start {
+				*list = append(*list, parseMavenItem(false, version[start:i], true))
+				start = i
+				addSubList()
+			}
+			digit = true
+		default:
+			if digit && i > start {
+				*list = append(*list, parseMavenItem(true, version[start:i], false))
+				start = i
+				addSubList()
+			}
+			digit = false
+		}
+	}
+	if len(version) > start {
+		*list = append(*list, parseMavenItem(digit, version[start:], false))
+	}
+	for i := len(stack) - 1; i >= 0; i-- {
+		stack[i].normalize()
+	}
+	return resolveMavenList(root)
+}
+
+func parseMavenItem(digit bool, value string, followedByDigit bool) mavenItem {
+	if digit {
+		return mavenIntItem(strings.TrimLeft(value, "0"))
+	}
+	if followedByDigit && len(value) == 1 {
+		switch value {
+		case "a":
+			value = "alpha"
+		case "b":
+			value = "beta"
+		case "m":
+			value = "milestone"
+		}
+	}
+	if alias, ok := mavenQualifierAliases[value]; ok {
+		value = alias
+	}
+	return mavenStringItem(value)
+}
+
+// normalize removes trailing null items, e.g. 1.0.0 becomes 1
+func (l *mavenListItem) normalize() {
+	for i := len(*l) - 1; i >= 0; i-- {
+		item := (*l)[i]
+		if item.isNull() {
+			*l = append((*l)[:i], (*l)[i+1:]...)
+		} else if _, ok := item.(*mavenListItem); !ok {
+			break
+		}
+	}
+}
+
+// resolveMavenList replaces the pointers used while parsing by values
+func resolveMavenList(list *mavenListItem) mavenListItem {
+	resolved := mavenListItem{}
+	for _, item := range *list {
+		if sub, ok := item.(*mavenListItem); ok {
+			item = resolveMavenList(sub)
+		}
+		resolved = append(resolved, item)
+	}
+	return resolved
+}
+
+func (i mavenIntItem) isNull() bool {
+	return len(i) == 0
+}
+
+func (i mavenIntItem) compareTo(other mavenItem) int {
+	switch o := other.(type) {
+	case nil:
+		if i.isNull() {
+			return 0
+		}
+		return 1
+	case mavenIntItem:
+		return compareSegment(string(i), string(o))
+	}
+	// a number is greater than a qualifier or a list
+	return 1
+}
+
+func (s mavenStringItem) comparable() string {
+	for index, qualifier := range mavenQualifiers {
+		if qualifier == string(s) {
+			return strconv.Itoa(index)
+		}
+	}
+	// unknown qualifiers sort after the known ones
+	return fmt.Sprintf("%v-%v", len(mavenQualifiers), string(s))
+}
+
+func (s mavenStringItem) isNull() bool {
+	return len(s) == 0
+}
+
+func (s mavenStringItem) compareTo(other mavenItem) int {
+	switch o := other.(type) {
+	case nil:
+		return strings.Compare(s.comparable(), mavenStringItem("").comparable())
+	case mavenStringItem:
+		return strings.Compare(s.comparable(), o.comparable())
+	}
+	return -1
+}
+
+func (l mavenListItem) isNull() bool {
+	return len(l) == 0
+}
+
+func (l mavenListItem) compareTo(other mavenItem) int {
+	switch o := other.(type) {
+	case nil:
+		if len(l) == 0 {
+			return 0
+		}
+		return l[0].compareTo(nil)
+	case mavenIntItem:
+		return -1
+	case mavenStringItem:
+		return 1
+	case mavenListItem:
+		for i := 0; i < len(l) || i < len(o); i++ {
+			var left, right mavenItem
+			if i < len(l) {
+				left = l[i]
+			}
+			if i < len(o) {
+				right = o[i]
+			}
+			result := 0
+			switch {
+			case left == nil && right == nil:
+			case left == nil:
+				result = -right.compareTo(nil)
+			default:
+				result = left.compareTo(right)
+			}
+			if result != 0 {
+				return result
+			}
+		}
+	}
+	return 0
+}
+
+func eventVersion(event Event) string {
+	switch {
+	case len(event.Introduced) > 0:
+		return event.Introduced
+	case len(event.Fixed) > 0:
+		return event.Fixed
+	case len(event.LastAffected) > 0:
+		return event.LastAffected
+	}
+	return event.Limit
+}
+
+// sortEvents sorts the events of a range by version, introduced events are handled before other events of the same version
+func sortEvents(events []Event, compare func(a, b string) int) []Event {
+	sorted := make([]Event, len(events))
+	copy(sorted, events)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Introduced == "0" {
+			return sorted[j].Introduced != "0"
+		}
+		if sorted[j].Introduced == "0" {
+			return false
+		}
+		result := compare(eventVersion(sorted[i]), eventVersion(sorted[j]))
+		if result == 0 {
+			return len(sorted[i].Introduced) > 0 && len(sorted[j].Introduced) == 0
+		}
+		return result < 0
+	})
+	return sorted
+}
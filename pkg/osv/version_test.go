@@ -0,0 +1,91 @@
+package osv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareVersions(t *testing.T) {
+	tt := []struct {
+		a, b     string
+		expected int
+	}{
+		{a: "1.0.0", b: "1.0.0", expected: 0},
+		{a: "1.0", b: "1.0.0", expected: 0},
+		{a: "v1.2.3", b: "1.2.3", expected: 0},
+		{a: "1.2.3+build.5", b: "1.2.3", expected: 0},
+		{a: "5.3.0.RELEASE", b: "5.3.0", expected: 0},
+		{a: "1.0.0", b: "1.0.1", expected: -1},
+		{a: "1.10.0", b: "1.9.0", expected: 1},
+		{a: "4.17.21", b: "4.17.12", expected: 1},
+		{a: "1.0.0-rc.1", b: "1.0.0", expected: -1},
+		{a: "1.0.0rc1", b: "1.0.0", expected: -1},
+		{a: "2.0-beta9", b: "2.0", expected: -1},
+		{a: "2.0-beta9", b: "2.0-beta10", expected: -1},
+		{a: "1.0.0-alpha", b: "1.0.0-beta", expected: -1},
+		{a: "1.0.0-alpha.1", b: "1.0.0-alpha", expected: 1},
+		{a: "20230101120000", b: "20221231235959", expected: 1},
+		{a: "99999999999999999999.1", b: "99999999999999999999.0", expected: 1},
+	}
+
+	for _, test := range tt {
+		t.Run(test.a+" vs "+test.b, func(t *testing.T) {
+			assert.Equal(t, test.expected, CompareVersions(test.a, test.b))
+			assert.Equal(t, -test.expected, CompareVersions(test.b, test.a))
+		})
+	}
+}
+
+func TestVersionComparison(t *testing.T) {
+	tt := []struct {
+		ecosystem string
+		a, b      string
+		expected  int
+	}{
+		{ecosystem: "Debian:11", a: "1.1.1n-0+deb11u3", b: "1.1.1n-0+deb11u3", expected: 0},
+		{ecosystem: "Debian:11", a: "1.1.1n-0+deb11u4", b: "1.1.1n-0+deb11u3", expected: 1},
+		{ecosystem: "Debian:11", a: "1:2.0-1", b: "3.0-1", expected: 1},
+		{ecosystem: "Debian:11", a: "2.0~rc1-1", b: "2.0-1", expected: -1},
+		{ecosystem: "Debian:11", a: "2.0-1~bpo11+1", b: "2.0-1", expected: -1},
+		{ecosystem: "Debian:11", a: "7.74.0-1.3+deb11u10", b: "7.74.0-1.3+deb11u9", expected: 1},
+		{ecosystem: "Debian", a: "1.2a-1", b: "1.2-1", expected: 1},
+		{ecosystem: "Alpine:v3.16", a: "7.83.1-r3", b: "7.83.1-r3", expected: 0},
+		{ecosystem: "Alpine:v3.16", a: "7.83.1-r10", b: "7.83.1-r9", expected: 1},
+		{ecosystem: "Alpine:v3.16", a: "1.1.1q-r0", b: "1.1.1p-r5", expected: 1},
+		{ecosystem: "Alpine:v3.16", a: "1.2.0_rc1-r0", b: "1.2.0-r0", expected: -1},
+		{ecosystem: "Alpine:v3.16", a: "1.2.0_p1-r0", b: "1.2.0-r0", expected: 1},
+		{ecosystem: "Alpine:v3.16", a: "1.2.0_alpha2", b: "1.2.0_beta1", expected: -1},
+		{ecosystem: "PyPI", a: "1.0.post1", b: "1.0", expected: 1},
+		{ecosystem: "PyPI", a: "1.0-1", b: "1.0.post1", expected: 0},
+		{ecosystem: "PyPI", a: "1.0rc1", b: "1.0", expected: -1},
+		{ecosystem: "PyPI", a: "1.0a2", b: "1.0b1", expected: -1},
+		{ecosystem: "PyPI", a: "1.0.dev1", b: "1.0a1", expected: -1},
+		{ecosystem: "PyPI", a: "1.0.post1.dev1", b: "1.0.post1", expected: -1},
+		{ecosystem: "PyPI", a: "1.0.post1.dev1", b: "1.0", expected: 1},
+		{ecosystem: "PyPI", a: "1.0+local.1", b: "1.0", expected: 1},
+		{ecosystem: "PyPI", a: "1!0.5", b: "2.0", expected: 1},
+		{ecosystem: "PyPI", a: "1.0.0", b: "1.0", expected: 0},
+		{ecosystem: "Maven", a: "1.0-SP1", b: "1.0", expected: 1},
+		{ecosystem: "Maven", a: "1.0-sp", b: "1.0", expected: 1},
+		{ecosystem: "Maven", a: "1.0.1", b: "1.0-sp1", expected: 1},
+		{ecosystem: "Maven", a: "1.0-RC1", b: "1.0", expected: -1},
+		{ecosystem: "Maven", a: "1.0-cr1", b: "1.0-rc1", expected: 0},
+		{ecosystem: "Maven", a: "1.0-SNAPSHOT", b: "1.0", expected: -1},
+		{ecosystem: "Maven", a: "1.0-alpha-1", b: "1.0-beta-1", expected: -1},
+		{ecosystem: "Maven", a: "1.0-m1", b: "1.0-rc1", expected: -1},
+		{ecosystem: "Maven", a: "1.0.Final", b: "1.0", expected: 0},
+		{ecosystem: "Maven", a: "2.0.0.RELEASE", b: "2.0", expected: 0},
+		{ecosystem: "Maven", a: "1.10", b: "1.9", expected: 1},
+		{ecosystem: "Maven", a: "1-1", b: "1", expected: 1},
+		{ecosystem: "npm", a: "1.10.0", b: "1.9.0", expected: 1},
+	}
+
+	for _, test := range tt {
+		t.Run(test.ecosystem+" "+test.a+" vs "+test.b, func(t *testing.T) {
+			compare := versionComparison(test.ecosystem)
+			assert.Equal(t, test.expected, compare(test.a, test.b))
+			assert.Equal(t, -test.expected, compare(test.b, test.a))
+		})
+	}
+}
@@ -3,6 +3,13 @@ package piperenv
 type Artifact struct {
 	Id   string `json:"id,omitempty"`
 	Name string `json:"name,omitempty"`
+	// Path is the location of the artifact in the workspace
+	Path string `json:"path,omitempty"`
+	// Type describes the artifact, e.g. archive, checksums or signature
+	Type string `json:"type,omitempty"`
+	// Platform is the target platform of the artifact, e.g. linux/amd64
+	Platform string `json:"platform,omitempty"`
+	SHA256   string `json:"sha256,omitempty"`
 }
 
 type Artifacts []Artifact
@@ -0,0 +1,31 @@
+package piperenv
+
+// Project describes a project of a monorepo workspace together with the version determined for the current pipeline run
+type Project struct {
+	Name      string `json:"name,omitempty"`
+	Path      string `json:"path,omitempty"`
+	BuildTool string `json:"buildTool,omitempty"`
+	Version   string `json:"version,omitempty"`
+	// Changed is true if the project or one of its dependencies changed since the last release of the project
+	Changed bool `json:"changed"`
+}
+
+type Projects []Project
+
+// FindChanged returns the changed projects using one of the given build tools, the order of the projects is kept
+func (p Projects) FindChanged(buildTools ...string) Projects {
+	var filtered Projects
+
+	for _, project := range p {
+		if !project.Changed {
+			continue
+		}
+		for _, buildTool := range buildTools {
+			if project.BuildTool == buildTool {
+				filtered = append(filtered, project)
+				break
+			}
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,20 @@
+package piperenv
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Projects_FindChanged(t *testing.T) {
+	projects := Projects([]Project{
+		{Name: "common", BuildTool: "maven", Changed: true},
+		{Name: "web", BuildTool: "npm", Changed: true},
+		{Name: "orders", BuildTool: "maven"},
+		{Name: "payments", BuildTool: "maven", Changed: true},
+	})
+
+	assert.Len(t, projects.FindChanged("golang"), 0)
+	assert.Equal(t, Projects{projects[0], projects[3]}, projects.FindChanged("maven"))
+	assert.Equal(t, Projects{projects[0], projects[1], projects[3]}, projects.FindChanged("maven", "npm"))
+}
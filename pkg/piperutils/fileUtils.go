@@ -12,6 +12,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
@@ -442,6 +443,38 @@ func ExcludeFiles(files, excludes []string) ([]string, error) {
 	return filteredFiles, nil
 }
 
+// fileFinder abstracts the file system access needed to search files, e.g. Files
+type fileFinder interface {
+	Glob(pattern string) (matches []string, err error)
+	DirExists(path string) (bool, error)
+}
+
+// FindFiles returns the sorted files matching any of the glob patterns and none of the excludes. Directories are omitted.
+func FindFiles(patterns, excludes []string, utils fileFinder) ([]string, error) {
+	found := map[string]bool{}
+	for _, pattern := range patterns {
+		matches, err := utils.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search for files with pattern '%v': %w", pattern, err)
+		}
+		for _, match := range matches {
+			if isDir, _ := utils.DirExists(match); !isDir {
+				found[match] = true
+			}
+		}
+	}
+	files := []string{}
+	for file := range found {
+		files = append(files, file)
+	}
+	files, err := ExcludeFiles(files, excludes)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
 // Getwd is a wrapper for os.Getwd().
 func (f Files) Getwd() (string, error) {
 	return os.Getwd()
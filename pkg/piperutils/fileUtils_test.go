@@ -95,6 +95,27 @@ func runInTempDir(t *testing.T, nameOfRun string, run func(t *testing.T)) {
 	})
 }
 
+func TestFindFiles(t *testing.T) {
+	runInTempDir(t, "matching files", func(t *testing.T) {
+		for _, file := range []string{"b/bom.json", "a/bom.xml", "a/bom.json", "node_modules/x/bom.json"} {
+			assert.NoError(t, os.MkdirAll(filepath.Dir(file), 0777))
+			assert.NoError(t, ioutil.WriteFile(file, []byte{}, 0666))
+		}
+		assert.NoError(t, os.MkdirAll(filepath.Join("c", "bom.json"), 0777))
+
+		files, err := FindFiles([]string{"**/bom.json", "**/bom.*"}, []string{"node_modules/**"}, Files{})
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a/bom.json", "a/bom.xml", "b/bom.json"}, files)
+	})
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		_, err := FindFiles([]string{"[a-"}, nil, Files{})
+
+		assert.EqualError(t, err, "failed to search for files with pattern '[a-': syntax error in pattern")
+	})
+}
+
 func TestExcludeFiles(t *testing.T) {
 	t.Parallel()
 	t.Run("nil slices", func(t *testing.T) {
@@ -22,6 +22,11 @@ func (projectStructure *ProjectStructure) UsesNpm() bool {
 	return projectStructure.anyFileExists("package.json")
 }
 
+// UsesGradle returns `true` if the project structure directory contains a Groovy or Kotlin Gradle build script, `false` otherwise
+func (projectStructure *ProjectStructure) UsesGradle() bool {
+	return projectStructure.anyFileExists("build.gradle", "build.gradle.kts")
+}
+
 func (projectStructure *ProjectStructure) anyFileExists(candidates ...string) bool {
 	for i := 0; i < len(candidates); i++ {
 		exists, err := FileExists(filepath.Join(projectStructure.directory, candidates[i]))
@@ -36,6 +36,14 @@ func TestProjectWithOnlyNpmFile(t *testing.T) {
 	assert.True(t, resultNpm)
 }
 
+func TestProjectWithOnlyGradleFile(t *testing.T) {
+	projectStructure := ProjectStructure{directory: "testdata/gradle"}
+	resultGradle := projectStructure.UsesGradle()
+	assert.True(t, resultGradle)
+	resultPom := projectStructure.UsesMaven()
+	assert.False(t, resultPom)
+}
+
 func TestDirectryParameterIsEmptyAndNoProjectFilesAreInIt(t *testing.T) {
 	projectStructure := ProjectStructure{}
 	resultMta := projectStructure.UsesMta()
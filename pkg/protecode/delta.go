@@ -0,0 +1,326 @@
+package protecode
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/reporting"
+)
+
+// DeltaReport contains the differences between the scan results of two products, e.g. two versions of an image
+type DeltaReport struct {
+	ProductID            int          `json:"productId"`
+	PreviousProductID    int          `json:"previousProductId"`
+	NewVulnerabilities   int          `json:"newVulnerabilities"`
+	FixedVulnerabilities int          `json:"fixedVulnerabilities"`
+	AddedComponents      int          `json:"addedComponents"`
+	RemovedComponents    int          `json:"removedComponents"`
+	Layers               []LayerDelta `json:"layers"`
+}
+
+// LayerDelta contains the differences attributed to one image layer.
+// New findings are attributed to the layer of the current image, fixed findings to the layer of the previous image.
+type LayerDelta struct {
+	Layer                string               `json:"layer"`
+	AddedComponents      []DeltaComponent     `json:"addedComponents,omitempty"`
+	RemovedComponents    []DeltaComponent     `json:"removedComponents,omitempty"`
+	NewVulnerabilities   []DeltaVulnerability `json:"newVulnerabilities,omitempty"`
+	FixedVulnerabilities []DeltaVulnerability `json:"fixedVulnerabilities,omitempty"`
+}
+
+// DeltaComponent identifies a component by its name and version
+type DeltaComponent struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// DeltaVulnerability describes a vulnerability of a component
+type DeltaVulnerability struct {
+	Cve        string         `json:"cve"`
+	Cvss       string         `json:"cvss,omitempty"`
+	Cvss3Score string         `json:"cvss3Score,omitempty"`
+	Component  DeltaComponent `json:"component"`
+}
+
+// noLayer is used for components which have not been found inside an image layer, e.g. for scans of plain files
+const noLayer = "-"
+
+// layerFindings contains the components and relevant vulnerabilities found in one scan result grouped by layer
+type layerFindings struct {
+	components      map[string]map[string]DeltaComponent
+	vulnerabilities map[string]map[string]DeltaVulnerability
+}
+
+// CompareResults determines which components and vulnerabilities have been introduced or fixed compared to the previous result.
+// Findings in several layers are listed for each layer but counted only once.
+// Only vulnerabilities which are relevant for the compliance check are considered, i.e. historical, triaged and excluded vulnerabilities are ignored.
+// A vulnerability of a component remains the same if only the version of the component changes.
+func CompareResults(current, previous Result, excludeCVEs string) DeltaReport {
+	currentFindings := collectLayerFindings(current, excludeCVEs)
+	previousFindings := collectLayerFindings(previous, excludeCVEs)
+
+	delta := DeltaReport{ProductID: current.ProductID, PreviousProductID: previous.ProductID}
+	layers := map[string]*LayerDelta{}
+	layerDelta := func(layer string) *LayerDelta {
+		if layers[layer] == nil {
+			layers[layer] = &LayerDelta{Layer: layer}
+		}
+		return layers[layer]
+	}
+
+	addedComponents := map[string]bool{}
+	for layer, components := range currentFindings.components {
+		for key, component := range components {
+			if !previousFindings.containsComponent(key) {
+				layerDelta(layer).AddedComponents = append(layerDelta(layer).AddedComponents, component)
+				addedComponents[key] = true
+			}
+		}
+	}
+	delta.AddedComponents = len(addedComponents)
+	removedComponents := map[string]bool{}
+	for layer, components := range previousFindings.components {
+		for key, component := range components {
+			if !currentFindings.containsComponent(key) {
+				layerDelta(layer).RemovedComponents = append(layerDelta(layer).RemovedComponents, component)
+				removedComponents[key] = true
+			}
+		}
+	}
+	delta.RemovedComponents = len(removedComponents)
+	newVulnerabilities := map[string]bool{}
+	for layer, vulnerabilities := range currentFindings.vulnerabilities {
+		for key, vulnerability := range vulnerabilities {
+			if !previousFindings.containsVulnerability(key) {
+				layerDelta(layer).NewVulnerabilities = append(layerDelta(layer).NewVulnerabilities, vulnerability)
+				newVulnerabilities[key] = true
+			}
+		}
+	}
+	delta.NewVulnerabilities = len(newVulnerabilities)
+	fixedVulnerabilities := map[string]bool{}
+	for layer, vulnerabilities := range previousFindings.vulnerabilities {
+		for key, vulnerability := range vulnerabilities {
+			if !currentFindings.containsVulnerability(key) {
+				layerDelta(layer).FixedVulnerabilities = append(layerDelta(layer).FixedVulnerabilities, vulnerability)
+				fixedVulnerabilities[key] = true
+			}
+		}
+	}
+	delta.FixedVulnerabilities = len(fixedVulnerabilities)
+
+	delta.Layers = []LayerDelta{}
+	for _, layer := range layers {
+		sortComponents(layer.AddedComponents)
+		sortComponents(layer.RemovedComponents)
+		sortVulnerabilities(layer.NewVulnerabilities)
+		sortVulnerabilities(layer.FixedVulnerabilities)
+		delta.Layers = append(delta.Layers, *layer)
+	}
+	sort.Slice(delta.Layers, func(i, j int) bool {
+		return delta.Layers[i].Layer < delta.Layers[j].Layer
+	})
+	return delta
+}
+
+func collectLayerFindings(result Result, excludeCVEs string) layerFindings {
+	findings := layerFindings{
+		components:      map[string]map[string]DeltaComponent{},
+		vulnerabilities: map[string]map[string]DeltaVulnerability{},
+	}
+	for _, component := range result.Components {
+		deltaComponent := DeltaComponent{Name: component.Lib, Version: component.Version}
+		for _, layer := range component.layers() {
+			if findings.components[layer] == nil {
+				findings.components[layer] = map[string]DeltaComponent{}
+				findings.vulnerabilities[layer] = map[string]DeltaVulnerability{}
+			}
+			findings.components[layer][component.Lib+"@"+component.Version] = deltaComponent
+			for _, vulnerability := range component.Vulns {
+				if !isExact(vulnerability) || isExcluded(vulnerability, excludeCVEs) || isTriaged(vulnerability) {
+					continue
+				}
+				findings.vulnerabilities[layer][component.Lib+"|"+vulnerability.Vuln.Cve] = DeltaVulnerability{
+					Cve:        vulnerability.Vuln.Cve,
+					Cvss:       vulnerability.Vuln.Cvss,
+					Cvss3Score: vulnerability.Vuln.Cvss3Score,
+					Component:  deltaComponent,
+				}
+			}
+		}
+	}
+	return findings
+}
+
+func (f layerFindings) containsComponent(key string) bool {
+	for _, components := range f.components {
+		if _, ok := components[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (f layerFindings) containsVulnerability(key string) bool {
+	for _, vulnerabilities := range f.vulnerabilities {
+		if _, ok := vulnerabilities[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// layers returns the image layers in which the component has been found.
+// Protecode lists the path of nested archives for each object, e.g. [image.tar, <digest>/layer.tar, usr/lib/lib.so],
+// the innermost layer archive is considered as layer of the object.
+func (c Component) layers() []string {
+	known := map[string]bool{}
+	layers := []string{}
+	for _, object := range c.ExtendedObjects {
+		layer := noLayer
+		for _, path := range object.FullPath {
+			if strings.HasSuffix(path, "layer.tar") || strings.HasPrefix(path, "sha256:") || strings.HasPrefix(path, "blobs/sha256/") {
+				layer = strings.TrimSuffix(strings.TrimSuffix(path, "layer.tar"), "/")
+			}
+		}
+		if !known[layer] {
+			known[layer] = true
+			layers = append(layers, layer)
+		}
+	}
+	if len(layers) == 0 {
+		layers = append(layers, noLayer)
+	}
+	return layers
+}
+
+func sortComponents(components []DeltaComponent) {
+	sort.Slice(components, func(i, j int) bool {
+		if components[i].Name != components[j].Name {
+			return components[i].Name < components[j].Name
+		}
+		return components[i].Version < components[j].Version
+	})
+}
+
+func sortVulnerabilities(vulnerabilities []DeltaVulnerability) {
+	sort.Slice(vulnerabilities, func(i, j int) bool {
+		if vulnerabilities[i].Cve != vulnerabilities[j].Cve {
+			return vulnerabilities[i].Cve < vulnerabilities[j].Cve
+		}
+		return vulnerabilities[i].Component.Name < vulnerabilities[j].Component.Name
+	})
+}
+
+// CreateDeltaReport creates the scan report listing the changes compared to the previous product
+func CreateDeltaReport(productName string, delta DeltaReport) reporting.ScanReport {
+	scanReport := reporting.ScanReport{
+		ReportTitle: "Protecode Delta Report",
+		Subheaders: []reporting.Subheader{
+			{Description: "Product name", Details: productName},
+			{Description: "Product ID", Details: fmt.Sprint(delta.ProductID)},
+			{Description: "Previous product ID", Details: fmt.Sprint(delta.PreviousProductID)},
+		},
+		Overview: []reporting.OverviewRow{
+			{Description: "New Vulnerabilities", Details: fmt.Sprint(delta.NewVulnerabilities)},
+			{Description: "Fixed Vulnerabilities", Details: fmt.Sprint(delta.FixedVulnerabilities)},
+			{Description: "Added Components", Details: fmt.Sprint(delta.AddedComponents)},
+			{Description: "Removed Components", Details: fmt.Sprint(delta.RemovedComponents)},
+		},
+		SuccessfulScan: delta.NewVulnerabilities == 0,
+		ReportTime:     time.Now(),
+	}
+
+	detailTable := reporting.ScanDetailTable{
+		NoRowsMessage: "No changes detected",
+		Headers: []string{
+			"Layer",
+			"Change",
+			"Component",
+			"Version",
+			"Issue CVE",
+			"CVSS Score",
+			"CVSS v3 Score",
+		},
+		WithCounter:   true,
+		CounterHeader: "Entry #",
+	}
+
+	for _, layer := range delta.Layers {
+		for _, vuln := range layer.NewVulnerabilities {
+			detailTable.Rows = append(detailTable.Rows, vulnerabilityRow(layer.Layer, "new vulnerability", reporting.Red, vuln))
+		}
+		for _, vuln := range layer.FixedVulnerabilities {
+			detailTable.Rows = append(detailTable.Rows, vulnerabilityRow(layer.Layer, "fixed vulnerability", reporting.Green, vuln))
+		}
+		for _, component := range layer.AddedComponents {
+			detailTable.Rows = append(detailTable.Rows, componentRow(layer.Layer, "added component", component))
+		}
+		for _, component := range layer.RemovedComponents {
+			detailTable.Rows = append(detailTable.Rows, componentRow(layer.Layer, "removed component", component))
+		}
+	}
+	scanReport.DetailTable = detailTable
+
+	return scanReport
+}
+
+func vulnerabilityRow(layer, change string, style reporting.ColumnStyle, vuln DeltaVulnerability) reporting.ScanRow {
+	row := reporting.ScanRow{}
+	row.AddColumn(layer, 0)
+	row.AddColumn(change, style)
+	row.AddColumn(vuln.Component.Name, 0)
+	row.AddColumn(vuln.Component.Version, 0)
+	row.AddColumn(vuln.Cve, 0)
+	row.AddColumn(vuln.Cvss, 0)
+	row.AddColumn(vuln.Cvss3Score, 0)
+	return row
+}
+
+func componentRow(layer, change string, component DeltaComponent) reporting.ScanRow {
+	row := reporting.ScanRow{}
+	row.AddColumn(layer, 0)
+	row.AddColumn(change, 0)
+	row.AddColumn(component.Name, 0)
+	row.AddColumn(component.Version, 0)
+	row.AddColumn("", 0)
+	row.AddColumn("", 0)
+	row.AddColumn("", 0)
+	return row
+}
+
+// WriteDeltaReports writes the delta report as HTML and as JSON file
+func WriteDeltaReports(delta DeltaReport, scanReport reporting.ScanReport, fileUtils piperutils.FileUtils) ([]piperutils.Path, error) {
+	reportPaths := []piperutils.Path{}
+
+	if err := fileUtils.MkdirAll(ReportsDirectory, 0777); err != nil {
+		return reportPaths, errors.Wrapf(err, "failed to create report directory")
+	}
+
+	// ignore templating errors since template is in our hands and issues will be detected with the automated tests
+	htmlReport, _ := scanReport.ToHTML()
+	htmlReportPath := filepath.Join(ReportsDirectory, "piper_protecode_delta_report.html")
+	if err := fileUtils.FileWrite(htmlReportPath, htmlReport, 0666); err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return reportPaths, errors.Wrapf(err, "failed to write html delta report")
+	}
+	reportPaths = append(reportPaths, piperutils.Path{Name: "Protecode Delta Report", Target: htmlReportPath})
+
+	jsonReport, _ := json.MarshalIndent(delta, "", "  ")
+	jsonReportPath := filepath.Join(ReportsDirectory, "piper_protecode_delta_report.json")
+	if err := fileUtils.FileWrite(jsonReportPath, jsonReport, 0666); err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return reportPaths, errors.Wrapf(err, "failed to write json delta report")
+	}
+	reportPaths = append(reportPaths, piperutils.Path{Name: "Protecode Delta Report (JSON)", Target: jsonReportPath})
+
+	return reportPaths, nil
+}
@@ -0,0 +1,113 @@
+package protecode
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+)
+
+func imageComponent(lib, version, layer string, vulns ...Vulnerability) Component {
+	return Component{
+		Lib:             lib,
+		Version:         version,
+		ExtendedObjects: []ExtendedObject{{Name: lib, FullPath: []string{"image.tar", layer + "/layer.tar", "usr/lib/" + lib}}},
+		Vulns:           vulns,
+	}
+}
+
+func exactVuln(cve, cvss3 string) Vulnerability {
+	return Vulnerability{Exact: true, Vuln: Vuln{Cve: cve, Cvss3Score: cvss3}}
+}
+
+func TestCompareResults(t *testing.T) {
+	previous := Result{ProductID: 1, Components: []Component{
+		imageComponent("openssl", "1.1.1k", "base1", exactVuln("CVE-2021-3711", "9.8"), exactVuln("CVE-2021-3712", "7.4")),
+		imageComponent("zlib", "1.2.11", "base1", exactVuln("CVE-2018-25032", "7.5")),
+		imageComponent("app", "1.0", "app"),
+	}}
+	current := Result{ProductID: 2, Components: []Component{
+		imageComponent("openssl", "1.1.1n", "base2", exactVuln("CVE-2021-3712", "7.4"), exactVuln("CVE-2022-0778", "7.5")),
+		imageComponent("zlib", "1.2.12", "base2", Vulnerability{Exact: false, Vuln: Vuln{Cve: "CVE-2018-25032"}}),
+		imageComponent("curl", "7.80.0", "base2", Vulnerability{Exact: true, Vuln: Vuln{Cve: "CVE-2022-22576"}, Triage: []Triage{{ID: 1}}}),
+		imageComponent("app", "1.0", "app"),
+	}}
+
+	delta := CompareResults(current, previous, "")
+
+	assert.Equal(t, 2, delta.ProductID)
+	assert.Equal(t, 1, delta.PreviousProductID)
+	assert.Equal(t, 1, delta.NewVulnerabilities)
+	assert.Equal(t, 2, delta.FixedVulnerabilities)
+	assert.Equal(t, 3, delta.AddedComponents)
+	assert.Equal(t, 2, delta.RemovedComponents)
+
+	if assert.Len(t, delta.Layers, 2) {
+		assert.Equal(t, "base1", delta.Layers[0].Layer)
+		assert.Equal(t, []DeltaComponent{{Name: "openssl", Version: "1.1.1k"}, {Name: "zlib", Version: "1.2.11"}}, delta.Layers[0].RemovedComponents)
+		assert.Equal(t, []DeltaVulnerability{
+			{Cve: "CVE-2018-25032", Cvss3Score: "7.5", Component: DeltaComponent{Name: "zlib", Version: "1.2.11"}},
+			{Cve: "CVE-2021-3711", Cvss3Score: "9.8", Component: DeltaComponent{Name: "openssl", Version: "1.1.1k"}},
+		}, delta.Layers[0].FixedVulnerabilities)
+
+		assert.Equal(t, "base2", delta.Layers[1].Layer)
+		assert.Equal(t, []DeltaComponent{{Name: "curl", Version: "7.80.0"}, {Name: "openssl", Version: "1.1.1n"}, {Name: "zlib", Version: "1.2.12"}}, delta.Layers[1].AddedComponents)
+		assert.Equal(t, []DeltaVulnerability{{Cve: "CVE-2022-0778", Cvss3Score: "7.5", Component: DeltaComponent{Name: "openssl", Version: "1.1.1n"}}}, delta.Layers[1].NewVulnerabilities)
+	}
+
+	t.Run("excluded vulnerabilities", func(t *testing.T) {
+		delta := CompareResults(current, previous, "CVE-2022-0778")
+		assert.Equal(t, 0, delta.NewVulnerabilities)
+	})
+
+	t.Run("no layers", func(t *testing.T) {
+		delta := CompareResults(Result{Components: []Component{{Lib: "lib", Version: "2"}}}, Result{Components: []Component{{Lib: "lib", Version: "1"}}}, "")
+		if assert.Len(t, delta.Layers, 1) {
+			assert.Equal(t, "-", delta.Layers[0].Layer)
+		}
+	})
+}
+
+func TestCreateDeltaReport(t *testing.T) {
+	delta := DeltaReport{
+		ProductID:          2,
+		PreviousProductID:  1,
+		NewVulnerabilities: 1,
+		AddedComponents:    1,
+		Layers: []LayerDelta{{
+			Layer:              "base2",
+			AddedComponents:    []DeltaComponent{{Name: "openssl", Version: "1.1.1n"}},
+			NewVulnerabilities: []DeltaVulnerability{{Cve: "CVE-2022-0778", Cvss3Score: "7.5", Component: DeltaComponent{Name: "openssl", Version: "1.1.1n"}}},
+		}},
+	}
+
+	scanReport := CreateDeltaReport("image.tar", delta)
+
+	assert.Equal(t, "Protecode Delta Report", scanReport.ReportTitle)
+	assert.False(t, scanReport.SuccessfulScan)
+	assert.Equal(t, "1", scanReport.Overview[0].Details)
+	if assert.Len(t, scanReport.DetailTable.Rows, 2) {
+		assert.Equal(t, "new vulnerability", scanReport.DetailTable.Rows[0].Columns[1].Content)
+		assert.Equal(t, "CVE-2022-0778", scanReport.DetailTable.Rows[0].Columns[4].Content)
+		assert.Equal(t, "added component", scanReport.DetailTable.Rows[1].Columns[1].Content)
+	}
+}
+
+func TestWriteDeltaReports(t *testing.T) {
+	files := mock.FilesMock{}
+	delta := DeltaReport{ProductID: 2, PreviousProductID: 1, Layers: []LayerDelta{}}
+
+	paths, err := WriteDeltaReports(delta, CreateDeltaReport("image.tar", delta), &files)
+
+	assert.NoError(t, err)
+	assert.Len(t, paths, 2)
+	assert.True(t, files.HasWrittenFile("protecode/piper_protecode_delta_report.html"))
+	content, err := files.FileRead("protecode/piper_protecode_delta_report.json")
+	if assert.NoError(t, err) {
+		written := DeltaReport{}
+		assert.NoError(t, json.Unmarshal(content, &written))
+		assert.Equal(t, delta, written)
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 
 	piperHttp "github.com/SAP/jenkins-library/pkg/http"
@@ -46,7 +47,16 @@ type Result struct {
 
 // Component the protecode component information
 type Component struct {
-	Vulns []Vulnerability `json:"vulns,omitempty"`
+	Lib             string           `json:"lib,omitempty"`
+	Version         string           `json:"version,omitempty"`
+	ExtendedObjects []ExtendedObject `json:"extended-objects,omitempty"`
+	Vulns           []Vulnerability  `json:"vulns,omitempty"`
+}
+
+// ExtendedObject holds the information about a file in which a component has been found
+type ExtendedObject struct {
+	Name     string   `json:"name,omitempty"`
+	FullPath []string `json:"fullpath,omitempty"`
 }
 
 // Vulnerability the protecode vulnerability information
@@ -527,6 +537,15 @@ func (pc *Protecode) pullResult(productID int) (ResultData, error) {
 
 }
 
+// LoadResult loads the scan result of an existing product
+func (pc *Protecode) LoadResult(productID int) (Result, error) {
+	response, err := pc.pullResult(productID)
+	if err != nil {
+		return Result{}, errors.Wrapf(err, "failed to load result of product %v", productID)
+	}
+	return response.Result, nil
+}
+
 // verify provided product id
 func (pc *Protecode) VerifyProductID(ProductID int) bool {
 	pc.logger.Infof("Verification of product id (%v) started ... ", ProductID)
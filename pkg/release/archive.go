@@ -0,0 +1,127 @@
+package release
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+)
+
+const (
+	// FormatTarGz is the archive format used for all platforms except windows
+	FormatTarGz = "tar.gz"
+	// FormatZip is the archive format used for windows
+	FormatZip = "zip"
+)
+
+// Utils provides the file system access required for packaging releases
+type Utils interface {
+	FileRead(path string) ([]byte, error)
+	FileWrite(path string, content []byte, perm os.FileMode) error
+}
+
+// File is a file added to a release archive
+type File struct {
+	// Path is the location of the file in the workspace
+	Path string
+	// Name is the path of the file within the archive
+	Name string
+	Mode os.FileMode
+}
+
+// ArchiveFormat returns the usual archive format for the operating system
+func ArchiveFormat(goos string) string {
+	if goos == "windows" {
+		return FormatZip
+	}
+	return FormatTarGz
+}
+
+// ArchiveName returns the file name of the archive of a platform, e.g. piper_1.2.3_linux_amd64.tar.gz
+func ArchiveName(project, version, goos, goarch, format string) string {
+	return fmt.Sprintf("%v_%v_%v_%v.%v", project, version, goos, goarch, format)
+}
+
+// CreateArchive writes the files into an archive of the given format. The archive is reproducible:
+// the files are sorted by name and all entries carry the given modification time and no owner.
+func CreateArchive(target, format string, files []File, modTime time.Time, utils Utils) error {
+	sorted := append([]File{}, files...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	var content bytes.Buffer
+	var err error
+	switch format {
+	case FormatTarGz:
+		err = writeTarGz(&content, sorted, modTime.UTC(), utils)
+	case FormatZip:
+		err = writeZip(&content, sorted, modTime.UTC(), utils)
+	default:
+		return fmt.Errorf("archive format '%v' is not supported, use '%v' or '%v'", format, FormatTarGz, FormatZip)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create archive '%v': %w", target, err)
+	}
+	if err := utils.FileWrite(target, content.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write archive '%v': %w", target, err)
+	}
+	return nil
+}
+
+func writeTarGz(target io.Writer, files []File, modTime time.Time, utils Utils) error {
+	// the gzip header must not contain a name or modification time
+	gzipWriter := gzip.NewWriter(target)
+	tarWriter := tar.NewWriter(gzipWriter)
+	for _, file := range files {
+		content, err := utils.FileRead(file.Path)
+		if err != nil {
+			return err
+		}
+		header := &tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     file.Name,
+			Size:     int64(len(content)),
+			Mode:     int64(file.Mode.Perm()),
+			ModTime:  modTime,
+			Format:   tar.FormatPAX,
+		}
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return err
+		}
+		if _, err := tarWriter.Write(content); err != nil {
+			return err
+		}
+	}
+	if err := tarWriter.Close(); err != nil {
+		return err
+	}
+	return gzipWriter.Close()
+}
+
+func writeZip(target io.Writer, files []File, modTime time.Time, utils Utils) error {
+	zipWriter := zip.NewWriter(target)
+	for _, file := range files {
+		content, err := utils.FileRead(file.Path)
+		if err != nil {
+			return err
+		}
+		header := &zip.FileHeader{
+			Name:     file.Name,
+			Method:   zip.Deflate,
+			Modified: modTime,
+		}
+		header.SetMode(file.Mode.Perm())
+		writer, err := zipWriter.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		if _, err := writer.Write(content); err != nil {
+			return err
+		}
+	}
+	return zipWriter.Close()
+}
@@ -0,0 +1,105 @@
+package release
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchiveName(t *testing.T) {
+	assert.Equal(t, "piper_1.2.3_linux_amd64.tar.gz", ArchiveName("piper", "1.2.3", "linux", "amd64", ArchiveFormat("linux")))
+	assert.Equal(t, "piper_1.2.3_windows_arm64.zip", ArchiveName("piper", "1.2.3", "windows", "arm64", ArchiveFormat("windows")))
+}
+
+func TestCreateArchive(t *testing.T) {
+	modTime := time.Unix(1700000000, 0)
+	files := []File{
+		{Path: "bin/piper-linux.amd64", Name: "piper", Mode: 0755},
+		{Path: "LICENSE", Name: "LICENSE", Mode: 0644},
+	}
+	newUtils := func() *mock.FilesMock {
+		utils := &mock.FilesMock{}
+		utils.AddFile("bin/piper-linux.amd64", []byte("binary"))
+		utils.AddFile("LICENSE", []byte("Apache License"))
+		return utils
+	}
+
+	t.Run("tar.gz", func(t *testing.T) {
+		utils := newUtils()
+
+		err := CreateArchive("dist/piper.tar.gz", FormatTarGz, files, modTime, utils)
+
+		assert.NoError(t, err)
+		content, err := utils.FileRead("dist/piper.tar.gz")
+		require.NoError(t, err)
+		gzipReader, err := gzip.NewReader(bytes.NewReader(content))
+		require.NoError(t, err)
+		tarReader := tar.NewReader(gzipReader)
+		names := []string{}
+		for {
+			header, err := tarReader.Next()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			names = append(names, header.Name)
+			assert.True(t, modTime.Equal(header.ModTime))
+			if header.Name == "piper" {
+				assert.Equal(t, int64(0755), header.Mode)
+			}
+		}
+		assert.Equal(t, []string{"LICENSE", "piper"}, names)
+	})
+
+	t.Run("zip", func(t *testing.T) {
+		utils := newUtils()
+
+		err := CreateArchive("dist/piper.zip", FormatZip, files, modTime, utils)
+
+		assert.NoError(t, err)
+		content, err := utils.FileRead("dist/piper.zip")
+		require.NoError(t, err)
+		zipReader, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+		require.NoError(t, err)
+		if assert.Len(t, zipReader.File, 2) {
+			assert.Equal(t, "LICENSE", zipReader.File[0].Name)
+			assert.Equal(t, "piper", zipReader.File[1].Name)
+			assert.Equal(t, os.FileMode(0755), zipReader.File[1].Mode().Perm())
+			assert.True(t, modTime.Equal(zipReader.File[1].Modified))
+		}
+	})
+
+	t.Run("reproducible", func(t *testing.T) {
+		for _, format := range []string{FormatTarGz, FormatZip} {
+			first, second := newUtils(), newUtils()
+			require.NoError(t, CreateArchive("archive", format, files, modTime, first))
+			// the order of the files does not matter
+			require.NoError(t, CreateArchive("archive", format, []File{files[1], files[0]}, modTime, second))
+
+			firstContent, _ := first.FileRead("archive")
+			secondContent, _ := second.FileRead("archive")
+			assert.Equal(t, firstContent, secondContent, format)
+		}
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		err := CreateArchive("piper.7z", "7z", files, modTime, newUtils())
+
+		assert.EqualError(t, err, "archive format '7z' is not supported, use 'tar.gz' or 'zip'")
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		err := CreateArchive("piper.tar.gz", FormatTarGz, files, modTime, &mock.FilesMock{})
+
+		assert.Contains(t, err.Error(), "failed to create archive 'piper.tar.gz'")
+	})
+}
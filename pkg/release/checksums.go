@@ -0,0 +1,30 @@
+package release
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ChecksumsFileName is the name of the file listing the SHA256 checksums of the release files
+const ChecksumsFileName = "SHA256SUMS"
+
+// Checksums calculates the SHA256 checksums of the files. It returns the content of a checksums file in the format
+// of sha256sum, which lists the files by their base name, together with the checksum of each file.
+func Checksums(files []string, utils Utils) ([]byte, map[string]string, error) {
+	checksums := map[string]string{}
+	lines := []string{}
+	for _, file := range files {
+		content, err := utils.FileRead(file)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to calculate checksum of '%v': %w", file, err)
+		}
+		checksum := fmt.Sprintf("%x", sha256.Sum256(content))
+		checksums[file] = checksum
+		lines = append(lines, fmt.Sprintf("%v  %v\n", checksum, filepath.Base(file)))
+	}
+	sort.Slice(lines, func(i, j int) bool { return lines[i][66:] < lines[j][66:] })
+	return []byte(strings.Join(lines, "")), checksums, nil
+}
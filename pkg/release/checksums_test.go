@@ -0,0 +1,24 @@
+package release
+
+import (
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChecksums(t *testing.T) {
+	utils := &mock.FilesMock{}
+	utils.AddFile("dist/piper_1.2.3_linux_amd64.tar.gz", []byte("linux"))
+	utils.AddFile("dist/piper_1.2.3_darwin_arm64.tar.gz", []byte("darwin"))
+
+	content, checksums, err := Checksums([]string{"dist/piper_1.2.3_linux_amd64.tar.gz", "dist/piper_1.2.3_darwin_arm64.tar.gz"}, utils)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "26ce1a1580f693873b6268fef54c5f0d0607f2896cad02ce2894c0c899a11575", checksums["dist/piper_1.2.3_darwin_arm64.tar.gz"])
+	assert.Equal(t, "26ce1a1580f693873b6268fef54c5f0d0607f2896cad02ce2894c0c899a11575  piper_1.2.3_darwin_arm64.tar.gz\n"+
+		checksums["dist/piper_1.2.3_linux_amd64.tar.gz"]+"  piper_1.2.3_linux_amd64.tar.gz\n", string(content))
+
+	_, _, err = Checksums([]string{"missing"}, utils)
+	assert.Contains(t, err.Error(), "failed to calculate checksum of 'missing'")
+}
@@ -0,0 +1,77 @@
+package reporting
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/pkg/errors"
+)
+
+// reportFileUtils abstracts the file system access needed to write reports, e.g. piperutils.Files
+type reportFileUtils interface {
+	DirExists(path string) (bool, error)
+	MkdirAll(path string, perm os.FileMode) error
+	FileWrite(path string, content []byte, perm os.FileMode) error
+}
+
+// ScanReportFiles describes the report files of a scan step
+type ScanReportFiles struct {
+	// Directory contains the HTML report and the SARIF file
+	Directory string
+	// Name is used for the links to the reports, e.g. "Secret Scan" results in "Secret Scan Report"
+	Name string
+	// HTMLFile is the name of the HTML report
+	HTMLFile string
+	// SarifFile is the name of the SARIF file, it is only written if Sarif is provided
+	SarifFile string
+	Sarif     interface{}
+	// JSONFile is the name of the JSON report within the StepReportDirectory
+	JSONFile string
+}
+
+// WriteScanReports writes the HTML report and the SARIF file into the report directory as well as the JSON report into
+// the StepReportDirectory. It returns the reports which are to be persisted, i.e. the HTML report and the SARIF file.
+func WriteScanReports(scanReport ScanReport, files ScanReportFiles, utils reportFileUtils) ([]piperutils.Path, error) {
+	reportPaths := []piperutils.Path{}
+	if err := utils.MkdirAll(files.Directory, 0777); err != nil {
+		return reportPaths, errors.Wrap(err, "failed to create report directory")
+	}
+
+	// ignore templating errors since template is in our hands and issues will be detected with the automated tests
+	htmlReport, _ := scanReport.ToHTML()
+	htmlReportPath := filepath.Join(files.Directory, files.HTMLFile)
+	if err := utils.FileWrite(htmlReportPath, htmlReport, 0666); err != nil {
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return reportPaths, errors.Wrap(err, "failed to write html report")
+	}
+	reportPaths = append(reportPaths, piperutils.Path{Name: files.Name + " Report", Target: htmlReportPath})
+
+	if files.Sarif != nil {
+		sarif, err := json.Marshal(files.Sarif)
+		if err != nil {
+			return reportPaths, errors.Wrap(err, "failed to marshall SARIF json file")
+		}
+		sarifPath := filepath.Join(files.Directory, files.SarifFile)
+		if err := utils.FileWrite(sarifPath, sarif, 0666); err != nil {
+			log.SetErrorCategory(log.ErrorConfiguration)
+			return reportPaths, errors.Wrap(err, "failed to write SARIF file")
+		}
+		reportPaths = append(reportPaths, piperutils.Path{Name: files.Name + " SARIF file", Target: sarifPath})
+	}
+
+	// JSON reports are used by step pipelineCreateSummary in order to e.g. prepare an issue creation in GitHub
+	// ignore JSON errors since structure is in our hands
+	jsonReport, _ := scanReport.ToJSON()
+	if exists, _ := utils.DirExists(StepReportDirectory); !exists {
+		if err := utils.MkdirAll(StepReportDirectory, 0777); err != nil {
+			return reportPaths, errors.Wrap(err, "failed to create step reporting directory")
+		}
+	}
+	if err := utils.FileWrite(filepath.Join(StepReportDirectory, files.JSONFile), jsonReport, 0666); err != nil {
+		return reportPaths, errors.Wrap(err, "failed to write json report")
+	}
+	return reportPaths, nil
+}
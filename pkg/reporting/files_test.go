@@ -0,0 +1,63 @@
+package reporting
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteScanReports(t *testing.T) {
+	t.Parallel()
+	scanReport := ScanReport{ReportTitle: "Test Scan Report"}
+	files := ScanReportFiles{
+		Directory: "testscan",
+		Name:      "Test Scan",
+		HTMLFile:  "piper_testscan_report.html",
+		SarifFile: "piper_testscan.sarif",
+		Sarif:     map[string]string{"version": "2.1.0"},
+		JSONFile:  "testScan_test.json",
+	}
+
+	t.Run("success", func(t *testing.T) {
+		t.Parallel()
+		utils := &mock.FilesMock{}
+
+		reportPaths, err := WriteScanReports(scanReport, files, utils)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []piperutils.Path{
+			{Name: "Test Scan Report", Target: "testscan/piper_testscan_report.html"},
+			{Name: "Test Scan SARIF file", Target: "testscan/piper_testscan.sarif"},
+		}, reportPaths)
+		sarif, err := utils.FileRead("testscan/piper_testscan.sarif")
+		if assert.NoError(t, err) {
+			assert.Equal(t, `{"version":"2.1.0"}`, string(sarif))
+		}
+		assert.True(t, utils.HasWrittenFile(".pipeline/stepReports/testScan_test.json"))
+	})
+
+	t.Run("without SARIF file", func(t *testing.T) {
+		t.Parallel()
+		utils := &mock.FilesMock{}
+		withoutSarif := files
+		withoutSarif.Sarif = nil
+
+		reportPaths, err := WriteScanReports(scanReport, withoutSarif, utils)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []piperutils.Path{{Name: "Test Scan Report", Target: "testscan/piper_testscan_report.html"}}, reportPaths)
+		assert.False(t, utils.HasWrittenFile("testscan/piper_testscan.sarif"))
+	})
+
+	t.Run("write error", func(t *testing.T) {
+		t.Parallel()
+		utils := &mock.FilesMock{FileWriteErrors: map[string]error{"testscan/piper_testscan_report.html": errors.New("disk full")}}
+
+		_, err := WriteScanReports(scanReport, files, utils)
+
+		assert.EqualError(t, err, "failed to write html report: disk full")
+	})
+}
@@ -27,6 +27,7 @@ type Component struct {
 	PolicyViolationLicenses        []PolicyViolationLicense       `json:"policyViolationLicenses"`
 	WarningMessage                 string                         `json:"warningMessage"`
 	ErrorMessage                   string                         `json:"errorMessage"`
+	Meta                           Meta                           `json:"_meta"`
 }
 
 type PolicyViolationVulnerability struct {
@@ -59,6 +60,7 @@ type RapidScanReport struct {
 	VulnerabilitiesTable []Vulnerabilities
 	LicensesTable        []Licenses
 	OtherViolationsTable []OtherViolations
+	UpgradeGuidanceTable []ComponentUpgradeGuidance
 }
 
 type Vulnerabilities struct {
@@ -93,6 +95,20 @@ type OtherViolation struct {
 	ComponentName string
 }
 
+// UpgradeGuidance contains the component versions which fix the vulnerabilities of a component
+type UpgradeGuidance struct {
+	ShortTerm string
+	LongTerm  string
+}
+
+type ComponentUpgradeGuidance struct {
+	ComponentName string
+	UpgradeGuidance
+}
+
+// UpgradeGuidanceLookup returns the upgrade guidance for a component of the rapid scan result
+type UpgradeGuidanceLookup func(component Component) (UpgradeGuidance, error)
+
 const rapidReportMdTemplate = `
 ## {{if .Success}}:heavy_check_mark: OSS related checks passed successfully
  ### :clipboard: OSS related checks executed by Black Duck - rapid scan passed successfully.
@@ -150,11 +166,22 @@ const rapidReportMdTemplate = `
 	</table>
 </details>
 {{end -}}
+{{if .UpgradeGuidanceTable -}}
+<details><summary><h3>:arrow_up: Upgrade guidance for vulnerable components</h3></summary>
+	<table>
+		<tr><td><b>Component Name</b></td><td><b>Short Term Upgrade</b></td><td><b>Long Term Upgrade</b></td></tr>
+		{{range $value := .UpgradeGuidanceTable -}}
+			<tr><td>{{$value.ComponentName}}</td><td>{{$value.ShortTerm}}</td><td>{{$value.LongTerm}}</td></tr>
+		{{end -}}
+	</table>
+</details>
+{{end -}}
 {{end}}
 `
 
 // RapidScanResult reads result of Rapid scan from generated file
-func RapidScanResult(dir string) (string, error) {
+// If an upgrade guidance lookup is provided, the report recommends upgrades for all vulnerable components
+func RapidScanResult(dir string, upgradeGuidance UpgradeGuidanceLookup) (string, error) {
 	components, removeDir, err := findAndReadJsonFile(dir)
 	if err != nil {
 		return "", err
@@ -163,7 +190,7 @@ func RapidScanResult(dir string) (string, error) {
 		return "", errors.New("couldn't parse info from file")
 	}
 
-	buf, err := createMarkdownReport(components)
+	buf, err := createMarkdownReport(components, upgradeGuidance)
 	if err != nil {
 		return "", err
 	}
@@ -238,7 +265,7 @@ func findAndReadJsonFile(dir string) (*Components, string, error) {
 }
 
 // createMarkdownReport creates markdown report to upload it as GitHub PR comment
-func createMarkdownReport(components *Components) (*bytes.Buffer, error) {
+func createMarkdownReport(components *Components, upgradeGuidance UpgradeGuidanceLookup) (*bytes.Buffer, error) {
 	// preparing report
 	var scanReport RapidScanReport
 	scanReport.Success = true
@@ -373,6 +400,11 @@ func createMarkdownReport(components *Components) (*bytes.Buffer, error) {
 		sort.Slice(scanReport.OtherViolationsTable, func(i, j int) bool {
 			return scanReport.OtherViolationsTable[i].PolicyViolationName < scanReport.OtherViolationsTable[j].PolicyViolationName
 		})
+
+		// UpgradeGuidanceTable
+		if upgradeGuidance != nil {
+			scanReport.UpgradeGuidanceTable = createUpgradeGuidanceTable(components, upgradeGuidance)
+		}
 	}
 
 	tmpl, err := template.New("report").Parse(rapidReportMdTemplate)
@@ -388,6 +420,30 @@ func createMarkdownReport(components *Components) (*bytes.Buffer, error) {
 	return buf, nil
 }
 
+// createUpgradeGuidanceTable collects the upgrade guidance of all components with vulnerabilities
+func createUpgradeGuidanceTable(components *Components, upgradeGuidance UpgradeGuidanceLookup) []ComponentUpgradeGuidance {
+	table := []ComponentUpgradeGuidance{}
+	for _, component := range *components {
+		if len(component.PolicyViolationVulnerabilities) == 0 {
+			continue
+		}
+		componentName := component.ComponentName + " " + component.ComponentVersion + " (" + component.ComponentIdentifier + ")"
+		guidance, err := upgradeGuidance(component)
+		if err != nil {
+			log.Entry().WithError(err).Warningf("Couldn't get upgrade guidance for component %s", componentName)
+			continue
+		}
+		if len(guidance.ShortTerm) == 0 && len(guidance.LongTerm) == 0 {
+			continue
+		}
+		table = append(table, ComponentUpgradeGuidance{ComponentName: componentName, UpgradeGuidance: guidance})
+	}
+	sort.Slice(table, func(i, j int) bool {
+		return table[i].ComponentName < table[j].ComponentName
+	})
+	return table
+}
+
 // getScore extracts score or severity from error message
 func getScore(message, key string) string {
 	indx := strings.Index(message, key)
@@ -1,6 +1,7 @@
 package reporting
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -145,7 +146,7 @@ func TestCreateMarkdownReport(t *testing.T) {
 		t.Run(c.testName, func(t *testing.T) {
 			t.Parallel()
 
-			buf, err := createMarkdownReport(c.components)
+			buf, err := createMarkdownReport(c.components, nil)
 
 			assert.Equal(t, c.expectedErr, err)
 			assert.Equal(t, c.expectedReport, buf.String())
@@ -153,6 +154,55 @@ func TestCreateMarkdownReport(t *testing.T) {
 	}
 }
 
+func TestCreateMarkdownReportWithUpgradeGuidance(t *testing.T) {
+	t.Parallel()
+
+	components := &Components{
+		{
+			ComponentName:        "qs",
+			ComponentVersion:     "5.2.1",
+			ComponentIdentifier:  "npmjs:qs/5.2.1",
+			ViolatingPolicyNames: []string{"High Vulnerability Security Issue"},
+			PolicyViolationVulnerabilities: []PolicyViolationVulnerability{
+				{Name: "CVE-2017-1000048", ViolatingPolicyNames: []string{"High Vulnerability Security Issue"}},
+			},
+			Meta: Meta{Href: "https://blackduck.example/api/components/1/versions/2"},
+		},
+		{
+			ComponentName:        "lodash",
+			ComponentVersion:     "4.17.10",
+			ComponentIdentifier:  "npmjs:lodash/4.17.10",
+			ViolatingPolicyNames: []string{"High Vulnerability Security Issue"},
+			PolicyViolationVulnerabilities: []PolicyViolationVulnerability{
+				{Name: "CVE-2019-10744", ViolatingPolicyNames: []string{"High Vulnerability Security Issue"}},
+			},
+		},
+		{
+			ComponentName:           "Apache Software License",
+			ComponentVersion:        "1.0",
+			ComponentIdentifier:     "maven:license/1.0",
+			ViolatingPolicyNames:    []string{"OutboundLicense"},
+			PolicyViolationLicenses: []PolicyViolationLicense{{LicenseName: "Apache License 1.0", ViolatingPolicyNames: []string{"OutboundLicense"}}},
+		},
+	}
+	lookedUp := []string{}
+	lookup := func(component Component) (UpgradeGuidance, error) {
+		lookedUp = append(lookedUp, component.ComponentName)
+		if len(component.Meta.Href) == 0 {
+			return UpgradeGuidance{}, errors.New("no link")
+		}
+		return UpgradeGuidance{ShortTerm: "6.0.4", LongTerm: "6.11.0"}, nil
+	}
+
+	buf, err := createMarkdownReport(components, lookup)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"qs", "lodash"}, lookedUp)
+	assert.Contains(t, buf.String(), "Upgrade guidance for vulnerable components")
+	assert.Contains(t, buf.String(), "<tr><td>qs 5.2.1 (npmjs:qs/5.2.1)</td><td>6.0.4</td><td>6.11.0</td></tr>")
+	assert.NotContains(t, buf.String(), "(npmjs:lodash/4.17.10)</td><td>6.0.4")
+}
+
 // Testing getScore function
 func TestGetScore(t *testing.T) {
 	t.Parallel()
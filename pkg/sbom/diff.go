@@ -0,0 +1,99 @@
+package sbom
+
+import (
+	"sort"
+	"strings"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/Masterminds/semver/v3"
+)
+
+// ComponentChange describes a component which is contained in both BOMs but differs in version or licenses
+type ComponentChange struct {
+	Key                string   `json:"key"`
+	PreviousVersion    string   `json:"previousVersion"`
+	Version            string   `json:"version"`
+	PreviousLicenses   []string `json:"previousLicenses,omitempty"`
+	Licenses           []string `json:"licenses,omitempty"`
+	PreviousPackageURL string   `json:"previousPurl,omitempty"`
+	PackageURL         string   `json:"purl,omitempty"`
+}
+
+// DiffResult contains the differences between two BOMs
+type DiffResult struct {
+	Added   []cdx.Component   `json:"added"`
+	Removed []cdx.Component   `json:"removed"`
+	Changed []ComponentChange `json:"changed"`
+}
+
+// HasChanges returns true if the BOMs differ
+func (d DiffResult) HasChanges() bool {
+	return len(d.Added)+len(d.Removed)+len(d.Changed) > 0
+}
+
+// Diff compares the components of a previous BOM with the ones of the current BOM.
+// Components are matched by their version-independent key, see ComponentKey.
+func Diff(previous, current *cdx.BOM) DiffResult {
+	result := DiffResult{Added: []cdx.Component{}, Removed: []cdx.Component{}, Changed: []ComponentChange{}}
+	previousComponents := componentsByKey(previous)
+	currentComponents := componentsByKey(current)
+
+	for _, key := range sortedKeys(currentComponents) {
+		component := currentComponents[key]
+		old, ok := previousComponents[key]
+		if !ok {
+			result.Added = append(result.Added, component)
+			continue
+		}
+		oldLicenses, newLicenses := LicenseIDs(old), LicenseIDs(component)
+		if old.Version != component.Version || strings.Join(oldLicenses, ",") != strings.Join(newLicenses, ",") {
+			result.Changed = append(result.Changed, ComponentChange{
+				Key:                key,
+				PreviousVersion:    old.Version,
+				Version:            component.Version,
+				PreviousLicenses:   oldLicenses,
+				Licenses:           newLicenses,
+				PreviousPackageURL: old.PackageURL,
+				PackageURL:         component.PackageURL,
+			})
+		}
+	}
+	for _, key := range sortedKeys(previousComponents) {
+		if _, ok := currentComponents[key]; !ok {
+			result.Removed = append(result.Removed, previousComponents[key])
+		}
+	}
+	return result
+}
+
+func componentsByKey(bom *cdx.BOM) map[string]cdx.Component {
+	components := map[string]cdx.Component{}
+	for _, component := range Components(bom) {
+		key := ComponentKey(component)
+		if existing, ok := components[key]; ok && compareVersions(existing.Version, component.Version) > 0 {
+			// keep one entry per key in case a component is contained in several versions
+			continue
+		}
+		components[key] = component
+	}
+	return components
+}
+
+// compareVersions compares semantic versions, e.g. 1.10.0 is newer than 1.9.0, other versions are compared lexically
+func compareVersions(a, b string) int {
+	versionA, errA := semver.NewVersion(a)
+	versionB, errB := semver.NewVersion(b)
+	if errA == nil && errB == nil {
+		return versionA.Compare(versionB)
+	}
+	return strings.Compare(a, b)
+}
+
+func sortedKeys(components map[string]cdx.Component) []string {
+	keys := make([]string, 0, len(components))
+	for key := range components {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
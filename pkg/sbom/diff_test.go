@@ -0,0 +1,56 @@
+package sbom
+
+import (
+	"testing"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	previous := cdx.NewBOM()
+	previous.Components = &[]cdx.Component{
+		{Name: "lodash", Version: "4.17.20", PackageURL: "pkg:npm/lodash@4.17.20"},
+		{Name: "left-pad", Version: "1.3.0", PackageURL: "pkg:npm/left-pad@1.3.0"},
+		{Name: "express", Version: "4.18.1", PackageURL: "pkg:npm/express@4.18.1", Licenses: &cdx.Licenses{{License: &cdx.License{ID: "MIT"}}}},
+		{Name: "react", Version: "18.2.0", PackageURL: "pkg:npm/react@18.2.0"},
+	}
+	current := cdx.NewBOM()
+	current.Components = &[]cdx.Component{
+		{Name: "lodash", Version: "4.17.21", PackageURL: "pkg:npm/lodash@4.17.21"},
+		{Name: "express", Version: "4.18.1", PackageURL: "pkg:npm/express@4.18.1", Licenses: &cdx.Licenses{{License: &cdx.License{ID: "Apache-2.0"}}}},
+		{Name: "react", Version: "18.2.0", PackageURL: "pkg:npm/react@18.2.0"},
+		{Name: "axios", Version: "1.1.3", PackageURL: "pkg:npm/axios@1.1.3"},
+	}
+
+	t.Run("changes", func(t *testing.T) {
+		result := Diff(previous, current)
+
+		assert.True(t, result.HasChanges())
+		assert.Len(t, result.Added, 1)
+		assert.Equal(t, "axios", result.Added[0].Name)
+		assert.Len(t, result.Removed, 1)
+		assert.Equal(t, "left-pad", result.Removed[0].Name)
+		assert.Equal(t, []ComponentChange{
+			{Key: "pkg:npm/express", PreviousVersion: "4.18.1", Version: "4.18.1", PreviousLicenses: []string{"MIT"}, Licenses: []string{"Apache-2.0"}, PreviousPackageURL: "pkg:npm/express@4.18.1", PackageURL: "pkg:npm/express@4.18.1"},
+			{Key: "pkg:npm/lodash", PreviousVersion: "4.17.20", Version: "4.17.21", PreviousLicenses: []string{}, Licenses: []string{}, PreviousPackageURL: "pkg:npm/lodash@4.17.20", PackageURL: "pkg:npm/lodash@4.17.21"},
+		}, result.Changed)
+	})
+
+	t.Run("component in several versions", func(t *testing.T) {
+		several := cdx.NewBOM()
+		several.Components = &[]cdx.Component{
+			{Name: "lodash", Version: "4.17.21", PackageURL: "pkg:npm/lodash@4.17.21"},
+			{Name: "lodash", Version: "4.17.9", PackageURL: "pkg:npm/lodash@4.17.9"},
+		}
+
+		result := Diff(previous, several)
+
+		assert.Equal(t, "4.17.21", result.Changed[len(result.Changed)-1].Version)
+	})
+
+	t.Run("no changes", func(t *testing.T) {
+		result := Diff(current, current)
+		assert.False(t, result.HasChanges())
+	})
+}
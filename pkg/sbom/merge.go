@@ -0,0 +1,138 @@
+package sbom
+
+import (
+	"sort"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+)
+
+// Merge combines several BOMs into one product BOM.
+// Components are de-duplicated based on their package URL (or group, name and version) and their bom-refs are
+// normalized accordingly so that the dependency graphs of all BOMs can be joined.
+// The main component of each BOM becomes a direct dependency of the product component.
+// Missing hashes and licenses of a component are completed from duplicates found in other BOMs.
+func Merge(product cdx.Component, boms []*cdx.BOM) *cdx.BOM {
+	if len(product.BOMRef) == 0 {
+		product.BOMRef = componentID(product)
+	}
+	if len(product.Type) == 0 {
+		product.Type = cdx.ComponentTypeApplication
+	}
+
+	components := map[string]*cdx.Component{}
+	order := []string{}
+	graph := map[string]map[string]bool{product.BOMRef: {}}
+
+	addComponent := func(component cdx.Component) string {
+		id := componentID(component)
+		if existing, ok := components[id]; ok {
+			completeComponent(existing, component)
+			return id
+		}
+		component.BOMRef = id
+		component.Components = nil
+		components[id] = &component
+		order = append(order, id)
+		return id
+	}
+
+	for _, bom := range boms {
+		if bom == nil {
+			continue
+		}
+		refs := map[string]string{}
+		if bom.Metadata != nil && bom.Metadata.Component != nil {
+			main := *bom.Metadata.Component
+			id := product.BOMRef
+			if componentID(main) != product.BOMRef {
+				id = addComponent(main)
+				graph[product.BOMRef][id] = true
+			}
+			refs[main.BOMRef] = id
+		}
+		for _, component := range Components(bom) {
+			id := addComponent(component)
+			if len(component.BOMRef) > 0 {
+				refs[component.BOMRef] = id
+			}
+		}
+		if bom.Dependencies == nil {
+			continue
+		}
+		for _, dependency := range *bom.Dependencies {
+			ref, ok := refs[dependency.Ref]
+			if !ok {
+				continue
+			}
+			if graph[ref] == nil {
+				graph[ref] = map[string]bool{}
+			}
+			if dependency.Dependencies == nil {
+				continue
+			}
+			for _, child := range *dependency.Dependencies {
+				if childRef, ok := refs[child.Ref]; ok && childRef != ref {
+					graph[ref][childRef] = true
+				}
+			}
+		}
+	}
+
+	mergedComponents := []cdx.Component{}
+	for _, id := range order {
+		mergedComponents = append(mergedComponents, *components[id])
+	}
+
+	bom := cdx.NewBOM()
+	bom.Metadata = &cdx.Metadata{Component: &product}
+	bom.Components = &mergedComponents
+	dependencies := dependencyList(graph)
+	bom.Dependencies = &dependencies
+	return bom
+}
+
+// completeComponent adds information to the target component which is only available in the source
+func completeComponent(target *cdx.Component, source cdx.Component) {
+	if (target.Hashes == nil || len(*target.Hashes) == 0) && source.Hashes != nil {
+		target.Hashes = source.Hashes
+	}
+	if (target.Licenses == nil || len(*target.Licenses) == 0) && source.Licenses != nil {
+		target.Licenses = source.Licenses
+	}
+	if len(target.PackageURL) == 0 {
+		target.PackageURL = source.PackageURL
+	}
+	if len(target.CPE) == 0 {
+		target.CPE = source.CPE
+	}
+	if target.Supplier == nil {
+		target.Supplier = source.Supplier
+	}
+}
+
+func dependencyList(graph map[string]map[string]bool) []cdx.Dependency {
+	refs := make([]string, 0, len(graph))
+	for ref := range graph {
+		refs = append(refs, ref)
+	}
+	sort.Strings(refs)
+
+	dependencies := []cdx.Dependency{}
+	for _, ref := range refs {
+		dependency := cdx.Dependency{Ref: ref}
+		children := make([]string, 0, len(graph[ref]))
+		for child := range graph[ref] {
+			children = append(children, child)
+		}
+		sort.Strings(children)
+		if len(children) > 0 {
+			childDependencies := []cdx.Dependency{}
+			for _, child := range children {
+				childDependencies = append(childDependencies, cdx.Dependency{Ref: child})
+			}
+			dependency.Dependencies = &childDependencies
+		}
+		dependencies = append(dependencies, dependency)
+	}
+	return dependencies
+}
@@ -0,0 +1,61 @@
+package sbom
+
+import (
+	"testing"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMerge(t *testing.T) {
+	t.Run("merge BOMs into product BOM", func(t *testing.T) {
+		boms := []*cdx.BOM{readTestBOM(t, "testdata/bom-maven.xml"), readTestBOM(t, "testdata/bom-npm.json")}
+
+		merged := Merge(cdx.Component{Name: "product", Version: "1.2.3"}, boms)
+
+		assert.Equal(t, "product@1.2.3", merged.Metadata.Component.BOMRef)
+		assert.Equal(t, cdx.ComponentTypeApplication, merged.Metadata.Component.Type)
+
+		components := Components(merged)
+		refs := []string{}
+		for _, component := range components {
+			refs = append(refs, component.BOMRef)
+		}
+		assert.Equal(t, []string{
+			"pkg:maven/com.sap/backend@1.0.0",
+			"pkg:maven/org.slf4j/slf4j-api@1.7.36",
+			"pkg:maven/com.fasterxml.jackson.core/jackson-databind@2.13.3",
+			"pkg:npm/frontend@2.0.0",
+			"pkg:npm/lodash@4.17.21",
+		}, refs)
+
+		graph := map[string][]string{}
+		for _, dependency := range *merged.Dependencies {
+			graph[dependency.Ref] = []string{}
+			if dependency.Dependencies != nil {
+				for _, child := range *dependency.Dependencies {
+					graph[dependency.Ref] = append(graph[dependency.Ref], child.Ref)
+				}
+			}
+		}
+		assert.Equal(t, []string{"pkg:maven/com.sap/backend@1.0.0", "pkg:npm/frontend@2.0.0"}, graph["product@1.2.3"])
+		assert.Equal(t, []string{"pkg:maven/com.fasterxml.jackson.core/jackson-databind@2.13.3", "pkg:maven/org.slf4j/slf4j-api@1.7.36"}, graph["pkg:maven/com.sap/backend@1.0.0"])
+		assert.Equal(t, []string{"pkg:maven/org.slf4j/slf4j-api@1.7.36", "pkg:npm/lodash@4.17.21"}, graph["pkg:npm/frontend@2.0.0"])
+		assert.Equal(t, []string{}, graph["pkg:npm/lodash@4.17.21"])
+
+		assert.Empty(t, Validate(merged, nil))
+	})
+
+	t.Run("complete duplicates", func(t *testing.T) {
+		withoutHash := cdx.NewBOM()
+		withoutHash.Components = &[]cdx.Component{{Type: cdx.ComponentTypeLibrary, Name: "lib", Version: "1.0", PackageURL: "pkg:npm/lib@1.0"}}
+		withHash := cdx.NewBOM()
+		withHash.Components = &[]cdx.Component{{Type: cdx.ComponentTypeLibrary, Name: "lib", Version: "1.0", PackageURL: "pkg:npm/lib@1.0", Hashes: &[]cdx.Hash{{Algorithm: cdx.HashAlgoSHA1, Value: "6c62681a2f655b49963a5983b8b0950a6120ae14"}}}}
+
+		merged := Merge(cdx.Component{Name: "product", BOMRef: "product"}, []*cdx.BOM{withoutHash, nil, withHash})
+
+		components := Components(merged)
+		assert.Len(t, components, 1)
+		assert.Len(t, *components[0].Hashes, 1)
+	})
+}
@@ -0,0 +1,170 @@
+package sbom
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/package-url/packageurl-go"
+	"github.com/pkg/errors"
+)
+
+// Format defines the file format of a bill of materials
+type Format string
+
+const (
+	// FormatCycloneDXXML is the CycloneDX XML format
+	FormatCycloneDXXML Format = "cyclonedx-xml"
+	// FormatCycloneDXJSON is the CycloneDX JSON format
+	FormatCycloneDXJSON Format = "cyclonedx-json"
+	// FormatSPDXJSON is the SPDX 2.3 JSON format
+	FormatSPDXJSON Format = "spdx-json"
+)
+
+// fileReader abstracts reading a file, e.g. piperutils.Files
+type fileReader interface {
+	FileRead(path string) ([]byte, error)
+}
+
+// DetectFormat determines the format of a BOM based on its content, falling back to the file extension
+func DetectFormat(path string, content []byte) (Format, error) {
+	trimmed := bytes.TrimSpace(content)
+	if bytes.HasPrefix(trimmed, []byte("<")) {
+		return FormatCycloneDXXML, nil
+	}
+	if bytes.HasPrefix(trimmed, []byte("{")) {
+		if bytes.Contains(trimmed, []byte(`"spdxVersion"`)) {
+			return FormatSPDXJSON, nil
+		}
+		return FormatCycloneDXJSON, nil
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".xml":
+		return FormatCycloneDXXML, nil
+	case ".json":
+		return FormatCycloneDXJSON, nil
+	}
+	return "", fmt.Errorf("unable to detect format of BOM file '%v'", path)
+}
+
+// ReadBOM reads a BOM file in any of the supported formats and returns it as CycloneDX BOM
+func ReadBOM(path string, utils fileReader) (*cdx.BOM, error) {
+	content, err := utils.FileRead(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read BOM file '%v'", path)
+	}
+	format, err := DetectFormat(path, content)
+	if err != nil {
+		return nil, err
+	}
+	bom, err := Decode(content, format)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse BOM file '%v'", path)
+	}
+	return bom, nil
+}
+
+// Decode parses the content of a BOM in the given format
+func Decode(content []byte, format Format) (*cdx.BOM, error) {
+	switch format {
+	case FormatCycloneDXXML, FormatCycloneDXJSON:
+		bom := cdx.NewBOM()
+		fileFormat := cdx.BOMFileFormatXML
+		if format == FormatCycloneDXJSON {
+			fileFormat = cdx.BOMFileFormatJSON
+		}
+		if err := cdx.NewBOMDecoder(bytes.NewReader(content), fileFormat).Decode(bom); err != nil {
+			return nil, err
+		}
+		return bom, nil
+	case FormatSPDXJSON:
+		return decodeSPDX(content)
+	}
+	return nil, fmt.Errorf("unsupported BOM format '%v'", format)
+}
+
+// Encode serializes the BOM into the given format
+func Encode(bom *cdx.BOM, format Format) ([]byte, error) {
+	switch format {
+	case FormatCycloneDXXML, FormatCycloneDXJSON:
+		fileFormat := cdx.BOMFileFormatXML
+		if format == FormatCycloneDXJSON {
+			fileFormat = cdx.BOMFileFormatJSON
+		}
+		var buffer bytes.Buffer
+		encoder := cdx.NewBOMEncoder(&buffer, fileFormat)
+		encoder.SetPretty(true)
+		if err := encoder.Encode(bom); err != nil {
+			return nil, err
+		}
+		return buffer.Bytes(), nil
+	case FormatSPDXJSON:
+		return encodeSPDX(bom)
+	}
+	return nil, fmt.Errorf("unsupported BOM format '%v'", format)
+}
+
+// Components returns a flat list of all components of the BOM including nested components
+func Components(bom *cdx.BOM) []cdx.Component {
+	if bom == nil || bom.Components == nil {
+		return []cdx.Component{}
+	}
+	return flatten(*bom.Components)
+}
+
+func flatten(components []cdx.Component) []cdx.Component {
+	result := []cdx.Component{}
+	for _, component := range components {
+		result = append(result, component)
+		if component.Components != nil {
+			result = append(result, flatten(*component.Components)...)
+		}
+	}
+	return result
+}
+
+// ComponentKey returns a version-independent key identifying a component,
+// i.e. the package URL without version, qualifiers and subpath or group and name if no purl is available
+func ComponentKey(component cdx.Component) string {
+	if len(component.PackageURL) > 0 {
+		if purl, err := packageurl.FromString(component.PackageURL); err == nil {
+			return packageurl.NewPackageURL(purl.Type, purl.Namespace, purl.Name, "", nil, "").ToString()
+		}
+	}
+	if len(component.Group) > 0 {
+		return fmt.Sprintf("%v:%v", component.Group, component.Name)
+	}
+	return component.Name
+}
+
+// componentID returns a key identifying a dedicated version of a component
+func componentID(component cdx.Component) string {
+	if len(component.PackageURL) > 0 {
+		return component.PackageURL
+	}
+	if len(component.Version) > 0 {
+		return fmt.Sprintf("%v@%v", ComponentKey(component), component.Version)
+	}
+	return ComponentKey(component)
+}
+
+// LicenseIDs returns the license ids, names or expressions of a component
+func LicenseIDs(component cdx.Component) []string {
+	licenses := []string{}
+	if component.Licenses == nil {
+		return licenses
+	}
+	for _, choice := range *component.Licenses {
+		switch {
+		case len(choice.Expression) > 0:
+			licenses = append(licenses, choice.Expression)
+		case choice.License != nil && len(choice.License.ID) > 0:
+			licenses = append(licenses, choice.License.ID)
+		case choice.License != nil && len(choice.License.Name) > 0:
+			licenses = append(licenses, choice.License.Name)
+		}
+	}
+	return licenses
+}
@@ -0,0 +1,107 @@
+package sbom
+
+import (
+	"os"
+	"testing"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func readTestBOM(t *testing.T, path string) *cdx.BOM {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	utils := &mock.FilesMock{}
+	utils.AddFile(path, content)
+	bom, err := ReadBOM(path, utils)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bom
+}
+
+func TestDetectFormat(t *testing.T) {
+	tt := []struct {
+		path     string
+		content  string
+		expected Format
+	}{
+		{path: "bom.xml", content: " <?xml version=\"1.0\"?><bom/>", expected: FormatCycloneDXXML},
+		{path: "bom.json", content: "{\"bomFormat\": \"CycloneDX\"}", expected: FormatCycloneDXJSON},
+		{path: "bom.json", content: "{\"spdxVersion\": \"SPDX-2.3\"}", expected: FormatSPDXJSON},
+		{path: "bom.xml", content: "", expected: FormatCycloneDXXML},
+	}
+	for _, test := range tt {
+		format, err := DetectFormat(test.path, []byte(test.content))
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, format)
+	}
+
+	_, err := DetectFormat("bom.txt", []byte("something"))
+	assert.EqualError(t, err, "unable to detect format of BOM file 'bom.txt'")
+}
+
+func TestReadBOM(t *testing.T) {
+	t.Run("xml", func(t *testing.T) {
+		bom := readTestBOM(t, "testdata/bom-maven.xml")
+		assert.Equal(t, "backend", bom.Metadata.Component.Name)
+		assert.Len(t, Components(bom), 2)
+		assert.Len(t, *(*bom.Dependencies)[0].Dependencies, 2)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		bom := readTestBOM(t, "testdata/bom-npm.json")
+		assert.Equal(t, "frontend", bom.Metadata.Component.Name)
+		assert.Len(t, Components(bom), 2)
+	})
+
+	t.Run("file not found", func(t *testing.T) {
+		_, err := ReadBOM("not-there.xml", &mock.FilesMock{})
+		assert.Contains(t, err.Error(), "failed to read BOM file 'not-there.xml'")
+	})
+
+	t.Run("invalid content", func(t *testing.T) {
+		utils := &mock.FilesMock{}
+		utils.AddFile("bom.json", []byte("{invalid"))
+		_, err := ReadBOM("bom.json", utils)
+		assert.Contains(t, err.Error(), "failed to parse BOM file 'bom.json'")
+	})
+}
+
+func TestEncode(t *testing.T) {
+	bom := readTestBOM(t, "testdata/bom-maven.xml")
+
+	for _, format := range []Format{FormatCycloneDXXML, FormatCycloneDXJSON, FormatSPDXJSON} {
+		content, err := Encode(bom, format)
+		assert.NoError(t, err)
+		detected, err := DetectFormat("", content)
+		assert.NoError(t, err)
+		assert.Equal(t, format, detected)
+		decoded, err := Decode(content, format)
+		assert.NoError(t, err)
+		assert.Len(t, Components(decoded), 2, format)
+	}
+
+	_, err := Encode(bom, Format("csv"))
+	assert.EqualError(t, err, "unsupported BOM format 'csv'")
+}
+
+func TestComponentKey(t *testing.T) {
+	assert.Equal(t, "pkg:maven/org.slf4j/slf4j-api", ComponentKey(cdx.Component{Name: "slf4j-api", PackageURL: "pkg:maven/org.slf4j/slf4j-api@1.7.36?type=jar"}))
+	assert.Equal(t, "org.slf4j:slf4j-api", ComponentKey(cdx.Component{Group: "org.slf4j", Name: "slf4j-api", Version: "1.7.36"}))
+	assert.Equal(t, "lodash", ComponentKey(cdx.Component{Name: "lodash", Version: "4.17.21"}))
+}
+
+func TestLicenseIDs(t *testing.T) {
+	component := cdx.Component{Licenses: &cdx.Licenses{
+		{License: &cdx.License{ID: "MIT"}},
+		{License: &cdx.License{Name: "The Apache License"}},
+		{Expression: "EPL-2.0 OR GPL-2.0-with-classpath-exception"},
+		{License: &cdx.License{}},
+	}}
+	assert.Equal(t, []string{"MIT", "The Apache License", "EPL-2.0 OR GPL-2.0-with-classpath-exception"}, LicenseIDs(component))
+	assert.Empty(t, LicenseIDs(cdx.Component{}))
+}
@@ -0,0 +1,187 @@
+package sbom
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/pkg/errors"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+const cycloneDXNamespace = "http://cyclonedx.org/schema/bom/"
+
+// DefaultSchemaLocation is the official location of the CycloneDX JSON schemas
+const DefaultSchemaLocation = "https://cyclonedx.org/schema/"
+
+// SchemaValidator validates BOM files against the official CycloneDX JSON schema of their spec version
+type SchemaValidator struct {
+	// Location is the URL or the directory which contains the schema files bom-<specVersion>.schema.json
+	// as well as the schema files they refer to, e.g. spdx.schema.json
+	Location string
+	schemas  map[string]*gojsonschema.Schema
+}
+
+// NewSchemaValidator creates a validator which loads the schemas from the location, e.g. a mirror of https://cyclonedx.org/schema/.
+// The official location is used if location is empty.
+func NewSchemaValidator(location string) *SchemaValidator {
+	if len(location) == 0 {
+		location = DefaultSchemaLocation
+	}
+	return &SchemaValidator{Location: location, schemas: map[string]*gojsonschema.Schema{}}
+}
+
+// ValidateFile validates the BOM file against the schema of its spec version. There is no XML schema validation available,
+// hence a BOM in XML format is validated in its JSON representation. SPDX documents are not validated.
+func (v *SchemaValidator) ValidateFile(path string, utils fileReader) ([]ValidationIssue, error) {
+	content, err := utils.FileRead(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read BOM file '%v'", path)
+	}
+	format, err := DetectFormat(path, content)
+	if err != nil {
+		return nil, err
+	}
+
+	var specVersion string
+	switch format {
+	case FormatSPDXJSON:
+		return []ValidationIssue{}, nil
+	case FormatCycloneDXJSON:
+		header := struct {
+			SpecVersion string `json:"specVersion"`
+		}{}
+		if err := json.Unmarshal(content, &header); err != nil {
+			return []ValidationIssue{{Rule: RuleSchema, File: path, Message: fmt.Sprintf("invalid JSON: %v", err)}}, nil
+		}
+		specVersion = header.SpecVersion
+	case FormatCycloneDXXML:
+		if specVersion, err = xmlSpecVersion(content); err != nil {
+			return []ValidationIssue{{Rule: RuleSchema, File: path, Message: err.Error()}}, nil
+		}
+		bom, err := Decode(content, format)
+		if err != nil {
+			return []ValidationIssue{{Rule: RuleSchema, File: path, Message: fmt.Sprintf("invalid XML: %v", err)}}, nil
+		}
+		bom.SpecVersion = specVersion
+		if content, err = Encode(bom, FormatCycloneDXJSON); err != nil {
+			return nil, errors.Wrapf(err, "failed to convert BOM file '%v' to JSON", path)
+		}
+	}
+	if len(specVersion) == 0 {
+		return []ValidationIssue{{Rule: RuleSchema, File: path, Message: "specVersion is missing"}}, nil
+	}
+
+	schema, err := v.schema(specVersion)
+	if err != nil {
+		// e.g. an unknown spec version or a schema location which is not reachable from the build environment
+		return []ValidationIssue{{Rule: RuleSchema, File: path, Message: fmt.Sprintf("schema validation not possible: %v", err)}}, nil
+	}
+	result, err := schema.Validate(gojsonschema.NewBytesLoader(content))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to validate BOM file '%v'", path)
+	}
+	issues := []ValidationIssue{}
+	for _, resultError := range result.Errors() {
+		issues = append(issues, ValidationIssue{Rule: RuleSchema, File: path, Message: fmt.Sprintf("%v: %v", resultError.Field(), resultError.Description())})
+	}
+	return issues, nil
+}
+
+// xmlSpecVersion determines the spec version from the namespace of the root element, e.g. http://cyclonedx.org/schema/bom/1.4
+func xmlSpecVersion(content []byte) (string, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return "", fmt.Errorf("invalid XML: %v", err)
+		}
+		if element, ok := token.(xml.StartElement); ok {
+			if !strings.HasPrefix(element.Name.Space, cycloneDXNamespace) {
+				return "", fmt.Errorf("unknown namespace '%v' of the root element", element.Name.Space)
+			}
+			return strings.TrimPrefix(element.Name.Space, cycloneDXNamespace), nil
+		}
+	}
+}
+
+// schema loads and compiles the schema of the spec version including the schemas it refers to
+func (v *SchemaValidator) schema(specVersion string) (*gojsonschema.Schema, error) {
+	if schema, ok := v.schemas[specVersion]; ok {
+		return schema, nil
+	}
+	files, location := v.schemaFiles()
+	name := fmt.Sprintf("bom-%v.schema.json", specVersion)
+	content, err := readSchemaFile(files, location, name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load CycloneDX schema of spec version '%v' from '%v'", specVersion, location)
+	}
+	var document interface{}
+	if err := json.Unmarshal(content, &document); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse CycloneDX schema of spec version '%v'", specVersion)
+	}
+
+	// the official schemas identify themselves by their URL on cyclonedx.org, they refer to each other relatively to it
+	loader := gojsonschema.NewSchemaLoader()
+	for _, reference := range externalReferences(document, []string{}) {
+		referenced, err := readSchemaFile(files, location, reference)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to load schema '%v' from '%v'", reference, location)
+		}
+		if err := loader.AddSchemas(gojsonschema.NewBytesLoader(referenced)); err != nil {
+			return nil, errors.Wrapf(err, "failed to load schema '%v' from '%v'", reference, location)
+		}
+	}
+	schema, err := loader.Compile(gojsonschema.NewGoLoader(document))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to compile CycloneDX schema of spec version '%v'", specVersion)
+	}
+	v.schemas[specVersion] = schema
+	return schema, nil
+}
+
+// schemaFiles returns the file system providing the schemas, it is nil if the schemas are loaded from a URL
+func (v *SchemaValidator) schemaFiles() (fs.FS, string) {
+	if strings.Contains(v.Location, "://") {
+		return nil, v.Location
+	}
+	return os.DirFS(v.Location), v.Location
+}
+
+func readSchemaFile(files fs.FS, location, name string) ([]byte, error) {
+	if files != nil {
+		return fs.ReadFile(files, name)
+	}
+	document, err := gojsonschema.NewReferenceLoader(strings.TrimSuffix(location, "/") + "/" + name).LoadJSON()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(document)
+}
+
+// externalReferences returns the files referred to by $ref, e.g. "spdx.schema.json", references within the schema are ignored
+func externalReferences(node interface{}, references []string) []string {
+	switch value := node.(type) {
+	case map[string]interface{}:
+		for key, child := range value {
+			if reference, ok := child.(string); ok && key == "$ref" {
+				file := strings.SplitN(reference, "#", 2)[0]
+				if len(file) > 0 && !strings.Contains(file, "://") && !piperutils.ContainsString(references, file) {
+					references = append(references, file)
+				}
+				continue
+			}
+			references = externalReferences(child, references)
+		}
+	case []interface{}:
+		for _, child := range value {
+			references = externalReferences(child, references)
+		}
+	}
+	return references
+}
@@ -0,0 +1,108 @@
+package sbom
+
+import (
+	"os"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemaValidator(t *testing.T) {
+	files := func(t *testing.T, path string) *mock.FilesMock {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		utils := &mock.FilesMock{}
+		utils.AddFile(path, content)
+		return utils
+	}
+
+	t.Run("valid BOMs", func(t *testing.T) {
+		validator := NewSchemaValidator("testdata/schema")
+		for _, path := range []string{"testdata/bom-maven.xml", "testdata/bom-npm.json"} {
+			issues, err := validator.ValidateFile(path, files(t, path))
+			if assert.NoError(t, err, path) {
+				assert.Empty(t, issues, path)
+			}
+		}
+	})
+
+	t.Run("schema violations", func(t *testing.T) {
+		utils := &mock.FilesMock{}
+		utils.AddFile("bom.json", []byte(`{
+  "bomFormat": "CycloneDX",
+  "specVersion": "1.4",
+  "serialNumber": "1234",
+  "components": [
+    {"type": "unknown", "name": "a"},
+    {"type": "library", "licenses": [{"license": {"id": "NO-LICENSE"}}]}
+  ]
+}`))
+
+		issues, err := NewSchemaValidator("testdata/schema").ValidateFile("bom.json", utils)
+
+		if assert.NoError(t, err) {
+			messages := []string{}
+			for _, issue := range issues {
+				assert.Equal(t, RuleSchema, issue.Rule)
+				assert.Equal(t, "bom.json", issue.File)
+				messages = append(messages, issue.Message)
+			}
+			assert.Contains(t, messages, "serialNumber: Does not match pattern '^urn:uuid:[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$'")
+			assert.Contains(t, messages, "components.0.type: components.0.type must be one of the following: \"application\", \"framework\", \"library\", \"container\", \"operating-system\", \"device\", \"firmware\", \"file\"")
+			assert.Contains(t, messages, "components.1: name is required")
+			assert.Contains(t, messages, "components.1.licenses.0.license.id: components.1.licenses.0.license.id must be one of the following: \"Apache-2.0\", \"BSD-3-Clause\", \"MIT\"")
+		}
+	})
+
+	t.Run("spec version missing", func(t *testing.T) {
+		utils := &mock.FilesMock{}
+		utils.AddFile("bom.json", []byte(`{"bomFormat": "CycloneDX"}`))
+
+		issues, err := NewSchemaValidator("testdata/schema").ValidateFile("bom.json", utils)
+
+		if assert.NoError(t, err) {
+			assert.Equal(t, []ValidationIssue{{Rule: RuleSchema, File: "bom.json", Message: "specVersion is missing"}}, issues)
+		}
+	})
+
+	t.Run("unknown spec version", func(t *testing.T) {
+		utils := &mock.FilesMock{}
+		utils.AddFile("bom.json", []byte(`{"bomFormat": "CycloneDX", "specVersion": "0.9"}`))
+
+		issues, err := NewSchemaValidator("testdata/schema").ValidateFile("bom.json", utils)
+
+		if assert.NoError(t, err) && assert.Len(t, issues, 1) {
+			assert.Equal(t, RuleSchema, issues[0].Rule)
+			assert.Contains(t, issues[0].Message, "schema validation not possible: failed to load CycloneDX schema of spec version '0.9' from 'testdata/schema'")
+		}
+	})
+
+	t.Run("schema location not available", func(t *testing.T) {
+		utils := &mock.FilesMock{}
+		utils.AddFile("bom.json", []byte(`{"bomFormat": "CycloneDX", "specVersion": "1.4"}`))
+
+		issues, err := NewSchemaValidator("http://localhost:0/schema/").ValidateFile("bom.json", utils)
+
+		if assert.NoError(t, err) && assert.Len(t, issues, 1) {
+			assert.Contains(t, issues[0].Message, "schema validation not possible: failed to load CycloneDX schema of spec version '1.4' from 'http://localhost:0/schema/'")
+		}
+	})
+
+	t.Run("default schema location", func(t *testing.T) {
+		assert.Equal(t, DefaultSchemaLocation, NewSchemaValidator("").Location)
+	})
+
+	t.Run("SPDX is not validated", func(t *testing.T) {
+		utils := &mock.FilesMock{}
+		utils.AddFile("sbom.spdx.json", []byte(`{"spdxVersion": "SPDX-2.3"}`))
+
+		issues, err := NewSchemaValidator("testdata/schema").ValidateFile("sbom.spdx.json", utils)
+
+		if assert.NoError(t, err) {
+			assert.Empty(t, issues)
+		}
+	})
+}
@@ -0,0 +1,294 @@
+package sbom
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+)
+
+const (
+	spdxVersion      = "SPDX-2.3"
+	spdxDocumentID   = "SPDXRef-DOCUMENT"
+	spdxNoAssertion  = "NOASSERTION"
+	spdxNone         = "NONE"
+	spdxDependsOn    = "DEPENDS_ON"
+	spdxDependencyOf = "DEPENDENCY_OF"
+	spdxDescribes    = "DESCRIBES"
+)
+
+type spdxDocument struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	DocumentDescribes []string           `json:"documentDescribes,omitempty"`
+	Packages          []spdxPackage      `json:"packages"`
+	Relationships     []spdxRelationship `json:"relationships,omitempty"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	SPDXID           string            `json:"SPDXID"`
+	Name             string            `json:"name"`
+	VersionInfo      string            `json:"versionInfo,omitempty"`
+	Supplier         string            `json:"supplier,omitempty"`
+	DownloadLocation string            `json:"downloadLocation"`
+	FilesAnalyzed    bool              `json:"filesAnalyzed"`
+	LicenseConcluded string            `json:"licenseConcluded"`
+	LicenseDeclared  string            `json:"licenseDeclared"`
+	CopyrightText    string            `json:"copyrightText"`
+	Checksums        []spdxChecksum    `json:"checksums,omitempty"`
+	ExternalRefs     []spdxExternalRef `json:"externalRefs,omitempty"`
+}
+
+type spdxChecksum struct {
+	Algorithm     string `json:"algorithm"`
+	ChecksumValue string `json:"checksumValue"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+var spdxChecksumAlgorithms = map[cdx.HashAlgorithm]string{
+	cdx.HashAlgoMD5:         "MD5",
+	cdx.HashAlgoSHA1:        "SHA1",
+	cdx.HashAlgoSHA256:      "SHA256",
+	cdx.HashAlgoSHA384:      "SHA384",
+	cdx.HashAlgoSHA512:      "SHA512",
+	cdx.HashAlgoSHA3_256:    "SHA3-256",
+	cdx.HashAlgoSHA3_512:    "SHA3-512",
+	cdx.HashAlgoBlake2b_256: "BLAKE2b-256",
+	cdx.HashAlgoBlake2b_384: "BLAKE2b-384",
+	cdx.HashAlgoBlake2b_512: "BLAKE2b-512",
+	cdx.HashAlgoBlake3:      "BLAKE3",
+}
+
+var spdxIDInvalidChars = regexp.MustCompile(`[^a-zA-Z0-9.-]`)
+
+func encodeSPDX(bom *cdx.BOM) ([]byte, error) {
+	document := spdxDocument{
+		SPDXVersion:  spdxVersion,
+		DataLicense:  "CC0-1.0",
+		SPDXID:       spdxDocumentID,
+		CreationInfo: spdxCreationInfo{Creators: []string{"Tool: piper"}},
+		Packages:     []spdxPackage{},
+	}
+
+	ids := map[string]string{}
+	addPackage := func(component cdx.Component) string {
+		ref := component.BOMRef
+		if len(ref) == 0 {
+			ref = componentID(component)
+		}
+		if id, ok := ids[ref]; ok {
+			return id
+		}
+		id := fmt.Sprintf("SPDXRef-Package-%v-%v", len(document.Packages)+1, spdxIDInvalidChars.ReplaceAllString(component.Name, "-"))
+		ids[ref] = id
+		document.Packages = append(document.Packages, toSPDXPackage(id, component))
+		return id
+	}
+
+	if bom.Metadata != nil && bom.Metadata.Component != nil {
+		main := *bom.Metadata.Component
+		document.Name = strings.TrimSuffix(fmt.Sprintf("%v-%v", main.Name, main.Version), "-")
+		document.DocumentDescribes = []string{addPackage(main)}
+	}
+	if bom.Metadata != nil && len(bom.Metadata.Timestamp) > 0 {
+		document.CreationInfo.Created = bom.Metadata.Timestamp
+	} else {
+		document.CreationInfo.Created = time.Now().UTC().Format(time.RFC3339)
+	}
+	for _, component := range Components(bom) {
+		addPackage(component)
+	}
+	if len(document.Name) == 0 {
+		document.Name = "sbom"
+	}
+
+	for _, described := range document.DocumentDescribes {
+		document.Relationships = append(document.Relationships, spdxRelationship{SPDXElementID: spdxDocumentID, RelationshipType: spdxDescribes, RelatedSPDXElement: described})
+	}
+	if bom.Dependencies != nil {
+		for _, dependency := range *bom.Dependencies {
+			if dependency.Dependencies == nil || len(ids[dependency.Ref]) == 0 {
+				continue
+			}
+			for _, child := range *dependency.Dependencies {
+				if len(ids[child.Ref]) == 0 {
+					continue
+				}
+				document.Relationships = append(document.Relationships, spdxRelationship{SPDXElementID: ids[dependency.Ref], RelationshipType: spdxDependsOn, RelatedSPDXElement: ids[child.Ref]})
+			}
+		}
+	}
+
+	namespace := bom.SerialNumber
+	if len(namespace) == 0 {
+		refs := make([]string, 0, len(ids))
+		for ref := range ids {
+			refs = append(refs, ref)
+		}
+		sort.Strings(refs)
+		namespace = fmt.Sprintf("%x", sha256.Sum256([]byte(strings.Join(refs, "\n"))))
+	}
+	document.DocumentNamespace = fmt.Sprintf("https://spdx.org/spdxdocs/%v-%v", spdxIDInvalidChars.ReplaceAllString(document.Name, "-"), strings.TrimPrefix(namespace, "urn:uuid:"))
+
+	return json.MarshalIndent(document, "", "  ")
+}
+
+func toSPDXPackage(id string, component cdx.Component) spdxPackage {
+	pkg := spdxPackage{
+		SPDXID:           id,
+		Name:             component.Name,
+		VersionInfo:      component.Version,
+		DownloadLocation: spdxNoAssertion,
+		LicenseConcluded: spdxNoAssertion,
+		LicenseDeclared:  spdxNoAssertion,
+		CopyrightText:    spdxNoAssertion,
+	}
+	if len(component.Group) > 0 {
+		pkg.Name = fmt.Sprintf("%v:%v", component.Group, component.Name)
+	}
+	if component.Supplier != nil && len(component.Supplier.Name) > 0 {
+		pkg.Supplier = fmt.Sprintf("Organization: %v", component.Supplier.Name)
+	}
+	if len(component.Copyright) > 0 {
+		pkg.CopyrightText = component.Copyright
+	}
+	if licenses := LicenseIDs(component); len(licenses) > 0 {
+		pkg.LicenseDeclared = strings.Join(licenses, " AND ")
+	}
+	if component.Hashes != nil {
+		for _, hash := range *component.Hashes {
+			if algorithm, ok := spdxChecksumAlgorithms[hash.Algorithm]; ok {
+				pkg.Checksums = append(pkg.Checksums, spdxChecksum{Algorithm: algorithm, ChecksumValue: hash.Value})
+			}
+		}
+	}
+	if len(component.PackageURL) > 0 {
+		pkg.ExternalRefs = append(pkg.ExternalRefs, spdxExternalRef{ReferenceCategory: "PACKAGE-MANAGER", ReferenceType: "purl", ReferenceLocator: component.PackageURL})
+	}
+	if len(component.CPE) > 0 {
+		pkg.ExternalRefs = append(pkg.ExternalRefs, spdxExternalRef{ReferenceCategory: "SECURITY", ReferenceType: "cpe23Type", ReferenceLocator: component.CPE})
+	}
+	return pkg
+}
+
+func decodeSPDX(content []byte) (*cdx.BOM, error) {
+	document := spdxDocument{}
+	if err := json.Unmarshal(content, &document); err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(document.SPDXVersion, "SPDX-2.") {
+		return nil, fmt.Errorf("unsupported SPDX version '%v'", document.SPDXVersion)
+	}
+
+	described := map[string]bool{}
+	for _, id := range document.DocumentDescribes {
+		described[id] = true
+	}
+	for _, relationship := range document.Relationships {
+		if relationship.SPDXElementID == spdxDocumentID && relationship.RelationshipType == spdxDescribes {
+			described[relationship.RelatedSPDXElement] = true
+		}
+	}
+
+	bom := cdx.NewBOM()
+	bom.Metadata = &cdx.Metadata{Timestamp: document.CreationInfo.Created}
+	components := []cdx.Component{}
+	for _, pkg := range document.Packages {
+		component := fromSPDXPackage(pkg)
+		if described[pkg.SPDXID] && bom.Metadata.Component == nil {
+			component.Type = cdx.ComponentTypeApplication
+			bom.Metadata.Component = &component
+			continue
+		}
+		components = append(components, component)
+	}
+	bom.Components = &components
+
+	graph := map[string]map[string]bool{}
+	addEdge := func(from, to string) {
+		if graph[from] == nil {
+			graph[from] = map[string]bool{}
+		}
+		graph[from][to] = true
+	}
+	for _, relationship := range document.Relationships {
+		switch relationship.RelationshipType {
+		case spdxDependsOn:
+			addEdge(relationship.SPDXElementID, relationship.RelatedSPDXElement)
+		case spdxDependencyOf:
+			addEdge(relationship.RelatedSPDXElement, relationship.SPDXElementID)
+		}
+	}
+	dependencies := dependencyList(graph)
+	bom.Dependencies = &dependencies
+	return bom, nil
+}
+
+func fromSPDXPackage(pkg spdxPackage) cdx.Component {
+	component := cdx.Component{
+		BOMRef:  pkg.SPDXID,
+		Type:    cdx.ComponentTypeLibrary,
+		Name:    pkg.Name,
+		Version: pkg.VersionInfo,
+	}
+	if parts := strings.SplitN(pkg.Name, ":", 2); len(parts) == 2 {
+		component.Group, component.Name = parts[0], parts[1]
+	}
+	if supplier := strings.TrimPrefix(strings.TrimPrefix(pkg.Supplier, "Organization: "), "Person: "); len(supplier) > 0 && supplier != spdxNoAssertion {
+		component.Supplier = &cdx.OrganizationalEntity{Name: supplier}
+	}
+	if pkg.CopyrightText != spdxNoAssertion && pkg.CopyrightText != spdxNone {
+		component.Copyright = pkg.CopyrightText
+	}
+	license := pkg.LicenseDeclared
+	if license == spdxNoAssertion || license == spdxNone || len(license) == 0 {
+		license = pkg.LicenseConcluded
+	}
+	if license != spdxNoAssertion && license != spdxNone && len(license) > 0 {
+		component.Licenses = &cdx.Licenses{{Expression: license}}
+	}
+	for _, checksum := range pkg.Checksums {
+		for algorithm, spdxAlgorithm := range spdxChecksumAlgorithms {
+			if spdxAlgorithm == checksum.Algorithm {
+				if component.Hashes == nil {
+					component.Hashes = &[]cdx.Hash{}
+				}
+				*component.Hashes = append(*component.Hashes, cdx.Hash{Algorithm: algorithm, Value: checksum.ChecksumValue})
+			}
+		}
+	}
+	for _, ref := range pkg.ExternalRefs {
+		switch ref.ReferenceType {
+		case "purl":
+			component.PackageURL = ref.ReferenceLocator
+		case "cpe23Type", "cpe22Type":
+			component.CPE = ref.ReferenceLocator
+		}
+	}
+	return component
+}
@@ -0,0 +1,58 @@
+package sbom
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSPDX(t *testing.T) {
+	t.Run("convert CycloneDX to SPDX", func(t *testing.T) {
+		bom := readTestBOM(t, "testdata/bom-maven.xml")
+		bom.Metadata.Timestamp = "2022-10-01T10:00:00Z"
+
+		content, err := Encode(bom, FormatSPDXJSON)
+		assert.NoError(t, err)
+
+		document := spdxDocument{}
+		assert.NoError(t, json.Unmarshal(content, &document))
+		assert.Equal(t, "SPDX-2.3", document.SPDXVersion)
+		assert.Equal(t, "backend-1.0.0", document.Name)
+		assert.Equal(t, "https://spdx.org/spdxdocs/backend-1.0.0-3e671687-395b-41f5-a30f-a58921a69b79", document.DocumentNamespace)
+		assert.Equal(t, "2022-10-01T10:00:00Z", document.CreationInfo.Created)
+		assert.Equal(t, []string{"SPDXRef-Package-1-backend"}, document.DocumentDescribes)
+		assert.Len(t, document.Packages, 3)
+		slf4j := document.Packages[1]
+		assert.Equal(t, "org.slf4j:slf4j-api", slf4j.Name)
+		assert.Equal(t, "MIT", slf4j.LicenseDeclared)
+		assert.Equal(t, []spdxChecksum{{Algorithm: "SHA1", ChecksumValue: "6c62681a2f655b49963a5983b8b0950a6120ae14"}}, slf4j.Checksums)
+		assert.Equal(t, []spdxExternalRef{{ReferenceCategory: "PACKAGE-MANAGER", ReferenceType: "purl", ReferenceLocator: "pkg:maven/org.slf4j/slf4j-api@1.7.36"}}, slf4j.ExternalRefs)
+		assert.Contains(t, document.Relationships, spdxRelationship{SPDXElementID: "SPDXRef-DOCUMENT", RelationshipType: "DESCRIBES", RelatedSPDXElement: "SPDXRef-Package-1-backend"})
+		assert.Contains(t, document.Relationships, spdxRelationship{SPDXElementID: "SPDXRef-Package-1-backend", RelationshipType: "DEPENDS_ON", RelatedSPDXElement: "SPDXRef-Package-2-slf4j-api"})
+	})
+
+	t.Run("convert SPDX to CycloneDX", func(t *testing.T) {
+		content, err := Encode(readTestBOM(t, "testdata/bom-maven.xml"), FormatSPDXJSON)
+		assert.NoError(t, err)
+
+		bom, err := Decode(content, FormatSPDXJSON)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "backend", bom.Metadata.Component.Name)
+		assert.Equal(t, "com.sap", bom.Metadata.Component.Group)
+		components := Components(bom)
+		assert.Len(t, components, 2)
+		assert.Equal(t, "pkg:maven/org.slf4j/slf4j-api@1.7.36", components[0].PackageURL)
+		assert.Equal(t, []string{"MIT"}, LicenseIDs(components[0]))
+		assert.Equal(t, "6c62681a2f655b49963a5983b8b0950a6120ae14", (*components[0].Hashes)[0].Value)
+		assert.Len(t, *bom.Dependencies, 1)
+		assert.Len(t, *(*bom.Dependencies)[0].Dependencies, 2)
+		assert.Empty(t, Validate(bom, nil))
+	})
+
+	t.Run("unsupported version", func(t *testing.T) {
+		_, err := Decode([]byte(`{"spdxVersion": "SPDX-3.0"}`), FormatSPDXJSON)
+		assert.EqualError(t, err, "unsupported SPDX version 'SPDX-3.0'")
+	})
+}
@@ -0,0 +1,110 @@
+package sbom
+
+import (
+	"fmt"
+	"strings"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+)
+
+// Minimum elements which can be requested for each component of a BOM
+const (
+	ElementPurl     = "purl"
+	ElementLicenses = "licenses"
+	ElementHashes   = "hashes"
+	ElementVersion  = "version"
+	ElementSupplier = "supplier"
+)
+
+// Rule identifiers of validation issues
+const (
+	RuleSchema          = "schema"
+	RuleMinimumElements = "minimumElements"
+	RuleReferences      = "references"
+)
+
+// ValidationIssue describes a problem found in a BOM
+type ValidationIssue struct {
+	Rule string `json:"rule"`
+	// File is the BOM file with the issue, it is empty for issues of the product BOM
+	File      string `json:"file,omitempty"`
+	Component string `json:"component,omitempty"`
+	Message   string `json:"message"`
+}
+
+func (i ValidationIssue) String() string {
+	message := fmt.Sprintf("%v: %v", i.Rule, i.Message)
+	if len(i.Component) > 0 {
+		message = fmt.Sprintf("%v (%v)", message, i.Component)
+	}
+	if len(i.File) > 0 {
+		message = fmt.Sprintf("%v in '%v'", message, i.File)
+	}
+	return message
+}
+
+// Validate verifies that every component of the BOM contains the requested minimum elements, that bom-refs are unique
+// and that the dependencies only refer to components of the BOM. The structure of BOM files is validated by SchemaValidator.
+func Validate(bom *cdx.BOM, requiredElements []string) []ValidationIssue {
+	issues := []ValidationIssue{}
+	if bom == nil {
+		return append(issues, ValidationIssue{Rule: RuleSchema, Message: "BOM is empty"})
+	}
+
+	refs := map[string]bool{}
+	if bom.Metadata != nil && bom.Metadata.Component != nil && len(bom.Metadata.Component.BOMRef) > 0 {
+		refs[bom.Metadata.Component.BOMRef] = true
+	}
+	for _, component := range Components(bom) {
+		if len(component.BOMRef) > 0 {
+			if refs[component.BOMRef] {
+				issues = append(issues, ValidationIssue{Rule: RuleReferences, Component: componentID(component), Message: fmt.Sprintf("duplicate bom-ref '%v'", component.BOMRef)})
+			}
+			refs[component.BOMRef] = true
+		}
+		issues = append(issues, validateMinimumElements(component, requiredElements)...)
+	}
+
+	if bom.Dependencies != nil {
+		for _, dependency := range *bom.Dependencies {
+			if !refs[dependency.Ref] {
+				issues = append(issues, ValidationIssue{Rule: RuleReferences, Message: fmt.Sprintf("dependency references unknown bom-ref '%v'", dependency.Ref)})
+			}
+			if dependency.Dependencies == nil {
+				continue
+			}
+			for _, child := range *dependency.Dependencies {
+				if !refs[child.Ref] {
+					issues = append(issues, ValidationIssue{Rule: RuleReferences, Component: dependency.Ref, Message: fmt.Sprintf("dependency references unknown bom-ref '%v'", child.Ref)})
+				}
+			}
+		}
+	}
+	return issues
+}
+
+func validateMinimumElements(component cdx.Component, requiredElements []string) []ValidationIssue {
+	issues := []ValidationIssue{}
+	id := componentID(component)
+	for _, element := range requiredElements {
+		missing := false
+		switch strings.ToLower(element) {
+		case ElementPurl:
+			missing = len(component.PackageURL) == 0
+		case ElementLicenses:
+			missing = len(LicenseIDs(component)) == 0
+		case ElementHashes:
+			missing = component.Hashes == nil || len(*component.Hashes) == 0
+		case ElementVersion:
+			missing = len(component.Version) == 0
+		case ElementSupplier:
+			missing = component.Supplier == nil || len(component.Supplier.Name) == 0
+		default:
+			continue
+		}
+		if missing {
+			issues = append(issues, ValidationIssue{Rule: RuleMinimumElements, Component: id, Message: fmt.Sprintf("required element '%v' is missing", element)})
+		}
+	}
+	return issues
+}
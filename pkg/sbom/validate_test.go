@@ -0,0 +1,45 @@
+package sbom
+
+import (
+	"testing"
+
+	cdx "github.com/CycloneDX/cyclonedx-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	t.Run("valid BOM", func(t *testing.T) {
+		bom := readTestBOM(t, "testdata/bom-maven.xml")
+		assert.Empty(t, Validate(bom, nil))
+	})
+
+	t.Run("minimum elements", func(t *testing.T) {
+		bom := readTestBOM(t, "testdata/bom-maven.xml")
+		issues := Validate(bom, []string{ElementPurl, ElementLicenses, ElementHashes, "unknown"})
+		assert.Equal(t, []ValidationIssue{
+			{Rule: RuleMinimumElements, Component: "pkg:maven/com.fasterxml.jackson.core/jackson-databind@2.13.3", Message: "required element 'licenses' is missing"},
+			{Rule: RuleMinimumElements, Component: "pkg:maven/com.fasterxml.jackson.core/jackson-databind@2.13.3", Message: "required element 'hashes' is missing"},
+		}, issues)
+	})
+
+	t.Run("references", func(t *testing.T) {
+		bom := cdx.NewBOM()
+		bom.Components = &[]cdx.Component{
+			{BOMRef: "a", Type: cdx.ComponentTypeLibrary, Name: "a"},
+			{BOMRef: "a", Type: cdx.ComponentTypeLibrary, Name: "b"},
+		}
+		bom.Dependencies = &[]cdx.Dependency{{Ref: "a", Dependencies: &[]cdx.Dependency{{Ref: "c"}}}, {Ref: "d"}}
+
+		issues := Validate(bom, nil)
+
+		assert.Equal(t, []ValidationIssue{
+			{Rule: RuleReferences, Component: "b", Message: "duplicate bom-ref 'a'"},
+			{Rule: RuleReferences, Component: "a", Message: "dependency references unknown bom-ref 'c'"},
+			{Rule: RuleReferences, Message: "dependency references unknown bom-ref 'd'"},
+		}, issues)
+	})
+
+	t.Run("empty BOM", func(t *testing.T) {
+		assert.Equal(t, []ValidationIssue{{Rule: RuleSchema, Message: "BOM is empty"}}, Validate(nil, nil))
+	})
+}
@@ -0,0 +1,169 @@
+package scancache
+
+import (
+	"bufio"
+	"bytes"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/pkg/errors"
+)
+
+// DependencyDescriptors are the files which define the dependencies of a project, i.e. its bill of materials.
+// A dependency scan of a project yields the same result as long as these files are unchanged.
+var DependencyDescriptors = []string{
+	"pom.xml", "lockfile.json",
+	"build.gradle", "build.gradle.kts", "settings.gradle", "settings.gradle.kts", "gradle.lockfile",
+	"package.json", "package-lock.json", "npm-shrinkwrap.json", "yarn.lock", "pnpm-lock.yaml",
+	"go.mod", "go.sum",
+	"requirements.txt", "setup.py", "setup.cfg", "pyproject.toml", "poetry.lock", "Pipfile", "Pipfile.lock",
+	"Gemfile", "Gemfile.lock",
+	"Cargo.toml", "Cargo.lock",
+	"packages.config", "packages.lock.json", "*.csproj",
+	"composer.json", "composer.lock",
+	"Dockerfile",
+	"mta.yaml",
+}
+
+// descriptorExcludes are directories which contain installed dependencies or build output
+var descriptorExcludes = []string{"node_modules", ".git", ".pipeline", "target", "vendor"}
+
+// FindDependencyDescriptors returns the dependency descriptors below the directory.
+// Descriptors of installed dependencies, e.g. within node_modules, are ignored.
+func FindDependencyDescriptors(utils piperutils.FileUtils, dir string) ([]string, error) {
+	descriptors := []string{}
+	for _, name := range DependencyDescriptors {
+		matches, err := utils.Glob(filepath.Join(dir, "**", name))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to find files matching '%v'", name)
+		}
+		for _, match := range matches {
+			if !isExcludedDescriptor(match) && !piperutils.ContainsString(descriptors, match) {
+				descriptors = append(descriptors, match)
+			}
+		}
+	}
+	sort.Strings(descriptors)
+	return descriptors, nil
+}
+
+func isExcludedDescriptor(path string) bool {
+	for _, part := range strings.Split(filepath.ToSlash(path), "/") {
+		if piperutils.ContainsString(descriptorExcludes, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// HashDependencyDescriptors calculates the hash of the bill of materials of the project below the directory
+func HashDependencyDescriptors(utils piperutils.FileUtils, dir string) (string, error) {
+	descriptors, err := FindDependencyDescriptors(utils, dir)
+	if err != nil {
+		return "", err
+	}
+	if len(descriptors) == 0 {
+		return "", errors.Errorf("no dependency descriptors found in '%v'", dir)
+	}
+	return HashFiles(utils, descriptors)
+}
+
+// lockFiles are the files which pin the resolved versions of all dependencies declared by a dependency descriptor.
+// Without one of them, the same descriptor may yield different dependencies on every resolution.
+var lockFiles = map[string][]string{
+	"pom.xml":          {"lockfile.json"},
+	"build.gradle":     {"gradle.lockfile"},
+	"build.gradle.kts": {"gradle.lockfile"},
+	"package.json":     {"package-lock.json", "npm-shrinkwrap.json", "yarn.lock", "pnpm-lock.yaml"},
+	"pyproject.toml":   {"poetry.lock"},
+	"Pipfile":          {"Pipfile.lock"},
+	"Gemfile":          {"Gemfile.lock"},
+	"Cargo.toml":       {"Cargo.lock"},
+	"composer.json":    {"composer.lock"},
+}
+
+var pinnedRequirement = regexp.MustCompile(`^[A-Za-z0-9._\[\],-]+\s*===?\s*[^*,;<>=!~\s]+(\s*;.*)?$`)
+
+// CheckPinnedDependencies returns an error if a dependency descriptor below the directory does not pin the versions
+// of its dependencies, i.e. if a scan of the unchanged descriptors may yield a different result.
+func CheckPinnedDependencies(utils piperutils.FileUtils, dir string) error {
+	descriptors, err := FindDependencyDescriptors(utils, dir)
+	if err != nil {
+		return err
+	}
+	for _, descriptor := range descriptors {
+		name := filepath.Base(descriptor)
+		if candidates, ok := lockFiles[name]; ok && !hasLockFile(utils, dir, filepath.Dir(descriptor), candidates) {
+			return errors.Errorf("'%v' does not pin its dependencies, none of %v found", descriptor, candidates)
+		}
+		switch name {
+		case "pom.xml", "build.gradle", "build.gradle.kts":
+			content, err := utils.FileRead(descriptor)
+			if err != nil {
+				return errors.Wrapf(err, "failed to read '%v'", descriptor)
+			}
+			if bytes.Contains(content, []byte("-SNAPSHOT")) {
+				return errors.Errorf("'%v' refers to SNAPSHOT versions", descriptor)
+			}
+		case "requirements.txt":
+			content, err := utils.FileRead(descriptor)
+			if err != nil {
+				return errors.Wrapf(err, "failed to read '%v'", descriptor)
+			}
+			scanner := bufio.NewScanner(bytes.NewReader(content))
+			for scanner.Scan() {
+				line := strings.TrimSpace(strings.SplitN(scanner.Text(), "#", 2)[0])
+				if len(line) == 0 || strings.HasPrefix(line, "-") {
+					continue
+				}
+				if !pinnedRequirement.MatchString(line) {
+					return errors.Errorf("'%v' contains the version range '%v'", descriptor, line)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// hasLockFile checks for one of the lock files in the directory of the descriptor or one of its parents up to the root
+// directory, e.g. workspaces of npm share the lock file of the root project
+func hasLockFile(utils piperutils.FileUtils, root, dir string, candidates []string) bool {
+	root = filepath.Clean(root)
+	for {
+		for _, candidate := range candidates {
+			if exists, _ := utils.FileExists(filepath.Join(dir, candidate)); exists {
+				return true
+			}
+		}
+		if dir == root || dir == "." || dir == filepath.Dir(dir) {
+			return false
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+// HashSourceTree calculates the hash of all files below the directory, e.g. for scans which fingerprint the sources
+// in addition to the dependencies. Directories with installed dependencies or build output are ignored.
+func HashSourceTree(utils piperutils.FileUtils, dir string) (string, error) {
+	matches, err := utils.Glob(filepath.Join(dir, "**"))
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to find files below '%v'", dir)
+	}
+	files := []string{}
+	for _, match := range matches {
+		if isExcludedDescriptor(match) {
+			continue
+		}
+		if isDir, _ := utils.DirExists(match); isDir {
+			continue
+		}
+		files = append(files, match)
+	}
+	if len(files) == 0 {
+		return "", errors.Errorf("no files found in '%v'", dir)
+	}
+	return HashFiles(utils, files)
+}
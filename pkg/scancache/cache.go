@@ -0,0 +1,195 @@
+package scancache
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/pkg/errors"
+)
+
+// Entry is the cached result of a scan. It contains the files written by the step, e.g. reports and the toolrecord,
+// as well as the outputs which are required to reproduce the result of the step without calling the scan backend.
+type Entry struct {
+	Key     string                     `json:"key"`
+	Step    string                     `json:"step"`
+	Created time.Time                  `json:"created"`
+	Files   []File                     `json:"files"`
+	Outputs map[string]json.RawMessage `json:"outputs,omitempty"`
+	// Error is the message with which the step failed, e.g. due to findings
+	Error string `json:"error,omitempty"`
+	// ErrorCategory is the category of the failure, e.g. "compliance"
+	ErrorCategory string `json:"errorCategory,omitempty"`
+}
+
+// File is a file which is restored on a cache hit
+type File struct {
+	Path    string `json:"path"`
+	Content []byte `json:"content"`
+}
+
+// NewEntry creates an empty entry for the given key
+func NewEntry(step, key string) *Entry {
+	return &Entry{Key: key, Step: step, Outputs: map[string]json.RawMessage{}}
+}
+
+// AddFiles adds all files matching the patterns to the entry
+func (e *Entry) AddFiles(utils piperutils.FileUtils, patterns ...string) error {
+	for _, pattern := range patterns {
+		matches, err := utils.Glob(pattern)
+		if err != nil {
+			return errors.Wrapf(err, "failed to find files matching '%v'", pattern)
+		}
+		for _, match := range matches {
+			if e.hasFile(match) {
+				continue
+			}
+			if isDir, _ := utils.DirExists(match); isDir {
+				continue
+			}
+			content, err := utils.FileRead(match)
+			if err != nil {
+				return errors.Wrapf(err, "failed to read '%v'", match)
+			}
+			e.Files = append(e.Files, File{Path: filepath.ToSlash(match), Content: content})
+		}
+	}
+	return nil
+}
+
+func (e *Entry) hasFile(path string) bool {
+	for _, file := range e.Files {
+		if file.Path == filepath.ToSlash(path) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetOutput stores a value which is not contained in a file, e.g. influx or common pipeline environment values
+func (e *Entry) SetOutput(name string, value interface{}) error {
+	output, err := json.Marshal(value)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal output '%v'", name)
+	}
+	if e.Outputs == nil {
+		e.Outputs = map[string]json.RawMessage{}
+	}
+	e.Outputs[name] = output
+	return nil
+}
+
+// Output reads a value stored with SetOutput. It returns false if the entry does not contain the output.
+func (e *Entry) Output(name string, value interface{}) (bool, error) {
+	output, ok := e.Outputs[name]
+	if !ok {
+		return false, nil
+	}
+	if err := json.Unmarshal(output, value); err != nil {
+		return true, errors.Wrapf(err, "failed to unmarshal output '%v'", name)
+	}
+	return true, nil
+}
+
+// Restore writes the files of the entry into the workspace
+func (e *Entry) Restore(utils piperutils.FileUtils) error {
+	for _, file := range e.Files {
+		path := filepath.FromSlash(file.Path)
+		if dir := filepath.Dir(path); dir != "." {
+			if err := utils.MkdirAll(dir, 0777); err != nil {
+				return errors.Wrapf(err, "failed to create directory for '%v'", file.Path)
+			}
+		}
+		if err := utils.FileWrite(path, file.Content, 0666); err != nil {
+			return errors.Wrapf(err, "failed to restore '%v'", file.Path)
+		}
+	}
+	return nil
+}
+
+// Store persists cache entries
+type Store interface {
+	// Load returns nil if there is no entry for the key
+	Load(key string) (*Entry, error)
+	Save(entry *Entry) error
+}
+
+// Cache provides scan results which have been stored by previous runs.
+// Errors of the underlying store are only logged since the cache must never fail a step.
+// A nil cache is valid and behaves like a cache without entries.
+type Cache struct {
+	store Store
+	ttl   time.Duration
+	now   func() time.Time
+}
+
+// New creates a cache which ignores entries older than the time to live
+func New(store Store, ttl time.Duration) *Cache {
+	return &Cache{store: store, ttl: ttl, now: time.Now}
+}
+
+// Lookup returns the entry for the key or nil in case there is no valid entry
+func (c *Cache) Lookup(key string) *Entry {
+	if c == nil {
+		return nil
+	}
+	entry, err := c.store.Load(key)
+	if err != nil {
+		log.Entry().WithError(err).Warn("failed to read scan cache, scanning without cache")
+		return nil
+	}
+	if entry == nil {
+		log.Entry().Infof("No cached scan result found for key %v", key)
+		return nil
+	}
+	if c.ttl > 0 && entry.Created.Add(c.ttl).Before(c.now()) {
+		log.Entry().Infof("Cached scan result for key %v from %v has expired", key, entry.Created.Format(time.RFC3339))
+		return nil
+	}
+	log.Entry().Infof("Reusing cached scan result for key %v from %v", key, entry.Created.Format(time.RFC3339))
+	return entry
+}
+
+// Save stores the entry for subsequent runs
+func (c *Cache) Save(entry *Entry) {
+	if c == nil {
+		return
+	}
+	entry.Created = c.now()
+	if err := c.store.Save(entry); err != nil {
+		log.Entry().WithError(err).Warn("failed to write scan cache")
+		return
+	}
+	log.Entry().Infof("Stored scan result with key %v in scan cache", entry.Key)
+}
+
+// Key calculates a cache key from the hash of the scanned content and the configuration which influences the scan result
+func Key(step, contentHash string, config interface{}) (string, error) {
+	configJSON, err := json.Marshal(config)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal configuration")
+	}
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%v\n%v\n%s", step, contentHash, configJSON)
+	return fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
+
+// HashFiles calculates a combined SHA256 hash of the files, independent of their order
+func HashFiles(utils piperutils.FileUtils, files []string) (string, error) {
+	sorted := append([]string{}, files...)
+	sort.Strings(sorted)
+	hash := sha256.New()
+	for _, file := range sorted {
+		fileHash, err := utils.SHA256(file)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to calculate hash of '%v'", file)
+		}
+		fmt.Fprintf(hash, "%v %v\n", filepath.ToSlash(file), fileHash)
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
@@ -0,0 +1,247 @@
+package scancache
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+type storeMock struct {
+	entries map[string]*Entry
+	err     error
+}
+
+func (s *storeMock) Load(key string) (*Entry, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.entries[key], nil
+}
+
+func (s *storeMock) Save(entry *Entry) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.entries[entry.Key] = entry
+	return nil
+}
+
+func TestCache(t *testing.T) {
+	now := time.Date(2022, 10, 1, 2, 0, 0, 0, time.UTC)
+
+	t.Run("save and lookup", func(t *testing.T) {
+		store := &storeMock{entries: map[string]*Entry{}}
+		cache := New(store, 24*time.Hour)
+		cache.now = func() time.Time { return now }
+
+		cache.Save(NewEntry("malwareExecuteScan", "abc"))
+		entry := cache.Lookup("abc")
+
+		if assert.NotNil(t, entry) {
+			assert.Equal(t, "malwareExecuteScan", entry.Step)
+			assert.Equal(t, now, entry.Created)
+		}
+		assert.Nil(t, cache.Lookup("def"))
+	})
+
+	t.Run("expired entry", func(t *testing.T) {
+		store := &storeMock{entries: map[string]*Entry{"abc": {Key: "abc", Created: now.Add(-25 * time.Hour)}}}
+		cache := New(store, 24*time.Hour)
+		cache.now = func() time.Time { return now }
+
+		assert.Nil(t, cache.Lookup("abc"))
+	})
+
+	t.Run("store failure", func(t *testing.T) {
+		store := &storeMock{entries: map[string]*Entry{}, err: errors.New("access denied")}
+		cache := New(store, 24*time.Hour)
+
+		cache.Save(NewEntry("malwareExecuteScan", "abc"))
+		assert.Nil(t, cache.Lookup("abc"))
+	})
+
+	t.Run("nil cache", func(t *testing.T) {
+		var cache *Cache
+
+		cache.Save(NewEntry("malwareExecuteScan", "abc"))
+		assert.Nil(t, cache.Lookup("abc"))
+	})
+}
+
+func TestEntry(t *testing.T) {
+	t.Run("files", func(t *testing.T) {
+		utils := &mock.FilesMock{}
+		utils.AddFile("report.json", []byte(`{"malwareDetected":false}`))
+		utils.AddFile("malwarescan/piper_malwarescan_report.html", []byte("<html/>"))
+		utils.AddDir("malwarescan/empty")
+
+		entry := NewEntry("malwareExecuteScan", "abc")
+		err := entry.AddFiles(utils, "report.json", "malwarescan/*", "toolrun_*.json", "report.json")
+
+		assert.NoError(t, err)
+		if assert.Len(t, entry.Files, 2) {
+			assert.Equal(t, "report.json", entry.Files[0].Path)
+			assert.Equal(t, "malwarescan/piper_malwarescan_report.html", entry.Files[1].Path)
+		}
+
+		restored := &mock.FilesMock{}
+		assert.NoError(t, entry.Restore(restored))
+		content, err := restored.FileRead("malwarescan/piper_malwarescan_report.html")
+		assert.NoError(t, err)
+		assert.Equal(t, "<html/>", string(content))
+	})
+
+	t.Run("outputs", func(t *testing.T) {
+		entry := NewEntry("whitesourceExecuteScan", "abc")
+		assert.NoError(t, entry.SetOutput("whitesourceProjectNames", []string{"project - 1"}))
+
+		projectNames := []string{}
+		found, err := entry.Output("whitesourceProjectNames", &projectNames)
+		assert.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, []string{"project - 1"}, projectNames)
+
+		found, err = entry.Output("influx", &projectNames)
+		assert.NoError(t, err)
+		assert.False(t, found)
+	})
+}
+
+func TestKey(t *testing.T) {
+	key, err := Key("malwareExecuteScan", "hash", map[string]interface{}{"host": "https://example.org"})
+	assert.NoError(t, err)
+	assert.Len(t, key, 64)
+
+	sameKey, _ := Key("malwareExecuteScan", "hash", map[string]interface{}{"host": "https://example.org"})
+	assert.Equal(t, key, sameKey)
+
+	otherConfig, _ := Key("malwareExecuteScan", "hash", map[string]interface{}{"host": "https://other.example.org"})
+	assert.NotEqual(t, key, otherConfig)
+
+	otherContent, _ := Key("malwareExecuteScan", "other hash", map[string]interface{}{"host": "https://example.org"})
+	assert.NotEqual(t, key, otherContent)
+}
+
+// hashingFilesMock calculates the hash of the content, while FilesMock always returns the same hash
+type hashingFilesMock struct {
+	*mock.FilesMock
+}
+
+func (f hashingFilesMock) SHA256(path string) (string, error) {
+	content, err := f.FileRead(path)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(content)), nil
+}
+
+func TestHashFiles(t *testing.T) {
+	utils := hashingFilesMock{&mock.FilesMock{}}
+	utils.AddFile("a.jar", []byte("a"))
+	utils.AddFile("b.jar", []byte("b"))
+
+	hash, err := HashFiles(utils, []string{"a.jar", "b.jar"})
+	assert.NoError(t, err)
+	reordered, _ := HashFiles(utils, []string{"b.jar", "a.jar"})
+	assert.Equal(t, hash, reordered)
+
+	utils.AddFile("b.jar", []byte("changed"))
+	changed, _ := HashFiles(utils, []string{"a.jar", "b.jar"})
+	assert.NotEqual(t, hash, changed)
+
+	_, err = HashFiles(utils, []string{"c.jar"})
+	assert.Error(t, err)
+}
+
+func TestHashDependencyDescriptors(t *testing.T) {
+	utils := hashingFilesMock{&mock.FilesMock{}}
+	utils.AddFile("pom.xml", []byte("<project/>"))
+	utils.AddFile("module/pom.xml", []byte("<project/>"))
+	utils.AddFile("ui/package-lock.json", []byte("{}"))
+	utils.AddFile("ui/node_modules/lodash/package.json", []byte("{}"))
+	utils.AddFile("target/classes/META-INF/maven/pom.xml", []byte("<project/>"))
+
+	descriptors, err := FindDependencyDescriptors(utils, ".")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"module/pom.xml", "pom.xml", "ui/package-lock.json"}, descriptors)
+
+	hash, err := HashDependencyDescriptors(utils, ".")
+	assert.NoError(t, err)
+	utils.AddFile("ui/node_modules/lodash/package.json", []byte(`{"version": "4.17.21"}`))
+	unchanged, _ := HashDependencyDescriptors(utils, ".")
+	assert.Equal(t, hash, unchanged)
+
+	_, err = HashDependencyDescriptors(&mock.FilesMock{}, ".")
+	assert.EqualError(t, err, "no dependency descriptors found in '.'")
+}
+
+func TestCheckPinnedDependencies(t *testing.T) {
+	t.Run("pinned", func(t *testing.T) {
+		utils := &mock.FilesMock{}
+		utils.AddFile("pom.xml", []byte("<project><version>1.0.0</version></project>"))
+		utils.AddFile("lockfile.json", []byte("{}"))
+		utils.AddFile("ui/package.json", []byte(`{"dependencies": {"lodash": "^4.17.0"}}`))
+		utils.AddFile("ui/package-lock.json", []byte("{}"))
+		utils.AddFile("workspace/package.json", []byte("{}"))
+		utils.AddFile("package-lock.json", []byte("{}"))
+		utils.AddFile("requirements.txt", []byte("# tools\n--index-url https://pypi.org/simple\nrequests==2.31.0\nurllib3 == 2.0.7 ; python_version >= '3.8'\n"))
+
+		assert.NoError(t, CheckPinnedDependencies(utils, "."))
+	})
+
+	t.Run("pom.xml without lock file", func(t *testing.T) {
+		utils := &mock.FilesMock{}
+		utils.AddFile("module/pom.xml", []byte("<project/>"))
+
+		assert.EqualError(t, CheckPinnedDependencies(utils, "."), "'module/pom.xml' does not pin its dependencies, none of [lockfile.json] found")
+	})
+
+	t.Run("SNAPSHOT version", func(t *testing.T) {
+		utils := &mock.FilesMock{}
+		utils.AddFile("pom.xml", []byte("<project><dependency><version>1.1.0-SNAPSHOT</version></dependency></project>"))
+		utils.AddFile("lockfile.json", []byte("{}"))
+
+		assert.EqualError(t, CheckPinnedDependencies(utils, "."), "'pom.xml' refers to SNAPSHOT versions")
+	})
+
+	t.Run("version range", func(t *testing.T) {
+		utils := &mock.FilesMock{}
+		utils.AddFile("requirements.txt", []byte("requests==2.31.0\nurllib3>=2.0\n"))
+
+		assert.EqualError(t, CheckPinnedDependencies(utils, "."), "'requirements.txt' contains the version range 'urllib3>=2.0'")
+	})
+
+	t.Run("lock file outside of the directory", func(t *testing.T) {
+		utils := &mock.FilesMock{}
+		utils.AddFile("package-lock.json", []byte("{}"))
+		utils.AddFile("ui/package.json", []byte("{}"))
+
+		assert.Error(t, CheckPinnedDependencies(utils, "ui"))
+	})
+}
+
+func TestHashSourceTree(t *testing.T) {
+	utils := hashingFilesMock{&mock.FilesMock{}}
+	utils.AddFile("pom.xml", []byte("<project/>"))
+	utils.AddFile("src/main/java/App.java", []byte("class App {}"))
+	utils.AddFile("target/app.jar", []byte("jar"))
+
+	hash, err := HashSourceTree(utils, ".")
+	assert.NoError(t, err)
+
+	utils.AddFile("target/app.jar", []byte("rebuilt jar"))
+	unchanged, _ := HashSourceTree(utils, ".")
+	assert.Equal(t, hash, unchanged)
+
+	utils.AddFile("src/main/java/App.java", []byte("class App { int i; }"))
+	changed, _ := HashSourceTree(utils, ".")
+	assert.NotEqual(t, hash, changed)
+
+	_, err = HashSourceTree(hashingFilesMock{&mock.FilesMock{}}, ".")
+	assert.EqualError(t, err, "no files found in '.'")
+}
@@ -0,0 +1,74 @@
+package scancache
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/SAP/jenkins-library/pkg/cachestore"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/pkg/errors"
+)
+
+// ObjectStore keeps the entries as JSON objects in a store of the cachestore package, e.g. a local directory or a bucket
+type ObjectStore struct {
+	Objects cachestore.Store
+	// Files is used to exchange the entries with the store which transfers files
+	Files piperutils.FileUtils
+}
+
+// Load reads the entry from the store
+func (s *ObjectStore) Load(key string) (*Entry, error) {
+	tempDir, err := s.Files.TempDir("", "scancache")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create temporary directory")
+	}
+	defer s.Files.RemoveAll(tempDir)
+
+	localPath := filepath.Join(tempDir, key+".json")
+	found, err := s.Objects.Download(key+".json", localPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load cache entry '%v'", key)
+	}
+	if !found {
+		return nil, nil
+	}
+	content, err := s.Files.FileRead(localPath)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read cache entry '%v'", key)
+	}
+	return unmarshalEntry(key, content)
+}
+
+// Save writes the entry into the store
+func (s *ObjectStore) Save(entry *Entry) error {
+	content, err := json.Marshal(entry)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal cache entry")
+	}
+	tempDir, err := s.Files.TempDir("", "scancache")
+	if err != nil {
+		return errors.Wrap(err, "failed to create temporary directory")
+	}
+	defer s.Files.RemoveAll(tempDir)
+
+	localPath := filepath.Join(tempDir, entry.Key+".json")
+	if err := s.Files.FileWrite(localPath, content, 0666); err != nil {
+		return errors.Wrapf(err, "failed to write cache entry '%v'", entry.Key)
+	}
+	if err := s.Objects.Upload(localPath, entry.Key+".json"); err != nil {
+		return errors.Wrapf(err, "failed to save cache entry '%v'", entry.Key)
+	}
+	return nil
+}
+
+func unmarshalEntry(key string, content []byte) (*Entry, error) {
+	entry := Entry{}
+	if err := json.Unmarshal(content, &entry); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal cache entry '%v'", key)
+	}
+	if entry.Key != key {
+		return nil, fmt.Errorf("cache entry '%v' contains the result of key '%v'", key, entry.Key)
+	}
+	return &entry, nil
+}
@@ -0,0 +1,84 @@
+package scancache
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/cachestore"
+	"github.com/SAP/jenkins-library/pkg/gcs/mocks"
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+	testifyMock "github.com/stretchr/testify/mock"
+)
+
+func TestObjectStore(t *testing.T) {
+	t.Run("local directory", func(t *testing.T) {
+		utils := &mock.FilesMock{}
+		store := &ObjectStore{Objects: &cachestore.LocalStore{Dir: "/var/cache/piper", Files: utils}, Files: utils}
+
+		entry, err := store.Load("abc")
+		assert.NoError(t, err)
+		assert.Nil(t, entry)
+
+		assert.NoError(t, store.Save(&Entry{Key: "abc", Step: "malwareExecuteScan", Error: "Malware detected"}))
+		assert.True(t, utils.HasFile("/var/cache/piper/abc.json"))
+
+		entry, err = store.Load("abc")
+		assert.NoError(t, err)
+		if assert.NotNil(t, entry) {
+			assert.Equal(t, "Malware detected", entry.Error)
+		}
+
+		utils.AddFile("/var/cache/piper/def.json", []byte(`{"key": "abc"}`))
+		_, err = store.Load("def")
+		assert.EqualError(t, err, "cache entry 'def' contains the result of key 'abc'")
+	})
+
+	t.Run("bucket", func(t *testing.T) {
+		utils := &mock.FilesMock{}
+		client := &mocks.Client{}
+		var uploaded []byte
+		client.On("UploadFile", "bucket", testifyMock.Anything, "cache/abc.json").Return(
+			func(bucketID, sourcePath, targetPath string) error {
+				uploaded, _ = utils.FileRead(sourcePath)
+				return nil
+			})
+		client.On("DownloadFile", "bucket", "cache/abc.json", testifyMock.Anything).Return(
+			func(bucketID, sourcePath, targetPath string) error {
+				utils.AddFile(targetPath, uploaded)
+				return nil
+			})
+		client.On("DownloadFile", "bucket", "cache/def.json", testifyMock.Anything).Return(errors.New("storage: object doesn't exist"))
+		store := &ObjectStore{Objects: &cachestore.GCSStore{Client: client, Bucket: "bucket", Folder: "cache"}, Files: utils}
+
+		assert.NoError(t, store.Save(&Entry{Key: "abc", Step: "protecodeExecuteScan"}))
+		entry, err := store.Load("abc")
+		assert.NoError(t, err)
+		if assert.NotNil(t, entry) {
+			assert.Equal(t, "protecodeExecuteScan", entry.Step)
+		}
+
+		entry, err = store.Load("def")
+		assert.NoError(t, err)
+		assert.Nil(t, entry)
+	})
+
+	t.Run("store failure", func(t *testing.T) {
+		store := &ObjectStore{Objects: &failingStore{}, Files: &mock.FilesMock{}}
+
+		_, err := store.Load("abc")
+		assert.EqualError(t, err, "failed to load cache entry 'abc': AccessDenied")
+		err = store.Save(&Entry{Key: "abc"})
+		assert.EqualError(t, err, "failed to save cache entry 'abc': AccessDenied")
+	})
+}
+
+type failingStore struct{}
+
+func (s *failingStore) Download(name, file string) (bool, error) {
+	return false, errors.New("AccessDenied")
+}
+
+func (s *failingStore) Upload(file, name string) error {
+	return errors.New("AccessDenied")
+}
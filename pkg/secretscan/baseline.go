@@ -0,0 +1,40 @@
+package secretscan
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Baseline contains the fingerprints of known findings, only findings not contained in the baseline are treated as new
+type Baseline map[string]bool
+
+// ReadBaseline reads the findings file written by a previous scan
+func ReadBaseline(content []byte) (Baseline, error) {
+	findings := []Finding{}
+	if err := json.Unmarshal(content, &findings); err != nil {
+		return nil, fmt.Errorf("invalid baseline: %w", err)
+	}
+	baseline := Baseline{}
+	for _, finding := range findings {
+		baseline[finding.Fingerprint] = true
+	}
+	return baseline, nil
+}
+
+// Apply marks the findings contained in the baseline as not new
+func (b Baseline) Apply(findings []Finding) {
+	for i := range findings {
+		findings[i].New = !b[findings[i].Fingerprint]
+	}
+}
+
+// CountNew returns the number of findings not contained in the baseline
+func CountNew(findings []Finding) int {
+	count := 0
+	for _, finding := range findings {
+		if finding.New {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,24 @@
+package secretscan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBaseline(t *testing.T) {
+	t.Parallel()
+
+	baseline, err := ReadBaseline([]byte(`[{"ruleId": "aws-access-key-id", "fingerprint": "abc", "new": true}]`))
+	assert.NoError(t, err)
+	findings := []Finding{{Fingerprint: "abc", New: true}, {Fingerprint: "def", New: true}}
+
+	baseline.Apply(findings)
+
+	assert.False(t, findings[0].New)
+	assert.True(t, findings[1].New)
+	assert.Equal(t, 1, CountNew(findings))
+
+	_, err = ReadBaseline([]byte(`{`))
+	assert.Contains(t, err.Error(), "invalid baseline")
+}
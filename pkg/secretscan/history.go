@@ -0,0 +1,97 @@
+package secretscan
+
+import (
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/format/diff"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/pkg/errors"
+)
+
+// ScanHistory scans the lines added by the given commits, e.g. the commits of a pull request provided by git.LogRange.
+// Merge commits are skipped since the changes of the merged commits are part of the range already.
+// A secret added in several commits is only reported for the first commit returned by the iterator.
+// Besides the findings, the number of scanned commits is returned.
+func (s *Scanner) ScanHistory(commits object.CommitIter) ([]Finding, int, error) {
+	findings := []Finding{}
+	found := map[string]bool{}
+	scanned := 0
+	err := commits.ForEach(func(commit *object.Commit) error {
+		if commit.NumParents() > 1 {
+			return nil
+		}
+		scanned++
+		commitFindings, err := s.scanCommit(commit)
+		if err != nil {
+			return err
+		}
+		for _, finding := range commitFindings {
+			if !found[finding.Fingerprint] {
+				found[finding.Fingerprint] = true
+				findings = append(findings, finding)
+			}
+		}
+		return nil
+	})
+	return findings, scanned, err
+}
+
+func (s *Scanner) scanCommit(commit *object.Commit) ([]Finding, error) {
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read tree of commit %v", commit.Hash)
+	}
+	var parentTree *object.Tree
+	if commit.NumParents() == 1 {
+		parent, err := commit.Parent(0)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read parent of commit %v", commit.Hash)
+		}
+		if parentTree, err = parent.Tree(); err != nil {
+			return nil, errors.Wrapf(err, "failed to read tree of commit %v", parent.Hash)
+		}
+	}
+	changes, err := object.DiffTree(parentTree, tree)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to determine changes of commit %v", commit.Hash)
+	}
+	patch, err := changes.Patch()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to determine changes of commit %v", commit.Hash)
+	}
+
+	findings := []Finding{}
+	for _, filePatch := range patch.FilePatches() {
+		_, to := filePatch.Files()
+		if to == nil || filePatch.IsBinary() || s.IsAllowedPath(to.Path()) {
+			continue
+		}
+		for _, finding := range s.scanChunks(to.Path(), filePatch.Chunks()) {
+			finding.Commit = commit.Hash.String()
+			finding.Author = commit.Author.Email
+			findings = append(findings, finding)
+		}
+	}
+	return findings, nil
+}
+
+// scanChunks scans the added lines of a file patch
+func (s *Scanner) scanChunks(file string, chunks []diff.Chunk) []Finding {
+	findings := []Finding{}
+	lineCount := 0
+	for _, chunk := range chunks {
+		if len(chunk.Content()) == 0 {
+			continue
+		}
+		lines := strings.Split(strings.TrimSuffix(chunk.Content(), "\n"), "\n")
+		switch chunk.Type() {
+		case diff.Equal:
+			lineCount += len(lines)
+		case diff.Add:
+			offset := lineCount
+			findings = append(findings, s.scanLines(file, lines, func(index int) int { return offset + index + 1 })...)
+			lineCount += len(lines)
+		}
+	}
+	return findings
+}
@@ -0,0 +1,133 @@
+package secretscan
+
+import (
+	"fmt"
+	"runtime"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+	"github.com/SAP/jenkins-library/pkg/reporting"
+)
+
+// CreateScanReport creates a secret scan ScanReport to be used for uploading into various sinks
+func CreateScanReport(findings []Finding, scannedFiles, scannedCommits int) reporting.ScanReport {
+	newFindings := CountNew(findings)
+	scanReport := reporting.ScanReport{
+		ReportTitle: "Secret Scan Report",
+		Overview: []reporting.OverviewRow{
+			{Description: "Scanned files", Details: fmt.Sprint(scannedFiles)},
+			{Description: "Scanned commits", Details: fmt.Sprint(scannedCommits)},
+			{Description: "Total number of secrets", Details: fmt.Sprint(len(findings))},
+			{Description: "New secrets", Details: fmt.Sprint(newFindings)},
+		},
+		SuccessfulScan: newFindings == 0,
+		ReportTime:     time.Now(),
+	}
+
+	detailTable := reporting.ScanDetailTable{
+		NoRowsMessage: "No secrets found",
+		Headers: []string{
+			"Rule",
+			"File",
+			"Line",
+			"Commit",
+			"Redacted match",
+			"State",
+		},
+		WithCounter:   true,
+		CounterHeader: "Entry #",
+	}
+	for _, finding := range findings {
+		state, style := "baseline", reporting.ColumnStyle(reporting.Yellow)
+		if finding.New {
+			state, style = "new", reporting.Red
+		}
+		row := reporting.ScanRow{}
+		row.AddColumn(finding.RuleID, 0)
+		row.AddColumn(finding.File, 0)
+		row.AddColumn(finding.Line, 0)
+		row.AddColumn(finding.Commit, 0)
+		row.AddColumn(finding.Match, 0)
+		row.AddColumn(state, style)
+		detailTable.Rows = append(detailTable.Rows, row)
+	}
+	scanReport.DetailTable = detailTable
+
+	return scanReport
+}
+
+// CreateSarif creates a SARIF result from the findings, the secrets are contained in redacted form only
+func CreateSarif(findings []Finding) *format.SARIF {
+	log.Entry().Debug("Creating SARIF file for data transfer")
+	sarif := format.SARIF{
+		Schema:  "https://docs.oasis-open.org/sarif/sarif/v2.1.0/cos02/schemas/sarif-schema-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []format.Runs{{}},
+	}
+
+	tool := format.Tool{Driver: format.Driver{
+		Name:           "Piper secret scanner",
+		InformationUri: "https://github.com/SAP/jenkins-library",
+	}}
+
+	collectedRules := []string{}
+	for _, finding := range findings {
+		message := fmt.Sprintf("%v detected: %v", finding.Description, finding.Match)
+		if len(finding.Commit) > 0 {
+			message = fmt.Sprintf("%v (commit %v)", message, finding.Commit)
+		}
+		result := format.Results{
+			RuleID:         finding.RuleID,
+			Level:          sarifLevel(finding.Severity),
+			Message:        &format.Message{Text: message},
+			AnalysisTarget: &format.ArtifactLocation{URI: finding.File},
+			Locations: []format.Location{{PhysicalLocation: format.PhysicalLocation{
+				ArtifactLocation: format.ArtifactLocation{URI: finding.File},
+				Region:           format.Region{StartLine: finding.Line, StartColumn: finding.Column},
+			}}},
+			PartialFingerprints: format.PartialFingerprints{PrimaryLocationLineHash: finding.Fingerprint},
+		}
+		if !finding.New {
+			result.Properties = &format.SarifProperties{
+				Audited:          true,
+				ToolAuditMessage: "contained in baseline",
+			}
+		}
+		sarif.Runs[0].Results = append(sarif.Runs[0].Results, result)
+
+		// only create rule on new rule id
+		if !piperutils.ContainsString(collectedRules, finding.RuleID) {
+			collectedRules = append(collectedRules, finding.RuleID)
+			tool.Driver.Rules = append(tool.Driver.Rules, format.SarifRule{
+				ID:                   finding.RuleID,
+				Name:                 finding.RuleID,
+				ShortDescription:     &format.Message{Text: finding.Description},
+				FullDescription:      &format.Message{Text: fmt.Sprintf("%v. Remove the secret from the sources and revoke it.", finding.Description)},
+				DefaultConfiguration: &format.DefaultConfiguration{Level: sarifLevel(finding.Severity)},
+				Properties: &format.SarifRuleProperties{
+					Tags:      []string{"security", "secret"},
+					Precision: "high",
+				},
+			})
+		}
+	}
+	sarif.Runs[0].Tool = tool
+
+	conversion := new(format.Conversion)
+	conversion.Tool.Driver.Name = "Piper secret scan to SARIF converter"
+	conversion.Tool.Driver.InformationUri = "https://github.com/SAP/jenkins-library"
+	conversion.Invocation.ExecutionSuccessful = true
+	conversion.Invocation.Properties = &format.InvocationProperties{Platform: runtime.GOOS}
+	sarif.Runs[0].Conversion = conversion
+
+	return &sarif
+}
+
+func sarifLevel(severity string) string {
+	if severity == "high" {
+		return "error"
+	}
+	return "warning"
+}
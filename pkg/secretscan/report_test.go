@@ -0,0 +1,46 @@
+package secretscan
+
+import (
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/reporting"
+	"github.com/stretchr/testify/assert"
+)
+
+var testFindings = []Finding{
+	{RuleID: "aws-access-key-id", Description: "AWS access key id", Severity: "high", File: "deploy.sh", Line: 3, Column: 26, Match: "AKIA********", Commit: "1234", Fingerprint: "abc", New: true},
+	{RuleID: "generic-secret", Description: "Generic password", Severity: "medium", File: "config.yaml", Line: 5, Column: 14, Match: "s3********", Fingerprint: "def"},
+}
+
+func TestCreateScanReport(t *testing.T) {
+	t.Parallel()
+
+	scanReport := CreateScanReport(testFindings, 10, 2)
+
+	assert.Equal(t, "Secret Scan Report", scanReport.ReportTitle)
+	assert.False(t, scanReport.SuccessfulScan)
+	assert.Equal(t, "1", scanReport.Overview[3].Details)
+	if assert.Len(t, scanReport.DetailTable.Rows, 2) {
+		assert.Equal(t, "new", scanReport.DetailTable.Rows[0].Columns[5].Content)
+		assert.Equal(t, reporting.ColumnStyle(reporting.Red), scanReport.DetailTable.Rows[0].Columns[5].Style)
+		assert.Equal(t, "baseline", scanReport.DetailTable.Rows[1].Columns[5].Content)
+	}
+}
+
+func TestCreateSarif(t *testing.T) {
+	t.Parallel()
+
+	sarif := CreateSarif(testFindings)
+
+	results := sarif.Runs[0].Results
+	if assert.Len(t, results, 2) {
+		assert.Equal(t, "error", results[0].Level)
+		assert.Equal(t, "AWS access key id detected: AKIA******** (commit 1234)", results[0].Message.Text)
+		assert.Equal(t, 3, results[0].Locations[0].PhysicalLocation.Region.StartLine)
+		assert.Equal(t, "abc", results[0].PartialFingerprints.PrimaryLocationLineHash)
+		assert.Nil(t, results[0].Properties)
+		assert.Equal(t, "warning", results[1].Level)
+		assert.True(t, results[1].Properties.Audited)
+	}
+	assert.Len(t, sarif.Runs[0].Tool.Driver.Rules, 2)
+}
@@ -0,0 +1,210 @@
+package secretscan
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/ghodss/yaml"
+)
+
+// Rule describes how to detect a certain type of secret
+type Rule struct {
+	ID          string `json:"id"`
+	Description string `json:"description,omitempty"`
+	// Regex is the regular expression detecting the secret
+	Regex string `json:"regex"`
+	// SecretGroup is the capturing group of the regex containing the secret, the whole match is used if it is 0
+	SecretGroup int `json:"secretGroup,omitempty"`
+	// Entropy is the minimum Shannon entropy of the secret, it allows to ignore placeholders like 'changeme'
+	Entropy float64 `json:"entropy,omitempty"`
+	// Keywords allow to skip lines quickly, the regex is only evaluated if a line contains one of the keywords (case-insensitive)
+	Keywords []string `json:"keywords,omitempty"`
+	Severity string   `json:"severity,omitempty"`
+
+	regex *regexp.Regexp
+}
+
+// RuleSet is the content of a rules file
+type RuleSet struct {
+	Rules []Rule `json:"rules"`
+}
+
+// DefaultRules returns the built-in rules covering common token formats and generic password assignments
+func DefaultRules() []Rule {
+	return []Rule{
+		{
+			ID:          "aws-access-key-id",
+			Description: "AWS access key id",
+			Regex:       `\b((?:AKIA|ASIA|AGPA|AIDA|AROA|AIPA|ANPA|ANVA)[A-Z0-9]{16})\b`,
+			SecretGroup: 1,
+			Keywords:    []string{"akia", "asia", "agpa", "aida", "aroa", "aipa", "anpa", "anva"},
+			Severity:    "high",
+		},
+		{
+			ID:          "github-token",
+			Description: "GitHub personal access, OAuth, app or refresh token",
+			Regex:       `\b(gh[pousr]_[A-Za-z0-9]{36,255}|github_pat_[A-Za-z0-9_]{82})\b`,
+			SecretGroup: 1,
+			Keywords:    []string{"ghp_", "gho_", "ghu_", "ghs_", "ghr_", "github_pat_"},
+			Severity:    "high",
+		},
+		{
+			ID:          "private-key",
+			Description: "Private key",
+			Regex:       `-----BEGIN[ A-Z0-9_-]{0,100}PRIVATE KEY( BLOCK)?-----`,
+			Keywords:    []string{"private key"},
+			Severity:    "high",
+		},
+		{
+			ID:          "slack-token",
+			Description: "Slack token",
+			Regex:       `\b(xox[abposr]-[0-9A-Za-z-]{10,72})\b`,
+			SecretGroup: 1,
+			Keywords:    []string{"xox"},
+			Severity:    "high",
+		},
+		{
+			ID:          "google-api-key",
+			Description: "Google API key",
+			Regex:       `\b(AIza[0-9A-Za-z_-]{35})\b`,
+			SecretGroup: 1,
+			Keywords:    []string{"aiza"},
+			Severity:    "high",
+		},
+		{
+			ID:          "npm-token",
+			Description: "npm access token",
+			Regex:       `\b(npm_[A-Za-z0-9]{36})\b`,
+			SecretGroup: 1,
+			Keywords:    []string{"npm_"},
+			Severity:    "high",
+		},
+		{
+			ID:          "azure-storage-key",
+			Description: "Azure storage account key",
+			Regex:       `AccountKey=([A-Za-z0-9+/]{86}==)`,
+			SecretGroup: 1,
+			Keywords:    []string{"accountkey="},
+			Severity:    "high",
+		},
+		{
+			ID:          "jwt",
+			Description: "JSON web token",
+			Regex:       `\b(eyJ[A-Za-z0-9_-]{10,}\.eyJ[A-Za-z0-9_-]{10,}\.[A-Za-z0-9_-]{10,})`,
+			SecretGroup: 1,
+			Keywords:    []string{"eyj"},
+			Severity:    "medium",
+		},
+		{
+			ID:          "url-credentials",
+			Description: "Password contained in a URL",
+			Regex:       `[A-Za-z][A-Za-z0-9+.-]*://[^\s:@/"']+:([^\s:@/"'$]{3,})@[^\s"']+`,
+			SecretGroup: 1,
+			Entropy:     2.5,
+			Keywords:    []string{"://"},
+			Severity:    "high",
+		},
+		{
+			ID:          "generic-secret",
+			Description: "Generic password, secret or API key assignment",
+			Regex:       `(?i)(?:password|passwd|pwd|secret|token|api[_-]?key|access[_-]?key|client[_-]?secret)["']?\s*[:=]\s*["']([^"'\s$]{8,})["']`,
+			SecretGroup: 1,
+			Entropy:     3.0,
+			Keywords:    []string{"password", "passwd", "pwd", "secret", "token", "key"},
+			Severity:    "medium",
+		},
+	}
+}
+
+// ReadRules parses a rules file in YAML or JSON format
+func ReadRules(content []byte) ([]Rule, error) {
+	ruleSet := RuleSet{}
+	if err := yaml.Unmarshal(content, &ruleSet); err != nil {
+		return nil, fmt.Errorf("invalid rules file: %w", err)
+	}
+	return ruleSet.Rules, nil
+}
+
+// MergeRules adds the custom rules to the given rules, custom rules replace rules with the same id
+func MergeRules(rules []Rule, custom []Rule) []Rule {
+	merged := []Rule{}
+	replaced := map[string]bool{}
+	for _, rule := range custom {
+		replaced[rule.ID] = true
+	}
+	for _, rule := range rules {
+		if !replaced[rule.ID] {
+			merged = append(merged, rule)
+		}
+	}
+	return append(merged, custom...)
+}
+
+func (r *Rule) compile() error {
+	if len(r.ID) == 0 {
+		return fmt.Errorf("rule without id")
+	}
+	regex, err := regexp.Compile(r.Regex)
+	if err != nil {
+		return fmt.Errorf("invalid regex of rule '%v': %w", r.ID, err)
+	}
+	if r.SecretGroup > regex.NumSubexp() {
+		return fmt.Errorf("invalid secretGroup %v of rule '%v', the regex only contains %v group(s)", r.SecretGroup, r.ID, regex.NumSubexp())
+	}
+	r.regex = regex
+	for i, keyword := range r.Keywords {
+		r.Keywords[i] = strings.ToLower(keyword)
+	}
+	return nil
+}
+
+// find returns the secrets contained in a line together with their column
+func (r *Rule) find(line string) (secrets []string, columns []int) {
+	if len(r.Keywords) > 0 {
+		lower := strings.ToLower(line)
+		found := false
+		for _, keyword := range r.Keywords {
+			if strings.Contains(lower, keyword) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return
+		}
+	}
+	for _, match := range r.regex.FindAllStringSubmatchIndex(line, -1) {
+		start, end := match[2*r.SecretGroup], match[2*r.SecretGroup+1]
+		if start < 0 {
+			continue
+		}
+		secret := line[start:end]
+		if r.Entropy > 0 && ShannonEntropy(secret) < r.Entropy {
+			continue
+		}
+		secrets = append(secrets, secret)
+		columns = append(columns, start+1)
+	}
+	return
+}
+
+// ShannonEntropy calculates the entropy of a string in bits per character
+func ShannonEntropy(value string) float64 {
+	if len(value) == 0 {
+		return 0
+	}
+	counts := map[rune]int{}
+	length := 0
+	for _, char := range value {
+		counts[char]++
+		length++
+	}
+	entropy := 0.0
+	for _, count := range counts {
+		probability := float64(count) / float64(length)
+		entropy -= probability * math.Log2(probability)
+	}
+	return entropy
+}
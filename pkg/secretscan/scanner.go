@@ -0,0 +1,193 @@
+package secretscan
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/bmatcuk/doublestar"
+	"github.com/ghodss/yaml"
+)
+
+// AllowMarker can be added to a line, e.g. as comment, in order to ignore secrets detected in this line
+const AllowMarker = "secretscan:allow"
+
+// Allowlist defines which findings are ignored
+type Allowlist struct {
+	// Paths are glob patterns of files which are not scanned
+	Paths []string `json:"paths,omitempty"`
+	// Regexes are matched against the detected secrets, e.g. to allow well-known example keys
+	Regexes []string `json:"regexes,omitempty"`
+	// Fingerprints are the fingerprints of findings which have been assessed as false positives
+	Fingerprints []string `json:"fingerprints,omitempty"`
+
+	regexes []*regexp.Regexp
+}
+
+// ReadAllowlist parses an allowlist file in YAML or JSON format
+func ReadAllowlist(content []byte) (*Allowlist, error) {
+	allowlist := Allowlist{}
+	if err := yaml.Unmarshal(content, &allowlist); err != nil {
+		return nil, fmt.Errorf("invalid allowlist: %w", err)
+	}
+	return &allowlist, nil
+}
+
+// Finding is a secret detected in a file
+type Finding struct {
+	RuleID      string `json:"ruleId"`
+	Description string `json:"description"`
+	Severity    string `json:"severity"`
+	File        string `json:"file"`
+	Line        int    `json:"line"`
+	Column      int    `json:"column"`
+	// Match is the redacted secret, the secret itself is never part of the reports
+	Match string `json:"match"`
+	// Commit is set for findings in the git history
+	Commit string `json:"commit,omitempty"`
+	Author string `json:"author,omitempty"`
+	// Fingerprint identifies a secret in a file independent of its line and the commit
+	Fingerprint string `json:"fingerprint"`
+	// New is false if the finding is contained in the baseline
+	New bool `json:"new"`
+}
+
+// Scanner detects secrets based on a set of rules
+type Scanner struct {
+	rules          []Rule
+	allowlist      Allowlist
+	fingerprintKey []byte
+}
+
+// NewScanner validates the rules and the allowlist and creates a scanner
+func NewScanner(rules []Rule, allowlist *Allowlist, fingerprintKey []byte) (*Scanner, error) {
+	if len(fingerprintKey) == 0 {
+		return nil, fmt.Errorf("no fingerprint key provided")
+	}
+	scanner := Scanner{fingerprintKey: fingerprintKey}
+	for _, rule := range rules {
+		if err := rule.compile(); err != nil {
+			return nil, err
+		}
+		scanner.rules = append(scanner.rules, rule)
+	}
+	if allowlist != nil {
+		scanner.allowlist = *allowlist
+	}
+	for _, expression := range scanner.allowlist.Regexes {
+		regex, err := regexp.Compile(expression)
+		if err != nil {
+			return nil, fmt.Errorf("invalid allowlist regex '%v': %w", expression, err)
+		}
+		scanner.allowlist.regexes = append(scanner.allowlist.regexes, regex)
+	}
+	for _, pattern := range scanner.allowlist.Paths {
+		if _, err := doublestar.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid allowlist path '%v': %w", pattern, err)
+		}
+	}
+	return &scanner, nil
+}
+
+// IsAllowedPath returns true if the file is excluded from the scan by the allowlist
+func (s *Scanner) IsAllowedPath(file string) bool {
+	for _, pattern := range s.allowlist.Paths {
+		if matched, _ := doublestar.Match(pattern, file); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// ScanContent scans the content of a file, binary content is skipped
+func (s *Scanner) ScanContent(file string, content []byte) []Finding {
+	if s.IsAllowedPath(file) || IsBinary(content) {
+		return []Finding{}
+	}
+	lines := strings.Split(string(content), "\n")
+	return s.scanLines(file, lines, func(index int) int { return index + 1 })
+}
+
+// scanLines scans the given lines, lineNumber maps the index of a line to its line number in the file
+func (s *Scanner) scanLines(file string, lines []string, lineNumber func(index int) int) []Finding {
+	findings := []Finding{}
+	for index, line := range lines {
+		line = strings.TrimSuffix(line, "\r")
+		if strings.Contains(line, AllowMarker) {
+			continue
+		}
+		for _, rule := range s.rules {
+			secrets, columns := rule.find(line)
+			for i, secret := range secrets {
+				if s.isAllowedSecret(secret) {
+					continue
+				}
+				finding := Finding{
+					RuleID:      rule.ID,
+					Description: rule.Description,
+					Severity:    rule.Severity,
+					File:        file,
+					Line:        lineNumber(index),
+					Column:      columns[i],
+					Match:       Redact(secret),
+					Fingerprint: Fingerprint(s.fingerprintKey, rule.ID, file, secret),
+					New:         true,
+				}
+				if s.isAllowedFingerprint(finding.Fingerprint) {
+					continue
+				}
+				findings = append(findings, finding)
+			}
+		}
+	}
+	return findings
+}
+
+func (s *Scanner) isAllowedSecret(secret string) bool {
+	for _, regex := range s.allowlist.regexes {
+		if regex.MatchString(secret) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Scanner) isAllowedFingerprint(fingerprint string) bool {
+	for _, allowed := range s.allowlist.Fingerprints {
+		if allowed == fingerprint {
+			return true
+		}
+	}
+	return false
+}
+
+// Fingerprint creates an HMAC of rule, file and secret keyed with a project specific key.
+// Without the key the fingerprint cannot be used to confirm a guessed secret.
+func Fingerprint(key []byte, ruleID, file, secret string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(fmt.Sprintf("%v:%v:%v", ruleID, file, secret)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Redact keeps the first characters of a secret for identification and masks the remainder
+func Redact(secret string) string {
+	// slice by characters to not cut multi-byte characters
+	runes := []rune(secret)
+	visible := len(runes) / 4
+	if visible > 4 {
+		visible = 4
+	}
+	return string(runes[:visible]) + strings.Repeat("*", 8)
+}
+
+// IsBinary checks for NUL bytes like git does in order to detect binary content
+func IsBinary(content []byte) bool {
+	if len(content) > 8000 {
+		content = content[:8000]
+	}
+	return bytes.IndexByte(content, 0) >= 0
+}
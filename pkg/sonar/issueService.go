@@ -2,7 +2,10 @@ package sonar
 
 import (
 	"net/http"
+	"strconv"
+	"strings"
 
+	"github.com/SAP/jenkins-library/pkg/log"
 	sonargo "github.com/magicsong/sonargo/sonar"
 	"github.com/pkg/errors"
 )
@@ -10,6 +13,26 @@ import (
 // EndpointIssuesSearch API endpoint for https://sonarcloud.io/web_api/api/issues/search
 const EndpointIssuesSearch = "issues/search"
 
+// maxIssueSearchResults is the limit of results the issues/search endpoint is able to return
+const maxIssueSearchResults = 10000
+
+const issueSearchPageSize = 500
+
+// IssueDetail is representing a single issue found by the analysis
+type IssueDetail struct {
+	Key       string `json:"key"`
+	Rule      string `json:"rule"`
+	RuleName  string `json:"ruleName,omitempty"`
+	Severity  string `json:"severity"`
+	Type      string `json:"type"`
+	Message   string `json:"message"`
+	File      string `json:"file,omitempty"`
+	Line      int    `json:"line,omitempty"`
+	EndLine   int    `json:"endLine,omitempty"`
+	Effort    string `json:"effort,omitempty"`
+	CreatedAt string `json:"creationDate,omitempty"`
+}
+
 // IssueService ...
 type IssueService struct {
 	Organization string
@@ -44,13 +67,7 @@ func (service *IssueService) SearchIssues(options *IssuesSearchOption) (*sonargo
 	return result, response, nil
 }
 
-func (service *IssueService) getIssueCount(severity issueSeverity) (int, error) {
-	options := &IssuesSearchOption{
-		ComponentKeys: service.Project,
-		Severities:    severity.ToString(),
-		Resolved:      "false",
-		Ps:            "1",
-	}
+func (service *IssueService) addScope(options *IssuesSearchOption) {
 	if len(service.Organization) > 0 {
 		options.Organization = service.Organization
 	}
@@ -60,6 +77,16 @@ func (service *IssueService) getIssueCount(severity issueSeverity) (int, error)
 	} else if len(service.Branch) > 0 {
 		options.Branch = service.Branch
 	}
+}
+
+func (service *IssueService) getIssueCount(severity issueSeverity) (int, error) {
+	options := &IssuesSearchOption{
+		ComponentKeys: service.Project,
+		Severities:    severity.ToString(),
+		Resolved:      "false",
+		Ps:            "1",
+	}
+	service.addScope(options)
 	result, _, err := service.SearchIssues(options)
 	if err != nil {
 		return -1, errors.Wrapf(err, "failed to fetch the numer of '%s' issues", severity)
@@ -92,6 +119,82 @@ func (service *IssueService) GetNumberOfInfoIssues() (int, error) {
 	return service.getIssueCount(info)
 }
 
+// GetNewIssues returns the unresolved issues of the new code.
+// For pull-requests these are all issues of the pull-request, for branches the issues created in the new code period.
+func (service *IssueService) GetNewIssues() ([]IssueDetail, error) {
+	issues := []IssueDetail{}
+	for page := 1; ; page++ {
+		options := &IssuesSearchOption{
+			ComponentKeys:    service.Project,
+			Resolved:         "false",
+			AdditionalFields: "rules",
+			S:                "SEVERITY",
+			P:                strconv.Itoa(page),
+			Ps:               strconv.Itoa(issueSearchPageSize),
+		}
+		service.addScope(options)
+		if len(service.PullRequest) == 0 {
+			options.SinceLeakPeriod = "true"
+		}
+		result, _, err := service.SearchIssues(options)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to fetch the new issues")
+		}
+		issues = append(issues, service.toIssueDetails(result)...)
+		if len(result.Issues) == 0 || len(issues) >= result.Total {
+			break
+		}
+		if page*issueSearchPageSize >= maxIssueSearchResults {
+			log.Entry().Warnf("only the first %v of %v new issues are exported", len(issues), result.Total)
+			break
+		}
+	}
+	return issues, nil
+}
+
+func (service *IssueService) toIssueDetails(result *sonargo.IssuesSearchObject) []IssueDetail {
+	paths := map[string]string{}
+	for _, component := range result.Components {
+		if component != nil && len(component.Path) > 0 {
+			paths[component.Key] = component.Path
+		}
+	}
+	ruleNames := map[string]string{}
+	for _, rule := range result.Rules {
+		if rule != nil {
+			ruleNames[rule.Key] = rule.Name
+		}
+	}
+	details := []IssueDetail{}
+	for _, issue := range result.Issues {
+		if issue == nil {
+			continue
+		}
+		detail := IssueDetail{
+			Key:       issue.Key,
+			Rule:      issue.Rule,
+			RuleName:  ruleNames[issue.Rule],
+			Severity:  issue.Severity,
+			Type:      issue.Type,
+			Message:   issue.Message,
+			File:      paths[issue.Component],
+			Line:      issue.Line,
+			Effort:    issue.Effort,
+			CreatedAt: issue.CreationDate,
+		}
+		if len(detail.File) == 0 && issue.Component != issue.Project {
+			// component keys of files are "<project key>:<path>"
+			detail.File = strings.TrimPrefix(issue.Component, issue.Project+":")
+		}
+		if issue.TextRange != nil {
+			detail.Line = issue.TextRange.StartLine
+			detail.EndLine = issue.TextRange.EndLine
+		}
+		details = append(details, detail)
+	}
+	return details
+}
+
 // NewIssuesService returns a new instance of a service for the issues API endpoint.
 func NewIssuesService(host, token, project, organization, branch, pullRequest string, client Sender) *IssueService {
 	return &IssueService{
@@ -70,6 +70,117 @@ func TestIssueService(t *testing.T) {
 	})
 }
 
+func TestGetNewIssues(t *testing.T) {
+	testURL := "https://example.org"
+	t.Run("branch", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		sender := &piperhttp.Client{}
+		sender.SetOptions(piperhttp.ClientOptions{MaxRetries: -1, UseDefaultTransport: true})
+		// add response handler
+		var requestedQuery string
+		httpmock.RegisterResponder(http.MethodGet, testURL+"/api/"+EndpointIssuesSearch+"", queryRecordingResponder(http.StatusOK, responseIssueSearchNew, &requestedQuery))
+		// create service instance
+		serviceUnderTest := NewIssuesService(testURL, mock.Anything, "SAP_jenkins-library", "", "main", "", sender)
+		// test
+		issues, err := serviceUnderTest.GetNewIssues()
+		// assert
+		assert.NoError(t, err)
+		assert.Equal(t, []IssueDetail{
+			{Key: "AXW3MmCVOYWf3_DBLGvL", Rule: "go:S3776", RuleName: "Cognitive Complexity of functions should not be too high", Severity: "CRITICAL", Type: "CODE_SMELL", Message: "Refactor this method to reduce its Cognitive Complexity from 16 to the 15 allowed.", File: "cmd/fortifyExecuteScan.go", Line: 647, EndLine: 647, Effort: "6min", CreatedAt: "2020-11-11T11:06:04+0100"},
+			{Key: "AXW3MmCVOYWf3_DBLGvM", Rule: "go:S1192", Severity: "MINOR", Type: "CODE_SMELL", Message: "Define a constant instead of duplicating this literal 3 times.", File: "pkg/sonar/sonar.go", Line: 12},
+		}, issues)
+		assert.Equal(t, "additionalFields=rules&branch=main&componentKeys=SAP_jenkins-library&p=1&ps=500&resolved=false&s=SEVERITY&sinceLeakPeriod=true", requestedQuery)
+	})
+	t.Run("pull-request", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		sender := &piperhttp.Client{}
+		sender.SetOptions(piperhttp.ClientOptions{MaxRetries: -1, UseDefaultTransport: true})
+		// add response handler
+		var requestedQuery string
+		httpmock.RegisterResponder(http.MethodGet, testURL+"/api/"+EndpointIssuesSearch+"", queryRecordingResponder(http.StatusOK, `{"total": 0, "issues": []}`, &requestedQuery))
+		// create service instance
+		serviceUnderTest := NewIssuesService(testURL, mock.Anything, "SAP_jenkins-library", "", "main", "42", sender)
+		// test
+		issues, err := serviceUnderTest.GetNewIssues()
+		// assert
+		assert.NoError(t, err)
+		assert.Empty(t, issues)
+		assert.Equal(t, "additionalFields=rules&componentKeys=SAP_jenkins-library&p=1&ps=500&pullRequest=42&resolved=false&s=SEVERITY", requestedQuery)
+	})
+	t.Run("error", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		sender := &piperhttp.Client{}
+		sender.SetOptions(piperhttp.ClientOptions{MaxRetries: -1, UseDefaultTransport: true})
+		// add response handler
+		httpmock.RegisterResponder(http.MethodGet, testURL+"/api/"+EndpointIssuesSearch+"", httpmock.NewStringResponder(http.StatusNotFound, responseIssueSearchError))
+		// create service instance
+		serviceUnderTest := NewIssuesService(testURL, mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything, sender)
+		// test
+		issues, err := serviceUnderTest.GetNewIssues()
+		// assert
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to fetch the new issues")
+		assert.Nil(t, issues)
+	})
+}
+
+const responseIssueSearchNew = `{
+  "total": 2,
+  "p": 1,
+  "ps": 500,
+  "issues": [
+    {
+      "key": "AXW3MmCVOYWf3_DBLGvL",
+      "rule": "go:S3776",
+      "severity": "CRITICAL",
+      "component": "SAP_jenkins-library:cmd/fortifyExecuteScan.go",
+      "project": "SAP_jenkins-library",
+      "line": 647,
+      "textRange": {
+        "startLine": 647,
+        "endLine": 647,
+        "startOffset": 5,
+        "endOffset": 23
+      },
+      "status": "OPEN",
+      "message": "Refactor this method to reduce its Cognitive Complexity from 16 to the 15 allowed.",
+      "effort": "6min",
+      "creationDate": "2020-11-11T11:06:04+0100",
+      "type": "CODE_SMELL"
+    },
+    {
+      "key": "AXW3MmCVOYWf3_DBLGvM",
+      "rule": "go:S1192",
+      "severity": "MINOR",
+      "component": "SAP_jenkins-library:pkg/sonar/sonar.go",
+      "project": "SAP_jenkins-library",
+      "line": 12,
+      "status": "OPEN",
+      "message": "Define a constant instead of duplicating this literal 3 times.",
+      "type": "CODE_SMELL"
+    }
+  ],
+  "components": [
+    {
+      "key": "SAP_jenkins-library:cmd/fortifyExecuteScan.go",
+      "qualifier": "FIL",
+      "path": "cmd/fortifyExecuteScan.go"
+    }
+  ],
+  "rules": [
+    {
+      "key": "go:S3776",
+      "name": "Cognitive Complexity of functions should not be too high"
+    }
+  ]
+}`
+
 const responseIssueSearchError = `{
   "errors": [
     {
@@ -0,0 +1,136 @@
+package sonar
+
+import (
+	"net/http"
+
+	sonargo "github.com/magicsong/sonargo/sonar"
+	"github.com/pkg/errors"
+)
+
+// EndpointQualityGatesProjectStatus API endpoint for https://sonarcloud.io/web_api/api/qualitygates/project_status
+const EndpointQualityGatesProjectStatus = "qualitygates/project_status"
+
+const (
+	// QualityGateStatusOK is returned for a passed quality gate
+	QualityGateStatusOK = "OK"
+	// QualityGateStatusWarning is returned for a quality gate with warnings (SonarQube < 7.6 only)
+	QualityGateStatusWarning = "WARN"
+	// QualityGateStatusError is returned for a failed quality gate
+	QualityGateStatusError = "ERROR"
+	// QualityGateStatusNone is returned if no quality gate is associated with the analysis
+	QualityGateStatusNone = "NONE"
+)
+
+// QualityGateStatus is representing the status of the quality gate of an analysis
+type QualityGateStatus struct {
+	Status     string                 `json:"status"`
+	Conditions []QualityGateCondition `json:"conditions,omitempty"`
+}
+
+// QualityGateCondition is representing a single condition of a quality gate and its evaluation
+type QualityGateCondition struct {
+	Metric     string `json:"metric"`
+	Comparator string `json:"comparator,omitempty"`
+	Threshold  string `json:"threshold,omitempty"`
+	Actual     string `json:"actual,omitempty"`
+	Status     string `json:"status"`
+}
+
+// Passed returns true if the quality gate did not fail.
+func (status QualityGateStatus) Passed() bool {
+	return status.Status != QualityGateStatusError
+}
+
+// FailedConditions returns the conditions which caused the quality gate to fail.
+func (status QualityGateStatus) FailedConditions() []QualityGateCondition {
+	failed := []QualityGateCondition{}
+	for _, condition := range status.Conditions {
+		if condition.Status == QualityGateStatusError {
+			failed = append(failed, condition)
+		}
+	}
+	return failed
+}
+
+// QualityGateService ...
+type QualityGateService struct {
+	Organization string
+	Project      string
+	Branch       string
+	PullRequest  string
+	apiClient    *Requester
+}
+
+// ProjectStatus ...
+func (service *QualityGateService) ProjectStatus(options *QualityGateProjectStatusOption) (*sonargo.QualitygatesProjectStatusObject, *http.Response, error) {
+	request, err := service.apiClient.create("GET", EndpointQualityGatesProjectStatus, options)
+	if err != nil {
+		return nil, nil, err
+	}
+	// use custom HTTP client to send request
+	response, err := service.apiClient.send(request)
+	if err != nil {
+		return nil, nil, err
+	}
+	// reuse response verrification from sonargo
+	err = sonargo.CheckResponse(response)
+	if err != nil {
+		return nil, response, err
+	}
+	// decode JSON response
+	result := new(sonargo.QualitygatesProjectStatusObject)
+	err = service.apiClient.decode(response, result)
+	if err != nil {
+		return nil, response, err
+	}
+	return result, response, nil
+}
+
+// GetStatus returns the quality gate status including all conditions.
+// If an analysisID is given, the status of this analysis is returned, otherwise the one of the latest analysis of the branch or pull-request.
+func (service *QualityGateService) GetStatus(analysisID string) (*QualityGateStatus, error) {
+	options := &QualityGateProjectStatusOption{}
+	if len(analysisID) > 0 {
+		options.AnalysisId = analysisID
+	} else {
+		options.ProjectKey = service.Project
+		// if PR, ignore branch name and consider PR branch name. If not PR, consider branch name
+		if len(service.PullRequest) > 0 {
+			options.PullRequest = service.PullRequest
+		} else if len(service.Branch) > 0 {
+			options.Branch = service.Branch
+		}
+	}
+	result, _, err := service.ProjectStatus(options)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to fetch the quality gate status")
+	}
+	if result.ProjectStatus == nil {
+		return nil, errors.New("failed to fetch the quality gate status: no project status received")
+	}
+	status := &QualityGateStatus{Status: result.ProjectStatus.Status}
+	for _, condition := range result.ProjectStatus.Conditions {
+		if condition == nil {
+			continue
+		}
+		status.Conditions = append(status.Conditions, QualityGateCondition{
+			Metric:     condition.MetricKey,
+			Comparator: condition.Comparator,
+			Threshold:  condition.ErrorThreshold,
+			Actual:     condition.ActualValue,
+			Status:     condition.Status,
+		})
+	}
+	return status, nil
+}
+
+// NewQualityGateService returns a new instance of a service for the qualitygates API endpoint.
+func NewQualityGateService(host, token, project, organization, branch, pullRequest string, client Sender) *QualityGateService {
+	return &QualityGateService{
+		Organization: organization,
+		Project:      project,
+		Branch:       branch,
+		PullRequest:  pullRequest,
+		apiClient:    NewAPIClient(host, token, client),
+	}
+}
@@ -0,0 +1,107 @@
+package sonar
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/jarcoal/httpmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	piperhttp "github.com/SAP/jenkins-library/pkg/http"
+)
+
+func TestQualityGateService(t *testing.T) {
+	testURL := "https://example.org"
+	t.Run("success", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		sender := &piperhttp.Client{}
+		sender.SetOptions(piperhttp.ClientOptions{MaxRetries: -1, UseDefaultTransport: true})
+		// add response handler
+		var requestedQuery string
+		httpmock.RegisterResponder(http.MethodGet, testURL+"/api/"+EndpointQualityGatesProjectStatus+"", queryRecordingResponder(http.StatusOK, responseQualityGateError, &requestedQuery))
+		// create service instance
+		serviceUnderTest := NewQualityGateService(testURL, mock.Anything, "my-project", mock.Anything, "main", "", sender)
+		// test
+		status, err := serviceUnderTest.GetStatus("")
+		// assert
+		assert.NoError(t, err)
+		assert.False(t, status.Passed())
+		assert.Equal(t, 2, len(status.Conditions))
+		assert.Equal(t, []QualityGateCondition{{Metric: "new_coverage", Comparator: "LT", Threshold: "80", Actual: "42.5", Status: "ERROR"}}, status.FailedConditions())
+		assert.Equal(t, 1, httpmock.GetTotalCallCount(), "unexpected number of requests")
+		assert.Equal(t, "branch=main&projectKey=my-project", requestedQuery)
+	})
+	t.Run("by analysis", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		sender := &piperhttp.Client{}
+		sender.SetOptions(piperhttp.ClientOptions{MaxRetries: -1, UseDefaultTransport: true})
+		// add response handler
+		var requestedQuery string
+		httpmock.RegisterResponder(http.MethodGet, testURL+"/api/"+EndpointQualityGatesProjectStatus+"", queryRecordingResponder(http.StatusOK, `{"projectStatus": {"status": "OK"}}`, &requestedQuery))
+		// create service instance
+		serviceUnderTest := NewQualityGateService(testURL, mock.Anything, "my-project", mock.Anything, "main", "42", sender)
+		// test
+		status, err := serviceUnderTest.GetStatus("AXe5y_mgcqEbAZBpFc0V")
+		// assert
+		assert.NoError(t, err)
+		assert.True(t, status.Passed())
+		assert.Empty(t, status.FailedConditions())
+		assert.Equal(t, "analysisId=AXe5y_mgcqEbAZBpFc0V", requestedQuery)
+	})
+	t.Run("error", func(t *testing.T) {
+		httpmock.Activate()
+		defer httpmock.DeactivateAndReset()
+
+		sender := &piperhttp.Client{}
+		sender.SetOptions(piperhttp.ClientOptions{MaxRetries: -1, UseDefaultTransport: true})
+		// add response handler
+		httpmock.RegisterResponder(http.MethodGet, testURL+"/api/"+EndpointQualityGatesProjectStatus+"", httpmock.NewStringResponder(http.StatusNotFound, `{"errors": [{"msg": "Analysis not found"}]}`))
+		// create service instance
+		serviceUnderTest := NewQualityGateService(testURL, mock.Anything, "my-project", mock.Anything, "", "42", sender)
+		// test
+		status, err := serviceUnderTest.GetStatus("")
+		// assert
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to fetch the quality gate status")
+		assert.Nil(t, status)
+	})
+}
+
+// queryRecordingResponder responds with the given body and records the query of the request
+func queryRecordingResponder(status int, body string, query *string) httpmock.Responder {
+	return func(req *http.Request) (*http.Response, error) {
+		*query = req.URL.RawQuery
+		return httpmock.NewStringResponse(status, body), nil
+	}
+}
+
+const responseQualityGateError = `{
+  "projectStatus": {
+    "status": "ERROR",
+    "conditions": [
+      {
+        "status": "ERROR",
+        "metricKey": "new_coverage",
+        "comparator": "LT",
+        "periodIndex": 1,
+        "errorThreshold": "80",
+        "actualValue": "42.5"
+      },
+      {
+        "status": "OK",
+        "metricKey": "new_duplicated_lines_density",
+        "comparator": "GT",
+        "periodIndex": 1,
+        "errorThreshold": "3",
+        "actualValue": "0.0"
+      }
+    ],
+    "periods": [],
+    "ignoredConditions": false
+  }
+}`
@@ -2,23 +2,30 @@ package sonar
 
 import (
 	"encoding/json"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 )
 
 const reportFileName = "sonarscan.json"
 
+// maxSummaryIssues limits the number of issues listed in the pull-request summary
+const maxSummaryIssues = 20
+
 // ReportData is representing the data of the step report JSON
 type ReportData struct {
-	ServerURL      string            `json:"serverUrl"`
-	ProjectKey     string            `json:"projectKey"`
-	TaskID         string            `json:"taskId"`
-	ChangeID       string            `json:"changeID,omitempty"`
-	BranchName     string            `json:"branchName,omitempty"`
-	Organization   string            `json:"organization,omitempty"`
-	NumberOfIssues Issues            `json:"numberOfIssues"`
-	Coverage       *SonarCoverage    `json:"coverage,omitempty"`
-	LinesOfCode    *SonarLinesOfCode `json:"linesOfCode,omitempty"`
+	ServerURL      string             `json:"serverUrl"`
+	ProjectKey     string             `json:"projectKey"`
+	TaskID         string             `json:"taskId"`
+	ChangeID       string             `json:"changeID,omitempty"`
+	BranchName     string             `json:"branchName,omitempty"`
+	Organization   string             `json:"organization,omitempty"`
+	NumberOfIssues Issues             `json:"numberOfIssues"`
+	Coverage       *SonarCoverage     `json:"coverage,omitempty"`
+	LinesOfCode    *SonarLinesOfCode  `json:"linesOfCode,omitempty"`
+	QualityGate    *QualityGateStatus `json:"qualityGate,omitempty"`
+	NewIssues      []IssueDetail      `json:"newIssues,omitempty"`
 }
 
 // Issues ...
@@ -38,3 +45,53 @@ func WriteReport(data ReportData, reportPath string, writeToFile func(f string,
 	}
 	return writeToFile(filepath.Join(reportPath, reportFileName), jsonData, 0644)
 }
+
+// CreatePullRequestSummary creates a markdown summary of the quality gate and the new issues to be posted on a pull-request.
+func CreatePullRequestSummary(data ReportData, dashboardURL string) []byte {
+	var summary strings.Builder
+	summary.WriteString("## SonarQube Analysis\n\n")
+
+	if data.QualityGate != nil {
+		if data.QualityGate.Passed() {
+			fmt.Fprintf(&summary, "**Quality Gate passed** (status: %v)\n\n", data.QualityGate.Status)
+		} else {
+			summary.WriteString("**Quality Gate failed**\n\n")
+		}
+		if len(data.QualityGate.Conditions) > 0 {
+			summary.WriteString("| Metric | Operator | Threshold | Actual | Status |\n")
+			summary.WriteString("|--------|----------|-----------|--------|--------|\n")
+			for _, condition := range data.QualityGate.Conditions {
+				fmt.Fprintf(&summary, "| %v | %v | %v | %v | %v |\n", condition.Metric, condition.Comparator, condition.Threshold, condition.Actual, condition.Status)
+			}
+			summary.WriteString("\n")
+		}
+	}
+
+	issues := data.NumberOfIssues
+	fmt.Fprintf(&summary, "Unresolved issues: %v blocker, %v critical, %v major, %v minor, %v info\n\n", issues.Blocker, issues.Critical, issues.Major, issues.Minor, issues.Info)
+
+	if len(data.NewIssues) == 0 {
+		summary.WriteString("No new issues found.\n")
+	} else {
+		fmt.Fprintf(&summary, "### New Issues (%v)\n\n", len(data.NewIssues))
+		summary.WriteString("| Severity | Type | Rule | Location | Message |\n")
+		summary.WriteString("|----------|------|------|----------|---------|\n")
+		for i, issue := range data.NewIssues {
+			if i == maxSummaryIssues {
+				fmt.Fprintf(&summary, "\n... and %v more\n", len(data.NewIssues)-maxSummaryIssues)
+				break
+			}
+			location := issue.File
+			if len(location) > 0 && issue.Line > 0 {
+				location = fmt.Sprintf("%v:%v", issue.File, issue.Line)
+			}
+			message := strings.ReplaceAll(issue.Message, "|", "\\|")
+			fmt.Fprintf(&summary, "| %v | %v | %v | %v | %v |\n", issue.Severity, issue.Type, issue.Rule, location, message)
+		}
+	}
+
+	if len(dashboardURL) > 0 {
+		fmt.Fprintf(&summary, "\n[See analysis details on SonarQube](%v)\n", dashboardURL)
+	}
+	return []byte(summary.String())
+}
@@ -51,3 +51,35 @@ func TestWriteReport(t *testing.T) {
 	assert.Equal(t, expected, fileContent)
 	assert.Equal(t, reportFileName, fileName)
 }
+
+func TestCreatePullRequestSummary(t *testing.T) {
+	t.Run("failed quality gate and new issues", func(t *testing.T) {
+		testData := ReportData{
+			NumberOfIssues: Issues{Critical: 1, Minor: 2},
+			QualityGate: &QualityGateStatus{Status: QualityGateStatusError, Conditions: []QualityGateCondition{
+				{Metric: "new_coverage", Comparator: "LT", Threshold: "80", Actual: "42.5", Status: QualityGateStatusError},
+			}},
+			NewIssues: []IssueDetail{
+				{Rule: "go:S3776", Severity: "CRITICAL", Type: "CODE_SMELL", Message: "Refactor | reduce", File: "cmd/main.go", Line: 12},
+			},
+		}
+		// test
+		summary := string(CreatePullRequestSummary(testData, "https://sonarcloud.io/dashboard?id=piper"))
+		// assert
+		assert.Contains(t, summary, "**Quality Gate failed**")
+		assert.Contains(t, summary, "| new_coverage | LT | 80 | 42.5 | ERROR |")
+		assert.Contains(t, summary, "Unresolved issues: 0 blocker, 1 critical, 0 major, 2 minor, 0 info")
+		assert.Contains(t, summary, "### New Issues (1)")
+		assert.Contains(t, summary, "| CRITICAL | CODE_SMELL | go:S3776 | cmd/main.go:12 | Refactor \\| reduce |")
+		assert.Contains(t, summary, "[See analysis details on SonarQube](https://sonarcloud.io/dashboard?id=piper)")
+	})
+	t.Run("passed quality gate", func(t *testing.T) {
+		testData := ReportData{QualityGate: &QualityGateStatus{Status: QualityGateStatusOK}}
+		// test
+		summary := string(CreatePullRequestSummary(testData, ""))
+		// assert
+		assert.Contains(t, summary, "**Quality Gate passed** (status: OK)")
+		assert.Contains(t, summary, "No new issues found.")
+		assert.NotContains(t, summary, "See analysis details")
+	})
+}
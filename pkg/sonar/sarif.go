@@ -0,0 +1,130 @@
+package sonar
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/format"
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/pkg/errors"
+)
+
+const sarifFileName = "sonarscan.sarif"
+
+// ConvertToSarif converts the new issues of a SonarQube analysis into SARIF
+func ConvertToSarif(issues []IssueDetail, dashboardURL string) format.SARIF {
+	start := time.Now() // For the conversion start time
+	log.Entry().Debug("[SARIF] Now handling results.")
+
+	var sarif format.SARIF
+	sarif.Schema = "https://docs.oasis-open.org/sarif/sarif/v2.1.0/cos02/schemas/sarif-schema-2.1.0.json"
+	sarif.Version = "2.1.0"
+	var sonarRun format.Runs
+	sonarRun.ColumnKind = "utf16CodeUnits"
+	sarif.Runs = append(sarif.Runs, sonarRun)
+
+	rulesArray := []format.SarifRule{}
+	ruleIndices := map[string]int{}
+	for _, issue := range issues {
+		if _, ok := ruleIndices[issue.Rule]; !ok {
+			ruleIndices[issue.Rule] = len(rulesArray)
+			rulesArray = append(rulesArray, sarifRule(issue))
+		}
+
+		result := format.Results{
+			RuleID:    issue.Rule,
+			RuleIndex: ruleIndices[issue.Rule],
+			Level:     sarifLevel(issue.Severity),
+			Message:   &format.Message{Text: issue.Message},
+		}
+		if len(issue.File) > 0 {
+			loc := format.Location{}
+			loc.PhysicalLocation.ArtifactLocation.URI = issue.File
+			loc.PhysicalLocation.Region.StartLine = issue.Line
+			loc.PhysicalLocation.Region.EndLine = issue.EndLine
+			result.Locations = append(result.Locations, loc)
+		}
+		result.PartialFingerprints.PrimaryLocationLineHash = issue.Key
+
+		props := new(format.SarifProperties)
+		props.InstanceID = issue.Key
+		props.ToolSeverity = issue.Severity
+		props.ToolSeverityIndex = severityIndex(issue.Severity)
+		props.ToolState = "Open"
+		props.RuleGUID = issue.Rule
+		result.Properties = props
+
+		sarif.Runs[0].Results = append(sarif.Runs[0].Results, result)
+	}
+
+	log.Entry().Debug("[SARIF] Now handling driver object.")
+	tool := format.Tool{}
+	tool.Driver.Name = "SonarQube"
+	tool.Driver.InformationUri = "https://docs.sonarqube.org"
+	tool.Driver.Rules = rulesArray
+	sarif.Runs[0].Tool = tool
+
+	sarif.Runs[0].AutomationDetails = &format.AutomationDetails{Id: dashboardURL}
+
+	// Add a conversion object to highlight this isn't native SARIF
+	conversion := new(format.Conversion)
+	conversion.Tool.Driver.Name = "Piper SonarQube issues to SARIF converter"
+	conversion.Tool.Driver.InformationUri = "https://github.com/SAP/jenkins-library"
+	conversion.Invocation.ExecutionSuccessful = true
+	conversion.Invocation.StartTimeUtc = start.Format("2006-01-02T15:04:05.000Z")
+	sarif.Runs[0].Conversion = conversion
+
+	return sarif
+}
+
+// WriteSarif writes the SARIF file into the given directory
+func WriteSarif(sarif format.SARIF, reportPath string, writeToFile func(f string, d []byte, p os.FileMode) error) error {
+	sarifJSON, err := json.MarshalIndent(sarif, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal SARIF")
+	}
+	return writeToFile(filepath.Join(reportPath, sarifFileName), sarifJSON, 0644)
+}
+
+func sarifRule(issue IssueDetail) format.SarifRule {
+	rule := format.SarifRule{ID: issue.Rule, Name: issue.Rule}
+	if len(issue.RuleName) > 0 {
+		rule.ShortDescription = &format.Message{Text: issue.RuleName}
+	}
+	// rule keys are "<repository>:<rule>", e.g. "go:S3776"
+	ruleKey := issue.Rule[strings.LastIndex(issue.Rule, ":")+1:]
+	rule.HelpURI = fmt.Sprintf("https://rules.sonarsource.com/search?q=%v", ruleKey)
+	rule.Properties = &format.SarifRuleProperties{Tags: []string{strings.ToLower(issue.Type)}}
+	if issue.Type == "VULNERABILITY" {
+		rule.Properties.Tags = append(rule.Properties.Tags, "security")
+	}
+	return rule
+}
+
+func sarifLevel(severity string) string {
+	switch severity {
+	case blocker.ToString(), critical.ToString():
+		return "error"
+	case major.ToString():
+		return "warning"
+	}
+	return "note"
+}
+
+func severityIndex(severity string) int {
+	switch severity {
+	case blocker.ToString():
+		return 4
+	case critical.ToString():
+		return 3
+	case major.ToString():
+		return 2
+	case minor.ToString():
+		return 1
+	}
+	return 0
+}
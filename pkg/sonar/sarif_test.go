@@ -0,0 +1,39 @@
+package sonar
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvertToSarif(t *testing.T) {
+	issues := []IssueDetail{
+		{Key: "AX1", Rule: "go:S3776", RuleName: "Cognitive Complexity of functions should not be too high", Severity: "CRITICAL", Type: "CODE_SMELL", Message: "Refactor this method", File: "cmd/main.go", Line: 12, EndLine: 14},
+		{Key: "AX2", Rule: "go:S3776", Severity: "CRITICAL", Type: "CODE_SMELL", Message: "Refactor this other method", File: "cmd/other.go", Line: 3},
+		{Key: "AX3", Rule: "go:S2068", Severity: "BLOCKER", Type: "VULNERABILITY", Message: "Remove this hard-coded password"},
+	}
+	// test
+	sarif := ConvertToSarif(issues, "https://sonarcloud.io/dashboard?id=piper")
+	// assert
+	assert.Equal(t, "2.1.0", sarif.Version)
+	assert.Equal(t, 3, len(sarif.Runs[0].Results))
+	assert.Equal(t, 2, len(sarif.Runs[0].Tool.Driver.Rules))
+	assert.Equal(t, "https://sonarcloud.io/dashboard?id=piper", sarif.Runs[0].AutomationDetails.Id)
+
+	first := sarif.Runs[0].Results[0]
+	assert.Equal(t, "go:S3776", first.RuleID)
+	assert.Equal(t, "error", first.Level)
+	assert.Equal(t, "cmd/main.go", first.Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, 12, first.Locations[0].PhysicalLocation.Region.StartLine)
+	assert.Equal(t, 14, first.Locations[0].PhysicalLocation.Region.EndLine)
+	assert.Equal(t, 3, first.Properties.ToolSeverityIndex)
+
+	assert.Equal(t, 0, sarif.Runs[0].Results[1].RuleIndex)
+	assert.Equal(t, 1, sarif.Runs[0].Results[2].RuleIndex)
+	assert.Empty(t, sarif.Runs[0].Results[2].Locations)
+
+	rule := sarif.Runs[0].Tool.Driver.Rules[1]
+	assert.Equal(t, "go:S2068", rule.ID)
+	assert.Equal(t, "https://rules.sonarsource.com/search?q=S2068", rule.HelpURI)
+	assert.Equal(t, []string{"vulnerability", "security"}, rule.Properties.Tags)
+}
@@ -23,6 +23,7 @@ const (
 // TaskService ...
 type TaskService struct {
 	TaskID       string
+	AnalysisID   string
 	PollInterval time.Duration
 	apiClient    *Requester
 }
@@ -65,6 +66,7 @@ func (service *TaskService) HasFinished() (bool, error) {
 	if result.Task.Status == taskStatusPending || result.Task.Status == taskStatusProcessing {
 		return false, nil
 	}
+	service.AnalysisID = result.Task.AnalysisID
 	// for _, warning := range result.Task.Warnings {
 	// 	log.Entry().Warnf("Warnings during analysis: %s", warning)
 	// }
@@ -99,6 +99,7 @@ func TestWaitForTask(t *testing.T) {
 		// assert
 		assert.NoError(t, err)
 		assert.Equal(t, 3, httpmock.GetTotalCallCount(), "unexpected number of requests")
+		assert.Equal(t, "AXe5y_mgcqEbAZBpFc0V", serviceUnderTest.AnalysisID)
 	})
 	t.Run("failure", func(t *testing.T) {
 		httpmock.Activate()
@@ -59,3 +59,13 @@ const (
 	minor    issueSeverity = "MINOR"
 	info     issueSeverity = "INFO"
 )
+
+// QualityGateProjectStatusOption is a copy from magicsong/sonargo plus the "internal" fields branch and pullRequest.
+type QualityGateProjectStatusOption struct {
+	Branch      string `url:"branch,omitempty"`      // Description:"Branch key"
+	PullRequest string `url:"pullRequest,omitempty"` // Description:"Pull request id"
+	// copied from https://github.com/magicsong/sonargo/blob/103eda7abc20bd192a064b6eb94ba26329e339f1/sonar/qualitygates_service.go#L276
+	AnalysisId string `url:"analysisId,omitempty"` // Description:"Analysis id",ExampleValue:"AU-TpxcA-iU5OvuD2FL1"
+	ProjectId  string `url:"projectId,omitempty"`  // Description:"Project id",ExampleValue:"AU-Tpxb--iU5OvuD2FLy"
+	ProjectKey string `url:"projectKey,omitempty"` // Description:"Project key",ExampleValue:"my_project"
+}
@@ -0,0 +1,286 @@
+package versioning
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pelletier/go-toml"
+)
+
+var (
+	// cargoDependencyTableRegex matches the dependency tables of a manifest, e.g. dependencies, workspace.dependencies
+	// or target.'cfg(unix)'.dev-dependencies, as well as the tables of single dependencies like dependencies.foo
+	cargoDependencyTableRegex  = regexp.MustCompile(`(^|\.)(dependencies|dev-dependencies|build-dependencies)(\.([A-Za-z0-9_-]+))?$`)
+	cargoInlineDependencyRegex = regexp.MustCompile(`^\s*["']?([A-Za-z0-9_-]+)["']?\s*=\s*\{(.*)\}`)
+	cargoPackageRegex          = regexp.MustCompile(`\bpackage\s*=\s*["']([^"']+)["']`)
+	cargoPathRegex             = regexp.MustCompile(`\bpath\s*=`)
+	// cargoRequirementRegex matches a version requirement, the operator of the requirement is kept when updating it
+	cargoRequirementRegex = regexp.MustCompile(`(\bversion\s*=\s*["'])([=^~<>]*\s*)[^"']*(["'])`)
+)
+
+// Cargo defines an artifact using a Cargo.toml for versioning, the members of a Rust workspace are considered as well
+type Cargo struct {
+	path  string
+	utils Utils
+	tree  *toml.Tree
+}
+
+func (c *Cargo) init() error {
+	if c.utils == nil {
+		return fmt.Errorf("no file utils provided")
+	}
+	if len(c.path) == 0 {
+		c.path = "Cargo.toml"
+	}
+	if c.tree == nil {
+		tree, err := c.readManifest(c.path)
+		if err != nil {
+			return err
+		}
+		c.tree = tree
+	}
+	return nil
+}
+
+func (c *Cargo) readManifest(path string) (*toml.Tree, error) {
+	content, err := c.utils.FileRead(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file '%v': %w", path, err)
+	}
+	tree, err := toml.LoadBytes(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse file '%v': %w", path, err)
+	}
+	return tree, nil
+}
+
+// cargoVersionTable returns the table of the manifest which contains the version,
+// members of a workspace may inherit the version of the workspace via "version.workspace = true"
+func cargoVersionTable(tree *toml.Tree) (string, bool) {
+	for _, table := range []string{"package", "workspace.package"} {
+		if _, ok := tree.Get(table + ".version").(string); ok {
+			return table, true
+		}
+	}
+	return "", false
+}
+
+// members returns the manifests of the workspace members
+func (c *Cargo) members() ([]string, error) {
+	patterns, _ := c.tree.Get("workspace.members").([]interface{})
+	members := []string{}
+	for _, pattern := range patterns {
+		matches, err := c.utils.Glob(filepath.Join(filepath.Dir(c.path), fmt.Sprint(pattern), "Cargo.toml"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to find workspace members matching '%v': %w", pattern, err)
+		}
+		members = append(members, matches...)
+	}
+	sort.Strings(members)
+	return members, nil
+}
+
+// VersioningScheme returns the relevant versioning scheme
+func (c *Cargo) VersioningScheme() string {
+	return "semver2"
+}
+
+// GetVersion returns the version of the package or workspace, for a workspace without version the version of its first member
+func (c *Cargo) GetVersion() (string, error) {
+	if err := c.init(); err != nil {
+		return "", fmt.Errorf("failed to init cargo versioning: %w", err)
+	}
+	if table, ok := cargoVersionTable(c.tree); ok {
+		return c.tree.Get(table + ".version").(string), nil
+	}
+
+	members, err := c.members()
+	if err != nil {
+		return "", err
+	}
+	for _, member := range members {
+		tree, err := c.readManifest(member)
+		if err != nil {
+			return "", err
+		}
+		if table, ok := cargoVersionTable(tree); ok {
+			return tree.Get(table + ".version").(string), nil
+		}
+	}
+	return "", fmt.Errorf("no version found in '%v' and its workspace members", c.path)
+}
+
+// SetVersion updates the version of the package or workspace as well as the versions of all workspace members
+func (c *Cargo) SetVersion(version string) error {
+	if err := c.init(); err != nil {
+		return fmt.Errorf("failed to init cargo versioning: %w", err)
+	}
+
+	members, err := c.members()
+	if err != nil {
+		return err
+	}
+	manifests := append([]string{c.path}, members...)
+	crates, err := c.crateNames(manifests)
+	if err != nil {
+		return err
+	}
+	updated := 0
+	for _, manifest := range manifests {
+		ok, err := c.setManifestVersion(manifest, version, crates)
+		if err != nil {
+			return err
+		}
+		if ok {
+			updated++
+		}
+	}
+	if updated == 0 {
+		return fmt.Errorf("no version found in '%v' and its workspace members", c.path)
+	}
+	c.tree = nil
+	return nil
+}
+
+// crateNames returns the names of the packages of the manifests
+func (c *Cargo) crateNames(manifests []string) (map[string]bool, error) {
+	crates := map[string]bool{}
+	for _, manifest := range manifests {
+		tree, err := c.readManifest(manifest)
+		if err != nil {
+			return nil, err
+		}
+		if name, ok := tree.Get("package.name").(string); ok {
+			crates[name] = true
+		}
+	}
+	return crates, nil
+}
+
+// setManifestVersion updates the version of the manifest as well as the version requirements of its path dependencies
+// on crates of the workspace, e.g. foo = { path = "../foo", version = "1.2.3" }, since they need to be published together
+func (c *Cargo) setManifestVersion(path, version string, crates map[string]bool) (bool, error) {
+	tree, err := c.readManifest(path)
+	if err != nil {
+		return false, err
+	}
+	content, err := c.utils.FileRead(path)
+	if err != nil {
+		return false, fmt.Errorf("failed to read file '%v': %w", path, err)
+	}
+	updated := string(content)
+	table, hasVersion := cargoVersionTable(tree)
+	// e.g. a member inheriting the version of the workspace has no version
+	if hasVersion {
+		var ok bool
+		if updated, ok = setTomlVersion(updated, table, version); !ok {
+			return false, fmt.Errorf("failed to update version in table '%v' of '%v'", table, path)
+		}
+	}
+	updated = setCargoDependencyVersions(updated, tree, crates, version)
+	if updated == string(content) {
+		return hasVersion, nil
+	}
+	if err := c.utils.FileWrite(path, []byte(updated), 0666); err != nil {
+		return false, fmt.Errorf("failed to write file '%v': %w", path, err)
+	}
+	return hasVersion, nil
+}
+
+// setCargoDependencyVersions updates the version requirements of the path dependencies on the given crates
+func setCargoDependencyVersions(content string, tree *toml.Tree, crates map[string]bool, version string) string {
+	lines := strings.Split(content, "\n")
+	currentTable := ""
+	// dependency is set within the table of a single path dependency on one of the crates
+	dependency := false
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "[") {
+			currentTable, dependency = "", false
+			if match := tomlTableRegex.FindStringSubmatch(line); match != nil {
+				currentTable = match[1]
+				if table := cargoDependencyTableRegex.FindStringSubmatch(currentTable); table != nil && len(table[4]) > 0 {
+					dependency = isCargoPathDependency(tree, currentTable, table[4], crates)
+				}
+			}
+			continue
+		}
+		if dependency {
+			if strings.HasPrefix(strings.TrimSpace(line), "version") && cargoRequirementRegex.MatchString(line) {
+				lines[i] = setCargoRequirement(line, version)
+			}
+			continue
+		}
+		if !cargoDependencyTableRegex.MatchString(currentTable) {
+			continue
+		}
+		match := cargoInlineDependencyRegex.FindStringSubmatch(line)
+		if match == nil || !cargoPathRegex.MatchString(match[2]) {
+			continue
+		}
+		name := match[1]
+		if pkg := cargoPackageRegex.FindStringSubmatch(match[2]); pkg != nil {
+			name = pkg[1]
+		}
+		if crates[name] {
+			lines[i] = setCargoRequirement(line, version)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// isCargoPathDependency checks whether the table of a single dependency, e.g. [dependencies.foo], is a path dependency on one of the crates
+func isCargoPathDependency(tree *toml.Tree, table, key string, crates map[string]bool) bool {
+	dependency, ok := tree.GetPath(tomlKeyPath(table)).(*toml.Tree)
+	if !ok || !dependency.Has("path") {
+		return false
+	}
+	name, ok := dependency.Get("package").(string)
+	if !ok {
+		name = key
+	}
+	return crates[name]
+}
+
+// tomlKeyPath splits a dotted key into its parts, dots within quoted parts like 'cfg(target_os = "linux")' are kept
+func tomlKeyPath(key string) []string {
+	parts := []string{}
+	current := strings.Builder{}
+	quote := rune(0)
+	for _, char := range key {
+		switch {
+		case quote != 0 && char == quote:
+			quote = 0
+		case quote == 0 && (char == '"' || char == '\''):
+			quote = char
+		case quote == 0 && char == '.':
+			parts = append(parts, strings.TrimSpace(current.String()))
+			current.Reset()
+		default:
+			current.WriteRune(char)
+		}
+	}
+	return append(parts, strings.TrimSpace(current.String()))
+}
+
+func setCargoRequirement(line, version string) string {
+	return cargoRequirementRegex.ReplaceAllString(line, "${1}${2}"+version+"${3}")
+}
+
+// GetCoordinates returns the coordinates of the package
+func (c *Cargo) GetCoordinates() (Coordinates, error) {
+	result := Coordinates{}
+	if err := c.init(); err != nil {
+		return result, fmt.Errorf("failed to init cargo versioning: %w", err)
+	}
+	result.ArtifactID, _ = c.tree.Get("package.name").(string)
+
+	var err error
+	result.Version, err = c.GetVersion()
+	if err != nil {
+		return result, fmt.Errorf("failed to retrieve coordinates: %w", err)
+	}
+	return result, nil
+}
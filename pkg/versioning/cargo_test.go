@@ -0,0 +1,97 @@
+package versioning
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCargoGetVersion(t *testing.T) {
+	t.Run("success case - package", func(t *testing.T) {
+		fileUtils := newVersioningMockUtils()
+		fileUtils.AddFile("Cargo.toml", []byte("[package]\nname = \"service\"\nversion = \"1.2.3\"\n\n[dependencies]\nserde = { version = \"1.0\" }\n"))
+
+		cargo := Cargo{utils: fileUtils}
+		version, err := cargo.GetVersion()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "1.2.3", version)
+	})
+
+	t.Run("success case - workspace member", func(t *testing.T) {
+		fileUtils := newVersioningMockUtils()
+		fileUtils.AddFile("Cargo.toml", []byte("[workspace]\nmembers = [\"crates/*\"]\n"))
+		fileUtils.AddFile("crates/api/Cargo.toml", []byte("[package]\nname = \"api\"\nversion = \"0.3.0\"\n"))
+
+		cargo := Cargo{utils: fileUtils}
+		version, err := cargo.GetVersion()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "0.3.0", version)
+	})
+
+	t.Run("error case - no version", func(t *testing.T) {
+		fileUtils := newVersioningMockUtils()
+		fileUtils.AddFile("Cargo.toml", []byte("[workspace]\nmembers = []\n"))
+
+		cargo := Cargo{utils: fileUtils}
+		_, err := cargo.GetVersion()
+
+		assert.EqualError(t, err, "no version found in 'Cargo.toml' and its workspace members")
+	})
+}
+
+func TestCargoSetVersion(t *testing.T) {
+	t.Run("success case - workspace", func(t *testing.T) {
+		fileUtils := newVersioningMockUtils()
+		fileUtils.AddFile("Cargo.toml", []byte("[workspace]\nmembers = [\"crates/*\", \"cli\"]\n\n[workspace.package]\nversion = \"1.0.0\"\nedition = \"2021\"\n"))
+		fileUtils.AddFile("crates/api/Cargo.toml", []byte("[package]\nname = \"api\"\nversion.workspace = true\n"))
+		fileUtils.AddFile("crates/core/Cargo.toml", []byte("[package]\nname = \"core\"\nversion = \"0.9.0\"\n"))
+		fileUtils.AddFile("cli/Cargo.toml", []byte("[package]\nname = \"cli\"\nversion = { workspace = true }\n"))
+
+		cargo := Cargo{utils: fileUtils}
+		err := cargo.SetVersion("1.1.0")
+
+		assert.NoError(t, err)
+		content, _ := fileUtils.FileRead("Cargo.toml")
+		assert.Contains(t, string(content), "[workspace.package]\nversion = \"1.1.0\"\n")
+		content, _ = fileUtils.FileRead("crates/core/Cargo.toml")
+		assert.Contains(t, string(content), "version = \"1.1.0\"")
+		content, _ = fileUtils.FileRead("crates/api/Cargo.toml")
+		assert.Contains(t, string(content), "version.workspace = true")
+		version, _ := cargo.GetVersion()
+		assert.Equal(t, "1.1.0", version)
+	})
+
+	t.Run("success case - path dependencies within workspace", func(t *testing.T) {
+		fileUtils := newVersioningMockUtils()
+		fileUtils.AddFile("Cargo.toml", []byte("[workspace]\nmembers = [\"crates/*\"]\n\n[workspace.package]\nversion = \"1.0.0\"\n\n"+
+			"[workspace.dependencies]\napi = { path = \"crates/api\", version = \"1.0.0\" }\nserde = { version = \"1.0.0\" }\n"))
+		fileUtils.AddFile("crates/api/Cargo.toml", []byte("[package]\nname = \"api\"\nversion.workspace = true\n"))
+		fileUtils.AddFile("crates/core/Cargo.toml", []byte("[package]\nname = \"core\"\nversion = \"1.0.0\"\n\n"+
+			"[dependencies]\napi = { version = \"=1.0.0\", path = \"../api\" }\nvendored = { path = \"../../vendor/lib\", version = \"0.3.0\" }\nlog = \"0.4\"\n\n"+
+			"[target.'cfg(unix)'.dev-dependencies.client]\npackage = \"api\"\npath = \"../api\"\nversion = \"^1.0.0\"\n"))
+
+		cargo := Cargo{utils: fileUtils}
+		err := cargo.SetVersion("1.1.0")
+
+		assert.NoError(t, err)
+		content, _ := fileUtils.FileRead("Cargo.toml")
+		assert.Contains(t, string(content), "api = { path = \"crates/api\", version = \"1.1.0\" }\nserde = { version = \"1.0.0\" }\n")
+		content, _ = fileUtils.FileRead("crates/core/Cargo.toml")
+		assert.Equal(t, "[package]\nname = \"core\"\nversion = \"1.1.0\"\n\n"+
+			"[dependencies]\napi = { version = \"=1.1.0\", path = \"../api\" }\nvendored = { path = \"../../vendor/lib\", version = \"0.3.0\" }\nlog = \"0.4\"\n\n"+
+			"[target.'cfg(unix)'.dev-dependencies.client]\npackage = \"api\"\npath = \"../api\"\nversion = \"^1.1.0\"\n", string(content))
+	})
+}
+
+func TestCargoGetCoordinates(t *testing.T) {
+	fileUtils := newVersioningMockUtils()
+	fileUtils.AddFile("Cargo.toml", []byte("[package]\nname = \"service\"\nversion = \"1.2.3\"\n"))
+
+	cargo := Cargo{utils: fileUtils}
+	coordinates, err := cargo.GetCoordinates()
+
+	assert.NoError(t, err)
+	assert.Equal(t, Coordinates{ArtifactID: "service", Version: "1.2.3"}, coordinates)
+}
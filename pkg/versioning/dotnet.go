@@ -0,0 +1,138 @@
+package versioning
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// DotNet defines an artifact using an MSBuild project file (e.g. *.csproj) or a Directory.Build.props for versioning
+type DotNet struct {
+	path    string
+	utils   Utils
+	content string
+}
+
+// dotNetProjectFiles are the patterns of MSBuild project files which are searched in case no path is provided
+var dotNetProjectFiles = []string{"*.csproj", "*.fsproj", "*.vbproj"}
+
+func (d *DotNet) init() error {
+	if d.utils == nil {
+		return fmt.Errorf("no file utils provided")
+	}
+	if len(d.path) == 0 {
+		path, err := d.findProjectFile()
+		if err != nil {
+			return err
+		}
+		d.path = path
+	}
+	if len(d.content) == 0 {
+		content, err := d.utils.FileRead(d.path)
+		if err != nil {
+			return fmt.Errorf("failed to read file '%v': %w", d.path, err)
+		}
+		d.content = string(content)
+	}
+	return nil
+}
+
+// findProjectFile returns the Directory.Build.props, which defines the properties of all projects, or the only project file
+func (d *DotNet) findProjectFile() (string, error) {
+	if exists, _ := d.utils.FileExists("Directory.Build.props"); exists {
+		return "Directory.Build.props", nil
+	}
+	projectFiles := []string{}
+	for _, pattern := range dotNetProjectFiles {
+		matches, err := d.utils.Glob(pattern)
+		if err != nil {
+			return "", fmt.Errorf("failed to find files matching '%v': %w", pattern, err)
+		}
+		projectFiles = append(projectFiles, matches...)
+	}
+	switch len(projectFiles) {
+	case 0:
+		return "", fmt.Errorf("failed to find a Directory.Build.props or a project file matching %v", dotNetProjectFiles)
+	case 1:
+		return projectFiles[0], nil
+	default:
+		return "", fmt.Errorf("multiple project files found %v, please define the build descriptor to use", projectFiles)
+	}
+}
+
+func msbuildPropertyRegex(name string) *regexp.Regexp {
+	return regexp.MustCompile(fmt.Sprintf(`(<%v>)\s*([^<]*?)\s*(</%v>)`, name, name))
+}
+
+// versionProperty returns the property containing the version, "VersionPrefix" is used in case the version suffix is maintained separately
+func (d *DotNet) versionProperty() (*regexp.Regexp, error) {
+	for _, name := range []string{"Version", "VersionPrefix"} {
+		property := msbuildPropertyRegex(name)
+		if property.MatchString(d.content) {
+			return property, nil
+		}
+	}
+	return nil, fmt.Errorf("no <Version> or <VersionPrefix> property found in '%v'", d.path)
+}
+
+// VersioningScheme returns the relevant versioning scheme
+func (d *DotNet) VersioningScheme() string {
+	return "semver2"
+}
+
+// GetVersion returns the version property of the project
+func (d *DotNet) GetVersion() (string, error) {
+	if err := d.init(); err != nil {
+		return "", fmt.Errorf("failed to init .NET versioning: %w", err)
+	}
+	property, err := d.versionProperty()
+	if err != nil {
+		return "", err
+	}
+	return property.FindStringSubmatch(d.content)[2], nil
+}
+
+// SetVersion updates the version property of the project, the formatting of the file is preserved
+func (d *DotNet) SetVersion(version string) error {
+	if err := d.init(); err != nil {
+		return fmt.Errorf("failed to init .NET versioning: %w", err)
+	}
+	property, err := d.versionProperty()
+	if err != nil {
+		return err
+	}
+	location := property.FindStringSubmatchIndex(d.content)
+	// only the first occurrence is updated, conditional property groups keep their version
+	content := d.content[:location[0]] + property.ReplaceAllString(d.content[location[0]:location[1]], "${1}"+version+"${3}") + d.content[location[1]:]
+	if err := d.utils.FileWrite(d.path, []byte(content), 0666); err != nil {
+		return fmt.Errorf("failed to write file '%v': %w", d.path, err)
+	}
+	d.content = content
+	return nil
+}
+
+// GetCoordinates returns the package id of the project together with its version
+func (d *DotNet) GetCoordinates() (Coordinates, error) {
+	result := Coordinates{}
+	if err := d.init(); err != nil {
+		return result, fmt.Errorf("failed to init .NET versioning: %w", err)
+	}
+	for _, name := range []string{"PackageId", "AssemblyName"} {
+		if match := msbuildPropertyRegex(name).FindStringSubmatch(d.content); match != nil {
+			result.ArtifactID = match[2]
+			break
+		}
+	}
+	if len(result.ArtifactID) == 0 && filepath.Base(d.path) != "Directory.Build.props" {
+		// the package id defaults to the name of the project file
+		result.ArtifactID = strings.TrimSuffix(filepath.Base(d.path), filepath.Ext(d.path))
+	}
+
+	var err error
+	result.Version, err = d.GetVersion()
+	if err != nil {
+		return result, fmt.Errorf("failed to retrieve coordinates: %w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,90 @@
+package versioning
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const csproj = `<Project Sdk="Microsoft.NET.Sdk.Web">
+  <PropertyGroup>
+    <TargetFramework>net6.0</TargetFramework>
+    <Version>1.2.3</Version>
+  </PropertyGroup>
+  <PropertyGroup Condition="'$(Configuration)' == 'Debug'">
+    <Version>0.0.0-dev</Version>
+  </PropertyGroup>
+</Project>
+`
+
+func TestDotNetInit(t *testing.T) {
+	t.Run("success case - Directory.Build.props", func(t *testing.T) {
+		fileUtils := newVersioningMockUtils()
+		fileUtils.AddFile("Service.csproj", []byte(csproj))
+		fileUtils.AddFile("Directory.Build.props", []byte("<Project><PropertyGroup><VersionPrefix>2.0.0</VersionPrefix></PropertyGroup></Project>"))
+
+		dotnet := DotNet{utils: fileUtils}
+		version, err := dotnet.GetVersion()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Directory.Build.props", dotnet.path)
+		assert.Equal(t, "2.0.0", version)
+	})
+
+	t.Run("success case - project file", func(t *testing.T) {
+		fileUtils := newVersioningMockUtils()
+		fileUtils.AddFile("Service.csproj", []byte(csproj))
+
+		dotnet := DotNet{utils: fileUtils}
+		version, err := dotnet.GetVersion()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "Service.csproj", dotnet.path)
+		assert.Equal(t, "1.2.3", version)
+	})
+
+	t.Run("error case - multiple project files", func(t *testing.T) {
+		fileUtils := newVersioningMockUtils()
+		fileUtils.AddFile("Service.csproj", []byte(csproj))
+		fileUtils.AddFile("Tool.fsproj", []byte(csproj))
+
+		dotnet := DotNet{utils: fileUtils}
+		_, err := dotnet.GetVersion()
+
+		assert.EqualError(t, err, "failed to init .NET versioning: multiple project files found [Service.csproj Tool.fsproj], please define the build descriptor to use")
+	})
+
+	t.Run("error case - no version", func(t *testing.T) {
+		fileUtils := newVersioningMockUtils()
+		fileUtils.AddFile("Service.csproj", []byte("<Project/>"))
+
+		dotnet := DotNet{utils: fileUtils}
+		_, err := dotnet.GetVersion()
+
+		assert.EqualError(t, err, "no <Version> or <VersionPrefix> property found in 'Service.csproj'")
+	})
+}
+
+func TestDotNetSetVersion(t *testing.T) {
+	fileUtils := newVersioningMockUtils()
+	fileUtils.AddFile("src/Service/Service.csproj", []byte(csproj))
+
+	dotnet := DotNet{path: "src/Service/Service.csproj", utils: fileUtils}
+	err := dotnet.SetVersion("1.2.3-20221001120000+abcdef")
+
+	assert.NoError(t, err)
+	content, _ := fileUtils.FileRead("src/Service/Service.csproj")
+	assert.Contains(t, string(content), "<Version>1.2.3-20221001120000+abcdef</Version>")
+	assert.Contains(t, string(content), "<Version>0.0.0-dev</Version>")
+}
+
+func TestDotNetGetCoordinates(t *testing.T) {
+	fileUtils := newVersioningMockUtils()
+	fileUtils.AddFile("Service.csproj", []byte(csproj))
+
+	dotnet := DotNet{utils: fileUtils}
+	coordinates, err := dotnet.GetCoordinates()
+
+	assert.NoError(t, err)
+	assert.Equal(t, Coordinates{ArtifactID: "Service", Version: "1.2.3"}, coordinates)
+}
@@ -1,28 +1,18 @@
 package versioning
 
 import (
-	"io"
 	"io/ioutil"
 	"os"
-	"regexp"
-	"strings"
+	"path/filepath"
 
-	"github.com/SAP/jenkins-library/pkg/command"
 	"github.com/SAP/jenkins-library/pkg/gradle"
 	"github.com/SAP/jenkins-library/pkg/log"
 )
 
-type gradleExecRunner interface {
-	Stdout(out io.Writer)
-	Stderr(err io.Writer)
-	RunExecutable(e string, p ...string) error
-}
-
 // Gradle defines a maven artifact used for versioning
 type Gradle struct {
-	execRunner     gradleExecRunner
-	utils          gradle.Utils
-	gradlePropsOut []byte
+	utils          gradle.ModelUtils
+	model          *gradle.Model
 	path           string
 	propertiesFile *PropertiesFile
 	versionField   string
@@ -61,21 +51,22 @@ func (g *Gradle) init() error {
 }
 
 func (g *Gradle) initGetArtifact() error {
-	if g.execRunner == nil {
-		g.execRunner = &command.Command{}
-	}
-
-	if g.gradlePropsOut == nil {
-		gradleOptions := &gradle.ExecuteOptions{
-			Task:       "properties",
-			UseWrapper: true,
+	if g.model == nil {
+		useWrapper, err := g.utils.FileExists("gradlew")
+		if err != nil {
+			return err
 		}
-		stdOut, err := gradle.Execute(gradleOptions, g.utils)
+		// the build script is located next to gradle.properties
+		buildGradlePath := filepath.Dir(g.path)
+		if buildGradlePath == "." {
+			buildGradlePath = ""
+		}
+		model, err := gradle.GetProjectModel(buildGradlePath, useWrapper, g.utils)
 		if err != nil {
-			log.Entry().WithError(err).Errorf("failed to retrieve properties of the gradle project: %v", err)
+			log.Entry().WithError(err).Errorf("failed to retrieve the project model of the gradle project: %v", err)
 			return err
 		}
-		g.gradlePropsOut = []byte(stdOut)
+		g.model = model
 	}
 	return nil
 }
@@ -85,7 +76,7 @@ func (g *Gradle) VersioningScheme() string {
 	return "semver2"
 }
 
-// GetCoordinates reads the coordinates from the maven pom.xml descriptor file
+// GetCoordinates returns the coordinates of the root project, the version is read from the properties file
 func (g *Gradle) GetCoordinates() (Coordinates, error) {
 	result := Coordinates{}
 	var err error
@@ -101,50 +92,53 @@ func (g *Gradle) GetCoordinates() (Coordinates, error) {
 	if err != nil {
 		return result, err
 	}
-	// result.Packaging, err = g.GetPackaging()
-	// if err != nil {
-	// 	return nil, err
-	// }
+	result.Packaging, err = g.GetPackaging()
+	if err != nil {
+		return result, err
+	}
 	return result, nil
 }
 
-// GetPackaging returns the current ID of the Group
-// func (g *Gradle) GetPackaging() (string, error) {
-// 	g.init()
-
-// 	packaging, err := g.runner.Evaluate(&g.options, "project.packaging", g.execRunner)
-// 	if err != nil {
-// 		return "", errors.Wrap(err, "Gradle - getting packaging failed")
-// 	}
-// 	return packaging, nil
-// }
+// GetPackaging returns the packaging of the root project
+func (g *Gradle) GetPackaging() (string, error) {
+	root, err := g.rootProject()
+	if err != nil {
+		return "", err
+	}
+	return root.Packaging, nil
+}
 
-// GetGroupID returns the current ID of the Group
+// GetGroupID returns the group of the root project
 func (g *Gradle) GetGroupID() (string, error) {
-	err := g.initGetArtifact()
+	root, err := g.rootProject()
 	if err != nil {
 		return "", err
 	}
-
-	regex := regexp.MustCompile(`(?m:^group: (.*))`)
-	match := string(regex.Find(g.gradlePropsOut))
-	groupID := strings.Split(match, ` `)[1]
-
-	return groupID, nil
+	return root.GroupID, nil
 }
 
-// GetArtifactID returns the current ID of the artifact
+// GetArtifactID returns the name of the root project, respectively the artifactId of its maven publication
 func (g *Gradle) GetArtifactID() (string, error) {
-	err := g.initGetArtifact()
+	root, err := g.rootProject()
 	if err != nil {
 		return "", err
 	}
+	return root.ArtifactID, nil
+}
 
-	regex := regexp.MustCompile(`(?m:^rootProject: root project '(.*)')`)
-	match := string(regex.Find(g.gradlePropsOut))
-	artifactID := strings.Split(match, `'`)[1]
+// GetProjects returns the coordinates of the root project and of all subprojects
+func (g *Gradle) GetProjects() ([]gradle.Project, error) {
+	if err := g.initGetArtifact(); err != nil {
+		return nil, err
+	}
+	return g.model.Projects, nil
+}
 
-	return artifactID, nil
+func (g *Gradle) rootProject() (gradle.Project, error) {
+	if err := g.initGetArtifact(); err != nil {
+		return gradle.Project{}, err
+	}
+	return g.model.RootProject()
 }
 
 // GetVersion returns the current version of the artifact
@@ -44,3 +44,44 @@ func TestGradleSetVersion(t *testing.T) {
 		assert.Contains(t, string(content), "version = 1.2.3")
 	})
 }
+
+func TestGradleGetCoordinates(t *testing.T) {
+	t.Run("success case - multi-project build", func(t *testing.T) {
+		utils := newVersioningMockUtils()
+		utils.AddFile("build.gradle", []byte{})
+		utils.AddFile("gradlew", []byte{})
+		utils.AddFile("piperProjectModel.json.tmp", []byte(`{"projects": [
+			{"path": ":", "name": "service", "dir": ".", "groupId": "com.example", "artifactId": "service", "version": "1.2.3", "packaging": "pom"},
+			{"path": ":api", "name": "api", "dir": "api", "groupId": "com.example", "artifactId": "service-api", "version": "1.2.3", "packaging": "jar"}
+		]}`))
+		gradle := &Gradle{path: "gradle.properties", utils: utils}
+
+		groupID, err := gradle.GetGroupID()
+		assert.NoError(t, err)
+		assert.Equal(t, "com.example", groupID)
+		artifactID, err := gradle.GetArtifactID()
+		assert.NoError(t, err)
+		assert.Equal(t, "service", artifactID)
+		packaging, err := gradle.GetPackaging()
+		assert.NoError(t, err)
+		assert.Equal(t, "pom", packaging)
+		projects, err := gradle.GetProjects()
+		assert.NoError(t, err)
+		if assert.Len(t, projects, 2) {
+			assert.Equal(t, "service-api", projects[1].ArtifactID)
+		}
+
+		// the model is only retrieved once
+		assert.Len(t, utils.Calls, 2)
+		assert.Equal(t, "./gradlew", utils.Calls[1].Exec)
+	})
+
+	t.Run("error case - build fails", func(t *testing.T) {
+		utils := newVersioningMockUtils()
+		gradle := &Gradle{path: "gradle.properties", utils: utils}
+
+		_, err := gradle.GetGroupID()
+
+		assert.EqualError(t, err, "failed to retrieve the project model of the gradle build: the specified gradle build script could not be found: no build script available, supported: [build.gradle build.gradle.kts]")
+	})
+}
@@ -18,6 +18,7 @@ type mavenExecRunner interface {
 type mavenRunner interface {
 	Execute(*maven.ExecuteOptions, maven.Utils) (string, error)
 	Evaluate(*maven.EvaluateOptions, string, maven.Utils) (string, error)
+	Analyze(*maven.AnalysisOptions, maven.Utils) (*maven.Analysis, error)
 }
 
 // Maven defines a maven artifact used for versioning
@@ -42,81 +43,93 @@ func (m *Maven) VersioningScheme() string {
 	return "maven"
 }
 
-// GetCoordinates reads the coordinates from the maven pom.xml descriptor file
+// GetCoordinates reads the coordinates from the effective POM of the maven pom.xml descriptor file
 func (m *Maven) GetCoordinates() (Coordinates, error) {
-	result := Coordinates{}
-	var err error
-	result.GroupID, err = m.GetGroupID()
+	project, err := m.analyzeProject()
 	if err != nil {
-		return result, err
+		return Coordinates{}, errors.Wrap(err, "Maven - getting coordinates failed")
 	}
-	result.ArtifactID, err = m.GetArtifactID()
+	return Coordinates{
+		GroupID:    project.GroupID,
+		ArtifactID: project.ArtifactID,
+		Version:    project.Version,
+		Packaging:  packaging(project),
+	}, nil
+}
+
+// analyzeProject returns the effective POM of the descriptor file. The analysis is cached by package maven,
+// i.e. Maven is only called once for all coordinates of the project.
+func (m *Maven) analyzeProject() (*maven.Project, error) {
+	m.init()
+
+	analysis, err := m.runner.Analyze(&maven.AnalysisOptions{
+		PomPath:             m.options.PomPath,
+		ProjectSettingsFile: m.options.ProjectSettingsFile,
+		GlobalSettingsFile:  m.options.GlobalSettingsFile,
+		M2Path:              m.options.M2Path,
+		Defines:             m.options.Defines,
+		NonRecursive:        true,
+	}, m.utils)
 	if err != nil {
-		return result, err
+		return nil, err
 	}
-	result.Version, err = m.GetVersion()
+	module, err := analysis.RootModule()
 	if err != nil {
-		return result, err
+		return nil, err
 	}
-	result.Packaging, err = m.GetPackaging()
-	if err != nil {
-		return result, err
+	return module.Project, nil
+}
+
+func packaging(project *maven.Project) string {
+	if len(project.Packaging) == 0 {
+		// the effective POM omits the default packaging
+		return "jar"
 	}
-	return result, nil
+	return project.Packaging
 }
 
-// GetPackaging returns the current ID of the Group
+// GetPackaging returns the packaging of the artifact
 func (m *Maven) GetPackaging() (string, error) {
-	m.init()
-
-	packaging, err := m.runner.Evaluate(&m.options, "project.packaging", m.utils)
+	project, err := m.analyzeProject()
 	if err != nil {
 		return "", errors.Wrap(err, "Maven - getting packaging failed")
 	}
-	return packaging, nil
+	return packaging(project), nil
 }
 
 // GetGroupID returns the current ID of the Group
 func (m *Maven) GetGroupID() (string, error) {
-	m.init()
-
-	groupID, err := m.runner.Evaluate(&m.options, "project.groupId", m.utils)
+	project, err := m.analyzeProject()
 	if err != nil {
 		return "", errors.Wrap(err, "Maven - getting groupId failed")
 	}
-	return groupID, nil
+	return project.GroupID, nil
 }
 
 // GetArtifactID returns the current ID of the artifact
 func (m *Maven) GetArtifactID() (string, error) {
-	m.init()
-
-	artifactID, err := m.runner.Evaluate(&m.options, "project.artifactId", m.utils)
+	project, err := m.analyzeProject()
 	if err != nil {
 		return "", errors.Wrap(err, "Maven - getting artifactId failed")
 	}
-	return artifactID, nil
+	return project.ArtifactID, nil
 }
 
 // GetVersion returns the current version of the artifact
 func (m *Maven) GetVersion() (string, error) {
-	m.init()
-
-	version, err := m.runner.Evaluate(&m.options, "project.version", m.utils)
+	project, err := m.analyzeProject()
 	if err != nil {
 		return "", errors.Wrap(err, "Maven - getting version failed")
 	}
 	//ToDo: how to deal with SNAPSHOT replacement?
-	return version, nil
+	return project.Version, nil
 }
 
 // SetVersion updates the version of the artifact
 func (m *Maven) SetVersion(version string) error {
-	m.init()
-
-	groupID, err := m.runner.Evaluate(&m.options, "project.groupId", m.utils)
+	groupID, err := m.GetGroupID()
 	if err != nil {
-		return errors.Wrap(err, "Maven - getting groupId failed")
+		return err
 	}
 	opts := maven.ExecuteOptions{
 		PomPath:             m.options.PomPath,
@@ -15,6 +15,8 @@ type mavenMockRunner struct {
 	opts                *maven.EvaluateOptions
 	execOpts            *maven.ExecuteOptions
 	expression          string
+	analysis            *maven.Analysis
+	analysisOpts        *maven.AnalysisOptions
 }
 
 func (m *mavenMockRunner) Evaluate(opts *maven.EvaluateOptions, expression string, utils maven.Utils) (string, error) {
@@ -37,10 +39,51 @@ func (m *mavenMockRunner) Execute(opts *maven.ExecuteOptions, utils maven.Utils)
 	return "", nil
 }
 
+func (m *mavenMockRunner) Analyze(opts *maven.AnalysisOptions, utils maven.Utils) (*maven.Analysis, error) {
+	m.analysisOpts = opts
+	if len(m.evaluateErrorString) > 0 {
+		return nil, fmt.Errorf(m.evaluateErrorString)
+	}
+	return m.analysis, nil
+}
+
+func TestMavenGetCoordinates(t *testing.T) {
+	t.Run("success case", func(t *testing.T) {
+		runner := mavenMockRunner{
+			analysis: &maven.Analysis{Root: "path/to", Modules: []maven.AnalyzedModule{
+				{Path: "path/to", Project: &maven.Project{GroupID: "com.example", ArtifactID: "app", Version: "1.2.3"}},
+			}},
+		}
+		mvn := &Maven{
+			runner:  &runner,
+			options: maven.EvaluateOptions{PomPath: "path/to/pom.xml", M2Path: "path/to/m2", Defines: []string{"-Pci"}},
+		}
+		coordinates, err := mvn.GetCoordinates()
+		assert.NoError(t, err)
+		assert.Equal(t, Coordinates{GroupID: "com.example", ArtifactID: "app", Version: "1.2.3", Packaging: "jar"}, coordinates)
+		assert.Equal(t, &maven.AnalysisOptions{PomPath: "path/to/pom.xml", M2Path: "path/to/m2", Defines: []string{"-Pci"}, NonRecursive: true}, runner.analysisOpts)
+	})
+
+	t.Run("error case", func(t *testing.T) {
+		runner := mavenMockRunner{
+			evaluateErrorString: "maven analysis failed",
+		}
+		mvn := &Maven{
+			runner: &runner,
+		}
+		_, err := mvn.GetCoordinates()
+		assert.EqualError(t, err, "Maven - getting coordinates failed: maven analysis failed")
+	})
+}
+
+func rootAnalysis(project maven.Project) *maven.Analysis {
+	return &maven.Analysis{Root: "path/to", Modules: []maven.AnalyzedModule{{Path: "path/to", Project: &project}}}
+}
+
 func TestMavenGetVersion(t *testing.T) {
 	t.Run("success case", func(t *testing.T) {
 		runner := mavenMockRunner{
-			stdout: "1.2.3",
+			analysis: rootAnalysis(maven.Project{GroupID: "com.example", ArtifactID: "app", Version: "1.2.3"}),
 		}
 		mvn := &Maven{
 			runner:  &runner,
@@ -49,30 +92,47 @@ func TestMavenGetVersion(t *testing.T) {
 		version, err := mvn.GetVersion()
 		assert.NoError(t, err)
 		assert.Equal(t, "1.2.3", version)
-		assert.Equal(t, "project.version", runner.expression)
-		assert.Equal(t, "path/to/pom.xml", runner.opts.PomPath)
-		assert.Equal(t, "path/to/m2", runner.opts.M2Path)
+		assert.Equal(t, "path/to/pom.xml", runner.analysisOpts.PomPath)
+		assert.Equal(t, "path/to/m2", runner.analysisOpts.M2Path)
 	})
 
 	t.Run("error case", func(t *testing.T) {
 		runner := mavenMockRunner{
-			stdout:              "1.2.3",
-			evaluateErrorString: "maven eval failed",
+			evaluateErrorString: "maven analysis failed",
 		}
 		mvn := &Maven{
 			runner: &runner,
 		}
 		version, err := mvn.GetVersion()
-		assert.EqualError(t, err, "Maven - getting version failed: maven eval failed")
+		assert.EqualError(t, err, "Maven - getting version failed: maven analysis failed")
 		assert.Equal(t, "", version)
 	})
 
 }
 
+func TestMavenGetProjectAttributes(t *testing.T) {
+	runner := mavenMockRunner{
+		analysis: rootAnalysis(maven.Project{GroupID: "com.example", ArtifactID: "app", Version: "1.2.3", Packaging: "war"}),
+	}
+	mvn := &Maven{runner: &runner}
+
+	groupID, err := mvn.GetGroupID()
+	assert.NoError(t, err)
+	assert.Equal(t, "com.example", groupID)
+	artifactID, err := mvn.GetArtifactID()
+	assert.NoError(t, err)
+	assert.Equal(t, "app", artifactID)
+	packaging, err := mvn.GetPackaging()
+	assert.NoError(t, err)
+	assert.Equal(t, "war", packaging)
+	assert.Equal(t, "pom.xml", runner.analysisOpts.PomPath)
+	assert.True(t, runner.analysisOpts.NonRecursive)
+}
+
 func TestMavenSetVersion(t *testing.T) {
 	t.Run("success case", func(t *testing.T) {
 		runner := mavenMockRunner{
-			stdout: "testGroup",
+			analysis: rootAnalysis(maven.Project{GroupID: "testGroup", ArtifactID: "app", Version: "1.2.3"}),
 		}
 		mvn := &Maven{
 			runner: &runner,
@@ -95,22 +155,21 @@ func TestMavenSetVersion(t *testing.T) {
 		assert.Equal(t, &expectedOptions, runner.execOpts)
 	})
 
-	t.Run("evaluate error", func(t *testing.T) {
+	t.Run("analysis error", func(t *testing.T) {
 		runner := mavenMockRunner{
-			stdout:              "testGroup",
-			evaluateErrorString: "maven eval failed",
+			evaluateErrorString: "maven analysis failed",
 		}
 		mvn := &Maven{
 			runner:  &runner,
 			options: maven.EvaluateOptions{PomPath: "path/to/pom.xml"},
 		}
 		err := mvn.SetVersion("1.2.4")
-		assert.EqualError(t, err, "Maven - getting groupId failed: maven eval failed")
+		assert.EqualError(t, err, "Maven - getting groupId failed: maven analysis failed")
 	})
 
 	t.Run("execute error", func(t *testing.T) {
 		runner := mavenMockRunner{
-			stdout:             "testGroup",
+			analysis:           rootAnalysis(maven.Project{GroupID: "testGroup", ArtifactID: "app", Version: "1.2.3"}),
 			executeErrorString: "maven exec failed",
 		}
 		mvn := &Maven{
@@ -0,0 +1,180 @@
+package versioning
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pelletier/go-toml"
+	"github.com/pkg/errors"
+)
+
+var (
+	tomlTableRegex      = regexp.MustCompile(`^\s*\[\s*([^\[\]]+?)\s*\]\s*(#.*)?$`)
+	tomlVersionRegex    = regexp.MustCompile(`^(\s*version\s*=\s*)(["'])[^"']*(["'].*)$`)
+	pythonVersionRegex  = regexp.MustCompile(`(?m)^(__version__\s*=\s*)(["'])([^"']*)(["'])`)
+	pyprojectVersionKey = map[string]string{"project": "project.version", "tool.poetry": "tool.poetry.version"}
+)
+
+// Pyproject utility to interact with the version of Python projects described by a pyproject.toml (PEP 621),
+// including projects managed by Poetry, Hatch or PDM
+type Pyproject struct {
+	path      string
+	readFile  func(string) ([]byte, error)
+	writeFile func(string, []byte, os.FileMode) error
+	content   string
+	tree      *toml.Tree
+}
+
+func (p *Pyproject) init() error {
+	if p.readFile == nil {
+		p.readFile = ioutil.ReadFile
+	}
+
+	if p.writeFile == nil {
+		p.writeFile = ioutil.WriteFile
+	}
+
+	if p.tree == nil {
+		content, err := p.readFile(p.path)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read file '%v'", p.path)
+		}
+		tree, err := toml.LoadBytes(content)
+		if err != nil {
+			return errors.Wrapf(err, "failed to parse file '%v'", p.path)
+		}
+		p.content = string(content)
+		p.tree = tree
+	}
+	return nil
+}
+
+// versionLocation returns the table of the pyproject.toml which contains the version or, for a dynamic version,
+// the Python file which contains the version as __version__ attribute
+func (p *Pyproject) versionLocation() (table string, versionFile string, err error) {
+	for _, table := range []string{"project", "tool.poetry"} {
+		if _, ok := p.tree.Get(pyprojectVersionKey[table]).(string); ok {
+			return table, "", nil
+		}
+	}
+	for _, key := range []string{"tool.hatch.version.path", "tool.pdm.version.path"} {
+		if path, ok := p.tree.Get(key).(string); ok && len(path) > 0 {
+			return "", filepath.Join(filepath.Dir(p.path), path), nil
+		}
+	}
+	return "", "", fmt.Errorf("no version found in '%v', supported are 'project.version', 'tool.poetry.version' as well as 'tool.hatch.version.path' and 'tool.pdm.version.path' for a dynamic version", p.path)
+}
+
+// GetVersion returns the version of the Python project
+func (p *Pyproject) GetVersion() (string, error) {
+	if err := p.init(); err != nil {
+		return "", err
+	}
+	table, versionFile, err := p.versionLocation()
+	if err != nil {
+		return "", err
+	}
+	if len(versionFile) == 0 {
+		return p.tree.Get(pyprojectVersionKey[table]).(string), nil
+	}
+
+	content, err := p.readFile(versionFile)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to read file '%v'", versionFile)
+	}
+	match := pythonVersionRegex.FindStringSubmatch(string(content))
+	if match == nil {
+		return "", fmt.Errorf("no __version__ attribute found in '%v'", versionFile)
+	}
+	return match[3], nil
+}
+
+// SetVersion updates the version of the Python project, the formatting of the files is preserved
+func (p *Pyproject) SetVersion(version string) error {
+	if err := p.init(); err != nil {
+		return err
+	}
+	table, versionFile, err := p.versionLocation()
+	if err != nil {
+		return err
+	}
+	if len(versionFile) == 0 {
+		content, replaced := setTomlVersion(p.content, table, version)
+		if !replaced {
+			return fmt.Errorf("failed to update version in table '%v' of '%v'", table, p.path)
+		}
+		if err := p.writeFile(p.path, []byte(content), 0666); err != nil {
+			return errors.Wrapf(err, "failed to write file '%v'", p.path)
+		}
+		p.content = content
+		p.tree = nil
+		return nil
+	}
+
+	content, err := p.readFile(versionFile)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read file '%v'", versionFile)
+	}
+	if !pythonVersionRegex.Match(content) {
+		return fmt.Errorf("no __version__ attribute found in '%v'", versionFile)
+	}
+	content = pythonVersionRegex.ReplaceAll(content, []byte("${1}${2}"+version+"${4}"))
+	if err := p.writeFile(versionFile, content, 0666); err != nil {
+		return errors.Wrapf(err, "failed to write file '%v'", versionFile)
+	}
+	return nil
+}
+
+// setTomlVersion replaces the value of the key version within the table
+func setTomlVersion(content, table, version string) (string, bool) {
+	lines := strings.Split(content, "\n")
+	currentTable := ""
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "[") {
+			currentTable = ""
+			if match := tomlTableRegex.FindStringSubmatch(line); match != nil {
+				currentTable = match[1]
+			}
+			continue
+		}
+		if currentTable != table {
+			continue
+		}
+		if match := tomlVersionRegex.FindStringSubmatch(line); match != nil {
+			lines[i] = match[1] + match[2] + version + match[3]
+			return strings.Join(lines, "\n"), true
+		}
+	}
+	return content, false
+}
+
+// VersioningScheme returns the relevant versioning scheme
+func (p *Pyproject) VersioningScheme() string {
+	return "pep440"
+}
+
+// GetCoordinates returns the coordinates of the Python project
+func (p *Pyproject) GetCoordinates() (Coordinates, error) {
+	result := Coordinates{}
+	if err := p.init(); err != nil {
+		return result, err
+	}
+
+	for _, key := range []string{"project.name", "tool.poetry.name"} {
+		if name, ok := p.tree.Get(key).(string); ok {
+			result.ArtifactID = name
+			break
+		}
+	}
+
+	var err error
+	result.Version, err = p.GetVersion()
+	if err != nil {
+		return result, errors.Wrap(err, "failed to retrieve coordinates")
+	}
+	return result, nil
+}
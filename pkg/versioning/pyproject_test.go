@@ -0,0 +1,157 @@
+package versioning
+
+import (
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+)
+
+const pep621Pyproject = `[build-system]
+requires = ["setuptools>=61.0"]
+build-backend = "setuptools.build_meta"
+
+[project]
+name = "simple-python"
+# the version is maintained by the pipeline
+version = "1.2.3"  # comment
+dependencies = ["requests"]
+
+[tool.black]
+target-version = ["py39"]
+version = "unrelated"
+`
+
+const poetryPyproject = `[tool.poetry]
+name = "poetry-python"
+version = '0.4.0'
+description = ""
+
+[tool.poetry.dependencies]
+python = "^3.9"
+
+[build-system]
+requires = ["poetry-core"]
+build-backend = "poetry.core.masonry.api"
+`
+
+const hatchPyproject = `[project]
+name = "hatch-python"
+dynamic = ["version"]
+
+[tool.hatch.version]
+path = "src/hatch_python/__about__.py"
+`
+
+func newPyproject(fileUtils *mock.FilesMock, path string) *Pyproject {
+	return &Pyproject{
+		path:      path,
+		readFile:  fileUtils.FileRead,
+		writeFile: fileUtils.FileWrite,
+	}
+}
+
+func TestPyprojectGetVersion(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success case - PEP 621", func(t *testing.T) {
+		fileUtils := mock.FilesMock{}
+		fileUtils.AddFile("pyproject.toml", []byte(pep621Pyproject))
+
+		version, err := newPyproject(&fileUtils, "pyproject.toml").GetVersion()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "1.2.3", version)
+	})
+
+	t.Run("success case - Poetry", func(t *testing.T) {
+		fileUtils := mock.FilesMock{}
+		fileUtils.AddFile("pyproject.toml", []byte(poetryPyproject))
+
+		version, err := newPyproject(&fileUtils, "pyproject.toml").GetVersion()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "0.4.0", version)
+	})
+
+	t.Run("success case - Hatch dynamic version", func(t *testing.T) {
+		fileUtils := mock.FilesMock{}
+		fileUtils.AddFile("app/pyproject.toml", []byte(hatchPyproject))
+		fileUtils.AddFile("app/src/hatch_python/__about__.py", []byte("__version__ = \"2.0.1\"\n"))
+
+		version, err := newPyproject(&fileUtils, "app/pyproject.toml").GetVersion()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "2.0.1", version)
+	})
+
+	t.Run("error case - no version", func(t *testing.T) {
+		fileUtils := mock.FilesMock{}
+		fileUtils.AddFile("pyproject.toml", []byte("[project]\nname = \"no-version\"\n"))
+
+		_, err := newPyproject(&fileUtils, "pyproject.toml").GetVersion()
+
+		assert.EqualError(t, err, "no version found in 'pyproject.toml', supported are 'project.version', 'tool.poetry.version' as well as 'tool.hatch.version.path' and 'tool.pdm.version.path' for a dynamic version")
+	})
+
+	t.Run("error case - invalid file", func(t *testing.T) {
+		fileUtils := mock.FilesMock{}
+		fileUtils.AddFile("pyproject.toml", []byte("[project"))
+
+		_, err := newPyproject(&fileUtils, "pyproject.toml").GetVersion()
+
+		assert.Contains(t, err.Error(), "failed to parse file 'pyproject.toml'")
+	})
+}
+
+func TestPyprojectSetVersion(t *testing.T) {
+	t.Parallel()
+
+	t.Run("success case - PEP 621", func(t *testing.T) {
+		fileUtils := mock.FilesMock{}
+		fileUtils.AddFile("pyproject.toml", []byte(pep621Pyproject))
+		pyproject := newPyproject(&fileUtils, "pyproject.toml")
+
+		err := pyproject.SetVersion("1.3.0")
+
+		assert.NoError(t, err)
+		content, _ := fileUtils.FileRead("pyproject.toml")
+		assert.Contains(t, string(content), "\nversion = \"1.3.0\"  # comment\n")
+		assert.Contains(t, string(content), "\nversion = \"unrelated\"\n")
+		version, _ := pyproject.GetVersion()
+		assert.Equal(t, "1.3.0", version)
+	})
+
+	t.Run("success case - Poetry", func(t *testing.T) {
+		fileUtils := mock.FilesMock{}
+		fileUtils.AddFile("pyproject.toml", []byte(poetryPyproject))
+
+		err := newPyproject(&fileUtils, "pyproject.toml").SetVersion("0.5.0")
+
+		assert.NoError(t, err)
+		content, _ := fileUtils.FileRead("pyproject.toml")
+		assert.Contains(t, string(content), "\nversion = '0.5.0'\n")
+	})
+
+	t.Run("success case - Hatch dynamic version", func(t *testing.T) {
+		fileUtils := mock.FilesMock{}
+		fileUtils.AddFile("pyproject.toml", []byte(hatchPyproject))
+		fileUtils.AddFile("src/hatch_python/__about__.py", []byte("# about\n__version__ = '2.0.1'\n"))
+
+		err := newPyproject(&fileUtils, "pyproject.toml").SetVersion("2.1.0")
+
+		assert.NoError(t, err)
+		content, _ := fileUtils.FileRead("src/hatch_python/__about__.py")
+		assert.Equal(t, "# about\n__version__ = '2.1.0'\n", string(content))
+	})
+}
+
+func TestPyprojectGetCoordinates(t *testing.T) {
+	fileUtils := mock.FilesMock{}
+	fileUtils.AddFile("pyproject.toml", []byte(poetryPyproject))
+
+	coordinates, err := newPyproject(&fileUtils, "pyproject.toml").GetCoordinates()
+
+	assert.NoError(t, err)
+	assert.Equal(t, Coordinates{ArtifactID: "poetry-python", Version: "0.4.0"}, coordinates)
+}
@@ -0,0 +1,200 @@
+package versioning
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/pkg/errors"
+)
+
+// ReleaseType describes which part of a semantic version is incremented by a release
+type ReleaseType int
+
+const (
+	// ReleaseNone means that the changes do not require a new release
+	ReleaseNone ReleaseType = iota
+	// ReleasePatch increments the patch version
+	ReleasePatch
+	// ReleaseMinor increments the minor version
+	ReleaseMinor
+	// ReleaseMajor increments the major version
+	ReleaseMajor
+)
+
+// ConventionalCommit is a commit whose message follows the Conventional Commits specification, see https://www.conventionalcommits.org
+type ConventionalCommit struct {
+	Hash        string `json:"hash"`
+	Type        string `json:"type"`
+	Scope       string `json:"scope,omitempty"`
+	Description string `json:"description"`
+	Breaking    bool   `json:"breaking,omitempty"`
+}
+
+var conventionalCommitHeader = regexp.MustCompile(`^([a-zA-Z]+)(?:\(([^()]*)\))?(!)?: (.+)$`)
+
+// ParseConventionalCommit parses the commit message. It returns false if the message does not follow the Conventional Commits specification.
+func ParseConventionalCommit(hash, message string) (ConventionalCommit, bool) {
+	lines := strings.Split(strings.TrimSpace(message), "\n")
+	match := conventionalCommitHeader.FindStringSubmatch(strings.TrimSpace(lines[0]))
+	if match == nil {
+		return ConventionalCommit{}, false
+	}
+	commit := ConventionalCommit{
+		Hash:        hash,
+		Type:        strings.ToLower(match[1]),
+		Scope:       strings.TrimSpace(match[2]),
+		Description: strings.TrimSpace(match[4]),
+		Breaking:    match[3] == "!",
+	}
+	for _, line := range lines[1:] {
+		if strings.HasPrefix(line, "BREAKING CHANGE:") || strings.HasPrefix(line, "BREAKING-CHANGE:") {
+			commit.Breaking = true
+		}
+	}
+	return commit, true
+}
+
+// ReleaseType returns the release which is required for the commit
+func (c ConventionalCommit) ReleaseType() ReleaseType {
+	switch {
+	case c.Breaking:
+		return ReleaseMajor
+	case c.Type == "feat":
+		return ReleaseMinor
+	case c.Type == "fix" || c.Type == "perf":
+		return ReleasePatch
+	}
+	return ReleaseNone
+}
+
+// Changelog contains the release relevant commits of a new version grouped by their kind
+type Changelog struct {
+	BreakingChanges []ConventionalCommit `json:"breakingChanges,omitempty"`
+	Features        []ConventionalCommit `json:"features,omitempty"`
+	BugFixes        []ConventionalCommit `json:"bugFixes,omitempty"`
+	Performance     []ConventionalCommit `json:"performanceImprovements,omitempty"`
+}
+
+// NewChangelog creates the changelog from the commits, which do not follow the Conventional Commits specification or which are
+// not relevant for a release, e.g. of type "docs" or "chore", are not contained.
+func NewChangelog(commits []ConventionalCommit) Changelog {
+	changelog := Changelog{}
+	for _, commit := range commits {
+		if commit.Breaking {
+			changelog.BreakingChanges = append(changelog.BreakingChanges, commit)
+		}
+		switch commit.Type {
+		case "feat":
+			changelog.Features = append(changelog.Features, commit)
+		case "fix":
+			changelog.BugFixes = append(changelog.BugFixes, commit)
+		case "perf":
+			changelog.Performance = append(changelog.Performance, commit)
+		}
+	}
+	return changelog
+}
+
+// ReleaseType returns the release which is required for the changes contained in the changelog
+func (c Changelog) ReleaseType() ReleaseType {
+	switch {
+	case len(c.BreakingChanges) > 0:
+		return ReleaseMajor
+	case len(c.Features) > 0:
+		return ReleaseMinor
+	case len(c.BugFixes) > 0 || len(c.Performance) > 0:
+		return ReleasePatch
+	}
+	return ReleaseNone
+}
+
+// Markdown renders the changelog, e.g. for the body of a GitHub release
+func (c Changelog) Markdown() string {
+	var sb strings.Builder
+	sections := []struct {
+		title   string
+		commits []ConventionalCommit
+	}{
+		{title: "Breaking Changes", commits: c.BreakingChanges},
+		{title: "Features", commits: c.Features},
+		{title: "Bug Fixes", commits: c.BugFixes},
+		{title: "Performance Improvements", commits: c.Performance},
+	}
+	for _, section := range sections {
+		if len(section.commits) == 0 {
+			continue
+		}
+		if sb.Len() > 0 {
+			sb.WriteString("\n")
+		}
+		sb.WriteString(fmt.Sprintf("### %v\n\n", section.title))
+		for _, commit := range section.commits {
+			sb.WriteString("* ")
+			if len(commit.Scope) > 0 {
+				sb.WriteString(fmt.Sprintf("**%v:** ", commit.Scope))
+			}
+			sb.WriteString(commit.Description)
+			if len(commit.Hash) >= 7 {
+				sb.WriteString(fmt.Sprintf(" (%v)", commit.Hash[:7]))
+			}
+			sb.WriteString("\n")
+		}
+	}
+	return sb.String()
+}
+
+// LatestRelease returns the highest version without pre-release identifier as well as the corresponding entry of the list.
+// Entries which are no semantic versions are ignored. It returns false if the list does not contain any release.
+func LatestRelease(versions []string, prefix string) (*semver.Version, string, bool) {
+	var latest *semver.Version
+	latestEntry := ""
+	for _, entry := range versions {
+		version, err := semver.StrictNewVersion(strings.TrimPrefix(entry, prefix))
+		if err != nil || len(version.Prerelease()) > 0 {
+			continue
+		}
+		if latest == nil || version.GreaterThan(latest) {
+			latest = version
+			latestEntry = entry
+		}
+	}
+	return latest, latestEntry, latest != nil
+}
+
+// NextSemanticVersion increments the latest release according to the release type. For a pre-release channel the version
+// receives the identifier "<channel>.<n>" where n is one higher than for the existing pre-releases of that version.
+func NextSemanticVersion(latest *semver.Version, releaseType ReleaseType, channel string, versions []string, prefix string) (string, error) {
+	var next semver.Version
+	switch releaseType {
+	case ReleaseMajor:
+		next = latest.IncMajor()
+	case ReleaseMinor:
+		next = latest.IncMinor()
+	case ReleasePatch:
+		next = latest.IncPatch()
+	default:
+		next = *latest
+	}
+	if len(channel) == 0 {
+		return next.String(), nil
+	}
+
+	preReleasePrefix := fmt.Sprintf("%v-%v.", next.String(), channel)
+	numbers := []int{0}
+	for _, entry := range versions {
+		number, err := strconv.Atoi(strings.TrimPrefix(strings.TrimPrefix(entry, prefix), preReleasePrefix))
+		if err == nil && strings.HasPrefix(strings.TrimPrefix(entry, prefix), preReleasePrefix) {
+			numbers = append(numbers, number)
+		}
+	}
+	sort.Ints(numbers)
+	version := fmt.Sprintf("%v%v", preReleasePrefix, numbers[len(numbers)-1]+1)
+	if _, err := semver.StrictNewVersion(version); err != nil {
+		return "", errors.Wrapf(err, "pre-release channel '%v' results in invalid version '%v'", channel, version)
+	}
+	return version, nil
+}
@@ -0,0 +1,107 @@
+package versioning
+
+import (
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseConventionalCommit(t *testing.T) {
+	tt := []struct {
+		message      string
+		expected     ConventionalCommit
+		conventional bool
+		releaseType  ReleaseType
+	}{
+		{message: "feat: add login", expected: ConventionalCommit{Type: "feat", Description: "add login"}, conventional: true, releaseType: ReleaseMinor},
+		{message: "fix(parser): handle empty input\n\nsome details", expected: ConventionalCommit{Type: "fix", Scope: "parser", Description: "handle empty input"}, conventional: true, releaseType: ReleasePatch},
+		{message: "perf: cache lookups", expected: ConventionalCommit{Type: "perf", Description: "cache lookups"}, conventional: true, releaseType: ReleasePatch},
+		{message: "refactor(api)!: drop v1 endpoints", expected: ConventionalCommit{Type: "refactor", Scope: "api", Description: "drop v1 endpoints", Breaking: true}, conventional: true, releaseType: ReleaseMajor},
+		{message: "Feat: remove option\n\nBREAKING CHANGE: option x is gone", expected: ConventionalCommit{Type: "feat", Description: "remove option", Breaking: true}, conventional: true, releaseType: ReleaseMajor},
+		{message: "docs: describe caching", expected: ConventionalCommit{Type: "docs", Description: "describe caching"}, conventional: true, releaseType: ReleaseNone},
+		{message: "Merge pull request #42 from feature", conventional: false},
+		{message: "fix:missing space", conventional: false},
+	}
+	for _, test := range tt {
+		t.Run(test.message, func(t *testing.T) {
+			commit, conventional := ParseConventionalCommit("", test.message)
+			assert.Equal(t, test.conventional, conventional)
+			assert.Equal(t, test.expected, commit)
+			assert.Equal(t, test.releaseType, commit.ReleaseType())
+		})
+	}
+}
+
+func TestChangelog(t *testing.T) {
+	t.Run("features and fixes", func(t *testing.T) {
+		changelog := NewChangelog([]ConventionalCommit{
+			{Hash: "0123456789abcdef", Type: "feat", Scope: "ui", Description: "dark mode"},
+			{Hash: "fedcba9876543210", Type: "fix", Description: "crash on start", Breaking: true},
+			{Hash: "0011223344556677", Type: "chore", Description: "update dependencies"},
+		})
+
+		assert.Equal(t, ReleaseMajor, changelog.ReleaseType())
+		assert.Equal(t, `### Breaking Changes
+
+* crash on start (fedcba9)
+
+### Features
+
+* **ui:** dark mode (0123456)
+
+### Bug Fixes
+
+* crash on start (fedcba9)
+`, changelog.Markdown())
+	})
+
+	t.Run("no relevant changes", func(t *testing.T) {
+		changelog := NewChangelog([]ConventionalCommit{{Type: "docs", Description: "typo"}})
+
+		assert.Equal(t, ReleaseNone, changelog.ReleaseType())
+		assert.Empty(t, changelog.Markdown())
+	})
+}
+
+func TestLatestRelease(t *testing.T) {
+	latest, tag, found := LatestRelease([]string{"v1.2.0", "v1.10.0", "v2.0.0-beta.1", "v1.9.9", "vnext", "v1.3"}, "v")
+	assert.True(t, found)
+	assert.Equal(t, "1.10.0", latest.String())
+	assert.Equal(t, "v1.10.0", tag)
+
+	_, _, found = LatestRelease([]string{"v2.0.0-beta.1"}, "v")
+	assert.False(t, found)
+}
+
+func TestNextSemanticVersion(t *testing.T) {
+	latest := semver.MustParse("1.2.3")
+	existing := []string{"v1.2.3", "v1.3.0-beta.1", "v1.3.0-beta.2", "v1.3.0-alpha.7"}
+
+	tt := []struct {
+		name        string
+		releaseType ReleaseType
+		channel     string
+		expected    string
+		err         string
+	}{
+		{name: "major", releaseType: ReleaseMajor, expected: "2.0.0"},
+		{name: "minor", releaseType: ReleaseMinor, expected: "1.3.0"},
+		{name: "patch", releaseType: ReleasePatch, expected: "1.2.4"},
+		{name: "none", releaseType: ReleaseNone, expected: "1.2.3"},
+		{name: "next pre-release", releaseType: ReleaseMinor, channel: "beta", expected: "1.3.0-beta.3"},
+		{name: "first pre-release", releaseType: ReleaseMajor, channel: "beta", expected: "2.0.0-beta.1"},
+		{name: "invalid channel", releaseType: ReleasePatch, channel: "feature_x", err: "pre-release channel 'feature_x' results in invalid version '1.2.4-feature_x.1'"},
+	}
+	for _, test := range tt {
+		t.Run(test.name, func(t *testing.T) {
+			version, err := NextSemanticVersion(latest, test.releaseType, test.channel, existing, "v")
+			if len(test.err) > 0 {
+				assert.Contains(t, err.Error(), test.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, version)
+		})
+	}
+}
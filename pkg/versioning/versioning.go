@@ -66,6 +66,9 @@ func (m *mvnRunner) Execute(options *maven.ExecuteOptions, utils maven.Utils) (s
 func (m *mvnRunner) Evaluate(options *maven.EvaluateOptions, expression string, utils maven.Utils) (string, error) {
 	return maven.Evaluate(options, expression, utils)
 }
+func (m *mvnRunner) Analyze(options *maven.AnalysisOptions, utils maven.Utils) (*maven.Analysis, error) {
+	return maven.Analyze(options, utils)
+}
 
 var fileExists func(string) (bool, error)
 
@@ -76,6 +79,11 @@ func GetArtifact(buildTool, buildDescriptorFilePath string, opts *Options, utils
 		fileExists = piperutils.FileExists
 	}
 	switch buildTool {
+	case "cargo":
+		artifact = &Cargo{
+			path:  buildDescriptorFilePath,
+			utils: utils,
+		}
 	case "custom":
 		var err error
 		artifact, err = customArtifact(buildDescriptorFilePath, opts.VersionField, opts.VersionSection, opts.VersioningScheme)
@@ -90,6 +98,11 @@ func GetArtifact(buildTool, buildDescriptorFilePath string, opts *Options, utils
 			versionSource:    opts.VersionSource,
 			versioningScheme: opts.VersioningScheme,
 		}
+	case "dotnet":
+		artifact = &DotNet{
+			path:  buildDescriptorFilePath,
+			utils: utils,
+		}
 	case "dub":
 		if len(buildDescriptorFilePath) == 0 {
 			buildDescriptorFilePath = "dub.json"
@@ -164,14 +177,20 @@ func GetArtifact(buildTool, buildDescriptorFilePath string, opts *Options, utils
 	case "pip":
 		if len(buildDescriptorFilePath) == 0 {
 			var err error
-			buildDescriptorFilePath, err = searchDescriptor([]string{"setup.py", "version.txt", "VERSION"}, fileExists)
+			buildDescriptorFilePath, err = searchDescriptor([]string{"setup.py", "version.txt", "VERSION", "pyproject.toml"}, fileExists)
 			if err != nil {
 				return artifact, err
 			}
 		}
-		artifact = &Pip{
-			path:       buildDescriptorFilePath,
-			fileExists: fileExists,
+		if filepath.Base(buildDescriptorFilePath) == "pyproject.toml" {
+			artifact = &Pyproject{
+				path: buildDescriptorFilePath,
+			}
+		} else {
+			artifact = &Pip{
+				path:       buildDescriptorFilePath,
+				fileExists: fileExists,
+			}
 		}
 	case "sbt":
 		if len(buildDescriptorFilePath) == 0 {
@@ -185,6 +204,11 @@ func GetArtifact(buildTool, buildDescriptorFilePath string, opts *Options, utils
 			path:         buildDescriptorFilePath,
 			versionField: "version",
 		}
+	case "xcode":
+		artifact = &Xcode{
+			path:  buildDescriptorFilePath,
+			utils: utils,
+		}
 	default:
 		return artifact, fmt.Errorf("build tool '%v' not supported", buildTool)
 	}
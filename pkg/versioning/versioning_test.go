@@ -199,7 +199,50 @@ func TestGetArtifact(t *testing.T) {
 		fileExists = func(string) (bool, error) { return false, nil }
 		_, err := GetArtifact("pip", "", &Options{}, nil)
 
-		assert.EqualError(t, err, "no build descriptor available, supported: [setup.py version.txt VERSION]")
+		assert.EqualError(t, err, "no build descriptor available, supported: [setup.py version.txt VERSION pyproject.toml]")
+	})
+
+	t.Run("pip - pyproject.toml", func(t *testing.T) {
+		fileExists = func(f string) (bool, error) { return f == "pyproject.toml", nil }
+		pip, err := GetArtifact("pip", "", &Options{}, nil)
+
+		assert.NoError(t, err)
+
+		theType, ok := pip.(*Pyproject)
+		assert.True(t, ok)
+		assert.Equal(t, "pyproject.toml", theType.path)
+		assert.Equal(t, "pep440", pip.VersioningScheme())
+	})
+
+	t.Run("cargo", func(t *testing.T) {
+		cargo, err := GetArtifact("cargo", "", &Options{}, nil)
+
+		assert.NoError(t, err)
+
+		_, ok := cargo.(*Cargo)
+		assert.True(t, ok)
+		assert.Equal(t, "semver2", cargo.VersioningScheme())
+	})
+
+	t.Run("dotnet", func(t *testing.T) {
+		dotnet, err := GetArtifact("dotnet", "src/Service/Service.csproj", &Options{}, nil)
+
+		assert.NoError(t, err)
+
+		theType, ok := dotnet.(*DotNet)
+		assert.True(t, ok)
+		assert.Equal(t, "src/Service/Service.csproj", theType.path)
+		assert.Equal(t, "semver2", dotnet.VersioningScheme())
+	})
+
+	t.Run("xcode", func(t *testing.T) {
+		xcode, err := GetArtifact("xcode", "", &Options{}, nil)
+
+		assert.NoError(t, err)
+
+		_, ok := xcode.(*Xcode)
+		assert.True(t, ok)
+		assert.Equal(t, "semver2", xcode.VersioningScheme())
 	})
 
 	t.Run("sbt", func(t *testing.T) {
@@ -0,0 +1,229 @@
+package versioning
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/piperutils"
+)
+
+const (
+	xcodeMarketingVersionKey = "CFBundleShortVersionString"
+	xcodeBuildNumberKey      = "CFBundleVersion"
+)
+
+var (
+	// xcodeVersionRegex splits e.g. "1.2.3-20221001120000+abcdef" into the marketing version and the build number
+	xcodeVersionRegex       = regexp.MustCompile(`^(\d+(?:\.\d+){0,2})(?:[-.](\d+))?`)
+	xcodeBuildSettingRegex  = regexp.MustCompile(`^\$\(([A-Za-z0-9_]+)\)$`)
+	xcodeDescriptorExcludes = []string{"Pods", "Carthage", ".build", "DerivedData", "build"}
+)
+
+// Xcode defines an artifact using the Info.plist of an iOS or macOS app for versioning.
+// The version is kept in CFBundleShortVersionString and the build number in CFBundleVersion, if the Info.plist refers
+// to build settings like $(MARKETING_VERSION) the values of the Xcode project (project.pbxproj) are used.
+type Xcode struct {
+	path    string
+	utils   Utils
+	content string
+	project string
+}
+
+func (x *Xcode) init() error {
+	if x.utils == nil {
+		return fmt.Errorf("no file utils provided")
+	}
+	if len(x.path) == 0 {
+		path, err := x.findDescriptor("**/Info.plist")
+		if err != nil {
+			return err
+		}
+		x.path = path
+	}
+	if len(x.content) == 0 {
+		content, err := x.utils.FileRead(x.path)
+		if err != nil {
+			return fmt.Errorf("failed to read file '%v': %w", x.path, err)
+		}
+		x.content = string(content)
+	}
+	return nil
+}
+
+// findDescriptor returns the only file matching the pattern, files of dependencies and build results are ignored
+func (x *Xcode) findDescriptor(pattern string) (string, error) {
+	matches, err := x.utils.Glob(pattern)
+	if err != nil {
+		return "", fmt.Errorf("failed to find files matching '%v': %w", pattern, err)
+	}
+	descriptors := []string{}
+	for _, match := range matches {
+		excluded := false
+		for _, part := range strings.Split(match, "/") {
+			if piperutils.ContainsString(xcodeDescriptorExcludes, part) {
+				excluded = true
+				break
+			}
+		}
+		if !excluded {
+			descriptors = append(descriptors, match)
+		}
+	}
+	sort.Strings(descriptors)
+	switch len(descriptors) {
+	case 0:
+		return "", fmt.Errorf("failed to find a file matching '%v'", pattern)
+	case 1:
+		return descriptors[0], nil
+	default:
+		return "", fmt.Errorf("multiple files matching '%v' found %v, please define the build descriptor to use", pattern, descriptors)
+	}
+}
+
+func plistStringRegex(key string) *regexp.Regexp {
+	return regexp.MustCompile(fmt.Sprintf(`(<key>%v</key>\s*<string>)([^<]*)(</string>)`, regexp.QuoteMeta(key)))
+}
+
+func buildSettingRegex(name string) *regexp.Regexp {
+	return regexp.MustCompile(fmt.Sprintf(`(\b%v = )("?)([^";]*)("?;)`, regexp.QuoteMeta(name)))
+}
+
+// readProject reads the Xcode project which defines the build settings referenced by the Info.plist
+func (x *Xcode) readProject() error {
+	if len(x.project) > 0 {
+		return nil
+	}
+	path, err := x.findDescriptor("**/*.xcodeproj/project.pbxproj")
+	if err != nil {
+		return err
+	}
+	content, err := x.utils.FileRead(path)
+	if err != nil {
+		return fmt.Errorf("failed to read file '%v': %w", path, err)
+	}
+	x.project = string(content)
+	return nil
+}
+
+// value returns the value of the Info.plist key, references to build settings are resolved
+func (x *Xcode) value(key string) (string, bool, error) {
+	match := plistStringRegex(key).FindStringSubmatch(x.content)
+	if match == nil {
+		return "", false, nil
+	}
+	setting := xcodeBuildSettingRegex.FindStringSubmatch(match[2])
+	if setting == nil {
+		return match[2], true, nil
+	}
+	if err := x.readProject(); err != nil {
+		return "", false, fmt.Errorf("failed to resolve '%v' of key '%v': %w", match[2], key, err)
+	}
+	projectMatch := buildSettingRegex(setting[1]).FindStringSubmatch(x.project)
+	if projectMatch == nil {
+		return "", false, fmt.Errorf("build setting '%v' of key '%v' not found in Xcode project", setting[1], key)
+	}
+	return projectMatch[3], true, nil
+}
+
+// setValue updates the value of the Info.plist key or of the build setting it refers to
+func (x *Xcode) setValue(key, value string) (bool, error) {
+	plistKey := plistStringRegex(key)
+	match := plistKey.FindStringSubmatch(x.content)
+	if match == nil {
+		return false, nil
+	}
+	setting := xcodeBuildSettingRegex.FindStringSubmatch(match[2])
+	if setting == nil {
+		x.content = plistKey.ReplaceAllString(x.content, "${1}"+value+"${3}")
+		return true, nil
+	}
+	if err := x.readProject(); err != nil {
+		return false, err
+	}
+	// the build setting is defined per build configuration, e.g. Debug and Release
+	x.project = buildSettingRegex(setting[1]).ReplaceAllString(x.project, "${1}${2}"+value+"${4}")
+	return true, nil
+}
+
+// VersioningScheme returns the relevant versioning scheme
+func (x *Xcode) VersioningScheme() string {
+	return "semver2"
+}
+
+// GetVersion returns the marketing version (CFBundleShortVersionString) of the app
+func (x *Xcode) GetVersion() (string, error) {
+	if err := x.init(); err != nil {
+		return "", fmt.Errorf("failed to init xcode versioning: %w", err)
+	}
+	version, found, err := x.value(xcodeMarketingVersionKey)
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		return "", fmt.Errorf("no %v found in '%v'", xcodeMarketingVersionKey, x.path)
+	}
+	return version, nil
+}
+
+// SetVersion updates the marketing version and the build number of the app. Both only allow period-separated integers,
+// thus the version is split into its core version (marketing version) and the numeric pre-release part, e.g. the timestamp
+// of a cloud version, which is used as build number. Without numeric pre-release part the core version is the build number.
+func (x *Xcode) SetVersion(version string) error {
+	if err := x.init(); err != nil {
+		return fmt.Errorf("failed to init xcode versioning: %w", err)
+	}
+	match := xcodeVersionRegex.FindStringSubmatch(version)
+	if match == nil {
+		return fmt.Errorf("version '%v' cannot be used for an Xcode project, it has to start with <major>.<minor>.<patch>", version)
+	}
+	marketingVersion, buildNumber := match[1], match[2]
+	if len(buildNumber) == 0 {
+		buildNumber = marketingVersion
+	}
+
+	found, err := x.setValue(xcodeMarketingVersionKey, marketingVersion)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("no %v found in '%v'", xcodeMarketingVersionKey, x.path)
+	}
+	if _, err := x.setValue(xcodeBuildNumberKey, buildNumber); err != nil {
+		return err
+	}
+
+	if err := x.utils.FileWrite(x.path, []byte(x.content), 0666); err != nil {
+		return fmt.Errorf("failed to write file '%v': %w", x.path, err)
+	}
+	if len(x.project) > 0 {
+		path, err := x.findDescriptor("**/*.xcodeproj/project.pbxproj")
+		if err != nil {
+			return err
+		}
+		if err := x.utils.FileWrite(path, []byte(x.project), 0666); err != nil {
+			return fmt.Errorf("failed to write file '%v': %w", path, err)
+		}
+	}
+	return nil
+}
+
+// GetCoordinates returns the bundle identifier of the app together with its version
+func (x *Xcode) GetCoordinates() (Coordinates, error) {
+	result := Coordinates{}
+	if err := x.init(); err != nil {
+		return result, fmt.Errorf("failed to init xcode versioning: %w", err)
+	}
+	bundleID, _, err := x.value("CFBundleIdentifier")
+	if err != nil {
+		return result, fmt.Errorf("failed to retrieve coordinates: %w", err)
+	}
+	result.ArtifactID = bundleID
+
+	result.Version, err = x.GetVersion()
+	if err != nil {
+		return result, fmt.Errorf("failed to retrieve coordinates: %w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,140 @@
+package versioning
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const infoPlist = `<?xml version="1.0" encoding="UTF-8"?>
+<plist version="1.0">
+<dict>
+	<key>CFBundleIdentifier</key>
+	<string>com.example.app</string>
+	<key>CFBundleShortVersionString</key>
+	<string>1.2.3</string>
+	<key>CFBundleVersion</key>
+	<string>42</string>
+</dict>
+</plist>
+`
+
+const infoPlistWithBuildSettings = `<plist version="1.0">
+<dict>
+	<key>CFBundleIdentifier</key>
+	<string>$(PRODUCT_BUNDLE_IDENTIFIER)</string>
+	<key>CFBundleShortVersionString</key>
+	<string>$(MARKETING_VERSION)</string>
+	<key>CFBundleVersion</key>
+	<string>$(CURRENT_PROJECT_VERSION)</string>
+</dict>
+</plist>
+`
+
+const pbxproj = `		/* Debug */ = {
+			buildSettings = {
+				CURRENT_PROJECT_VERSION = 1;
+				MARKETING_VERSION = 2.0;
+				PRODUCT_BUNDLE_IDENTIFIER = "com.example.app";
+			};
+		};
+		/* Release */ = {
+			buildSettings = {
+				CURRENT_PROJECT_VERSION = 1;
+				MARKETING_VERSION = 2.0;
+				PRODUCT_BUNDLE_IDENTIFIER = "com.example.app";
+			};
+		};
+`
+
+func TestXcodeGetVersion(t *testing.T) {
+	t.Run("success case - Info.plist", func(t *testing.T) {
+		fileUtils := newVersioningMockUtils()
+		fileUtils.AddFile("App/Info.plist", []byte(infoPlist))
+		fileUtils.AddFile("Pods/Alamofire/Info.plist", []byte(infoPlist))
+
+		xcode := Xcode{utils: fileUtils}
+		version, err := xcode.GetVersion()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "App/Info.plist", xcode.path)
+		assert.Equal(t, "1.2.3", version)
+	})
+
+	t.Run("success case - build settings", func(t *testing.T) {
+		fileUtils := newVersioningMockUtils()
+		fileUtils.AddFile("App/Info.plist", []byte(infoPlistWithBuildSettings))
+		fileUtils.AddFile("App.xcodeproj/project.pbxproj", []byte(pbxproj))
+
+		xcode := Xcode{utils: fileUtils}
+		version, err := xcode.GetVersion()
+
+		assert.NoError(t, err)
+		assert.Equal(t, "2.0", version)
+	})
+
+	t.Run("error case - multiple Info.plist", func(t *testing.T) {
+		fileUtils := newVersioningMockUtils()
+		fileUtils.AddFile("App/Info.plist", []byte(infoPlist))
+		fileUtils.AddFile("Widget/Info.plist", []byte(infoPlist))
+
+		xcode := Xcode{utils: fileUtils}
+		_, err := xcode.GetVersion()
+
+		assert.EqualError(t, err, "failed to init xcode versioning: multiple files matching '**/Info.plist' found [App/Info.plist Widget/Info.plist], please define the build descriptor to use")
+	})
+}
+
+func TestXcodeSetVersion(t *testing.T) {
+	t.Run("success case - Info.plist", func(t *testing.T) {
+		fileUtils := newVersioningMockUtils()
+		fileUtils.AddFile("App/Info.plist", []byte(infoPlist))
+
+		xcode := Xcode{path: "App/Info.plist", utils: fileUtils}
+		err := xcode.SetVersion("1.2.3-20221001120000+abcdef")
+
+		assert.NoError(t, err)
+		content, _ := fileUtils.FileRead("App/Info.plist")
+		assert.Contains(t, string(content), "<key>CFBundleShortVersionString</key>\n\t<string>1.2.3</string>")
+		assert.Contains(t, string(content), "<key>CFBundleVersion</key>\n\t<string>20221001120000</string>")
+	})
+
+	t.Run("success case - build settings", func(t *testing.T) {
+		fileUtils := newVersioningMockUtils()
+		fileUtils.AddFile("App/Info.plist", []byte(infoPlistWithBuildSettings))
+		fileUtils.AddFile("App.xcodeproj/project.pbxproj", []byte(pbxproj))
+
+		xcode := Xcode{utils: fileUtils}
+		err := xcode.SetVersion("2.1.0")
+
+		assert.NoError(t, err)
+		content, _ := fileUtils.FileRead("App.xcodeproj/project.pbxproj")
+		assert.NotContains(t, string(content), "MARKETING_VERSION = 2.0;")
+		assert.Contains(t, string(content), "\t\t\t\tMARKETING_VERSION = 2.1.0;\n\t\t\t\tPRODUCT_BUNDLE_IDENTIFIER")
+		assert.Contains(t, string(content), "CURRENT_PROJECT_VERSION = 2.1.0;")
+		content, _ = fileUtils.FileRead("App/Info.plist")
+		assert.Equal(t, infoPlistWithBuildSettings, string(content))
+	})
+
+	t.Run("error case - invalid version", func(t *testing.T) {
+		fileUtils := newVersioningMockUtils()
+		fileUtils.AddFile("App/Info.plist", []byte(infoPlist))
+
+		xcode := Xcode{utils: fileUtils}
+		err := xcode.SetVersion("latest")
+
+		assert.EqualError(t, err, "version 'latest' cannot be used for an Xcode project, it has to start with <major>.<minor>.<patch>")
+	})
+}
+
+func TestXcodeGetCoordinates(t *testing.T) {
+	fileUtils := newVersioningMockUtils()
+	fileUtils.AddFile("App/Info.plist", []byte(infoPlistWithBuildSettings))
+	fileUtils.AddFile("App.xcodeproj/project.pbxproj", []byte(pbxproj))
+
+	xcode := Xcode{utils: fileUtils}
+	coordinates, err := xcode.GetCoordinates()
+
+	assert.NoError(t, err)
+	assert.Equal(t, Coordinates{ArtifactID: "com.example.app", Version: "2.0"}, coordinates)
+}
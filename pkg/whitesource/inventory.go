@@ -0,0 +1,107 @@
+package whitesource
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/pkg/errors"
+)
+
+// Dependency types as understood by the WhiteSource agent update API
+const (
+	DependencyTypeGradle = "GRADLE"
+	DependencyTypePython = "PYTHON"
+	DependencyTypeGo     = "GO"
+	DependencyTypeDebian = "DEBIAN"
+	DependencyTypeAlpine = "ALPINE"
+)
+
+const (
+	nativeAgentName    = "piper-native-agent"
+	nativeAgentVersion = "1.0.0"
+	// agentStatusSuccess is the status of a successful agent request
+	agentStatusSuccess = 1
+)
+
+// DependencyInfo describes a dependency in the format of the WhiteSource agent update API.
+type DependencyInfo struct {
+	GroupID        string           `json:"groupId,omitempty"`
+	ArtifactID     string           `json:"artifactId"`
+	Version        string           `json:"version,omitempty"`
+	Scope          string           `json:"scope,omitempty"`
+	Filename       string           `json:"filename,omitempty"`
+	DependencyType string           `json:"dependencyType"`
+	Children       []DependencyInfo `json:"children,omitempty"`
+}
+
+// Key returns an identifier of the dependency which is unique within a dependency tree.
+func (d DependencyInfo) Key() string {
+	return fmt.Sprintf("%v:%v:%v", d.GroupID, d.ArtifactID, d.Version)
+}
+
+// AgentCoordinates identify the WhiteSource project an inventory update belongs to.
+type AgentCoordinates struct {
+	GroupID    string `json:"groupId,omitempty"`
+	ArtifactID string `json:"artifactId"`
+	Version    string `json:"version,omitempty"`
+}
+
+// AgentProjectInfo contains the dependencies of one WhiteSource project.
+type AgentProjectInfo struct {
+	Coordinates  AgentCoordinates `json:"coordinates"`
+	ProjectToken string           `json:"projectToken,omitempty"`
+	Dependencies []DependencyInfo `json:"dependencies"`
+}
+
+// UpdateInventory uploads the dependency trees of the given projects via the agent update API,
+// i.e. the same API the Unified Agent uses after having resolved the dependencies.
+func (s *System) UpdateInventory(agentURL, productName, productVersion string, projects []AgentProjectInfo) error {
+	diff, err := json.Marshal(projects)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal dependency inventory")
+	}
+	form := url.Values{
+		"type":           {"UPDATE"},
+		"agent":          {nativeAgentName},
+		"agentVersion":   {nativeAgentVersion},
+		"pluginVersion":  {nativeAgentVersion},
+		"token":          {s.orgToken},
+		"userKey":        {s.userToken},
+		"product":        {productName},
+		"productVersion": {productVersion},
+		"timeStamp":      {fmt.Sprintf("%v", time.Now().UnixNano()/int64(time.Millisecond))},
+		"diff":           {string(diff)},
+	}
+
+	headers := http.Header{}
+	headers.Add("Content-Type", "application/x-www-form-urlencoded")
+	response, err := s.httpClient.SendRequest(http.MethodPost, agentURL, strings.NewReader(form.Encode()), headers, nil)
+	if err != nil {
+		return errors.Wrap(err, "failed to send inventory update to WhiteSource")
+	}
+	defer response.Body.Close()
+	responseBody, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return errors.Wrap(err, "failed to read WhiteSource response")
+	}
+	log.Entry().Debugf("response: %v", string(responseBody))
+
+	envelope := struct {
+		Status  int    `json:"status"`
+		Message string `json:"message"`
+		Data    string `json:"data"`
+	}{}
+	if err := json.Unmarshal(responseBody, &envelope); err != nil {
+		return errors.Wrap(err, "failed to parse WhiteSource response")
+	}
+	if envelope.Status != agentStatusSuccess {
+		return fmt.Errorf("inventory update failed with status %v: %v %v", envelope.Status, envelope.Message, envelope.Data)
+	}
+	return nil
+}
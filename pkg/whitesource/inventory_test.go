@@ -0,0 +1,56 @@
+package whitesource
+
+import (
+	"io/ioutil"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateInventory(t *testing.T) {
+	t.Parallel()
+	projects := []AgentProjectInfo{{
+		Coordinates:  AgentCoordinates{ArtifactID: "my-project", Version: "1"},
+		Dependencies: []DependencyInfo{{ArtifactID: "click", Version: "8.1.3", DependencyType: DependencyTypePython}},
+	}}
+
+	t.Run("success", func(t *testing.T) {
+		myTestClient := whitesourceMockClient{responseBody: `{"envelopeVersion":"2.1.0","status":1,"message":"ok","data":"{}"}`}
+		sys := System{serverURL: "https://my.test.server/api", httpClient: &myTestClient, orgToken: "test_org_token", userToken: "test_user_token"}
+
+		err := sys.UpdateInventory("https://my.test.server/agent", "my-product", "1", projects)
+
+		require.NoError(t, err)
+		assert.Equal(t, "POST", myTestClient.httpMethod)
+		assert.Equal(t, "https://my.test.server/agent", myTestClient.urlsCalled)
+		body, _ := ioutil.ReadAll(myTestClient.requestBody)
+		form, err := url.ParseQuery(string(body))
+		require.NoError(t, err)
+		assert.Equal(t, "UPDATE", form.Get("type"))
+		assert.Equal(t, "test_org_token", form.Get("token"))
+		assert.Equal(t, "test_user_token", form.Get("userKey"))
+		assert.Equal(t, "my-product", form.Get("product"))
+		assert.Equal(t, "1", form.Get("productVersion"))
+		assert.Equal(t, `[{"coordinates":{"artifactId":"my-project","version":"1"},"dependencies":[{"artifactId":"click","version":"8.1.3","dependencyType":"PYTHON"}]}]`, form.Get("diff"))
+	})
+
+	t.Run("failure status", func(t *testing.T) {
+		myTestClient := whitesourceMockClient{responseBody: `{"status":2,"message":"bad request","data":"invalid token"}`}
+		sys := System{httpClient: &myTestClient}
+
+		err := sys.UpdateInventory("https://my.test.server/agent", "my-product", "1", projects)
+
+		assert.EqualError(t, err, "inventory update failed with status 2: bad request invalid token")
+	})
+
+	t.Run("request error", func(t *testing.T) {
+		myTestClient := whitesourceMockClient{requestError: assert.AnError}
+		sys := System{httpClient: &myTestClient}
+
+		err := sys.UpdateInventory("https://my.test.server/agent", "my-product", "1", projects)
+
+		assert.Contains(t, err.Error(), "failed to send inventory update to WhiteSource")
+	})
+}
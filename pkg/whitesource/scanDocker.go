@@ -0,0 +1,204 @@
+package whitesource
+
+import (
+	"archive/tar"
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strings"
+)
+
+const (
+	dpkgStatusFile    = "var/lib/dpkg/status"
+	dpkgStatusDir     = "var/lib/dpkg/status.d/"
+	apkInstalledFile  = "lib/apk/db/installed"
+	imageManifestFile = "manifest.json"
+)
+
+// resolveImageDependencies reads the installed OS packages from the layers of a container image
+// saved in the legacy "docker save" format. Debian (dpkg) and Alpine (apk) based images are supported.
+func resolveImageDependencies(imageArchive string, utils Utils) ([]DependencyInfo, error) {
+	if len(imageArchive) == 0 {
+		return nil, fmt.Errorf("no container image archive available for scanning")
+	}
+	layers, err := readImageLayerOrder(imageArchive, utils)
+	if err != nil {
+		return nil, err
+	}
+	databases, err := readPackageDatabases(imageArchive, layers, utils)
+	if err != nil {
+		return nil, err
+	}
+
+	dependencies := []DependencyInfo{}
+	names := []string{}
+	for name := range databases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if name == apkInstalledFile {
+			dependencies = append(dependencies, parseApkInstalled(databases[name])...)
+		} else {
+			dependencies = append(dependencies, parseDpkgStatus(databases[name])...)
+		}
+	}
+	if len(dependencies) == 0 {
+		return nil, fmt.Errorf("no dpkg or apk package database found in image, only Debian and Alpine based images are supported")
+	}
+	return dependencies, nil
+}
+
+// readImageLayerOrder returns the position of each layer archive within the image as listed in the manifest
+func readImageLayerOrder(imageArchive string, utils Utils) (map[string]int, error) {
+	layers := map[string]int{}
+	err := walkTarArchive(imageArchive, utils, func(header *tar.Header, content io.Reader) error {
+		if header.Name != imageManifestFile {
+			return nil
+		}
+		manifest := []struct {
+			Layers []string `json:"Layers"`
+		}{}
+		if err := json.NewDecoder(content).Decode(&manifest); err != nil {
+			return fmt.Errorf("failed to parse image manifest: %w", err)
+		}
+		if len(manifest) == 0 {
+			return fmt.Errorf("image manifest does not contain any image")
+		}
+		for i, layer := range manifest[0].Layers {
+			layers[layer] = i
+		}
+		return nil
+	})
+	if err == nil && len(layers) == 0 {
+		err = fmt.Errorf("image archive '%v' does not contain a manifest with layers", imageArchive)
+	}
+	return layers, err
+}
+
+// readPackageDatabases returns the package databases of the image, files in upper layers replace those of lower layers
+func readPackageDatabases(imageArchive string, layers map[string]int, utils Utils) (map[string][]byte, error) {
+	databases := map[string][]byte{}
+	databaseLayer := map[string]int{}
+	err := walkTarArchive(imageArchive, utils, func(header *tar.Header, content io.Reader) error {
+		layer, isLayer := layers[header.Name]
+		if !isLayer {
+			return nil
+		}
+		layerReader := tar.NewReader(content)
+		for {
+			layerHeader, err := layerReader.Next()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("failed to read layer %v: %w", header.Name, err)
+			}
+			name := strings.TrimPrefix(path.Clean(layerHeader.Name), "/")
+			if layerHeader.Typeflag != tar.TypeReg || !isPackageDatabase(name) {
+				continue
+			}
+			if previous, ok := databaseLayer[name]; ok && previous > layer {
+				continue
+			}
+			data, err := ioutil.ReadAll(layerReader)
+			if err != nil {
+				return fmt.Errorf("failed to read %v from layer %v: %w", name, header.Name, err)
+			}
+			databases[name] = data
+			databaseLayer[name] = layer
+		}
+	})
+	return databases, err
+}
+
+func isPackageDatabase(name string) bool {
+	return name == dpkgStatusFile || name == apkInstalledFile || strings.HasPrefix(name, dpkgStatusDir)
+}
+
+func walkTarArchive(archive string, utils Utils, visit func(header *tar.Header, content io.Reader) error) error {
+	file, err := utils.Open(archive)
+	if err != nil {
+		return fmt.Errorf("failed to open image archive '%v': %w", archive, err)
+	}
+	defer file.Close()
+	reader := tar.NewReader(file)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read image archive '%v': %w", archive, err)
+		}
+		if err := visit(header, reader); err != nil {
+			return err
+		}
+	}
+}
+
+// parseDpkgStatus parses the stanzas of a dpkg status file and returns the installed packages
+func parseDpkgStatus(content []byte) []DependencyInfo {
+	dependencies := []DependencyInfo{}
+	for _, stanza := range parseStanzas(content, ": ") {
+		if len(stanza["Package"]) == 0 || (len(stanza["Status"]) > 0 && !strings.HasSuffix(stanza["Status"], " installed")) {
+			continue
+		}
+		dependencies = append(dependencies, DependencyInfo{
+			ArtifactID:     stanza["Package"],
+			Version:        stanza["Version"],
+			Filename:       fmt.Sprintf("%v_%v_%v.deb", stanza["Package"], stanza["Version"], stanza["Architecture"]),
+			DependencyType: DependencyTypeDebian,
+		})
+	}
+	return dependencies
+}
+
+// parseApkInstalled parses the apk database and returns the installed packages
+func parseApkInstalled(content []byte) []DependencyInfo {
+	dependencies := []DependencyInfo{}
+	for _, stanza := range parseStanzas(content, ":") {
+		if len(stanza["P"]) == 0 {
+			continue
+		}
+		dependencies = append(dependencies, DependencyInfo{
+			ArtifactID:     stanza["P"],
+			Version:        stanza["V"],
+			Filename:       fmt.Sprintf("%v-%v.apk", stanza["P"], stanza["V"]),
+			DependencyType: DependencyTypeAlpine,
+		})
+	}
+	return dependencies
+}
+
+// parseStanzas splits blank-line separated blocks of "key<separator>value" lines, continuation lines are ignored
+func parseStanzas(content []byte, separator string) []map[string]string {
+	stanzas := []map[string]string{}
+	current := map[string]string{}
+	scanner := bufio.NewScanner(strings.NewReader(string(content)))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if len(strings.TrimSpace(line)) == 0 {
+			if len(current) > 0 {
+				stanzas = append(stanzas, current)
+				current = map[string]string{}
+			}
+			continue
+		}
+		if strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			continue
+		}
+		if key, value, found := strings.Cut(line, separator); found {
+			current[key] = strings.TrimSpace(value)
+		}
+	}
+	if len(current) > 0 {
+		stanzas = append(stanzas, current)
+	}
+	return stanzas
+}
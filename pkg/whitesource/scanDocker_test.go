@@ -0,0 +1,104 @@
+package whitesource
+
+import (
+	"archive/tar"
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createTestTar(t *testing.T, files map[string][]byte, order []string) []byte {
+	var buffer bytes.Buffer
+	writer := tar.NewWriter(&buffer)
+	for _, name := range order {
+		require.NoError(t, writer.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(files[name])), Typeflag: tar.TypeReg}))
+		_, err := writer.Write(files[name])
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+	return buffer.Bytes()
+}
+
+const dpkgStatus = `Package: libc6
+Status: install ok installed
+Architecture: amd64
+Version: 2.31-13
+Description: GNU C Library
+ continuation line: with colon
+
+Package: removed-package
+Status: deinstall ok config-files
+Version: 1.0
+
+Package: openssl
+Status: install ok installed
+Architecture: amd64
+Version: 1.1.1n-0
+`
+
+func TestResolveImageDependencies(t *testing.T) {
+	t.Parallel()
+	t.Run("debian image with package database updated in upper layer", func(t *testing.T) {
+		baseLayer := createTestTar(t, map[string][]byte{"var/lib/dpkg/status": []byte("Package: outdated\nStatus: install ok installed\nVersion: 1\n")}, []string{"var/lib/dpkg/status"})
+		upperLayer := createTestTar(t, map[string][]byte{
+			"./var/lib/dpkg/status":            []byte(dpkgStatus),
+			"var/lib/dpkg/status.d/distroless": []byte("Package: tzdata\nVersion: 2021a\nArchitecture: all\n"),
+			"etc/hostname":                     []byte("host"),
+		}, []string{"./var/lib/dpkg/status", "var/lib/dpkg/status.d/distroless", "etc/hostname"})
+		// upper layer is stored first in the archive to verify that the manifest order is respected
+		image := createTestTar(t, map[string][]byte{
+			"manifest.json":   []byte(`[{"Config":"config.json","RepoTags":["app:1"],"Layers":["base/layer.tar","upper/layer.tar"]}]`),
+			"upper/layer.tar": upperLayer,
+			"base/layer.tar":  baseLayer,
+		}, []string{"manifest.json", "upper/layer.tar", "base/layer.tar"})
+		utilsMock := NewScanUtilsMock()
+		utilsMock.AddFile("cache/app.tar", image)
+
+		dependencies, err := resolveImageDependencies("cache/app.tar", utilsMock)
+
+		require.NoError(t, err)
+		assert.Equal(t, []DependencyInfo{
+			{ArtifactID: "libc6", Version: "2.31-13", Filename: "libc6_2.31-13_amd64.deb", DependencyType: DependencyTypeDebian},
+			{ArtifactID: "openssl", Version: "1.1.1n-0", Filename: "openssl_1.1.1n-0_amd64.deb", DependencyType: DependencyTypeDebian},
+			{ArtifactID: "tzdata", Version: "2021a", Filename: "tzdata_2021a_all.deb", DependencyType: DependencyTypeDebian},
+		}, dependencies)
+	})
+
+	t.Run("alpine image", func(t *testing.T) {
+		layer := createTestTar(t, map[string][]byte{"lib/apk/db/installed": []byte("C:Q1abc=\nP:musl\nV:1.2.3-r0\nA:x86_64\n\nP:busybox\nV:1.35.0-r17\n")}, []string{"lib/apk/db/installed"})
+		image := createTestTar(t, map[string][]byte{
+			"manifest.json": []byte(`[{"Layers":["abc/layer.tar"]}]`),
+			"abc/layer.tar": layer,
+		}, []string{"manifest.json", "abc/layer.tar"})
+		utilsMock := NewScanUtilsMock()
+		utilsMock.AddFile("app.tar", image)
+
+		dependencies, err := resolveImageDependencies("app.tar", utilsMock)
+
+		require.NoError(t, err)
+		assert.Equal(t, []DependencyInfo{
+			{ArtifactID: "musl", Version: "1.2.3-r0", Filename: "musl-1.2.3-r0.apk", DependencyType: DependencyTypeAlpine},
+			{ArtifactID: "busybox", Version: "1.35.0-r17", Filename: "busybox-1.35.0-r17.apk", DependencyType: DependencyTypeAlpine},
+		}, dependencies)
+	})
+
+	t.Run("unsupported image", func(t *testing.T) {
+		image := createTestTar(t, map[string][]byte{
+			"manifest.json": []byte(`[{"Layers":["abc/layer.tar"]}]`),
+			"abc/layer.tar": createTestTar(t, map[string][]byte{"etc/hostname": []byte("host")}, []string{"etc/hostname"}),
+		}, []string{"manifest.json", "abc/layer.tar"})
+		utilsMock := NewScanUtilsMock()
+		utilsMock.AddFile("app.tar", image)
+
+		_, err := resolveImageDependencies("app.tar", utilsMock)
+
+		assert.EqualError(t, err, "no dpkg or apk package database found in image, only Debian and Alpine based images are supported")
+	})
+
+	t.Run("no archive", func(t *testing.T) {
+		_, err := resolveImageDependencies("", NewScanUtilsMock())
+		assert.EqualError(t, err, "no container image archive available for scanning")
+	})
+}
@@ -0,0 +1,87 @@
+package whitesource
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+type goModule struct {
+	Path    string `json:"Path"`
+	Version string `json:"Version"`
+	Main    bool   `json:"Main"`
+}
+
+// resolveGoDependencies determines the module graph with "go mod graph" and reduces it to the module
+// versions selected by the minimal version selection as reported by "go list -m all".
+func resolveGoDependencies(utils Utils) ([]DependencyInfo, error) {
+	modules, err := runAndCaptureOutput(utils, "go", "list", "-m", "-json", "all")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list go modules: %w", err)
+	}
+	graph, err := runAndCaptureOutput(utils, "go", "mod", "graph")
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine go module graph: %w", err)
+	}
+	return parseGoDependencies(modules, graph)
+}
+
+func parseGoDependencies(modulesOutput, graphOutput string) ([]DependencyInfo, error) {
+	mainModule := ""
+	selected := map[string]string{}
+	decoder := json.NewDecoder(strings.NewReader(modulesOutput))
+	for {
+		module := goModule{}
+		if err := decoder.Decode(&module); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to parse go module list: %w", err)
+		}
+		if module.Main {
+			mainModule = module.Path
+			continue
+		}
+		selected[module.Path] = module.Version
+	}
+	if len(mainModule) == 0 {
+		return nil, fmt.Errorf("main module not found")
+	}
+
+	// edges only contains requirements on the selected module versions
+	edges := map[string][]string{}
+	scanner := bufio.NewScanner(strings.NewReader(graphOutput))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		path, version, _ := strings.Cut(fields[1], "@")
+		if selected[path] == version {
+			edges[fields[0]] = append(edges[fields[0]], path)
+		}
+	}
+
+	// every module is expanded once only, like gradle does for repeated sub-trees
+	expanded := map[string]bool{}
+	var resolve func(path string) DependencyInfo
+	resolve = func(path string) DependencyInfo {
+		version := selected[path]
+		info := DependencyInfo{ArtifactID: path, Version: version, DependencyType: DependencyTypeGo}
+		if expanded[path] {
+			return info
+		}
+		expanded[path] = true
+		for _, child := range edges[path+"@"+version] {
+			info.Children = append(info.Children, resolve(child))
+		}
+		return info
+	}
+
+	dependencies := []DependencyInfo{}
+	for _, path := range edges[mainModule] {
+		dependencies = append(dependencies, resolve(path))
+	}
+	return dependencies, nil
+}
@@ -0,0 +1,70 @@
+package whitesource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const goModuleList = `{
+	"Path": "example.com/app",
+	"Main": true,
+	"Dir": "/app",
+	"GoVersion": "1.18"
+}
+{
+	"Path": "github.com/pkg/errors",
+	"Version": "v0.9.1"
+}
+{
+	"Path": "github.com/stretchr/testify",
+	"Version": "v1.8.0"
+}
+{
+	"Path": "github.com/davecgh/go-spew",
+	"Version": "v1.1.1",
+	"Indirect": true
+}
+`
+
+const goModuleGraph = `example.com/app github.com/pkg/errors@v0.9.1
+example.com/app github.com/stretchr/testify@v1.8.0
+github.com/stretchr/testify@v1.8.0 github.com/davecgh/go-spew@v1.1.1
+github.com/stretchr/testify@v1.7.0 github.com/davecgh/go-spew@v1.1.0
+github.com/stretchr/testify@v1.8.0 github.com/pkg/errors@v0.8.0
+`
+
+func TestParseGoDependencies(t *testing.T) {
+	t.Parallel()
+	t.Run("success", func(t *testing.T) {
+		dependencies, err := parseGoDependencies(goModuleList, goModuleGraph)
+
+		require.NoError(t, err)
+		assert.Equal(t, []DependencyInfo{
+			{ArtifactID: "github.com/pkg/errors", Version: "v0.9.1", DependencyType: DependencyTypeGo},
+			{ArtifactID: "github.com/stretchr/testify", Version: "v1.8.0", DependencyType: DependencyTypeGo, Children: []DependencyInfo{
+				{ArtifactID: "github.com/davecgh/go-spew", Version: "v1.1.1", DependencyType: DependencyTypeGo},
+			}},
+		}, dependencies)
+	})
+
+	t.Run("no main module", func(t *testing.T) {
+		_, err := parseGoDependencies(`{"Path": "github.com/pkg/errors", "Version": "v0.9.1"}`, "")
+		assert.EqualError(t, err, "main module not found")
+	})
+}
+
+func TestResolveGoDependencies(t *testing.T) {
+	t.Parallel()
+	utilsMock := NewScanUtilsMock()
+	utilsMock.StdoutReturn = map[string]string{
+		"go list -m -json all": goModuleList,
+		"go mod graph":         goModuleGraph,
+	}
+
+	dependencies, err := resolveGoDependencies(utilsMock)
+
+	require.NoError(t, err)
+	assert.Equal(t, 2, len(dependencies))
+}
@@ -0,0 +1,137 @@
+package whitesource
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+)
+
+const gradleDependencyConfiguration = "runtimeClasspath"
+
+var gradleDependencyLine = regexp.MustCompile(`^([| ]*)[+\\]--- (.+)$`)
+var gradleProjectLine = regexp.MustCompile(`Project '(:[^']+)'`)
+
+// dependencyNode is used to assemble a dependency tree before it is converted into DependencyInfo entries.
+type dependencyNode struct {
+	info     DependencyInfo
+	children []*dependencyNode
+}
+
+func (n *dependencyNode) toDependencyInfo() DependencyInfo {
+	info := n.info
+	info.Children = toDependencyInfos(n.children)
+	return info
+}
+
+func toDependencyInfos(nodes []*dependencyNode) []DependencyInfo {
+	infos := []DependencyInfo{}
+	for _, node := range nodes {
+		infos = append(infos, node.toDependencyInfo())
+	}
+	return infos
+}
+
+// resolveGradleDependencies executes the "dependencies" task of the root project and of all sub-projects
+// and merges their runtime dependency trees.
+func resolveGradleDependencies(utils Utils) ([]DependencyInfo, error) {
+	gradle := "gradle"
+	if exists, _ := utils.FileExists("gradlew"); exists {
+		gradle = "./gradlew"
+	}
+
+	projectsOutput, err := runAndCaptureOutput(utils, gradle, "--quiet", "projects")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list gradle projects: %w", err)
+	}
+	tasks := []string{"dependencies"}
+	for _, match := range gradleProjectLine.FindAllStringSubmatch(projectsOutput, -1) {
+		tasks = append(tasks, match[1]+":dependencies")
+	}
+
+	dependencies := []DependencyInfo{}
+	known := map[string]bool{}
+	for _, task := range tasks {
+		log.Entry().Debugf("Resolving dependencies with gradle task %v", task)
+		output, err := runAndCaptureOutput(utils, gradle, "--quiet", task, "--configuration", gradleDependencyConfiguration)
+		if err != nil {
+			return nil, fmt.Errorf("failed to execute gradle task %v: %w", task, err)
+		}
+		for _, dependency := range parseGradleDependencyTree(output) {
+			if !known[dependency.Key()] {
+				known[dependency.Key()] = true
+				dependencies = append(dependencies, dependency)
+			}
+		}
+	}
+	return dependencies, nil
+}
+
+// parseGradleDependencyTree parses the output of the gradle "dependencies" task for a single configuration.
+// Dependencies on other projects of the build are dissolved, i.e. their dependencies are attached to the parent.
+func parseGradleDependencyTree(output string) []DependencyInfo {
+	root := &dependencyNode{}
+	// parents holds the node which receives the children of the respective depth, nil for ignored sub-trees
+	parents := []*dependencyNode{root}
+
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		match := gradleDependencyLine.FindStringSubmatch(scanner.Text())
+		if match == nil {
+			continue
+		}
+		depth := len(match[1]) / 5
+		if depth >= len(parents) {
+			continue
+		}
+		parents = parents[:depth+1]
+		parent := parents[depth]
+
+		entry := strings.TrimSuffix(match[2], " (*)")
+		switch {
+		case parent == nil, strings.HasSuffix(entry, " (c)"), strings.HasSuffix(entry, " (n)"), strings.HasSuffix(entry, " FAILED"):
+			parents = append(parents, nil)
+		case strings.HasPrefix(entry, "project "):
+			parents = append(parents, parent)
+		default:
+			node := &dependencyNode{info: parseGradleCoordinates(entry)}
+			parent.children = append(parent.children, node)
+			parents = append(parents, node)
+		}
+	}
+	return toDependencyInfos(root.children)
+}
+
+// parseGradleCoordinates parses entries like "group:artifact:1.0", "group:artifact:1.0 -> 1.1" or "group:artifact -> 1.1"
+func parseGradleCoordinates(entry string) DependencyInfo {
+	requested, resolved, conflict := strings.Cut(entry, " -> ")
+	if conflict && strings.Contains(resolved, ":") {
+		// dependency substitution to different coordinates
+		requested, resolved = resolved, ""
+	}
+	parts := strings.Split(requested, ":")
+	info := DependencyInfo{DependencyType: DependencyTypeGradle, ArtifactID: parts[0]}
+	if len(parts) > 1 {
+		info.GroupID = parts[0]
+		info.ArtifactID = parts[1]
+	}
+	if len(parts) > 2 {
+		info.Version = parts[2]
+	}
+	if len(resolved) > 0 {
+		info.Version = resolved
+	}
+	return info
+}
+
+// runAndCaptureOutput executes the given executable and returns what was written to stdout
+func runAndCaptureOutput(utils Utils, executable string, params ...string) (string, error) {
+	var buffer bytes.Buffer
+	utils.Stdout(&buffer)
+	defer utils.Stdout(log.Writer())
+	err := utils.RunExecutable(executable, params...)
+	return buffer.String(), err
+}
@@ -0,0 +1,77 @@
+package whitesource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const gradleDependencyOutput = `
+runtimeClasspath - Runtime classpath of source set 'main'.
++--- org.springframework:spring-core:5.3.9
+|    \--- org.springframework:spring-jcl:5.3.9
++--- com.google.guava:guava:30.1-jre -> 31.0-jre
+|    +--- com.google.guava:failureaccess:1.0.1
+|    \--- org.checkerframework:checker-qual:3.12.0
++--- project :lib
+|    \--- org.apache.commons:commons-lang3 -> 3.12.0
++--- org.slf4j:slf4j-api:1.7.32 (c)
++--- org.example:missing:1.0 FAILED
+\--- org.springframework:spring-jcl:5.3.9 (*)
+`
+
+func TestParseGradleDependencyTree(t *testing.T) {
+	t.Parallel()
+	dependencies := parseGradleDependencyTree(gradleDependencyOutput)
+
+	expected := []DependencyInfo{
+		{GroupID: "org.springframework", ArtifactID: "spring-core", Version: "5.3.9", DependencyType: DependencyTypeGradle, Children: []DependencyInfo{
+			{GroupID: "org.springframework", ArtifactID: "spring-jcl", Version: "5.3.9", DependencyType: DependencyTypeGradle, Children: []DependencyInfo{}},
+		}},
+		{GroupID: "com.google.guava", ArtifactID: "guava", Version: "31.0-jre", DependencyType: DependencyTypeGradle, Children: []DependencyInfo{
+			{GroupID: "com.google.guava", ArtifactID: "failureaccess", Version: "1.0.1", DependencyType: DependencyTypeGradle, Children: []DependencyInfo{}},
+			{GroupID: "org.checkerframework", ArtifactID: "checker-qual", Version: "3.12.0", DependencyType: DependencyTypeGradle, Children: []DependencyInfo{}},
+		}},
+		{GroupID: "org.apache.commons", ArtifactID: "commons-lang3", Version: "3.12.0", DependencyType: DependencyTypeGradle, Children: []DependencyInfo{}},
+		{GroupID: "org.springframework", ArtifactID: "spring-jcl", Version: "5.3.9", DependencyType: DependencyTypeGradle, Children: []DependencyInfo{}},
+	}
+	assert.Equal(t, expected, dependencies)
+}
+
+func TestParseGradleCoordinates(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, DependencyInfo{GroupID: "g", ArtifactID: "a", Version: "1.0", DependencyType: DependencyTypeGradle}, parseGradleCoordinates("g:a:1.0"))
+	assert.Equal(t, DependencyInfo{GroupID: "g", ArtifactID: "a", Version: "1.1", DependencyType: DependencyTypeGradle}, parseGradleCoordinates("g:a:1.0 -> 1.1"))
+	assert.Equal(t, DependencyInfo{GroupID: "g2", ArtifactID: "b", Version: "2.0", DependencyType: DependencyTypeGradle}, parseGradleCoordinates("g:a:1.0 -> g2:b:2.0"))
+}
+
+func TestResolveGradleDependencies(t *testing.T) {
+	t.Parallel()
+	t.Run("root and sub-projects with wrapper", func(t *testing.T) {
+		utilsMock := NewScanUtilsMock()
+		utilsMock.AddFile("gradlew", []byte("#!/bin/sh"))
+		utilsMock.StdoutReturn = map[string]string{
+			`./gradlew --quiet projects`:                                           "Root project 'demo'\n+--- Project ':app'\n\\--- Project ':lib'\n",
+			`./gradlew --quiet dependencies --configuration runtimeClasspath`:      "No dependencies\n",
+			`./gradlew --quiet :app:dependencies --configuration runtimeClasspath`: "+--- g:a:1.0\n\\--- g:b:1.0\n",
+			`./gradlew --quiet :lib:dependencies --configuration runtimeClasspath`: "\\--- g:a:1.0\n",
+		}
+
+		dependencies, err := resolveGradleDependencies(utilsMock)
+
+		require.NoError(t, err)
+		assert.Equal(t, 4, len(utilsMock.Calls))
+		assert.Equal(t, []string{"g:a:1.0", "g:b:1.0"}, []string{dependencies[0].Key(), dependencies[1].Key()})
+		assert.Equal(t, 2, len(dependencies))
+	})
+
+	t.Run("failing task", func(t *testing.T) {
+		utilsMock := NewScanUtilsMock()
+		utilsMock.ShouldFailOnCommand = map[string]error{"gradle --quiet dependencies.*": assert.AnError}
+
+		_, err := resolveGradleDependencies(utilsMock)
+
+		assert.EqualError(t, err, "failed to execute gradle task dependencies: "+assert.AnError.Error())
+	})
+}
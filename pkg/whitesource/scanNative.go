@@ -0,0 +1,64 @@
+package whitesource
+
+import (
+	"fmt"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/pkg/errors"
+)
+
+// InventoryUpdater uploads resolved dependency trees to WhiteSource.
+type InventoryUpdater interface {
+	UpdateInventory(agentURL, productName, productVersion string, projects []AgentProjectInfo) error
+}
+
+// ExecuteNativeScan resolves the dependencies of the project without the Unified Agent.
+// Maven, NPM and MTA projects are scanned with the respective WhiteSource plugins, for Gradle, Python, Go
+// and Docker projects the dependency tree is extracted in-process and uploaded via the agent update API.
+func (s *Scan) ExecuteNativeScan(config *ScanOptions, utils Utils, sys InventoryUpdater) error {
+	var dependencies []DependencyInfo
+	var err error
+	switch config.BuildTool {
+	case "maven":
+		return s.ExecuteMavenScan(config, utils)
+	case "npm":
+		return s.ExecuteNpmScan(config, utils)
+	case "yarn":
+		return s.ExecuteYarnScan(config, utils)
+	case "mta":
+		return s.ExecuteMTAScan(config, utils)
+	case "gradle":
+		dependencies, err = resolveGradleDependencies(utils)
+	case "pip":
+		dependencies, err = resolvePythonDependencies(utils)
+	case "golang":
+		dependencies, err = resolveGoDependencies(utils)
+	case "docker":
+		dependencies, err = resolveImageDependencies(config.ImageArchive, utils)
+	default:
+		log.SetErrorCategory(log.ErrorConfiguration)
+		return fmt.Errorf("native scan is not supported for build tool '%v', please use the Unified Agent", config.BuildTool)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "failed to resolve dependencies of %v project", config.BuildTool)
+	}
+	s.AgentName = "Piper Native Scan"
+	s.AgentVersion = nativeAgentVersion
+	return s.uploadDependencies(config, sys, dependencies)
+}
+
+func (s *Scan) uploadDependencies(config *ScanOptions, sys InventoryUpdater, dependencies []DependencyInfo) error {
+	projectName := s.AggregateProjectName
+	if len(projectName) == 0 {
+		projectName = config.ProjectName
+	}
+	log.Entry().Infof("Uploading %v direct dependencies of project '%v' to WhiteSource", len(dependencies), projectName)
+	project := AgentProjectInfo{
+		Coordinates:  AgentCoordinates{ArtifactID: projectName, Version: s.ProductVersion},
+		Dependencies: dependencies,
+	}
+	if err := sys.UpdateInventory(config.AgentURL, config.ProductName, s.ProductVersion, []AgentProjectInfo{project}); err != nil {
+		return err
+	}
+	return s.AppendScannedProject(projectName)
+}
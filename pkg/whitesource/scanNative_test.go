@@ -0,0 +1,65 @@
+package whitesource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecuteNativeScan(t *testing.T) {
+	t.Parallel()
+	t.Run("golang project", func(t *testing.T) {
+		config := ScanOptions{BuildTool: "golang", ProductName: "mock-product", AgentURL: "https://agent", ProjectName: "mock-project"}
+		utilsMock := NewScanUtilsMock()
+		utilsMock.StdoutReturn = map[string]string{
+			"go list -m -json all": goModuleList,
+			"go mod graph":         goModuleGraph,
+		}
+		sys := &SystemMock{}
+		scan := &Scan{AggregateProjectName: "my-app", ProductVersion: "1"}
+
+		err := scan.ExecuteNativeScan(&config, utilsMock, sys)
+
+		require.NoError(t, err)
+		require.Equal(t, 1, len(sys.Inventory))
+		assert.Equal(t, AgentCoordinates{ArtifactID: "my-app", Version: "1"}, sys.Inventory[0].Coordinates)
+		assert.Equal(t, 2, len(sys.Inventory[0].Dependencies))
+		_, exists := scan.ProjectByName("my-app - 1")
+		assert.True(t, exists)
+		assert.Equal(t, "Piper Native Scan", scan.AgentName)
+	})
+
+	t.Run("upload failure", func(t *testing.T) {
+		config := ScanOptions{BuildTool: "golang"}
+		utilsMock := NewScanUtilsMock()
+		utilsMock.StdoutReturn = map[string]string{"go list -m -json all": goModuleList}
+		sys := &SystemMock{InventoryError: assert.AnError}
+		scan := &Scan{AggregateProjectName: "my-app", ProductVersion: "1"}
+
+		err := scan.ExecuteNativeScan(&config, utilsMock, sys)
+
+		assert.Equal(t, assert.AnError, err)
+		assert.Empty(t, scan.ScannedProjects())
+	})
+
+	t.Run("resolution failure", func(t *testing.T) {
+		config := ScanOptions{BuildTool: "gradle"}
+		utilsMock := NewScanUtilsMock()
+		utilsMock.ShouldFailOnCommand = map[string]error{"gradle --quiet projects": assert.AnError}
+		scan := &Scan{AggregateProjectName: "my-app", ProductVersion: "1"}
+
+		err := scan.ExecuteNativeScan(&config, utilsMock, &SystemMock{})
+
+		assert.EqualError(t, err, "failed to resolve dependencies of gradle project: failed to list gradle projects: "+assert.AnError.Error())
+	})
+
+	t.Run("unsupported build tool", func(t *testing.T) {
+		config := ScanOptions{BuildTool: "sbt"}
+		scan := &Scan{AggregateProjectName: "my-app", ProductVersion: "1"}
+
+		err := scan.ExecuteNativeScan(&config, NewScanUtilsMock(), &SystemMock{})
+
+		assert.EqualError(t, err, "native scan is not supported for build tool 'sbt', please use the Unified Agent")
+	})
+}
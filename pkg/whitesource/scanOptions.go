@@ -44,5 +44,8 @@ type ScanOptions struct {
 
 	ScanPath string
 
+	// ImageArchive is the path to the saved container image which is analyzed for native scans of docker images.
+	ImageArchive string
+
 	Verbose bool
 }
@@ -0,0 +1,173 @@
+package whitesource
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/SAP/jenkins-library/pkg/log"
+	"github.com/pelletier/go-toml"
+)
+
+// pythonToolingPackages are installed for resolving the dependencies and thus not reported
+var pythonToolingPackages = map[string]bool{"pipdeptree": true, "pip": true, "setuptools": true, "wheel": true}
+
+type pipdeptreeEntry struct {
+	Key              string            `json:"key"`
+	PackageName      string            `json:"package_name"`
+	InstalledVersion string            `json:"installed_version"`
+	Dependencies     []pipdeptreeEntry `json:"dependencies"`
+}
+
+type poetryLock struct {
+	Package []struct {
+		Name         string                 `toml:"name"`
+		Version      string                 `toml:"version"`
+		Dependencies map[string]interface{} `toml:"dependencies"`
+	} `toml:"package"`
+}
+
+type poetryProject struct {
+	Tool struct {
+		Poetry struct {
+			Dependencies map[string]interface{} `toml:"dependencies"`
+		} `toml:"poetry"`
+	} `toml:"tool"`
+}
+
+// pythonVirtualEnvironment is the temporary virtual environment the project is installed into
+const pythonVirtualEnvironment = ".whitesource-venv"
+
+// resolvePythonDependencies reads the dependency tree from poetry.lock if present.
+// Otherwise the project is installed into a temporary virtual environment and the tree of installed packages is determined with pipdeptree.
+func resolvePythonDependencies(utils Utils) ([]DependencyInfo, error) {
+	if exists, _ := utils.FileExists("poetry.lock"); exists {
+		log.Entry().Info("Resolving Python dependencies from poetry.lock")
+		return resolvePoetryDependencies(utils)
+	}
+
+	// a virtual environment isolates the project from the packages installed in the build image
+	if err := utils.RunExecutable("python3", "-m", "venv", pythonVirtualEnvironment); err != nil {
+		return nil, fmt.Errorf("failed to create virtual environment: %w", err)
+	}
+	defer func() {
+		if err := utils.RemoveAll(pythonVirtualEnvironment); err != nil {
+			log.Entry().WithError(err).Warnf("failed to remove virtual environment '%v'", pythonVirtualEnvironment)
+		}
+	}()
+	pip := filepath.Join(pythonVirtualEnvironment, "bin", "pip")
+
+	if exists, _ := utils.FileExists("requirements.txt"); exists {
+		if err := utils.RunExecutable(pip, "install", "-r", "requirements.txt"); err != nil {
+			return nil, fmt.Errorf("failed to install requirements: %w", err)
+		}
+	} else if err := utils.RunExecutable(pip, "install", "."); err != nil {
+		return nil, fmt.Errorf("failed to install project: %w", err)
+	}
+	if err := utils.RunExecutable(pip, "install", "--upgrade", "pipdeptree"); err != nil {
+		return nil, fmt.Errorf("failed to install pipdeptree: %w", err)
+	}
+	output, err := runAndCaptureOutput(utils, filepath.Join(pythonVirtualEnvironment, "bin", "pipdeptree"), "--json-tree", "--local-only")
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine Python dependency tree: %w", err)
+	}
+	return parsePipdeptree([]byte(output))
+}
+
+func parsePipdeptree(content []byte) ([]DependencyInfo, error) {
+	entries := []pipdeptreeEntry{}
+	if err := json.Unmarshal(content, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse pipdeptree output: %w", err)
+	}
+	dependencies := []DependencyInfo{}
+	for _, entry := range entries {
+		if !pythonToolingPackages[entry.Key] {
+			dependencies = append(dependencies, entry.toDependencyInfo())
+		}
+	}
+	return dependencies, nil
+}
+
+func (e pipdeptreeEntry) toDependencyInfo() DependencyInfo {
+	info := DependencyInfo{ArtifactID: e.PackageName, Version: e.InstalledVersion, DependencyType: DependencyTypePython}
+	for _, dependency := range e.Dependencies {
+		info.Children = append(info.Children, dependency.toDependencyInfo())
+	}
+	return info
+}
+
+func resolvePoetryDependencies(utils Utils) ([]DependencyInfo, error) {
+	lockContent, err := utils.FileRead("poetry.lock")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read poetry.lock: %w", err)
+	}
+	projectContent, err := utils.FileRead("pyproject.toml")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pyproject.toml: %w", err)
+	}
+	return parsePoetryDependencies(lockContent, projectContent)
+}
+
+func parsePoetryDependencies(lockContent, projectContent []byte) ([]DependencyInfo, error) {
+	lock := poetryLock{}
+	if err := toml.Unmarshal(lockContent, &lock); err != nil {
+		return nil, fmt.Errorf("failed to parse poetry.lock: %w", err)
+	}
+	project := poetryProject{}
+	if err := toml.Unmarshal(projectContent, &project); err != nil {
+		return nil, fmt.Errorf("failed to parse pyproject.toml: %w", err)
+	}
+
+	packages := map[string]int{}
+	for i, pkg := range lock.Package {
+		packages[normalizePythonPackageName(pkg.Name)] = i
+	}
+
+	// every package is expanded once only, like gradle does for repeated sub-trees
+	expanded := map[string]bool{}
+	var resolve func(name string) (DependencyInfo, bool)
+	resolve = func(name string) (DependencyInfo, bool) {
+		index, ok := packages[name]
+		if !ok {
+			return DependencyInfo{}, false
+		}
+		pkg := lock.Package[index]
+		info := DependencyInfo{ArtifactID: pkg.Name, Version: pkg.Version, DependencyType: DependencyTypePython}
+		if expanded[name] {
+			return info, true
+		}
+		expanded[name] = true
+		for _, child := range sortedPythonPackageNames(pkg.Dependencies) {
+			if dependency, ok := resolve(child); ok {
+				info.Children = append(info.Children, dependency)
+			}
+		}
+		return info, true
+	}
+
+	dependencies := []DependencyInfo{}
+	for _, name := range sortedPythonPackageNames(project.Tool.Poetry.Dependencies) {
+		if dependency, ok := resolve(name); ok {
+			dependencies = append(dependencies, dependency)
+		}
+	}
+	return dependencies, nil
+}
+
+func sortedPythonPackageNames(dependencies map[string]interface{}) []string {
+	names := []string{}
+	for name := range dependencies {
+		if name != "python" {
+			names = append(names, normalizePythonPackageName(name))
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// normalizePythonPackageName normalizes package names as defined in PEP 503
+func normalizePythonPackageName(name string) string {
+	return strings.ToLower(strings.NewReplacer("_", "-", ".", "-").Replace(name))
+}
@@ -0,0 +1,146 @@
+package whitesource
+
+import (
+	"testing"
+
+	"github.com/SAP/jenkins-library/pkg/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePoetryDependencies(t *testing.T) {
+	t.Parallel()
+	lock := `
+[[package]]
+name = "requests"
+version = "2.28.1"
+category = "main"
+
+[package.dependencies]
+certifi = ">=2017.4.17"
+charset-normalizer = ">=2,<3"
+
+[[package]]
+name = "certifi"
+version = "2022.9.24"
+category = "main"
+
+[package.dependencies]
+idna = ">=2.5"
+
+[[package]]
+name = "idna"
+version = "3.4"
+category = "main"
+
+[[package]]
+name = "httpx"
+version = "0.23.1"
+category = "main"
+
+[package.dependencies]
+certifi = "*"
+
+[[package]]
+name = "charset-normalizer"
+version = "2.1.1"
+category = "main"
+
+[package.dependencies]
+requests = "*"
+
+[[package]]
+name = "pytest"
+version = "7.2.0"
+category = "dev"
+`
+	project := `
+[tool.poetry.dependencies]
+python = "^3.9"
+Requests = { version = "^2.28", extras = ["socks"] }
+httpx = "^0.23"
+unknown_package = "1.0"
+
+[tool.poetry.dev-dependencies]
+pytest = "^7.2"
+`
+	dependencies, err := parsePoetryDependencies([]byte(lock), []byte(project))
+
+	require.NoError(t, err)
+	// repeated sub-trees and cycles are expanded once only
+	assert.Equal(t, []DependencyInfo{
+		{ArtifactID: "httpx", Version: "0.23.1", DependencyType: DependencyTypePython, Children: []DependencyInfo{
+			{ArtifactID: "certifi", Version: "2022.9.24", DependencyType: DependencyTypePython, Children: []DependencyInfo{
+				{ArtifactID: "idna", Version: "3.4", DependencyType: DependencyTypePython},
+			}},
+		}},
+		{ArtifactID: "requests", Version: "2.28.1", DependencyType: DependencyTypePython, Children: []DependencyInfo{
+			{ArtifactID: "certifi", Version: "2022.9.24", DependencyType: DependencyTypePython},
+			{ArtifactID: "charset-normalizer", Version: "2.1.1", DependencyType: DependencyTypePython, Children: []DependencyInfo{
+				{ArtifactID: "requests", Version: "2.28.1", DependencyType: DependencyTypePython},
+			}},
+		}},
+	}, dependencies)
+}
+
+func TestParsePipdeptree(t *testing.T) {
+	t.Parallel()
+	output := `[
+  {"key": "flask", "package_name": "Flask", "installed_version": "2.2.2", "dependencies": [
+    {"key": "click", "package_name": "click", "installed_version": "8.1.3", "required_version": ">=8.0", "dependencies": []}
+  ]},
+  {"key": "pipdeptree", "package_name": "pipdeptree", "installed_version": "2.3.3", "dependencies": []}
+]`
+	dependencies, err := parsePipdeptree([]byte(output))
+
+	require.NoError(t, err)
+	assert.Equal(t, []DependencyInfo{
+		{ArtifactID: "Flask", Version: "2.2.2", DependencyType: DependencyTypePython, Children: []DependencyInfo{
+			{ArtifactID: "click", Version: "8.1.3", DependencyType: DependencyTypePython},
+		}},
+	}, dependencies)
+
+	_, err = parsePipdeptree([]byte("no json"))
+	assert.Contains(t, err.Error(), "failed to parse pipdeptree output")
+}
+
+func TestResolvePythonDependencies(t *testing.T) {
+	t.Parallel()
+	t.Run("requirements", func(t *testing.T) {
+		utilsMock := NewScanUtilsMock()
+		utilsMock.AddFile("requirements.txt", []byte("flask"))
+		utilsMock.StdoutReturn = map[string]string{".whitesource-venv/bin/pipdeptree --json-tree --local-only": `[{"key": "flask", "package_name": "Flask", "installed_version": "2.2.2"}]`}
+
+		dependencies, err := resolvePythonDependencies(utilsMock)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, len(dependencies))
+		assert.Equal(t, mock.ExecCall{Exec: "python3", Params: []string{"-m", "venv", ".whitesource-venv"}}, utilsMock.Calls[0])
+		assert.Equal(t, mock.ExecCall{Exec: ".whitesource-venv/bin/pip", Params: []string{"install", "-r", "requirements.txt"}}, utilsMock.Calls[1])
+		assert.Equal(t, mock.ExecCall{Exec: ".whitesource-venv/bin/pip", Params: []string{"install", "--upgrade", "pipdeptree"}}, utilsMock.Calls[2])
+		assert.Contains(t, utilsMock.RemoveAllDirs, ".whitesource-venv")
+	})
+
+	t.Run("setup.py", func(t *testing.T) {
+		utilsMock := NewScanUtilsMock()
+		utilsMock.StdoutReturn = map[string]string{".whitesource-venv/bin/pipdeptree --json-tree --local-only": `[]`}
+
+		_, err := resolvePythonDependencies(utilsMock)
+
+		require.NoError(t, err)
+		assert.Equal(t, mock.ExecCall{Exec: ".whitesource-venv/bin/pip", Params: []string{"install", "."}}, utilsMock.Calls[1])
+		assert.Contains(t, utilsMock.RemoveAllDirs, ".whitesource-venv")
+	})
+
+	t.Run("poetry", func(t *testing.T) {
+		utilsMock := NewScanUtilsMock()
+		utilsMock.AddFile("poetry.lock", []byte("[[package]]\nname = \"click\"\nversion = \"8.1.3\"\n"))
+		utilsMock.AddFile("pyproject.toml", []byte("[tool.poetry.dependencies]\nclick = \"^8\"\n"))
+
+		dependencies, err := resolvePythonDependencies(utilsMock)
+
+		require.NoError(t, err)
+		assert.Empty(t, utilsMock.Calls)
+		assert.Equal(t, []DependencyInfo{{ArtifactID: "click", Version: "8.1.3", DependencyType: DependencyTypePython}}, dependencies)
+	})
+}
@@ -20,6 +20,8 @@ type SystemMock struct {
 	Libraries           []Library
 	RiskReport          []byte
 	VulnerabilityReport []byte
+	Inventory           []AgentProjectInfo
+	InventoryError      error
 }
 
 // GetProductByName mimics retrieving a Product by name. It returns an error of no Product is stored in the mock.
@@ -129,6 +131,15 @@ func (m *SystemMock) GetProjectHierarchy(projectToken string, inHouse bool) ([]L
 	return m.Libraries, nil
 }
 
+// UpdateInventory records the uploaded projects, it returns InventoryError if configured.
+func (m *SystemMock) UpdateInventory(agentURL, productName, productVersion string, projects []AgentProjectInfo) error {
+	if m.InventoryError != nil {
+		return m.InventoryError
+	}
+	m.Inventory = append(m.Inventory, projects...)
+	return nil
+}
+
 // NewSystemMockWithProjectName returns a pointer to a new instance of SystemMock using a project with a defined name.
 func NewSystemMockWithProjectName(lastUpdateDate, projectName string) *SystemMock {
 	mockLibrary := Library{
@@ -27,6 +27,7 @@ type Utils interface {
 	GetExitCode() int
 	RemoveAll(path string) error
 	FileOpen(name string, flag int, perm os.FileMode) (File, error)
+	Open(name string) (io.ReadWriteCloser, error)
 
 	FindPackageJSONFiles(config *ScanOptions) ([]string, error)
 	InstallAllNPMDependencies(config *ScanOptions, packageJSONFiles []string) error
@@ -228,6 +228,13 @@ public class CommonStepsTest extends BasePiperTest{
         'apiProviderList', //implementing new golang pattern without fields    
         'tmsUpload',
         'tmsExport',
+        'sbomProcess', //implementing new golang pattern without fields
+        'osvExecuteScan', //implementing new golang pattern without fields
+        'licenseComplianceCheck', //implementing new golang pattern without fields
+        'secretExecuteScan', //implementing new golang pattern without fields
+        'iacExecuteScan', //implementing new golang pattern without fields
+        'checkmarxOneExecuteScan', //implementing new golang pattern without fields
+        'fortifySyncAudits', //implementing new golang pattern without fields
     ]
 
     @Test
@@ -0,0 +1,11 @@
+import groovy.transform.Field
+
+@Field String STEP_NAME = getClass().getName()
+@Field String METADATA_FILE = 'metadata/checkmarxOneExecuteScan.yaml'
+
+//Metadata maintained in file project://resources/metadata/checkmarxOneExecuteScan.yaml
+
+void call(Map parameters = [:]) {
+    List credentials = [[type: 'token', id: 'checkmarxOneCredentialsId', env: ['PIPER_apiKey']], [type: 'token', id: 'githubTokenCredentialsId', env: ['PIPER_githubToken']]]
+    piperExecuteBin(parameters, STEP_NAME, METADATA_FILE, credentials, true)
+}
@@ -4,6 +4,9 @@ import groovy.transform.Field
 @Field String METADATA_FILE = 'metadata/codeqlExecuteScan.yaml'
 
 void call(Map parameters = [:]) {
-    List credentials = [[type: 'token', id: 'githubTokenCredentialsId', env: ['PIPER_githubToken']]]
+    List credentials = [
+        [type: 'token', id: 'githubTokenCredentialsId', env: ['PIPER_githubToken']],
+        [type: 'token', id: 'packRegistryTokenCredentialsId', env: ['PIPER_packRegistryToken']],
+    ]
     piperExecuteBin(parameters, STEP_NAME, METADATA_FILE, credentials)
 }
@@ -0,0 +1,11 @@
+import groovy.transform.Field
+
+@Field String STEP_NAME = getClass().getName()
+@Field String METADATA_FILE = 'metadata/fortifySyncAudits.yaml'
+
+//Metadata maintained in file project://resources/metadata/fortifySyncAudits.yaml
+
+void call(Map parameters = [:]) {
+    List credentials = [[type: 'token', id: 'fortifyCredentialsId', env: ['PIPER_authToken']]]
+    piperExecuteBin(parameters, STEP_NAME, METADATA_FILE, credentials, true)
+}
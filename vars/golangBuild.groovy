@@ -5,7 +5,9 @@ import groovy.transform.Field
 
 void call(Map parameters = [:]) {
     List credentials = [
-        [type: 'usernamePassword', id: 'golangPrivateModulesGitTokenCredentialsId', env: ['PIPER_privateModulesGitUsername', 'PIPER_privateModulesGitToken']]
+        [type: 'usernamePassword', id: 'golangPrivateModulesGitTokenCredentialsId', env: ['PIPER_privateModulesGitUsername', 'PIPER_privateModulesGitToken']],
+        [type: 'file', id: 'golangReleaseSigningKeyCredentialsId', env: ['PIPER_releaseSigningKey']],
+        [type: 'token', id: 'golangReleaseSigningKeyPassphraseCredentialsId', env: ['PIPER_releaseSigningKeyPassphrase']]
     ]
     piperExecuteBin(parameters, STEP_NAME, METADATA_FILE, credentials)
 }